@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 	"github.com/abdul-hamid-achik/nexo/pkg/scanner"
 	"github.com/abdul-hamid-achik/nexo/pkg/tools"
 	"github.com/fatih/color"
@@ -24,10 +25,21 @@ This command:
   1. Runs templ generate (if .templ files exist)
   2. Builds an optimized Go binary with ldflags
 
+With --embed-static, the static directory is embedded into the binary so it
+can be deployed as a single file. With --generate, only the generated code
+(templates, CSS and nexo_routes.go) is written and checked to compile, for
+pipelines that run go build themselves.
+
+With --docker, a static Linux binary is built into a distroless container
+image tagged with the project name and version; no Dockerfile is needed.
+
 Examples:
   nexo build
   nexo build --output ./bin/myapp
   nexo build --os linux --arch amd64
+  nexo build --embed-static
+  nexo build --generate
+  nexo build --docker
   nexo build --json`,
 	Run: runBuild,
 }
@@ -36,12 +48,19 @@ var (
 	buildOutput string
 	buildOS     string
 	buildArch   string
+
+	buildEmbedStatic bool
+	buildGenerate    bool
+	buildDocker      bool
 )
 
 func init() {
 	buildCmd.Flags().StringVarP(&buildOutput, "output", "o", "", "Output binary path (default: ./bin/<project-name>)")
 	buildCmd.Flags().StringVar(&buildOS, "os", "", "Target OS (linux, darwin, windows)")
 	buildCmd.Flags().StringVar(&buildArch, "arch", "", "Target architecture (amd64, arm64)")
+	buildCmd.Flags().BoolVar(&buildEmbedStatic, "embed-static", false, "Embed the static directory into the binary")
+	buildCmd.Flags().BoolVar(&buildGenerate, "generate", false, "Only generate code and verify it compiles, without building a binary")
+	buildCmd.Flags().BoolVar(&buildDocker, "docker", false, "Build a container image instead of a local binary")
 }
 
 func runBuild(cmd *cobra.Command, args []string) {
@@ -83,6 +102,56 @@ func runBuild(cmd *cobra.Command, args []string) {
 		fmt.Printf("\n  %s Production Build\n\n", cyan("Nexo"))
 	}
 
+	// Without RegisterRoutes the app scans app/ at startup and only registers placeholders
+	if !jsonOutput && !mainRegistersRoutes("main.go") {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("  %s main.go doesn't call RegisterRoutes(app); generated routes won't be used\n", yellow("Warning:"))
+	}
+
+	// With --generate the build only verifies that the generated code compiles
+	if buildGenerate {
+		outputPath = os.DevNull
+	}
+
+	// With --docker the binary is built for Linux into a temporary build context
+	var dockerContext string
+	if buildDocker {
+		var err error
+		switch {
+		case buildGenerate:
+			err = fmt.Errorf("--docker cannot be combined with --generate")
+		case buildOS != "" && buildOS != "linux":
+			err = fmt.Errorf("--docker builds Linux images, got --os %s", buildOS)
+		default:
+			if _, lookErr := exec.LookPath("docker"); lookErr != nil {
+				err = fmt.Errorf("docker not found, please install Docker to build images")
+			}
+		}
+		if err == nil {
+			dockerContext, err = os.MkdirTemp("", "nexo-docker-")
+		}
+		if err != nil {
+			if jsonOutput {
+				printJSONError(err)
+			} else {
+				red := color.New(color.FgRed).SprintFunc()
+				fmt.Printf("  %s %v\n", red("Error:"), err)
+			}
+			os.Exit(1)
+		}
+		defer func() { _ = os.RemoveAll(dockerContext) }()
+
+		targetOS = "linux"
+		outputPath = filepath.Join(dockerContext, "app")
+
+		// The distroless image only holds the binary, so static files must be embedded
+		if cfg, err := nexo.LoadConfig(""); err == nil {
+			if info, err := os.Stat(cfg.StaticDir); err == nil && info.IsDir() {
+				buildEmbedStatic = true
+			}
+		}
+	}
+
 	// Create bin directory
 	binDir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(binDir, 0755); err != nil {
@@ -157,6 +226,7 @@ func runBuild(cmd *cobra.Command, args []string) {
 
 	// Regenerate routes before building
 	// This ensures the generated routes file is up-to-date with the latest route structure
+	var generated []string
 	if _, err := os.Stat("app"); !os.IsNotExist(err) {
 		if !jsonOutput {
 			yellow := color.New(color.FgYellow).SprintFunc()
@@ -175,20 +245,52 @@ func runBuild(cmd *cobra.Command, args []string) {
 			green := color.New(color.FgGreen).SprintFunc()
 			fmt.Printf("  %s Routes generated\n", green("✓"))
 		}
+		generated = append(generated, "nexo_routes.go")
+	}
+
+	// Embed static files after CSS is built so the output is included
+	var embeddedDir string
+	if buildEmbedStatic {
+		cfg, err := nexo.LoadConfig("")
+		if err != nil {
+			cfg = nexo.DefaultConfig()
+		}
+		if _, err := generator.GenerateStaticEmbed(generator.StaticEmbedConfig{Dir: cfg.StaticDir}); err != nil {
+			if jsonOutput {
+				printJSONError(fmt.Errorf("failed to embed static files: %w", err))
+			} else {
+				red := color.New(color.FgRed).SprintFunc()
+				fmt.Printf("  %s Failed to embed static files: %v\n", red("Error:"), err)
+			}
+			os.Exit(1)
+		}
+		embeddedDir = cfg.StaticDir
+		generated = append(generated, "nexo_static.go")
+		if !jsonOutput {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("  %s Embedding %s/ (nexo_static.go)\n", green("✓"), embeddedDir)
+		}
 	}
 
 	// Build the binary
 	if !jsonOutput {
 		yellow := color.New(color.FgYellow).SprintFunc()
-		fmt.Printf("  %s Building binary...\n", yellow("→"))
+		if buildGenerate {
+			fmt.Printf("  %s Verifying generated code compiles...\n", yellow("→"))
+		} else {
+			fmt.Printf("  %s Building binary...\n", yellow("→"))
+		}
 	}
 
 	buildArgs := []string{
 		"build",
 		"-ldflags", "-s -w", // Strip debug info for smaller binary
 		"-o", outputPath,
-		".",
 	}
+	if buildEmbedStatic {
+		buildArgs = append(buildArgs, "-tags", generator.StaticEmbedTag)
+	}
+	buildArgs = append(buildArgs, ".")
 
 	buildEnv := os.Environ()
 	if buildOS != "" {
@@ -197,6 +299,9 @@ func runBuild(cmd *cobra.Command, args []string) {
 	if buildArch != "" {
 		buildEnv = append(buildEnv, fmt.Sprintf("GOARCH=%s", buildArch))
 	}
+	if buildDocker {
+		buildEnv = append(buildEnv, "GOOS=linux", "CGO_ENABLED=0")
+	}
 
 	goBuild := exec.Command("go", buildArgs...)
 	goBuild.Env = buildEnv
@@ -215,6 +320,31 @@ func runBuild(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if buildGenerate {
+		if jsonOutput {
+			printSuccess(BuildOutput{
+				OS:        targetOS,
+				Arch:      targetArch,
+				Generated: generated,
+				Static:    embeddedDir,
+				Success:   true,
+			})
+		} else {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("  %s Generated code compiles\n\n", green("✓"))
+			for _, file := range generated {
+				fmt.Printf("  %s\n", file)
+			}
+			fmt.Println()
+		}
+		return
+	}
+
+	if buildDocker {
+		runDockerBuild(dockerContext, targetOS, targetArch, embeddedDir)
+		return
+	}
+
 	// Get binary size
 	info, err := os.Stat(outputPath)
 	var size int64
@@ -230,6 +360,7 @@ func runBuild(cmd *cobra.Command, args []string) {
 			OS:      targetOS,
 			Arch:    targetArch,
 			Size:    size,
+			Static:  embeddedDir,
 			Success: true,
 		})
 	} else {
@@ -254,6 +385,16 @@ func runBuild(cmd *cobra.Command, args []string) {
 	}
 }
 
+// mainRegistersRoutes reports whether the main file at path calls the
+// generated RegisterRoutes function.
+func mainRegistersRoutes(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), "RegisterRoutes(")
+}
+
 // generateRoutesForBuild handles route generation with Next.js-style support
 func generateRoutesForBuild(appDir string) error {
 	// Check if there are Next.js-style directories
@@ -288,3 +429,51 @@ func generateRoutesForBuild(appDir string) error {
 	_, err := generator.ScanAndGenerateRoutes(appDir, "nexo_routes.go")
 	return err
 }
+
+// runDockerBuild builds the container image from the binary in contextDir
+// and prints how to run it.
+func runDockerBuild(contextDir, targetOS, targetArch, embeddedDir string) {
+	v := readProjectConfig()
+	name := dockerImageName(v)
+	image := name + ":" + dockerImageVersion(v)
+	tags := []string{image}
+	if !strings.HasSuffix(image, ":latest") {
+		tags = append(tags, name+":latest")
+	}
+
+	if !jsonOutput {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("  %s Building image %s...\n", yellow("→"), image)
+	}
+	if err := buildDockerImage(contextDir, tags, jsonOutput); err != nil {
+		if jsonOutput {
+			printJSONError(fmt.Errorf("docker build failed: %w", err))
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s Docker build failed: %v\n", red("Error:"), err)
+		}
+		os.Exit(1)
+	}
+
+	runCommand := fmt.Sprintf("docker run --rm -p 3000:3000 %s", image)
+	if jsonOutput {
+		printSuccess(BuildOutput{
+			OS:      targetOS,
+			Arch:    targetArch,
+			Image:   image,
+			Tags:    tags,
+			Static:  embeddedDir,
+			Run:     runCommand,
+			Success: true,
+		})
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("  %s Image built\n\n", green("✓"))
+	fmt.Printf("  Image:  %s\n", cyan(image))
+	fmt.Printf("  Tags:   %s\n", strings.Join(tags, ", "))
+	fmt.Printf("  Target: %s/%s\n", targetOS, targetArch)
+	fmt.Printf("\n  Run with: %s\n\n", cyan(runCommand))
+}
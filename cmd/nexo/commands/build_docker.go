@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// dockerBaseImage is the runtime image for nexo build --docker. The binary is
+// built without cgo, so it needs nothing beyond CA certificates and tzdata.
+const dockerBaseImage = "gcr.io/distroless/static-debian12:nonroot"
+
+// dockerfileTemplate is written into the build context next to the binary.
+const dockerfileTemplate = `FROM %s
+COPY app /app
+ENV PORT=3000
+EXPOSE 3000
+USER nonroot:nonroot
+ENTRYPOINT ["/app"]
+`
+
+var (
+	invalidImageNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+	invalidImageTagChars  = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// readProjectConfig reads nexo.yaml from the current directory. Missing or
+// invalid files yield an empty config.
+func readProjectConfig() *viper.Viper {
+	v := viper.New()
+	v.SetConfigName("nexo")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+	_ = v.ReadInConfig()
+	return v
+}
+
+// dockerImageName returns the image repository for the project: the name in
+// nexo.yaml, or the directory name, lowercased to a valid image name.
+func dockerImageName(v *viper.Viper) string {
+	name := v.GetString("name")
+	if name == "" {
+		cwd, _ := os.Getwd()
+		name = filepath.Base(cwd)
+	}
+
+	name = invalidImageNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "._-")
+	if name == "" {
+		name = "app"
+	}
+	return name
+}
+
+// dockerImageVersion returns the tag for the image: the version in nexo.yaml,
+// else `git describe` of the current commit, else "latest".
+func dockerImageVersion(v *viper.Viper) string {
+	if version := sanitizeImageTag(v.GetString("version")); version != "" {
+		return version
+	}
+
+	out, err := exec.Command("git", "describe", "--tags", "--always", "--dirty").Output()
+	if err != nil {
+		return "latest"
+	}
+	if tag := sanitizeImageTag(strings.TrimSpace(string(out))); tag != "" {
+		return tag
+	}
+	return "latest"
+}
+
+// sanitizeImageTag makes s a valid image tag: letters, digits, '_', '.' and
+// '-', not starting with '.' or '-', at most 128 characters.
+func sanitizeImageTag(s string) string {
+	tag := invalidImageTagChars.ReplaceAllString(s, "-")
+	tag = strings.TrimLeft(tag, ".-")
+	if len(tag) > 128 {
+		tag = tag[:128]
+	}
+	return tag
+}
+
+// buildDockerImage writes a Dockerfile next to the binary in contextDir and
+// builds the image with the given tags.
+func buildDockerImage(contextDir string, tags []string, quiet bool) error {
+	dockerfile := fmt.Sprintf(dockerfileTemplate, dockerBaseImage)
+	if err := os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		return fmt.Errorf("failed to write Dockerfile: %w", err)
+	}
+
+	args := []string{"build"}
+	for _, tag := range tags {
+		args = append(args, "-t", tag)
+	}
+	args = append(args, contextDir)
+
+	cmd := exec.Command("docker", args...)
+	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+	if !quiet {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	return cmd.Run()
+}
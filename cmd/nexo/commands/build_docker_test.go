@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestDockerImageName(t *testing.T) {
+	v := viper.New()
+	v.Set("name", "My App_v2!")
+	if got := dockerImageName(v); got != "my-app_v2" {
+		t.Errorf("dockerImageName = %q, want %q", got, "my-app_v2")
+	}
+}
+
+func TestDockerImageVersion_FromConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("version", "v1.2.3+build/7")
+	if got := dockerImageVersion(v); got != "v1.2.3-build-7" {
+		t.Errorf("dockerImageVersion = %q, want %q", got, "v1.2.3-build-7")
+	}
+}
+
+func TestSanitizeImageTag(t *testing.T) {
+	tests := map[string]string{
+		"v0.4.1":              "v0.4.1",
+		"v0.4.1-3-gabc-dirty": "v0.4.1-3-gabc-dirty",
+		".hidden":             "hidden",
+		"feature/login":       "feature-login",
+		"":                    "",
+	}
+	for in, want := range tests {
+		if got := sanitizeImageTag(in); got != want {
+			t.Errorf("sanitizeImageTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
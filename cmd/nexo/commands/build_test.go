@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMainRegistersRoutes(t *testing.T) {
+	dir := t.TempDir()
+	withRoutes := filepath.Join(dir, "with.go")
+	without := filepath.Join(dir, "without.go")
+	_ = os.WriteFile(withRoutes, []byte("package main\n\nfunc main() {\n\tapp := nexo.New()\n\tRegisterRoutes(app)\n}\n"), 0644)
+	_ = os.WriteFile(without, []byte("package main\n\nfunc main() {\n\t_ = nexo.New().Listen()\n}\n"), 0644)
+
+	if !mainRegistersRoutes(withRoutes) {
+		t.Error("expected RegisterRoutes call to be detected")
+	}
+	if mainRegistersRoutes(without) {
+		t.Error("expected no RegisterRoutes call")
+	}
+	if mainRegistersRoutes(filepath.Join(dir, "missing.go")) {
+		t.Error("expected false for a missing file")
+	}
+}
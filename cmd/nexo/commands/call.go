@@ -0,0 +1,413 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var callCmd = &cobra.Command{
+	Use:   "call <method> <path>",
+	Short: "Send a request to a route and print the response",
+	Long: `Send an HTTP request to your app and pretty-print the response.
+
+The request goes to the dev server if one is listening on the configured
+port. Otherwise the app is built and started on a free port for the request
+and stopped afterwards. Either way the request runs through the full
+middleware and proxy stack.
+
+The request body is given with --data: a literal string, @file to read a
+file, or - to read stdin. JSON bodies are sent as application/json unless
+a Content-Type header is set.
+
+Use --fail in CI smoke tests to exit with status 1 on 4xx and 5xx responses.
+
+Examples:
+  nexo call GET /api/health
+  nexo call GET /api/users/1 -i
+  nexo call POST /api/users --data '{"name":"Ada"}'
+  nexo call PUT /api/users/1 --data @user.json -H "Authorization: Bearer token"
+  nexo call GET /api/health --url https://staging.example.com --fail`,
+	Args: cobra.ExactArgs(2),
+	Run:  runCall,
+}
+
+var (
+	callData    string
+	callHeaders []string
+	callURL     string
+	callStart   bool
+	callInclude bool
+	callFail    bool
+	callTimeout time.Duration
+)
+
+func init() {
+	callCmd.Flags().StringVarP(&callData, "data", "d", "", "Request body (@file reads a file, - reads stdin)")
+	callCmd.Flags().StringArrayVarP(&callHeaders, "header", "H", nil, `Request header as "Name: value" (repeatable)`)
+	callCmd.Flags().StringVar(&callURL, "url", "", "Base URL of a running server (default: the dev server on the configured port)")
+	callCmd.Flags().BoolVar(&callStart, "start", false, "Always build and start the app, even if a server is running")
+	callCmd.Flags().BoolVarP(&callInclude, "include", "i", false, "Print response headers")
+	callCmd.Flags().BoolVar(&callFail, "fail", false, "Exit with status 1 on 4xx and 5xx responses")
+	callCmd.Flags().DurationVar(&callTimeout, "timeout", 30*time.Second, "Request timeout")
+	rootCmd.AddCommand(callCmd)
+}
+
+func runCall(cmd *cobra.Command, args []string) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	fail := func(err error) {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			fmt.Printf("  %s %v\n\n", color.RedString("Error:"), err)
+		}
+		os.Exit(1)
+	}
+
+	method := strings.ToUpper(args[0])
+	path := args[1]
+
+	body, err := readCallBody(callData)
+	if err != nil {
+		fail(err)
+	}
+	header, err := parseCallHeaders(callHeaders)
+	if err != nil {
+		fail(err)
+	}
+	if len(body) > 0 && header.Get("Content-Type") == "" && json.Valid(body) {
+		header.Set("Content-Type", "application/json")
+	}
+
+	// Pick the server: a full URL, --url, a running dev server, or the app
+	target := "url"
+	base := strings.TrimSuffix(callURL, "/")
+	var app *callApp
+	switch {
+	case strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://"):
+		base = ""
+	case base != "":
+	default:
+		port := callPort()
+		if !callStart && portAcceptsConnections(port) {
+			target = "server"
+			base = "http://localhost:" + port
+			break
+		}
+
+		target = "app"
+		if !jsonOutput {
+			fmt.Printf("  %s Building and starting the app...\n", dim("→"))
+		}
+		app, err = startCallApp()
+		if err != nil {
+			fail(err)
+		}
+		defer app.Stop()
+		base = "http://localhost:" + app.port
+	}
+	if base != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	req, err := http.NewRequest(method, base+path, bytes.NewReader(body))
+	if err != nil {
+		fail(err)
+	}
+	req.Header = header
+
+	client := &http.Client{
+		Timeout: callTimeout,
+		// Show redirects instead of following them
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		if app != nil {
+			err = fmt.Errorf("%w\n%s", err, app.Logs())
+		}
+		fail(err)
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		fail(fmt.Errorf("failed to read response: %w", err))
+	}
+	duration := time.Since(start)
+
+	if jsonOutput {
+		output := CallOutput{
+			Method:     method,
+			URL:        req.URL.String(),
+			Target:     target,
+			Status:     resp.StatusCode,
+			Headers:    flattenHeader(resp.Header),
+			DurationMs: duration.Milliseconds(),
+		}
+		if json.Valid(respBody) && len(respBody) > 0 {
+			output.Body = json.RawMessage(respBody)
+		} else {
+			output.Body = string(respBody)
+		}
+		printSuccess(output)
+	} else {
+		fmt.Printf("\n  %s %s %s\n", cyan(method), req.URL.String(), dim(fmt.Sprintf("(%s)", formatDuration(duration))))
+		fmt.Printf("  %s %s\n", statusColor(resp.StatusCode)(resp.Status), dim(resp.Proto))
+		if callInclude {
+			names := make([]string, 0, len(resp.Header))
+			for name := range resp.Header {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("  %s %s\n", dim(name+":"), strings.Join(resp.Header[name], ", "))
+			}
+		}
+		if len(respBody) > 0 {
+			fmt.Printf("\n%s\n", formatCallBody(respBody, resp.Header.Get("Content-Type")))
+		}
+		fmt.Println()
+
+		// Server errors are usually explained by the app's logs
+		if app != nil && resp.StatusCode >= 500 {
+			if logs := strings.TrimSpace(app.Logs()); logs != "" {
+				fmt.Printf("  %s\n%s\n\n", dim("App output:"), logs)
+			}
+		}
+	}
+
+	if callFail && resp.StatusCode >= 400 {
+		if app != nil {
+			app.Stop()
+		}
+		os.Exit(1)
+	}
+}
+
+// readCallBody returns the request body for --data: the value itself,
+// the contents of the file after @, or stdin for -.
+func readCallBody(data string) ([]byte, error) {
+	switch {
+	case data == "-":
+		return io.ReadAll(os.Stdin)
+	case strings.HasPrefix(data, "@"):
+		body, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		return body, nil
+	default:
+		return []byte(data), nil
+	}
+}
+
+// parseCallHeaders parses "Name: value" headers.
+func parseCallHeaders(headers []string) (http.Header, error) {
+	header := make(http.Header)
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q (use \"Name: value\")", h)
+		}
+		header.Add(name, strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+// flattenHeader joins multi-value headers for JSON output.
+func flattenHeader(h http.Header) map[string]string {
+	flat := make(map[string]string, len(h))
+	for name, values := range h {
+		flat[name] = strings.Join(values, ", ")
+	}
+	return flat
+}
+
+// formatCallBody indents JSON bodies and returns other bodies unchanged.
+func formatCallBody(body []byte, contentType string) string {
+	if strings.Contains(contentType, "json") || json.Valid(body) {
+		var out bytes.Buffer
+		if err := json.Indent(&out, body, "", "  "); err == nil {
+			return out.String()
+		}
+	}
+	return strings.TrimRight(string(body), "\n")
+}
+
+// statusColor returns the color function for an HTTP status code.
+func statusColor(status int) func(a ...interface{}) string {
+	switch {
+	case status >= 500:
+		return color.New(color.FgRed, color.Bold).SprintFunc()
+	case status >= 400:
+		return color.New(color.FgYellow, color.Bold).SprintFunc()
+	case status >= 300:
+		return color.New(color.FgCyan, color.Bold).SprintFunc()
+	default:
+		return color.New(color.FgGreen, color.Bold).SprintFunc()
+	}
+}
+
+// callPort returns the port the dev server listens on: $PORT, the port in
+// nexo.yaml, or 3000.
+func callPort() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return port
+	}
+	if cfg, err := nexo.LoadConfig(""); err == nil && cfg.Port != "" {
+		return cfg.Port
+	}
+	return "3000"
+}
+
+// portAcceptsConnections reports whether a server is listening on port.
+func portAcceptsConnections(port string) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", port), 300*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// callApp is the app started by nexo call.
+type callApp struct {
+	cmd  *exec.Cmd
+	dir  string
+	port string
+	done chan struct{}
+
+	mu   sync.Mutex
+	logs bytes.Buffer
+	once sync.Once
+}
+
+// Write collects the app's output.
+func (a *callApp) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.logs.Write(p)
+}
+
+// Logs returns the app's output so far.
+func (a *callApp) Logs() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.logs.String()
+}
+
+// Stop shuts the app down gracefully, killing it after 5 seconds, and
+// removes the binary.
+func (a *callApp) Stop() {
+	a.once.Do(func() {
+		_ = a.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-a.done:
+		case <-time.After(5 * time.Second):
+			_ = a.cmd.Process.Kill()
+			<-a.done
+		}
+		_ = os.RemoveAll(a.dir)
+	})
+}
+
+// startCallApp generates routes and templates, builds the app and starts it
+// on a free port, returning once the port accepts connections.
+func startCallApp() (*callApp, error) {
+	if _, err := os.Stat("main.go"); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no main.go found in current directory (run from your project root or pass --url)")
+	}
+
+	appDir := "app"
+	if cfg, err := nexo.LoadConfig(""); err == nil && cfg.AppDir != "" {
+		appDir = cfg.AppDir
+	}
+	if hasTemplFiles(".") {
+		if output, err := exec.Command("templ", "generate").CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("templ generate failed: %w\n%s", err, output)
+		}
+	}
+	if err := generateRoutes(appDir, false); err != nil {
+		return nil, fmt.Errorf("failed to generate routes: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "nexo-call-")
+	if err != nil {
+		return nil, err
+	}
+	binary := filepath.Join(dir, "app")
+	if output, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("build failed:\n%s", output)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to find a free port: %w", err)
+	}
+
+	app := &callApp{dir: dir, port: port, done: make(chan struct{})}
+	app.cmd = exec.Command(binary)
+	app.cmd.Env = append(os.Environ(), "PORT="+port)
+	app.cmd.Stdout = app
+	app.cmd.Stderr = app
+	if err := app.cmd.Start(); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to start app: %w", err)
+	}
+	go func() {
+		_ = app.cmd.Wait()
+		close(app.done)
+	}()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for !portAcceptsConnections(port) {
+		select {
+		case <-app.done:
+			_ = os.RemoveAll(dir)
+			return nil, fmt.Errorf("app exited before accepting requests:\n%s", app.Logs())
+		case <-time.After(100 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			app.Stop()
+			return nil, fmt.Errorf("app did not start listening on port %s:\n%s", port, app.Logs())
+		}
+	}
+	return app, nil
+}
+
+// hasTemplFiles reports whether dir contains .templ files.
+func hasTemplFiles(dir string) bool {
+	found := false
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasSuffix(path, ".templ") {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
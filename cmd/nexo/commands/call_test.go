@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCallHeaders(t *testing.T) {
+	header, err := parseCallHeaders([]string{"Authorization: Bearer token", "X-Tag:a", "X-Tag: b"})
+	if err != nil {
+		t.Fatalf("parseCallHeaders() error = %v", err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+	}
+	if got := header.Values("X-Tag"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("X-Tag = %v, want [a b]", got)
+	}
+
+	for _, h := range []string{"no-colon", ": value"} {
+		if _, err := parseCallHeaders([]string{h}); err == nil {
+			t.Errorf("parseCallHeaders(%q) expected an error", h)
+		}
+	}
+}
+
+func TestReadCallBody(t *testing.T) {
+	body, err := readCallBody(`{"x":1}`)
+	if err != nil || string(body) != `{"x":1}` {
+		t.Errorf("readCallBody(literal) = %q, %v", body, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(path, []byte(`{"from":"file"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	body, err = readCallBody("@" + path)
+	if err != nil || string(body) != `{"from":"file"}` {
+		t.Errorf("readCallBody(@file) = %q, %v", body, err)
+	}
+
+	if _, err := readCallBody("@" + filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("readCallBody(@missing) expected an error")
+	}
+}
+
+func TestFormatCallBody(t *testing.T) {
+	if got := formatCallBody([]byte(`{"a":[1,2]}`), "application/json"); got != "{\n  \"a\": [\n    1,\n    2\n  ]\n}" {
+		t.Errorf("formatCallBody(json) = %q", got)
+	}
+	if got := formatCallBody([]byte("hello\n"), "text/plain"); got != "hello" {
+		t.Errorf("formatCallBody(text) = %q", got)
+	}
+}
+
+func TestPortAcceptsConnections(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	if !portAcceptsConnections(port) {
+		t.Error("expected the listening port to accept connections")
+	}
+	_ = ln.Close()
+	if portAcceptsConnections(port) {
+		t.Error("expected the closed port to refuse connections")
+	}
+}
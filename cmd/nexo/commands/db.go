@@ -0,0 +1,437 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/abdul-hamid-achik/nexo/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the project database",
+	Long: `Manage the database of your Nexo project.
+
+Migrations are plain SQL files in the migrations/ directory, created with
+'nexo db create' and applied with the golang-migrate CLI.
+
+Seeds are Go functions in the seeds/ directory, created with
+'nexo generate seed' and run with 'nexo db seed'.
+
+The database URL is read from --database, the DATABASE_URL environment
+variable, DATABASE_URL in .env, or database.url in nexo.yaml, in that order.
+
+Commands:
+  nexo db create     Create a migration
+  nexo db migrate    Apply pending migrations
+  nexo db rollback   Roll back applied migrations
+  nexo db status     Show which migrations are applied
+  nexo db seed       Run seeds for an environment`,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending migrations",
+	Long: `Apply pending SQL migrations from the migrations/ directory.
+
+Migrations are run with the golang-migrate CLI, which must be installed
+and on your PATH.
+
+Examples:
+  nexo db migrate
+  nexo db migrate --steps 1
+  nexo db migrate --down --steps 1
+  nexo db migrate --database postgres://localhost:5432/app?sslmode=disable`,
+	Run: runDBMigrate,
+}
+
+var dbRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back applied migrations",
+	Long: `Roll back the most recently applied migrations by running their
+down files.
+
+Examples:
+  nexo db rollback
+  nexo db rollback --steps 3
+  nexo db rollback --all`,
+	Run: runDBRollback,
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which migrations are applied",
+	Long: `List the migrations in the migrations/ directory and whether each is
+applied to the database.
+
+Examples:
+  nexo db status
+  nexo db status --json`,
+	Run: runDBStatus,
+}
+
+var dbCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a migration",
+	Long: `Create a pair of up/down SQL migration files in the migrations/ directory.
+Same as 'nexo generate migration'.
+
+Examples:
+  nexo db create add_users_table`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDBCreate,
+}
+
+var dbSeedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Run seeds for an environment",
+	Long: `Run the seeds registered in the seeds/ directory.
+
+Only seeds declared for the selected environment are run, in name order.
+The environment defaults to $GO_ENV, or "development" if unset. The seeds
+are executed with 'go run ./cmd/seed' and receive DATABASE_URL.
+
+Examples:
+  nexo db seed
+  nexo db seed --env staging`,
+	Run: runDBSeed,
+}
+
+var (
+	dbDatabaseURL   string
+	dbMigrationsDir string
+	dbMigrateDown   bool
+	dbMigrateSteps  int
+	dbRollbackSteps int
+	dbRollbackAll   bool
+	dbSeedEnv       string
+)
+
+func init() {
+	dbCmd.PersistentFlags().StringVar(&dbDatabaseURL, "database", "", "Database URL (default: $DATABASE_URL)")
+	dbCmd.PersistentFlags().StringVar(&dbMigrationsDir, "dir", tools.DefaultMigrationsDir, "Migrations directory")
+
+	dbMigrateCmd.Flags().BoolVar(&dbMigrateDown, "down", false, "Roll back migrations instead of applying them")
+	dbMigrateCmd.Flags().IntVar(&dbMigrateSteps, "steps", 0, "Number of migrations to apply or roll back (default: all)")
+
+	dbRollbackCmd.Flags().IntVar(&dbRollbackSteps, "steps", 1, "Number of migrations to roll back")
+	dbRollbackCmd.Flags().BoolVar(&dbRollbackAll, "all", false, "Roll back all migrations")
+
+	dbSeedCmd.Flags().StringVar(&dbSeedEnv, "env", "", "Environment to seed (default: $GO_ENV or development)")
+
+	dbCmd.AddCommand(dbCreateCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbRollbackCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+	dbCmd.AddCommand(dbSeedCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+// errNoDatabaseURL is returned when no database URL is configured.
+var errNoDatabaseURL = fmt.Errorf("no database URL configured (set DATABASE_URL, add it to .env, set database.url in nexo.yaml or pass --database)")
+
+// databaseURL returns the database URL from the --database flag, the
+// DATABASE_URL environment variable, DATABASE_URL in .env, or database.url
+// in nexo.yaml. Environment variables in the nexo.yaml value are expanded.
+func databaseURL() string {
+	if dbDatabaseURL != "" {
+		return dbDatabaseURL
+	}
+	if url := os.Getenv("DATABASE_URL"); url != "" {
+		return url
+	}
+	if env, err := loadEnvFile(".env"); err == nil && env["DATABASE_URL"] != "" {
+		return env["DATABASE_URL"]
+	}
+	return os.ExpandEnv(readProjectConfig().GetString("database.url"))
+}
+
+// migrationsDir returns the migrations directory from --dir, or
+// database.migrations_dir in nexo.yaml.
+func migrationsDir(cmd *cobra.Command) string {
+	if !cmd.Flags().Changed("dir") {
+		if dir := readProjectConfig().GetString("database.migrations_dir"); dir != "" {
+			return dir
+		}
+	}
+	return dbMigrationsDir
+}
+
+// exitDBError prints err and exits.
+func exitDBError(err error) {
+	if jsonOutput {
+		printJSONError(err)
+	} else {
+		fmt.Printf("  %s %v\n\n", color.RedString("Error:"), err)
+	}
+	os.Exit(1)
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	dir := migrationsDir(cmd)
+	url := databaseURL()
+	if url == "" {
+		exitDBError(errNoDatabaseURL)
+	}
+
+	direction := "up"
+	if dbMigrateDown {
+		direction = "down"
+	}
+
+	if !jsonOutput {
+		fmt.Printf("\n  %s Running migrations (%s) from %s\n\n", cyan("→"), direction, dir)
+	}
+
+	migrate := tools.NewMigrateCLI(dir, url)
+	var err error
+	if dbMigrateDown {
+		err = migrate.Down(dbMigrateSteps)
+	} else {
+		err = migrate.Up(dbMigrateSteps)
+	}
+	if err != nil {
+		exitDBError(err)
+	}
+
+	if jsonOutput {
+		printSuccess(DBMigrateOutput{
+			Direction: direction,
+			Dir:       dir,
+			Steps:     dbMigrateSteps,
+		})
+		return
+	}
+
+	fmt.Printf("\n  %s Migrations complete\n\n", green("✓"))
+}
+
+func runDBRollback(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	dir := migrationsDir(cmd)
+	url := databaseURL()
+	if url == "" {
+		exitDBError(errNoDatabaseURL)
+	}
+
+	steps := dbRollbackSteps
+	if dbRollbackAll {
+		steps = 0
+	} else if steps < 1 {
+		exitDBError(fmt.Errorf("--steps must be at least 1 (use --all to roll back everything)"))
+	}
+
+	if !jsonOutput {
+		what := "all migrations"
+		if steps > 0 {
+			what = fmt.Sprintf("%d migration(s)", steps)
+		}
+		fmt.Printf("\n  %s Rolling back %s from %s\n\n", cyan("→"), what, dir)
+	}
+
+	if err := tools.NewMigrateCLI(dir, url).Down(steps); err != nil {
+		exitDBError(err)
+	}
+
+	if jsonOutput {
+		printSuccess(DBMigrateOutput{
+			Direction: "down",
+			Dir:       dir,
+			Steps:     steps,
+		})
+		return
+	}
+
+	fmt.Printf("\n  %s Rollback complete\n\n", green("✓"))
+}
+
+func runDBStatus(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	dir := migrationsDir(cmd)
+	url := databaseURL()
+	if url == "" {
+		exitDBError(errNoDatabaseURL)
+	}
+
+	migrations, err := tools.ListMigrations(dir)
+	if err != nil {
+		exitDBError(err)
+	}
+	version, dirty, err := tools.NewMigrateCLI(dir, url).Version()
+	if err != nil {
+		exitDBError(err)
+	}
+
+	output := DBStatusOutput{
+		Dir:        dir,
+		Version:    version,
+		Dirty:      dirty,
+		Migrations: migrationStatuses(migrations, version, dirty),
+	}
+	for _, m := range output.Migrations {
+		if m.Status == "pending" {
+			output.Pending++
+		}
+	}
+
+	if jsonOutput {
+		printSuccess(output)
+		return
+	}
+
+	fmt.Printf("\n  Migrations in %s\n\n", dir)
+	if len(output.Migrations) == 0 {
+		fmt.Printf("  %s\n\n", dim("No migrations found. Create one with 'nexo db create <name>'"))
+		return
+	}
+	for _, m := range output.Migrations {
+		status := green("applied")
+		switch m.Status {
+		case "pending":
+			status = yellow("pending")
+		case "dirty":
+			status = red("dirty  ")
+		}
+		fmt.Printf("    %s  %d  %s\n", status, m.Version, m.Name)
+	}
+	fmt.Println()
+
+	switch {
+	case dirty:
+		fmt.Printf("  %s Migration %d failed part-way. Fix the database, then force the version with the migrate CLI.\n\n", red("Error:"), version)
+	case output.Pending > 0:
+		fmt.Printf("  %d pending migration(s). Run 'nexo db migrate' to apply them.\n\n", output.Pending)
+	default:
+		fmt.Printf("  %s Database is up to date\n\n", green("✓"))
+	}
+}
+
+// migrationStatuses reports each migration as applied, pending or dirty
+// given the current database version.
+func migrationStatuses(migrations []tools.Migration, version uint64, dirty bool) []MigrationStatusOutput {
+	statuses := make([]MigrationStatusOutput, 0, len(migrations))
+	for _, m := range migrations {
+		status := "pending"
+		switch {
+		case m.Version == version && dirty:
+			status = "dirty"
+		case m.Version <= version:
+			status = "applied"
+		}
+		statuses = append(statuses, MigrationStatusOutput{
+			Version: m.Version,
+			Name:    m.Name,
+			Status:  status,
+		})
+	}
+	return statuses
+}
+
+func runDBCreate(cmd *cobra.Command, args []string) {
+	dir := migrationsDir(cmd)
+	result, err := generator.GenerateMigration(generator.MigrationConfig{
+		Name: args[0],
+		Dir:  dir,
+	})
+	if err != nil {
+		exitDBError(err)
+	}
+
+	recordGeneration("db create", result.Files)
+
+	if jsonOutput {
+		printSuccess(GenerateOutput{
+			Command: "db create",
+			Path:    dir,
+			Files:   result.Files,
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Created migration\n\n", green("✓"))
+	for _, f := range result.Files {
+		fmt.Printf("    Created: %s\n", cyan(f))
+	}
+	fmt.Printf("\n  Run 'nexo db migrate' to apply it\n\n")
+}
+
+func runDBSeed(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	env := dbSeedEnv
+	if env == "" {
+		env = os.Getenv("GO_ENV")
+	}
+	if env == "" {
+		env = "development"
+	}
+
+	fail := func(err error) {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			fmt.Printf("  %s %v\n\n", red("Error:"), err)
+		}
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(filepath.Join("cmd", "seed", "main.go")); os.IsNotExist(err) {
+		fail(fmt.Errorf("no seed runner found at cmd/seed/main.go (create one with 'nexo generate seed <name>')"))
+	}
+
+	url := databaseURL()
+	if url == "" {
+		fail(errNoDatabaseURL)
+	}
+
+	if !jsonOutput {
+		fmt.Printf("\n  %s Seeding %s database\n\n", cyan("→"), env)
+	}
+
+	seedCmd := exec.Command("go", "run", "./cmd/seed", "-env", env)
+	seedCmd.Env = append(os.Environ(), "DATABASE_URL="+url)
+	output, err := seedCmd.CombinedOutput()
+	if err != nil {
+		fail(fmt.Errorf("seed failed: %w\n%s", err, output))
+	}
+
+	var seeded []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if name, ok := strings.CutPrefix(line, "seeded "); ok {
+			seeded = append(seeded, name)
+		}
+	}
+
+	if jsonOutput {
+		printSuccess(DBSeedOutput{
+			Env:    env,
+			Seeded: seeded,
+		})
+		return
+	}
+
+	for _, name := range seeded {
+		fmt.Printf("    Seeded: %s\n", cyan(name))
+	}
+	fmt.Printf("\n  %s Seeded %d seed(s)\n\n", green("✓"), len(seeded))
+}
@@ -16,19 +16,20 @@ import (
 )
 
 var (
-	deployNoBuild   bool
-	deployEnvVars   []string
-	deployApp       string
-	deployEnvFile   string
-	deployNoEnvFile bool
+	deployNoBuild      bool
+	deployEnvVars      []string
+	deployApp          string
+	deployEnvFile      string
+	deployNoEnvFile    bool
+	deployProviderFlag string
 )
 
 var deployCmd = &cobra.Command{
 	Use:   "deploy",
-	Short: "Build and deploy to Nexo Cloud",
-	Long: `Build and deploy the current project to Nexo Cloud.
+	Short: "Build and deploy to Nexo Cloud, Fly.io, Railway or a VPS",
+	Long: `Build and deploy the current project.
 
-This command will:
+By default this deploys to Nexo Cloud:
 1. Read nexo.yaml for app configuration
 2. Build the Go binary (unless --no-build)
 3. Build a Docker image
@@ -36,13 +37,23 @@ This command will:
 5. Trigger deployment on Nexo Cloud
 6. Stream deployment logs
 
+Set deploy.provider in nexo.yaml (or use --provider) to deploy elsewhere:
+  fly       Deploy with flyctl to Fly.io
+  railway   Deploy with the railway CLI to Railway
+  vps       Upload with rsync to a server and run it with systemd
+
+These providers build a Linux binary with templates, CSS, routes and static
+files, upload it, and check the app responds at deploy.url (or the
+provider's URL) plus deploy.health_check.
+
 Examples:
   nexo deploy                    # Deploy current directory
   nexo deploy --no-build         # Skip build, use existing image
   nexo deploy --env KEY=value    # Set env var for this deployment
   nexo deploy --app my-app       # Deploy to specific app
   nexo deploy --env-file .env    # Load env vars from file
-  nexo deploy --no-env-file      # Skip auto-loading .env file`,
+  nexo deploy --no-env-file      # Skip auto-loading .env file
+  nexo deploy --provider fly     # Deploy to Fly.io`,
 	Run: runDeploy,
 }
 
@@ -52,6 +63,7 @@ func init() {
 	deployCmd.Flags().StringVar(&deployApp, "app", "", "App name (defaults to name in nexo.yaml)")
 	deployCmd.Flags().StringVar(&deployEnvFile, "env-file", "", "Load environment variables from file (default: .env if exists)")
 	deployCmd.Flags().BoolVar(&deployNoEnvFile, "no-env-file", false, "Skip auto-loading .env file")
+	deployCmd.Flags().StringVar(&deployProviderFlag, "provider", "", "Deploy provider: cloud, fly, railway or vps (defaults to deploy.provider in nexo.yaml)")
 
 	rootCmd.AddCommand(deployCmd)
 }
@@ -66,6 +78,33 @@ func runDeploy(cmd *cobra.Command, args []string) {
 		fmt.Printf("\n  %s Deploy\n\n", cyan("Nexo"))
 	}
 
+	// Deploy with another provider if nexo.yaml or --provider selects one
+	projectConfig := readProjectConfig()
+	providerApp := deployApp
+	if providerApp == "" {
+		providerApp = projectConfig.GetString("name")
+	}
+	deployCfg, err := loadDeployConfig(projectConfig, providerApp)
+	if err == nil && deployProviderFlag != "" {
+		deployCfg.Provider = deployProviderFlag
+	}
+	if err == nil && deployCfg.Provider != deployProviderCloud {
+		var envMap map[string]string
+		envMap, err = loadDeployEnv()
+		if err == nil {
+			runProviderDeploy(deployCfg, envMap)
+			return
+		}
+	}
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		os.Exit(1)
+	}
+
 	// Load credentials
 	client, err := cloud.NewClientFromCredentials()
 	if err != nil {
@@ -174,45 +213,14 @@ func runDeploy(cmd *cobra.Command, args []string) {
 	}
 
 	// Load environment variables from file and/or flags
-	envMap := make(map[string]string)
-
-	// Auto-load .env file unless disabled
-	if !deployNoEnvFile {
-		envFile := deployEnvFile
-		if envFile == "" {
-			// Check for .env file in current directory
-			if _, err := os.Stat(".env"); err == nil {
-				envFile = ".env"
-			}
-		}
-
-		if envFile != "" {
-			if !jsonOutput {
-				fmt.Printf("  %s Loading environment from %s...\n", dim("->"), envFile)
-			}
-
-			fileEnv, err := loadEnvFile(envFile)
-			if err != nil {
-				if jsonOutput {
-					printJSONError(fmt.Errorf("failed to load env file: %w", err))
-				} else {
-					fmt.Printf("  %s Failed to load env file: %v\n", red("Error:"), err)
-				}
-				os.Exit(1)
-			}
-
-			for k, v := range fileEnv {
-				envMap[k] = v
-			}
-		}
-	}
-
-	// Override with command-line env vars
-	for _, env := range deployEnvVars {
-		parts := strings.SplitN(env, "=", 2)
-		if len(parts) == 2 {
-			envMap[parts[0]] = parts[1]
+	envMap, err := loadDeployEnv()
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			fmt.Printf("  %s %v\n", red("Error:"), err)
 		}
+		os.Exit(1)
 	}
 
 	// Set environment variables if any
@@ -555,6 +563,50 @@ CMD ["./app"]
 	}
 }
 
+// loadDeployEnv collects the environment variables for a deployment: the
+// --env-file (or .env if present, unless --no-env-file), overridden by --env
+// flags.
+func loadDeployEnv() (map[string]string, error) {
+	dim := color.New(color.Faint).SprintFunc()
+	envMap := make(map[string]string)
+
+	// Auto-load .env file unless disabled
+	if !deployNoEnvFile {
+		envFile := deployEnvFile
+		if envFile == "" {
+			// Check for .env file in current directory
+			if _, err := os.Stat(".env"); err == nil {
+				envFile = ".env"
+			}
+		}
+
+		if envFile != "" {
+			if !jsonOutput {
+				fmt.Printf("  %s Loading environment from %s...\n", dim("->"), envFile)
+			}
+
+			fileEnv, err := loadEnvFile(envFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load env file: %w", err)
+			}
+
+			for k, v := range fileEnv {
+				envMap[k] = v
+			}
+		}
+	}
+
+	// Override with command-line env vars
+	for _, env := range deployEnvVars {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) == 2 {
+			envMap[parts[0]] = parts[1]
+		}
+	}
+
+	return envMap, nil
+}
+
 // loadEnvFile reads a .env file and returns a map of key-value pairs.
 // It supports:
 // - KEY=value
@@ -0,0 +1,559 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/fatih/color"
+	"github.com/spf13/viper"
+)
+
+// Deploy providers selected with the deploy.provider key or --provider.
+const (
+	deployProviderCloud   = "cloud"
+	deployProviderFly     = "fly"
+	deployProviderRailway = "railway"
+	deployProviderVPS     = "vps"
+)
+
+// deployConfig is the deploy: block of nexo.yaml.
+type deployConfig struct {
+	Provider      string        `mapstructure:"provider"`
+	Arch          string        `mapstructure:"arch"`
+	URL           string        `mapstructure:"url"`
+	HealthCheck   string        `mapstructure:"health_check"`
+	HealthTimeout time.Duration `mapstructure:"health_timeout"`
+
+	Fly     flyDeployConfig     `mapstructure:"fly"`
+	Railway railwayDeployConfig `mapstructure:"railway"`
+	VPS     vpsDeployConfig     `mapstructure:"vps"`
+}
+
+// flyDeployConfig configures deploys to Fly.io with flyctl.
+type flyDeployConfig struct {
+	App    string `mapstructure:"app"`
+	Config string `mapstructure:"config"`
+}
+
+// railwayDeployConfig configures deploys to Railway with the railway CLI.
+type railwayDeployConfig struct {
+	Service     string `mapstructure:"service"`
+	Environment string `mapstructure:"environment"`
+}
+
+// vpsDeployConfig configures deploys to a server over SSH: the binary is
+// uploaded with rsync and run by a systemd unit.
+type vpsDeployConfig struct {
+	Host    string `mapstructure:"host"`
+	Path    string `mapstructure:"path"`
+	Service string `mapstructure:"service"`
+	User    string `mapstructure:"user"`
+	Port    string `mapstructure:"port"`
+	SSHKey  string `mapstructure:"ssh_key"`
+}
+
+// loadDeployConfig reads the deploy: block of nexo.yaml and fills in
+// defaults. The app name is used for the Fly app and systemd service.
+func loadDeployConfig(v *viper.Viper, appName string) (deployConfig, error) {
+	var cfg deployConfig
+	if err := v.UnmarshalKey("deploy", &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid deploy config in nexo.yaml: %w", err)
+	}
+
+	if cfg.Provider == "" {
+		cfg.Provider = deployProviderCloud
+	}
+	if cfg.Arch == "" {
+		cfg.Arch = "amd64"
+	}
+	if cfg.HealthCheck == "" {
+		cfg.HealthCheck = "/"
+	}
+	if cfg.HealthTimeout == 0 {
+		cfg.HealthTimeout = 60 * time.Second
+	}
+	if cfg.Fly.App == "" {
+		cfg.Fly.App = appName
+	}
+	if cfg.VPS.Service == "" {
+		cfg.VPS.Service = appName
+	}
+	if cfg.VPS.Path == "" && cfg.VPS.Service != "" {
+		cfg.VPS.Path = "/opt/" + cfg.VPS.Service
+	}
+	if cfg.VPS.Port == "" {
+		cfg.VPS.Port = "3000"
+	}
+	return cfg, nil
+}
+
+// deployProvider deploys a build context — a directory holding the Linux
+// binary named "app" — to a hosting provider.
+type deployProvider interface {
+	// Check verifies that the provider's CLI tools are installed and the
+	// config has what the provider needs.
+	Check() error
+	// SetEnv sets environment variables for the app.
+	SetEnv(env map[string]string) error
+	// Deploy uploads the build context and starts the new release.
+	Deploy(contextDir string) error
+	// URL is the public URL of the app, or "" if unknown.
+	URL() string
+}
+
+// newDeployProvider returns the provider for cfg.Provider.
+func newDeployProvider(cfg deployConfig) (deployProvider, error) {
+	switch cfg.Provider {
+	case deployProviderFly:
+		return &flyProvider{cfg: cfg}, nil
+	case deployProviderRailway:
+		return &railwayProvider{cfg: cfg}, nil
+	case deployProviderVPS:
+		return &vpsProvider{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unknown deploy provider %q (use cloud, fly, railway or vps)", cfg.Provider)
+	}
+}
+
+// runDeployCommand runs a provider CLI, streaming its output unless --json
+// is set, in which case the output is included in the error.
+func runDeployCommand(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	var out bytes.Buffer
+	if jsonOutput {
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		if out.Len() > 0 {
+			return fmt.Errorf("%s %s: %w\n%s", name, args[0], err, strings.TrimSpace(out.String()))
+		}
+		return fmt.Errorf("%s %s: %w", name, args[0], err)
+	}
+	return nil
+}
+
+// envPairs returns env as sorted KEY=value pairs.
+func envPairs(env map[string]string) []string {
+	pairs := make([]string, 0, len(env))
+	for k, v := range env {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// flyProvider deploys with flyctl, which builds the Dockerfile in the build
+// context on Fly's remote builders.
+type flyProvider struct {
+	cfg deployConfig
+}
+
+func (p *flyProvider) Check() error {
+	if _, err := exec.LookPath("flyctl"); err != nil {
+		return fmt.Errorf("flyctl not found, install it from https://fly.io/docs/flyctl/install/")
+	}
+	if p.cfg.Fly.App == "" {
+		return fmt.Errorf("fly app name required. Set 'deploy.fly.app' or 'name' in nexo.yaml")
+	}
+	return nil
+}
+
+func (p *flyProvider) SetEnv(env map[string]string) error {
+	args := append([]string{"secrets", "set", "--app", p.cfg.Fly.App, "--stage"}, envPairs(env)...)
+	return runDeployCommand("flyctl", args...)
+}
+
+func (p *flyProvider) Deploy(contextDir string) error {
+	return runDeployCommand("flyctl", p.deployArgs(contextDir)...)
+}
+
+func (p *flyProvider) deployArgs(contextDir string) []string {
+	args := []string{"deploy", contextDir, "--app", p.cfg.Fly.App, "--remote-only", "--yes"}
+	if p.cfg.Fly.Config != "" {
+		config, _ := filepath.Abs(p.cfg.Fly.Config)
+		args = append(args, "--config", config)
+	}
+	return args
+}
+
+func (p *flyProvider) URL() string {
+	if p.cfg.URL != "" {
+		return p.cfg.URL
+	}
+	return fmt.Sprintf("https://%s.fly.dev", p.cfg.Fly.App)
+}
+
+// railwayProvider deploys with `railway up`, which uploads the build context
+// and builds its Dockerfile. The project must be linked with `railway link`.
+type railwayProvider struct {
+	cfg deployConfig
+}
+
+func (p *railwayProvider) Check() error {
+	if _, err := exec.LookPath("railway"); err != nil {
+		return fmt.Errorf("railway CLI not found, install it from https://docs.railway.com/guides/cli")
+	}
+	return nil
+}
+
+func (p *railwayProvider) SetEnv(env map[string]string) error {
+	args := []string{"variables", "--skip-deploys"}
+	args = append(args, p.targetArgs()...)
+	for _, pair := range envPairs(env) {
+		args = append(args, "--set", pair)
+	}
+	return runDeployCommand("railway", args...)
+}
+
+func (p *railwayProvider) Deploy(contextDir string) error {
+	return runDeployCommand("railway", p.upArgs(contextDir)...)
+}
+
+func (p *railwayProvider) upArgs(contextDir string) []string {
+	args := []string{"up", contextDir, "--ci"}
+	return append(args, p.targetArgs()...)
+}
+
+func (p *railwayProvider) targetArgs() []string {
+	var args []string
+	if p.cfg.Railway.Service != "" {
+		args = append(args, "--service", p.cfg.Railway.Service)
+	}
+	if p.cfg.Railway.Environment != "" {
+		args = append(args, "--environment", p.cfg.Railway.Environment)
+	}
+	return args
+}
+
+// Railway URLs are assigned per service, so health checks need deploy.url.
+func (p *railwayProvider) URL() string {
+	return p.cfg.URL
+}
+
+// systemdUnitTemplate runs the uploaded binary as a systemd service.
+var systemdUnitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description={{.Service}} (nexo)
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+{{- if .User}}
+User={{.User}}
+{{- end}}
+WorkingDirectory={{.Path}}
+ExecStart={{.Path}}/app
+Environment=PORT={{.Port}}
+EnvironmentFile=-{{.Path}}/.env
+Restart=on-failure
+RestartSec=2
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// vpsProvider deploys to a server over SSH: the build context, a systemd
+// unit and a .env file are synced with rsync, then the service is restarted.
+type vpsProvider struct {
+	cfg deployConfig
+	env map[string]string
+}
+
+func (p *vpsProvider) Check() error {
+	for _, tool := range []string{"ssh", "rsync"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not found, it is required for vps deploys", tool)
+		}
+	}
+	if p.cfg.VPS.Host == "" {
+		return fmt.Errorf("vps host required. Set 'deploy.vps.host' in nexo.yaml (e.g., deploy@203.0.113.10)")
+	}
+	if p.cfg.VPS.Service == "" {
+		return fmt.Errorf("vps service name required. Set 'deploy.vps.service' or 'name' in nexo.yaml")
+	}
+	return nil
+}
+
+// SetEnv stores env to be uploaded as the service's .env file on Deploy.
+func (p *vpsProvider) SetEnv(env map[string]string) error {
+	p.env = env
+	return nil
+}
+
+func (p *vpsProvider) Deploy(contextDir string) error {
+	unit, err := p.unit()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(contextDir, p.cfg.VPS.Service+".service"), unit, 0644); err != nil {
+		return fmt.Errorf("failed to write systemd unit: %w", err)
+	}
+	if len(p.env) > 0 {
+		env := strings.Join(envPairs(p.env), "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(contextDir, ".env"), []byte(env), 0600); err != nil {
+			return fmt.Errorf("failed to write env file: %w", err)
+		}
+	}
+
+	if err := runDeployCommand("ssh", p.sshArgs("mkdir -p "+p.cfg.VPS.Path)...); err != nil {
+		return err
+	}
+	if err := runDeployCommand("rsync", p.rsyncArgs(contextDir)...); err != nil {
+		return err
+	}
+	return runDeployCommand("ssh", p.sshArgs(p.restartScript())...)
+}
+
+func (p *vpsProvider) unit() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := systemdUnitTemplate.Execute(&buf, p.cfg.VPS); err != nil {
+		return nil, fmt.Errorf("failed to render systemd unit: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (p *vpsProvider) sshArgs(command string) []string {
+	var args []string
+	if p.cfg.VPS.SSHKey != "" {
+		args = append(args, "-i", p.cfg.VPS.SSHKey)
+	}
+	return append(args, p.cfg.VPS.Host, command)
+}
+
+func (p *vpsProvider) rsyncArgs(contextDir string) []string {
+	args := []string{"-az", "--delete"}
+	if len(p.env) == 0 {
+		// Keep an env file managed on the server
+		args = append(args, "--exclude", ".env")
+	}
+	if p.cfg.VPS.SSHKey != "" {
+		args = append(args, "-e", "ssh -i "+p.cfg.VPS.SSHKey)
+	}
+	return append(args, strings.TrimSuffix(contextDir, "/")+"/", p.cfg.VPS.Host+":"+p.cfg.VPS.Path+"/")
+}
+
+// restartScript installs the unit and restarts the service. It uses sudo
+// unless the SSH user is root.
+func (p *vpsProvider) restartScript() string {
+	service := p.cfg.VPS.Service
+	unit := fmt.Sprintf("%s/%s.service", p.cfg.VPS.Path, service)
+	return strings.Join([]string{
+		"set -e",
+		`SUDO=""; [ "$(id -u)" -eq 0 ] || SUDO=sudo`,
+		fmt.Sprintf("$SUDO install -m 644 %s /etc/systemd/system/%s.service", unit, service),
+		"$SUDO systemctl daemon-reload",
+		fmt.Sprintf("$SUDO systemctl enable %s", service),
+		fmt.Sprintf("$SUDO systemctl restart %s", service),
+	}, "\n")
+}
+
+func (p *vpsProvider) URL() string {
+	if p.cfg.URL != "" {
+		return p.cfg.URL
+	}
+	host := p.cfg.VPS.Host
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	return fmt.Sprintf("http://%s:%s", host, p.cfg.VPS.Port)
+}
+
+// buildDeployContext builds the app for Linux into a new temporary directory
+// by running `nexo build`, so deploys get the same templ, CSS and route
+// generation as local builds. The static directory is embedded when present.
+// Fly and Railway build the Dockerfile written next to the binary.
+func buildDeployContext(cfg deployConfig) (string, error) {
+	contextDir, err := os.MkdirTemp("", "nexo-deploy-")
+	if err != nil {
+		return "", err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		_ = os.RemoveAll(contextDir)
+		return "", err
+	}
+
+	args := []string{"build", "--os", "linux", "--arch", cfg.Arch, "--output", filepath.Join(contextDir, "app")}
+	if projectCfg, err := nexo.LoadConfig(""); err == nil {
+		if info, err := os.Stat(projectCfg.StaticDir); err == nil && info.IsDir() {
+			args = append(args, "--embed-static")
+		}
+	}
+	if jsonOutput {
+		args = append(args, "--json")
+	}
+
+	build := exec.Command(self, args...)
+	build.Env = append(os.Environ(), "CGO_ENABLED=0")
+	var out bytes.Buffer
+	if jsonOutput {
+		build.Stdout = &out
+		build.Stderr = &out
+	} else {
+		build.Stdout = os.Stdout
+		build.Stderr = os.Stderr
+	}
+	if err := build.Run(); err != nil {
+		_ = os.RemoveAll(contextDir)
+		return "", fmt.Errorf("build failed: %w %s", err, strings.TrimSpace(out.String()))
+	}
+
+	if cfg.Provider == deployProviderFly || cfg.Provider == deployProviderRailway {
+		dockerfile := fmt.Sprintf(dockerfileTemplate, dockerBaseImage)
+		if err := os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+			_ = os.RemoveAll(contextDir)
+			return "", fmt.Errorf("failed to write Dockerfile: %w", err)
+		}
+	}
+	return contextDir, nil
+}
+
+// waitForHealthy polls url until it responds with a status below 400 or
+// timeout expires.
+func waitForHealthy(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(timeout)
+
+	var lastErr error
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode < 400 {
+				return nil
+			}
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+		} else {
+			lastErr = err
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s is not healthy after %s: %w", url, timeout, lastErr)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// runProviderDeploy builds the app and deploys it with the provider in cfg,
+// then verifies the deployment with a health check.
+func runProviderDeploy(cfg deployConfig, env map[string]string) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	fail := func(err error) {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		os.Exit(1)
+	}
+
+	if deployNoBuild {
+		fail(fmt.Errorf("--no-build is only supported for Nexo Cloud deploys"))
+	}
+
+	provider, err := newDeployProvider(cfg)
+	if err != nil {
+		fail(err)
+	}
+	if err := provider.Check(); err != nil {
+		fail(err)
+	}
+
+	if !jsonOutput {
+		fmt.Printf("  %s Provider: %s\n", dim("->"), cyan(cfg.Provider))
+		fmt.Printf("  %s Target: linux/%s\n\n", dim("->"), cfg.Arch)
+	}
+
+	// Step 1: Environment variables
+	if len(env) > 0 {
+		if !jsonOutput {
+			fmt.Printf("  %s Setting %d environment variable(s)...\n", yellow("->"), len(env))
+		}
+		if err := provider.SetEnv(env); err != nil {
+			fail(fmt.Errorf("failed to set env vars: %w", err))
+		}
+		if !jsonOutput {
+			fmt.Printf("  %s Environment configured\n\n", green("OK"))
+		}
+	}
+
+	// Step 2: Build binary and assets
+	if !jsonOutput {
+		fmt.Printf("  %s Building application...\n", yellow("->"))
+	}
+	contextDir, err := buildDeployContext(cfg)
+	if err != nil {
+		fail(err)
+	}
+	defer func() { _ = os.RemoveAll(contextDir) }()
+	if !jsonOutput {
+		fmt.Printf("  %s Build complete\n\n", green("OK"))
+	}
+
+	// Step 3: Upload and release
+	if !jsonOutput {
+		fmt.Printf("  %s Deploying to %s...\n", yellow("->"), cfg.Provider)
+	}
+	if err := provider.Deploy(contextDir); err != nil {
+		_ = os.RemoveAll(contextDir)
+		fail(fmt.Errorf("deployment failed: %w", err))
+	}
+	if !jsonOutput {
+		fmt.Printf("  %s Deployed\n\n", green("OK"))
+	}
+
+	// Step 4: Health check
+	appURL := strings.TrimSuffix(provider.URL(), "/")
+	status := "deployed"
+	if appURL != "" {
+		healthURL := appURL + "/" + strings.TrimPrefix(cfg.HealthCheck, "/")
+		if !jsonOutput {
+			fmt.Printf("  %s Checking %s...\n", yellow("->"), healthURL)
+		}
+		if err := waitForHealthy(healthURL, cfg.HealthTimeout); err != nil {
+			_ = os.RemoveAll(contextDir)
+			fail(fmt.Errorf("health check failed: %w", err))
+		}
+		status = "healthy"
+		if !jsonOutput {
+			fmt.Printf("  %s Healthy\n", green("OK"))
+		}
+	} else if !jsonOutput {
+		fmt.Printf("  %s Skipping health check: set 'deploy.url' in nexo.yaml\n", yellow("!"))
+	}
+
+	if jsonOutput {
+		printSuccess(DeployOutput{
+			Success:  true,
+			Provider: cfg.Provider,
+			Status:   status,
+			URL:      appURL,
+			Message:  "Deployment successful",
+		})
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("  %s Deployment successful!\n", green("OK"))
+	if appURL != "" {
+		fmt.Printf("  URL: %s\n", cyan(appURL))
+	}
+}
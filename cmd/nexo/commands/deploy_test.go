@@ -1,9 +1,15 @@
 package commands
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
 func TestLoadEnvFile(t *testing.T) {
@@ -246,3 +252,169 @@ func TestDeployFlags_DefaultValues(t *testing.T) {
 		t.Error("deployNoEnvFile should default to false")
 	}
 }
+
+func TestLoadDeployConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	config := `
+deploy:
+  provider: vps
+  url: https://example.com
+  health_timeout: 30s
+  vps:
+    host: deploy@203.0.113.10
+`
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadDeployConfig(v, "myapp")
+	if err != nil {
+		t.Fatalf("loadDeployConfig() error = %v", err)
+	}
+
+	if cfg.Provider != deployProviderVPS {
+		t.Errorf("Provider = %q, want %q", cfg.Provider, deployProviderVPS)
+	}
+	if cfg.HealthTimeout != 30*time.Second {
+		t.Errorf("HealthTimeout = %v, want 30s", cfg.HealthTimeout)
+	}
+	if cfg.HealthCheck != "/" {
+		t.Errorf("HealthCheck = %q, want /", cfg.HealthCheck)
+	}
+	if cfg.Arch != "amd64" {
+		t.Errorf("Arch = %q, want amd64", cfg.Arch)
+	}
+	if cfg.VPS.Service != "myapp" || cfg.VPS.Path != "/opt/myapp" || cfg.VPS.Port != "3000" {
+		t.Errorf("VPS defaults = %+v", cfg.VPS)
+	}
+	if cfg.Fly.App != "myapp" {
+		t.Errorf("Fly.App = %q, want myapp", cfg.Fly.App)
+	}
+
+	empty, err := loadDeployConfig(viper.New(), "myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.Provider != deployProviderCloud {
+		t.Errorf("default Provider = %q, want %q", empty.Provider, deployProviderCloud)
+	}
+}
+
+func TestNewDeployProvider(t *testing.T) {
+	cfg := deployConfig{URL: "https://app.example.com"}
+	cfg.Fly.App = "myapp"
+	cfg.VPS = vpsDeployConfig{Host: "deploy@203.0.113.10", Port: "8080"}
+
+	tests := []struct {
+		provider string
+		url      string
+	}{
+		{deployProviderFly, "https://app.example.com"},
+		{deployProviderRailway, "https://app.example.com"},
+		{deployProviderVPS, "https://app.example.com"},
+	}
+	for _, tt := range tests {
+		cfg.Provider = tt.provider
+		p, err := newDeployProvider(cfg)
+		if err != nil {
+			t.Fatalf("newDeployProvider(%q) error = %v", tt.provider, err)
+		}
+		if p.URL() != tt.url {
+			t.Errorf("%s URL() = %q, want %q", tt.provider, p.URL(), tt.url)
+		}
+	}
+
+	// Without deploy.url, Fly and VPS derive the URL
+	cfg.URL = ""
+	if got := (&flyProvider{cfg: cfg}).URL(); got != "https://myapp.fly.dev" {
+		t.Errorf("fly URL() = %q", got)
+	}
+	if got := (&vpsProvider{cfg: cfg}).URL(); got != "http://203.0.113.10:8080" {
+		t.Errorf("vps URL() = %q", got)
+	}
+	if got := (&railwayProvider{cfg: cfg}).URL(); got != "" {
+		t.Errorf("railway URL() = %q, want empty", got)
+	}
+
+	cfg.Provider = "heroku"
+	if _, err := newDeployProvider(cfg); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestDeployProviderArgs(t *testing.T) {
+	cfg := deployConfig{}
+	cfg.Fly.App = "myapp"
+	cfg.Railway = railwayDeployConfig{Service: "web", Environment: "production"}
+
+	fly := strings.Join((&flyProvider{cfg: cfg}).deployArgs("/tmp/ctx"), " ")
+	if fly != "deploy /tmp/ctx --app myapp --remote-only --yes" {
+		t.Errorf("fly args = %q", fly)
+	}
+
+	railway := strings.Join((&railwayProvider{cfg: cfg}).upArgs("/tmp/ctx"), " ")
+	if railway != "up /tmp/ctx --ci --service web --environment production" {
+		t.Errorf("railway args = %q", railway)
+	}
+
+	cfg.VPS = vpsDeployConfig{Host: "deploy@host", Path: "/opt/myapp", Service: "myapp", SSHKey: "~/.ssh/deploy"}
+	vps := &vpsProvider{cfg: cfg}
+	rsync := strings.Join(vps.rsyncArgs("/tmp/ctx"), " ")
+	if rsync != "-az --delete --exclude .env -e ssh -i ~/.ssh/deploy /tmp/ctx/ deploy@host:/opt/myapp/" {
+		t.Errorf("rsync args = %q", rsync)
+	}
+	if err := vps.SetEnv(map[string]string{"A": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(strings.Join(vps.rsyncArgs("/tmp/ctx"), " "), "--exclude") {
+		t.Error("rsync should upload .env when env vars are set")
+	}
+
+	script := vps.restartScript()
+	for _, want := range []string{"install -m 644 /opt/myapp/myapp.service /etc/systemd/system/myapp.service", "systemctl restart myapp"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("restart script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestVPSUnit(t *testing.T) {
+	p := &vpsProvider{cfg: deployConfig{VPS: vpsDeployConfig{Path: "/opt/myapp", Service: "myapp", User: "www", Port: "8080"}}}
+	unit, err := p.unit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"User=www",
+		"WorkingDirectory=/opt/myapp",
+		"ExecStart=/opt/myapp/app",
+		"Environment=PORT=8080",
+		"EnvironmentFile=-/opt/myapp/.env",
+	} {
+		if !strings.Contains(string(unit), want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	if err := waitForHealthy(healthy.URL, time.Second); err != nil {
+		t.Errorf("waitForHealthy() error = %v", err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	if err := waitForHealthy(failing.URL, 0); err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("waitForHealthy() error = %v, want status 503", err)
+	}
+}
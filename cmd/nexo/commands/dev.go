@@ -3,7 +3,9 @@ package commands
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -11,14 +13,17 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 	"github.com/abdul-hamid-achik/nexo/pkg/scanner"
 	"github.com/abdul-hamid-achik/nexo/pkg/tools"
 	"github.com/fatih/color"
 	"github.com/fsnotify/fsnotify"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -28,10 +33,18 @@ var devCmd = &cobra.Command{
 	Long: `Start the development server with automatic hot reloading.
 
 The server will automatically rebuild and restart when Go or templ files change.
+The app is compiled to tmp/app and only restarted once the new code builds.
+Open pages reload in the browser once the new server is up.
+
+The browser talks to a proxy on --port that forwards to the app and holds
+requests while it restarts, so the port never changes. If the port is busy,
+the next free one is used.
 
 Example:
   nexo dev
-  nexo dev --port 8080`,
+  nexo dev --port 8080
+  nexo dev --no-reload
+  nexo dev --no-proxy`,
 	Run: runDev,
 }
 
@@ -39,12 +52,22 @@ var (
 	devPort    string
 	devHost    string
 	devVerbose bool
+
+	devNoReload   bool
+	devReloadPort string
+	devLiveReload *liveReloadServer
+	devTemplWatch bool
+
+	devNoProxy bool
 )
 
 func init() {
 	devCmd.Flags().StringVarP(&devPort, "port", "p", "3000", "Port to run the server on")
 	devCmd.Flags().StringVarP(&devHost, "host", "H", "0.0.0.0", "Host to bind to")
 	devCmd.Flags().BoolVarP(&devVerbose, "verbose", "v", false, "Show detailed file watching and rebuild info")
+	devCmd.Flags().BoolVar(&devNoReload, "no-reload", false, "Disable browser live reload")
+	devCmd.Flags().StringVar(&devReloadPort, "reload-port", defaultLiveReloadPort, "Port for the live reload server")
+	devCmd.Flags().BoolVar(&devNoProxy, "no-proxy", false, "Run the app directly on --port instead of behind the dev proxy")
 }
 
 // ensureNexoModule checks if the nexo module can be resolved and adds a replace
@@ -251,6 +274,23 @@ func generateRoutes(appDir string, verbose bool) error {
 	return err
 }
 
+// printRouteDiagnostics warns about the handlers and middleware in appDir
+// that aren't registered, e.g. because of a misspelled name, so they don't
+// go unnoticed as 404s. Each line starts with prefix.
+func printRouteDiagnostics(appDir, prefix string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	s := nexo.NewScanner(appDir)
+	s.SetCacheDir(nexo.DefaultScanCacheDir)
+	_, diags, err := s.ScanRouteInfo()
+	if err != nil {
+		return
+	}
+	for _, d := range diags {
+		fmt.Printf("%s%s %s\n", prefix, yellow("Warning:"), d)
+	}
+}
+
 func runDev(cmd *cobra.Command, args []string) {
 	cyan := color.New(color.FgCyan).SprintFunc()
 	green := color.New(color.FgGreen).SprintFunc()
@@ -282,6 +322,15 @@ func runDev(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 	fmt.Printf("  %s Routes generated\n", green("✓"))
+	printRouteDiagnostics("app", "  ")
+
+	// Load watch settings from the dev section of nexo.yaml
+	cfg, err := nexo.LoadConfig("")
+	if err != nil {
+		fmt.Printf("  %s %v, using defaults\n", yellow("Warning:"), err)
+		cfg = nexo.DefaultConfig()
+	}
+	rules := newDevWatchRules(cfg.Dev)
 
 	// Check for templ files and run templ generate if needed
 	hasTemplFiles := false
@@ -296,6 +345,7 @@ func runDev(cmd *cobra.Command, args []string) {
 		return nil
 	})
 
+	var templProcess, tailwindProcess *managedProcess
 	if hasTemplFiles {
 		fmt.Printf("  %s Running templ generate...\n", yellow("→"))
 		templCmd := exec.Command("templ", "generate")
@@ -304,11 +354,20 @@ func runDev(cmd *cobra.Command, args []string) {
 		if err := templCmd.Run(); err != nil {
 			fmt.Printf("  %s templ generate failed (is templ installed?): %v\n", yellow("Warning:"), err)
 			fmt.Printf("  Install with: go install github.com/a-h/templ/cmd/templ@latest\n\n")
+		} else {
+			// Keep templates up to date; text-only edits are served without a rebuild
+			proc, err := startManagedProcess("templ", color.FgMagenta, exec.Command("templ", "generate", "--watch"))
+			if err != nil {
+				fmt.Printf("  %s Failed to start templ watcher: %v\n", yellow("Warning:"), err)
+			} else {
+				templProcess = proc
+				devTemplWatch = true
+				fmt.Printf("  %s templ watcher started\n", green("✓"))
+			}
 		}
 	}
 
 	// Check for Tailwind and start watch mode
-	var tailwindProcess *exec.Cmd
 	if tools.HasStyles() {
 		fmt.Printf("  %s Starting Tailwind CSS watcher...\n", yellow("→"))
 		tw := tools.NewTailwindCLI()
@@ -324,18 +383,108 @@ func runDev(cmd *cobra.Command, args []string) {
 		}
 
 		// Start watch mode
-		proc, err := tw.Watch(tools.DefaultInputPath(), tools.DefaultOutputPath())
+		twCmd, err := tw.WatchCommand(tools.DefaultInputPath(), tools.DefaultOutputPath())
+		if err == nil {
+			tailwindProcess, err = startManagedProcess("tailwind", color.FgBlue, twCmd)
+		}
 		if err != nil {
 			fmt.Printf("  %s Failed to start Tailwind watcher: %v\n", yellow("Warning:"), err)
 		} else {
-			tailwindProcess = proc
 			fmt.Printf("  %s Tailwind watcher started\n", green("✓"))
 		}
 	}
 
-	// Start the server
+	// Start the live reload server
+	if !devNoReload {
+		lr := newLiveReloadServer()
+		if err := lr.Listen(devReloadPort); err != nil {
+			fmt.Printf("  %s Live reload disabled: %v\n", yellow("Warning:"), err)
+		} else {
+			devLiveReload = lr
+			if devVerbose {
+				fmt.Printf("  %s Live reload on port %s\n", cyan("→"), devReloadPort)
+			}
+		}
+	}
+
+	// Serve the browser through the dev proxy so the port survives restarts;
+	// the app itself runs on an internal port
+	var proxy *devProxy
+	appPort := devPort
+	if !devNoProxy {
+		if !isPortAvailable(devPort) {
+			if port := findAvailablePort(devPort); port != devPort {
+				fmt.Printf("  %s Port %s is in use, using %s instead\n", yellow("⚠"), devPort, port)
+				devPort = port
+			}
+		}
+		proxy = newDevProxy()
+		if err := proxy.Listen(devPort); err != nil {
+			fmt.Printf("  %s Dev proxy disabled: %v\n", yellow("Warning:"), err)
+			proxy = nil
+		} else if port, err := freePort(); err == nil {
+			appPort = port
+			if devVerbose {
+				fmt.Printf("  %s App on internal port %s\n", cyan("→"), appPort)
+			}
+		}
+	}
+
+	// serverReady points the proxy at the new server and reloads open pages
+	// once it accepts connections
+	var readyPort atomic.Value
+	serverReady := func(port string) {
+		go func() {
+			if !waitForPort(port, 60*time.Second) {
+				return
+			}
+			readyPort.Store(port)
+			if proxy != nil {
+				proxy.Ready(port)
+			}
+			if devLiveReload != nil {
+				devLiveReload.Reload()
+			}
+		}()
+	}
+
+	// showBuildError stops the server and serves the error overlay in its place
 	var serverProcess *exec.Cmd
-	serverProcess = startDevServer(devPort)
+	showBuildError := func(timestamp, title, output string) {
+		fmt.Printf("  [%s] %s %s\n", timestamp, red("✗"), title)
+		fmt.Print(output)
+		if proxy != nil {
+			proxy.Restarting()
+		}
+		stopDevServer(serverProcess, timestamp)
+		serverProcess = nil
+		devOverlay.Show(title, output)
+		if proxy == nil {
+			if err := devOverlay.Listen(devPort); err != nil {
+				fmt.Printf("  [%s] %s Failed to serve error overlay: %v\n", timestamp, yellow("⚠"), err)
+				return
+			}
+		}
+		if devLiveReload != nil {
+			devLiveReload.Reload()
+		}
+	}
+
+	// Start the server, or the error overlay if the app doesn't compile
+	fmt.Printf("  %s Building...\n", yellow("→"))
+	buildStart := time.Now()
+	if output, err := buildDevServer(); err != nil {
+		showBuildError(time.Now().Format("15:04:05"), "Build failed", output)
+	} else if err := installDevServer(); err != nil {
+		fmt.Printf("  %s %v\n", red("Error:"), err)
+		os.Exit(1)
+	} else {
+		fmt.Printf("  %s Built in %s\n", green("✓"), formatDuration(time.Since(buildStart)))
+		var serverPort string
+		if serverProcess, serverPort = startDevServer(appPort, proxy != nil); serverProcess != nil {
+			serverReady(serverPort)
+		}
+	}
 
 	// Set up file watcher
 	watcher, err := fsnotify.NewWatcher()
@@ -345,36 +494,10 @@ func runDev(cmd *cobra.Command, args []string) {
 	}
 	defer func() { _ = watcher.Close() }()
 
-	// Watch directories recursively
-	watchDirs := []string{"."}
-	if _, err := os.Stat("app"); err == nil {
-		watchDirs = append(watchDirs, "app")
-	}
-
-	for _, dir := range watchDirs {
-		_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			// Skip hidden directories and common non-source directories
-			if info.IsDir() {
-				name := info.Name()
-				if strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "tmp" {
-					return filepath.SkipDir
-				}
-				_ = watcher.Add(path)
-			}
-			return nil
-		})
-	}
-
-	// Also watch styles directory for CSS changes
-	if tools.HasStyles() {
-		stylesDir := "styles"
-		if err := watcher.Add(stylesDir); err == nil {
-			if devVerbose {
-				fmt.Printf("  %s Watching: %s\n", cyan("→"), stylesDir)
-			}
+	// Watch directories recursively, skipping excluded ones
+	for _, dir := range rules.addDirs(watcher.Add, ".") {
+		if devVerbose {
+			fmt.Printf("  %s Watching: %s\n", cyan("→"), dir)
 		}
 	}
 
@@ -384,12 +507,22 @@ func runDev(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("  %s Watching for changes...\n", green("✓"))
 	fmt.Printf("\n  ➜ Local:   %s\n", cyan(fmt.Sprintf("http://localhost:%s", devPort)))
-	fmt.Printf("  ➜ Network: %s\n\n", cyan(fmt.Sprintf("http://%s:%s", devHost, devPort)))
+	fmt.Printf("  ➜ Network: %s\n", cyan(fmt.Sprintf("http://%s:%s", devHost, devPort)))
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		fmt.Printf("  ➜ Press %s + Enter for a latency summary\n", cyan("l"))
+		go watchDevKeys(os.Stdin, func() string {
+			port, _ := readyPort.Load().(string)
+			return port
+		})
+	}
+	fmt.Println()
 
 	// Debounce channel - increased from 100ms to 300ms for more reliable rebuilds
 	var debounceTimer *time.Timer
 	debounceDuration := 300 * time.Millisecond
 
+	generatedTempl := newTemplCodeTracker()
+
 	// Signal handling
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
@@ -406,30 +539,28 @@ func runDev(cmd *cobra.Command, args []string) {
 				continue
 			}
 
-			// Handle new directory creation - add to watcher dynamically
+			// Handle new directory creation - add it and its subdirectories to the watcher
 			if event.Op&fsnotify.Create != 0 {
 				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-					dirName := info.Name()
-					// Skip hidden directories and common non-source directories
-					if !strings.HasPrefix(dirName, ".") && dirName != "node_modules" && dirName != "vendor" && dirName != "tmp" {
-						if err := watcher.Add(event.Name); err == nil {
-							if devVerbose {
-								fmt.Printf("  [%s] %s Added new directory to watcher: %s\n", time.Now().Format("15:04:05"), cyan("ℹ"), event.Name)
-							}
+					for _, dir := range rules.addDirs(watcher.Add, event.Name) {
+						if devVerbose {
+							fmt.Printf("  [%s] %s Added new directory to watcher: %s\n", time.Now().Format("15:04:05"), cyan("ℹ"), dir)
 						}
 					}
 					continue
 				}
 			}
 
-			// Check file extension
 			ext := filepath.Ext(event.Name)
-			if ext != ".go" && ext != ".templ" && ext != ".css" {
-				continue
-			}
-
-			// Skip generated templ files
 			if strings.HasSuffix(event.Name, "_templ.go") {
+				// Generated templ files only matter when the templ watcher wrote them
+				if templProcess == nil {
+					continue
+				}
+			} else if !rules.watchesFile(event.Name) {
+				continue
+			} else if tailwindProcess == nil && filepath.Clean(event.Name) == filepath.FromSlash(tools.DefaultOutputPath()) {
+				// Rebuilds write the CSS output themselves
 				continue
 			}
 
@@ -447,15 +578,23 @@ func runDev(cmd *cobra.Command, args []string) {
 			fileName := event.Name
 
 			debounceTimer = time.AfterFunc(debounceDuration, func() {
-				timestamp := time.Now().Format("15:04:05")
-
-				// Regenerate routes if a route/middleware/proxy/page/layout/loader file changed
-				needsRouteRegen := strings.Contains(fileName, "route.go") ||
+				cycleStart := time.Now()
+				timestamp := cycleStart.Format("15:04:05")
+
+				// Regenerate routes if a route/ws/sse/action/middleware/proxy/page/layout/loader/loading/error/not-found file changed
+				needsRouteRegen := nexo.IsRouteFile(filepath.Base(fileName)) ||
+					filepath.Base(fileName) == "ws.go" ||
+					filepath.Base(fileName) == "sse.go" ||
+					filepath.Base(fileName) == "action.go" ||
 					strings.Contains(fileName, "middleware.go") ||
 					strings.Contains(fileName, "proxy.go") ||
 					strings.Contains(fileName, "loader.go") ||
 					strings.HasSuffix(fileName, "page.templ") ||
-					strings.HasSuffix(fileName, "layout.templ")
+					strings.HasSuffix(fileName, "layout.templ") ||
+					strings.HasSuffix(fileName, "loading.templ") ||
+					strings.HasSuffix(fileName, "error.templ") ||
+					strings.HasSuffix(fileName, "not-found.templ") ||
+					strings.HasSuffix(fileName, "error.go")
 
 				if needsRouteRegen {
 					if devVerbose {
@@ -465,6 +604,22 @@ func runDev(cmd *cobra.Command, args []string) {
 						fmt.Printf("  [%s] %s route generation failed: %v\n", timestamp, red("✗"), err)
 						return
 					}
+					printRouteDiagnostics("app", fmt.Sprintf("  [%s] ", timestamp))
+				}
+
+				// Watchers regenerate templates and CSS themselves; the browser only needs a reload.
+				// Template changes that alter Go code arrive as a _templ.go change and rebuild.
+				reloadOnly := (fileExt == ".templ" && templProcess != nil) ||
+					(fileExt == ".css" && tailwindProcess != nil) ||
+					(strings.HasSuffix(fileName, "_templ.go") && !generatedTempl.changed(fileName))
+				if reloadOnly {
+					if devLiveReload != nil {
+						devLiveReload.Reload()
+					}
+					if devVerbose {
+						fmt.Printf("  [%s] %s Reloaded browser for %s\n", timestamp, cyan("ℹ"), fileName)
+					}
+					return
 				}
 
 				// Run templ generate if it's a templ file
@@ -473,8 +628,8 @@ func runDev(cmd *cobra.Command, args []string) {
 						fmt.Printf("  [%s] %s Regenerating templates...\n", timestamp, yellow("→"))
 					}
 					templCmd := exec.Command("templ", "generate")
-					if err := templCmd.Run(); err != nil {
-						fmt.Printf("  [%s] %s templ generate failed: %v\n", timestamp, red("✗"), err)
+					if output, err := templCmd.CombinedOutput(); err != nil {
+						showBuildError(timestamp, "templ generate failed", string(output))
 						return
 					}
 				}
@@ -493,38 +648,37 @@ func runDev(cmd *cobra.Command, args []string) {
 
 				fmt.Printf("  [%s] %s Rebuilding...\n", timestamp, yellow("→"))
 
-				// Stop old server with graceful shutdown
-				if serverProcess != nil && serverProcess.Process != nil {
-					_ = serverProcess.Process.Signal(syscall.SIGTERM)
-
-					// Wait for process to exit with timeout
-					done := make(chan error, 1)
-					go func() {
-						done <- serverProcess.Wait()
-					}()
-
-					select {
-					case <-done:
-						// Process exited gracefully
-						if devVerbose {
-							fmt.Printf("  [%s] %s Server stopped gracefully\n", timestamp, cyan("ℹ"))
-						}
-					case <-time.After(5 * time.Second):
-						// Force kill if not responding
-						if devVerbose {
-							fmt.Printf("  [%s] %s Server didn't stop gracefully, force killing\n", timestamp, yellow("⚠"))
-						}
-						_ = serverProcess.Process.Kill()
-					}
+				// Compile while the old server keeps running; only restart on success
+				buildStart := time.Now()
+				if output, err := buildDevServer(); err != nil {
+					showBuildError(timestamp, "Build failed", output)
+					return
+				}
+				if devVerbose {
+					fmt.Printf("  [%s] %s Compiled in %s\n", timestamp, cyan("ℹ"), formatDuration(time.Since(buildStart)))
+				}
 
-					// Small delay to ensure port is released
-					time.Sleep(100 * time.Millisecond)
+				// Stop old server (or the error overlay) to release the port;
+				// the proxy holds requests until the new one is up
+				if proxy != nil {
+					proxy.Restarting()
+				}
+				stopDevServer(serverProcess, timestamp)
+				devOverlay.Hide()
+				if err := installDevServer(); err != nil {
+					fmt.Printf("  [%s] %s %v\n", timestamp, red("✗"), err)
+					return
 				}
 
 				// Start new server
-				serverProcess = startDevServer(devPort)
+				var serverPort string
+				serverProcess, serverPort = startDevServer(appPort, proxy != nil)
 
-				fmt.Printf("  [%s] %s Ready\n", timestamp, green("✓"))
+				fmt.Printf("  [%s] %s Rebuilt in %s\n", timestamp, green("✓"), formatDuration(time.Since(cycleStart)))
+
+				if serverProcess != nil {
+					serverReady(serverPort)
+				}
 			})
 
 		case err, ok := <-watcher.Errors:
@@ -535,9 +689,8 @@ func runDev(cmd *cobra.Command, args []string) {
 
 		case <-signals:
 			fmt.Println("\n  Shutting down...")
-			if tailwindProcess != nil && tailwindProcess.Process != nil {
-				_ = tailwindProcess.Process.Kill()
-			}
+			templProcess.Stop()
+			tailwindProcess.Stop()
 			if serverProcess != nil && serverProcess.Process != nil {
 				_ = serverProcess.Process.Signal(syscall.SIGTERM)
 				// Wait with timeout for graceful shutdown
@@ -556,7 +709,113 @@ func runDev(cmd *cobra.Command, args []string) {
 	}
 }
 
-func startDevServer(port string) *exec.Cmd {
+// watchDevKeys reads commands typed into nexo dev: "l" prints the app's
+// latency summary, fetched from the port the app serves on.
+func watchDevKeys(in io.Reader, port func() string) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "l" {
+			continue
+		}
+		if err := printLatencySummary(port()); err != nil {
+			fmt.Printf("  %s Latency summary unavailable: %v\n", color.YellowString("⚠"), err)
+		}
+	}
+}
+
+// printLatencySummary prints the latency summary of the app on port.
+func printLatencySummary(port string) error {
+	if port == "" {
+		return fmt.Errorf("the app is not running")
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://127.0.0.1:" + port + nexo.DevDashboardPath + "/latency")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the app responded %s; is the dev dashboard disabled?", resp.Status)
+	}
+	_, err = io.Copy(os.Stdout, resp.Body)
+	return err
+}
+
+// devBinaryPath returns where nexo dev places the compiled app.
+func devBinaryPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join("tmp", "app.exe")
+	}
+	return filepath.Join("tmp", "app")
+}
+
+// buildDevServer compiles the app next to devBinaryPath, leaving the running
+// binary untouched, and returns the compiler output on failure. Unchanged
+// packages come from the Go build cache.
+func buildDevServer() (string, error) {
+	if err := os.MkdirAll(filepath.Dir(devBinaryPath()), 0755); err != nil {
+		return err.Error(), err
+	}
+	output, err := exec.Command("go", "build", "-o", devBinaryPath()+".next", ".").CombinedOutput()
+	return string(output), err
+}
+
+// installDevServer replaces the app binary with the one from the last
+// successful build. The old server must be stopped first on Windows.
+func installDevServer() error {
+	if err := os.Rename(devBinaryPath()+".next", devBinaryPath()); err != nil {
+		return fmt.Errorf("failed to install built binary: %w", err)
+	}
+	return nil
+}
+
+// formatDuration formats a build duration for display, e.g. "850ms" or "1.2s".
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// stopDevServer stops the server process with a graceful shutdown, force
+// killing it if it doesn't exit within 5 seconds.
+func stopDevServer(cmd *exec.Cmd, timestamp string) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	_ = cmd.Process.Signal(syscall.SIGTERM)
+
+	// Wait for process to exit with timeout
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case <-done:
+		// Process exited gracefully
+		if devVerbose {
+			fmt.Printf("  [%s] %s Server stopped gracefully\n", timestamp, cyan("ℹ"))
+		}
+	case <-time.After(5 * time.Second):
+		// Force kill if not responding
+		if devVerbose {
+			fmt.Printf("  [%s] %s Server didn't stop gracefully, force killing\n", timestamp, yellow("⚠"))
+		}
+		_ = cmd.Process.Kill()
+	}
+
+	// Small delay to ensure port is released
+	time.Sleep(100 * time.Millisecond)
+}
+
+// startDevServer runs the compiled app and returns the process and the port
+// it was started on. behindProxy marks the app as served through the dev
+// proxy, whose port is the one users open.
+func startDevServer(port string, behindProxy bool) (*exec.Cmd, string) {
 	// Check if port is available, find alternative if not
 	actualPort := port
 	if !isPortAvailable(port) {
@@ -564,22 +823,32 @@ func startDevServer(port string) *exec.Cmd {
 			fmt.Printf("  %s Port %s is busy, finding alternative...\n", color.YellowString("⚠"), port)
 		}
 		actualPort = findAvailablePort(port)
-		if actualPort != port {
+		if actualPort != port && !behindProxy {
 			fmt.Printf("  %s Using port %s (requested %s was busy)\n", color.YellowString("⚠"), actualPort, port)
 		}
 	}
 
-	cmd := exec.Command("go", "run", ".")
+	cmd := exec.Command(devBinaryPath())
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Env = append(os.Environ(), fmt.Sprintf("PORT=%s", actualPort))
+	if devLiveReload != nil {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", nexo.LiveReloadPortEnv, devReloadPort))
+	}
+	if behindProxy {
+		cmd.Env = append(cmd.Env, nexo.DevProxyEnv+"=1")
+	}
+	if devTemplWatch {
+		// Serve text from the templ watcher without recompiling
+		cmd.Env = append(cmd.Env, "TEMPL_DEV_MODE=true")
+	}
 
 	if err := cmd.Start(); err != nil {
 		fmt.Printf("  %s Failed to start server: %v\n", color.RedString("Error:"), err)
-		return nil
+		return nil, actualPort
 	}
 
-	return cmd
+	return cmd, actualPort
 }
 
 // isPortAvailable checks if a port is available for binding
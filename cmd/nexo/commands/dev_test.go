@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		850*time.Millisecond + 400*time.Microsecond: "850ms",
+		1234 * time.Millisecond:                     "1.2s",
+		12*time.Second + 60*time.Millisecond:        "12.1s",
+	}
+	for in, want := range tests {
+		if got := formatDuration(in); got != want {
+			t.Errorf("formatDuration(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPrintLatencySummary_Errors(t *testing.T) {
+	if err := printLatencySummary(""); err == nil {
+		t.Error("Expected an error before the app is running")
+	}
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	if err := printLatencySummary(port); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+}
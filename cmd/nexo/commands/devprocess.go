@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/abdul-hamid-achik/nexo/pkg/tools"
+	"github.com/fatih/color"
+)
+
+// devOutputMu serializes output from the processes managed by nexo dev so
+// their lines don't interleave.
+var devOutputMu sync.Mutex
+
+// prefixWriter writes each line it receives to out, prefixed with a label.
+// Partial lines are held until their newline arrives.
+type prefixWriter struct {
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func newPrefixWriter(out io.Writer, name string, attr color.Attribute) *prefixWriter {
+	return &prefixWriter{
+		out:    out,
+		prefix: "  " + color.New(attr).Sprintf("[%s]", name) + " ",
+	}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	devOutputMu.Lock()
+	defer devOutputMu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+		line := w.buf[:i+1]
+		if _, err := io.WriteString(w.out, w.prefix+string(line)); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// managedProcess is a long-running helper started by nexo dev, such as the
+// templ or Tailwind watcher. Its output is merged into the dev server output
+// with a prefix, and it is stopped when nexo dev exits.
+type managedProcess struct {
+	name string
+	cmd  *exec.Cmd
+}
+
+// startManagedProcess starts cmd with its output prefixed by name.
+func startManagedProcess(name string, attr color.Attribute, cmd *exec.Cmd) (*managedProcess, error) {
+	cmd.Stdout = newPrefixWriter(os.Stdout, name, attr)
+	cmd.Stderr = newPrefixWriter(os.Stderr, name, attr)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &managedProcess{name: name, cmd: cmd}, nil
+}
+
+// Stop terminates the process, force killing it after 2 seconds.
+func (p *managedProcess) Stop() {
+	if p == nil || p.cmd.Process == nil {
+		return
+	}
+	_ = p.cmd.Process.Signal(syscall.SIGTERM)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.cmd.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		_ = p.cmd.Process.Kill()
+	}
+}
+
+// devWatchRules decides which files and directories nexo dev watches, based
+// on the dev section of nexo.yaml.
+type devWatchRules struct {
+	extensions  map[string]bool
+	excludeDirs []string // Directory names, paths or glob patterns such as "_*"
+}
+
+func newDevWatchRules(cfg nexo.DevConfig) devWatchRules {
+	rules := devWatchRules{
+		extensions:  make(map[string]bool),
+		excludeDirs: []string{"node_modules", "vendor", "tmp"},
+	}
+	for _, ext := range cfg.WatchExtensions {
+		if ext = strings.TrimSpace(ext); ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		rules.extensions[ext] = true
+	}
+	for _, dir := range cfg.ExcludeDirs {
+		if dir = strings.Trim(strings.TrimSpace(dir), "/"); dir != "" {
+			rules.excludeDirs = append(rules.excludeDirs, dir)
+		}
+	}
+	return rules
+}
+
+// watchesFile reports whether changes to path should be acted on. The
+// Tailwind input is always watched, so style edits rebuild the CSS whatever
+// the watched extensions.
+func (r devWatchRules) watchesFile(path string) bool {
+	return r.extensions[filepath.Ext(path)] || filepath.Clean(path) == filepath.FromSlash(tools.DefaultInputPath())
+}
+
+// skipsDir reports whether the directory at path is excluded from watching.
+// Hidden directories are always skipped.
+func (r devWatchRules) skipsDir(path string) bool {
+	name := filepath.Base(path)
+	if name != "." && strings.HasPrefix(name, ".") {
+		return true
+	}
+	slashPath := filepath.ToSlash(filepath.Clean(path))
+	for _, pattern := range r.excludeDirs {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, slashPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// addDirs adds root and every directory below it that isn't excluded to the
+// watcher, returning the directories added.
+func (r devWatchRules) addDirs(add func(string) error, root string) []string {
+	var added []string
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if r.skipsDir(path) {
+			return filepath.SkipDir
+		}
+		if add(path) == nil {
+			added = append(added, path)
+		}
+		return nil
+	})
+	return added
+}
+
+// templWriteStringRe matches the text literals in code generated by templ's
+// watch mode, which are served from the watcher's text files in dev mode.
+var templWriteStringRe = regexp.MustCompile(`(templruntime\.WriteString\([^,]+, \d+), ".*"\)`)
+
+// templCodeTracker remembers the code of generated templ files so rewrites
+// that only change text, which templ dev mode serves without a rebuild, can
+// be told apart from changes that need a recompile.
+type templCodeTracker struct {
+	mu     sync.Mutex
+	hashes map[string][sha256.Size]byte
+}
+
+func newTemplCodeTracker() *templCodeTracker {
+	return &templCodeTracker{hashes: make(map[string][sha256.Size]byte)}
+}
+
+// changed reports whether the code in path, ignoring text literals, differs
+// from when it was last seen.
+func (t *templCodeTracker) changed(path string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		delete(t.hashes, path)
+		return true
+	}
+	sum := sha256.Sum256(templWriteStringRe.ReplaceAll(data, []byte("$1)")))
+	if prev, ok := t.hashes[path]; ok && prev == sum {
+		return false
+	}
+	t.hashes[path] = sum
+	return true
+}
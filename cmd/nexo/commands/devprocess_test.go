@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/fatih/color"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	color.NoColor = true
+	defer func() { color.NoColor = false }()
+
+	var out bytes.Buffer
+	w := newPrefixWriter(&out, "templ", color.FgMagenta)
+
+	_, _ = w.Write([]byte("first line\nsecond "))
+	_, _ = w.Write([]byte("line\n"))
+	_, _ = w.Write([]byte("partial"))
+
+	want := "  [templ] first line\n  [templ] second line\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestDevWatchRules(t *testing.T) {
+	rules := newDevWatchRules(nexo.DevConfig{
+		WatchExtensions: []string{".go", "templ", " .css "},
+		ExcludeDirs:     []string{"_*", "web/dist/"},
+	})
+
+	for _, f := range []string{"main.go", "app/page.templ", "styles/input.css"} {
+		if !rules.watchesFile(f) {
+			t.Errorf("expected %s to be watched", f)
+		}
+	}
+	if rules.watchesFile("README.md") {
+		t.Error("expected README.md to be ignored")
+	}
+
+	// Style edits are watched by default, and the Tailwind input always
+	if rules := newDevWatchRules(nexo.DefaultConfig().Dev); !rules.watchesFile("styles/theme.css") {
+		t.Error("expected CSS to be watched by default")
+	}
+	if rules := newDevWatchRules(nexo.DevConfig{WatchExtensions: []string{".go"}}); !rules.watchesFile("styles/input.css") {
+		t.Error("expected the Tailwind input to always be watched")
+	}
+
+	for _, dir := range []string{".git", "node_modules", "tmp", "_build", "web/dist", "app/_private"} {
+		if !rules.skipsDir(dir) {
+			t.Errorf("expected %s to be skipped", dir)
+		}
+	}
+	for _, dir := range []string{".", "app", "web", "app/users"} {
+		if rules.skipsDir(dir) {
+			t.Errorf("expected %s to be watched", dir)
+		}
+	}
+}
+
+func TestDevWatchRules_AddDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"app/users/[id]", "node_modules/pkg", ".git/objects", "_scratch"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules := newDevWatchRules(nexo.DevConfig{ExcludeDirs: []string{"_*"}})
+	var added []string
+	rules.addDirs(func(path string) error {
+		rel, _ := filepath.Rel(root, path)
+		added = append(added, filepath.ToSlash(rel))
+		return nil
+	}, root)
+	sort.Strings(added)
+
+	want := ".,app,app/users,app/users/[id]"
+	if got := strings.Join(added, ","); got != want {
+		t.Errorf("added = %s, want %s", got, want)
+	}
+}
+
+func TestTemplCodeTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page_templ.go")
+	write := func(code string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tracker := newTemplCodeTracker()
+	write(`templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<h1>Contact</h1>")`)
+	if !tracker.changed(path) {
+		t.Error("expected first sighting to count as a change")
+	}
+
+	write(`templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<h1>Contact \"Us\"</h1>")`)
+	if tracker.changed(path) {
+		t.Error("expected a text-only change to be ignored")
+	}
+
+	write(`templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<h1>Contact</h1>")`)
+	if !tracker.changed(path) {
+		t.Error("expected a code change to count as a change")
+	}
+}
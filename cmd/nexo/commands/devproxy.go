@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// devProxyHoldTimeout is how long the dev proxy holds a request while the
+// app is restarting before giving up.
+const devProxyHoldTimeout = 60 * time.Second
+
+type devProxyTargetKey struct{}
+
+// devProxy is the browser-facing server of nexo dev. It forwards requests to
+// the app, which runs on an internal port, and holds them while the app is
+// rebuilding, so the browser never sees a refused connection. While the app
+// doesn't compile it serves the error overlay instead.
+type devProxy struct {
+	mu     sync.Mutex
+	target *url.URL      // nil while the app is restarting
+	ready  chan struct{} // closed once target is set
+	proxy  *httputil.ReverseProxy
+}
+
+func newDevProxy() *devProxy {
+	p := &devProxy{ready: make(chan struct{})}
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(r.In.Context().Value(devProxyTargetKey{}).(*url.URL))
+			r.Out.Host = r.In.Host
+			r.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, "nexo dev: the app is not responding: "+err.Error(), http.StatusBadGateway)
+		},
+	}
+	return p
+}
+
+// Listen starts serving on port in the background.
+func (p *devProxy) Listen(port string) error {
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	go func() { _ = http.Serve(ln, p) }()
+	return nil
+}
+
+// Restarting holds new requests until Ready is called.
+func (p *devProxy) Restarting() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.target != nil {
+		p.target = nil
+		p.ready = make(chan struct{})
+	}
+}
+
+// Ready forwards requests, including held ones, to the app on port.
+func (p *devProxy) Ready(port string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wasRestarting := p.target == nil
+	p.target = &url.URL{Scheme: "http", Host: "127.0.0.1:" + port}
+	if wasRestarting {
+		close(p.ready)
+	}
+}
+
+func (p *devProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target, ok := p.waitForTarget(r.Context())
+	if !ok {
+		if page := devOverlay.Page(); page != nil {
+			devOverlay.serveHTTP(w, r)
+			return
+		}
+		if r.Context().Err() == nil {
+			http.Error(w, "nexo dev: timed out waiting for the app to start", http.StatusServiceUnavailable)
+		}
+		return
+	}
+	p.proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), devProxyTargetKey{}, target)))
+}
+
+// waitForTarget returns the app URL, waiting while the app restarts. It gives
+// up when the request is canceled, the hold timeout expires or the error
+// overlay is shown.
+func (p *devProxy) waitForTarget(ctx context.Context) (*url.URL, bool) {
+	timeout := time.NewTimer(devProxyHoldTimeout)
+	defer timeout.Stop()
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+
+	for {
+		if devOverlay.Page() != nil {
+			return nil, false
+		}
+
+		p.mu.Lock()
+		target, ready := p.target, p.ready
+		p.mu.Unlock()
+		if target != nil {
+			return target, true
+		}
+
+		select {
+		case <-ready:
+		case <-tick.C:
+		case <-timeout.C:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// freePort returns a port that is currently free on the loopback interface.
+func freePort() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = ln.Close() }()
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	return port, err
+}
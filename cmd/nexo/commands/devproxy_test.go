@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDevProxy_HoldsRequestsUntilReady(t *testing.T) {
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello from "+r.Host)
+	}))
+	defer app.Close()
+	_, appPort, _ := net.SplitHostPort(app.Listener.Addr().String())
+
+	proxy := httptest.NewServer(newDevProxy())
+	defer proxy.Close()
+
+	type result struct {
+		body string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(proxy.URL + "/")
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		done <- result{body: string(body)}
+	}()
+
+	select {
+	case res := <-done:
+		t.Fatalf("request finished before the app was ready: %+v", res)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	p := proxy.Config.Handler.(*devProxy)
+	p.Ready(appPort)
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			t.Fatalf("GET failed: %v", res.err)
+		}
+		// The original Host is preserved so absolute URLs point at the proxy
+		want := "hello from " + strings.TrimPrefix(proxy.URL, "http://")
+		if res.body != want {
+			t.Errorf("body = %q, want %q", res.body, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request still held after Ready")
+	}
+}
+
+func TestDevProxy_ServesOverlay(t *testing.T) {
+	p := newDevProxy()
+	devOverlay.Show("Build failed", "main.go:3:1: boom")
+	defer devOverlay.Hide()
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Build failed") {
+		t.Errorf("expected overlay, got:\n%s", rec.Body.String())
+	}
+}
+
+func TestDevProxy_AppDown(t *testing.T) {
+	p := newDevProxy()
+	port, err := freePort()
+	if err != nil {
+		t.Fatalf("freePort failed: %v", err)
+	}
+	p.Ready(port)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+}
@@ -1,6 +1,13 @@
 package commands
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"os"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
 
 var generateCmd = &cobra.Command{
 	Use:     "generate",
@@ -8,6 +15,10 @@ var generateCmd = &cobra.Command{
 	Short:   "Generate Nexo components",
 	Long: `Generate routes, middleware, proxy, pages, and loaders for your Nexo project.
 
+Files created by generators are recorded in .nexo/generated.json, so the
+last generation can be undone with --undo-last. Files modified since they
+were generated are never removed.
+
 Examples:
   nexo generate routes                           Generate route registration code
   nexo generate route users --methods GET,POST
@@ -15,10 +26,69 @@ Examples:
   nexo generate middleware auth --path api/protected
   nexo generate proxy --template auth-check
   nexo generate page dashboard
-  nexo generate loader dashboard --data-type DashboardData`,
+  nexo generate loader dashboard --data-type DashboardData
+  nexo generate migration add_users_table
+  nexo generate seed users
+  nexo generate form contact --fields name,email,message
+  nexo generate e2e
+  nexo generate from-openapi openapi.yaml
+  nexo generate --undo-last                      Remove files from the last generation`,
+	Run: runGenerate,
 }
 
+var generateUndoLast bool
+
 func init() {
+	generateCmd.Flags().BoolVar(&generateUndoLast, "undo-last", false, "Remove the files created by the last generation run")
 	rootCmd.AddCommand(generateCmd)
 	generateCmd.AddCommand(generateRoutesCmd)
 }
+
+func runGenerate(cmd *cobra.Command, args []string) {
+	if !generateUndoLast {
+		_ = cmd.Help()
+		return
+	}
+
+	run, err := generator.UndoLastRun(generator.ManifestPath)
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		os.Exit(1)
+	}
+
+	files := make([]string, 0, len(run.Files))
+	for _, f := range run.Files {
+		files = append(files, f.Path)
+	}
+
+	if jsonOutput {
+		printSuccess(UndoOutput{
+			Command: run.Command,
+			Removed: files,
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Undid '%s'\n\n", green("✓"), run.Command)
+	for _, f := range files {
+		fmt.Printf("    Removed: %s\n", cyan(f))
+	}
+	fmt.Println()
+}
+
+// recordGeneration records generated files in the manifest so they can be
+// removed with 'nexo generate --undo-last'.
+func recordGeneration(command string, files []string) {
+	if err := generator.RecordRun(generator.ManifestPath, command, files); err != nil && !jsonOutput {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("  %s could not record generated files: %v\n", yellow("Warning:"), err)
+	}
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var generateE2ECmd = &cobra.Command{
+	Use:   "e2e",
+	Short: "Generate an end-to-end test harness",
+	Long: `Generate end-to-end tests that run the whole app over HTTP.
+
+Creates e2e_setup_test.go with a helper that starts the app on a random port
+using httptest, and e2e_test.go with an example API assertion (and a page
+assertion when app/page.templ exists). The tests use the e2e build tag so
+they only run when requested.
+
+Examples:
+  nexo generate e2e
+  nexo generate e2e --api-path /api/status
+
+Run the tests with:
+  go test -tags e2e ./...`,
+	Args: cobra.NoArgs,
+	Run:  runGenerateE2E,
+}
+
+var (
+	e2eAPIPath string
+	e2eAppDir  string
+)
+
+func init() {
+	generateE2ECmd.Flags().StringVar(&e2eAPIPath, "api-path", "/api/health", "API endpoint used by the example test")
+	generateE2ECmd.Flags().StringVarP(&e2eAppDir, "app-dir", "d", "app", "App directory")
+	generateCmd.AddCommand(generateE2ECmd)
+}
+
+func runGenerateE2E(cmd *cobra.Command, args []string) {
+	result, err := generator.GenerateE2E(generator.E2EConfig{
+		AppDir:  e2eAppDir,
+		APIPath: e2eAPIPath,
+	})
+
+	// The harness registers routes from nexo_routes.go; make sure it exists
+	if err == nil {
+		if _, statErr := os.Stat("nexo_routes.go"); os.IsNotExist(statErr) {
+			_, err = generator.ScanAndGenerateRoutes(e2eAppDir, "nexo_routes.go")
+		}
+	}
+
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		return
+	}
+
+	recordGeneration("generate e2e", result.Files)
+
+	if jsonOutput {
+		printSuccess(GenerateOutput{
+			Command: "generate e2e",
+			Files:   result.Files,
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Generated e2e tests\n\n", green("✓"))
+	for _, f := range result.Files {
+		fmt.Printf("    Created: %s\n", cyan(f))
+	}
+	fmt.Printf("\n  Next steps:\n")
+	fmt.Printf("    1. Add assertions for your routes in %s\n", cyan(result.Files[1]))
+	fmt.Printf("    2. Run them with: go test -tags e2e ./...\n\n")
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var generateFormCmd = &cobra.Command{
+	Use:   "form <path>",
+	Short: "Generate a form page with validation",
+	Long: `Generate a complete form page following the Post/Redirect/Get pattern.
+
+Creates in app/<path>/:
+- form.go: the form struct, binding and validation
+- page.templ: the page and a Form component that renders the fields
+- route.go: a POST handler that re-renders the form with errors
+- loader.go: shows a flash message after a successful submission
+
+The form submits with HTMX when available (re-rendering only the form) and
+falls back to a regular POST with a redirect.
+
+Field types are inferred from the name (email, message, password, ...) or
+set explicitly with name:type. Supported types: text, email, textarea,
+password, number, tel, url, date.
+
+Examples:
+  nexo generate form contact --fields name,email,message
+  nexo generate form signup --fields email,password,age:number`,
+	Args: cobra.ExactArgs(1),
+	Run:  runGenerateForm,
+}
+
+var (
+	formFields []string
+	formAppDir string
+)
+
+func init() {
+	generateFormCmd.Flags().StringSliceVar(&formFields, "fields", nil, "Form fields, comma-separated (e.g., name,email,message:textarea)")
+	generateFormCmd.Flags().StringVarP(&formAppDir, "app-dir", "d", "app", "App directory")
+	_ = generateFormCmd.MarkFlagRequired("fields")
+	generateCmd.AddCommand(generateFormCmd)
+}
+
+func runGenerateForm(cmd *cobra.Command, args []string) {
+	path := args[0]
+
+	result, err := generator.GenerateForm(generator.FormConfig{
+		Path:   path,
+		Fields: formFields,
+		AppDir: formAppDir,
+	})
+
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		return
+	}
+
+	recordGeneration("generate form", result.Files)
+
+	if jsonOutput {
+		printSuccess(GenerateOutput{
+			Command: "generate form",
+			Path:    path,
+			Files:   result.Files,
+			Pattern: result.Pattern,
+			Methods: []string{"GET", "POST"},
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Generated form\n\n", green("✓"))
+	for _, f := range result.Files {
+		fmt.Printf("    Created: %s\n", cyan(f))
+	}
+	fmt.Printf("    URL: %s\n\n", result.Pattern)
+	fmt.Printf("  Next steps:\n")
+	fmt.Printf("    1. Handle the submission in %s\n", cyan(result.Files[2]))
+	fmt.Printf("    2. Adjust validation rules in %s\n", cyan(result.Files[0]))
+	fmt.Printf("    3. Run 'templ generate' to compile the page\n\n")
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var generateFromOpenAPICmd = &cobra.Command{
+	Use:   "from-openapi <spec>",
+	Short: "Generate routes from an OpenAPI document",
+	Long: `Generate the app/ folder structure from an existing OpenAPI 3 document.
+
+Each path becomes a directory with a route.go containing a handler per
+operation. JSON request and response schemas become typed structs, and
+request structs get a Validate method enforcing required fields, string
+lengths, enums and numeric bounds. Path parameters become dynamic segments:
+/users/{id} is generated in app/users/[id]/route.go.
+
+Existing route files are never overwritten.
+
+Examples:
+  nexo generate from-openapi openapi.yaml
+  nexo generate from-openapi api/spec.json --app-dir app`,
+	Args: cobra.ExactArgs(1),
+	Run:  runGenerateFromOpenAPI,
+}
+
+var fromOpenAPIAppDir string
+
+func init() {
+	generateFromOpenAPICmd.Flags().StringVarP(&fromOpenAPIAppDir, "app-dir", "d", "app", "App directory")
+	generateCmd.AddCommand(generateFromOpenAPICmd)
+}
+
+func runGenerateFromOpenAPI(cmd *cobra.Command, args []string) {
+	specPath := args[0]
+
+	result, err := generator.GenerateFromOpenAPI(generator.FromOpenAPIConfig{
+		SpecPath: specPath,
+		AppDir:   fromOpenAPIAppDir,
+	})
+
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		return
+	}
+
+	recordGeneration("generate from-openapi", result.Files)
+
+	if jsonOutput {
+		printSuccess(GenerateOutput{
+			Command: "generate from-openapi",
+			Path:    specPath,
+			Files:   result.Files,
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Generated %d route file(s) from %s\n\n", green("✓"), len(result.Files), specPath)
+	for _, f := range result.Files {
+		fmt.Printf("    Created: %s\n", cyan(f))
+	}
+	fmt.Printf("\n  Next steps:\n")
+	fmt.Printf("    1. Implement the TODO handlers\n")
+	fmt.Printf("    2. Run 'nexo routes' to review the generated routes\n\n")
+}
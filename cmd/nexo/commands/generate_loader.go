@@ -17,11 +17,13 @@ The loader pattern separates data fetching from page rendering:
 - loader.go: Loads data (Loader function)
 - page.templ: Renders UI (Page function)
 
-The framework automatically wires them together.
+The framework automatically wires them together. Dynamic segments in the
+path become typed fields of the data struct, and a page.templ accepting the
+data type is created if the directory doesn't have one yet.
 
 Examples:
   nexo generate loader dashboard
-  nexo generate loader users/_id
+  nexo generate loader users/[id]
   nexo generate loader admin/settings --data-type AdminSettingsData`,
 	Args: cobra.ExactArgs(1),
 	Run:  runGenerateLoader,
@@ -57,6 +59,8 @@ func runGenerateLoader(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	recordGeneration("generate loader", result.Files)
+
 	if jsonOutput {
 		printSuccess(GenerateOutput{
 			Command: "generate loader",
@@ -78,6 +82,8 @@ func runGenerateLoader(cmd *cobra.Command, args []string) {
 	fmt.Printf("  Next steps:\n")
 	fmt.Printf("    1. Edit %s to add your data fields\n", cyan(result.Files[0]))
 	fmt.Printf("    2. Implement the Loader() function to fetch data\n")
-	fmt.Printf("    3. Update page.templ to use the data type as parameter\n")
+	if len(result.Files) == 1 {
+		fmt.Printf("    3. Update page.templ to accept the data type as parameter\n")
+	}
 	fmt.Printf("\n  See: https://nexo.build/docs/routing/data-loaders\n\n")
 }
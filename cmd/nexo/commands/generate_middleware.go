@@ -61,6 +61,8 @@ func runGenerateMiddleware(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	recordGeneration("generate middleware", result.Files)
+
 	if jsonOutput {
 		printSuccess(GenerateOutput{
 			Command: "generate middleware",
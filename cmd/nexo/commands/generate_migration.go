@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var generateMigrationCmd = &cobra.Command{
+	Use:   "migration <name>",
+	Short: "Generate a SQL migration",
+	Long: `Generate a pair of up/down SQL migration files.
+
+Migrations live in the migrations/ directory and follow the golang-migrate
+naming convention: <timestamp>_<name>.up.sql and <timestamp>_<name>.down.sql.
+Apply them with 'nexo db migrate'.
+
+Examples:
+  nexo generate migration add_users_table
+  nexo generate migration add_posts_index --dir db/migrations`,
+	Args: cobra.ExactArgs(1),
+	Run:  runGenerateMigration,
+}
+
+var migrationDir string
+
+func init() {
+	generateMigrationCmd.Flags().StringVar(&migrationDir, "dir", "migrations", "Migrations directory")
+	generateCmd.AddCommand(generateMigrationCmd)
+}
+
+func runGenerateMigration(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	result, err := generator.GenerateMigration(generator.MigrationConfig{
+		Name: name,
+		Dir:  migrationDir,
+	})
+
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		return
+	}
+
+	recordGeneration("generate migration", result.Files)
+
+	if jsonOutput {
+		printSuccess(GenerateOutput{
+			Command: "generate migration",
+			Path:    migrationDir,
+			Files:   result.Files,
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Generated migration\n\n", green("✓"))
+	for _, f := range result.Files {
+		fmt.Printf("    Created: %s\n", cyan(f))
+	}
+	fmt.Printf("\n  Next steps:\n")
+	fmt.Printf("    1. Write the schema change in %s\n", cyan(result.Files[0]))
+	fmt.Printf("    2. Write the rollback in %s\n", cyan(result.Files[1]))
+	fmt.Printf("    3. Run 'nexo db migrate' to apply it\n\n")
+}
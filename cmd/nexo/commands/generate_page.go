@@ -54,6 +54,8 @@ func runGeneratePage(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	recordGeneration("generate page", result.Files)
+
 	if jsonOutput {
 		printSuccess(GenerateOutput{
 			Command: "generate page",
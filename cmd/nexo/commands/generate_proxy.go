@@ -16,7 +16,7 @@ var generateProxyCmd = &cobra.Command{
 Available templates:
   blank        - Empty proxy (default)
   auth-check   - Authentication checking before routing
-  rate-limit   - Simple IP-based rate limiting
+  rate-limit   - IP-based rate limiting
   maintenance  - Maintenance mode with allowed IPs
   redirect-www - WWW/non-WWW redirect handling
 
@@ -60,6 +60,8 @@ func runGenerateProxy(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	recordGeneration("generate proxy", result.Files)
+
 	if jsonOutput {
 		printSuccess(GenerateOutput{
 			Command: "generate proxy",
@@ -24,19 +24,28 @@ Examples:
   nexo generate route users              # GET /api/users
   nexo generate route users/[id]         # Dynamic route /api/users/:id
   nexo generate route posts/[...slug]    # Catch-all /api/posts/*
-  nexo generate route users/[id] --methods GET,PUT,DELETE`,
+  nexo generate route users/[id] --methods GET,PUT,DELETE
+  nexo generate route users --methods POST --append   # Add Post to existing route.go
+  nexo generate route orders --methods GET,POST --from-json sample.json
+
+With --from-json, the request/response struct is inferred from a sample
+payload. POST, PUT and PATCH handlers bind and validate it, GET returns it.`,
 	Args: cobra.ExactArgs(1),
 	Run:  runGenerateRoute,
 }
 
 var (
-	routeMethods string
-	routeAppDir  string
+	routeMethods  string
+	routeAppDir   string
+	routeAppend   bool
+	routeFromJSON string
 )
 
 func init() {
 	generateRouteCmd.Flags().StringVarP(&routeMethods, "methods", "m", "GET", "HTTP methods (comma-separated: GET,POST,PUT,DELETE)")
 	generateRouteCmd.Flags().StringVarP(&routeAppDir, "app-dir", "d", "app", "App directory")
+	generateRouteCmd.Flags().BoolVar(&routeAppend, "append", false, "Add missing handlers to an existing route.go")
+	generateRouteCmd.Flags().StringVar(&routeFromJSON, "from-json", "", "Infer the request/response struct from a JSON sample file")
 	generateCmd.AddCommand(generateRouteCmd)
 }
 
@@ -50,9 +59,11 @@ func runGenerateRoute(cmd *cobra.Command, args []string) {
 	}
 
 	result, err := generator.GenerateRoute(generator.RouteConfig{
-		Path:    path,
-		Methods: methods,
-		AppDir:  routeAppDir,
+		Path:     path,
+		Methods:  methods,
+		AppDir:   routeAppDir,
+		Append:   routeAppend,
+		FromJSON: routeFromJSON,
 	})
 
 	if err != nil {
@@ -65,6 +76,10 @@ func runGenerateRoute(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if !routeAppend {
+		recordGeneration("generate route", result.Files)
+	}
+
 	if jsonOutput {
 		printSuccess(GenerateOutput{
 			Command: "generate route",
@@ -80,8 +95,12 @@ func runGenerateRoute(cmd *cobra.Command, args []string) {
 	cyan := color.New(color.FgCyan).SprintFunc()
 
 	fmt.Printf("\n  %s Generated route\n\n", green("✓"))
+	action := "Created"
+	if routeAppend {
+		action = "Updated"
+	}
 	for _, f := range result.Files {
-		fmt.Printf("    Created: %s\n", cyan(f))
+		fmt.Printf("    %s: %s\n", action, cyan(f))
 	}
 	fmt.Printf("    Pattern: %s\n", result.Pattern)
 	fmt.Printf("    Methods: %s\n\n", strings.Join(methods, ", "))
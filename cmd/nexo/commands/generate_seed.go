@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/generator"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var generateSeedCmd = &cobra.Command{
+	Use:   "seed <name>",
+	Short: "Generate a database seed",
+	Long: `Generate a seed function in the seeds/ directory.
+
+Seeds register themselves with the seeds package and declare which
+environments they run in. The first seed also creates the seeds registry
+and the cmd/seed runner used by 'nexo db seed'. Seeds should be idempotent
+so they can be run again on an existing database.
+
+Examples:
+  nexo generate seed users
+  nexo generate seed demo_posts --env development,staging`,
+	Args: cobra.ExactArgs(1),
+	Run:  runGenerateSeed,
+}
+
+var seedEnvs []string
+
+func init() {
+	generateSeedCmd.Flags().StringSliceVar(&seedEnvs, "env", []string{"development"}, "Environments the seed runs in (comma-separated)")
+	generateCmd.AddCommand(generateSeedCmd)
+}
+
+func runGenerateSeed(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	result, err := generator.GenerateSeed(generator.SeedConfig{
+		Name: name,
+		Envs: seedEnvs,
+	})
+
+	if err != nil {
+		if jsonOutput {
+			printJSONError(err)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("  %s %v\n", red("Error:"), err)
+		}
+		return
+	}
+
+	recordGeneration("generate seed", result.Files)
+
+	if jsonOutput {
+		printSuccess(GenerateOutput{
+			Command: "generate seed",
+			Path:    name,
+			Files:   result.Files,
+		})
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n  %s Generated seed\n\n", green("✓"))
+	for _, f := range result.Files {
+		fmt.Printf("    Created: %s\n", cyan(f))
+	}
+	fmt.Printf("\n  Next steps:\n")
+	fmt.Printf("    1. Insert your data in %s\n", cyan(result.Files[len(result.Files)-1]))
+	if len(result.Files) > 1 {
+		fmt.Printf("    2. Import your database driver in %s\n", cyan("cmd/seed/main.go"))
+		fmt.Printf("    3. Run 'nexo db seed' to load the data\n\n")
+	} else {
+		fmt.Printf("    2. Run 'nexo db seed' to load the data\n\n")
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultLiveReloadPort is the port the dev live reload server listens on.
+const defaultLiveReloadPort = "35729"
+
+// liveReloadServer streams reload events to browsers over Server-Sent Events.
+// Pages served by the app under nexo dev connect to /events and reload
+// themselves when a reload event arrives.
+type liveReloadServer struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func newLiveReloadServer() *liveReloadServer {
+	return &liveReloadServer{
+		clients: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Listen starts serving on port in the background.
+func (s *liveReloadServer) Listen(port string) error {
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", s.handleEvents)
+	go func() { _ = http.Serve(ln, mux) }()
+	return nil
+}
+
+// Reload tells every connected browser to reload.
+func (s *liveReloadServer) Reload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *liveReloadServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ch:
+			_, _ = fmt.Fprint(w, "event: reload\ndata: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// waitForPort waits until something accepts connections on port, returning
+// false if nothing does within timeout.
+func waitForPort(port string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", "127.0.0.1:"+port, 200*time.Millisecond)
+		if err == nil {
+			_ = conn.Close()
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
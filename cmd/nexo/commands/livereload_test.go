@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLiveReloadServer_Reload(t *testing.T) {
+	lr := newLiveReloadServer()
+	srv := httptest.NewServer(http.HandlerFunc(lr.handleEvents))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET /events failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("expected connected comment, got %q", line)
+	}
+
+	lr.Reload()
+
+	lines := make(chan string)
+	go func() {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed before reload event")
+			}
+			if line == "event: reload\n" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for reload event")
+		}
+	}
+}
+
+func TestWaitForPort(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	port := srv.URL[strings.LastIndex(srv.URL, ":")+1:]
+	if !waitForPort(port, time.Second) {
+		t.Error("expected port to be reachable")
+	}
+
+	srv.Close()
+	if waitForPort(port, 300*time.Millisecond) {
+		t.Error("expected closed port to time out")
+	}
+}
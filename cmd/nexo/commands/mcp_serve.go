@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/abdul-hamid-achik/nexo/pkg/mcp"
 	"github.com/spf13/cobra"
@@ -10,9 +15,16 @@ import (
 
 var mcpServeCmd = &cobra.Command{
 	Use:   "serve",
-	Short: "Start MCP server over stdio",
+	Short: "Start MCP server over stdio or HTTP",
 	Long: `Start an MCP server over stdio for LLM agent integration.
 
+With --http the server listens on an address instead, for web-based agents
+and remote IDEs. It serves the streamable HTTP transport at /mcp and the
+SSE transport at /sse. Use --token (or NEXO_MCP_TOKEN) to require an
+"Authorization: Bearer <token>" header. A token is required unless the
+server listens on a loopback address such as 127.0.0.1:8765, and requests
+whose Host or Origin names another host are rejected.
+
 The server exposes tools for:
   - Creating new Nexo projects
   - Generating routes, middleware, proxy, and pages
@@ -35,6 +47,11 @@ Usage with Claude Code or OpenCode:
   Configure your MCP settings to run:
     nexo mcp serve --workdir /path/to/project
 
+Usage over HTTP:
+
+  nexo mcp serve --http :8765 --token secret
+  # Connect clients to http://localhost:8765/mcp
+
 Available tools:
   - nexo_new: Create a new Nexo project
   - nexo_generate_route: Generate a route file
@@ -43,14 +60,22 @@ Available tools:
   - nexo_generate_page: Generate page template
   - nexo_list_routes: List all routes
   - nexo_info: Get project information
-  - nexo_validate: Validate project structure`,
+  - nexo_validate: Validate project structure
+  - nexo_dev: Start or stop the dev server and read its output
+  - nexo_request: Send an HTTP request to the app`,
 	Run: runMCPServe,
 }
 
-var mcpWorkdir string
+var (
+	mcpWorkdir string
+	mcpHTTP    string
+	mcpToken   string
+)
 
 func init() {
 	mcpServeCmd.Flags().StringVarP(&mcpWorkdir, "workdir", "w", "", "Working directory for operations (default: current directory)")
+	mcpServeCmd.Flags().StringVar(&mcpHTTP, "http", "", "Serve over HTTP on this address (e.g. :8765) instead of stdio")
+	mcpServeCmd.Flags().StringVar(&mcpToken, "token", "", "Bearer token required for HTTP requests (default: $NEXO_MCP_TOKEN)")
 	mcpCmd.AddCommand(mcpServeCmd)
 }
 
@@ -66,8 +91,44 @@ func runMCPServe(cmd *cobra.Command, args []string) {
 	}
 
 	server := mcp.NewServer(workdir)
+	if mcpHTTP != "" {
+		serveMCPHTTP(server)
+		return
+	}
 	if err := server.ServeStdio(); err != nil {
 		fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// serveMCPHTTP serves the MCP server on --http until interrupted. Status is
+// written to stderr, like the stdio server.
+func serveMCPHTTP(server *mcp.Server) {
+	token := mcpToken
+	if token == "" {
+		token = os.Getenv("NEXO_MCP_TOKEN")
+	}
+
+	host, port, err := net.SplitHostPort(mcpHTTP)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --http address %q: %v\n", mcpHTTP, err)
+		os.Exit(1)
+	}
+	if host == "" {
+		host = "localhost"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "MCP server starting on http://%s%s (SSE: %s)\n", net.JoinHostPort(host, port), mcp.HTTPEndpoint, mcp.SSEEndpoint)
+	err = server.ServeHTTP(ctx, mcpHTTP, token)
+	if errors.Is(err, mcp.ErrTokenRequired) {
+		fmt.Fprintf(os.Stderr, "Refusing to serve on %s without --token: anyone who can reach it could run tools\n", mcpHTTP)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
+		os.Exit(1)
+	}
+}
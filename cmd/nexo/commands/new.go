@@ -10,6 +10,7 @@ import (
 	"text/template"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +24,20 @@ Supports Next.js-style routing with actual bracket notation:
   app/api/docs/[...slug]/route.go → Catch-all route
   app/(admin)/dashboard/route.go → Route group
 
+Run in a terminal without flags to choose the module path, template,
+database, auth and frontend options interactively.
+
+Templates:
+  fullstack      templ pages, Tailwind CSS and HTMX (default)
+  api            JSON API without pages
+  api-postgres   JSON API with Postgres, sqlc and migrations
+  htmx-auth      HTMX pages with session login
+  microservice   minimal service with health and readiness checks
+
 Examples:
   nexo new myapp
+  nexo new myapp --template api-postgres --module github.com/me/myapp
+  nexo new myapp --template htmx-auth --db postgres
   nexo new myapp --api-only
   nexo new myapp --skip-prompts`,
 	Args: cobra.ExactArgs(1),
@@ -34,11 +47,82 @@ Examples:
 var (
 	apiOnly     bool
 	skipPrompts bool
+	newTemplate string
+	newModule   string
+	newDatabase string
+	newAuth     bool
+	newTailwind bool
+	newHTMX     bool
 )
 
 func init() {
-	newCmd.Flags().BoolVar(&apiOnly, "api-only", false, "Create API-only project without templ")
-	newCmd.Flags().BoolVar(&skipPrompts, "skip-prompts", false, "Skip prompts and use defaults")
+	addNewFlags(newCmd)
+}
+
+// addNewFlags registers the nexo new flags on cmd.
+func addNewFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&apiOnly, "api-only", false, "Create API-only project without templ (same as --template api)")
+	cmd.Flags().BoolVar(&skipPrompts, "skip-prompts", false, "Skip prompts and use defaults")
+	cmd.Flags().StringVarP(&newTemplate, "template", "t", starterFullstack, "Starter template ("+strings.Join(starterNames(), ", ")+")")
+	cmd.Flags().StringVarP(&newModule, "module", "m", "", "Go module path (default: the project name)")
+	cmd.Flags().StringVar(&newDatabase, "db", "", "Database: none or postgres (default: the template's)")
+	cmd.Flags().BoolVar(&newAuth, "auth", false, "Add authentication (default: the template's)")
+	cmd.Flags().BoolVar(&newTailwind, "tailwind", false, "Add Tailwind CSS (default: the template's)")
+	cmd.Flags().BoolVar(&newHTMX, "htmx", false, "Add HTMX (default: the template's)")
+}
+
+// newProjectFlags are the flags that choose project options. Setting any of
+// them skips the interactive prompts.
+var newProjectFlags = []string{"api-only", "template", "module", "db", "auth", "tailwind", "htmx"}
+
+// resolveProjectOptions builds the project options from the starter
+// template and the flags that were set.
+func resolveProjectOptions(cmd *cobra.Command, name string) (projectOptions, error) {
+	templateName := newTemplate
+	if apiOnly {
+		if cmd.Flags().Changed("template") && newTemplate != starterAPI {
+			return projectOptions{}, fmt.Errorf("--api-only conflicts with --template %s", newTemplate)
+		}
+		templateName = starterAPI
+	}
+
+	s, ok := findStarter(templateName)
+	if !ok {
+		return projectOptions{}, fmt.Errorf("unknown template %q (use %s)", templateName, strings.Join(starterNames(), ", "))
+	}
+
+	opts := projectOptions{Name: name, ModuleName: name}
+	opts.applyStarter(s)
+
+	if newModule != "" {
+		opts.ModuleName = newModule
+	}
+	if cmd.Flags().Changed("db") {
+		opts.Database = newDatabase
+	}
+	if cmd.Flags().Changed("auth") {
+		opts.Auth = newAuth
+	}
+	if cmd.Flags().Changed("tailwind") {
+		opts.Tailwind = newTailwind
+	}
+	if cmd.Flags().Changed("htmx") {
+		opts.HTMX = newHTMX
+	}
+	return opts, nil
+}
+
+// shouldPromptNew reports whether nexo new asks for the project options.
+func shouldPromptNew(cmd *cobra.Command) bool {
+	if skipPrompts || jsonOutput || !isatty.IsTerminal(os.Stdin.Fd()) {
+		return false
+	}
+	for _, name := range newProjectFlags {
+		if cmd.Flags().Changed(name) {
+			return false
+		}
+	}
+	return true
 }
 
 func runNew(cmd *cobra.Command, args []string) {
@@ -47,84 +131,44 @@ func runNew(cmd *cobra.Command, args []string) {
 	green := color.New(color.FgGreen).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
 
-	if !jsonOutput {
-		fmt.Printf("\n  %s Creating new project: %s\n\n", cyan("Nexo"), name)
-	}
-
-	// Check if directory exists
-	if _, err := os.Stat(name); !os.IsNotExist(err) {
+	fail := func(err error) {
 		if jsonOutput {
-			printJSONError(fmt.Errorf("directory %s already exists", name))
+			printJSONError(err)
 		} else {
-			fmt.Printf("  %s Directory %s already exists\n\n", color.RedString("Error:"), name)
+			fmt.Printf("  %s %v\n\n", color.RedString("Error:"), err)
 		}
 		os.Exit(1)
 	}
 
-	// Determine project type
-	useTempl := !apiOnly
-
-	// Create directories
-	dirs := []string{
-		filepath.Join(name, "app", "api", "health"),
-		filepath.Join(name, ".vscode"),
+	if !jsonOutput {
+		fmt.Printf("\n  %s Creating new project: %s\n\n", cyan("Nexo"), name)
 	}
 
-	if useTempl {
-		dirs = append(dirs,
-			filepath.Join(name, "styles"),
-			filepath.Join(name, "static", "css"),
-		)
+	// Check if directory exists
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		fail(fmt.Errorf("directory %s already exists", name))
 	}
 
-	var createdFiles []string
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			if jsonOutput {
-				printJSONError(fmt.Errorf("failed to create %s: %w", dir, err))
-			} else {
-				fmt.Printf("  %s Failed to create %s: %v\n", color.RedString("Error:"), dir, err)
-			}
-			os.Exit(1)
-		}
-		if !jsonOutput {
-			green := color.New(color.FgGreen).SprintFunc()
-			fmt.Printf("  %s Created %s/\n", green("✓"), dir)
+	opts, err := resolveProjectOptions(cmd, name)
+	if err != nil {
+		fail(err)
+	}
+	if shouldPromptNew(cmd) {
+		if err := promptProjectOptions(&opts); err != nil {
+			fail(err)
 		}
+		fmt.Println()
 	}
-
-	// Template data
-	data := struct {
-		Name       string
-		ModuleName string
-	}{
-		Name:       name,
-		ModuleName: name,
+	if err := opts.validate(); err != nil {
+		fail(err)
 	}
 
 	// Create files from templates
-	files := map[string]string{
-		filepath.Join(name, "go.mod"):                           goModTmpl,
-		filepath.Join(name, "nexo.yaml"):                        nexoYamlTmpl,
-		filepath.Join(name, ".gitignore"):                       gitignoreTmpl,
-		filepath.Join(name, ".vscode", "settings.json"):         vscodeSettingsTmpl,
-		filepath.Join(name, "app", "api", "health", "route.go"): healthRouteTmpl,
-	}
-
-	// Choose main.go template based on project type
-	if useTempl {
-		files[filepath.Join(name, "main.go")] = mainGoTemplTmpl
-		files[filepath.Join(name, "app", "layout.templ")] = layoutTemplTmpl
-		files[filepath.Join(name, "app", "page.templ")] = pageTemplTmpl
-		files[filepath.Join(name, "styles", "input.css")] = tailwindInputCssTmpl
-		// Create .gitkeep for static/css (output.css will be generated)
-		files[filepath.Join(name, "static", "css", ".gitkeep")] = ""
-	} else {
-		files[filepath.Join(name, "main.go")] = mainGoAPIOnlyTmpl
-	}
-
-	for path, tmplContent := range files {
-		if err := createFileFromTemplate(path, tmplContent, data); err != nil {
+	files := projectFiles(opts)
+	var createdFiles []string
+	for _, rel := range sortedPaths(files) {
+		path := filepath.Join(name, rel)
+		if err := createFileFromTemplate(path, files[rel], opts); err != nil {
 			if jsonOutput {
 				printJSONError(fmt.Errorf("failed to create %s: %w", path, err))
 			} else {
@@ -134,13 +178,12 @@ func runNew(cmd *cobra.Command, args []string) {
 		}
 		createdFiles = append(createdFiles, path)
 		if !jsonOutput {
-			green := color.New(color.FgGreen).SprintFunc()
 			fmt.Printf("  %s Created %s\n", green("✓"), path)
 		}
 	}
 
 	// Install templ CLI if using templ
-	if useTempl && !skipPrompts {
+	if opts.Pages() && !skipPrompts {
 		if !jsonOutput {
 			fmt.Printf("\n  %s Installing templ CLI...\n", yellow("→"))
 		}
@@ -195,14 +238,21 @@ func runNew(cmd *cobra.Command, args []string) {
 	_ = os.Chdir(origDir)
 
 	// Output result
+	nextSteps := projectNextSteps(opts)
 	if jsonOutput {
 		result := map[string]any{
 			"name":      name,
+			"module":    opts.ModuleName,
+			"template":  opts.Template,
+			"database":  opts.Database,
+			"auth":      opts.Auth,
+			"tailwind":  opts.Tailwind,
+			"htmx":      opts.HTMX,
 			"files":     createdFiles,
 			"type":      "full",
-			"nextSteps": []string{"cd " + name, "nexo dev"},
+			"nextSteps": nextSteps,
 		}
-		if apiOnly {
+		if !opts.Pages() {
 			result["type"] = "api-only"
 		}
 		enc := json.NewEncoder(os.Stdout)
@@ -211,8 +261,13 @@ func runNew(cmd *cobra.Command, args []string) {
 	} else {
 		fmt.Printf("\n  %s Project created successfully!\n\n", green("✓"))
 		fmt.Printf("  Next steps:\n")
-		fmt.Printf("    %s cd %s\n", cyan("$"), name)
-		fmt.Printf("    %s nexo dev\n\n", cyan("$"))
+		for _, step := range nextSteps {
+			fmt.Printf("    %s %s\n", cyan("$"), step)
+		}
+		if opts.Template == starterMicroservice {
+			fmt.Printf("\n  Package it as a container with %s\n", cyan("nexo build --docker"))
+		}
+		fmt.Println()
 	}
 }
 
@@ -243,61 +298,6 @@ func createFileFromTemplate(path, tmplContent string, data any) error {
 
 // --- Templates ---
 
-var mainGoTemplTmpl = strings.TrimSpace(`
-package main
-
-import (
-	"log"
-	"os"
-
-	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
-)
-
-func main() {
-	app := nexo.New()
-
-	// Serve static files
-	app.Static("/static", "static")
-
-	// Run the application
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
-	}
-
-	log.Printf("Starting server on http://localhost:%s", port)
-	if err := app.Listen(":" + port); err != nil {
-		log.Fatal(err)
-	}
-}
-`) + "\n"
-
-var mainGoAPIOnlyTmpl = strings.TrimSpace(`
-package main
-
-import (
-	"log"
-	"os"
-
-	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
-)
-
-func main() {
-	app := nexo.New()
-
-	// Run the application
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
-	}
-
-	log.Printf("Starting server on http://localhost:%s", port)
-	if err := app.Listen(":" + port); err != nil {
-		log.Fatal(err)
-	}
-}
-`) + "\n"
-
 var goModTmpl = strings.TrimSpace(`
 module {{.ModuleName}}
 
@@ -306,18 +306,25 @@ go 1.21
 
 var nexoYamlTmpl = strings.TrimSpace(`
 # Nexo Configuration
+name: "{{.Name}}"
 port: 3000
 host: "0.0.0.0"
 
 # Directories
 app_dir: "app"
+{{- if .Pages}}
 static_dir: "static"
 static_path: "/static"
+{{- end}}
 
 # Development
 dev:
   hot_reload: true
+{{- if .Pages}}
   watch_extensions: [".go", ".templ", ".css"]
+{{- else}}
+  watch_extensions: [".go"]
+{{- end}}
   exclude_dirs: ["node_modules", ".git"]
 
 # Middleware
@@ -392,7 +399,7 @@ func Get(c *nexo.Context) error {
 }
 `) + "\n"
 
-// Layout template with Tailwind CSS and HTMX
+// Layout template, with Tailwind CSS and HTMX when enabled
 var layoutTemplTmpl = strings.TrimSpace(`
 package app
 
@@ -403,8 +410,12 @@ templ Layout(title string) {
 			<meta charset="UTF-8"/>
 			<meta name="viewport" content="width=device-width, initial-scale=1.0"/>
 			<title>{ title } | {{.Name}}</title>
+{{- if .Tailwind}}
 			<link href="/static/css/output.css" rel="stylesheet"/>
+{{- end}}
+{{- if .HTMX}}
 			<script src="https://unpkg.com/htmx.org@1.9.10"></script>
+{{- end}}
 		</head>
 		<body class="bg-gray-50 min-h-screen">
 			{ children... }
@@ -431,6 +442,11 @@ templ Page() {
 					<a href="/api/health" class="inline-block px-6 py-3 bg-blue-600 text-white rounded-lg hover:bg-blue-700 transition">
 						Check API Health
 					</a>
+{{- if .Auth}}
+					<a href="/dashboard" class="inline-block px-6 py-3 bg-gray-200 rounded-lg hover:bg-gray-300 transition">
+						Dashboard
+					</a>
+{{- end}}
 				</div>
 			</div>
 		</main>
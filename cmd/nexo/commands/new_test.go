@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// parseNewFlags parses args with a fresh set of nexo new flags.
+func parseNewFlags(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "new"}
+	addNewFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags(%v) error = %v", args, err)
+	}
+	return cmd
+}
+
+func TestResolveProjectOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want projectOptions
+	}{
+		{
+			name: "default",
+			want: projectOptions{Name: "myapp", ModuleName: "myapp", Template: starterFullstack, Database: databaseNone, Tailwind: true, HTMX: true},
+		},
+		{
+			name: "api-only",
+			args: []string{"--api-only"},
+			want: projectOptions{Name: "myapp", ModuleName: "myapp", Template: starterAPI, Database: databaseNone},
+		},
+		{
+			name: "starter defaults",
+			args: []string{"-t", "htmx-auth", "-m", "github.com/me/myapp"},
+			want: projectOptions{Name: "myapp", ModuleName: "github.com/me/myapp", Template: starterHTMXAuth, Database: databaseNone, Auth: true, Tailwind: true, HTMX: true},
+		},
+		{
+			name: "flags override starter",
+			args: []string{"-t", "htmx-auth", "--db", "postgres", "--tailwind=false"},
+			want: projectOptions{Name: "myapp", ModuleName: "myapp", Template: starterHTMXAuth, Database: databasePostgres, Auth: true, HTMX: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveProjectOptions(parseNewFlags(t, tt.args...), "myapp")
+			if err != nil {
+				t.Fatalf("resolveProjectOptions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveProjectOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveProjectOptions_Errors(t *testing.T) {
+	tests := map[string][]string{
+		"unknown template":      {"-t", "rails"},
+		"api-only and template": {"--api-only", "-t", "fullstack"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := resolveProjectOptions(parseNewFlags(t, args...), "myapp"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestProjectOptionsValidate(t *testing.T) {
+	valid := projectOptions{Name: "myapp", ModuleName: "github.com/me/myapp", Template: starterAPI, Database: databaseNone}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+
+	tests := map[string]func(o *projectOptions){
+		"unknown database":  func(o *projectOptions) { o.Database = "mysql" },
+		"bad module path":   func(o *projectOptions) { o.ModuleName = "my app" },
+		"tailwind with api": func(o *projectOptions) { o.Tailwind = true },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := valid
+			mutate(&opts)
+			if err := opts.validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// TestProjectFiles renders every starter and checks the generated Go code
+// parses.
+func TestProjectFiles(t *testing.T) {
+	tests := []struct {
+		opts projectOptions
+		want []string
+		skip []string
+	}{
+		{
+			opts: projectOptions{Template: starterFullstack, Database: databaseNone, Tailwind: true, HTMX: true},
+			want: []string{"main.go", "app/layout.templ", "app/page.templ", "styles/input.css", ".vscode/settings.json"},
+			skip: []string{"internal/db/db.go", "app/proxy.go"},
+		},
+		{
+			opts: projectOptions{Template: starterAPI, Database: databaseNone},
+			want: []string{"main.go", "app/api/health/route.go"},
+			skip: []string{"app/layout.templ", "styles/input.css"},
+		},
+		{
+			opts: projectOptions{Template: starterAPIPostgres, Database: databasePostgres},
+			want: []string{"sqlc.yaml", "db/queries/users.sql", "internal/db/users.sql.go", "internal/db/connect.go", "app/api/users/route.go", "docker-compose.yml", ".env.example"},
+		},
+		{
+			opts: projectOptions{Template: starterAPI, Database: databaseNone, Auth: true},
+			want: []string{"app/api/middleware.go", ".env.example"},
+		},
+		{
+			opts: projectOptions{Template: starterHTMXAuth, Database: databasePostgres, Auth: true, HTMX: true},
+			want: []string{"internal/auth/auth.go", "app/proxy.go", "app/login/page.templ", "app/login/route.go", "app/logout/route.go", "app/dashboard/page.templ", "app/sso/login/route.go", "app/sso/callback/route.go"},
+			skip: []string{"styles/input.css"},
+		},
+		{
+			opts: projectOptions{Template: starterMicroservice, Database: databaseNone},
+			want: []string{"app/api/ready/route.go"},
+			skip: []string{".vscode/settings.json", "app/layout.templ"},
+		},
+	}
+
+	for _, tt := range tests {
+		opts := tt.opts
+		opts.Name = "myapp"
+		opts.ModuleName = "github.com/me/myapp"
+		t.Run(opts.Template, func(t *testing.T) {
+			dir := t.TempDir()
+			files := projectFiles(opts)
+			for _, rel := range tt.want {
+				if _, ok := files[rel]; !ok {
+					t.Errorf("missing %s", rel)
+				}
+			}
+			for _, rel := range tt.skip {
+				if _, ok := files[rel]; ok {
+					t.Errorf("unexpected %s", rel)
+				}
+			}
+
+			fset := token.NewFileSet()
+			for rel, tmpl := range files {
+				path := filepath.Join(dir, rel)
+				if err := createFileFromTemplate(path, tmpl, opts); err != nil {
+					t.Fatalf("createFileFromTemplate(%s) error = %v", rel, err)
+				}
+				if filepath.Ext(rel) != ".go" {
+					continue
+				}
+				if _, err := parser.ParseFile(fset, path, nil, parser.AllErrors); err != nil {
+					t.Errorf("%s does not parse: %v", rel, err)
+				}
+			}
+
+			main, err := os.ReadFile(filepath.Join(dir, "main.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(main), "RegisterRoutes(app)") {
+				t.Error("main.go should call RegisterRoutes")
+			}
+			if got := strings.Contains(string(main), "db.Connect("); got != opts.Postgres() {
+				t.Errorf("main.go connects to the database = %v, want %v", got, opts.Postgres())
+			}
+		})
+	}
+}
+
+func TestLayoutTemplateOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "layout.templ")
+	opts := projectOptions{Name: "myapp", Template: starterFullstack, Tailwind: true}
+	if err := createFileFromTemplate(path, layoutTemplTmpl, opts); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "output.css") {
+		t.Error("layout should link the Tailwind stylesheet")
+	}
+	if strings.Contains(string(content), "htmx.org") {
+		t.Error("layout should not load HTMX when it is disabled")
+	}
+}
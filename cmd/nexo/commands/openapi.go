@@ -32,7 +32,8 @@ var openapiGenerateCmd = &cobra.Command{
 
 This command scans your app/ directory and generates an OpenAPI 
 specification with all discovered routes, including documentation 
-extracted from code comments.
+extracted from code comments and RouteMeta declarations, and schemas
+for the structs handlers bind with c.Bind and respond with via c.JSON.
 
 Examples:
   nexo openapi generate
@@ -48,7 +49,8 @@ var openapiServeCmd = &cobra.Command{
 	Long: `Start a local server with Swagger UI for interactive API exploration.
 
 This command generates (or uses an existing) OpenAPI specification 
-and serves it with Swagger UI at /docs and the raw spec at /openapi.json.
+and serves it with Swagger UI at /docs, Redoc at /redoc and the raw
+spec at /openapi.json.
 
 Examples:
   nexo openapi serve
@@ -133,6 +135,7 @@ func runOpenAPIGenerate(cmd *cobra.Command, args []string) {
 		Description: openapiDesc,
 	}
 
+	config.OpenAPIVersion = "3.1.0"
 	if openapiOpenAPI30 {
 		config.OpenAPIVersion = "3.0.3"
 	}
@@ -150,9 +153,8 @@ func runOpenAPIGenerate(cmd *cobra.Command, args []string) {
 	// Create generator
 	gen := nexo.NewOpenAPIGenerator(openapiAppDir, config)
 
-	// Count routes
-	scanner := nexo.NewScanner(openapiAppDir)
-	routes, err := scanner.ScanRouteInfo()
+	// Generate once to count routes and schemas
+	doc, err := gen.Generate()
 	if err != nil {
 		if jsonOutput {
 			printJSONError(err)
@@ -161,9 +163,17 @@ func runOpenAPIGenerate(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	routes := 0
+	for _, item := range doc.Paths.Map() {
+		routes += len(item.Operations())
+	}
+	schemas := 0
+	if doc.Components != nil {
+		schemas = len(doc.Components.Schemas)
+	}
 
 	if !jsonOutput {
-		fmt.Printf("  %s Found %d routes\n", green("✓"), len(routes))
+		fmt.Printf("  %s Found %d routes\n", green("✓"), routes)
 		fmt.Printf("  → Generating OpenAPI spec...\n")
 	}
 
@@ -186,7 +196,8 @@ func runOpenAPIGenerate(cmd *cobra.Command, args []string) {
 			"file":    openapiOutput,
 			"format":  openapiFormat,
 			"version": config.OpenAPIVersion,
-			"routes":  len(routes),
+			"routes":  routes,
+			"schemas": schemas,
 			"size":    size,
 		})
 		return
@@ -195,7 +206,8 @@ func runOpenAPIGenerate(cmd *cobra.Command, args []string) {
 	fmt.Printf("  %s Spec generated\n\n", green("✓"))
 	fmt.Printf("  Output:  %s\n", green(openapiOutput))
 	fmt.Printf("  Format:  OpenAPI %s (%s)\n", config.OpenAPIVersion, openapiFormat)
-	fmt.Printf("  Routes:  %d\n", len(routes))
+	fmt.Printf("  Routes:  %d\n", routes)
+	fmt.Printf("  Schemas: %d\n", schemas)
 	fmt.Printf("  Size:    %s\n\n", dim(size))
 }
 
@@ -269,6 +281,13 @@ func runOpenAPIServe(cmd *cobra.Command, args []string) {
 		_, _ = w.Write([]byte(swaggerHTML))
 	})
 
+	// Serve Redoc at /redoc
+	redocHTML := getRedocHTML("/openapi.json")
+	mux.HandleFunc("/redoc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(redocHTML))
+	})
+
 	// Redirect root to /docs
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
@@ -280,6 +299,7 @@ func runOpenAPIServe(cmd *cobra.Command, args []string) {
 
 	addr := fmt.Sprintf(":%s", openapiPort)
 	fmt.Printf("  %s Swagger UI:    %s\n", green("➜"), cyan(fmt.Sprintf("http://localhost%s/docs", addr)))
+	fmt.Printf("  %s Redoc:         %s\n", green("➜"), cyan(fmt.Sprintf("http://localhost%s/redoc", addr)))
 	fmt.Printf("  %s OpenAPI JSON:  %s\n\n", green("➜"), dim(fmt.Sprintf("http://localhost%s/openapi.json", addr)))
 	fmt.Printf("  Press %s to stop\n\n", yellow("Ctrl+C"))
 
@@ -330,6 +350,25 @@ func getSwaggerUIHTML(specURL string) string {
 </html>`, specURL)
 }
 
+// getRedocHTML returns the HTML for Redoc
+func getRedocHTML(specURL string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>API Documentation</title>
+    <style>
+        body { margin: 0; padding: 0; }
+    </style>
+</head>
+<body>
+    <redoc spec-url="%s"></redoc>
+    <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`, specURL)
+}
+
 // getProjectNameFromGoMod tries to extract the project name from go.mod
 func getProjectNameFromGoMod() string {
 	data, err := os.ReadFile("go.mod")
@@ -222,3 +222,15 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGetRedocHTML(t *testing.T) {
+	html := getRedocHTML("/openapi.json")
+
+	if !containsString(html, `spec-url="/openapi.json"`) {
+		t.Error("HTML should load the spec URL")
+	}
+
+	if !containsString(html, "redoc.standalone.js") {
+		t.Error("HTML should include the Redoc bundle")
+	}
+}
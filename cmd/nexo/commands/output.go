@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 )
 
 // jsonOutput is the global flag for JSON output mode
@@ -23,10 +25,18 @@ type RoutesOutput struct {
 	Middleware  []MiddlewareOutput `json:"middleware,omitempty"`
 	Routes      []RouteOutput      `json:"routes"`
 	Pages       []PageOutput       `json:"pages,omitempty"`
+	Diagnostics []nexo.Diagnostic  `json:"diagnostics,omitempty"`
 	TotalRoutes int                `json:"total_routes"`
 	TotalPages  int                `json:"total_pages,omitempty"`
 }
 
+// RoutesLintOutput represents the JSON output for routes --lint
+type RoutesLintOutput struct {
+	Diagnostics []nexo.Diagnostic `json:"diagnostics"`
+	Errors      int               `json:"errors"`
+	Warnings    int               `json:"warnings"`
+}
+
 // ProxyOutput represents proxy information in JSON output
 type ProxyOutput struct {
 	Enabled  bool     `json:"enabled"`
@@ -42,10 +52,17 @@ type MiddlewareOutput struct {
 
 // RouteOutput represents a single route in JSON output
 type RouteOutput struct {
-	Method   string `json:"method"`
-	Pattern  string `json:"pattern"`
-	File     string `json:"file"`
-	Priority int    `json:"priority,omitempty"`
+	Method     string   `json:"method"`
+	Pattern    string   `json:"pattern"`
+	File       string   `json:"file"`
+	Priority   int      `json:"priority,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Auth       string   `json:"auth,omitempty"`
+	Deprecated bool     `json:"deprecated,omitempty"`
+	WebSocket  bool     `json:"websocket,omitempty"`
+	SSE        bool     `json:"sse,omitempty"`
+	Action     bool     `json:"action,omitempty"`
 }
 
 // PageOutput represents a single page in JSON output
@@ -66,11 +83,16 @@ type NewProjectOutput struct {
 
 // BuildOutput represents the JSON output for the build command
 type BuildOutput struct {
-	Binary  string `json:"binary"`
-	OS      string `json:"os"`
-	Arch    string `json:"arch"`
-	Size    int64  `json:"size,omitempty"`
-	Success bool   `json:"success"`
+	Binary    string   `json:"binary,omitempty"`
+	OS        string   `json:"os"`
+	Arch      string   `json:"arch"`
+	Size      int64    `json:"size,omitempty"`
+	Generated []string `json:"generated,omitempty"`
+	Static    string   `json:"embedded_static,omitempty"`
+	Image     string   `json:"image,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Run       string   `json:"run,omitempty"`
+	Success   bool     `json:"success"`
 }
 
 // DevOutput represents the JSON output for the dev command
@@ -90,6 +112,52 @@ type GenerateOutput struct {
 	Methods []string `json:"methods,omitempty"`
 }
 
+// UndoOutput represents the JSON output for generate --undo-last
+type UndoOutput struct {
+	Command string   `json:"command"`
+	Removed []string `json:"removed"`
+}
+
+// DBMigrateOutput represents the JSON output for the db migrate command
+type DBMigrateOutput struct {
+	Direction string `json:"direction"`
+	Dir       string `json:"dir"`
+	Steps     int    `json:"steps,omitempty"`
+}
+
+// CallOutput represents the JSON output for the call command
+type CallOutput struct {
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Target     string            `json:"target"` // server, app or url
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers"`
+	Body       any               `json:"body"`
+	DurationMs int64             `json:"duration_ms"`
+}
+
+// DBStatusOutput represents the JSON output for the db status command
+type DBStatusOutput struct {
+	Dir        string                  `json:"dir"`
+	Version    uint64                  `json:"version"`
+	Dirty      bool                    `json:"dirty"`
+	Pending    int                     `json:"pending"`
+	Migrations []MigrationStatusOutput `json:"migrations"`
+}
+
+// MigrationStatusOutput represents a migration in db status output
+type MigrationStatusOutput struct {
+	Version uint64 `json:"version"`
+	Name    string `json:"name"`
+	Status  string `json:"status"` // applied, pending or dirty
+}
+
+// DBSeedOutput represents the JSON output for the db seed command
+type DBSeedOutput struct {
+	Env    string   `json:"env"`
+	Seeded []string `json:"seeded"`
+}
+
 // ValidateOutput represents the JSON output for the validate command
 type ValidateOutput struct {
 	Valid      bool     `json:"valid"`
@@ -173,6 +241,7 @@ type AppDeleteOutput struct {
 type DeployOutput struct {
 	Success      bool              `json:"success"`
 	DeploymentID string            `json:"deployment_id,omitempty"`
+	Provider     string            `json:"provider,omitempty"`
 	Version      string            `json:"version,omitempty"`
 	Status       string            `json:"status,omitempty"`
 	URL          string            `json:"url,omitempty"`
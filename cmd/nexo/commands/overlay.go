@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// compilerErrorRe matches compiler diagnostics such as "app/page.templ:12:5: message".
+var compilerErrorRe = regexp.MustCompile(`^(\S+\.(?:go|templ)):(\d+)(?::(\d+))?:\s*(.*)$`)
+
+// errorOverlay holds a page with the compiler output while the app cannot be
+// built, so the browser shows the error instead of a refused connection. The
+// dev proxy serves it in place of the app; without the proxy it listens on
+// the app port itself.
+type errorOverlay struct {
+	mu     sync.Mutex
+	server *http.Server
+	page   []byte
+}
+
+var devOverlay = &errorOverlay{}
+
+// Show sets the overlay for output, replacing any overlay already shown.
+func (o *errorOverlay) Show(title, output string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.page = renderErrorOverlay(title, output)
+}
+
+// Listen serves the overlay on port until Hide is called. Calling it again
+// while serving is a no-op.
+func (o *errorOverlay) Listen(port string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.server != nil {
+		return nil
+	}
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	o.server = &http.Server{Handler: http.HandlerFunc(o.serveHTTP)}
+	go func(srv *http.Server) { _ = srv.Serve(ln) }(o.server)
+	return nil
+}
+
+// Page returns the overlay page, or nil if no overlay is shown.
+func (o *errorOverlay) Page() []byte {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.page
+}
+
+// Hide clears the overlay and releases the port if it was listening.
+func (o *errorOverlay) Hide() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.page = nil
+	if o.server != nil {
+		_ = o.server.Close()
+		o.server = nil
+	}
+}
+
+func (o *errorOverlay) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	page := o.page
+	o.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write(page)
+}
+
+// renderErrorOverlay renders the overlay page, highlighting file:line
+// locations in the compiler output.
+func renderErrorOverlay(title, output string) []byte {
+	var lines strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if m := compilerErrorRe.FindStringSubmatch(line); m != nil {
+			loc := m[1] + ":" + m[2]
+			if m[3] != "" {
+				loc += ":" + m[3]
+			}
+			fmt.Fprintf(&lines, "<span class=\"loc\">%s</span> <span class=\"msg\">%s</span>\n", html.EscapeString(loc), html.EscapeString(m[4]))
+			continue
+		}
+		lines.WriteString(html.EscapeString(line) + "\n")
+	}
+
+	var script string
+	if devLiveReload != nil {
+		script = nexo.LiveReloadScript(devReloadPort)
+	}
+
+	return []byte(fmt.Sprintf(errorOverlayTemplate, html.EscapeString(title), html.EscapeString(title), lines.String(), script))
+}
+
+const errorOverlayTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { margin: 0; background: #1e1e1e; color: #d4d4d4; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+header { padding: 1rem 2rem; background: #b42318; color: #fff; font-size: 1.1rem; font-weight: bold; }
+pre { margin: 0; padding: 1.5rem 2rem; font-size: 14px; line-height: 1.5; white-space: pre-wrap; }
+.loc { color: #4fc1ff; }
+.msg { color: #f48771; }
+footer { padding: 0 2rem 1.5rem; color: #808080; font-size: 13px; }
+</style>
+</head>
+<body>
+<header>%s</header>
+<pre>%s</pre>
+<footer>Fix the error and save; the page reloads once the app builds again.</footer>
+%s
+</body>
+</html>
+`
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderErrorOverlay(t *testing.T) {
+	output := "# example\n./main.go:12:5: undefined: foo <bar>\napp/page.templ:3: parse error\n"
+	page := string(renderErrorOverlay("Build failed", output))
+
+	for _, s := range []string{
+		"<header>Build failed</header>",
+		`<span class="loc">./main.go:12:5</span> <span class="msg">undefined: foo &lt;bar&gt;</span>`,
+		`<span class="loc">app/page.templ:3</span> <span class="msg">parse error</span>`,
+		"# example\n",
+	} {
+		if !strings.Contains(page, s) {
+			t.Errorf("Expected %q in overlay, got:\n%s", s, page)
+		}
+	}
+}
+
+func TestErrorOverlay_ShowHide(t *testing.T) {
+	port := findAvailablePort("39517")
+	overlay := &errorOverlay{}
+
+	overlay.Show("Build failed", "main.go:1:1: boom")
+	if err := overlay.Listen(port); err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	// Showing again updates the page without rebinding the port
+	overlay.Show("templ generate failed", "page.templ:2:1: bang")
+	if err := overlay.Listen(port); err != nil {
+		t.Fatalf("second Listen failed: %v", err)
+	}
+
+	resp, err := http.Get("http://127.0.0.1:" + port + "/any/path")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "templ generate failed") {
+		t.Errorf("expected latest error in overlay, got:\n%s", body)
+	}
+
+	overlay.Hide()
+	if overlay.Page() != nil {
+		t.Error("expected no page after Hide")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !isPortAvailable(port) {
+		t.Error("expected port to be released after Hide")
+	}
+}
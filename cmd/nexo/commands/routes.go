@@ -6,9 +6,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/abdul-hamid-achik/nexo/pkg/scanner"
 	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 )
 
@@ -21,22 +24,73 @@ This command scans the app/ directory and displays:
 - API routes (route.go files) with their HTTP methods and patterns
 - Pages (page.templ files) with their URL patterns and associated layouts
 
+With --lint, the routes are checked for conflicts, shadowed catch-alls,
+unreachable routes and handlers with invalid signatures instead. The command
+exits with status 1 when errors are found.
+
+With --watch, the output is refreshed whenever the app directory changes.
+
 Examples:
   nexo routes
   nexo routes --json
-  nexo routes --app-dir custom/app`,
+  nexo routes --app-dir custom/app
+  nexo routes --lint
+  nexo routes --watch`,
 	Run: runRoutes,
 }
 
 var (
 	routesAppDir string
+	routesLint   bool
+	routesWatch  bool
 )
 
 func init() {
 	routesCmd.Flags().StringVarP(&routesAppDir, "app-dir", "d", "app", "App directory to scan")
+	routesCmd.Flags().BoolVar(&routesLint, "lint", false, "Check routes for conflicts and other problems")
+	routesCmd.Flags().BoolVarP(&routesWatch, "watch", "w", false, "Refresh the output when the app directory changes")
 }
 
 func runRoutes(cmd *cobra.Command, args []string) {
+	if routesWatch {
+		if jsonOutput {
+			printJSONError(fmt.Errorf("--watch cannot be combined with --json"))
+			os.Exit(1)
+		}
+		watchRoutes()
+		return
+	}
+
+	if routesLint {
+		errors, err := lintRoutes()
+		if err == nil && errors > 0 {
+			os.Exit(1)
+		}
+		if err != nil {
+			printRoutesError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := printRoutes(); err != nil {
+		printRoutesError(err)
+		os.Exit(1)
+	}
+}
+
+// printRoutesError reports a scan failure in the current output mode.
+func printRoutesError(err error) {
+	if jsonOutput {
+		printJSONError(err)
+	} else {
+		red := color.New(color.FgRed).SprintFunc()
+		fmt.Printf("  %s %v\n", red("Error:"), err)
+	}
+}
+
+// printRoutes prints the routes, pages, middleware and proxy in the app directory.
+func printRoutes() error {
 	// Check if app directory exists
 	if _, err := os.Stat(routesAppDir); os.IsNotExist(err) {
 		if jsonOutput {
@@ -48,11 +102,12 @@ func runRoutes(cmd *cobra.Command, args []string) {
 			yellow := color.New(color.FgYellow).SprintFunc()
 			fmt.Printf("\n  %s No app directory found at %s\n\n", yellow("Warning:"), routesAppDir)
 		}
-		return
+		return nil
 	}
 
 	// Scan for routes
 	scanner := nexo.NewScanner(routesAppDir)
+	scanner.SetCacheDir(nexo.DefaultScanCacheDir)
 
 	// Check for proxy
 	proxyInfo, proxyErr := scanner.ScanProxyInfo()
@@ -61,15 +116,9 @@ func runRoutes(cmd *cobra.Command, args []string) {
 	middlewares, mwErr := scanner.ScanMiddlewareInfo()
 
 	// Scan for routes
-	routes, routeErr := scanner.ScanRouteInfo()
+	routes, diags, routeErr := scanner.ScanRouteInfo()
 	if routeErr != nil {
-		if jsonOutput {
-			printJSONError(routeErr)
-		} else {
-			red := color.New(color.FgRed).SprintFunc()
-			fmt.Printf("  %s Failed to scan routes: %v\n", red("Error:"), routeErr)
-		}
-		os.Exit(1)
+		return fmt.Errorf("failed to scan routes: %w", routeErr)
 	}
 
 	// Sort routes by pattern
@@ -83,25 +132,13 @@ func runRoutes(cmd *cobra.Command, args []string) {
 	// Scan for pages
 	pages, pageErr := scanner.ScanPageInfo()
 	if pageErr != nil {
-		if jsonOutput {
-			printJSONError(pageErr)
-		} else {
-			red := color.New(color.FgRed).SprintFunc()
-			fmt.Printf("  %s Failed to scan pages: %v\n", red("Error:"), pageErr)
-		}
-		os.Exit(1)
+		return fmt.Errorf("failed to scan pages: %w", pageErr)
 	}
 
 	// Scan for layouts
 	layouts, layoutErr := scanner.ScanLayoutInfo()
 	if layoutErr != nil {
-		if jsonOutput {
-			printJSONError(layoutErr)
-		} else {
-			red := color.New(color.FgRed).SprintFunc()
-			fmt.Printf("  %s Failed to scan layouts: %v\n", red("Error:"), layoutErr)
-		}
-		os.Exit(1)
+		return fmt.Errorf("failed to scan layouts: %w", layoutErr)
 	}
 
 	// JSON output mode
@@ -140,10 +177,17 @@ func runRoutes(cmd *cobra.Command, args []string) {
 		// Add routes
 		for _, r := range routes {
 			output.Routes = append(output.Routes, RouteOutput{
-				Method:   r.Method,
-				Pattern:  r.Pattern,
-				File:     r.FilePath,
-				Priority: r.Priority,
+				Method:     r.Method,
+				Pattern:    r.Pattern,
+				File:       r.FilePath,
+				Priority:   r.Priority,
+				Summary:    r.Directives.Summary,
+				Tags:       r.Directives.Tags,
+				Auth:       r.Directives.Auth,
+				Deprecated: r.Directives.Deprecated,
+				WebSocket:  r.WebSocket,
+				SSE:        r.SSE,
+				Action:     r.Action,
 			})
 		}
 
@@ -157,8 +201,11 @@ func runRoutes(cmd *cobra.Command, args []string) {
 			})
 		}
 
+		// Add handlers and middleware the scanner skipped
+		output.Diagnostics = diags
+
 		printSuccess(output)
-		return
+		return nil
 	}
 
 	// Text output mode
@@ -212,6 +259,8 @@ func runRoutes(cmd *cobra.Command, args []string) {
 			return color.MagentaString("%-7s", method)
 		case "DELETE":
 			return red(fmt.Sprintf("%-7s", method))
+		case "WS", "SSE", "ACTION":
+			return magenta(fmt.Sprintf("%-7s", method))
 		default:
 			return fmt.Sprintf("%-7s", method)
 		}
@@ -221,10 +270,26 @@ func runRoutes(cmd *cobra.Command, args []string) {
 	if len(routes) > 0 {
 		fmt.Printf("  %s\n\n", cyan("API Routes:"))
 		for _, route := range routes {
-			fmt.Printf("  %s %s  %s\n",
-				methodColor(route.Method),
+			directives := ""
+			if d := formatDirectives(route.Directives); d != "" {
+				directives = "  " + d
+			}
+			// ws.go and sse.go routes are GETs that upgrade to WebSocket
+			// or stream events, and action.go routes POSTs to a page
+			method := route.Method
+			switch {
+			case route.WebSocket:
+				method = "WS"
+			case route.SSE:
+				method = "SSE"
+			case route.Action:
+				method = "ACTION"
+			}
+			fmt.Printf("  %s %s  %s%s\n",
+				methodColor(method),
 				fmt.Sprintf("%-30s", route.Pattern),
 				dim(route.FilePath),
+				directives,
 			)
 		}
 	}
@@ -251,6 +316,20 @@ func runRoutes(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Show skipped handlers and middleware, which would otherwise be 404s
+	if len(diags) > 0 {
+		if len(routes) > 0 || len(pages) > 0 {
+			fmt.Printf("\n")
+		}
+		fmt.Printf("  %s\n\n", yellow("Skipped:"))
+		for _, d := range diags {
+			fmt.Printf("  %s\n", d)
+		}
+		if len(routes) == 0 && len(pages) == 0 {
+			fmt.Printf("\n")
+		}
+	}
+
 	// Show warning if no routes and no pages
 	if len(routes) == 0 && len(pages) == 0 {
 		fmt.Printf("  %s No routes or pages found\n\n", yellow("Warning:"))
@@ -258,10 +337,157 @@ func runRoutes(cmd *cobra.Command, args []string) {
 		fmt.Printf("    %s/api/health/route.go\n\n", routesAppDir)
 		fmt.Printf("  Or create a page by adding a page.templ file:\n")
 		fmt.Printf("    %s/page.templ\n\n", routesAppDir)
-		return
+		return nil
 	}
 
 	fmt.Printf("\n  Total: %d API routes, %d pages\n\n", len(routes), len(pages))
+	return nil
+}
+
+// lintRoutes prints the problems found in the app directory and returns the
+// number of errors.
+func lintRoutes() (int, error) {
+	result, err := scanner.NewScanner(routesAppDir).Scan()
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan routes: %w", err)
+	}
+	diags := scanner.Lint(result)
+
+	errors, warnings := 0, 0
+	for _, d := range diags {
+		if d.Severity == scanner.SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	if jsonOutput {
+		output := RoutesLintOutput{
+			Diagnostics: diags,
+			Errors:      errors,
+			Warnings:    warnings,
+		}
+		if output.Diagnostics == nil {
+			output.Diagnostics = []nexo.Diagnostic{}
+		}
+		printSuccess(output)
+		return errors, nil
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	fmt.Printf("\n  %s Route Lint\n\n", cyan("Nexo"))
+
+	for _, d := range diags {
+		severity := yellow(fmt.Sprintf("%-8s", d.Severity))
+		if d.Severity == scanner.SeverityError {
+			severity = red(fmt.Sprintf("%-8s", d.Severity))
+		}
+		file, message := d.FilePath, d.Message
+		if d.Line > 0 {
+			file = fmt.Sprintf("%s:%d", file, d.Line)
+		}
+		if d.Symbol != "" {
+			message = d.Symbol + ": " + message
+		}
+		fmt.Printf("  %s %-19s %s\n", severity, d.Rule, dim(file))
+		fmt.Printf("  %s %s\n\n", strings.Repeat(" ", 28), message)
+	}
+
+	if len(diags) == 0 {
+		fmt.Printf("  %s No problems found\n\n", green("✓"))
+	} else {
+		summary := fmt.Sprintf("%d %s, %d %s", errors, plural(errors, "error"), warnings, plural(warnings, "warning"))
+		if errors > 0 {
+			fmt.Printf("  %s %s\n\n", red("✗"), summary)
+		} else {
+			fmt.Printf("  %s %s\n\n", yellow("⚠"), summary)
+		}
+	}
+	return errors, nil
+}
+
+// watchRoutes prints the routes, or lint results with --lint, and refreshes
+// them whenever a file in the app directory changes.
+func watchRoutes() {
+	render := func() {
+		fmt.Print("\033[H\033[2J")
+		var err error
+		if routesLint {
+			_, err = lintRoutes()
+		} else {
+			err = printRoutes()
+		}
+		if err != nil {
+			printRoutesError(err)
+		}
+		dim := color.New(color.Faint).SprintFunc()
+		fmt.Printf("  %s\n", dim(fmt.Sprintf("Watching %s/ for changes (Ctrl+C to stop)", routesAppDir)))
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		printRoutesError(fmt.Errorf("failed to create file watcher: %w", err))
+		os.Exit(1)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	rules := newDevWatchRules(nexo.DevConfig{})
+	rules.addDirs(watcher.Add, routesAppDir)
+	render()
+
+	var debounce *time.Timer
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					rules.addDirs(watcher.Add, event.Name)
+				}
+			}
+			if debounce != nil {
+				debounce.Stop()
+			}
+			debounce = time.AfterFunc(200*time.Millisecond, render)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			printRoutesError(fmt.Errorf("watcher error: %w", err))
+		}
+	}
+}
+
+// formatDirectives formats a route's directives for the text output, e.g.
+// "Get a user [auth: bearer] [deprecated]".
+func formatDirectives(d nexo.RouteDirectives) string {
+	var parts []string
+	if d.Summary != "" {
+		parts = append(parts, d.Summary)
+	}
+	if d.Auth != "" {
+		parts = append(parts, "[auth: "+d.Auth+"]")
+	}
+	if d.Deprecated {
+		parts = append(parts, "[deprecated]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// plural returns word with an "s" appended unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
 }
 
 // findLayoutForPage returns the layout file path that applies to a page pattern.
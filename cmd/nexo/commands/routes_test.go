@@ -74,7 +74,7 @@ func TestRoutesScanning_EmptyProject(t *testing.T) {
 
 	// Don't create app dir - should handle gracefully
 	scanner := nexo.NewScanner(appDir)
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 
 	// Should return empty, not error
 	if err != nil {
@@ -95,7 +95,7 @@ func TestRoutesScanning_NoRoutes(t *testing.T) {
 	}
 
 	scanner := nexo.NewScanner(appDir)
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 
 	if err != nil {
 		t.Errorf("Expected no error for empty app dir, got %v", err)
@@ -132,7 +132,7 @@ func Post(c *nexo.Context) error {
 	}
 
 	scanner := nexo.NewScanner(appDir)
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 
 	if err != nil {
 		t.Fatalf("ScanRouteInfo failed: %v", err)
@@ -289,7 +289,7 @@ func Get(c *nexo.Context) error {
 	}
 
 	scanner := nexo.NewScanner(appDir)
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 
 	if err != nil {
 		t.Fatalf("ScanRouteInfo failed: %v", err)
@@ -328,7 +328,7 @@ func Get(c *nexo.Context) error {
 	}
 
 	scanner := nexo.NewScanner(appDir)
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 
 	if err != nil {
 		t.Fatalf("ScanRouteInfo failed: %v", err)
@@ -342,3 +342,45 @@ func Get(c *nexo.Context) error {
 		t.Errorf("Expected pattern /api/docs/*, got %s", routes[0].Pattern)
 	}
 }
+
+func TestLintRoutes(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	route := "package x\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error { return nil }\n"
+	for _, dir := range []string{"users/[id]", "users/[userId]"} {
+		if err := os.MkdirAll(filepath.Join(appDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(appDir, dir, "route.go"), []byte(route), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldAppDir := routesAppDir
+	routesAppDir = appDir
+	defer func() { routesAppDir = oldAppDir }()
+
+	errors, err := lintRoutes()
+	if err != nil {
+		t.Fatalf("lintRoutes failed: %v", err)
+	}
+	if errors != 1 {
+		t.Errorf("Expected 1 error for conflicting [id] and [userId] routes, got %d", errors)
+	}
+}
+
+func TestFormatDirectives(t *testing.T) {
+	tests := []struct {
+		d    nexo.RouteDirectives
+		want string
+	}{
+		{nexo.RouteDirectives{}, ""},
+		{nexo.RouteDirectives{Summary: "Get a user", Tags: []string{"users"}}, "Get a user"},
+		{nexo.RouteDirectives{Summary: "Get a user", Auth: "bearer", Deprecated: true}, "Get a user [auth: bearer] [deprecated]"},
+		{nexo.RouteDirectives{Deprecated: true}, "[deprecated]"},
+	}
+	for _, tt := range tests {
+		if got := formatDirectives(tt.d); got != tt.want {
+			t.Errorf("formatDirectives(%+v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+// Package auth signs users in with OAuth2 and OpenID Connect providers such
+// as Google, GitHub, Auth0 or Keycloak. It handles the login redirect, the
+// callback with state and PKCE checks, ID token verification, and keeps
+// the signed-in user in an encrypted session cookie.
+//
+//	sso, err := auth.New(auth.Config{
+//		Provider:     auth.Google(),
+//		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+//		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+//	})
+//
+//	app := nexo.New(nexo.WithCookieSecret(os.Getenv("COOKIE_SECRET")))
+//	sso.Mount(app) // GET /auth/login, GET /auth/callback, POST /auth/logout
+//
+//	app.Get("/me", func(c *nexo.Context) error {
+//		user, _ := sso.User(c)
+//		return c.JSON(200, user)
+//	})
+//
+// Sessions are stored with Context.SetEncryptedCookie, so the app needs a
+// cookie secret (see nexo.WithCookieSecret).
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// loginTTL is how long a user has to finish signing in at the provider.
+const loginTTL = 10 * time.Minute
+
+// User is a signed-in user.
+type User struct {
+	// ID is the user's ID at the provider (the "sub" claim).
+	ID string `json:"id"`
+
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
+
+	// Provider is the Name of the provider the user signed in with.
+	Provider string `json:"provider"`
+
+	// Claims are the ID token's or user info claims. They are only set
+	// during login, in Config.OnLogin, and aren't kept in the session.
+	Claims map[string]any `json:"-"`
+}
+
+// Config holds configuration for an Auth.
+type Config struct {
+	// Provider is the identity provider, e.g. Google() or OIDC(issuer).
+	Provider Provider
+
+	// ClientID and ClientSecret are the app's credentials at the provider.
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the callback URL registered at the provider. Default
+	// is BasePath + "/callback" on the request's host.
+	RedirectURL string
+
+	// Scopes are requested at login. Default is the provider's scopes.
+	Scopes []string
+
+	// BasePath is where Mount registers the login, callback and logout
+	// routes. Default is "/auth".
+	BasePath string
+
+	// AfterLogin is where users go after signing in, unless the login URL
+	// has a return_to path. Default is "/".
+	AfterLogin string
+
+	// AfterLogout is where users go after signing out. Default is "/".
+	AfterLogout string
+
+	// CookieName is the name of the session cookie. Default is
+	// "nexo_session".
+	CookieName string
+
+	// SessionTTL is how long a session lasts. Default is 7 days.
+	SessionTTL time.Duration
+
+	// HTTPClient makes the requests to the provider. Default is a client
+	// with a 10 second timeout.
+	HTTPClient *http.Client
+
+	// OnLogin runs after the provider signs a user in and before the
+	// session starts. It can change the user, or return an error to
+	// refuse them, e.g. nexo.Forbidden for emails outside a domain.
+	OnLogin func(c *nexo.Context, user *User) error
+}
+
+// Auth signs users in with one provider. Use its handlers in the app/
+// tree or register them with Mount.
+type Auth struct {
+	config Config
+
+	mu         sync.Mutex
+	provider   Provider
+	discovered bool
+	keys       *keySet
+}
+
+// currentUser caches the session's user for the request.
+var currentUser = nexo.NewKey[*User]("auth.user")
+
+// New creates an Auth. Providers are only contacted once users sign in.
+// Without a Provider, Login and Callback respond 404 and the Auth only
+// keeps sessions started with SetUser, e.g. by a password login.
+func New(config Config) (*Auth, error) {
+	p := config.Provider
+	if p.Issuer != "" || p.AuthURL != "" || p.TokenURL != "" {
+		if config.ClientID == "" {
+			return nil, errors.New("auth: ClientID is required")
+		}
+		if p.Issuer == "" && (p.AuthURL == "" || p.TokenURL == "") {
+			return nil, errors.New("auth: provider needs an Issuer or AuthURL and TokenURL")
+		}
+	}
+	if config.Provider.Name == "" {
+		config.Provider.Name = "oidc"
+	}
+	if config.Scopes == nil {
+		config.Scopes = config.Provider.Scopes
+	}
+	if config.BasePath == "" {
+		config.BasePath = "/auth"
+	}
+	config.BasePath = strings.TrimSuffix(config.BasePath, "/")
+	if config.AfterLogin == "" {
+		config.AfterLogin = "/"
+	}
+	if config.AfterLogout == "" {
+		config.AfterLogout = "/"
+	}
+	if config.CookieName == "" {
+		config.CookieName = "nexo_session"
+	}
+	if config.SessionTTL <= 0 {
+		config.SessionTTL = 7 * 24 * time.Hour
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Auth{config: config}, nil
+}
+
+// Mount registers GET BasePath/login, GET BasePath/callback and
+// POST BasePath/logout on app. Call it before app.Mount.
+func (a *Auth) Mount(app *nexo.App) {
+	app.Get(a.config.BasePath+"/login", a.Login)
+	app.Get(a.config.BasePath+"/callback", a.Callback)
+	app.Post(a.config.BasePath+"/logout", a.Logout)
+}
+
+// loginState is kept in an encrypted cookie between Login and Callback.
+type loginState struct {
+	State    string `json:"s"`
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	ReturnTo string `json:"r,omitempty"`
+}
+
+// session is the session cookie's value.
+type session struct {
+	User    User  `json:"u"`
+	Expires int64 `json:"e"`
+}
+
+// Login sends the user to the provider to sign in. A return_to query
+// parameter with a local path is where they go afterwards.
+//
+// Example:
+//
+//	// app/login/route.go
+//	func Get(c *nexo.Context) error {
+//		return sso.Login(c)
+//	}
+func (a *Auth) Login(c *nexo.Context) error {
+	provider, err := a.endpoints(c.Context())
+	if err != nil {
+		return err
+	}
+
+	state := loginState{
+		State:    randomString(),
+		Verifier: randomString(),
+		Nonce:    randomString(),
+	}
+	if returnTo := c.Query("return_to"); isLocalPath(returnTo) {
+		state.ReturnTo = returnTo
+	}
+	data, _ := json.Marshal(state)
+	if err := c.SetEncryptedCookie(&http.Cookie{
+		Name:     a.loginCookie(),
+		Value:    string(data),
+		Path:     "/",
+		MaxAge:   int(loginTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}); err != nil {
+		return err
+	}
+
+	challenge := sha256.Sum256([]byte(state.Verifier))
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {a.config.ClientID},
+		"redirect_uri":          {a.redirectURL(c)},
+		"scope":                 {strings.Join(a.config.Scopes, " ")},
+		"state":                 {state.State},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	if provider.Issuer != "" {
+		params.Set("nonce", state.Nonce)
+	}
+
+	sep := "?"
+	if strings.Contains(provider.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.Redirect(provider.AuthURL+sep+params.Encode(), http.StatusFound)
+}
+
+// Callback handles the provider's redirect back to the app: it checks the
+// state, exchanges the code, loads the user and starts their session.
+func (a *Auth) Callback(c *nexo.Context) error {
+	if e := c.Query("error"); e != "" {
+		message := c.Query("error_description")
+		if message == "" {
+			message = e
+		}
+		return nexo.Unauthorized("login failed: " + message)
+	}
+
+	value, err := c.EncryptedCookie(a.loginCookie())
+	if errors.Is(err, nexo.ErrNoCookieSecret) {
+		return err
+	}
+	var state loginState
+	if err != nil || json.Unmarshal([]byte(value), &state) != nil {
+		return nexo.BadRequest("login expired, please try again")
+	}
+	c.SetCookie(&http.Cookie{Name: a.loginCookie(), Path: "/", MaxAge: -1, HttpOnly: true})
+
+	if subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(state.State)) != 1 {
+		return nexo.BadRequest("invalid login state")
+	}
+	code := c.Query("code")
+	if code == "" {
+		return nexo.BadRequest("missing authorization code")
+	}
+
+	user, err := a.exchange(c, code, state)
+	if err != nil {
+		return nexo.NewHTTPErrorWithCause(http.StatusUnauthorized, "login failed", err)
+	}
+	if a.config.OnLogin != nil {
+		if err := a.config.OnLogin(c, user); err != nil {
+			return err
+		}
+	}
+	if err := a.SetUser(c, user); err != nil {
+		return err
+	}
+
+	returnTo := state.ReturnTo
+	if returnTo == "" {
+		returnTo = a.config.AfterLogin
+	}
+	return c.Redirect(returnTo, http.StatusSeeOther)
+}
+
+// Logout ends the session and sends the user to AfterLogout.
+func (a *Auth) Logout(c *nexo.Context) error {
+	a.ClearUser(c)
+	return c.Redirect(a.config.AfterLogout, http.StatusSeeOther)
+}
+
+// User returns the signed-in user, or false if the request has no valid
+// session.
+func (a *Auth) User(c *nexo.Context) (*User, bool) {
+	if user, ok := currentUser.Get(c); ok {
+		return user, user != nil
+	}
+
+	var s session
+	value, err := c.EncryptedCookie(a.config.CookieName)
+	if err != nil || json.Unmarshal([]byte(value), &s) != nil || time.Now().Unix() > s.Expires {
+		currentUser.Set(c, nil)
+		return nil, false
+	}
+	currentUser.Set(c, &s.User)
+	return &s.User, true
+}
+
+// SetUser starts a session for user, e.g. after a password login, so one
+// session cookie serves every way of signing in.
+func (a *Auth) SetUser(c *nexo.Context, user *User) error {
+	expires := time.Now().Add(a.config.SessionTTL)
+	data, _ := json.Marshal(session{User: *user, Expires: expires.Unix()})
+	if err := c.SetEncryptedCookie(&http.Cookie{
+		Name:     a.config.CookieName,
+		Value:    string(data),
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}); err != nil {
+		return err
+	}
+	currentUser.Set(c, user)
+	return nil
+}
+
+// ClearUser ends the session.
+func (a *Auth) ClearUser(c *nexo.Context) {
+	c.SetCookie(&http.Cookie{Name: a.config.CookieName, Path: "/", MaxAge: -1, HttpOnly: true})
+	currentUser.Set(c, nil)
+}
+
+// Require returns a middleware for routes that need a signed-in user.
+// Browsers are sent to the login page and come back afterwards; other
+// requests get a 401.
+func (a *Auth) Require() nexo.MiddlewareFunc {
+	return func(next nexo.HandlerFunc) nexo.HandlerFunc {
+		return func(c *nexo.Context) error {
+			if _, ok := a.User(c); ok {
+				return next(c)
+			}
+			if c.Method() == http.MethodGet && strings.Contains(c.Header("Accept"), "text/html") {
+				login := a.config.BasePath + "/login?return_to=" + url.QueryEscape(c.Request.URL.RequestURI())
+				return c.Redirect(login, http.StatusFound)
+			}
+			return nexo.Unauthorized("login required")
+		}
+	}
+}
+
+// endpoints returns the provider, discovering its endpoints the first time.
+func (a *Auth) endpoints(ctx context.Context) (Provider, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.config.Provider.Issuer == "" && a.config.Provider.AuthURL == "" {
+		return Provider{}, nexo.NotFound("no login provider configured")
+	}
+
+	if !a.discovered {
+		p := a.config.Provider
+		needsDiscovery := p.AuthURL == "" || p.TokenURL == "" || (p.JWKSURL == "" && p.FetchUser == nil)
+		if p.Issuer != "" && needsDiscovery {
+			if err := discover(ctx, a.config.HTTPClient, &p); err != nil {
+				return Provider{}, err
+			}
+		}
+		a.provider = p
+		a.keys = &keySet{url: p.JWKSURL, client: a.config.HTTPClient}
+		a.discovered = true
+	}
+	return a.provider, nil
+}
+
+// tokenResponse is the token endpoint's response.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchange trades the authorization code for tokens and loads the user.
+func (a *Auth) exchange(c *nexo.Context, code string, state loginState) (*User, error) {
+	ctx := c.Context()
+	provider, err := a.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {a.redirectURL(c)},
+		"client_id":     {a.config.ClientID},
+		"code_verifier": {state.Verifier},
+	}
+	if a.config.ClientSecret != "" {
+		form.Set("client_secret", a.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("token request returned %s", resp.Status)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s %s", token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return nil, fmt.Errorf("token request returned %s", resp.Status)
+	}
+
+	var user *User
+	switch {
+	case provider.FetchUser != nil:
+		user, err = provider.FetchUser(ctx, a.config.HTTPClient, token.AccessToken)
+	case token.IDToken != "" && provider.Issuer != "":
+		var claims map[string]any
+		claims, err = verifyIDToken(ctx, a.keys, token.IDToken, provider.Issuer, a.config.ClientID, state.Nonce)
+		user = userFromClaims(claims)
+	case provider.UserInfoURL != "":
+		var claims map[string]any
+		err = getJSON(ctx, a.config.HTTPClient, provider.UserInfoURL, token.AccessToken, &claims)
+		user = userFromClaims(claims)
+	default:
+		err = errors.New("provider returned no ID token and has no UserInfoURL")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.ID == "" {
+		return nil, errors.New("provider returned no user ID")
+	}
+	user.Provider = provider.Name
+	return user, nil
+}
+
+// userFromClaims returns the user described by standard OpenID Connect
+// claims.
+func userFromClaims(claims map[string]any) *User {
+	if claims == nil {
+		return nil
+	}
+	str := func(name string) string {
+		s, _ := claims[name].(string)
+		return s
+	}
+	verified, _ := claims["email_verified"].(bool)
+	return &User{
+		ID:            str("sub"),
+		Email:         str("email"),
+		EmailVerified: verified,
+		Name:          str("name"),
+		Picture:       str("picture"),
+		Claims:        claims,
+	}
+}
+
+// redirectURL returns the callback URL for the request.
+func (a *Auth) redirectURL(c *nexo.Context) string {
+	if a.config.RedirectURL != "" {
+		return a.config.RedirectURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.Header("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + a.config.BasePath + "/callback"
+}
+
+func (a *Auth) loginCookie() string {
+	return a.config.CookieName + "_login"
+}
+
+// randomString returns 32 random bytes, base64url encoded.
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// isLocalPath reports whether path is on this site, so return_to can't
+// send users elsewhere.
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") && !strings.HasPrefix(path, "/\\")
+}
@@ -0,0 +1,435 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// fakeProvider is an OpenID Connect provider for tests.
+type fakeProvider struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string         // code_challenge from the last authorization request
+	nonce     string         // nonce from the last authorization request
+	claims    map[string]any // extra ID token claims
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &fakeProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		verifier := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if r.FormValue("code") != "good-code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := map[string]any{
+			"iss":   p.URL,
+			"aud":   "client",
+			"sub":   "user-1",
+			"email": "ada@example.com",
+			"name":  "Ada",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": p.nonce,
+		}
+		for k, v := range p.claims {
+			claims[k] = v
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"id_token":     p.sign(t, claims),
+		})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *fakeProvider) sign(t *testing.T, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// newTestApp returns an app using a for sign in, with a protected /me and
+// the routes registered by setup.
+func newTestApp(t *testing.T, a *Auth, setup ...func(app *nexo.App)) *nexo.App {
+	t.Helper()
+	app := nexo.New(nexo.WithCookieSecret("0123456789abcdef0123456789abcdef"))
+	app.DisableLogger()
+	a.Mount(app)
+	for _, fn := range setup {
+		fn(app)
+	}
+	app.Group("/me", func(g *nexo.RouteGroup) {
+		g.Use(a.Require())
+		g.Get("", func(c *nexo.Context) error {
+			user, _ := a.User(c)
+			return c.JSON(http.StatusOK, user)
+		})
+	})
+	app.Mount()
+	return app
+}
+
+// do sends a request with cookies and returns the response.
+func do(app *nexo.App, method, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+// login starts a login and returns the login cookies and the provider's
+// authorization URL.
+func login(t *testing.T, app *nexo.App, p *fakeProvider, target string) ([]*http.Cookie, *url.URL) {
+	t.Helper()
+	w := do(app, http.MethodGet, target, nil)
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected a redirect to the provider, got %d: %s", w.Code, w.Body.String())
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.challenge = location.Query().Get("code_challenge")
+	p.nonce = location.Query().Get("nonce")
+	return w.Result().Cookies(), location
+}
+
+func TestAuth_OIDCLogin(t *testing.T) {
+	p := newFakeProvider(t)
+	a, err := New(Config{Provider: OIDC(p.URL), ClientID: "client", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, a)
+
+	cookies, location := login(t, app, p, "/auth/login?return_to=/me")
+	if location.Path != "/authorize" {
+		t.Errorf("expected the discovered authorization endpoint, got %s", location)
+	}
+	q := location.Query()
+	if q.Get("client_id") != "client" || q.Get("code_challenge_method") != "S256" || q.Get("scope") != "openid email profile" {
+		t.Errorf("unexpected authorization parameters: %v", q)
+	}
+	if q.Get("redirect_uri") != "http://example.com/auth/callback" {
+		t.Errorf("expected the callback on the request's host, got %q", q.Get("redirect_uri"))
+	}
+
+	w := do(app, http.MethodGet, "/auth/callback?code=good-code&state="+q.Get("state"), cookies)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/me" {
+		t.Fatalf("expected a redirect to return_to, got %d %q: %s", w.Code, w.Header().Get("Location"), w.Body.String())
+	}
+
+	var session *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "nexo_session" {
+			session = cookie
+		}
+	}
+	if session == nil {
+		t.Fatal("expected a session cookie")
+	}
+
+	w = do(app, http.MethodGet, "/me", []*http.Cookie{session})
+	var user User
+	if err := json.Unmarshal(w.Body.Bytes(), &user); err != nil {
+		t.Fatalf("expected the user, got %d %s", w.Code, w.Body.String())
+	}
+	if user.ID != "user-1" || user.Email != "ada@example.com" || user.Provider != "oidc" {
+		t.Errorf("unexpected user %+v", user)
+	}
+
+	// Logging out clears the session
+	w = do(app, http.MethodPost, "/auth/logout", []*http.Cookie{session})
+	cleared := w.Result().Cookies()
+	if w.Code != http.StatusSeeOther || len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("expected the session cookie to be cleared, got %d %v", w.Code, cleared)
+	}
+}
+
+func TestAuth_CallbackChecks(t *testing.T) {
+	p := newFakeProvider(t)
+	a, _ := New(Config{Provider: OIDC(p.URL), ClientID: "client"})
+	app := newTestApp(t, a)
+
+	tests := []struct {
+		name   string
+		setup  func() (string, []*http.Cookie)
+		status int
+	}{
+		{
+			name: "no login cookie",
+			setup: func() (string, []*http.Cookie) {
+				return "/auth/callback?code=good-code&state=x", nil
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "wrong state",
+			setup: func() (string, []*http.Cookie) {
+				cookies, _ := login(t, app, p, "/auth/login")
+				return "/auth/callback?code=good-code&state=forged", cookies
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "wrong PKCE verifier",
+			setup: func() (string, []*http.Cookie) {
+				cookies, location := login(t, app, p, "/auth/login")
+				p.challenge = "other"
+				return "/auth/callback?code=good-code&state=" + location.Query().Get("state"), cookies
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong nonce",
+			setup: func() (string, []*http.Cookie) {
+				cookies, location := login(t, app, p, "/auth/login")
+				p.nonce = "replayed"
+				return "/auth/callback?code=good-code&state=" + location.Query().Get("state"), cookies
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "provider error",
+			setup: func() (string, []*http.Cookie) {
+				return "/auth/callback?error=access_denied", nil
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, cookies := tt.setup()
+			w := do(app, http.MethodGet, target, cookies)
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuth_IDTokenClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]any
+	}{
+		{"wrong audience", map[string]any{"aud": "someone-else"}},
+		{"wrong issuer", map[string]any{"iss": "https://evil.example.com"}},
+		{"expired", map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newFakeProvider(t)
+			p.claims = tt.claims
+			a, _ := New(Config{Provider: OIDC(p.URL), ClientID: "client"})
+			app := newTestApp(t, a)
+
+			cookies, location := login(t, app, p, "/auth/login")
+			w := do(app, http.MethodGet, "/auth/callback?code=good-code&state="+location.Query().Get("state"), cookies)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestAuth_OnLogin(t *testing.T) {
+	p := newFakeProvider(t)
+	a, _ := New(Config{
+		Provider: OIDC(p.URL),
+		ClientID: "client",
+		OnLogin: func(c *nexo.Context, user *User) error {
+			if !strings.HasSuffix(user.Email, "@corp.example.com") {
+				return nexo.Forbidden("not a corp account")
+			}
+			return nil
+		},
+	})
+	app := newTestApp(t, a)
+
+	cookies, location := login(t, app, p, "/auth/login")
+	w := do(app, http.MethodGet, "/auth/callback?code=good-code&state="+location.Query().Get("state"), cookies)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected OnLogin to refuse the user, got %d", w.Code)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "nexo_session" {
+			t.Error("expected no session for a refused user")
+		}
+	}
+}
+
+func TestAuth_Require(t *testing.T) {
+	a, _ := New(Config{Provider: Google(), ClientID: "client"})
+	app := newTestApp(t, a)
+
+	req := httptest.NewRequest(http.MethodGet, "/me?tab=1", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/auth/login?return_to=%2Fme%3Ftab%3D1" {
+		t.Errorf("expected browsers to be sent to login, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	if w := do(app, http.MethodGet, "/me", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for API requests, got %d", w.Code)
+	}
+}
+
+func TestAuth_ReturnToMustBeLocal(t *testing.T) {
+	p := newFakeProvider(t)
+	a, _ := New(Config{Provider: OIDC(p.URL), ClientID: "client", AfterLogin: "/home"})
+	app := newTestApp(t, a)
+
+	cookies, location := login(t, app, p, "/auth/login?return_to=//evil.example.com")
+	w := do(app, http.MethodGet, "/auth/callback?code=good-code&state="+location.Query().Get("state"), cookies)
+	if w.Header().Get("Location") != "/home" {
+		t.Errorf("expected AfterLogin, got %q", w.Header().Get("Location"))
+	}
+}
+
+// rewriteTransport sends every request to a test server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestAuth_GitHub(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "gh-token"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 42, "login": "octocat", "avatar_url": "https://example.com/a.png"})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "old@example.com", "primary": false, "verified": true},
+			{"email": "octo@example.com", "primary": true, "verified": true},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	a, _ := New(Config{
+		Provider:   GitHub(),
+		ClientID:   "client",
+		HTTPClient: &http.Client{Transport: rewriteTransport{target}},
+	})
+	app := newTestApp(t, a)
+
+	w := do(app, http.MethodGet, "/auth/login", nil)
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if location.Host != "github.com" || location.Query().Get("nonce") != "" {
+		t.Errorf("expected a GitHub authorization URL without a nonce, got %s", location)
+	}
+
+	w = do(app, http.MethodGet, "/auth/callback?code=c&state="+location.Query().Get("state"), w.Result().Cookies())
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do(app, http.MethodGet, "/me", w.Result().Cookies())
+	var user User
+	_ = json.Unmarshal(w.Body.Bytes(), &user)
+	if user.ID != "42" || user.Name != "octocat" || user.Email != "octo@example.com" || !user.EmailVerified || user.Provider != "github" {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	if _, err := New(Config{Provider: Google()}); err == nil {
+		t.Error("expected an error without a client ID")
+	}
+	if _, err := New(Config{Provider: Provider{AuthURL: "https://example.com/authorize"}, ClientID: "client"}); err == nil {
+		t.Error("expected an error without a token URL")
+	}
+}
+
+func TestAuth_SessionsOnly(t *testing.T) {
+	a, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, a, func(app *nexo.App) {
+		app.Post("/password-login", func(c *nexo.Context) error {
+			return a.SetUser(c, &User{ID: "ada", Email: "ada@example.com", Provider: "password"})
+		})
+	})
+
+	if w := do(app, http.MethodGet, "/auth/login", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without a provider, got %d", w.Code)
+	}
+
+	w := do(app, http.MethodPost, "/password-login", nil)
+	w = do(app, http.MethodGet, "/me", w.Result().Cookies())
+	var user User
+	_ = json.Unmarshal(w.Body.Bytes(), &user)
+	if user.ID != "ada" || user.Provider != "password" {
+		t.Errorf("expected the password session's user, got %d %+v", w.Code, user)
+	}
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Provider describes an OAuth2 or OpenID Connect identity provider. Use a
+// preset such as Google, GitHub or OIDC, or fill in the endpoints.
+type Provider struct {
+	// Name identifies the provider in sessions, e.g. "google".
+	Name string
+
+	// Issuer is the OpenID Connect issuer URL. ID tokens are verified
+	// against it, and endpoints left empty are discovered from
+	// Issuer + "/.well-known/openid-configuration" on the first login.
+	Issuer string
+
+	// AuthURL is the authorization endpoint users are sent to.
+	AuthURL string
+
+	// TokenURL is the endpoint authorization codes are exchanged at.
+	TokenURL string
+
+	// UserInfoURL returns the signed-in user's claims. It is used when the
+	// token response has no ID token.
+	UserInfoURL string
+
+	// JWKSURL serves the keys ID tokens are signed with.
+	JWKSURL string
+
+	// Scopes are requested by default.
+	Scopes []string
+
+	// FetchUser loads the user for providers without OpenID Connect, such
+	// as GitHub. Default is the ID token's or UserInfoURL's claims.
+	FetchUser func(ctx context.Context, client *http.Client, accessToken string) (*User, error)
+}
+
+// Google returns a provider for signing in with Google accounts.
+func Google() Provider {
+	return Provider{
+		Name:        "google",
+		Issuer:      "https://accounts.google.com",
+		AuthURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:    "https://oauth2.googleapis.com/token",
+		UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+		JWKSURL:     "https://www.googleapis.com/oauth2/v3/certs",
+		Scopes:      []string{"openid", "email", "profile"},
+	}
+}
+
+// GitHub returns a provider for signing in with GitHub accounts. GitHub
+// doesn't support OpenID Connect, so the user is loaded from its API.
+func GitHub() Provider {
+	return Provider{
+		Name:      "github",
+		AuthURL:   "https://github.com/login/oauth/authorize",
+		TokenURL:  "https://github.com/login/oauth/access_token",
+		Scopes:    []string{"read:user", "user:email"},
+		FetchUser: fetchGitHubUser,
+	}
+}
+
+// OIDC returns a provider for any OpenID Connect issuer, such as Auth0,
+// Okta, Keycloak or Microsoft Entra ID. Its endpoints are discovered.
+//
+// Example:
+//
+//	auth.OIDC("https://example.eu.auth0.com/")
+func OIDC(issuer string) Provider {
+	return Provider{
+		Name:   "oidc",
+		Issuer: strings.TrimSuffix(issuer, "/"),
+		Scopes: []string{"openid", "email", "profile"},
+	}
+}
+
+// discovery is the part of an OpenID Connect discovery document that's used.
+type discovery struct {
+	Issuer      string `json:"issuer"`
+	AuthURL     string `json:"authorization_endpoint"`
+	TokenURL    string `json:"token_endpoint"`
+	UserInfoURL string `json:"userinfo_endpoint"`
+	JWKSURL     string `json:"jwks_uri"`
+}
+
+// discover fills in p's empty endpoints from its discovery document.
+func discover(ctx context.Context, client *http.Client, p *Provider) error {
+	var doc discovery
+	if err := getJSON(ctx, client, p.Issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return fmt.Errorf("failed to discover %s: %w", p.Issuer, err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.Issuer {
+		return fmt.Errorf("discovery document for %s has issuer %q", p.Issuer, doc.Issuer)
+	}
+	if p.AuthURL == "" {
+		p.AuthURL = doc.AuthURL
+	}
+	if p.TokenURL == "" {
+		p.TokenURL = doc.TokenURL
+	}
+	if p.UserInfoURL == "" {
+		p.UserInfoURL = doc.UserInfoURL
+	}
+	if p.JWKSURL == "" {
+		p.JWKSURL = doc.JWKSURL
+	}
+	return nil
+}
+
+// fetchGitHubUser loads the user from the GitHub API, with their primary
+// verified email if the profile's email is private.
+func fetchGitHubUser(ctx context.Context, client *http.Client, accessToken string) (*User, error) {
+	var profile struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user", accessToken, &profile); err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		ID:      strconv.FormatInt(profile.ID, 10),
+		Email:   profile.Email,
+		Name:    profile.Name,
+		Picture: profile.AvatarURL,
+	}
+	if user.Name == "" {
+		user.Name = profile.Login
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user/emails", accessToken, &emails); err == nil {
+		for _, e := range emails {
+			if e.Primary && e.Verified {
+				user.Email = e.Email
+				user.EmailVerified = true
+			}
+		}
+	}
+	return user, nil
+}
+
+// getJSON decodes the JSON response of a GET request, authorized with
+// accessToken if set.
+func getJSON(ctx context.Context, client *http.Client, url, accessToken string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// generateRouteFromJSON writes a route.go whose handlers bind, validate and
+// return a struct inferred from the JSON sample at cfg.FromJSON.
+func generateRouteFromJSON(cfg RouteConfig, filePath, pkgName, pattern string) error {
+	raw, err := os.ReadFile(cfg.FromJSON)
+	if err != nil {
+		return fmt.Errorf("failed to read JSON sample: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var sample any
+	if err := dec.Decode(&sample); err != nil {
+		return fmt.Errorf("failed to parse JSON sample %s: %w", cfg.FromJSON, err)
+	}
+
+	// A list sample describes the collection; its first element is the resource
+	isList := false
+	if list, ok := sample.([]any); ok {
+		if len(list) == 0 {
+			return fmt.Errorf("JSON sample %s is an empty array", cfg.FromJSON)
+		}
+		sample = list[0]
+		isList = true
+	}
+	if _, ok := sample.(map[string]any); !ok {
+		return fmt.Errorf("JSON sample %s must be an object or an array of objects", cfg.FromJSON)
+	}
+
+	types := newOpenAPITypes()
+	types.source = "JSON sample"
+	typeName := types.goType(jsonSampleSchema(sample).NewRef(), resourceTypeName(cfg.Path), true)
+
+	var params []openAPIParam
+	for _, p := range extractParams(cfg.Path) {
+		params = append(params, openAPIParam{Name: p.Name, Var: goVarName(p.Name)})
+	}
+
+	data := openAPIRouteData{Package: pkgName}
+	for _, method := range cfg.Methods {
+		op := openAPIOperation{
+			FuncName:   toTitleCase(method),
+			Method:     method,
+			Pattern:    "/api/" + pattern,
+			PathParams: params,
+			Status:     http.StatusOK,
+		}
+		switch method {
+		case "GET":
+			op.ResponseType = typeName
+			if isList && len(op.PathParams) == 0 {
+				op.ResponseType = "[]" + typeName
+			}
+		case "POST", "PUT", "PATCH":
+			op.RequestType = typeName
+			op.Validate = types.hasValidate(typeName)
+			op.ResponseType = typeName
+			if method == "POST" {
+				op.Status = http.StatusCreated
+			}
+		case "DELETE":
+			op.Status = http.StatusNoContent
+		}
+		data.Operations = append(data.Operations, op)
+	}
+	data.Types = types.decls
+	data.NeedsFmt = len(types.validated) > 0
+
+	tmpl, err := template.New("route.go").Parse(openAPIRouteTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, src, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// jsonSampleSchema infers a schema from a decoded JSON value. Object keys
+// with a non-null, non-empty value are required. Arrays take the type of
+// their first element.
+func jsonSampleSchema(v any) *openapi3.Schema {
+	switch v := v.(type) {
+	case map[string]any:
+		schema := openapi3.NewObjectSchema()
+		for key, val := range v {
+			schema.Properties[key] = jsonSampleSchema(val).NewRef()
+			if val != nil && val != "" {
+				schema.Required = append(schema.Required, key)
+			}
+		}
+		sort.Strings(schema.Required)
+		return schema
+	case []any:
+		schema := openapi3.NewArraySchema()
+		if len(v) > 0 {
+			schema.Items = jsonSampleSchema(v[0]).NewRef()
+		}
+		return schema
+	case string:
+		return openapi3.NewStringSchema()
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return openapi3.NewIntegerSchema()
+		}
+		return openapi3.NewFloat64Schema()
+	case bool:
+		return openapi3.NewBoolSchema()
+	}
+	return &openapi3.Schema{}
+}
+
+// resourceTypeName derives the struct name for a route from its last static
+// segment, singularized.
+// e.g., "orders" -> "Order", "users/[id]" -> "User", "categories" -> "Category"
+func resourceTypeName(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if seg == "" || seg == "api" || strings.HasPrefix(seg, "[") || strings.HasPrefix(seg, "(") {
+			continue
+		}
+		return goIdent(singularize(seg))
+	}
+	return "Resource"
+}
+
+// singularize applies simple English plural rules to a word.
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
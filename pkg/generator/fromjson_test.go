@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testJSONSample = `[{
+	"id": 42,
+	"total": 19.5,
+	"paid": true,
+	"note": "",
+	"coupon": null,
+	"customer": {"name": "Ada", "email": "ada@example.com"},
+	"items": [{"sku": "A1", "qty": 2}]
+}]`
+
+func TestGenerateRouteFromJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	samplePath := filepath.Join(tmpDir, "sample.json")
+	if err := os.WriteFile(samplePath, []byte(testJSONSample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	appDir := filepath.Join(tmpDir, "app")
+
+	result, err := GenerateRoute(RouteConfig{
+		Path:     "orders",
+		Methods:  []string{"GET", "POST", "DELETE"},
+		AppDir:   appDir,
+		FromJSON: samplePath,
+	})
+	if err != nil {
+		t.Fatalf("GenerateRoute failed: %v", err)
+	}
+	if result.Pattern != "/api/orders" {
+		t.Errorf("Pattern = %q, want /api/orders", result.Pattern)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), result.Files[0], nil, 0); err != nil {
+		t.Fatalf("generated route does not parse: %v", err)
+	}
+
+	content, _ := os.ReadFile(result.Files[0])
+	for _, s := range []string{
+		"package orders",
+		"type Order struct",
+		"ID       int              `json:\"id\"`",
+		"Total    float64          `json:\"total\"`",
+		"Paid     bool             `json:\"paid\"`",
+		"Note     string           `json:\"note,omitempty\"`",
+		"Coupon   any              `json:\"coupon,omitempty\"`",
+		"Customer OrderCustomer    `json:\"customer\"`",
+		"Items    []OrderItemsItem `json:\"items\"`",
+		"type OrderCustomer struct",
+		"// Order is generated from the JSON sample.",
+		"func (r Order) Validate() error",
+		`return fmt.Errorf("items is required")`,
+		"return c.JSON(200, []Order{})",
+		"var req Order",
+		"if err := req.Validate(); err != nil {",
+		"return c.JSON(201, req)",
+		"return c.NoContent()",
+	} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("Expected %q in route.go, got:\n%s", s, content)
+		}
+	}
+
+	// --from-json never appends to an existing route
+	if _, err := GenerateRoute(RouteConfig{Path: "orders", AppDir: appDir, Append: true, FromJSON: samplePath}); err == nil {
+		t.Error("Expected error when route.go already exists")
+	}
+}
+
+func TestGenerateRouteFromJSON_InvalidSample(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, sample := range map[string]string{
+		"scalar":  `42`,
+		"empty":   `[]`,
+		"invalid": `{"id":`,
+	} {
+		samplePath := filepath.Join(tmpDir, name+".json")
+		if err := os.WriteFile(samplePath, []byte(sample), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := GenerateRoute(RouteConfig{
+			Path:     name,
+			AppDir:   filepath.Join(tmpDir, "app"),
+			FromJSON: samplePath,
+		})
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestResourceTypeName(t *testing.T) {
+	tests := map[string]string{
+		"orders":           "Order",
+		"users/[id]":       "User",
+		"api/categories":   "Category",
+		"addresses":        "Address",
+		"boxes":            "Box",
+		"(admin)/settings": "Setting",
+		"status":           "Status",
+		"[id]":             "Resource",
+	}
+	for in, want := range tests {
+		if got := resourceTypeName(in); got != want {
+			t.Errorf("resourceTypeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -3,23 +3,33 @@ package generator
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 )
 
 // RouteConfig holds configuration for route generation.
 type RouteConfig struct {
-	Path    string   // Route path (e.g., "users/[id]")
-	Methods []string // HTTP methods (e.g., ["GET", "PUT", "DELETE"])
-	AppDir  string   // App directory (default: "app")
+	Path     string   // Route path (e.g., "users/[id]")
+	Methods  []string // HTTP methods (e.g., ["GET", "PUT", "DELETE"])
+	AppDir   string   // App directory (default: "app")
+	Append   bool     // Add missing handlers to an existing route.go instead of failing
+	FromJSON string   // Path to a JSON sample to infer the request/response struct from
 }
 
 // MiddlewareConfig holds configuration for middleware generation.
@@ -51,14 +61,17 @@ type Result struct {
 
 // Regular expressions for parsing route paths using Next.js-style naming:
 //   - [param]      -> dynamic segment
+//   - [param:int]  -> dynamic segment with a constraint (see nexo.RouteParam)
 //   - [...param]   -> catch-all segment
 //   - [[...param]] -> optional catch-all segment
 //   - (group)      -> route group (doesn't affect URL)
+//   - @slot        -> parallel route slot (doesn't affect URL)
 var (
-	dynamicSegmentRe   = regexp.MustCompile(`^\[([a-zA-Z_][a-zA-Z0-9_]*)\]$`)
+	dynamicSegmentRe   = regexp.MustCompile(`^\[([a-zA-Z_][a-zA-Z0-9_]*)(?::(.+))?\]$`)
 	catchAllSegmentRe  = regexp.MustCompile(`^\[\.\.\.([a-zA-Z_][a-zA-Z0-9_]*)\]$`)
 	optionalCatchAllRe = regexp.MustCompile(`^\[\[\.\.\.([a-zA-Z_][a-zA-Z0-9_]*)\]\]$`)
 	routeGroupRe       = regexp.MustCompile(`^\(([a-zA-Z_][a-zA-Z0-9_]*)\)$`)
+	slotSegmentRe      = regexp.MustCompile(`^@([a-zA-Z_][a-zA-Z0-9_]*)$`)
 )
 
 // knownPrivateFolders contains folder prefixes that are private (not routable)
@@ -104,6 +117,9 @@ func GenerateRoute(cfg RouteConfig) (*Result, error) {
 	if len(cfg.Methods) == 0 {
 		cfg.Methods = []string{"GET"}
 	}
+	if err := checkConstraints(cfg.Path); err != nil {
+		return nil, err
+	}
 
 	// Normalize methods to uppercase
 	for i, m := range cfg.Methods {
@@ -124,11 +140,6 @@ func GenerateRoute(cfg RouteConfig) (*Result, error) {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); err == nil {
-		return nil, fmt.Errorf("file already exists: %s", filePath)
-	}
-
 	// Generate package name from last segment (cleaned)
 	pkgName := packageNameFromPath(cfg.Path)
 
@@ -147,6 +158,34 @@ func GenerateRoute(cfg RouteConfig) (*Result, error) {
 		}
 	}
 
+	// Check if file exists
+	if _, err := os.Stat(filePath); err == nil {
+		if !cfg.Append || cfg.FromJSON != "" {
+			return nil, fmt.Errorf("file already exists: %s", filePath)
+		}
+		if err := appendRouteHandlers(filePath, routeTemplateData{
+			Methods: methods,
+			Params:  params,
+			Pattern: pattern,
+		}); err != nil {
+			return nil, err
+		}
+		return &Result{
+			Files:   []string{filePath},
+			Pattern: "/api/" + pattern,
+		}, nil
+	}
+
+	if cfg.FromJSON != "" {
+		if err := generateRouteFromJSON(cfg, filePath, pkgName, pattern); err != nil {
+			return nil, err
+		}
+		return &Result{
+			Files:   []string{filePath},
+			Pattern: "/api/" + pattern,
+		}, nil
+	}
+
 	// Generate code
 	data := routeTemplateData{
 		Package: pkgName,
@@ -165,6 +204,79 @@ func GenerateRoute(cfg RouteConfig) (*Result, error) {
 	}, nil
 }
 
+// nexoImportPath is the import path generated files use for the nexo package.
+const nexoImportPath = "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+// appendRouteHandlers adds handlers for the methods in data that are not yet
+// declared in the existing route file. The file is parsed to find existing
+// handlers and imports, the missing handlers are rendered and appended, and
+// the result is re-parsed and gofmt'ed before being written back.
+func appendRouteHandlers(filePath string, data routeTemplateData) error {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	existing := make(map[string]bool)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+			existing[fn.Name.Name] = true
+		}
+	}
+
+	var missing []methodInfo
+	for _, m := range data.Methods {
+		if !existing[m.FuncName] {
+			missing = append(missing, m)
+		}
+	}
+	if len(missing) == 0 {
+		return fmt.Errorf("all requested handlers already exist in %s", filePath)
+	}
+	data.Methods = missing
+
+	tmpl, err := template.New(filepath.Base(filePath)).Parse(routeHandlersTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	var handlers bytes.Buffer
+	if err := tmpl.Execute(&handlers, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	// Make sure the handlers can refer to nexo.Context
+	var out bytes.Buffer
+	hasImport := false
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) == nexoImportPath {
+			hasImport = imp.Name == nil || imp.Name.Name == "nexo"
+		}
+	}
+	if hasImport {
+		out.Write(src)
+	} else {
+		offset := fset.Position(file.Name.End()).Offset
+		out.Write(src[:offset])
+		fmt.Fprintf(&out, "\n\nimport %q\n", nexoImportPath)
+		out.Write(src[offset:])
+	}
+	out.WriteString("\n")
+	out.Write(handlers.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", filePath, err)
+	}
+
+	return os.WriteFile(filePath, formatted, 0644)
+}
+
 // GenerateMiddleware generates a middleware file.
 func GenerateMiddleware(cfg MiddlewareConfig) (*Result, error) {
 	if cfg.AppDir == "" {
@@ -261,6 +373,9 @@ func GeneratePage(cfg PageConfig) (*Result, error) {
 	if cfg.AppDir == "" {
 		cfg.AppDir = "app"
 	}
+	if err := checkConstraints(cfg.Path); err != nil {
+		return nil, err
+	}
 
 	// Determine directory path
 	var dirPath string
@@ -336,7 +451,27 @@ type LoaderConfig struct {
 	AppDir   string // App directory (default: "app")
 }
 
-// GenerateLoader generates a loader.go file.
+// loaderField is a field of the generated loader data struct, populated from a URL parameter.
+type loaderField struct {
+	Name       string // Go field name (e.g., "ID")
+	Param      string // URL parameter name (e.g., "id")
+	IsCatchAll bool   // True for [...param] and [[...param]] segments
+}
+
+// loaderTemplateData holds the data for the loader and loader page templates.
+type loaderTemplateData struct {
+	Package  string
+	DataType string
+	Title    string
+	Fields   []loaderField
+	FilePath string
+}
+
+// GenerateLoader generates a loader.go file with a typed data struct.
+// Dynamic segments in the path become fields of the data struct, and the
+// generated Loader reads them from the request. If no page.templ exists in
+// the directory, one is created that accepts the data type so the loader is
+// wired to the page convention.
 func GenerateLoader(cfg LoaderConfig) (*Result, error) {
 	if cfg.AppDir == "" {
 		cfg.AppDir = "app"
@@ -350,6 +485,7 @@ func GenerateLoader(cfg LoaderConfig) (*Result, error) {
 		dirPath = cfg.AppDir
 	}
 	loaderFilePath := filepath.Join(dirPath, "loader.go")
+	pageFilePath := filepath.Join(dirPath, "page.templ")
 
 	// Create directory
 	if err := os.MkdirAll(dirPath, 0755); err != nil {
@@ -370,32 +506,490 @@ func GenerateLoader(cfg LoaderConfig) (*Result, error) {
 	// Generate data type name
 	dataType := cfg.DataType
 	if dataType == "" {
-		// Derive from directory name
-		baseName := filepath.Base(cfg.Path)
-		if baseName == "" || baseName == "." {
-			baseName = "Page"
-		}
-		// Convert to PascalCase and add Data suffix
-		dataType = toTitle(strings.ReplaceAll(baseName, "-", " "))
-		dataType = strings.ReplaceAll(dataType, " ", "") + "Data"
+		dataType = loaderDataTypeFromPath(cfg.Path)
 	}
 
-	// Generate loader
-	data := struct {
-		Package  string
-		DataType string
-	}{
+	// Derive struct fields from the dynamic segments of the path
+	var fields []loaderField
+	for _, p := range extractParams(cfg.Path) {
+		fields = append(fields, loaderField{
+			Name:       toGoFieldName(p.Name),
+			Param:      p.Name,
+			IsCatchAll: p.IsCatchAll,
+		})
+	}
+
+	data := loaderTemplateData{
 		Package:  pkgName,
 		DataType: dataType,
+		Title:    deriveTitle(dirPath, cfg.AppDir),
+		Fields:   fields,
+		FilePath: pageFilePath,
 	}
 
 	if err := executeTemplate(loaderFilePath, loaderTemplate, data); err != nil {
 		return nil, err
 	}
+	files := []string{loaderFilePath}
+
+	// Create a page that receives the loader data if none exists yet
+	if _, err := os.Stat(pageFilePath); os.IsNotExist(err) {
+		if err := executeTemplate(pageFilePath, loaderPageTemplate, data); err != nil {
+			return nil, err
+		}
+		files = append(files, pageFilePath)
+	}
 
 	return &Result{
-		Files:   []string{loaderFilePath},
-		Pattern: "/" + cfg.Path,
+		Files:   files,
+		Pattern: "/" + pathToPattern(cfg.Path),
+	}, nil
+}
+
+// loaderDataTypeFromPath derives a data type name from the last static segment of a path.
+// e.g., "dashboard" -> "DashboardData", "users/[id]" -> "UsersData", "" -> "PageData"
+func loaderDataTypeFromPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if seg == "" || dynamicSegmentRe.MatchString(seg) || catchAllSegmentRe.MatchString(seg) || optionalCatchAllRe.MatchString(seg) {
+			continue
+		}
+		if matches := routeGroupRe.FindStringSubmatch(seg); len(matches) > 1 {
+			seg = matches[1]
+		}
+		name := toTitle(strings.NewReplacer("-", " ", "_", " ").Replace(seg))
+		return strings.ReplaceAll(name, " ", "") + "Data"
+	}
+	return "PageData"
+}
+
+// toGoFieldName converts a URL parameter name to an exported Go field name.
+// e.g., "id" -> "ID", "userId" -> "UserID", "slug" -> "Slug"
+func toGoFieldName(param string) string {
+	if param == "" {
+		return ""
+	}
+	if strings.EqualFold(param, "id") {
+		return "ID"
+	}
+	name := strings.ToUpper(param[:1]) + param[1:]
+	if strings.HasSuffix(name, "Id") {
+		name = strings.TrimSuffix(name, "Id") + "ID"
+	}
+	return name
+}
+
+// MigrationConfig holds configuration for migration generation.
+type MigrationConfig struct {
+	Name string // Migration name (e.g., "add_users_table")
+	Dir  string // Migrations directory (default: "migrations")
+}
+
+// migrationNameRe matches valid migration names (lowercase snake_case).
+var migrationNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// migrationTimeFormat is the version prefix of migration files (e.g., 20240115093000).
+const migrationTimeFormat = "20060102150405"
+
+// migrationNow returns the migration timestamp; replaced in tests.
+var migrationNow = time.Now
+
+// GenerateMigration generates a pair of up/down SQL files in the migrations
+// directory, named <timestamp>_<name>.up.sql and <timestamp>_<name>.down.sql
+// following the golang-migrate convention.
+func GenerateMigration(cfg MigrationConfig) (*Result, error) {
+	if cfg.Dir == "" {
+		cfg.Dir = "migrations"
+	}
+
+	name := strings.ToLower(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(cfg.Name)))
+	if !migrationNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q: use letters, digits and underscores (e.g., add_users_table)", cfg.Name)
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Refuse to create a second migration with the same name
+	existing, err := filepath.Glob(filepath.Join(cfg.Dir, "*_"+name+".up.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("migration already exists: %s", existing[0])
+	}
+
+	createdAt := migrationNow().UTC()
+	base := filepath.Join(cfg.Dir, createdAt.Format(migrationTimeFormat)+"_"+name)
+	data := map[string]string{
+		"Name":      name,
+		"CreatedAt": createdAt.Format(time.RFC3339),
+	}
+
+	upPath := base + ".up.sql"
+	downPath := base + ".down.sql"
+	if err := executeTemplate(upPath, migrationUpTemplate, data); err != nil {
+		return nil, err
+	}
+	if err := executeTemplate(downPath, migrationDownTemplate, data); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Files: []string{upPath, downPath},
+	}, nil
+}
+
+// SeedConfig holds configuration for seed generation.
+type SeedConfig struct {
+	Name       string   // Seed name (e.g., "users")
+	Envs       []string // Environments the seed runs in (default: ["development"])
+	Root       string   // Project root (default: ".")
+	ModuleName string   // Go module name (default: read from go.mod)
+}
+
+// seedTemplateData holds the data for the seed templates.
+type seedTemplateData struct {
+	Name       string
+	FuncName   string
+	Envs       []string
+	ModuleName string
+}
+
+// GenerateSeed generates seeds/<name>.go registering an idempotent seed
+// function. The seeds package registry and the cmd/seed runner used by
+// 'nexo db seed' are created the first time a seed is generated.
+func GenerateSeed(cfg SeedConfig) (*Result, error) {
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+	if len(cfg.Envs) == 0 {
+		cfg.Envs = []string{"development"}
+	}
+
+	name := strings.ToLower(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(cfg.Name)))
+	if !migrationNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid seed name %q: use letters, digits and underscores (e.g., users)", cfg.Name)
+	}
+
+	seedsDir := filepath.Join(cfg.Root, "seeds")
+	seedPath := filepath.Join(seedsDir, name+".go")
+	if _, err := os.Stat(seedPath); err == nil {
+		return nil, fmt.Errorf("file already exists: %s", seedPath)
+	}
+
+	data := seedTemplateData{
+		Name:       name,
+		FuncName:   "seed" + strings.ReplaceAll(toTitle(strings.ReplaceAll(name, "_", " ")), " ", ""),
+		Envs:       cfg.Envs,
+		ModuleName: cfg.ModuleName,
+	}
+
+	var files []string
+
+	// Create the registry and runner on first use
+	registryPath := filepath.Join(seedsDir, "seeds.go")
+	mainDir := filepath.Join(cfg.Root, "cmd", "seed")
+	mainPath := filepath.Join(mainDir, "main.go")
+
+	if _, err := os.Stat(mainPath); os.IsNotExist(err) && data.ModuleName == "" {
+		moduleName, err := getModuleName()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get module name: %w", err)
+		}
+		data.ModuleName = moduleName
+	}
+
+	if err := os.MkdirAll(seedsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		if err := executeTemplate(registryPath, seedRegistryTemplate, data); err != nil {
+			return nil, err
+		}
+		files = append(files, registryPath)
+	}
+	if _, err := os.Stat(mainPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(mainDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := executeTemplate(mainPath, seedMainTemplate, data); err != nil {
+			return nil, err
+		}
+		files = append(files, mainPath)
+	}
+
+	if err := executeTemplate(seedPath, seedTemplate, data); err != nil {
+		return nil, err
+	}
+	files = append(files, seedPath)
+
+	return &Result{
+		Files: files,
+	}, nil
+}
+
+// FormConfig holds configuration for form generation.
+type FormConfig struct {
+	Path   string   // Page path (e.g., "contact")
+	Fields []string // Form fields, optionally typed (e.g., ["name", "email", "message:textarea"])
+	AppDir string   // App directory (default: "app")
+}
+
+// formField is a field of a generated form.
+type formField struct {
+	Name      string // Form field name (e.g., "email")
+	GoName    string // Go struct field name (e.g., "Email")
+	Label     string // Human-readable label (e.g., "Email")
+	InputType string // HTML input type, or "textarea"
+}
+
+// formInputTypes lists the supported field types for "name:type" fields.
+var formInputTypes = map[string]bool{
+	"text": true, "email": true, "textarea": true, "password": true,
+	"number": true, "tel": true, "url": true, "date": true,
+}
+
+// GenerateForm generates a form page following the Post/Redirect/Get pattern:
+// a page.templ rendering the form, a form.go with the form struct and its
+// validation, a POST route that re-renders the form with errors, and a
+// loader that shows a flash message after a successful submission. The form
+// submits with HTMX when available and falls back to a regular POST.
+func GenerateForm(cfg FormConfig) (*Result, error) {
+	if cfg.AppDir == "" {
+		cfg.AppDir = "app"
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("form path is required")
+	}
+	if len(extractParams(cfg.Path)) > 0 {
+		return nil, fmt.Errorf("form paths cannot contain dynamic segments: %s", cfg.Path)
+	}
+	if len(cfg.Fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+
+	var fields []formField
+	hasEmail := false
+	for _, f := range cfg.Fields {
+		field, err := parseFormField(f)
+		if err != nil {
+			return nil, err
+		}
+		if field.InputType == "email" {
+			hasEmail = true
+		}
+		fields = append(fields, field)
+	}
+
+	dirPath := filepath.Join(cfg.AppDir, cfg.Path)
+	outputs := []struct {
+		path string
+		tmpl string
+	}{
+		{filepath.Join(dirPath, "form.go"), formTemplate},
+		{filepath.Join(dirPath, "loader.go"), formLoaderTemplate},
+		{filepath.Join(dirPath, "route.go"), formRouteTemplate},
+		{filepath.Join(dirPath, "page.templ"), formPageTemplate},
+	}
+	for _, o := range outputs {
+		if _, err := os.Stat(o.path); err == nil {
+			return nil, fmt.Errorf("file already exists: %s", o.path)
+		}
+	}
+
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	pkgName := packageNameFromPath(cfg.Path)
+	dataType := loaderDataTypeFromPath(cfg.Path)
+	pattern := "/" + pathToPattern(cfg.Path)
+	data := struct {
+		Package     string
+		FormType    string
+		DataType    string
+		Title       string
+		Pattern     string
+		FormID      string
+		FlashCookie string
+		Fields      []formField
+		HasEmail    bool
+	}{
+		Package:     pkgName,
+		FormType:    strings.TrimSuffix(dataType, "Data") + "Form",
+		DataType:    dataType,
+		Title:       deriveTitle(dirPath, cfg.AppDir),
+		Pattern:     pattern,
+		FormID:      pkgName + "-form",
+		FlashCookie: pkgName + "_flash",
+		Fields:      fields,
+		HasEmail:    hasEmail,
+	}
+
+	var files []string
+	for _, o := range outputs {
+		if err := executeTemplate(o.path, o.tmpl, data); err != nil {
+			return nil, err
+		}
+		if filepath.Ext(o.path) == ".go" {
+			if err := formatGoFile(o.path); err != nil {
+				return nil, err
+			}
+		}
+		files = append(files, o.path)
+	}
+
+	return &Result{
+		Files:   files,
+		Pattern: pattern,
+	}, nil
+}
+
+// parseFormField parses a "name" or "name:type" field definition. Without an
+// explicit type, the input type is inferred from the name.
+func parseFormField(def string) (formField, error) {
+	name, inputType, _ := strings.Cut(strings.TrimSpace(def), ":")
+	name = strings.ToLower(name)
+	if !migrationNameRe.MatchString(name) {
+		return formField{}, fmt.Errorf("invalid field name %q: use letters, digits and underscores", def)
+	}
+
+	if inputType == "" {
+		switch name {
+		case "email":
+			inputType = "email"
+		case "message", "body", "content", "description", "comment", "notes":
+			inputType = "textarea"
+		case "password":
+			inputType = "password"
+		case "phone", "tel":
+			inputType = "tel"
+		case "url", "website":
+			inputType = "url"
+		default:
+			inputType = "text"
+		}
+	}
+	if !formInputTypes[inputType] {
+		return formField{}, fmt.Errorf("unknown field type %q for %s", inputType, name)
+	}
+
+	label := toTitle(strings.ReplaceAll(name, "_", " "))
+	goName := strings.ReplaceAll(label, " ", "")
+	if strings.HasSuffix(goName, "Id") {
+		goName = strings.TrimSuffix(goName, "Id") + "ID"
+	}
+
+	return formField{
+		Name:      name,
+		GoName:    goName,
+		Label:     label,
+		InputType: inputType,
+	}, nil
+}
+
+// E2EConfig holds configuration for end-to-end test generation.
+type E2EConfig struct {
+	Root    string // Project root (default: ".")
+	AppDir  string // App directory (default: "app")
+	APIPath string // API endpoint used by the example test (default: "/api/health")
+}
+
+// GenerateE2E generates an end-to-end test harness in the project root.
+// The tests are guarded by the e2e build tag, start the app on a random port
+// with httptest and include an example API assertion, plus a page assertion
+// when the app has a root page.templ.
+func GenerateE2E(cfg E2EConfig) (*Result, error) {
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+	if cfg.AppDir == "" {
+		cfg.AppDir = "app"
+	}
+	if cfg.APIPath == "" {
+		cfg.APIPath = "/api/health"
+	}
+	if !strings.HasPrefix(cfg.APIPath, "/") {
+		cfg.APIPath = "/" + cfg.APIPath
+	}
+
+	setupPath := filepath.Join(cfg.Root, "e2e_setup_test.go")
+	testPath := filepath.Join(cfg.Root, "e2e_test.go")
+	for _, p := range []string{setupPath, testPath} {
+		if _, err := os.Stat(p); err == nil {
+			return nil, fmt.Errorf("file already exists: %s", p)
+		}
+	}
+
+	_, err := os.Stat(filepath.Join(cfg.Root, cfg.AppDir, "page.templ"))
+	data := struct {
+		APIPath  string
+		WithPage bool
+	}{
+		APIPath:  cfg.APIPath,
+		WithPage: err == nil,
+	}
+
+	if err := executeTemplate(setupPath, e2eSetupTemplate, data); err != nil {
+		return nil, err
+	}
+	if err := executeTemplate(testPath, e2eTestTemplate, data); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Files: []string{setupPath, testPath},
+	}, nil
+}
+
+// StaticEmbedConfig holds configuration for the embedded static files shim.
+type StaticEmbedConfig struct {
+	Root string // Project root (default: ".")
+	Dir  string // Static directory, relative to Root (default: "static")
+}
+
+// StaticEmbedTag is the build tag that enables the generated static embed shim.
+const StaticEmbedTag = "nexo_embed_static"
+
+// GenerateStaticEmbed writes nexo_static.go to the project root. Built with
+// the StaticEmbedTag build tag, it embeds the static directory into the
+// binary and registers it with nexo.EmbedStatic, so app.Static serves the
+// files from memory. Without the tag the file is ignored and static files
+// are read from disk as usual.
+func GenerateStaticEmbed(cfg StaticEmbedConfig) (*Result, error) {
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = "static"
+	}
+
+	dir := filepath.ToSlash(filepath.Clean(cfg.Dir))
+	if filepath.IsAbs(cfg.Dir) || dir == "." || strings.HasPrefix(dir, "../") || dir == ".." {
+		return nil, fmt.Errorf("static directory must be inside the project: %s", cfg.Dir)
+	}
+	info, err := os.Stat(filepath.Join(cfg.Root, dir))
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("static directory not found: %s", cfg.Dir)
+	}
+
+	filePath := filepath.Join(cfg.Root, "nexo_static.go")
+	data := struct {
+		Tag string
+		Dir string
+	}{
+		Tag: StaticEmbedTag,
+		Dir: dir,
+	}
+	if err := executeRouteTemplate(filePath, staticEmbedTemplate, data); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Files: []string{filePath},
 	}, nil
 }
 
@@ -431,6 +1025,29 @@ func packageNameFromPath(path string) string {
 	return cleanPackageName(lastSeg)
 }
 
+// routeParam returns the route pattern segment for a dynamicSegmentRe
+// match. Invalid constraints are reported by checkConstraints.
+func routeParam(matches []string) string {
+	param, err := nexo.RouteParam(matches[1], matches[2])
+	if err != nil {
+		return "{" + matches[1] + "}"
+	}
+	return param
+}
+
+// checkConstraints returns an error if a dynamic segment in path has an
+// unknown or invalid constraint.
+func checkConstraints(path string) error {
+	for _, seg := range strings.Split(filepath.ToSlash(path), "/") {
+		if matches := dynamicSegmentRe.FindStringSubmatch(seg); len(matches) > 1 {
+			if _, err := nexo.RouteParam(matches[1], matches[2]); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
 func cleanPackageName(name string) string {
 	// Remove non-alphanumeric chars except underscore
 	re := regexp.MustCompile(`[^a-zA-Z0-9_]`)
@@ -446,7 +1063,12 @@ func cleanPackageName(name string) string {
 		return "route"
 	}
 
-	return strings.ToLower(name)
+	name = strings.ToLower(name)
+	// Keywords can't name packages, e.g. [type] -> pkgtype
+	if token.IsKeyword(name) {
+		name = "pkg" + name
+	}
+	return name
 }
 
 func extractParams(path string) []ParamInfo {
@@ -499,8 +1121,8 @@ func pathToPattern(path string) string {
 	var result []string
 
 	for _, seg := range segments {
-		// Skip route groups (group)
-		if routeGroupRe.MatchString(seg) {
+		// Skip route groups (group) and slots @slot
+		if routeGroupRe.MatchString(seg) || slotSegmentRe.MatchString(seg) {
 			continue
 		}
 
@@ -518,7 +1140,7 @@ func pathToPattern(path string) string {
 
 		// Handle dynamic segment [param]
 		if matches := dynamicSegmentRe.FindStringSubmatch(seg); len(matches) > 1 {
-			result = append(result, "{"+matches[1]+"}")
+			result = append(result, routeParam(matches))
 			continue
 		}
 
@@ -534,6 +1156,17 @@ func pathToPattern(path string) string {
 
 // routeTemplateFuncs contains custom template functions for route generation.
 var routeTemplateFuncs = template.FuncMap{
+	"layoutPage": func(page PageRegistration, comp string) string {
+		for i := len(page.GroupLayouts) - 1; i >= 0; i-- {
+			l := page.GroupLayouts[i]
+			args := ""
+			if l.TakesTitle {
+				args = strconv.Quote(page.Title)
+			}
+			comp = fmt.Sprintf("nexo.WithLayout(%s.Layout(%s), %s)", l.ImportAlias, args, comp)
+		}
+		return comp
+	},
 	"paramArgs": func(params []PageParam) string {
 		var args []string
 		for _, p := range params {
@@ -559,6 +1192,8 @@ func zeroValue(typeName string) string {
 		return "0"
 	case "bool":
 		return "false"
+	case "any":
+		return "nil"
 	default:
 		// For structs and other types, use the type name with empty braces
 		// e.g., "User" -> "User{}"
@@ -588,6 +1223,19 @@ func executeTemplate(filePath, tmplContent string, data any) error {
 	return nil
 }
 
+// formatGoFile runs gofmt on a generated Go file.
+func formatGoFile(filePath string) error {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", filePath, err)
+	}
+	return os.WriteFile(filePath, formatted, 0644)
+}
+
 // executeRouteTemplate executes a template with route-specific functions.
 func executeRouteTemplate(filePath, tmplContent string, data any) error {
 	tmpl, err := template.New(filepath.Base(filePath)).Funcs(routeTemplateFuncs).Parse(tmplContent)
@@ -595,14 +1243,18 @@ func executeRouteTemplate(filePath, tmplContent string, data any) error {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
 	}
-	defer func() { _ = f.Close() }()
 
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	// Leave an unchanged file untouched so file watchers don't see a write
+	if existing, err := os.ReadFile(filePath); err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return nil
+	}
+
+	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
 	}
 
 	return nil
@@ -639,6 +1291,59 @@ type RouteRegistration struct {
 	Pattern     string // Route pattern (/api/users/{id})
 	Handler     string // Handler function name (Get, Post, etc.)
 	FilePath    string // Source file path (for comments)
+	Scope       string // Route group scope (e.g., "(marketing)/about"), empty outside groups
+}
+
+// RouteConfigRegistration holds information for a route.go that exports a
+// Config (nexo.RouteConfig).
+type RouteConfigRegistration struct {
+	ImportPath  string // Full import path
+	ImportAlias string // Alias for the import
+	Package     string // Package name
+	Pattern     string // Route pattern the config applies to
+	FilePath    string // Source file path
+}
+
+// SocketRegistration holds information for a ws.go whose Socket function
+// serves WebSocket connections.
+type SocketRegistration struct {
+	ImportPath  string // Full import path
+	ImportAlias string // Alias for the import
+	Package     string // Package name
+	Pattern     string // Route pattern (/chat/{room})
+	FilePath    string // Source file path (ws.go)
+	HasConfig   bool   // Whether SocketConfig (nexo.WebSocketConfig) is defined
+	Scope       string // Route group scope (e.g., "(marketing)/chat"), empty outside groups
+}
+
+// StreamRegistration holds information for an sse.go whose Stream
+// function streams Server-Sent Events.
+type StreamRegistration struct {
+	ImportPath  string // Full import path
+	ImportAlias string // Alias for the import
+	Package     string // Package name
+	Pattern     string // Route pattern (/events/{topic})
+	FilePath    string // Source file path (sse.go)
+	HasConfig   bool   // Whether StreamConfig (nexo.SSEConfig) is defined
+	Scope       string // Route group scope (e.g., "(marketing)/events"), empty outside groups
+}
+
+// ActionRegistration holds information for an action.go whose Action
+// functions handle the forms of the page next to it.
+type ActionRegistration struct {
+	ImportPath  string          // Full import path
+	ImportAlias string          // Alias for the import
+	Package     string          // Package name
+	Pattern     string          // Route pattern of the page (/contact)
+	FilePath    string          // Source file path (action.go)
+	Funcs       []ActionFuncRef // The actions, the default Action first
+	Scope       string          // Route group scope (e.g., "(marketing)/contact"), empty outside groups
+}
+
+// ActionFuncRef is one action of an action.go.
+type ActionFuncRef struct {
+	Name string // Name forms select it by, e.g. "delete"; "" for Action
+	Func string // Function name (Action, ActionDelete, ...)
 }
 
 // MiddlewareRegistration holds information for middleware registration.
@@ -648,6 +1353,8 @@ type MiddlewareRegistration struct {
 	Package     string // Package name
 	PathPrefix  string // Path prefix the middleware applies to
 	FilePath    string // Source file path
+	HasConfig   bool   // Whether Config (nexo.MiddlewareOptions) is defined
+	Scope       string // Route group scope (e.g., "(marketing)"), empty outside groups
 }
 
 // ProxyRegistration holds information for proxy registration.
@@ -685,28 +1392,87 @@ type PageRegistration struct {
 	HasLoader        bool   // True if a loader.go exists in the same directory
 	LoaderImportPath string // Import path for the loader
 	LoaderPackage    string // Package name for the loader
+	HasLoaderConfig  bool   // True if the loader.go defines LoaderConfig (nexo.LoaderConfig)
+	HasLoading       bool   // True if a loading.templ covers the page while its loader runs
+
+	// Route group support
+	Scope        string           // Route group scope (e.g., "(marketing)/about"), empty outside groups
+	GroupLayouts []GroupLayoutRef // Layouts of the route groups the page is in, outermost first
+}
+
+// GroupLayoutRef refers to the layout.templ of a route group directory,
+// which wraps the pages below it.
+type GroupLayoutRef struct {
+	ImportPath  string // Full import path for the layout's package
+	ImportAlias string // Alias for the import
+	Package     string // Package name
+	TakesTitle  bool   // True for Layout(title string), false for Layout()
 }
 
 // LayoutRegistration holds information for layout registration.
 type LayoutRegistration struct {
-	ImportPath  string // Full import path for the generated _templ.go package
+	ImportPath  string      // Full import path for the generated _templ.go package
+	ImportAlias string      // Alias for the import
+	Package     string      // Package name
+	PathPrefix  string      // Path prefix this layout applies to
+	FilePath    string      // Source file path (layout.templ)
+	Params      []PageParam // Parameters of the templ Layout() signature
+}
+
+// SlotRegistration holds information for a parallel route slot registration:
+// a page.templ under an @slot directory, rendered by the parent layout with
+// nexo.Slot instead of registered as a route.
+type SlotRegistration struct {
+	PageRegistration
+	Name string // Slot name (e.g., "analytics" for @analytics)
+}
+
+// ErrorRegistration holds information for an error boundary registration.
+type ErrorRegistration struct {
+	ImportPath  string // Full import path for the package defining Error()
+	ImportAlias string // Alias for the import
+	Package     string // Package name
+	PathPrefix  string // Path prefix this error boundary applies to
+	FilePath    string // Source file path (error.templ or error.go)
+}
+
+// LoadingRegistration holds information for a loading component registration.
+type LoadingRegistration struct {
+	ImportPath  string // Full import path for the package defining Loading()
+	ImportAlias string // Alias for the import
+	Package     string // Package name
+	PathPrefix  string // Path prefix this loading component applies to
+	FilePath    string // Source file path (loading.templ)
+}
+
+// NotFoundRegistration holds information for a 404 page registration.
+type NotFoundRegistration struct {
+	ImportPath  string // Full import path for the package defining NotFound()
 	ImportAlias string // Alias for the import
 	Package     string // Package name
-	PathPrefix  string // Path prefix this layout applies to
-	FilePath    string // Source file path (layout.templ)
+	PathPrefix  string // Path prefix this 404 page applies to
+	FilePath    string // Source file path (not-found.templ)
 }
 
 // RoutesGenConfig holds configuration for generating the routes file.
 type RoutesGenConfig struct {
-	ModuleName  string                   // Go module name (from go.mod)
-	AppDir      string                   // App directory (default: "app")
-	OutputPath  string                   // Output file path (default: "nexo_routes.go")
-	Routes      []RouteRegistration      // Discovered routes
-	Middlewares []MiddlewareRegistration // Discovered middlewares
-	Proxy       *ProxyRegistration       // Discovered proxy (optional)
-	Pages       []PageRegistration       // Discovered pages
-	Layouts     []LayoutRegistration     // Discovered layouts
-	Loaders     []LoaderRegistration     // Discovered data loaders
+	ModuleName   string                    // Go module name (from go.mod)
+	AppDir       string                    // App directory (default: "app")
+	OutputPath   string                    // Output file path (default: "nexo_routes.go")
+	Routes       []RouteRegistration       // Discovered routes
+	RouteConfigs []RouteConfigRegistration // Discovered route configs
+	Sockets      []SocketRegistration      // Discovered WebSocket routes
+	Streams      []StreamRegistration      // Discovered Server-Sent Events routes
+	Actions      []ActionRegistration      // Discovered form actions
+	Middlewares  []MiddlewareRegistration  // Discovered middlewares
+	Proxy        *ProxyRegistration        // Discovered proxy (optional)
+	Pages        []PageRegistration        // Discovered pages
+	Layouts      []LayoutRegistration      // Discovered layouts
+	Slots        []SlotRegistration        // Discovered parallel route slots
+	Loaders      []LoaderRegistration      // Discovered data loaders
+	Errors       []ErrorRegistration       // Discovered error boundaries
+	Loadings     []LoadingRegistration     // Discovered loading components
+	NotFounds    []NotFoundRegistration    // Discovered 404 pages
 }
 
 // GenerateRoutesFile generates the nexo_routes.go file that registers all routes.
@@ -715,33 +1481,95 @@ func GenerateRoutesFile(cfg RoutesGenConfig) (*Result, error) {
 		cfg.OutputPath = "nexo_routes.go"
 	}
 
-	// Check if we have any routes to register
-	if len(cfg.Routes) == 0 && len(cfg.Middlewares) == 0 && cfg.Proxy == nil && len(cfg.Pages) == 0 && len(cfg.Layouts) == 0 {
-		// No routes found, create a minimal file
-		if err := executeTemplate(cfg.OutputPath, emptyRoutesTemplate, nil); err != nil {
-			return nil, err
+	// Check if we have any routes to register
+	if len(cfg.Routes) == 0 && len(cfg.Sockets) == 0 && len(cfg.Streams) == 0 && len(cfg.Actions) == 0 && len(cfg.Middlewares) == 0 && cfg.Proxy == nil && len(cfg.Pages) == 0 && len(cfg.Layouts) == 0 && len(cfg.Slots) == 0 && len(cfg.Errors) == 0 && len(cfg.Loadings) == 0 && len(cfg.NotFounds) == 0 {
+		// No routes found, create a minimal file
+		if err := executeTemplate(cfg.OutputPath, emptyRoutesTemplate, nil); err != nil {
+			return nil, err
+		}
+		return &Result{Files: []string{cfg.OutputPath}}, nil
+	}
+
+	// Group routes by import path to avoid duplicate imports
+	imports := make(map[string]string) // importPath -> alias
+	// Reserve the identifiers RegisterRoutes uses, so the root app package
+	// (e.g. app/proxy.go) doesn't shadow the app parameter
+	aliasCounter := map[string]int{"app": 1, "nexo": 1}
+
+	for i := range cfg.Routes {
+		r := &cfg.Routes[i]
+		if _, ok := imports[r.ImportPath]; !ok {
+			alias := r.Package
+			// Handle alias conflicts
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[r.ImportPath] = alias
+		}
+		r.ImportAlias = imports[r.ImportPath]
+	}
+
+	for i := range cfg.RouteConfigs {
+		rc := &cfg.RouteConfigs[i]
+		if _, ok := imports[rc.ImportPath]; !ok {
+			alias := rc.Package
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[rc.ImportPath] = alias
+		}
+		rc.ImportAlias = imports[rc.ImportPath]
+	}
+
+	for i := range cfg.Sockets {
+		ws := &cfg.Sockets[i]
+		if _, ok := imports[ws.ImportPath]; !ok {
+			alias := ws.Package
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[ws.ImportPath] = alias
 		}
-		return &Result{Files: []string{cfg.OutputPath}}, nil
+		ws.ImportAlias = imports[ws.ImportPath]
 	}
 
-	// Group routes by import path to avoid duplicate imports
-	imports := make(map[string]string) // importPath -> alias
-	aliasCounter := make(map[string]int)
+	for i := range cfg.Streams {
+		st := &cfg.Streams[i]
+		if _, ok := imports[st.ImportPath]; !ok {
+			alias := st.Package
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[st.ImportPath] = alias
+		}
+		st.ImportAlias = imports[st.ImportPath]
+	}
 
-	for i := range cfg.Routes {
-		r := &cfg.Routes[i]
-		if _, ok := imports[r.ImportPath]; !ok {
-			alias := r.Package
-			// Handle alias conflicts
+	for i := range cfg.Actions {
+		ac := &cfg.Actions[i]
+		if _, ok := imports[ac.ImportPath]; !ok {
+			alias := ac.Package
 			if count, exists := aliasCounter[alias]; exists {
 				aliasCounter[alias] = count + 1
 				alias = fmt.Sprintf("%s%d", alias, count+1)
 			} else {
 				aliasCounter[alias] = 1
 			}
-			imports[r.ImportPath] = alias
+			imports[ac.ImportPath] = alias
 		}
-		r.ImportAlias = imports[r.ImportPath]
+		ac.ImportAlias = imports[ac.ImportPath]
 	}
 
 	for i := range cfg.Middlewares {
@@ -789,9 +1617,90 @@ func GenerateRoutesFile(cfg RoutesGenConfig) (*Result, error) {
 		p.ImportAlias = imports[p.ImportPath]
 	}
 
+	// Route group layouts wrapping pages
+	for i := range cfg.Pages {
+		for j := range cfg.Pages[i].GroupLayouts {
+			ref := &cfg.Pages[i].GroupLayouts[j]
+			if _, ok := imports[ref.ImportPath]; !ok {
+				alias := ref.Package + "_page"
+				if count, exists := aliasCounter[alias]; exists {
+					aliasCounter[alias] = count + 1
+					alias = fmt.Sprintf("%s%d", alias, count+1)
+				} else {
+					aliasCounter[alias] = 1
+				}
+				imports[ref.ImportPath] = alias
+			}
+			ref.ImportAlias = imports[ref.ImportPath]
+		}
+	}
+
+	// Slots use the same aliases as pages
+	for i := range cfg.Slots {
+		sl := &cfg.Slots[i]
+		if _, ok := imports[sl.ImportPath]; !ok {
+			alias := sl.Package + "_page"
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[sl.ImportPath] = alias
+		}
+		sl.ImportAlias = imports[sl.ImportPath]
+	}
+
+	// Error boundaries share the page import when they're in a page directory
+	for i := range cfg.Errors {
+		e := &cfg.Errors[i]
+		if _, ok := imports[e.ImportPath]; !ok {
+			alias := e.Package + "_page"
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[e.ImportPath] = alias
+		}
+		e.ImportAlias = imports[e.ImportPath]
+	}
+
+	for i := range cfg.Loadings {
+		l := &cfg.Loadings[i]
+		if _, ok := imports[l.ImportPath]; !ok {
+			alias := l.Package + "_page"
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[l.ImportPath] = alias
+		}
+		l.ImportAlias = imports[l.ImportPath]
+	}
+
+	for i := range cfg.NotFounds {
+		n := &cfg.NotFounds[i]
+		if _, ok := imports[n.ImportPath]; !ok {
+			alias := n.Package + "_page"
+			if count, exists := aliasCounter[alias]; exists {
+				aliasCounter[alias] = count + 1
+				alias = fmt.Sprintf("%s%d", alias, count+1)
+			} else {
+				aliasCounter[alias] = 1
+			}
+			imports[n.ImportPath] = alias
+		}
+		n.ImportAlias = imports[n.ImportPath]
+	}
+
 	// Build import list
 	// Note: Layout imports are NOT included here because layouts are used by templ pages
-	// via @Layout() syntax, and templ handles the dependency automatically.
+	// via @Layout() syntax, and templ handles the dependency automatically. Only the
+	// layouts of route groups, which wrap the pages below them, are imported.
 	type importEntry struct {
 		Alias string
 		Path  string
@@ -803,21 +1712,48 @@ func GenerateRoutesFile(cfg RoutesGenConfig) (*Result, error) {
 
 	// Check if we need templ import
 	hasPages := len(cfg.Pages) > 0
+	hasSlots := len(cfg.Slots) > 0
+	hasLoading := false
+	for _, p := range cfg.Pages {
+		if p.HasLoading {
+			hasLoading = true
+		}
+	}
 
 	data := struct {
-		Imports     []importEntry
-		Routes      []RouteRegistration
-		Middlewares []MiddlewareRegistration
-		Proxy       *ProxyRegistration
-		Pages       []PageRegistration
-		HasPages    bool
+		Imports      []importEntry
+		Routes       []RouteRegistration
+		RouteConfigs []RouteConfigRegistration
+		Sockets      []SocketRegistration
+		Streams      []StreamRegistration
+		Actions      []ActionRegistration
+		Middlewares  []MiddlewareRegistration
+		Proxy        *ProxyRegistration
+		Pages        []PageRegistration
+		Slots        []SlotRegistration
+		Errors       []ErrorRegistration
+		Loadings     []LoadingRegistration
+		NotFounds    []NotFoundRegistration
+		HasPages     bool
+		HasSlots     bool
+		HasLoading   bool
 	}{
-		Imports:     importList,
-		Routes:      cfg.Routes,
-		Middlewares: cfg.Middlewares,
-		Proxy:       cfg.Proxy,
-		Pages:       cfg.Pages,
-		HasPages:    hasPages,
+		Imports:      importList,
+		Routes:       cfg.Routes,
+		RouteConfigs: cfg.RouteConfigs,
+		Sockets:      cfg.Sockets,
+		Streams:      cfg.Streams,
+		Actions:      cfg.Actions,
+		Middlewares:  cfg.Middlewares,
+		Proxy:        cfg.Proxy,
+		Pages:        cfg.Pages,
+		Slots:        cfg.Slots,
+		Errors:       cfg.Errors,
+		Loadings:     cfg.Loadings,
+		NotFounds:    cfg.NotFounds,
+		HasPages:     hasPages,
+		HasSlots:     hasSlots,
+		HasLoading:   hasLoading,
 	}
 
 	if err := executeRouteTemplate(cfg.OutputPath, routesGenTemplate, data); err != nil {
@@ -838,6 +1774,18 @@ var httpMethods = map[string]string{
 	"Options": http.MethodOptions,
 }
 
+// anyMethods are the methods an Any handler serves when the file has no
+// function of its own for them.
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
 // GenerationWarning represents a warning during route generation.
 type GenerationWarning struct {
 	File    string
@@ -852,6 +1800,7 @@ type LoaderRegistration struct {
 	FilePath    string // Source file path (loader.go)
 	ReturnType  string // Return type of the Loader function
 	Dir         string // Directory containing the loader
+	HasConfig   bool   // Whether LoaderConfig (nexo.LoaderConfig) is defined
 }
 
 // RouteConflict represents a conflict between page.templ and route.go
@@ -886,9 +1835,6 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 		return GenerateRoutesFile(cfg)
 	}
 
-	// With Next.js-style naming ([id], [...slug], (group)), directories need bracket/paren characters removed for Go packages
-	// No symlinks or import sanitization needed
-
 	fset := token.NewFileSet()
 
 	var warnings []GenerationWarning
@@ -896,8 +1842,13 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 
 	// Track which directories have route.go with Get() handlers
 	routeGetHandlers := make(map[string]bool) // dir -> hasGetHandler
+	routeGetFiles := make(map[string]string)  // dir -> file declaring Get()
+	// Track which directories' route files have been scanned
+	routeDirs := make(map[string]bool)
 	// Track which directories have loaders
 	loaderDirs := make(map[string]*LoaderRegistration)
+	// Track which directories have loading components
+	loadingDirs := make(map[string]*LoadingRegistration)
 
 	// First pass: scan route.go and loader.go files to detect conflicts
 	err = filepath.Walk(appDir, func(path string, info os.FileInfo, err error) error {
@@ -911,15 +1862,20 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 
 		dir := filepath.Dir(path)
 
-		switch info.Name() {
-		case "route.go":
-			// Check if this route.go has a Get() handler
+		if nexo.IsRouteFile(info.Name()) {
+			// Check if this route.go, or a route_<method>.go splitting it, has a Get() handler
 			hasGet, err := routeFileHasGetHandler(path)
 			if err != nil {
 				return nil // Continue scanning even if we can't parse this file
 			}
-			routeGetHandlers[dir] = hasGet
+			if hasGet {
+				routeGetFiles[dir] = path
+			}
+			routeGetHandlers[dir] = routeGetHandlers[dir] || hasGet
+			return nil
+		}
 
+		switch info.Name() {
 		case "loader.go":
 			// Scan for Loader() function
 			loader, err := scanLoaderFile(fset, path, appDir, moduleName)
@@ -929,6 +1885,15 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 			if loader != nil {
 				loaderDirs[dir] = loader
 			}
+
+		case "loading.templ":
+			loading, err := scanLoadingFile(path, appDir, moduleName)
+			if err != nil {
+				return nil // Continue scanning
+			}
+			if loading != nil {
+				loadingDirs[dir] = loading
+			}
 		}
 
 		return nil
@@ -960,13 +1925,52 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 			return nil
 		}
 
-		switch info.Name() {
-		case "route.go":
-			routes, err := scanRouteFile(fset, path, appDir, moduleName)
+		// Scan each route once, however many files it's split into
+		if nexo.IsRouteFile(info.Name()) {
+			dir := filepath.Dir(path)
+			if routeDirs[dir] {
+				return nil
+			}
+			routeDirs[dir] = true
+
+			routes, routeConfig, err := scanRouteFiles(fset, dir, appDir, moduleName)
 			if err != nil {
 				return err
 			}
 			cfg.Routes = append(cfg.Routes, routes...)
+			if routeConfig != nil {
+				cfg.RouteConfigs = append(cfg.RouteConfigs, *routeConfig)
+			}
+			return nil
+		}
+
+		switch info.Name() {
+		case "ws.go":
+			socket, err := scanSocketFile(fset, path, appDir, moduleName)
+			if err != nil {
+				return err
+			}
+			if socket != nil {
+				cfg.Sockets = append(cfg.Sockets, *socket)
+			}
+
+		case "sse.go":
+			stream, err := scanStreamFile(fset, path, appDir, moduleName)
+			if err != nil {
+				return err
+			}
+			if stream != nil {
+				cfg.Streams = append(cfg.Streams, *stream)
+			}
+
+		case "action.go":
+			action, err := scanActionFile(fset, path, appDir, moduleName)
+			if err != nil {
+				return err
+			}
+			if action != nil {
+				cfg.Actions = append(cfg.Actions, *action)
+			}
 
 		case "middleware.go":
 			mw, err := scanMiddlewareFile(fset, path, appDir, moduleName)
@@ -1004,8 +2008,29 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 				return nil
 			}
 
+			// Pages under an @slot directory render in the parent layout
+			if name := slotName(dir, appDir); name != "" {
+				slot := SlotRegistration{PageRegistration: *page, Name: name}
+				if loader, hasLoader := loaderDirs[dir]; hasLoader {
+					slot.HasLoader = true
+					slot.LoaderImportPath = loader.ImportPath
+					slot.LoaderPackage = loader.Package
+					slot.HasLoaderConfig = loader.HasConfig
+				}
+				warnings = append(warnings, validatePageParams(&slot.PageRegistration)...)
+				if slot.HasParams && !slot.HasLoader && hasComplexParams(slot.Params) {
+					warnings = append(warnings, GenerationWarning{
+						File:    path,
+						Message: fmt.Sprintf("Slot page has complex parameters %s but no loader.go. Consider adding a loader.go file.", slot.ParamSignature),
+					})
+					return nil
+				}
+				cfg.Slots = append(cfg.Slots, slot)
+				return nil
+			}
+
 			// Check for conflict with route.go
-			routeGoPath := filepath.Join(dir, "route.go")
+			routeGoPath := routeGetFiles[dir]
 			if hasGetHandler, hasRouteGo := routeGetHandlers[dir]; hasRouteGo {
 				if hasGetHandler {
 					// Conflict: route.go has Get() handler, page.templ would also register GET
@@ -1017,9 +2042,6 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 						Pattern:     page.Pattern,
 						HasRouteGet: true,
 					})
-
-					// Remove the Get handler from routes since page.templ takes precedence
-					cfg.Routes = removeGetHandlerForPattern(cfg.Routes, page.Pattern)
 				}
 				// If route.go doesn't have Get(), no conflict - page handles GET, route handles other methods
 			}
@@ -1030,6 +2052,8 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 				page.HasLoader = true
 				page.LoaderImportPath = loader.ImportPath
 				page.LoaderPackage = loader.Package
+				page.HasLoaderConfig = loader.HasConfig
+				page.HasLoading = hasLoadingForDir(loadingDirs, dir, appDir)
 			}
 
 			// Check for parameter mismatches and add warnings
@@ -1058,6 +2082,30 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 			if layout != nil {
 				cfg.Layouts = append(cfg.Layouts, *layout)
 			}
+
+		case "loading.templ":
+			// Already scanned in first pass, add to config
+			if loading, ok := loadingDirs[filepath.Dir(path)]; ok {
+				cfg.Loadings = append(cfg.Loadings, *loading)
+			}
+
+		case "not-found.templ":
+			notFound, err := scanNotFoundFile(path, appDir, moduleName)
+			if err != nil {
+				return err
+			}
+			if notFound != nil {
+				cfg.NotFounds = append(cfg.NotFounds, *notFound)
+			}
+
+		case "error.templ", "error.go":
+			errBoundary, err := scanErrorFile(path, appDir, moduleName)
+			if err != nil {
+				return err
+			}
+			if errBoundary != nil && !hasErrorForPrefix(cfg.Errors, errBoundary.PathPrefix) {
+				cfg.Errors = append(cfg.Errors, *errBoundary)
+			}
 		}
 
 		return nil
@@ -1067,6 +2115,68 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 		return nil, fmt.Errorf("failed to scan app directory: %w", err)
 	}
 
+	// Wrap the pages of route groups in the groups' layouts
+	for i := range cfg.Pages {
+		refs, layoutWarnings := groupLayoutsForPage(cfg.Layouts, cfg.Pages[i].FilePath, appDir)
+		cfg.Pages[i].GroupLayouts = refs
+		warnings = append(warnings, layoutWarnings...)
+	}
+
+	// Remove the Get handlers of conflicting route files since page.templ
+	// takes precedence. The route files are walked after page.templ, so
+	// this waits until the walk is done.
+	for _, c := range conflicts {
+		cfg.Routes = removeGetHandlerForPattern(cfg.Routes, c.Pattern)
+	}
+
+	// Pages take GET from an Any() next to them without a conflict, since
+	// Any only handles the methods nothing else does
+	for _, page := range cfg.Pages {
+		cfg.Routes = slices.DeleteFunc(cfg.Routes, func(r RouteRegistration) bool {
+			return r.Handler == "Any" && r.Method == http.MethodGet && r.Pattern == page.Pattern
+		})
+	}
+
+	// A ws.go or sse.go can't share GET with a page, a Get handler or each
+	// other
+	cfg.Sockets = slices.DeleteFunc(cfg.Sockets, func(ws SocketRegistration) bool {
+		if w, ok := connGetConflict(cfg, "WebSocket", ws.Pattern, ws.FilePath); ok {
+			warnings = append(warnings, w)
+			return true
+		}
+		return false
+	})
+	cfg.Streams = slices.DeleteFunc(cfg.Streams, func(st StreamRegistration) bool {
+		if w, ok := connGetConflict(cfg, "Server-Sent Events", st.Pattern, st.FilePath); ok {
+			warnings = append(warnings, w)
+			return true
+		}
+		return false
+	})
+
+	// Sockets and streams take GET from an Any() next to them, like pages
+	for _, ws := range cfg.Sockets {
+		cfg.Routes = removeAnyGetForPattern(cfg.Routes, ws.Pattern)
+	}
+	for _, st := range cfg.Streams {
+		cfg.Routes = removeAnyGetForPattern(cfg.Routes, st.Pattern)
+	}
+
+	// An action.go posts back to the page next to it, so it needs one and
+	// can't share POST with a Post handler. It takes POST from an Any().
+	cfg.Actions = slices.DeleteFunc(cfg.Actions, func(ac ActionRegistration) bool {
+		if w, ok := actionConflict(cfg, ac); ok {
+			warnings = append(warnings, w)
+			return true
+		}
+		return false
+	})
+	for _, ac := range cfg.Actions {
+		cfg.Routes = slices.DeleteFunc(cfg.Routes, func(r RouteRegistration) bool {
+			return r.Handler == "Any" && r.Method == http.MethodPost && r.Pattern == ac.Pattern
+		})
+	}
+
 	// Print conflict warnings
 	for _, c := range conflicts {
 		printConflictWarning(c)
@@ -1077,6 +2187,11 @@ func ScanAndGenerateRoutes(appDir, outputPath string) (*Result, error) {
 		fmt.Printf("Warning: %s: %s\n", w.File, w.Message)
 	}
 
+	// Directories like [id:int] can only be imported through an alias
+	if err := linkImportAliases(appDir); err != nil {
+		return nil, fmt.Errorf("failed to link import aliases: %w", err)
+	}
+
 	return GenerateRoutesFile(cfg)
 }
 
@@ -1093,6 +2208,10 @@ func routeFileHasGetHandler(filePath string) (bool, error) {
 	return getHandlerRe.Match(content), nil
 }
 
+// loaderConfigRe matches the declaration of a LoaderConfig variable, on
+// its own or in a var block.
+var loaderConfigRe = regexp.MustCompile(`(?m)^(?:var)?\s+LoaderConfig\s*(?:=|nexo\.LoaderConfig\b)`)
+
 // scanLoaderFile scans a loader.go file for a Loader() function
 func scanLoaderFile(fset *token.FileSet, filePath, appDir, moduleName string) (*LoaderRegistration, error) {
 	content, err := os.ReadFile(filePath)
@@ -1108,6 +2227,7 @@ func scanLoaderFile(fset *token.FileSet, filePath, appDir, moduleName string) (*
 	}
 
 	returnType := strings.TrimSpace(string(matches[1]))
+	hasConfig := loaderConfigRe.Match(content)
 
 	dir := filepath.Dir(filePath)
 	relDir, err := filepath.Rel(".", dir)
@@ -1124,9 +2244,69 @@ func scanLoaderFile(fset *token.FileSet, filePath, appDir, moduleName string) (*
 		FilePath:   filePath,
 		ReturnType: returnType,
 		Dir:        dir,
+		HasConfig:  hasConfig,
 	}, nil
 }
 
+// connGetConflict returns a warning for a ws.go or sse.go at pattern if a
+// page, a Get handler or a socket already serves GET there.
+func connGetConflict(cfg RoutesGenConfig, kind, pattern, filePath string) (GenerationWarning, bool) {
+	conflict := ""
+	for _, page := range cfg.Pages {
+		if page.Pattern == pattern {
+			conflict = page.FilePath
+		}
+	}
+	for _, r := range cfg.Routes {
+		if r.Method == http.MethodGet && r.Pattern == pattern && r.Handler != "Any" {
+			conflict = fmt.Sprintf("%s() in %s", r.Handler, r.FilePath)
+		}
+	}
+	for _, ws := range cfg.Sockets {
+		if ws.Pattern == pattern && ws.FilePath != filePath {
+			conflict = ws.FilePath
+		}
+	}
+	if conflict == "" {
+		return GenerationWarning{}, false
+	}
+	return GenerationWarning{
+		File:    filePath,
+		Message: fmt.Sprintf("%s route %s conflicts with %s, skipping", kind, pattern, conflict),
+	}, true
+}
+
+// actionConflict returns a warning for an action.go without a page at its
+// pattern, or whose POST a Post handler already serves.
+func actionConflict(cfg RoutesGenConfig, ac ActionRegistration) (GenerationWarning, bool) {
+	hasPage := slices.ContainsFunc(cfg.Pages, func(page PageRegistration) bool {
+		return page.Pattern == ac.Pattern
+	})
+	if !hasPage {
+		return GenerationWarning{
+			File:    ac.FilePath,
+			Message: fmt.Sprintf("Actions for %s have no page.templ to render, skipping", ac.Pattern),
+		}, true
+	}
+	for _, r := range cfg.Routes {
+		if r.Method == http.MethodPost && r.Pattern == ac.Pattern && r.Handler != "Any" {
+			return GenerationWarning{
+				File:    ac.FilePath,
+				Message: fmt.Sprintf("Actions for %s conflict with %s() in %s, skipping", ac.Pattern, r.Handler, r.FilePath),
+			}, true
+		}
+	}
+	return GenerationWarning{}, false
+}
+
+// removeAnyGetForPattern removes the GET routes an Any() handler serves
+// for a pattern from the routes slice
+func removeAnyGetForPattern(routes []RouteRegistration, pattern string) []RouteRegistration {
+	return slices.DeleteFunc(routes, func(r RouteRegistration) bool {
+		return r.Handler == "Any" && r.Method == http.MethodGet && r.Pattern == pattern
+	})
+}
+
 // removeGetHandlerForPattern removes GET handlers for a specific pattern from the routes slice
 func removeGetHandlerForPattern(routes []RouteRegistration, pattern string) []RouteRegistration {
 	result := make([]RouteRegistration, 0, len(routes))
@@ -1201,6 +2381,9 @@ func scanPageFile(filePath, appDir, moduleName string) (*PageRegistration, error
 	if err != nil {
 		return nil, err
 	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, err
+	}
 
 	// Extract URL parameters from the path (e.g., [slug] -> "slug")
 	urlParams := extractURLParams(dir, appDir)
@@ -1222,6 +2405,7 @@ func scanPageFile(filePath, appDir, moduleName string) (*PageRegistration, error
 		URLParams:      urlParams,
 		HasParams:      hasParams,
 		ParamSignature: paramSignature,
+		Scope:          groupScope(dir, appDir),
 	}, nil
 }
 
@@ -1378,6 +2562,9 @@ func validatePageParams(page *PageRegistration) []GenerationWarning {
 // Note: With Next.js-style naming ([id], [...slug], (group)),
 // directories are already valid Go package names. No sanitization needed.
 
+// templLayoutSignatureRe matches templ Layout() or templ Layout(params...)
+var templLayoutSignatureRe = regexp.MustCompile(`templ\s+Layout\s*\(([^)]*)\)`)
+
 // scanLayoutFile scans a layout.templ file and returns registration info
 func scanLayoutFile(filePath, appDir, moduleName string) (*LayoutRegistration, error) {
 	// Validate the layout has a valid Layout() function with children
@@ -1387,7 +2574,8 @@ func scanLayoutFile(filePath, appDir, moduleName string) (*LayoutRegistration, e
 	}
 
 	contentStr := string(content)
-	if !strings.Contains(contentStr, "templ Layout(") {
+	matches := templLayoutSignatureRe.FindStringSubmatch(contentStr)
+	if len(matches) < 2 {
 		return nil, nil // Skip layouts without Layout() function
 	}
 	if !strings.Contains(contentStr, "{ children... }") {
@@ -1399,19 +2587,149 @@ func scanLayoutFile(filePath, appDir, moduleName string) (*LayoutRegistration, e
 	if err != nil {
 		return nil, err
 	}
-	// Get import path (uses .nexo/imports/ if sanitization is needed)
-	importPath := getImportPath(moduleName, relDir)
-	pathPrefix := layoutPathToPrefix(filepath.Dir(filePath), appDir)
-	pkgName := packageNameFromDir(filepath.Dir(filePath))
+	// Get import path (uses .nexo/imports/ if sanitization is needed)
+	importPath := getImportPath(moduleName, relDir)
+	pathPrefix := layoutPathToPrefix(filepath.Dir(filePath), appDir)
+	pkgName := packageNameFromDir(filepath.Dir(filePath))
+
+	return &LayoutRegistration{
+		ImportPath: importPath,
+		Package:    pkgName,
+		PathPrefix: pathPrefix,
+		FilePath:   filePath,
+		Params:     parseTemplParams(strings.TrimSpace(matches[1])),
+	}, nil
+}
+
+// templErrorSignatureRe matches templ Error(err error) in error.templ
+var templErrorSignatureRe = regexp.MustCompile(`templ\s+Error\s*\(\s*\w+\s+error\s*\)`)
+
+// goErrorSignatureRe matches func Error(err error) templ.Component in error.go
+var goErrorSignatureRe = regexp.MustCompile(`func\s+Error\s*\(\s*\w+\s+error\s*\)\s*templ\.Component`)
+
+// scanErrorFile scans an error.templ or error.go file and returns registration
+// info for its Error(err error) component, which renders the errors of the
+// pages and handlers under its directory.
+func scanErrorFile(filePath, appDir, moduleName string) (*ErrorRegistration, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	re := templErrorSignatureRe
+	if filepath.Ext(filePath) == ".go" {
+		re = goErrorSignatureRe
+	}
+	if !re.Match(content) {
+		return nil, nil // Skip files without an Error(err error) component
+	}
+
+	dir := filepath.Dir(filePath)
+	relDir, err := filepath.Rel(".", dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, err
+	}
+
+	return &ErrorRegistration{
+		ImportPath: getImportPath(moduleName, relDir),
+		Package:    packageNameFromDir(dir),
+		PathPrefix: pagePathToPattern(dir, appDir),
+		FilePath:   filePath,
+	}, nil
+}
+
+// templLoadingSignatureRe matches templ Loading() in loading.templ
+var templLoadingSignatureRe = regexp.MustCompile(`templ\s+Loading\s*\(\s*\)`)
+
+// scanLoadingFile scans a loading.templ file and returns registration info
+// for its Loading() component, which is streamed while the loaders of the
+// pages under its directory run.
+func scanLoadingFile(filePath, appDir, moduleName string) (*LoadingRegistration, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !templLoadingSignatureRe.Match(content) {
+		return nil, nil // Skip files without a Loading() component
+	}
+
+	dir := filepath.Dir(filePath)
+	relDir, err := filepath.Rel(".", dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, err
+	}
+
+	return &LoadingRegistration{
+		ImportPath: getImportPath(moduleName, relDir),
+		Package:    packageNameFromDir(dir),
+		PathPrefix: pagePathToPattern(dir, appDir),
+		FilePath:   filePath,
+	}, nil
+}
+
+// templNotFoundSignatureRe matches templ NotFound() in not-found.templ
+var templNotFoundSignatureRe = regexp.MustCompile(`templ\s+NotFound\s*\(\s*\)`)
+
+// scanNotFoundFile scans a not-found.templ file and returns registration
+// info for its NotFound() component, which is rendered for unmatched paths
+// under its directory.
+func scanNotFoundFile(filePath, appDir, moduleName string) (*NotFoundRegistration, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !templNotFoundSignatureRe.Match(content) {
+		return nil, nil // Skip files without a NotFound() component
+	}
+
+	dir := filepath.Dir(filePath)
+	relDir, err := filepath.Rel(".", dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, err
+	}
 
-	return &LayoutRegistration{
-		ImportPath: importPath,
-		Package:    pkgName,
-		PathPrefix: pathPrefix,
+	return &NotFoundRegistration{
+		ImportPath: getImportPath(moduleName, relDir),
+		Package:    packageNameFromDir(dir),
+		PathPrefix: pagePathToPattern(dir, appDir),
 		FilePath:   filePath,
 	}, nil
 }
 
+// hasLoadingForDir reports whether dir or one of its parents up to appDir
+// has a loading component.
+func hasLoadingForDir(loadingDirs map[string]*LoadingRegistration, dir, appDir string) bool {
+	for {
+		if _, ok := loadingDirs[dir]; ok {
+			return true
+		}
+		if dir == appDir || dir == "." || dir == string(filepath.Separator) {
+			return false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// hasErrorForPrefix reports whether errs already has an error boundary for
+// prefix, e.g. from an error.go next to an error.templ.
+func hasErrorForPrefix(errs []ErrorRegistration, prefix string) bool {
+	for _, e := range errs {
+		if e.PathPrefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
 // pagePathToPattern converts a page directory to a route pattern
 func pagePathToPattern(dir, appDir string) string {
 	rel, err := filepath.Rel(appDir, dir)
@@ -1423,8 +2741,8 @@ func pagePathToPattern(dir, appDir string) string {
 	var routeSegments []string
 
 	for _, seg := range segments {
-		// Skip route groups (group) - they don't affect the URL
-		if routeGroupRe.MatchString(seg) {
+		// Skip route groups (group) and slots @slot - they don't affect the URL
+		if routeGroupRe.MatchString(seg) || slotSegmentRe.MatchString(seg) {
 			continue
 		}
 
@@ -1443,7 +2761,7 @@ func pagePathToPattern(dir, appDir string) string {
 			continue
 		}
 		if matches := dynamicSegmentRe.FindStringSubmatch(seg); len(matches) > 1 {
-			routeSegments = append(routeSegments, "{"+matches[1]+"}")
+			routeSegments = append(routeSegments, routeParam(matches))
 			continue
 		}
 
@@ -1468,8 +2786,8 @@ func layoutPathToPrefix(dir, appDir string) string {
 	var routeSegments []string
 
 	for _, seg := range segments {
-		// Skip route groups (group) - they don't affect the URL
-		if routeGroupRe.MatchString(seg) {
+		// Skip route groups (group) and slots @slot - they don't affect the URL
+		if routeGroupRe.MatchString(seg) || slotSegmentRe.MatchString(seg) {
 			continue
 		}
 
@@ -1497,9 +2815,88 @@ func packageNameFromDir(dir string) string {
 		base = matches[1]
 	}
 
+	// Handle slots @slot -> slot
+	if matches := slotSegmentRe.FindStringSubmatch(base); len(matches) > 1 {
+		base = matches[1]
+	}
+
 	return cleanPackageName(base)
 }
 
+// groupScope returns dir relative to appDir as a route group scope, e.g.
+// "(marketing)/about", or "" if dir isn't in a route group.
+func groupScope(dir, appDir string) string {
+	rel, err := filepath.Rel(appDir, dir)
+	if err != nil || rel == "." {
+		return ""
+	}
+
+	for _, seg := range strings.Split(rel, string(filepath.Separator)) {
+		if routeGroupRe.MatchString(seg) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
+
+// groupLayoutsForPage returns the layouts of the route group directories
+// above a page, outermost first. A page in the group directory itself is
+// expected to call its Layout directly, like any page next to a layout.
+func groupLayoutsForPage(layouts []LayoutRegistration, pageFile, appDir string) ([]GroupLayoutRef, []GenerationWarning) {
+	var refs []GroupLayoutRef
+	var warnings []GenerationWarning
+
+	pageDir := filepath.Dir(pageFile)
+	for _, l := range layouts {
+		dir := filepath.Dir(l.FilePath)
+		if !routeGroupRe.MatchString(filepath.Base(dir)) {
+			continue
+		}
+		rel, err := filepath.Rel(dir, pageDir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		takesTitle := len(l.Params) == 1 && l.Params[0].Type == "string"
+		if len(l.Params) > 0 && !takesTitle {
+			warnings = append(warnings, GenerationWarning{
+				File:    pageFile,
+				Message: fmt.Sprintf("Route group layout %s takes parameters other than a title, so it can't wrap the page. Use templ Layout() or templ Layout(title string).", l.FilePath),
+			})
+			continue
+		}
+		refs = append(refs, GroupLayoutRef{
+			ImportPath: l.ImportPath,
+			Package:    l.Package,
+			TakesTitle: takesTitle,
+		})
+	}
+
+	// Outer groups have shorter import paths
+	sort.SliceStable(refs, func(i, j int) bool {
+		return len(refs[i].ImportPath) < len(refs[j].ImportPath)
+	})
+	return refs, warnings
+}
+
+// slotName returns the name of the innermost @slot directory dir is in,
+// or "" if it isn't in one.
+// e.g., "app/dashboard/@analytics/settings" -> "analytics"
+func slotName(dir, appDir string) string {
+	rel, err := filepath.Rel(appDir, dir)
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(rel, string(filepath.Separator))
+	for i := len(segments) - 1; i >= 0; i-- {
+		if matches := slotSegmentRe.FindStringSubmatch(segments[i]); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
 // deriveTitle derives a page title from the directory path
 func deriveTitle(dir, appDir string) string {
 	rel, err := filepath.Rel(appDir, dir)
@@ -1511,8 +2908,8 @@ func deriveTitle(dir, appDir string) string {
 	segments := strings.Split(rel, string(filepath.Separator))
 	for i := len(segments) - 1; i >= 0; i-- {
 		seg := segments[i]
-		// Skip route groups (group)
-		if routeGroupRe.MatchString(seg) {
+		// Skip route groups (group) and slots @slot
+		if routeGroupRe.MatchString(seg) || slotSegmentRe.MatchString(seg) {
 			continue
 		}
 		// Skip dynamic segments [param], catch-all [...param], optional [[...param]]
@@ -1548,51 +2945,268 @@ func getModuleName() (string, error) {
 	return "", fmt.Errorf("module name not found in go.mod")
 }
 
-// scanRouteFile scans a route.go file for handler functions
-func scanRouteFile(fset *token.FileSet, filePath, appDir, moduleName string) ([]RouteRegistration, error) {
-	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+// scanRouteFiles scans the route files in dir for handler functions:
+// route.go and the route_<method>.go files it may be split into, which are
+// merged into one route.
+func scanRouteFiles(fset *token.FileSet, dir, appDir, moduleName string) ([]RouteRegistration, *RouteConfigRegistration, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		return nil, nil, err
 	}
 
 	// Get the route pattern and import path
-	relDir, err := filepath.Rel(".", filepath.Dir(filePath))
+	relDir, err := filepath.Rel(".", dir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, nil, err
 	}
 	// Get import path (uses .nexo/imports/ if sanitization is needed)
 	importPath := getImportPath(moduleName, relDir)
-	pattern := dirToPattern(filepath.Dir(filePath), appDir)
-	pkgName := file.Name.Name
+	pattern := dirToPattern(dir, appDir)
+	scope := groupScope(dir, appDir)
 
 	var routes []RouteRegistration
+	var routeConfig *RouteConfigRegistration
+	handled := make(map[string]bool)
+	pkgName := ""
+	anyFile := ""
+
+	for _, entry := range entries {
+		if entry.IsDir() || !nexo.IsRouteFile(entry.Name()) {
+			continue
+		}
+		filePath := filepath.Join(dir, entry.Name())
+		file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		}
+		pkgName = file.Name.Name
+
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+				for _, spec := range gd.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok {
+						for _, name := range vs.Names {
+							if name.Name == "Config" {
+								routeConfig = &RouteConfigRegistration{
+									ImportPath: importPath,
+									Pattern:    pattern,
+									FilePath:   filePath,
+								}
+							}
+						}
+					}
+				}
+				continue
+			}
+
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !fn.Name.IsExported() {
+				continue
+			}
+
+			method, ok := httpMethods[fn.Name.Name]
+			if !ok && fn.Name.Name != "Any" {
+				continue
+			}
+
+			if !isValidHandlerSignature(fn) {
+				continue
+			}
+
+			if !ok {
+				anyFile = filePath
+				continue
+			}
+			handled[method] = true
+
+			routes = append(routes, RouteRegistration{
+				ImportPath: importPath,
+				Package:    pkgName,
+				Method:     method,
+				Pattern:    pattern,
+				Handler:    fn.Name.Name,
+				FilePath:   filePath,
+				Scope:      scope,
+			})
+		}
+	}
+
+	// Any handles the methods without a function of their own
+	if anyFile != "" {
+		for _, method := range anyMethods {
+			if handled[method] {
+				continue
+			}
+			routes = append(routes, RouteRegistration{
+				ImportPath: importPath,
+				Package:    pkgName,
+				Method:     method,
+				Pattern:    pattern,
+				Handler:    "Any",
+				FilePath:   anyFile,
+				Scope:      scope,
+			})
+		}
+	}
+
+	if routeConfig != nil {
+		routeConfig.Package = pkgName
+	}
+
+	return routes, routeConfig, nil
+}
+
+// scanSocketFile scans a ws.go file for a Socket function
+func scanSocketFile(fset *token.FileSet, filePath, appDir, moduleName string) (*SocketRegistration, error) {
+	conn, err := scanConnFile(fset, filePath, appDir, moduleName, "Socket", "WebSocketConn", "SocketConfig")
+	if err != nil || conn == nil {
+		return nil, err
+	}
+	return &SocketRegistration{
+		ImportPath: conn.importPath,
+		Package:    conn.pkgName,
+		Pattern:    conn.pattern,
+		FilePath:   filePath,
+		HasConfig:  conn.hasConfig,
+		Scope:      conn.scope,
+	}, nil
+}
+
+// scanStreamFile scans an sse.go file for a Stream function
+func scanStreamFile(fset *token.FileSet, filePath, appDir, moduleName string) (*StreamRegistration, error) {
+	conn, err := scanConnFile(fset, filePath, appDir, moduleName, "Stream", "SSEWriter", "StreamConfig")
+	if err != nil || conn == nil {
+		return nil, err
+	}
+	return &StreamRegistration{
+		ImportPath: conn.importPath,
+		Package:    conn.pkgName,
+		Pattern:    conn.pattern,
+		FilePath:   filePath,
+		HasConfig:  conn.hasConfig,
+		Scope:      conn.scope,
+	}, nil
+}
+
+// scanActionFile scans an action.go file for Action functions: Action,
+// and e.g. ActionDelete. It returns nil if there are none.
+func scanActionFile(fset *token.FileSet, filePath, appDir, moduleName string) (*ActionRegistration, error) {
+	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	dir := filepath.Dir(filePath)
+	relDir, err := filepath.Rel(".", dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, err
+	}
 
+	var funcs []ActionFuncRef
 	for _, decl := range file.Decls {
 		fn, ok := decl.(*ast.FuncDecl)
-		if !ok || !fn.Name.IsExported() {
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() || !strings.HasPrefix(fn.Name.Name, "Action") {
+			continue
+		}
+		if !isValidHandlerSignature(fn) {
 			continue
 		}
+		ref := ActionFuncRef{
+			Name: strings.ToLower(strings.TrimPrefix(fn.Name.Name, "Action")),
+			Func: fn.Name.Name,
+		}
+		if ref.Name == "" {
+			funcs = append([]ActionFuncRef{ref}, funcs...)
+		} else {
+			funcs = append(funcs, ref)
+		}
+	}
 
-		method, ok := httpMethods[fn.Name.Name]
-		if !ok {
+	if len(funcs) == 0 {
+		return nil, nil
+	}
+
+	return &ActionRegistration{
+		// Get import path (uses .nexo/imports/ if sanitization is needed)
+		ImportPath: getImportPath(moduleName, relDir),
+		Package:    file.Name.Name,
+		Pattern:    dirToPattern(dir, appDir),
+		FilePath:   filePath,
+		Funcs:      funcs,
+		Scope:      groupScope(dir, appDir),
+	}, nil
+}
+
+// connFile is what scanConnFile finds in a ws.go or sse.go.
+type connFile struct {
+	importPath string
+	pkgName    string
+	pattern    string
+	scope      string
+	hasConfig  bool
+}
+
+// scanConnFile scans a ws.go or sse.go file for a funcName function taking
+// a *nexo.Context and a *nexo.<connType>, and a configName variable. It
+// returns nil if there is no such function.
+func scanConnFile(fset *token.FileSet, filePath, appDir, moduleName, funcName, connType, configName string) (*connFile, error) {
+	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	// Get the route pattern and import path
+	dir := filepath.Dir(filePath)
+	relDir, err := filepath.Rel(".", dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(relDir); err != nil {
+		return nil, err
+	}
+
+	var hasFunc, hasConfig bool
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+			for _, spec := range gd.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					for _, name := range vs.Names {
+						if name.Name == configName {
+							hasConfig = true
+						}
+					}
+				}
+			}
 			continue
 		}
 
-		if !isValidHandlerSignature(fn) {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != funcName {
 			continue
 		}
+		if isValidConnSignature(fn, connType) {
+			hasFunc = true
+		}
+	}
 
-		routes = append(routes, RouteRegistration{
-			ImportPath: importPath,
-			Package:    pkgName,
-			Method:     method,
-			Pattern:    pattern,
-			Handler:    fn.Name.Name,
-			FilePath:   filePath,
-		})
+	if !hasFunc {
+		return nil, nil
 	}
 
-	return routes, nil
+	return &connFile{
+		// Get import path (uses .nexo/imports/ if sanitization is needed)
+		importPath: getImportPath(moduleName, relDir),
+		pkgName:    file.Name.Name,
+		pattern:    dirToPattern(dir, appDir),
+		scope:      groupScope(dir, appDir),
+		hasConfig:  hasConfig,
+	}, nil
 }
 
 // scanMiddlewareFile scans a middleware.go file
@@ -1612,8 +3226,22 @@ func scanMiddlewareFile(fset *token.FileSet, filePath, appDir, moduleName string
 	pathPrefix := dirToPattern(filepath.Dir(filePath), appDir)
 	pkgName := file.Name.Name
 
-	// Look for Middleware function
+	var hasMiddleware, hasConfig bool
 	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+			for _, spec := range gd.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					for _, name := range vs.Names {
+						if name.Name == "Config" {
+							hasConfig = true
+						}
+					}
+				}
+			}
+			continue
+		}
+
+		// Look for Middleware function
 		fn, ok := decl.(*ast.FuncDecl)
 		if !ok {
 			continue
@@ -1623,19 +3251,23 @@ func scanMiddlewareFile(fset *token.FileSet, filePath, appDir, moduleName string
 			continue
 		}
 
-		if !isValidMiddlewareSignature(fn) {
-			continue
+		if isValidMiddlewareSignature(fn) {
+			hasMiddleware = true
 		}
+	}
 
-		return &MiddlewareRegistration{
-			ImportPath: importPath,
-			Package:    pkgName,
-			PathPrefix: pathPrefix,
-			FilePath:   filePath,
-		}, nil
+	if !hasMiddleware {
+		return nil, nil
 	}
 
-	return nil, nil
+	return &MiddlewareRegistration{
+		ImportPath: importPath,
+		Package:    pkgName,
+		PathPrefix: pathPrefix,
+		FilePath:   filePath,
+		HasConfig:  hasConfig,
+		Scope:      groupScope(filepath.Dir(filePath), appDir),
+	}, nil
 }
 
 // scanProxyFile scans a proxy.go file
@@ -1701,8 +3333,8 @@ func dirToPattern(dir, appDir string) string {
 	var routeSegments []string
 
 	for _, seg := range segments {
-		// Skip route groups (group) - they don't affect the URL
-		if routeGroupRe.MatchString(seg) {
+		// Skip route groups (group) and slots @slot - they don't affect the URL
+		if routeGroupRe.MatchString(seg) || slotSegmentRe.MatchString(seg) {
 			continue
 		}
 
@@ -1720,7 +3352,7 @@ func dirToPattern(dir, appDir string) string {
 
 		// Handle dynamic segment [param]
 		if matches := dynamicSegmentRe.FindStringSubmatch(seg); len(matches) > 1 {
-			routeSegments = append(routeSegments, "{"+matches[1]+"}")
+			routeSegments = append(routeSegments, routeParam(matches))
 			continue
 		}
 
@@ -1773,6 +3405,46 @@ checkReturn:
 	return false
 }
 
+// isValidConnSignature checks if a function has the signature:
+// func(c *nexo.Context, conn *nexo.<connType>) error
+func isValidConnSignature(fn *ast.FuncDecl, connType string) bool {
+	// Flatten the parameters, e.g. (c *nexo.Context, ws *nexo.WebSocketConn)
+	var params []ast.Expr
+	for _, field := range fn.Type.Params.List {
+		for range max(len(field.Names), 1) {
+			params = append(params, field.Type)
+		}
+	}
+	if len(params) != 2 || !isNexoPointer(params[0], "Context") || !isNexoPointer(params[1], connType) {
+		return false
+	}
+
+	// Must return only an error
+	results := fn.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	ident, ok := results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// isNexoPointer reports whether expr is *nexo.<name>, or *<name> within
+// the nexo package.
+func isNexoPointer(expr ast.Expr, name string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	switch x := star.X.(type) {
+	case *ast.SelectorExpr:
+		ident, ok := x.X.(*ast.Ident)
+		return ok && ident.Name == "nexo" && x.Sel.Name == name
+	case *ast.Ident:
+		return x.Name == name
+	}
+	return false
+}
+
 // isValidMiddlewareSignature checks if a function has the correct middleware signature
 func isValidMiddlewareSignature(fn *ast.FuncDecl) bool {
 	// Check for: func(next nexo.HandlerFunc) nexo.HandlerFunc
@@ -1884,8 +3556,97 @@ func isValidProxySignature(fn *ast.FuncDecl) bool {
 	return false
 }
 
+// ImportAliasDir holds symlinks to the app directories whose paths aren't
+// valid Go import paths, e.g. app/users/[id:int], so nexo_routes.go can
+// import them.
+const ImportAliasDir = ".nexo/imports"
+
+var (
+	importElemRe       = regexp.MustCompile(`^[a-zA-Z0-9._~+-]+$`)
+	invalidImportRunes = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
 // getImportPath returns the import path for a directory.
-// With Next.js-style naming ([id], [...slug], (group)), directories need bracket/paren characters removed for Go packages.
+// Brackets, parens and constraints ([id:int], [...slug], (group)) aren't
+// allowed in import paths, so those directories are imported through their
+// alias in ImportAliasDir.
 func getImportPath(moduleName, relDir string) string {
+	if alias, ok := importAlias(relDir); ok {
+		return moduleName + "/" + ImportAliasDir + "/" + alias
+	}
 	return moduleName + "/" + filepath.ToSlash(relDir)
 }
+
+// importAlias returns the name of relDir's symlink in ImportAliasDir, e.g.
+// app_users_id_int for app/users/[id:int]. It returns false if relDir can be
+// imported as is.
+func importAlias(relDir string) (string, bool) {
+	relDir = filepath.ToSlash(relDir)
+	valid := true
+	for _, elem := range strings.Split(relDir, "/") {
+		if !importElemRe.MatchString(elem) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return "", false
+	}
+	return strings.Trim(invalidImportRunes.ReplaceAllString(relDir, "_"), "_"), true
+}
+
+// linkImportAliases recreates ImportAliasDir with a symlink for each
+// directory under appDir that can't be imported by its path. Two directories
+// sharing an alias are rejected rather than generating an import that points
+// at the wrong package.
+func linkImportAliases(appDir string) error {
+	aliases := make(map[string]string) // alias -> relDir
+	err := filepath.Walk(appDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != appDir && (strings.HasPrefix(info.Name(), ".") || isGeneratorPrivateFolder(info.Name(), path)) {
+			return filepath.SkipDir
+		}
+
+		relDir, err := filepath.Rel(".", path)
+		if err != nil {
+			return err
+		}
+		alias, ok := importAlias(relDir)
+		if !ok {
+			return nil
+		}
+		if other, taken := aliases[alias]; taken {
+			return fmt.Errorf("%s and %s both import as %s/%s; rename one of them", other, relDir, ImportAliasDir, alias)
+		}
+		aliases[alias] = relDir
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(ImportAliasDir); err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(ImportAliasDir, 0755); err != nil {
+		return err
+	}
+	for alias, relDir := range aliases {
+		target, err := filepath.Rel(ImportAliasDir, relDir)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, filepath.Join(ImportAliasDir, alias)); err != nil {
+			return fmt.Errorf("failed to link %s: %w", relDir, err)
+		}
+	}
+	return nil
+}
@@ -2,10 +2,13 @@ package generator
 
 import (
 	"fmt"
+	"go/format"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenerateRoute(t *testing.T) {
@@ -136,6 +139,99 @@ func TestGenerateRoute_AlreadyExists(t *testing.T) {
 	}
 }
 
+func TestGenerateRoute_Append(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+
+	if _, err := GenerateRoute(RouteConfig{
+		Path:    "users/[id]",
+		Methods: []string{"GET"},
+		AppDir:  appDir,
+	}); err != nil {
+		t.Fatalf("GenerateRoute() error = %v", err)
+	}
+
+	routeFile := filepath.Join(appDir, "api", "users", "[id]", "route.go")
+	original, _ := os.ReadFile(routeFile)
+	custom := strings.Replace(string(original), "// TODO: Implement Get handler", "// custom Get body", 1)
+	if err := os.WriteFile(routeFile, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := GenerateRoute(RouteConfig{
+		Path:    "users/[id]",
+		Methods: []string{"GET", "PUT", "DELETE"},
+		AppDir:  appDir,
+		Append:  true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateRoute() with Append error = %v", err)
+	}
+	if result.Pattern != "/api/users/{id}" {
+		t.Errorf("Pattern = %q, want /api/users/{id}", result.Pattern)
+	}
+
+	content, _ := os.ReadFile(routeFile)
+	contentStr := string(content)
+
+	if strings.Count(contentStr, "func Get(") != 1 {
+		t.Error("Expected existing Get handler to be kept exactly once")
+	}
+	if !strings.Contains(contentStr, "// custom Get body") {
+		t.Error("Expected existing handler body to be preserved")
+	}
+	for _, fn := range []string{"func Put(c *nexo.Context) error", "func Delete(c *nexo.Context) error"} {
+		if !strings.Contains(contentStr, fn) {
+			t.Errorf("Expected %q to be appended", fn)
+		}
+	}
+}
+
+func TestGenerateRoute_AppendAddsImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	dir := filepath.Join(appDir, "api", "health")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "route.go"), []byte("package health\n\nconst version = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GenerateRoute(RouteConfig{
+		Path:    "health",
+		Methods: []string{"GET"},
+		AppDir:  appDir,
+		Append:  true,
+	}); err != nil {
+		t.Fatalf("GenerateRoute() with Append error = %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(dir, "route.go"))
+	if !strings.Contains(string(content), `import "github.com/abdul-hamid-achik/nexo/pkg/nexo"`) {
+		t.Errorf("Expected nexo import to be added, got:\n%s", content)
+	}
+	if !strings.Contains(string(content), "func Get(c *nexo.Context) error") {
+		t.Error("Expected Get handler to be appended")
+	}
+}
+
+func TestGenerateRoute_AppendNothingMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+
+	cfg := RouteConfig{Path: "users", Methods: []string{"GET"}, AppDir: appDir}
+	if _, err := GenerateRoute(cfg); err != nil {
+		t.Fatalf("GenerateRoute() error = %v", err)
+	}
+
+	cfg.Methods = []string{"GET"}
+	cfg.Append = true
+	if _, err := GenerateRoute(cfg); err == nil {
+		t.Error("Expected error when all handlers already exist")
+	}
+}
+
 func TestGenerateMiddleware(t *testing.T) {
 	templates := []string{"blank", "auth", "logging", "timing", "cors"}
 
@@ -317,6 +413,7 @@ func TestPackageNameFromPath(t *testing.T) {
 		{"", "app"},
 		{"users", "users"},
 		{"[id]", "id"},
+		{"[id:int]", "id"},
 		{"[...slug]", "slug"},
 		{"[[...categories]]", "categories"},
 		{"(dashboard)", "dashboard"},
@@ -348,6 +445,8 @@ func TestPathToPattern(t *testing.T) {
 		{"(dashboard)/apps", "apps"},
 		{"(dashboard)/apps/[name]", "apps/{name}"},
 		{"api/v1/users/[id]/posts", "api/v1/users/{id}/posts"},
+		{"users/[id:int]", "users/{id:-?[0-9]+}"},
+		{"airports/[code:regexp([A-Z]{3})]", "airports/{code:[A-Z]{3}}"},
 	}
 
 	for _, tt := range tests {
@@ -360,6 +459,19 @@ func TestPathToPattern(t *testing.T) {
 	}
 }
 
+func TestCheckConstraints(t *testing.T) {
+	for _, path := range []string{"users/[id]", "users/[id:int]", "tags/[tag:alpha]/posts"} {
+		if err := checkConstraints(path); err != nil {
+			t.Errorf("checkConstraints(%q) error = %v", path, err)
+		}
+	}
+	for _, path := range []string{"users/[id:number]", "codes/[code:regexp([A-Z)]"} {
+		if err := checkConstraints(path); err == nil {
+			t.Errorf("checkConstraints(%q) should fail", path)
+		}
+	}
+}
+
 func TestExtractParams(t *testing.T) {
 	tests := []struct {
 		path      string
@@ -453,6 +565,38 @@ func TestGenerateRoutesFile(t *testing.T) {
 		}
 	})
 
+	t.Run("unchanged output is not rewritten", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		outputPath := filepath.Join(tmpDir, "nexo_routes.go")
+
+		cfg := RoutesGenConfig{
+			ModuleName: "testapp",
+			OutputPath: outputPath,
+			Routes: []RouteRegistration{
+				{ImportPath: "testapp/app/api/health", Package: "health", Method: "GET", Pattern: "/api/health", Handler: "Get"},
+			},
+		}
+
+		if _, err := GenerateRoutesFile(cfg); err != nil {
+			t.Fatalf("GenerateRoutesFile() error = %v", err)
+		}
+		past := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(outputPath, past, past); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := GenerateRoutesFile(cfg); err != nil {
+			t.Fatalf("GenerateRoutesFile() error = %v", err)
+		}
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(past) {
+			t.Error("Expected unchanged nexo_routes.go to be left untouched")
+		}
+	})
+
 	t.Run("with routes", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		outputPath := filepath.Join(tmpDir, "nexo_routes.go")
@@ -562,7 +706,7 @@ func TestGenerateRoutesFile(t *testing.T) {
 		contentStr := string(content)
 
 		// Check middleware registration
-		if !strings.Contains(contentStr, `AddMiddleware("/api"`) {
+		if !strings.Contains(contentStr, `AddMiddleware("/api", "", api.Middleware)`) {
 			t.Error("Expected file to register middleware for /api")
 		}
 
@@ -612,6 +756,9 @@ func TestGenerateRoutesFile(t *testing.T) {
 		if !strings.Contains(contentStr, `ProxyConfig`) {
 			t.Error("Expected file to use ProxyConfig")
 		}
+		if !strings.Contains(contentStr, `app2.Proxy, app2.ProxyConfig`) {
+			t.Error("Expected the app package alias not to shadow the app parameter")
+		}
 
 		_ = result
 	})
@@ -661,6 +808,8 @@ func TestPagePathToPattern(t *testing.T) {
 		{"app/(dashboard)", "app", "/"},
 		{"app/(dashboard)/apps", "app", "/apps"},
 		{"app/(dashboard)/apps/[name]", "app", "/apps/{name}"},
+		{"app/dashboard/@analytics", "app", "/dashboard"},
+		{"app/dashboard/@analytics/settings", "app", "/dashboard/settings"},
 	}
 
 	for _, tt := range tests {
@@ -1050,6 +1199,9 @@ func TestZeroValue(t *testing.T) {
 		{"User", "User{}"},
 		{"*User", "nil"},
 		{"MyStruct", "MyStruct{}"},
+		{"any", "nil"},
+		{"[]User", "[]User{}"},
+		{"map[string]any", "map[string]any{}"},
 	}
 
 	for _, tt := range tests {
@@ -1267,6 +1419,94 @@ func TestGenerateLoader(t *testing.T) {
 	}
 }
 
+func TestGenerateLoader_TypedParams(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+
+	result, err := GenerateLoader(LoaderConfig{
+		Path:   "users/[id]/files/[...path]",
+		AppDir: appDir,
+	})
+	if err != nil {
+		t.Fatalf("GenerateLoader() error = %v", err)
+	}
+
+	if result.Pattern != "/users/{id}/files/*" {
+		t.Errorf("Pattern = %q, want /users/{id}/files/*", result.Pattern)
+	}
+
+	content, err := os.ReadFile(filepath.Join(appDir, "users/[id]/files/[...path]/loader.go"))
+	if err != nil {
+		t.Fatalf("Failed to read loader: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		"type FilesData struct",
+		"ID string",
+		"Path []string",
+		"func Loader(c *nexo.Context) (FilesData, error)",
+		`ID: c.Param("id"),`,
+		`Path: c.ParamAll("path"),`,
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("Expected loader to contain %q, got:\n%s", want, contentStr)
+		}
+	}
+
+	// A page accepting the data type should be created alongside the loader
+	if len(result.Files) != 2 {
+		t.Fatalf("Expected loader and page files, got %v", result.Files)
+	}
+	page, err := os.ReadFile(filepath.Join(appDir, "users/[id]/files/[...path]/page.templ"))
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if !strings.Contains(string(page), "templ Page(data FilesData)") {
+		t.Errorf("Expected page to accept FilesData, got:\n%s", page)
+	}
+}
+
+func TestGenerateLoader_KeepsExistingPage(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	pageDir := filepath.Join(appDir, "dashboard")
+	if err := os.MkdirAll(pageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "package dashboard\n\ntempl Page() {}\n"
+	if err := os.WriteFile(filepath.Join(pageDir, "page.templ"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := GenerateLoader(LoaderConfig{Path: "dashboard", AppDir: appDir})
+	if err != nil {
+		t.Fatalf("GenerateLoader() error = %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Errorf("Expected only loader file, got %v", result.Files)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(pageDir, "page.templ"))
+	if string(content) != existing {
+		t.Error("Existing page.templ should not be modified")
+	}
+}
+
+func TestToGoFieldName(t *testing.T) {
+	tests := map[string]string{
+		"id":     "ID",
+		"userId": "UserID",
+		"slug":   "Slug",
+		"postID": "PostID",
+	}
+	for in, want := range tests {
+		if got := toGoFieldName(in); got != want {
+			t.Errorf("toGoFieldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
 func TestIsGeneratorPrivateFolder(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1330,6 +1570,7 @@ func TestCleanPackageName(t *testing.T) {
 		{"(admin)", "admin"},
 		{"api-v1", "apiv1"},
 		{"API", "api"},
+		{"type", "pkgtype"},
 		{"", "route"}, // empty defaults to "route" not "pkg"
 	}
 
@@ -1407,8 +1648,11 @@ func TestGetImportPath(t *testing.T) {
 	}{
 		{"myapp", "app/api/users", "myapp/app/api/users"},
 		{"github.com/user/project", "app/api", "github.com/user/project/app/api"},
-		{"myapp", "app/api/users/[id]", "myapp/app/api/users/[id]"},
-		{"myapp", "app/(admin)/settings", "myapp/app/(admin)/settings"},
+		{"myapp", "app/api/users/[id]", "myapp/.nexo/imports/app_api_users_id"},
+		{"myapp", "app/api/users/[id:int]", "myapp/.nexo/imports/app_api_users_id_int"},
+		{"myapp", "app/docs/[...slug]", "myapp/.nexo/imports/app_docs_slug"},
+		{"myapp", "app/(admin)/settings", "myapp/.nexo/imports/app_admin_settings"},
+		{"myapp", "app/my-app/v1.2", "myapp/app/my-app/v1.2"},
 	}
 
 	for _, tt := range tests {
@@ -1569,3 +1813,1270 @@ func TestRemoveGetHandlerForPattern(t *testing.T) {
 		t.Error("DELETE /dashboard should be preserved")
 	}
 }
+
+func TestGenerateMigration(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "migrations")
+
+	orig := migrationNow
+	migrationNow = func() time.Time { return time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC) }
+	defer func() { migrationNow = orig }()
+
+	result, err := GenerateMigration(MigrationConfig{Name: "add_users_table", Dir: dir})
+	if err != nil {
+		t.Fatalf("GenerateMigration failed: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "20240115093000_add_users_table.up.sql"),
+		filepath.Join(dir, "20240115093000_add_users_table.down.sql"),
+	}
+	if len(result.Files) != 2 || result.Files[0] != want[0] || result.Files[1] != want[1] {
+		t.Fatalf("Files = %v, want %v", result.Files, want)
+	}
+	for _, f := range want {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("Expected file %s: %v", f, err)
+		}
+	}
+
+	// Same name again should fail
+	if _, err := GenerateMigration(MigrationConfig{Name: "add_users_table", Dir: dir}); err == nil {
+		t.Error("Expected error for duplicate migration name")
+	}
+}
+
+func TestGenerateMigration_NormalizesName(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err := GenerateMigration(MigrationConfig{Name: "Add-Posts Table", Dir: dir})
+	if err != nil {
+		t.Fatalf("GenerateMigration failed: %v", err)
+	}
+	if !strings.HasSuffix(result.Files[0], "_add_posts_table.up.sql") {
+		t.Errorf("Files[0] = %q, want suffix _add_posts_table.up.sql", result.Files[0])
+	}
+
+	if _, err := GenerateMigration(MigrationConfig{Name: "1bad!", Dir: dir}); err == nil {
+		t.Error("Expected error for invalid migration name")
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	root := t.TempDir()
+
+	result, err := GenerateSeed(SeedConfig{
+		Name:       "users",
+		Envs:       []string{"development", "test"},
+		Root:       root,
+		ModuleName: "example.com/myapp",
+	})
+	if err != nil {
+		t.Fatalf("GenerateSeed failed: %v", err)
+	}
+
+	// First seed creates the registry and runner too
+	if len(result.Files) != 3 {
+		t.Fatalf("Expected 3 files, got %v", result.Files)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(root, "seeds", "users.go"))
+	for _, want := range []string{`Name: "users"`, `Envs: []string{"development", "test"}`, "func seedUsers("} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %q in seed file, got:\n%s", want, content)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(root, "cmd", "seed", "main.go"))
+	if !strings.Contains(string(main), `"example.com/myapp/seeds"`) {
+		t.Errorf("Expected seeds import in runner, got:\n%s", main)
+	}
+
+	// Second seed only adds its own file
+	result, err = GenerateSeed(SeedConfig{Name: "blog_posts", Root: root})
+	if err != nil {
+		t.Fatalf("GenerateSeed failed: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Errorf("Expected 1 file, got %v", result.Files)
+	}
+	content, _ = os.ReadFile(result.Files[0])
+	if !strings.Contains(string(content), "func seedBlogPosts(") {
+		t.Errorf("Expected seedBlogPosts in seed file, got:\n%s", content)
+	}
+
+	if _, err := GenerateSeed(SeedConfig{Name: "users", Root: root}); err == nil {
+		t.Error("Expected error for existing seed")
+	}
+}
+
+func TestGenerateE2E(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "app", "page.templ"), []byte("package app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := GenerateE2E(E2EConfig{Root: root, APIPath: "api/status"})
+	if err != nil {
+		t.Fatalf("GenerateE2E failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("Expected 2 files, got %v", result.Files)
+	}
+
+	setup, _ := os.ReadFile(filepath.Join(root, "e2e_setup_test.go"))
+	if !strings.HasPrefix(string(setup), "//go:build e2e") {
+		t.Error("Expected e2e build tag in setup file")
+	}
+	if !strings.Contains(string(setup), "httptest.NewServer(app)") {
+		t.Error("Expected httptest server in setup file")
+	}
+
+	tests, _ := os.ReadFile(filepath.Join(root, "e2e_test.go"))
+	if !strings.Contains(string(tests), `get(t, srv, "/api/status")`) {
+		t.Error("Expected API assertion against /api/status")
+	}
+	if !strings.Contains(string(tests), "TestE2E_HomePage") {
+		t.Error("Expected page assertion when app/page.templ exists")
+	}
+
+	if _, err := GenerateE2E(E2EConfig{Root: root}); err == nil {
+		t.Error("Expected error when e2e tests already exist")
+	}
+}
+
+func TestGenerateE2E_APIOnly(t *testing.T) {
+	root := t.TempDir()
+
+	if _, err := GenerateE2E(E2EConfig{Root: root}); err != nil {
+		t.Fatalf("GenerateE2E failed: %v", err)
+	}
+
+	tests, _ := os.ReadFile(filepath.Join(root, "e2e_test.go"))
+	if strings.Contains(string(tests), "TestE2E_HomePage") {
+		t.Error("Expected no page assertion without app/page.templ")
+	}
+	if !strings.Contains(string(tests), `"/api/health"`) {
+		t.Error("Expected default /api/health assertion")
+	}
+}
+
+func TestGenerateStaticEmbed(t *testing.T) {
+	root := t.TempDir()
+
+	if _, err := GenerateStaticEmbed(StaticEmbedConfig{Root: root}); err == nil {
+		t.Error("Expected error when the static directory is missing")
+	}
+	if _, err := GenerateStaticEmbed(StaticEmbedConfig{Root: root, Dir: "../static"}); err == nil {
+		t.Error("Expected error for a directory outside the project")
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "public", "css"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	result, err := GenerateStaticEmbed(StaticEmbedConfig{Root: root, Dir: "./public/"})
+	if err != nil {
+		t.Fatalf("GenerateStaticEmbed failed: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("Expected 1 file, got %v", result.Files)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(root, "nexo_static.go"))
+	for _, want := range []string{"//go:build " + StaticEmbedTag, "//go:embed all:public", `nexo.EmbedStatic("public", sub)`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %q in nexo_static.go, got:\n%s", want, content)
+		}
+	}
+	if _, err := format.Source(content); err != nil {
+		t.Errorf("Generated file is not valid Go: %v", err)
+	}
+}
+
+func TestGenerateForm(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+
+	result, err := GenerateForm(FormConfig{
+		Path:   "contact",
+		Fields: []string{"name", "email", "message"},
+		AppDir: appDir,
+	})
+	if err != nil {
+		t.Fatalf("GenerateForm failed: %v", err)
+	}
+	if result.Pattern != "/contact" {
+		t.Errorf("Pattern = %q, want /contact", result.Pattern)
+	}
+	if len(result.Files) != 4 {
+		t.Fatalf("Expected 4 files, got %v", result.Files)
+	}
+
+	form, _ := os.ReadFile(filepath.Join(appDir, "contact", "form.go"))
+	for _, want := range []string{"type ContactForm struct", `c.FormValue("email")`, "mail.ParseAddress(f.Email)", `errs["message"] = "Message is required"`} {
+		if !strings.Contains(string(form), want) {
+			t.Errorf("Expected %q in form.go, got:\n%s", want, form)
+		}
+	}
+
+	route, _ := os.ReadFile(filepath.Join(appDir, "contact", "route.go"))
+	if !strings.Contains(string(route), "func Post(c *nexo.Context) error") {
+		t.Error("Expected Post handler in route.go")
+	}
+	if !strings.Contains(string(route), `c.Redirect("/contact", http.StatusSeeOther)`) {
+		t.Error("Expected redirect after successful submission")
+	}
+
+	page, _ := os.ReadFile(filepath.Join(appDir, "contact", "page.templ"))
+	if !strings.Contains(string(page), `<textarea id="message" name="message" required>`) {
+		t.Errorf("Expected textarea for message, got:\n%s", page)
+	}
+	if !strings.Contains(string(page), `<input type="email" id="email"`) {
+		t.Errorf("Expected email input, got:\n%s", page)
+	}
+	if !strings.Contains(string(page), `hx-post="/contact"`) {
+		t.Error("Expected hx-post on form")
+	}
+
+	if _, err := GenerateForm(FormConfig{Path: "contact", Fields: []string{"name"}, AppDir: appDir}); err == nil {
+		t.Error("Expected error when form files already exist")
+	}
+}
+
+func TestGenerateForm_Errors(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+
+	tests := []FormConfig{
+		{Path: "contact", AppDir: appDir},
+		{Path: "users/[id]/edit", Fields: []string{"name"}, AppDir: appDir},
+		{Path: "signup", Fields: []string{"age:range"}, AppDir: appDir},
+		{Path: "signup", Fields: []string{"bad-name!"}, AppDir: appDir},
+	}
+	for _, cfg := range tests {
+		if _, err := GenerateForm(cfg); err == nil {
+			t.Errorf("GenerateForm(%+v) expected error", cfg)
+		}
+	}
+}
+
+func TestParseFormField(t *testing.T) {
+	tests := []struct {
+		def       string
+		goName    string
+		inputType string
+	}{
+		{"name", "Name", "text"},
+		{"email", "Email", "email"},
+		{"message", "Message", "textarea"},
+		{"first_name", "FirstName", "text"},
+		{"user_id:number", "UserID", "number"},
+	}
+	for _, tt := range tests {
+		f, err := parseFormField(tt.def)
+		if err != nil {
+			t.Fatalf("parseFormField(%q) error: %v", tt.def, err)
+		}
+		if f.GoName != tt.goName || f.InputType != tt.inputType {
+			t.Errorf("parseFormField(%q) = %s/%s, want %s/%s", tt.def, f.GoName, f.InputType, tt.goName, tt.inputType)
+		}
+	}
+}
+
+func TestScanAndGenerateRoutesWithErrorBoundaries(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	files := map[string]string{
+		"page.templ":           "package app\n\ntempl Page() {\n\t<h1>Home</h1>\n}\n",
+		"error.templ":          "package app\n\ntempl Error(err error) {\n\t<p>{ err.Error() }</p>\n}\n",
+		"dashboard/page.templ": "package dashboard\n\ntempl Page() {\n\t<h1>Dashboard</h1>\n}\n",
+		"dashboard/error.go": `package dashboard
+
+import "github.com/a-h/templ"
+
+func Error(err error) templ.Component {
+	return templ.Raw("<p>" + err.Error() + "</p>")
+}
+`,
+		"posts/[slug]/error.templ": "package slug\n\ntempl Error(err error) {\n\t<p>{ err.Error() }</p>\n}\n",
+		// Not an error boundary: Error() has the wrong signature
+		"settings/error.templ": "package settings\n\ntempl Error(message string) {\n\t<p>{ message }</p>\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		`app.Renderer().SetErrorComponent("/", app_page.Error)`,
+		`app.Renderer().SetErrorComponent("/dashboard", dashboard_page.Error)`,
+		`app.Renderer().SetErrorComponent("/posts/{slug}", slug_page.Error)`,
+		`slug_page "testmodule/.nexo/imports/app_posts_slug"`,
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("generated file missing %q\n%s", want, contentStr)
+		}
+	}
+	if strings.Contains(contentStr, `"/settings"`) {
+		t.Errorf("error.templ without Error(err error) should be skipped\n%s", contentStr)
+	}
+	// The page and its error boundary share one import
+	if n := strings.Count(contentStr, `"testmodule/app/dashboard"`); n != 1 {
+		t.Errorf("dashboard package imported %d times, want 1", n)
+	}
+}
+
+func TestScanAndGenerateRoutesWithLoading(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	loader := `package %s
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Loader(c *nexo.Context) (Data, error) {
+	return Data{}, nil
+}
+`
+	files := map[string]string{
+		"dashboard/loading.templ":    "package dashboard\n\ntempl Loading() {\n\t<p>Loading...</p>\n}\n",
+		"dashboard/page.templ":       "package dashboard\n\ntempl Page(data Data) {\n\t<h1>Dashboard</h1>\n}\n",
+		"dashboard/loader.go":        fmt.Sprintf(loader, "dashboard"),
+		"dashboard/stats/page.templ": "package stats\n\ntempl Page(data Data) {\n\t<h1>Stats</h1>\n}\n",
+		"dashboard/stats/loader.go":  fmt.Sprintf(loader, "stats"),
+		"blog/page.templ":            "package blog\n\ntempl Page(data Data) {\n\t<h1>Blog</h1>\n}\n",
+		"blog/loader.go":             fmt.Sprintf(loader, "blog"),
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		`"github.com/a-h/templ"`,
+		`app.Renderer().SetLoadingComponent("/dashboard", dashboard_page.Loading())`,
+		// Pages below the loading.templ stream it too
+		"return stats_page.Page(data), nil",
+		"return dashboard_page.Page(data), nil",
+		// Pages without one render once their loader returns
+		"return nexo.TemplComponent(c, 200, blog_page.Page(data))",
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("generated file missing %q\n%s", want, contentStr)
+		}
+	}
+	if n := strings.Count(contentStr, "RenderWithLoading"); n != 2 {
+		t.Errorf("RenderWithLoading used %d times, want 2\n%s", n, contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesWithLoaderConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	loader := `package %s
+
+import (
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+%s
+func Loader(c *nexo.Context) (Data, error) {
+	return Data{}, nil
+}
+`
+	files := map[string]string{
+		"products/page.templ":    "package products\n\ntempl Page(data Data) {\n\t<h1>Products</h1>\n}\n",
+		"products/loader.go":     fmt.Sprintf(loader, "products", "\nvar LoaderConfig = nexo.LoaderConfig{TTL: time.Minute, StaleWhileRevalidate: true}\n"),
+		"reports/loading.templ":  "package reports\n\ntempl Loading() {\n\t<p>Loading...</p>\n}\n",
+		"reports/page.templ":     "package reports\n\ntempl Page(data Data) {\n\t<h1>Reports</h1>\n}\n",
+		"reports/loader.go":      fmt.Sprintf(loader, "reports", "\nvar (\n\tLoaderConfig = nexo.LoaderConfig{TTL: time.Hour}\n)\n"),
+		"home/@stats/page.templ": "package stats\n\ntempl Page(data Data) {\n\t<h2>Stats</h2>\n}\n",
+		"home/@stats/loader.go":  fmt.Sprintf(loader, "stats", "\nvar LoaderConfig nexo.LoaderConfig\n"),
+		"home/layout.templ":      "package home\n\ntempl Layout() {\n\t{ children... }\n\t@nexo.Slot(\"stats\")\n}\n",
+		"home/page.templ":        "package home\n\ntempl Page() {\n\t<h1>Home</h1>\n}\n",
+		"blog/page.templ":        "package blog\n\ntempl Page(data Data) {\n\t<h1>Blog</h1>\n}\n",
+		"blog/loader.go":         fmt.Sprintf(loader, "blog", ""),
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		"// Data loaded by: products.Loader(), cached by products.LoaderConfig",
+		"data, err := nexo.LoadWithConfig(c, &products_page.LoaderConfig, products_page.Loader)",
+		"data, err := nexo.LoadWithConfig(c, &reports_page.LoaderConfig, reports_page.Loader)",
+		"data, err := nexo.LoadWithConfig(c, &stats_page.LoaderConfig, stats_page.Loader)",
+		// Loaders without a LoaderConfig run on every request
+		"data, err := blog_page.Loader(c)",
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("generated file missing %q\n%s", want, contentStr)
+		}
+	}
+}
+
+func TestScanAndGenerateRoutesWithNotFoundPages(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	files := map[string]string{
+		"not-found.templ":              "package app\n\ntempl NotFound() {\n\t<h1>Page not found</h1>\n}\n",
+		"dashboard/not-found.templ":    "package dashboard\n\ntempl NotFound() {\n\t<h1>No such dashboard page</h1>\n}\n",
+		"(marketing)/about/page.templ": "package about\n\ntempl Page() {\n\t<h1>About</h1>\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		`app.Renderer().SetNotFoundComponent(app_page.NotFound())`,
+		`app.Renderer().SetNotFoundComponentFor("/dashboard", dashboard_page.NotFound())`,
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("generated file missing %q\n%s", want, contentStr)
+		}
+	}
+}
+
+func TestScanAndGenerateRoutesWithRouteConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	files := map[string]string{
+		"api/users/route.go": `package users
+
+import (
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+var Config = nexo.RouteConfig{Timeout: 5 * time.Second, RequireAuth: true}
+
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+
+func Post(c *nexo.Context) error {
+	return c.JSON(201, nil)
+}
+`,
+		"api/health/route.go": `package health
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	// The config is applied once for all methods of the route
+	if n := strings.Count(contentStr, `app.RouteTree().SetRouteConfig("/api/users", users.Config)`); n != 1 {
+		t.Errorf("expected the users config to be set once, got %d\n%s", n, contentStr)
+	}
+	if strings.Contains(contentStr, "health.Config") {
+		t.Errorf("route.go without a Config should not set one\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesWithMiddlewareConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	files := map[string]string{
+		"api/middleware.go": `package api
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+var Config = nexo.MiddlewareOptions{Skip: []string{"/api/health"}, Priority: 10}
+
+func Middleware(next nexo.HandlerFunc) nexo.HandlerFunc {
+	return next
+}
+`,
+		"admin/middleware.go": `package admin
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Middleware(next nexo.HandlerFunc) nexo.HandlerFunc {
+	return next
+}
+`,
+		"api/health/route.go": `package health
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	if !strings.Contains(contentStr, `app.RouteTree().AddMiddlewareWithOptions("/api", "", api.Middleware, api.Config)`) {
+		t.Errorf("expected the api middleware to be added with its Config\n%s", contentStr)
+	}
+	if !strings.Contains(contentStr, `app.RouteTree().AddMiddleware("/admin", "", admin.Middleware)`) {
+		t.Errorf("expected middleware without a Config to be added as before\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesWithMethodFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	handler := func(pkg, name string) string {
+		return "package " + pkg + "\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc " + name + "(c *nexo.Context) error {\n\treturn nil\n}\n"
+	}
+	files := map[string]string{
+		"api/users/route.go":        "package users\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nvar Config = nexo.RouteConfig{RequireAuth: true}\n",
+		"api/users/route_get.go":    handler("users", "Get"),
+		"api/users/route_post.go":   handler("users", "Post"),
+		"api/users/route_any.go":    handler("users", "Any"),
+		"api/users/helpers.go":      handler("users", "Put"),
+		"posts/page.templ":          "package posts\n\ntempl Page() {\n\t<h1>Posts</h1>\n}\n",
+		"posts/route_get.go":        handler("posts", "Get"),
+		"posts/route_delete.go":     handler("posts", "Delete"),
+		"api/orders/route_patch.go": handler("orders", "Patch"),
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	expected := []string{
+		"// GET /api/users (from app/api/users/route_get.go)\n\tapp.RegisterRoute(\"GET\", \"/api/users\", users.Get)",
+		"// POST /api/users (from app/api/users/route_post.go)\n\tapp.RegisterRoute(\"POST\", \"/api/users\", users.Post)",
+		"// PUT /api/users (from app/api/users/route_any.go)\n\tapp.RegisterRoute(\"PUT\", \"/api/users\", users.Any)",
+		"// DELETE /api/users (from app/api/users/route_any.go)\n\tapp.RegisterRoute(\"DELETE\", \"/api/users\", users.Any)",
+		"// DELETE /posts (from app/posts/route_delete.go)",
+		"// PATCH /api/orders (from app/api/orders/route_patch.go)",
+		`app.RouteTree().SetRouteConfig("/api/users", users.Config)`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(contentStr, exp) {
+			t.Errorf("expected generated file to contain %q\n%s", exp, contentStr)
+		}
+	}
+	if strings.Contains(contentStr, `app.RegisterRoute("GET", "/posts"`) {
+		t.Errorf("expected page.templ to take GET /posts from route_get.go\n%s", contentStr)
+	}
+	if strings.Contains(contentStr, `users.Put`) {
+		t.Errorf("expected handlers outside route files to be ignored\n%s", contentStr)
+	}
+
+	if strings.Count(contentStr, `"testmodule/app/api/users"`) != 1 {
+		t.Errorf("expected the users route files to share one import\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesPageWithAnyHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	files := map[string]string{
+		"contact/page.templ": "package contact\n\ntempl Page() {\n\t<form method=\"post\"></form>\n}\n",
+		"contact/route.go":   "package contact\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Any(c *nexo.Context) error {\n\treturn nil\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	if strings.Contains(contentStr, `app.RegisterRoute("GET", "/contact", contact.Any)`) {
+		t.Errorf("expected the page to take GET from Any\n%s", contentStr)
+	}
+	if !strings.Contains(contentStr, `app.RegisterRoute("POST", "/contact", contact.Any)`) {
+		t.Errorf("expected Any to handle the other methods\n%s", contentStr)
+	}
+	if !strings.Contains(contentStr, `app.Get("/contact", func(c *nexo.Context) error {`) {
+		t.Errorf("expected the page to be registered\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesWithSockets(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	socket := func(pkg string) string {
+		return "package " + pkg + "\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Socket(c *nexo.Context, ws *nexo.WebSocketConn) error {\n\treturn nil\n}\n"
+	}
+	files := map[string]string{
+		"chat/[room]/ws.go":     socket("room"),
+		"chat/[room]/route.go":  "package room\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Post(c *nexo.Context) error {\n\treturn nil\n}\n",
+		"(live)/feed/ws.go":     socket("feed") + "\nvar SocketConfig = nexo.WebSocketConfig{MaxMessageSize: 1024}\n",
+		"home/ws.go":            socket("home"),
+		"home/page.templ":       "package home\n\ntempl Page() {\n\t<h1>Home</h1>\n}\n",
+		"broken/ws.go":          "package broken\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Socket(c *nexo.Context) error {\n\treturn nil\n}\n",
+		"api/events/ws.go":      socket("events"),
+		"api/events/route.go":   "package events\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn nil\n}\n",
+		"api/fallback/ws.go":    socket("fallback"),
+		"api/fallback/route.go": "package fallback\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Any(c *nexo.Context) error {\n\treturn nil\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	expected := []string{
+		"// WebSocket /chat/{room} (from app/chat/[room]/ws.go)\n\tapp.RegisterRoute(\"GET\", \"/chat/{room}\", nexo.SocketHandler(room.Socket))",
+		`app.RegisterRoute("POST", "/chat/{room}", room.Post)`,
+		`app.RegisterScopedRoute("GET", "/feed", "(live)/feed", nexo.SocketHandler(feed.Socket, feed.SocketConfig))`,
+		`app.RegisterRoute("GET", "/api/fallback", nexo.SocketHandler(fallback.Socket))`,
+		`app.RegisterRoute("POST", "/api/fallback", fallback.Any)`,
+		`app.RegisterRoute("GET", "/api/events", events.Get)`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(contentStr, exp) {
+			t.Errorf("expected generated file to contain %q\n%s", exp, contentStr)
+		}
+	}
+
+	unexpected := []string{
+		"home.Socket",                          // conflicts with page.templ
+		"events.Socket",                        // conflicts with Get()
+		"broken.Socket",                        // invalid signature
+		`"GET", "/api/fallback", fallback.Any`, // Socket takes GET from Any
+	}
+	for _, exp := range unexpected {
+		if strings.Contains(contentStr, exp) {
+			t.Errorf("expected generated file not to contain %q\n%s", exp, contentStr)
+		}
+	}
+
+	if strings.Count(contentStr, `"testmodule/.nexo/imports/app_chat_room"`) != 1 {
+		t.Errorf("expected ws.go and route.go to share one import\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesWithStreams(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	stream := func(pkg string) string {
+		return "package " + pkg + "\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Stream(c *nexo.Context, sse *nexo.SSEWriter) error {\n\treturn nil\n}\n"
+	}
+	files := map[string]string{
+		"orders/[id]/sse.go":   stream("id") + "\nvar StreamConfig = nexo.SSEConfig{Retry: time.Second}\n",
+		"orders/[id]/route.go": "package id\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Delete(c *nexo.Context) error {\n\treturn nil\n}\n",
+		"(admin)/logs/sse.go":  stream("logs"),
+		"chat/sse.go":          stream("chat"),
+		"chat/ws.go":           "package chat\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Socket(c *nexo.Context, ws *nexo.WebSocketConn) error {\n\treturn nil\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	expected := []string{
+		"// Server-Sent Events /orders/{id} (from app/orders/[id]/sse.go)\n\tapp.RegisterRoute(\"GET\", \"/orders/{id}\", nexo.StreamHandler(id.Stream, id.StreamConfig))",
+		`app.RegisterRoute("DELETE", "/orders/{id}", id.Delete)`,
+		`app.RegisterScopedRoute("GET", "/logs", "(admin)/logs", nexo.StreamHandler(logs.Stream))`,
+		`app.RegisterRoute("GET", "/chat", nexo.SocketHandler(chat.Socket))`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(contentStr, exp) {
+			t.Errorf("expected generated file to contain %q\n%s", exp, contentStr)
+		}
+	}
+	if strings.Contains(contentStr, "chat.Stream") {
+		t.Errorf("expected the sse.go next to a ws.go to be skipped\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesWithActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	action := func(pkg string, funcs ...string) string {
+		src := "package " + pkg + "\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n"
+		for _, fn := range funcs {
+			src += "\nfunc " + fn + "(c *nexo.Context) error {\n\treturn nil\n}\n"
+		}
+		return src
+	}
+	files := map[string]string{
+		"contact/page.templ":     "package contact\n\ntempl Page() {\n\t<form method=\"post\"></form>\n}\n",
+		"contact/action.go":      action("contact", "ActionDelete", "Action", "helper"),
+		"contact/route.go":       action("contact", "Any"),
+		"(shop)/cart/page.templ": "package cart\n\ntempl Page() {\n\t<h1>Cart</h1>\n}\n",
+		"(shop)/cart/action.go":  action("cart", "Action"),
+		"orphan/action.go":       action("orphan", "Action"),
+		"subscribe/page.templ":   "package subscribe\n\ntempl Page() {\n\t<h1>Subscribe</h1>\n}\n",
+		"subscribe/action.go":    action("subscribe", "Action"),
+		"subscribe/route.go":     action("subscribe", "Post"),
+		"invalid/page.templ":     "package invalid\n\ntempl Page() {\n\t<h1>Invalid</h1>\n}\n",
+		"invalid/action.go":      "package invalid\n\nfunc Action() error {\n\treturn nil\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	expected := []string{
+		"// Actions: POST /contact (from app/contact/action.go)\n\tapp.Post(\"/contact\", app.ActionHandler(\"/contact\", nexo.Actions{\n\t\t\"\": contact.Action,\n\t\t\"delete\": contact.ActionDelete,\n\t}))",
+		`app.RegisterScopedRoute("POST", "/cart", "(shop)/cart", app.ActionHandler("/cart", nexo.Actions{`,
+		`app.RegisterRoute("PUT", "/contact", contact.Any)`,
+		`app.RegisterRoute("POST", "/subscribe", subscribe.Post)`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(contentStr, exp) {
+			t.Errorf("expected generated file to contain %q\n%s", exp, contentStr)
+		}
+	}
+	unexpected := []string{
+		`app.RegisterRoute("POST", "/contact", contact.Any)`, // Any gives POST to the actions
+		"contact.helper",
+		"orphan.Action",    // No page to render
+		"subscribe.Action", // Post handler conflict
+		"invalid.Action",   // Invalid signature
+	}
+	for _, exp := range unexpected {
+		if strings.Contains(contentStr, exp) {
+			t.Errorf("expected generated file not to contain %q\n%s", exp, contentStr)
+		}
+	}
+}
+
+func TestScanAndGenerateRoutesWithSlots(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	files := map[string]string{
+		"dashboard/layout.templ":          "package dashboard\n\ntempl Layout() {\n\t{ children... }\n\t@nexo.Slot(\"analytics\")\n\t@nexo.Slot(\"team\")\n}\n",
+		"dashboard/page.templ":            "package dashboard\n\ntempl Page() {\n\t<h1>Dashboard</h1>\n}\n",
+		"dashboard/@team/page.templ":      "package team\n\ntempl Page() {\n\t<h2>Team</h2>\n}\n",
+		"dashboard/@analytics/page.templ": "package analytics\n\ntempl Page(data Stats) {\n\t<h2>Analytics</h2>\n}\n",
+		"dashboard/@analytics/loader.go": `package analytics
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+type Stats struct{ Visits int }
+
+func Loader(c *nexo.Context) (Stats, error) {
+	return Stats{}, nil
+}
+`,
+		"projects/[id]/@activity/page.templ": "package activity\n\ntempl Page(id string) {\n\t<h2>Activity</h2>\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		`"github.com/a-h/templ"`,
+		`app.Renderer().SetSlot("/dashboard", "team", func(c *nexo.Context) (templ.Component, error) {
+		return team_page.Page(), nil
+	})`,
+		`app.Renderer().SetSlot("/dashboard", "analytics", func(c *nexo.Context) (templ.Component, error) {
+		data, err := analytics_page.Loader(c)`,
+		`app.Renderer().SetSlot("/projects/{id}", "activity", func(c *nexo.Context) (templ.Component, error) {
+		id := c.Param("id")
+		return activity_page.Page(id), nil`,
+		`app.Get("/dashboard", func(c *nexo.Context) error {`,
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("generated file missing %q\n%s", want, contentStr)
+		}
+	}
+
+	// Slot pages aren't routes of their own
+	if n := strings.Count(contentStr, "app.Get("); n != 1 {
+		t.Errorf("expected only the dashboard page to be registered, got %d pages\n%s", n, contentStr)
+	}
+}
+
+func TestSlotName(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"app/dashboard/@analytics", "analytics"},
+		{"app/dashboard/@analytics/settings", "analytics"},
+		{"app/(admin)/@team", "team"},
+		{"app/dashboard", ""},
+		{"app", ""},
+	}
+	for _, tt := range tests {
+		if got := slotName(tt.dir, "app"); got != tt.want {
+			t.Errorf("slotName(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestScanAndGenerateRoutesWithRouteGroupLayoutsAndMiddleware(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+	appDir := filepath.Join(tmpDir, "app")
+
+	middleware := func(pkg string) string {
+		return "package " + pkg + "\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Middleware(next nexo.HandlerFunc) nexo.HandlerFunc {\n\treturn next\n}\n"
+	}
+	files := map[string]string{
+		"(marketing)/layout.templ":         "package marketing\n\ntempl Layout(title string) {\n\t<title>{ title }</title>\n\t{ children... }\n}\n",
+		"(marketing)/middleware.go":        middleware("marketing"),
+		"(marketing)/about/page.templ":     "package about\n\ntempl Page() {\n\t<h1>About</h1>\n}\n",
+		"(shop)/layout.templ":              "package shop\n\ntempl Layout() {\n\t<nav>Shop</nav>\n\t{ children... }\n}\n",
+		"(shop)/middleware.go":             middleware("shop"),
+		"(shop)/page.templ":                "package shop\n\ntempl Page() {\n\t@Layout() {\n\t\t<h1>Shop</h1>\n\t}\n}\n",
+		"(shop)/products/[id]/page.templ":  "package id\n\ntempl Page(id string) {\n\t<h1>Product</h1>\n}\n",
+		"(shop)/(account)/layout.templ":    "package account\n\ntempl Layout() {\n\t{ children... }\n}\n",
+		"(shop)/(account)/orders/route.go": "package orders\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn c.JSON(200, nil)\n}\n",
+		"(shop)/(account)/cart/page.templ": "package cart\n\ntempl Page() {\n\t<h1>Cart</h1>\n}\n",
+		"blog/page.templ":                  "package blog\n\ntempl Page() {\n\t<h1>Blog</h1>\n}\n",
+		"api/health/route.go":              "package health\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn c.JSON(200, nil)\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		// Group middleware only applies to the group
+		`app.RouteTree().AddMiddleware("/", "(marketing)", marketing.Middleware)`,
+		`app.RouteTree().AddMiddleware("/", "(shop)", shop.Middleware)`,
+		// Group routes and pages carry their scope
+		`app.RegisterScopedRoute("GET", "/orders", "(shop)/(account)/orders", orders.Get)`,
+		`app.RegisterScopedRoute("GET", "/about", "(marketing)/about", func(c *nexo.Context) error {`,
+		// Pages are wrapped in the layouts of the groups above them, which
+		// share the import of the group's middleware
+		`nexo.WithLayout(marketing.Layout("About"), about_page.Page())`,
+		`nexo.WithLayout(shop.Layout(), id_page.Page(id))`,
+		`nexo.WithLayout(shop.Layout(), nexo.WithLayout(account_page.Layout(), cart_page.Page()))`,
+		// Pages outside groups are unchanged
+		`app.Get("/blog", func(c *nexo.Context) error {
+		return nexo.TemplComponent(c, 200, blog_page.Page())`,
+		`app.RegisterRoute("GET", "/api/health", health.Get)`,
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("generated file missing %q\n%s", want, contentStr)
+		}
+	}
+
+	// A page in the group directory calls the layout itself
+	if !strings.Contains(contentStr, `nexo.TemplComponent(c, 200, shop.Page())`) {
+		t.Errorf("expected the group's own page not to be wrapped again\n%s", contentStr)
+	}
+}
+
+func TestScanAndGenerateRoutesCompilesConstrainedDirs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go build in short mode")
+	}
+
+	repoRoot, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(repoRoot, "go.sum"))
+	if err != nil {
+		t.Fatalf("Failed to read go.sum: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                                "module testmodule\n\ngo 1.25\n\nrequire github.com/abdul-hamid-achik/nexo v0.0.0\n\nreplace github.com/abdul-hamid-achik/nexo => " + repoRoot + "\n",
+		"go.sum":                                string(goSum),
+		"main.go":                               "package main\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc main() {\n\tRegisterRoutes(nexo.New())\n}\n",
+		"app/api/users/[id:int]/route.go":       "package id\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn c.JSON(200, nil)\n}\n",
+		"app/api/users/[id:int]/posts/route.go": "package posts\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn c.JSON(200, nil)\n}\n",
+		"app/(admin)/settings/route.go":         "package settings\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Post(c *nexo.Context) error {\n\treturn c.NoContent()\n}\n",
+		"app/docs/[...slug]/route.go":           "package slug\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn c.JSON(200, nil)\n}\n",
+		"app/api/health/route.go":               "package health\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn c.JSON(200, nil)\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	if _, err := ScanAndGenerateRoutes("app", "nexo_routes.go"); err != nil {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v", err)
+	}
+
+	content, err := os.ReadFile("nexo_routes.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	for _, want := range []string{
+		`"testmodule/.nexo/imports/app_api_users_id_int"`,
+		`"testmodule/.nexo/imports/app_api_users_id_int_posts"`,
+		`"testmodule/app/api/health"`,
+		`"/api/users/{id:-?[0-9]+}"`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("generated file missing %q\n%s", want, content)
+		}
+	}
+
+	cmd := exec.Command("go", "build", "-o", os.DevNull, ".")
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s\n%s", err, out, content)
+	}
+}
+
+func TestScanAndGenerateRoutesRejectsAliasCollisions(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"app/[a]/b", "app/[a_b]"} {
+		path := filepath.Join(tmpDir, dir)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("Failed to create directory %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "route.go"), []byte("package b\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn nil\n}\n"), 0644); err != nil {
+			t.Fatalf("Failed to write route.go in %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module testmodule\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	_, err := ScanAndGenerateRoutes("app", "nexo_routes.go")
+	if err == nil || !strings.Contains(err.Error(), "rename one of them") {
+		t.Fatalf("ScanAndGenerateRoutes() error = %v, want an alias collision", err)
+	}
+	if _, err := os.Stat("nexo_routes.go"); !os.IsNotExist(err) {
+		t.Errorf("expected no generated file, got err = %v", err)
+	}
+}
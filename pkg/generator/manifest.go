@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManifestPath is the default location of the generation manifest.
+const ManifestPath = ".nexo/generated.json"
+
+// Manifest records the files created by each generation run so the last
+// run can be undone.
+type Manifest struct {
+	Runs []ManifestRun `json:"runs"`
+}
+
+// ManifestRun is a single generation run.
+type ManifestRun struct {
+	Command   string         `json:"command"`
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a file created by a generation run.
+type ManifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// LoadManifest reads the manifest at path. A missing manifest is empty.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Save writes the manifest to path.
+func (m *Manifest) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// RecordRun appends a generation run with the given files to the manifest at path.
+func RecordRun(path, command string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	m, err := LoadManifest(path)
+	if err != nil {
+		return err
+	}
+
+	run := ManifestRun{
+		Command:   command,
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, f := range files {
+		sum, err := fileSHA256(f)
+		if err != nil {
+			return err
+		}
+		run.Files = append(run.Files, ManifestFile{Path: f, SHA256: sum})
+	}
+
+	m.Runs = append(m.Runs, run)
+	return m.Save(path)
+}
+
+// UndoLastRun removes the files created by the most recent generation run
+// recorded in the manifest at path. It refuses to remove anything if one of
+// the files has been modified since it was generated. Files that no longer
+// exist are skipped. Directories left empty are removed as well.
+func UndoLastRun(path string) (*ManifestRun, error) {
+	m, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Runs) == 0 {
+		return nil, fmt.Errorf("nothing to undo: no generation runs recorded in %s", path)
+	}
+
+	run := m.Runs[len(m.Runs)-1]
+
+	// Check every file first so the undo is all-or-nothing
+	var modified []string
+	for _, f := range run.Files {
+		sum, err := fileSHA256(f.Path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sum != f.SHA256 {
+			modified = append(modified, f.Path)
+		}
+	}
+	if len(modified) > 0 {
+		return nil, fmt.Errorf("refusing to undo %q: files modified since generation: %s", run.Command, strings.Join(modified, ", "))
+	}
+
+	for _, f := range run.Files {
+		if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove %s: %w", f.Path, err)
+		}
+		removeEmptyParents(f.Path)
+	}
+
+	m.Runs = m.Runs[:len(m.Runs)-1]
+	if err := m.Save(path); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// removeEmptyParents removes the empty parent directories of a removed file,
+// stopping at the top-level directory of a relative path.
+func removeEmptyParents(filePath string) {
+	dir := filepath.Dir(filePath)
+	for dir != "." && dir != string(filepath.Separator) && filepath.Dir(dir) != "." {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndUndoLastRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, ".nexo", "generated.json")
+	appDir := filepath.Join(tmpDir, "app")
+
+	first, err := GenerateRoute(RouteConfig{Path: "users", Methods: []string{"GET"}, AppDir: appDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordRun(manifest, "generate route", first.Files); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+
+	second, err := GeneratePage(PageConfig{Path: "admin/settings", AppDir: appDir, WithLayout: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordRun(manifest, "generate page", second.Files); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+
+	run, err := UndoLastRun(manifest)
+	if err != nil {
+		t.Fatalf("UndoLastRun failed: %v", err)
+	}
+	if run.Command != "generate page" {
+		t.Errorf("Command = %q, want generate page", run.Command)
+	}
+	for _, f := range second.Files {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", f)
+		}
+	}
+	// Earlier runs are untouched
+	if _, err := os.Stat(first.Files[0]); err != nil {
+		t.Errorf("Expected %s to remain: %v", first.Files[0], err)
+	}
+
+	m, err := LoadManifest(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Runs) != 1 {
+		t.Errorf("Expected 1 run left in manifest, got %d", len(m.Runs))
+	}
+}
+
+func TestUndoLastRun_RefusesModifiedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, "generated.json")
+	appDir := filepath.Join(tmpDir, "app")
+
+	result, err := GenerateRoute(RouteConfig{Path: "orders", Methods: []string{"GET"}, AppDir: appDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordRun(manifest, "generate route", result.Files); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(result.Files[0], []byte("package orders\n// edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = UndoLastRun(manifest)
+	if err == nil || !strings.Contains(err.Error(), "modified") {
+		t.Fatalf("UndoLastRun error = %v, want modified error", err)
+	}
+	if _, err := os.Stat(result.Files[0]); err != nil {
+		t.Errorf("Expected modified file to remain: %v", err)
+	}
+}
+
+func TestUndoLastRun_NothingRecorded(t *testing.T) {
+	if _, err := UndoLastRun(filepath.Join(t.TempDir(), "generated.json")); err == nil {
+		t.Error("Expected error with empty manifest")
+	}
+}
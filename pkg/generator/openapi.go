@@ -0,0 +1,447 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// FromOpenAPIConfig holds configuration for generating routes from an OpenAPI document.
+type FromOpenAPIConfig struct {
+	SpecPath string // Path to the OpenAPI document (YAML or JSON)
+	AppDir   string // App directory (default: "app")
+}
+
+// openAPIOperation is a handler generated from an OpenAPI operation.
+type openAPIOperation struct {
+	FuncName     string // Handler name (e.g., "Get")
+	Method       string // HTTP method (e.g., "GET")
+	Pattern      string // Route pattern (e.g., "/users/{id}")
+	Summary      string
+	PathParams   []openAPIParam
+	QueryParams  []string
+	RequestType  string // Request struct name, empty if the operation has no JSON body
+	Validate     bool   // True if the request struct has a Validate method
+	ResponseType string // Response type, empty if the response has no JSON body
+	Status       int    // Success status code
+}
+
+// ResponseZero returns the zero value of the response type, e.g. "User{}"
+// or `""`.
+func (o openAPIOperation) ResponseZero() string {
+	return zeroValue(o.ResponseType)
+}
+
+// openAPIParam is a path parameter and the variable holding it.
+type openAPIParam struct {
+	Name string // Parameter name (e.g., "user-id")
+	Var  string // Go variable name (e.g., "userID")
+}
+
+// openAPIRouteData holds the data for the OpenAPI route template.
+type openAPIRouteData struct {
+	Package    string
+	NeedsFmt   bool     // True if a Validate method uses fmt
+	Types      []string // Rendered type declarations
+	Operations []openAPIOperation
+}
+
+// GenerateFromOpenAPI creates the app/ folder structure from an OpenAPI
+// document. Every path becomes a directory with a route.go containing one
+// handler per operation, typed request/response structs built from the JSON
+// schemas, and a Validate method enforcing the request schema constraints.
+// Path parameters map to dynamic segments, e.g. /users/{id} -> app/users/[id].
+func GenerateFromOpenAPI(cfg FromOpenAPIConfig) (*Result, error) {
+	if cfg.AppDir == "" {
+		cfg.AppDir = "app"
+	}
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile(cfg.SpecPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+	if doc.Paths == nil || doc.Paths.Len() == 0 {
+		return nil, fmt.Errorf("no paths found in %s", cfg.SpecPath)
+	}
+
+	paths := doc.Paths.Map()
+	specPaths := make([]string, 0, len(paths))
+	for p := range paths {
+		specPaths = append(specPaths, p)
+	}
+	sort.Strings(specPaths)
+
+	// Render everything first so nothing is written if any path fails
+	rendered := make(map[string][]byte, len(specPaths))
+	var files []string
+	for _, specPath := range specPaths {
+		dir := openAPIPathToDir(specPath)
+		filePath := filepath.Join(cfg.AppDir, dir, "route.go")
+		if _, err := os.Stat(filePath); err == nil {
+			return nil, fmt.Errorf("file already exists: %s", filePath)
+		}
+
+		src, err := renderOpenAPIRoute(dir, specPath, paths[specPath])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", specPath, err)
+		}
+		rendered[filePath] = src
+		files = append(files, filePath)
+	}
+
+	for _, filePath := range files {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(filePath, rendered[filePath], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+
+	return &Result{
+		Files: files,
+	}, nil
+}
+
+// openAPIPathToDir converts an OpenAPI path to a directory relative to the app directory.
+// e.g., "/users/{id}/posts" -> "users/[id]/posts"
+func openAPIPathToDir(specPath string) string {
+	var segments []string
+	for _, seg := range strings.Split(strings.Trim(specPath, "/"), "/") {
+		if seg == "" {
+			continue
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			seg = "[" + strings.Trim(seg, "{}") + "]"
+		}
+		segments = append(segments, seg)
+	}
+	return filepath.Join(segments...)
+}
+
+// renderOpenAPIRoute renders the route.go for one OpenAPI path.
+func renderOpenAPIRoute(dir, specPath string, item *openapi3.PathItem) ([]byte, error) {
+	pkgName := packageNameFromPath(filepath.ToSlash(dir))
+	if pkgName == "" {
+		pkgName = "app"
+	}
+
+	types := newOpenAPITypes()
+	data := openAPIRouteData{Package: pkgName}
+
+	ops := item.Operations()
+	methods := make([]string, 0, len(ops))
+	for m := range ops {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methodOrder(methods[i]) < methodOrder(methods[j])
+	})
+
+	for _, method := range methods {
+		op := ops[method]
+		funcName := toTitleCase(method)
+		typePrefix := funcName
+		if op.OperationID != "" {
+			typePrefix = goIdent(op.OperationID)
+		}
+
+		operation := openAPIOperation{
+			FuncName: funcName,
+			Method:   method,
+			Pattern:  specPath,
+			Summary:  strings.TrimSpace(op.Summary),
+			Status:   http.StatusOK,
+		}
+
+		for _, p := range append(item.Parameters, op.Parameters...) {
+			if p.Value == nil {
+				continue
+			}
+			switch p.Value.In {
+			case openapi3.ParameterInPath:
+				if !slices.ContainsFunc(operation.PathParams, func(pp openAPIParam) bool { return pp.Name == p.Value.Name }) {
+					operation.PathParams = append(operation.PathParams, openAPIParam{Name: p.Value.Name, Var: goVarName(p.Value.Name)})
+				}
+			case openapi3.ParameterInQuery:
+				operation.QueryParams = appendUnique(operation.QueryParams, p.Value.Name)
+			}
+		}
+
+		if op.RequestBody != nil && op.RequestBody.Value != nil {
+			if media := op.RequestBody.Value.Content.Get("application/json"); media != nil && media.Schema != nil {
+				name := typePrefix + "Request"
+				operation.RequestType = types.goType(media.Schema, name, true)
+				operation.Validate = types.hasValidate(operation.RequestType)
+			}
+		}
+
+		status, resp := successResponse(op)
+		operation.Status = status
+		if resp != nil {
+			if media := resp.Content.Get("application/json"); media != nil && media.Schema != nil {
+				operation.ResponseType = types.goType(media.Schema, typePrefix+"Response", false)
+			}
+		}
+
+		data.Operations = append(data.Operations, operation)
+	}
+	data.Types = types.decls
+	data.NeedsFmt = len(types.validated) > 0
+
+	tmpl, err := template.New("route.go").Parse(openAPIRouteTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// successResponse returns the first 2xx status code of an operation and its response.
+func successResponse(op *openapi3.Operation) (int, *openapi3.Response) {
+	if op.Responses == nil {
+		return http.StatusOK, nil
+	}
+	codes := make([]string, 0, op.Responses.Len())
+	for code := range op.Responses.Map() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 200 || status > 299 {
+			continue
+		}
+		return status, op.Responses.Value(code).Value
+	}
+	return http.StatusOK, nil
+}
+
+// methodOrder sorts HTTP methods in their conventional order.
+func methodOrder(method string) int {
+	for i, m := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"} {
+		if m == method {
+			return i
+		}
+	}
+	return 100
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// goIdent converts an OpenAPI name to an exported Go identifier.
+// e.g., "createUser" -> "CreateUser", "first_name" -> "FirstName", "user-id" -> "UserID"
+func goIdent(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(toGoFieldName(p))
+	}
+	ident := b.String()
+	if ident == "" {
+		return "Value"
+	}
+	if ident[0] >= '0' && ident[0] <= '9' {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// goVarName converts an OpenAPI name to an unexported Go identifier that
+// doesn't clash with keywords, predeclared names or the handler's names.
+// e.g., "id" -> "id", "user-id" -> "userID", "type" -> "typeParam"
+func goVarName(name string) string {
+	ident := goIdent(name)
+	// Lower the leading capitals, keeping the last one of an initialism
+	// that starts a word: "ID" -> "id", "URLPath" -> "urlPath"
+	n := 0
+	for n < len(ident) && ident[n] >= 'A' && ident[n] <= 'Z' {
+		n++
+	}
+	if n > 1 && n < len(ident) {
+		n--
+	}
+	ident = strings.ToLower(ident[:n]) + ident[n:]
+	switch {
+	case token.IsKeyword(ident), types.Universe.Lookup(ident) != nil:
+	case ident == "c", ident == "req", ident == "nexo", ident == "fmt":
+	default:
+		return ident
+	}
+	return ident + "Param"
+}
+
+// openAPITypes collects the Go type declarations needed by one route file.
+type openAPITypes struct {
+	source    string // What the types are generated from, used in doc comments
+	decls     []string
+	defined   map[string]bool
+	validated map[string]bool
+}
+
+func newOpenAPITypes() *openAPITypes {
+	return &openAPITypes{
+		source:    "OpenAPI schema",
+		defined:   make(map[string]bool),
+		validated: make(map[string]bool),
+	}
+}
+
+func (t *openAPITypes) hasValidate(name string) bool {
+	return t.validated[name]
+}
+
+// goType returns the Go type for a schema, declaring struct types as needed.
+// Objects are declared using the $ref name when available, otherwise name.
+// When validate is true, a Validate method is generated for declared structs.
+func (t *openAPITypes) goType(ref *openapi3.SchemaRef, name string, validate bool) string {
+	if ref == nil || ref.Value == nil {
+		return "any"
+	}
+	if ref.Ref != "" {
+		name = goIdent(ref.Ref[strings.LastIndex(ref.Ref, "/")+1:])
+	}
+	schema := ref.Value
+
+	switch {
+	case schema.Type.Is("string"):
+		return "string"
+	case schema.Type.Is("integer"):
+		if schema.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case schema.Type.Is("number"):
+		return "float64"
+	case schema.Type.Is("boolean"):
+		return "bool"
+	case schema.Type.Is("array"):
+		return "[]" + t.goType(schema.Items, name+"Item", false)
+	case len(schema.Properties) > 0:
+		t.declareStruct(name, schema, validate)
+		return name
+	case schema.Type.Is("object"):
+		return "map[string]any"
+	}
+	return "any"
+}
+
+// declareStruct adds a struct declaration (and optionally its Validate method) for an object schema.
+func (t *openAPITypes) declareStruct(name string, schema *openapi3.Schema, validate bool) {
+	if t.defined[name] {
+		return
+	}
+	t.defined[name] = true
+
+	props := make([]string, 0, len(schema.Properties))
+	for p := range schema.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	var decl, checks strings.Builder
+	if schema.Description != "" {
+		lines := strings.Split(strings.TrimSpace(schema.Description), "\n")
+		lines[0] = name + " " + lines[0]
+		for _, line := range lines {
+			decl.WriteString(strings.TrimRight("// "+line, " \t\r") + "\n")
+		}
+	} else {
+		fmt.Fprintf(&decl, "// %s is generated from the %s.\n", name, t.source)
+	}
+	fmt.Fprintf(&decl, "type %s struct {\n", name)
+	for _, p := range props {
+		fieldName := goIdent(p)
+		fieldType := t.goType(schema.Properties[p], name+fieldName, false)
+		tag := p
+		if !required[p] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&decl, "\t%s %s `json:%q`\n", fieldName, fieldType, tag)
+
+		if validate {
+			writeValidation(&checks, p, fieldName, fieldType, schema.Properties[p].Value, required[p])
+		}
+	}
+	decl.WriteString("}\n")
+
+	if validate && checks.Len() > 0 {
+		t.validated[name] = true
+		fmt.Fprintf(&decl, "\n// Validate checks the request against the %s constraints.\n", t.source)
+		fmt.Fprintf(&decl, "func (r %s) Validate() error {\n%s\treturn nil\n}\n", name, checks.String())
+	}
+
+	t.decls = append(t.decls, decl.String())
+}
+
+// writeValidation writes the checks for one property of a request struct.
+func writeValidation(b *strings.Builder, prop, field, fieldType string, schema *openapi3.Schema, required bool) {
+	if schema == nil {
+		return
+	}
+	switch {
+	case fieldType == "string":
+		if required {
+			fmt.Fprintf(b, "\tif r.%s == \"\" {\n\t\treturn fmt.Errorf(\"%s is required\")\n\t}\n", field, prop)
+		}
+		if schema.MinLength > 0 {
+			fmt.Fprintf(b, "\tif r.%s != \"\" && len(r.%s) < %d {\n\t\treturn fmt.Errorf(\"%s must be at least %d characters\")\n\t}\n", field, field, schema.MinLength, prop, schema.MinLength)
+		}
+		if schema.MaxLength != nil {
+			fmt.Fprintf(b, "\tif len(r.%s) > %d {\n\t\treturn fmt.Errorf(\"%s must be at most %d characters\")\n\t}\n", field, *schema.MaxLength, prop, *schema.MaxLength)
+		}
+		if len(schema.Enum) > 0 {
+			var values []string
+			for _, e := range schema.Enum {
+				values = append(values, fmt.Sprintf("%q", fmt.Sprint(e)))
+			}
+			fmt.Fprintf(b, "\tswitch r.%s {\n\tcase \"\", %s:\n\tdefault:\n\t\treturn fmt.Errorf(\"%s must be one of %s\")\n\t}\n",
+				field, strings.Join(values, ", "), prop, strings.ReplaceAll(strings.Join(values, ", "), `"`, ""))
+		}
+	case fieldType == "int" || fieldType == "int64" || fieldType == "float64":
+		if schema.Min != nil {
+			fmt.Fprintf(b, "\tif r.%s < %v {\n\t\treturn fmt.Errorf(\"%s must be at least %v\")\n\t}\n", field, *schema.Min, prop, *schema.Min)
+		}
+		if schema.Max != nil {
+			fmt.Fprintf(b, "\tif r.%s > %v {\n\t\treturn fmt.Errorf(\"%s must be at most %v\")\n\t}\n", field, *schema.Max, prop, *schema.Max)
+		}
+	case strings.HasPrefix(fieldType, "[]") || strings.HasPrefix(fieldType, "map["):
+		if required {
+			fmt.Fprintf(b, "\tif len(r.%s) == 0 {\n\t\treturn fmt.Errorf(\"%s is required\")\n\t}\n", field, prop)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testOpenAPISpec = `openapi: 3.0.3
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      summary: List users
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, email]
+              properties:
+                name:
+                  type: string
+                  minLength: 2
+                  maxLength: 50
+                email:
+                  type: string
+                role:
+                  type: string
+                  enum: [admin, member]
+                age:
+                  type: integer
+                  minimum: 0
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    delete:
+      responses:
+        "204":
+          description: Deleted
+  /kinds/{user-id}/{type}:
+    get:
+      parameters:
+        - name: user-id
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: type
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: string
+components:
+  schemas:
+    User:
+      type: object
+      description: |
+        A registered user.
+
+        Users sign up with an email.
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string
+`
+
+func TestGenerateFromOpenAPI(t *testing.T) {
+	tmpDir := t.TempDir()
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(testOpenAPISpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	appDir := filepath.Join(tmpDir, "app")
+
+	result, err := GenerateFromOpenAPI(FromOpenAPIConfig{SpecPath: specPath, AppDir: appDir})
+	if err != nil {
+		t.Fatalf("GenerateFromOpenAPI failed: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(appDir, "kinds", "[user-id]", "[type]", "route.go"),
+		filepath.Join(appDir, "users", "route.go"),
+		filepath.Join(appDir, "users", "[id]", "route.go"),
+	}
+	if !slices.Equal(result.Files, want) {
+		t.Fatalf("Files = %v, want %v", result.Files, want)
+	}
+
+	// Generated files must be valid Go
+	for _, f := range result.Files {
+		if _, err := parser.ParseFile(token.NewFileSet(), f, nil, 0); err != nil {
+			t.Errorf("generated %s does not parse: %v", f, err)
+		}
+	}
+
+	kinds, _ := os.ReadFile(want[0])
+	for _, s := range []string{
+		"package pkgtype",
+		`userID := c.Param("user-id")`,
+		`typeParam := c.Param("type")`,
+		`return c.JSON(200, "")`,
+	} {
+		if !strings.Contains(string(kinds), s) {
+			t.Errorf("Expected %q in kinds/[user-id]/[type]/route.go, got:\n%s", s, kinds)
+		}
+	}
+
+	users, _ := os.ReadFile(want[1])
+	for _, s := range []string{
+		"// User A registered user.\n//\n// Users sign up with an email.\ntype User struct",
+		"type CreateUserRequest struct",
+		"Name  string `json:\"name\"`",
+		"Role  string `json:\"role,omitempty\"`",
+		"type User struct",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"func (r CreateUserRequest) Validate() error",
+		`return fmt.Errorf("name is required")`,
+		"len(r.Name) > 50",
+		`case "", "admin", "member":`,
+		"if r.Age < 0 {",
+		"if err := req.Validate(); err != nil {",
+		"return c.JSON(201, User{})",
+		"return c.JSON(200, []User{})",
+		`// Query parameter: c.Query("limit")`,
+	} {
+		if !strings.Contains(string(users), s) {
+			t.Errorf("Expected %q in users/route.go, got:\n%s", s, users)
+		}
+	}
+
+	byID, _ := os.ReadFile(want[2])
+	for _, s := range []string{"package id", `id := c.Param("id")`, "func Delete(", "return c.NoContent()"} {
+		if !strings.Contains(string(byID), s) {
+			t.Errorf("Expected %q in users/[id]/route.go, got:\n%s", s, byID)
+		}
+	}
+	if strings.Contains(string(byID), `"fmt"`) {
+		t.Error("Expected no fmt import without validation")
+	}
+
+	// Existing files are not overwritten
+	if _, err := GenerateFromOpenAPI(FromOpenAPIConfig{SpecPath: specPath, AppDir: appDir}); err == nil {
+		t.Error("Expected error when route files already exist")
+	}
+}
+
+func TestOpenAPIPathToDir(t *testing.T) {
+	tests := map[string]string{
+		"/":                      "",
+		"/users":                 "users",
+		"/users/{id}":            filepath.Join("users", "[id]"),
+		"/users/{userId}/posts/": filepath.Join("users", "[userId]", "posts"),
+	}
+	for in, want := range tests {
+		if got := openAPIPathToDir(in); got != want {
+			t.Errorf("openAPIPathToDir(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	tests := map[string]string{
+		"createUser": "CreateUser",
+		"first_name": "FirstName",
+		"user-id":    "UserID",
+		"id":         "ID",
+		"2fa":        "X2fa",
+	}
+	for in, want := range tests {
+		if got := goIdent(in); got != want {
+			t.Errorf("goIdent(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGoVarName(t *testing.T) {
+	tests := map[string]string{
+		"id":       "id",
+		"user-id":  "userID",
+		"userId":   "userID",
+		"URLPath":  "urlPath",
+		"type":     "typeParam",
+		"nil":      "nilParam",
+		"c":        "cParam",
+		"req":      "reqParam",
+		"page_num": "pageNum",
+	}
+	for in, want := range tests {
+		if got := goVarName(in); got != want {
+			t.Errorf("goVarName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
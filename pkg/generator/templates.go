@@ -31,7 +31,11 @@ type pageTemplateData struct {
 var routeTemplate = `package {{.Package}}
 
 import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
-{{range .Methods}}
+` + routeHandlersTemplate
+
+// routeHandlersTemplate renders only the handler functions; used on its own
+// when appending handlers to an existing route.go.
+var routeHandlersTemplate = `{{range .Methods}}
 // {{.FuncName}} handles {{.Method}} /api/{{$.Pattern}}
 func {{.FuncName}}(c *nexo.Context) error {
 {{- range $.Params}}
@@ -276,8 +280,8 @@ func Proxy(c *nexo.Context) (*nexo.ProxyResult, error) {
 	"rate-limit": `package app
 
 import (
-	"fmt"
-	"sync"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
@@ -285,49 +289,36 @@ import (
 
 // Rate limit configuration
 var (
-	rateLimitMu sync.Mutex
-	requests    = make(map[string][]time.Time)
-	maxRequests = 100           // Maximum requests per window
-	window      = time.Minute   // Time window
+	rateLimitStore = nexo.NewMemoryRateLimitStore()
+	rateLimitRule  = nexo.RateLimitRule{
+		Limit:  100,         // Maximum requests per window
+		Window: time.Minute, // Time window
+	}
 )
 
-// Proxy implements simple IP-based rate limiting.
+// Proxy implements IP-based rate limiting before routing. For limits on
+// specific routes, use the nexo.RateLimit middleware instead.
 func Proxy(c *nexo.Context) (*nexo.ProxyResult, error) {
 	ip := c.ClientIP()
-
-	rateLimitMu.Lock()
-	defer rateLimitMu.Unlock()
-
-	now := time.Now()
-	windowStart := now.Add(-window)
-
-	// Clean old requests and count recent ones
-	var recent []time.Time
-	for _, t := range requests[ip] {
-		if t.After(windowStart) {
-			recent = append(recent, t)
-		}
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
 	}
 
-	// Check if rate limit exceeded
-	if len(recent) >= maxRequests {
-		retryAfter := recent[0].Add(window).Sub(now)
-		c.SetHeader("Retry-After", retryAfter.String())
-		c.SetHeader("X-RateLimit-Limit", fmt.Sprintf("%d", maxRequests))
-		c.SetHeader("X-RateLimit-Remaining", "0")
-		
-		return nexo.ResponseJSON(429, map[string]string{
-			"error":   "too_many_requests",
-			"message": "Rate limit exceeded. Please try again later.",
-		}), nil
+	result, err := rateLimitStore.Take(c.Context(), ip, rateLimitRule)
+	if err != nil {
+		return nexo.Continue(), nil
 	}
 
-	// Record this request
-	requests[ip] = append(recent, now)
-
 	// Add rate limit headers
-	c.SetHeader("X-RateLimit-Limit", fmt.Sprintf("%d", maxRequests))
-	c.SetHeader("X-RateLimit-Remaining", fmt.Sprintf("%d", maxRequests-len(recent)-1))
+	c.SetHeader("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.SetHeader("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.SetHeader("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())+1))
+
+	// Check if rate limit exceeded
+	if !result.Allowed {
+		c.SetHeader("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())+1))
+		return nexo.ResponseJSON(429, "{\"error\":\"too_many_requests\",\"message\":\"Rate limit exceeded. Please try again later.\"}"), nil
+	}
 
 	return nexo.Continue(), nil
 }
@@ -336,7 +327,9 @@ func Proxy(c *nexo.Context) (*nexo.ProxyResult, error) {
 
 import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
 
-// Set to true to enable maintenance mode
+// Set to true to enable maintenance mode.
+// To switch it at runtime, e.g. from an admin endpoint or a signal, use
+// nexo.WithMaintenance and app.SetMaintenance instead of this proxy.
 var maintenanceMode = false
 
 // Allowed IPs during maintenance (e.g., admin IPs)
@@ -363,10 +356,7 @@ func Proxy(c *nexo.Context) (*nexo.ProxyResult, error) {
 	// Return maintenance response
 	c.SetHeader("Retry-After", "3600") // Suggest retry in 1 hour
 
-	return nexo.ResponseJSON(503, map[string]string{
-		"error":   "service_unavailable",
-		"message": "Service is under maintenance. Please try again later.",
-	}), nil
+	return nexo.ResponseJSON(503, "{\"error\":\"service_unavailable\",\"message\":\"Service is under maintenance. Please try again later.\"}"), nil
 }
 `,
 	"redirect-www": `package app
@@ -426,6 +416,9 @@ import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
 // {{.DataType}} holds the data for this page.
 // Add your data fields here.
 type {{.DataType}} struct {
+{{- range .Fields}}
+	{{.Name}} {{if .IsCatchAll}}[]string{{else}}string{{end}}
+{{- end}}
 	// TODO: Add your data fields
 	// Example:
 	// UserName string
@@ -446,7 +439,22 @@ func Loader(c *nexo.Context) ({{.DataType}}, error) {
 	//     return {{.DataType}}{}, nexo.NotFound("Resource not found")
 	// }
 
-	return {{.DataType}}{}, nil
+	return {{.DataType}}{
+{{- range .Fields}}
+		{{.Name}}: {{if .IsCatchAll}}c.ParamAll("{{.Param}}"){{else}}c.Param("{{.Param}}"){{end}},
+{{- end}}
+	}, nil
+}
+`
+
+// loaderPageTemplate is the page generated alongside a loader; it receives the loader data.
+var loaderPageTemplate = `package {{.Package}}
+
+templ Page(data {{.DataType}}) {
+	<main style="max-width: 800px; margin: 0 auto; padding: 2rem;">
+		<h1>{{.Title}}</h1>
+		<p>Edit this page at {{.FilePath}}</p>
+	</main>
 }
 `
 
@@ -488,6 +496,454 @@ templ Layout(title string) {
 }
 `
 
+// Migration templates (golang-migrate format)
+var migrationUpTemplate = `-- Migration: {{.Name}}
+-- Created at: {{.CreatedAt}}
+--
+-- Write the schema change here, e.g.:
+-- CREATE TABLE users (
+--     id SERIAL PRIMARY KEY,
+--     email TEXT NOT NULL UNIQUE,
+--     created_at TIMESTAMP NOT NULL DEFAULT NOW()
+-- );
+`
+
+var migrationDownTemplate = `-- Migration: {{.Name}}
+-- Created at: {{.CreatedAt}}
+--
+-- Revert the changes made in the matching .up.sql file, e.g.:
+-- DROP TABLE IF EXISTS users;
+`
+
+// Seed templates
+var seedRegistryTemplate = `// Package seeds holds the seed data for this project.
+// Run the seeds with 'nexo db seed'.
+package seeds
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Seed is a named function that inserts data into the database.
+// Seeds must be idempotent: running them twice must not duplicate data.
+type Seed struct {
+	Name string
+	Envs []string // Environments the seed runs in; empty means all
+	Run  func(ctx context.Context, db *sql.DB) error
+}
+
+var registry []Seed
+
+// Register adds a seed to the registry. Call it from an init function.
+func Register(s Seed) {
+	registry = append(registry, s)
+}
+
+// Run executes all seeds registered for env, in name order.
+func Run(ctx context.Context, db *sql.DB, env string) ([]string, error) {
+	seeds := slices.Clone(registry)
+	sort.Slice(seeds, func(i, j int) bool { return seeds[i].Name < seeds[j].Name })
+
+	var ran []string
+	for _, s := range seeds {
+		if len(s.Envs) > 0 && !slices.Contains(s.Envs, env) {
+			continue
+		}
+		if err := s.Run(ctx, db); err != nil {
+			return ran, fmt.Errorf("seed %s: %w", s.Name, err)
+		}
+		ran = append(ran, s.Name)
+	}
+	return ran, nil
+}
+`
+
+var seedMainTemplate = `// Command seed runs the project seeds. Use 'nexo db seed' to invoke it.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"{{.ModuleName}}/seeds"
+	// TODO: Import your database driver, e.g.:
+	// _ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func main() {
+	env := flag.String("env", "development", "Environment to seed")
+	flag.Parse()
+
+	driver := os.Getenv("DATABASE_DRIVER")
+	if driver == "" {
+		driver = "pgx"
+	}
+
+	db, err := sql.Open(driver, os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ran, err := seeds.Run(context.Background(), db, *env)
+	for _, name := range ran {
+		fmt.Printf("seeded %s\n", name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+`
+
+var seedTemplate = `package seeds
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	Register(Seed{
+		Name: "{{.Name}}",
+		Envs: []string{ {{- range $i, $e := .Envs}}{{if $i}}, {{end}}"{{$e}}"{{end -}} },
+		Run:  {{.FuncName}},
+	})
+}
+
+// {{.FuncName}} seeds {{.Name}} data.
+// Keep it idempotent so it can run on every 'nexo db seed'.
+func {{.FuncName}}(ctx context.Context, db *sql.DB) error {
+	// TODO: Insert your seed data, e.g.:
+	// _, err := db.ExecContext(ctx,
+	//     "INSERT INTO users (email) VALUES ($1) ON CONFLICT (email) DO NOTHING",
+	//     "admin@example.com",
+	// )
+	// return err
+	return nil
+}
+`
+
+// Form templates
+var formTemplate = `package {{.Package}}
+
+import (
+{{- if .HasEmail}}
+	"net/mail"
+{{- end}}
+	"strings"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// {{.FormType}} holds the submitted form values.
+type {{.FormType}} struct {
+{{- range .Fields}}
+	{{.GoName}} string
+{{- end}}
+}
+
+// {{.DataType}} is passed to the page and form components.
+type {{.DataType}} struct {
+	Form      {{.FormType}}
+	Errors    map[string]string // Validation errors keyed by field name
+	Submitted bool              // Set after a successful submission
+}
+
+// bind{{.FormType}} reads the form values from the request.
+func bind{{.FormType}}(c *nexo.Context) {{.FormType}} {
+	return {{.FormType}}{
+{{- range .Fields}}
+		{{.GoName}}: strings.TrimSpace(c.FormValue("{{.Name}}")),
+{{- end}}
+	}
+}
+
+// Validate checks the form values and returns errors keyed by field name.
+func (f {{.FormType}}) Validate() map[string]string {
+	errs := map[string]string{}
+{{- range .Fields}}
+	if f.{{.GoName}} == "" {
+		errs["{{.Name}}"] = "{{.Label}} is required"
+	}
+{{- if eq .InputType "email"}} else if _, err := mail.ParseAddress(f.{{.GoName}}); err != nil {
+		errs["{{.Name}}"] = "{{.Label}} must be a valid email address"
+	}
+{{- end}}
+{{- end}}
+	return errs
+}
+`
+
+var formLoaderTemplate = `package {{.Package}}
+
+import (
+	"net/http"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// Loader loads the form page. It consumes the flash cookie set by Post after
+// a successful submission so the success message is shown once.
+func Loader(c *nexo.Context) ({{.DataType}}, error) {
+	data := {{.DataType}}{
+		Submitted: c.Cookie("{{.FlashCookie}}") != "",
+	}
+	if data.Submitted {
+		c.SetCookie(&http.Cookie{Name: "{{.FlashCookie}}", Path: "{{.Pattern}}", MaxAge: -1})
+	}
+	return data, nil
+}
+`
+
+var formRouteTemplate = `package {{.Package}}
+
+import (
+	"net/http"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// Post handles POST {{.Pattern}}
+func Post(c *nexo.Context) error {
+	form := bind{{.FormType}}(c)
+
+	if errs := form.Validate(); len(errs) > 0 {
+		data := {{.DataType}}{Form: form, Errors: errs}
+		if c.IsHTMX() {
+			// htmx only swaps 2xx responses by default
+			return c.Render(http.StatusOK, Form(data))
+		}
+		return c.Render(http.StatusUnprocessableEntity, Page(data))
+	}
+
+	// TODO: Handle the submission (save to database, send email, ...)
+
+	if c.IsHTMX() {
+		return c.Render(http.StatusOK, Form({{.DataType}}{Submitted: true}))
+	}
+
+	// Post/Redirect/Get: flash the success message and redirect back to the form
+	c.SetCookie(&http.Cookie{
+		Name:     "{{.FlashCookie}}",
+		Value:    "submitted",
+		Path:     "{{.Pattern}}",
+		MaxAge:   60,
+		HttpOnly: true,
+	})
+	return c.Redirect("{{.Pattern}}", http.StatusSeeOther)
+}
+`
+
+var formPageTemplate = `package {{.Package}}
+
+templ Page(data {{.DataType}}) {
+	<main style="max-width: 800px; margin: 0 auto; padding: 2rem;">
+		<h1>{{.Title}}</h1>
+		@Form(data)
+	</main>
+}
+
+// Form renders the form; Post re-renders it with validation errors.
+templ Form(data {{.DataType}}) {
+	<form id="{{.FormID}}" method="POST" action="{{.Pattern}}" hx-post="{{.Pattern}}" hx-swap="outerHTML">
+		if data.Submitted {
+			<p class="flash flash-success" role="status">Thanks! Your submission was received.</p>
+		}
+{{- range .Fields}}
+		<div>
+			<label for="{{.Name}}">{{.Label}}</label>
+{{- if eq .InputType "textarea"}}
+			<textarea id="{{.Name}}" name="{{.Name}}" required>{ data.Form.{{.GoName}} }</textarea>
+{{- else}}
+			<input type="{{.InputType}}" id="{{.Name}}" name="{{.Name}}" value={ data.Form.{{.GoName}} } required/>
+{{- end}}
+			if data.Errors["{{.Name}}"] != "" {
+				<p class="error">{ data.Errors["{{.Name}}"] }</p>
+			}
+		</div>
+{{- end}}
+		<button type="submit">Submit</button>
+	</form>
+}
+`
+
+// OpenAPI route template
+var openAPIRouteTemplate = `package {{.Package}}
+
+{{if .NeedsFmt -}}
+import (
+	"fmt"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+{{- else -}}
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+{{- end}}
+{{range .Types}}
+{{.}}
+{{- end}}
+{{range .Operations}}
+// {{.FuncName}} handles {{.Method}} {{.Pattern}}
+{{- if .Summary}}
+// {{.Summary}}
+{{- end}}
+func {{.FuncName}}(c *nexo.Context) error {
+{{- range .PathParams}}
+	{{.Var}} := c.Param("{{.Name}}")
+	_ = {{.Var}} // TODO: use this parameter
+{{- end}}
+{{- range .QueryParams}}
+	// Query parameter: c.Query("{{.}}")
+{{- end}}
+{{- if .RequestType}}
+	var req {{.RequestType}}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+{{- if .Validate}}
+	if err := req.Validate(); err != nil {
+		return nexo.BadRequest(err.Error())
+	}
+{{- end}}
+{{- end}}
+{{- if or .PathParams .QueryParams .RequestType}}
+{{end}}
+	// TODO: Implement {{.FuncName}} handler
+{{- if eq .Status 204}}
+	return c.NoContent()
+{{- else if and .RequestType (eq .RequestType .ResponseType)}}
+	return c.JSON({{.Status}}, req)
+{{- else if .ResponseType}}
+	return c.JSON({{.Status}}, {{.ResponseZero}})
+{{- else}}
+	return c.JSON({{.Status}}, map[string]any{})
+{{- end}}
+}
+{{end}}`
+
+// E2E templates
+var e2eSetupTemplate = `//go:build e2e
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// newTestServer starts the app on a random port and stops it when the test ends.
+// Routes are registered from nexo_routes.go, which 'nexo dev' and 'nexo build'
+// keep up to date.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	app := nexo.New()
+	RegisterRoutes(app)
+	app.Mount()
+
+	srv := httptest.NewServer(app)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// get performs a GET request against the test server and returns the response and body.
+func get(t *testing.T, srv *httptest.Server, path string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := srv.Client().Get(srv.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s: reading body: %v", path, err)
+	}
+	return resp, string(body)
+}
+`
+
+var e2eTestTemplate = `//go:build e2e
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Run with: go test -tags e2e ./...
+
+func TestE2E_API(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, body := get(t, srv, "{{.APIPath}}")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET {{.APIPath}} status = %d, want %d\nbody: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("GET {{.APIPath}} Content-Type = %q, want application/json", ct)
+	}
+}
+{{- if .WithPage}}
+
+func TestE2E_HomePage(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, body := get(t, srv, "/")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET / status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("GET / Content-Type = %q, want text/html", ct)
+	}
+	if !strings.Contains(body, "<html") {
+		t.Error("GET / expected an HTML document")
+	}
+}
+{{- end}}
+`
+
+// Static embed template
+var staticEmbedTemplate = `// Code generated by nexo. DO NOT EDIT.
+
+//go:build {{.Tag}}
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+//go:embed all:{{.Dir}}
+var embeddedStatic embed.FS
+
+func init() {
+	sub, err := fs.Sub(embeddedStatic, {{printf "%q" .Dir}})
+	if err != nil {
+		panic(err)
+	}
+	nexo.EmbedStatic({{printf "%q" .Dir}}, sub)
+}
+`
+
 // Routes generation templates
 
 var emptyRoutesTemplate = `// Code generated by nexo. DO NOT EDIT.
@@ -512,6 +968,9 @@ var routesGenTemplate = `// Code generated by nexo. DO NOT EDIT.
 package main
 
 import (
+{{- if or .HasLoading .HasSlots}}
+	"github.com/a-h/templ"
+{{- end}}
 	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 {{range .Imports}}
 	{{.Alias}} "{{.Path}}"
@@ -530,40 +989,123 @@ func RegisterRoutes(app *nexo.App) {
 {{end}}
 {{- range .Middlewares}}
 	// Middleware for {{.PathPrefix}} (from {{.FilePath}})
-	app.RouteTree().AddMiddleware("{{.PathPrefix}}", {{.ImportAlias}}.Middleware)
+	{{- if .HasConfig}}
+	app.RouteTree().AddMiddlewareWithOptions("{{.PathPrefix}}", "{{.Scope}}", {{.ImportAlias}}.Middleware, {{.ImportAlias}}.Config)
+	{{- else}}
+	app.RouteTree().AddMiddleware("{{.PathPrefix}}", "{{.Scope}}", {{.ImportAlias}}.Middleware)
+	{{- end}}
+{{- end}}
+{{- range .Errors}}
+	// Error boundary for {{.PathPrefix}} (from {{.FilePath}})
+	app.Renderer().SetErrorComponent("{{.PathPrefix}}", {{.ImportAlias}}.Error)
+{{- end}}
+{{- range .Loadings}}
+	// Loading component for {{.PathPrefix}} (from {{.FilePath}})
+	app.Renderer().SetLoadingComponent("{{.PathPrefix}}", {{.ImportAlias}}.Loading())
+{{- end}}
+{{- range .NotFounds}}
+	// 404 page for {{.PathPrefix}} (from {{.FilePath}})
+{{- if eq .PathPrefix "/"}}
+	app.Renderer().SetNotFoundComponent({{.ImportAlias}}.NotFound())
+{{- else}}
+	app.Renderer().SetNotFoundComponentFor("{{.PathPrefix}}", {{.ImportAlias}}.NotFound())
+{{- end}}
+{{- end}}
+{{- range .Slots}}
+	// Slot @{{.Name}} for {{.Pattern}} (from {{.FilePath}})
+	app.Renderer().SetSlot("{{.Pattern}}", "{{.Name}}", func(c *nexo.Context) (templ.Component, error) {
+		{{- if .HasLoader}}
+		data, err := {{if .HasLoaderConfig}}nexo.LoadWithConfig(c, &{{.ImportAlias}}.LoaderConfig, {{.ImportAlias}}.Loader){{else}}{{.ImportAlias}}.Loader(c){{end}}
+		if err != nil {
+			return nil, err
+		}
+		return {{.ImportAlias}}.Page(data), nil
+		{{- else}}
+		{{- range .Params}}
+		{{- if .FromPath}}
+		{{.Name}} := c.Param("{{.Name}}")
+		{{- end}}
+		{{- end}}
+		return {{.ImportAlias}}.Page({{paramArgs .Params}}), nil
+		{{- end}}
+	})
+{{- end}}
+{{- range .RouteConfigs}}
+	// Config for {{.Pattern}} (from {{.FilePath}})
+	app.RouteTree().SetRouteConfig("{{.Pattern}}", {{.ImportAlias}}.Config)
 {{- end}}
 {{range .Routes}}
 	// {{.Method}} {{.Pattern}} (from {{.FilePath}})
+	{{- if .Scope}}
+	app.RegisterScopedRoute("{{.Method}}", "{{.Pattern}}", "{{.Scope}}", {{.ImportAlias}}.{{.Handler}})
+	{{- else}}
 	app.RegisterRoute("{{.Method}}", "{{.Pattern}}", {{.ImportAlias}}.{{.Handler}})
+	{{- end}}
+{{- end}}
+{{- range .Sockets}}
+	// WebSocket {{.Pattern}} (from {{.FilePath}})
+	{{- if .Scope}}
+	app.RegisterScopedRoute("GET", "{{.Pattern}}", "{{.Scope}}", nexo.SocketHandler({{.ImportAlias}}.Socket{{if .HasConfig}}, {{.ImportAlias}}.SocketConfig{{end}}))
+	{{- else}}
+	app.RegisterRoute("GET", "{{.Pattern}}", nexo.SocketHandler({{.ImportAlias}}.Socket{{if .HasConfig}}, {{.ImportAlias}}.SocketConfig{{end}}))
+	{{- end}}
+{{- end}}
+{{- range .Streams}}
+	// Server-Sent Events {{.Pattern}} (from {{.FilePath}})
+	{{- if .Scope}}
+	app.RegisterScopedRoute("GET", "{{.Pattern}}", "{{.Scope}}", nexo.StreamHandler({{.ImportAlias}}.Stream{{if .HasConfig}}, {{.ImportAlias}}.StreamConfig{{end}}))
+	{{- else}}
+	app.RegisterRoute("GET", "{{.Pattern}}", nexo.StreamHandler({{.ImportAlias}}.Stream{{if .HasConfig}}, {{.ImportAlias}}.StreamConfig{{end}}))
+	{{- end}}
 {{- end}}
 {{- range .Pages}}
-{{- if .HasLoader}}
+{{- if and .HasLoader .HasLoading}}
 	// Page: {{.Pattern}} (from {{.FilePath}})
-	// Data loaded by: {{.LoaderPackage}}.Loader()
-	app.Get("{{.Pattern}}", func(c *nexo.Context) error {
-		data, err := {{.ImportAlias}}.Loader(c)
+	// Data loaded by: {{.LoaderPackage}}.Loader(){{if .HasLoaderConfig}}, cached by {{.LoaderPackage}}.LoaderConfig{{end}}, streaming the loading component while it runs
+	{{if .Scope}}app.RegisterScopedRoute("GET", "{{.Pattern}}", "{{.Scope}}", {{else}}app.Get("{{.Pattern}}", {{end}}func(c *nexo.Context) error {
+		return app.Renderer().RenderWithLoading(c, func() (templ.Component, error) {
+			data, err := {{if .HasLoaderConfig}}nexo.LoadWithConfig(c, &{{.ImportAlias}}.LoaderConfig, {{.ImportAlias}}.Loader){{else}}{{.ImportAlias}}.Loader(c){{end}}
+			if err != nil {
+				return nil, err
+			}
+			return {{layoutPage . (printf "%s.Page(data)" .ImportAlias)}}, nil
+		})
+	})
+{{- else if .HasLoader}}
+	// Page: {{.Pattern}} (from {{.FilePath}})
+	// Data loaded by: {{.LoaderPackage}}.Loader(){{if .HasLoaderConfig}}, cached by {{.LoaderPackage}}.LoaderConfig{{end}}
+	{{if .Scope}}app.RegisterScopedRoute("GET", "{{.Pattern}}", "{{.Scope}}", {{else}}app.Get("{{.Pattern}}", {{end}}func(c *nexo.Context) error {
+		data, err := {{if .HasLoaderConfig}}nexo.LoadWithConfig(c, &{{.ImportAlias}}.LoaderConfig, {{.ImportAlias}}.Loader){{else}}{{.ImportAlias}}.Loader(c){{end}}
 		if err != nil {
 			return err
 		}
-		return nexo.TemplComponent(c, 200, {{.ImportAlias}}.Page(data))
+		return nexo.TemplComponent(c, 200, {{layoutPage . (printf "%s.Page(data)" .ImportAlias)}})
 	})
 {{- else if .HasParams}}
 	// Page: {{.Pattern}} (from {{.FilePath}})
 	// Dynamic page with signature: {{.ParamSignature}}
-	app.Get("{{.Pattern}}", func(c *nexo.Context) error {
+	{{if .Scope}}app.RegisterScopedRoute("GET", "{{.Pattern}}", "{{.Scope}}", {{else}}app.Get("{{.Pattern}}", {{end}}func(c *nexo.Context) error {
 		{{- range .Params}}
 		{{- if .FromPath}}
 		{{.Name}} := c.Param("{{.Name}}")
 		{{- end}}
 		{{- end}}
-		return nexo.TemplComponent(c, 200, {{.ImportAlias}}.Page({{paramArgs .Params}}))
+		return nexo.TemplComponent(c, 200, {{layoutPage . (printf "%s.Page(%s)" .ImportAlias (paramArgs .Params))}})
 	})
 {{- else}}
 	// Page: {{.Pattern}} (from {{.FilePath}})
-	app.Get("{{.Pattern}}", func(c *nexo.Context) error {
-		return nexo.TemplComponent(c, 200, {{.ImportAlias}}.Page())
+	{{if .Scope}}app.RegisterScopedRoute("GET", "{{.Pattern}}", "{{.Scope}}", {{else}}app.Get("{{.Pattern}}", {{end}}func(c *nexo.Context) error {
+		return nexo.TemplComponent(c, 200, {{layoutPage . (printf "%s.Page()" .ImportAlias)}})
 	})
 {{- end}}
 {{- end}}
+{{- range $action := .Actions}}
+	// Actions: POST {{.Pattern}} (from {{.FilePath}})
+	{{if .Scope}}app.RegisterScopedRoute("POST", "{{.Pattern}}", "{{.Scope}}", {{else}}app.Post("{{.Pattern}}", {{end}}app.ActionHandler("{{.Pattern}}", nexo.Actions{
+		{{- range .Funcs}}
+		"{{.Name}}": {{$action.ImportAlias}}.{{.Func}},
+		{{- end}}
+	}))
+{{- end}}
 }
 `
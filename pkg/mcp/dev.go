@@ -0,0 +1,292 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxDevLogLines is how many lines of dev server output are kept.
+	maxDevLogLines = 2000
+
+	// devStopTimeout is how long the dev server gets to shut down before it
+	// is killed.
+	devStopTimeout = 10 * time.Second
+)
+
+// ansiRe matches terminal color escape sequences.
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// devServer is a `nexo dev` process started by the nexo_dev tool. Its output
+// is kept so agents can read build errors and request logs.
+type devServer struct {
+	cmd       *exec.Cmd
+	port      string
+	startedAt time.Time
+	done      chan struct{}
+
+	mu      sync.Mutex
+	changed chan struct{} // Closed and replaced when output arrives
+	lines   []string
+	dropped int    // Lines dropped from the front of lines
+	read    int    // Index of the first line not yet returned by logs
+	partial string // Output after the last newline
+	build   string // ok or failed, from the last build
+	exitErr error
+}
+
+// Write collects the process output line by line.
+func (d *devServer) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	text := d.partial + ansiRe.ReplaceAllString(string(p), "")
+	parts := strings.Split(text, "\n")
+	d.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.Contains(line, "Build failed"):
+			d.build = "failed"
+		case strings.Contains(line, "Built in") || strings.Contains(line, "Rebuilt in"):
+			d.build = "ok"
+		}
+		d.lines = append(d.lines, line)
+	}
+	if over := len(d.lines) - maxDevLogLines; over > 0 {
+		d.lines = d.lines[over:]
+		d.dropped += over
+	}
+
+	close(d.changed)
+	d.changed = make(chan struct{})
+	return len(p), nil
+}
+
+// running reports whether the process is still running.
+func (d *devServer) running() bool {
+	select {
+	case <-d.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// unread returns the lines not yet returned and marks them as read.
+func (d *devServer) unread() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	start := d.read - d.dropped
+	if start < 0 {
+		start = 0
+	}
+	lines := append([]string(nil), d.lines[start:]...)
+	d.read = d.dropped + len(d.lines)
+	return lines
+}
+
+// tail returns the last n lines and marks all output as read.
+func (d *devServer) tail(n int) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	start := len(d.lines) - n
+	if start < 0 {
+		start = 0
+	}
+	d.read = d.dropped + len(d.lines)
+	return append([]string(nil), d.lines[start:]...)
+}
+
+// waitFor waits until ready reports true, the process exits, or timeout
+// expires. ready is called with the lock held.
+func (d *devServer) waitFor(ctx context.Context, timeout time.Duration, ready func() bool) {
+	deadline := time.After(timeout)
+	for {
+		d.mu.Lock()
+		done := ready()
+		changed := d.changed
+		d.mu.Unlock()
+		if done {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-d.done:
+			return
+		case <-deadline:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stop shuts the dev server down, killing it after devStopTimeout.
+func (d *devServer) stop() {
+	if !d.running() {
+		return
+	}
+	_ = d.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-d.done:
+	case <-time.After(devStopTimeout):
+		_ = d.cmd.Process.Kill()
+		<-d.done
+	}
+}
+
+// status describes the dev server for tool results.
+func (d *devServer) status(lines []string) map[string]any {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := map[string]any{
+		"running": d.running(),
+		"url":     "http://localhost:" + d.port,
+		"pid":     d.cmd.Process.Pid,
+		"uptime":  time.Since(d.startedAt).Round(time.Second).String(),
+		"output":  strings.Join(lines, "\n"),
+	}
+	if d.build != "" {
+		result["build"] = d.build
+	}
+	if !d.running() {
+		delete(result, "uptime")
+		if d.exitErr != nil {
+			result["exit_error"] = d.exitErr.Error()
+		}
+	}
+	return result
+}
+
+func (s *Server) handleDev(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	action, err := req.RequireString("action")
+	if err != nil {
+		return mcp.NewToolResultError("action is required (start, stop, status or logs)"), nil
+	}
+
+	var result map[string]any
+	switch action {
+	case "start":
+		result, err = s.startDev(ctx, req.GetString("port", "3000"), time.Duration(req.GetInt("timeout", 60))*time.Second)
+	case "stop":
+		result, err = s.stopDev()
+	case "status":
+		result, err = s.devStatus()
+	case "logs":
+		result, err = s.devLogs(ctx, req.GetInt("tail", 0), time.Duration(req.GetInt("wait", 0))*time.Second)
+	default:
+		err = fmt.Errorf("unknown action %q (use start, stop, status or logs)", action)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(output)), nil
+}
+
+// startDev starts `nexo dev` in the workdir and waits until the first build
+// finishes, the process exits, or timeout expires.
+func (s *Server) startDev(ctx context.Context, port string, timeout time.Duration) (map[string]any, error) {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+
+	if s.dev != nil && s.dev.running() {
+		return nil, fmt.Errorf("dev server is already running on port %s (stop it first)", s.dev.port)
+	}
+
+	d := &devServer{
+		port:      port,
+		startedAt: time.Now(),
+		done:      make(chan struct{}),
+		changed:   make(chan struct{}),
+	}
+	// Not tied to ctx: the server outlives this tool call
+	d.cmd = exec.Command(s.nexoBinary, "dev", "--port", port, "--no-reload")
+	d.cmd.Dir = s.workdir
+	d.cmd.Stdout = d
+	d.cmd.Stderr = d
+	// Don't wait on output from an app process that outlives nexo dev
+	d.cmd.WaitDelay = 2 * time.Second
+	if err := d.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start dev server: %w", err)
+	}
+	go func() {
+		err := d.cmd.Wait()
+		d.mu.Lock()
+		d.exitErr = err
+		d.mu.Unlock()
+		close(d.done)
+	}()
+	s.dev = d
+
+	d.waitFor(ctx, timeout, func() bool { return d.build != "" })
+	return d.status(d.unread()), nil
+}
+
+// stopDev stops the dev server and returns its remaining output.
+func (s *Server) stopDev() (map[string]any, error) {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+
+	if s.dev == nil {
+		return nil, fmt.Errorf("dev server is not running")
+	}
+	s.dev.stop()
+	return s.dev.status(s.dev.unread()), nil
+}
+
+// devStatus returns the dev server state without consuming output.
+func (s *Server) devStatus() (map[string]any, error) {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+
+	if s.dev == nil {
+		return map[string]any{"running": false}, nil
+	}
+	result := s.dev.status(nil)
+	delete(result, "output")
+	return result, nil
+}
+
+// devLogs returns the output since the last call, or the last tail lines.
+// With wait it waits up to that long for new output first.
+func (s *Server) devLogs(ctx context.Context, tail int, wait time.Duration) (map[string]any, error) {
+	s.devMu.Lock()
+	d := s.dev
+	s.devMu.Unlock()
+
+	if d == nil {
+		return nil, fmt.Errorf("dev server is not running (start it with action start)")
+	}
+	if tail > 0 {
+		return d.status(d.tail(tail)), nil
+	}
+	if wait > 0 {
+		d.waitFor(ctx, wait, func() bool { return d.dropped+len(d.lines) > d.read })
+	}
+	return d.status(d.unread()), nil
+}
+
+// closeDev stops the dev server when the MCP server shuts down.
+func (s *Server) closeDev() {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	if s.dev != nil {
+		s.dev.stop()
+	}
+}
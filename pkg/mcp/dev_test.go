@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDevServer_Write(t *testing.T) {
+	d := &devServer{changed: make(chan struct{})}
+
+	_, _ = d.Write([]byte("  \x1b[32m✓\x1b[0m Built in 1.2s\n  GET /api/he"))
+	_, _ = d.Write([]byte("alth 200\n"))
+
+	if d.build != "ok" {
+		t.Errorf("build = %q, want ok", d.build)
+	}
+	lines := d.unread()
+	want := []string{"  ✓ Built in 1.2s", "  GET /api/health 200"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("unread() = %q, want %q", lines, want)
+	}
+	if lines := d.unread(); len(lines) != 0 {
+		t.Errorf("second unread() = %q, want nothing", lines)
+	}
+
+	_, _ = d.Write([]byte("  [12:00:00] ✗ Build failed\n./main.go:3: undefined: x\n"))
+	if d.build != "failed" {
+		t.Errorf("build = %q, want failed", d.build)
+	}
+	if lines := d.unread(); len(lines) != 2 || lines[1] != "./main.go:3: undefined: x" {
+		t.Errorf("unread() after failure = %q", lines)
+	}
+}
+
+func TestDevServer_KeepsRecentLines(t *testing.T) {
+	d := &devServer{changed: make(chan struct{})}
+	for i := 0; i < maxDevLogLines+10; i++ {
+		_, _ = d.Write([]byte("line\n"))
+	}
+	if len(d.lines) != maxDevLogLines || d.dropped != 10 {
+		t.Errorf("kept %d lines, dropped %d", len(d.lines), d.dropped)
+	}
+	if got := len(d.unread()); got != maxDevLogLines {
+		t.Errorf("unread() returned %d lines, want %d", got, maxDevLogLines)
+	}
+	if got := d.tail(3); len(got) != 3 {
+		t.Errorf("tail(3) returned %d lines", len(got))
+	}
+}
+
+// writeFakeNexo writes a script that behaves like `nexo dev`: it reports a
+// build, logs a request, and exits on SIGTERM.
+func writeFakeNexo(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "nexo")
+	script := `#!/bin/sh
+trap 'echo "  Shutting down"; exit 0' TERM
+echo "  args: $*"
+echo "  → Building..."
+echo "  ✓ Built in 10ms"
+sleep 0.3
+echo "  GET /api/health 200"
+while true; do sleep 0.05; done
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func devResult(t *testing.T, s *Server, args map[string]any) map[string]any {
+	t.Helper()
+	result, err := s.handleDev(context.Background(), makeRequest(args))
+	if err != nil {
+		t.Fatalf("handleDev(%v) error = %v", args, err)
+	}
+	text := getResultText(result)
+	if result.IsError {
+		t.Fatalf("handleDev(%v) returned an error: %s", args, text)
+	}
+	var out map[string]any
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", text, err)
+	}
+	return out
+}
+
+func TestHandleDev_Lifecycle(t *testing.T) {
+	s := NewServer(t.TempDir())
+	s.nexoBinary = writeFakeNexo(t)
+	defer s.closeDev()
+
+	started := devResult(t, s, map[string]any{"action": "start", "port": "4123"})
+	if started["running"] != true || started["build"] != "ok" {
+		t.Errorf("start = %v", started)
+	}
+	if out := started["output"].(string); !strings.Contains(out, "Built in") || !strings.Contains(out, "args: dev --port 4123 --no-reload") {
+		t.Errorf("start output = %q", out)
+	}
+
+	// A second start is refused while the server runs
+	result, _ := s.handleDev(context.Background(), makeRequest(map[string]any{"action": "start"}))
+	if !result.IsError {
+		t.Error("expected an error starting a second dev server")
+	}
+
+	logs := devResult(t, s, map[string]any{"action": "logs", "wait": 5})
+	if out := logs["output"].(string); out != "  GET /api/health 200" {
+		t.Errorf("logs output = %q, want only the new request log", out)
+	}
+
+	tail := devResult(t, s, map[string]any{"action": "logs", "tail": 2})
+	if out := tail["output"].(string); !strings.HasSuffix(out, "GET /api/health 200") {
+		t.Errorf("tail output = %q", out)
+	}
+
+	stopped := devResult(t, s, map[string]any{"action": "stop"})
+	if stopped["running"] != false || !strings.Contains(stopped["output"].(string), "Shutting down") {
+		t.Errorf("stop = %v", stopped)
+	}
+
+	status := devResult(t, s, map[string]any{"action": "status"})
+	if status["running"] != false {
+		t.Errorf("status = %v", status)
+	}
+}
+
+func TestHandleDev_Errors(t *testing.T) {
+	s := NewServer(t.TempDir())
+	for _, args := range []map[string]any{
+		{},
+		{"action": "restart"},
+		{"action": "logs"},
+		{"action": "stop"},
+	} {
+		result, err := s.handleDev(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("handleDev(%v) error = %v", args, err)
+		}
+		if !result.IsError {
+			t.Errorf("handleDev(%v) expected an error result", args)
+		}
+	}
+
+	status := devResult(t, s, map[string]any{"action": "status"})
+	if status["running"] != false {
+		t.Errorf("status without a server = %v", status)
+	}
+}
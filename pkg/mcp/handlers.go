@@ -59,6 +59,7 @@ func (s *Server) handleGenerateRoute(ctx context.Context, req mcp.CallToolReques
 		Path:    path,
 		Methods: methods,
 		AppDir:  appDir,
+		Append:  req.GetBool("append", false),
 	})
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -155,7 +156,7 @@ func (s *Server) handleListRoutes(ctx context.Context, req mcp.CallToolRequest)
 	appDir := filepath.Join(s.workdir, "app")
 	scanner := nexo.NewScanner(appDir)
 
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
@@ -209,7 +210,7 @@ func (s *Server) handleInfo(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 		info["has_app_dir"] = true
 		scanner := nexo.NewScanner(appDir)
 
-		routes, _ := scanner.ScanRouteInfo()
+		routes, _, _ := scanner.ScanRouteInfo()
 		middlewares, _ := scanner.ScanMiddlewareInfo()
 		proxyInfo, _ := scanner.ScanProxyInfo()
 
@@ -260,7 +261,7 @@ func (s *Server) handleValidate(ctx context.Context, req mcp.CallToolRequest) (*
 		scanner := nexo.NewScanner(appDir)
 		scanner.SetVerbose(false)
 
-		routes, err := scanner.ScanRouteInfo()
+		routes, diags, err := scanner.ScanRouteInfo()
 		if err != nil {
 			issues = append(issues, "Failed to scan routes: "+err.Error())
 		} else {
@@ -270,6 +271,11 @@ func (s *Server) handleValidate(ctx context.Context, req mcp.CallToolRequest) (*
 			}
 		}
 
+		// Check for handlers and middleware that aren't registered
+		for _, d := range diags {
+			warnings = append(warnings, d.String())
+		}
+
 		// Check middleware
 		middlewares, err := scanner.ScanMiddlewareInfo()
 		if err != nil {
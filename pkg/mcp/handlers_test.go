@@ -367,6 +367,38 @@ func TestHandleValidate_InvalidProject(t *testing.T) {
 	}
 }
 
+func TestHandleValidate_SkippedHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	usersDir := filepath.Join(tmpDir, "app", "api", "users")
+	if err := os.MkdirAll(usersDir, 0755); err != nil {
+		t.Fatalf("Failed to create app dir: %v", err)
+	}
+	route := `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func GET(c *nexo.Context) error {
+	return nil
+}
+`
+	if err := os.WriteFile(filepath.Join(usersDir, "route.go"), []byte(route), 0644); err != nil {
+		t.Fatalf("Failed to write route.go: %v", err)
+	}
+
+	server := NewServer(tmpDir)
+
+	result, err := server.handleValidate(context.Background(), makeRequest(map[string]any{}))
+	if err != nil {
+		t.Fatalf("handleValidate failed: %v", err)
+	}
+
+	content := getResultText(result)
+	if !strings.Contains(content, "route.go:5: GET: not a handler name, did you mean Get?") {
+		t.Errorf("Expected a warning for the misspelled handler, got: %s", content)
+	}
+}
+
 // Helper to extract text from CallToolResult
 func getResultText(result *mcp.CallToolResult) string {
 	if result == nil || len(result.Content) == 0 {
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// HTTP endpoints of the MCP server.
+const (
+	HTTPEndpoint    = "/mcp"     // Streamable HTTP transport
+	SSEEndpoint     = "/sse"     // SSE transport stream
+	MessageEndpoint = "/message" // SSE transport messages
+)
+
+// ErrTokenRequired is returned by ServeHTTP for addresses other machines
+// can reach when no token is set.
+var ErrTokenRequired = errors.New("a token is required to serve MCP on a non-loopback address")
+
+// Handler returns an http.Handler serving the MCP server over the
+// streamable HTTP transport at /mcp and the SSE transport at /sse and
+// /message, for a server listening on host. Requests whose Host or Origin
+// names another host are rejected, so web pages can't reach the server
+// through DNS rebinding. When token is not empty, requests must send it as
+// a bearer token.
+func (s *Server) Handler(host, token string) http.Handler {
+	streamable := server.NewStreamableHTTPServer(s.mcpServer)
+	sse := server.NewSSEServer(s.mcpServer,
+		server.WithSSEEndpoint(SSEEndpoint),
+		server.WithMessageEndpoint(MessageEndpoint),
+		// Relative URLs work behind proxies and port forwarding
+		server.WithUseFullURLForMessageEndpoint(false),
+		server.WithKeepAlive(true),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(HTTPEndpoint, streamable)
+	mux.Handle(SSEEndpoint, sse)
+	mux.Handle(MessageEndpoint, sse)
+
+	var h http.Handler = mux
+	if token != "" {
+		h = bearerAuth(token, h)
+	}
+	return hostGuard(host, h)
+}
+
+// ServeHTTP serves the MCP server over HTTP on addr until ctx is canceled.
+// Tools can start processes, so addresses other than loopback ones, such
+// as ":8765", require a token.
+func (s *Server) ServeHTTP(ctx context.Context, addr, token string) error {
+	defer s.closeDev()
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if token == "" && !isLoopbackHost(host) {
+		return ErrTokenRequired
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           s.Handler(host, token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	// SSE streams stay open, so don't wait long for them
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		_ = srv.Close()
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// bearerAuth rejects requests without "Authorization: Bearer <token>".
+func bearerAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="nexo"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hostGuard rejects requests whose Host or Origin doesn't name listenHost.
+// Servers listening on loopback accept any loopback name. Servers listening
+// on all interfaces can't tell their names apart, so they only require an
+// Origin to match the Host.
+func hostGuard(listenHost string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed := isUnspecifiedHost(listenHost) || matchesHost(listenHost, (&url.URL{Host: r.Host}).Hostname())
+		if origin := r.Header.Get("Origin"); allowed && origin != "" {
+			u, err := url.Parse(origin)
+			allowed = err == nil && (matchesHost(listenHost, u.Hostname()) ||
+				isUnspecifiedHost(listenHost) && strings.EqualFold(u.Host, r.Host))
+		}
+		if !allowed {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchesHost reports whether host names the listenHost a server listens on.
+func matchesHost(listenHost, host string) bool {
+	if isLoopbackHost(listenHost) {
+		return isLoopbackHost(host)
+	}
+	return host != "" && strings.EqualFold(host, listenHost)
+}
+
+// isLoopbackHost reports whether listening on host only accepts local
+// connections. An empty host listens on all interfaces.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isUnspecifiedHost reports whether listening on host listens on all
+// interfaces, e.g. for ":8765" or "0.0.0.0:8765".
+func isUnspecifiedHost(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "" || ip != nil && ip.IsUnspecified()
+}
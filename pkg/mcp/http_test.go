@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+
+func postMCP(t *testing.T, url, token string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url+HTTPEndpoint, strings.NewReader(initializeRequest))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestHandler_StreamableHTTP(t *testing.T) {
+	ts := httptest.NewServer(NewServer(t.TempDir()).Handler("127.0.0.1", ""))
+	defer ts.Close()
+
+	status, body := postMCP(t, ts.URL, "")
+	if status != http.StatusOK || !strings.Contains(body, `"name":"nexo"`) {
+		t.Errorf("initialize = %d %s", status, body)
+	}
+}
+
+func TestHandler_BearerAuth(t *testing.T) {
+	ts := httptest.NewServer(NewServer(t.TempDir()).Handler("127.0.0.1", "secret"))
+	defer ts.Close()
+
+	for _, token := range []string{"", "wrong"} {
+		if status, _ := postMCP(t, ts.URL, token); status != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, status)
+		}
+	}
+	if status, _ := postMCP(t, ts.URL, "secret"); status != http.StatusOK {
+		t.Errorf("valid token: status = %d, want 200", status)
+	}
+
+	resp, err := http.Get(ts.URL + SSEEndpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("SSE without token: status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestHandler_HostGuard(t *testing.T) {
+	tests := []struct {
+		name       string
+		listenHost string
+		host       string
+		origin     string
+		want       int
+	}{
+		{"loopback", "127.0.0.1", "127.0.0.1:8765", "", http.StatusOK},
+		{"loopback alias", "127.0.0.1", "localhost:8765", "http://localhost:3000", http.StatusOK},
+		{"rebound host", "127.0.0.1", "evil.example:8765", "", http.StatusForbidden},
+		{"foreign origin", "localhost", "localhost:8765", "https://evil.example", http.StatusForbidden},
+		{"listen host", "10.0.0.5", "10.0.0.5:8765", "", http.StatusOK},
+		{"other host", "10.0.0.5", "evil.example:8765", "", http.StatusForbidden},
+		{"all interfaces", "", "mcp.internal:8765", "", http.StatusOK},
+		{"all interfaces same origin", "", "mcp.internal:8765", "http://mcp.internal:8765", http.StatusOK},
+		{"all interfaces cross origin", "", "mcp.internal:8765", "https://evil.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewServer(t.TempDir()).Handler(tt.listenHost, "")
+			req := httptest.NewRequest(http.MethodPost, HTTPEndpoint, strings.NewReader(initializeRequest))
+			req.Host = tt.host
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json, text/event-stream")
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServeHTTP_RequiresTokenOffLoopback(t *testing.T) {
+	for _, addr := range []string{":0", "0.0.0.0:0"} {
+		err := NewServer(t.TempDir()).ServeHTTP(context.Background(), addr, "")
+		if !errors.Is(err, ErrTokenRequired) {
+			t.Errorf("ServeHTTP(%q) error = %v, want ErrTokenRequired", addr, err)
+		}
+	}
+}
+
+func TestHandler_SSE(t *testing.T) {
+	ts := httptest.NewServer(NewServer(t.TempDir()).Handler("127.0.0.1", ""))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+SSEEndpoint, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The first event tells the client where to send messages
+	reader := bufio.NewReader(resp.Body)
+	var endpoint string
+	for endpoint == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading SSE stream: %v", err)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			endpoint = strings.TrimSpace(data)
+		}
+	}
+	if !strings.HasPrefix(endpoint, MessageEndpoint+"?sessionId=") {
+		t.Errorf("endpoint = %q", endpoint)
+	}
+}
+
+func TestServeHTTP_StopsOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- NewServer(t.TempDir()).ServeHTTP(ctx, addr, "") }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			_ = conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("server did not start")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if status, _ := postMCP(t, "http://"+addr, ""); status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("ServeHTTP() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeHTTP did not return after cancel")
+	}
+}
@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// requestTimeout limits requests sent by the nexo_request tool.
+const requestTimeout = 30 * time.Second
+
+// requestResult is the nexo_request tool result. It matches the JSON output
+// of nexo call.
+type requestResult struct {
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Target     string            `json:"target"` // dev, server or app
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers"`
+	Body       any               `json:"body"`
+	DurationMs int64             `json:"duration_ms"`
+	Logs       string            `json:"logs,omitempty"` // Dev server output written during the request
+}
+
+func (s *Server) handleRequest(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	method, err := req.RequireString("method")
+	if err != nil {
+		return mcp.NewToolResultError("method is required"), nil
+	}
+	path, err := req.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("path is required"), nil
+	}
+	method = strings.ToUpper(method)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	body := req.GetString("body", "")
+
+	headers := make(map[string]string)
+	if raw, ok := req.GetArguments()["headers"].(map[string]any); ok {
+		for name, value := range raw {
+			headers[name] = fmt.Sprint(value)
+		}
+	}
+
+	s.devMu.Lock()
+	dev := s.dev
+	s.devMu.Unlock()
+
+	var result *requestResult
+	if dev != nil && dev.running() {
+		result, err = requestDev(ctx, dev, method, path, headers, body)
+	} else {
+		result, err = s.requestCall(ctx, method, path, headers, body)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(output)), nil
+}
+
+// requestDev sends the request to the dev server started by nexo_dev and
+// returns the output it wrote meanwhile, such as request logs and panics.
+func requestDev(ctx context.Context, dev *devServer, method, path string, headers map[string]string, body string) (*requestResult, error) {
+	dev.unread()
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, "http://localhost:"+dev.port+path, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		httpReq.Header.Set(name, value)
+	}
+	if body != "" && httpReq.Header.Get("Content-Type") == "" && json.Valid([]byte(body)) {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{
+		Timeout: requestTimeout,
+		// Report redirects instead of following them
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w\n%s", err, strings.Join(dev.unread(), "\n"))
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	result := &requestResult{
+		Method:     method,
+		URL:        httpReq.URL.String(),
+		Target:     "dev",
+		Status:     resp.StatusCode,
+		Headers:    make(map[string]string, len(resp.Header)),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	for name, values := range resp.Header {
+		result.Headers[name] = strings.Join(values, ", ")
+	}
+	if len(respBody) > 0 && json.Valid(respBody) {
+		result.Body = json.RawMessage(respBody)
+	} else {
+		result.Body = string(respBody)
+	}
+
+	// The request log is written after the response, so give it a moment
+	dev.waitFor(ctx, 200*time.Millisecond, func() bool { return dev.dropped+len(dev.lines) > dev.read })
+	result.Logs = strings.Join(dev.unread(), "\n")
+	return result, nil
+}
+
+// requestCall sends the request with nexo call, which uses a dev server
+// listening on the configured port or builds and starts the app.
+func (s *Server) requestCall(ctx context.Context, method, path string, headers map[string]string, body string) (*requestResult, error) {
+	args := []string{"call", method, path, "--json"}
+	if body != "" {
+		args = append(args, "--data", body)
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--header", name+": "+headers[name])
+	}
+
+	cmd := exec.CommandContext(ctx, s.nexoBinary, args...)
+	cmd.Dir = s.workdir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var response struct {
+		Success bool           `json:"success"`
+		Data    *requestResult `json:"data"`
+		Error   string         `json:"error"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("nexo call failed: %v\n%s%s", runErr, stdout.String(), stderr.String())
+		}
+		return nil, fmt.Errorf("unexpected output from nexo call: %s", stdout.String())
+	}
+	if !response.Success || response.Data == nil {
+		return nil, fmt.Errorf("request failed: %s", response.Error)
+	}
+	return response.Data, nil
+}
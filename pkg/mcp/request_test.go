@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHandleRequest_DevServer(t *testing.T) {
+	dev := &devServer{done: make(chan struct{}), changed: make(chan struct{})}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"type":"` + r.Header.Get("Content-Type") + `","token":"` + r.Header.Get("X-Token") + `","body":` + string(body) + `}`))
+		_, _ = dev.Write([]byte("  " + r.Method + " " + r.URL.Path + " 201\n"))
+	}))
+	defer ts.Close()
+	_, dev.port, _ = net.SplitHostPort(ts.Listener.Addr().String())
+
+	s := NewServer(t.TempDir())
+	s.dev = dev
+	_, _ = dev.Write([]byte("  ✓ Built in 1ms\n"))
+
+	result, err := s.handleRequest(context.Background(), makeRequest(map[string]any{
+		"method":  "post",
+		"path":    "api/users",
+		"headers": map[string]any{"X-Token": "secret"},
+		"body":    `{"name":"Ada"}`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := getResultText(result)
+	if result.IsError {
+		t.Fatalf("handleRequest() error: %s", text)
+	}
+
+	var out struct {
+		Method string `json:"method"`
+		Target string `json:"target"`
+		Status int    `json:"status"`
+		Body   struct {
+			Type  string            `json:"type"`
+			Token string            `json:"token"`
+			Body  map[string]string `json:"body"`
+		} `json:"body"`
+		Logs string `json:"logs"`
+	}
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", text, err)
+	}
+	if out.Method != "POST" || out.Target != "dev" || out.Status != http.StatusCreated {
+		t.Errorf("result = %s", text)
+	}
+	if out.Body.Type != "application/json" || out.Body.Token != "secret" || out.Body.Body["name"] != "Ada" {
+		t.Errorf("request was not sent as expected: %s", text)
+	}
+	if out.Logs != "  POST /api/users 201" {
+		t.Errorf("logs = %q, want only the request log", out.Logs)
+	}
+}
+
+func TestHandleRequest_NexoCall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	dir := t.TempDir()
+	s := NewServer(dir)
+	s.nexoBinary = filepath.Join(dir, "nexo")
+	script := `#!/bin/sh
+printf '%s\n' "$@" > args.txt
+echo '{"success":true,"data":{"method":"GET","url":"http://localhost:3000/api/health","target":"app","status":200,"headers":{},"body":{"ok":true},"duration_ms":3}}'
+`
+	if err := os.WriteFile(s.nexoBinary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.handleRequest(context.Background(), makeRequest(map[string]any{
+		"method":  "GET",
+		"path":    "/api/health",
+		"headers": map[string]any{"Accept": "application/json"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := getResultText(result)
+	if result.IsError || !strings.Contains(text, `"target": "app"`) || !strings.Contains(text, `"ok": true`) {
+		t.Errorf("handleRequest() = %s", text)
+	}
+
+	args, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	want := "call\nGET\n/api/health\n--json\n--header\nAccept: application/json\n"
+	if string(args) != want {
+		t.Errorf("nexo call args = %q, want %q", args, want)
+	}
+}
+
+func TestHandleRequest_Errors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	dir := t.TempDir()
+	s := NewServer(dir)
+	s.nexoBinary = filepath.Join(dir, "nexo")
+	script := `#!/bin/sh
+echo '{"success":false,"error":"build failed: undefined: x"}'
+exit 1
+`
+	if err := os.WriteFile(s.nexoBinary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"path": "/"}, "method is required"},
+		{map[string]any{"method": "GET"}, "path is required"},
+		{map[string]any{"method": "GET", "path": "/"}, "undefined: x"},
+	}
+	for _, tt := range tests {
+		result, err := s.handleRequest(context.Background(), makeRequest(tt.args))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := getResultText(result); !result.IsError || !strings.Contains(text, tt.want) {
+			t.Errorf("handleRequest(%v) = %q, want error containing %q", tt.args, text, tt.want)
+		}
+	}
+}
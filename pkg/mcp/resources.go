@@ -0,0 +1,245 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	configURI = "nexo://config"
+	routesURI = "nexo://routes"
+)
+
+// routeFiles are the convention files returned for a route directory, in
+// the order they are returned.
+var routeFiles = []string{"route.go", "ws.go", "sse.go", "action.go", "page.templ", "layout.templ", "middleware.go", "proxy.go"}
+
+func (s *Server) registerResources() {
+	// nexo://config - Project configuration
+	s.mcpServer.AddResource(
+		mcp.NewResource(configURI, "nexo.yaml",
+			mcp.WithResourceDescription("Project configuration file"),
+			mcp.WithMIMEType("application/yaml"),
+		),
+		s.readConfig,
+	)
+
+	// nexo://routes - Scanned route table
+	s.mcpServer.AddResource(
+		mcp.NewResource(routesURI, "Route table",
+			mcp.WithResourceDescription("Routes, pages, middleware and proxy found in app/, with the resource URI of each route's files"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readRouteTable,
+	)
+
+	// nexo://routes/{path} - Files of one route
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(routesURI+"/{+path}", "Route files",
+			mcp.WithTemplateDescription("Files in an app/ directory, e.g. nexo://routes/api/users returns its route.go (and route_<method>.go), page.templ, layout.templ and middleware.go. "+
+				"The path may also be a URL pattern such as api/users/%7Bid%7D ({id} escaped), or name a single file such as api/users/route.go"),
+		),
+		s.readRouteFiles,
+	)
+}
+
+func (s *Server) readConfig(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	content, err := os.ReadFile(filepath.Join(s.workdir, "nexo.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("nexo.yaml not found in %s", s.workdir)
+		}
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: configURI, MIMEType: "application/yaml", Text: string(content)},
+	}, nil
+}
+
+func (s *Server) readRouteTable(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	appDir := filepath.Join(s.workdir, "app")
+	scanner := nexo.NewScanner(appDir)
+
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		return nil, err
+	}
+	pages, _ := scanner.ScanPageInfo()
+	middlewares, _ := scanner.ScanMiddlewareInfo()
+	proxyInfo, _ := scanner.ScanProxyInfo()
+
+	routeList := make([]map[string]any, 0, len(routes))
+	for _, r := range routes {
+		route := map[string]any{
+			"method":  r.Method,
+			"pattern": r.Pattern,
+			"file":    s.relPath(r.FilePath),
+			"uri":     routeURI(appDir, r.FilePath),
+		}
+		if r.WebSocket {
+			route["websocket"] = true
+		}
+		if r.SSE {
+			route["sse"] = true
+		}
+		if r.Action {
+			route["action"] = true
+		}
+		routeList = append(routeList, route)
+	}
+	pageList := make([]map[string]any, 0, len(pages))
+	for _, p := range pages {
+		pageList = append(pageList, map[string]any{
+			"pattern": p.Pattern,
+			"title":   p.Title,
+			"file":    s.relPath(p.FilePath),
+			"uri":     routeURI(appDir, p.FilePath),
+		})
+	}
+	middlewareList := make([]map[string]any, 0, len(middlewares))
+	for _, m := range middlewares {
+		middlewareList = append(middlewareList, map[string]any{
+			"path": m.Path,
+			"file": s.relPath(m.FilePath),
+			"uri":  routeURI(appDir, m.FilePath),
+		})
+	}
+
+	result := map[string]any{
+		"routes":     routeList,
+		"pages":      pageList,
+		"middleware": middlewareList,
+	}
+	if proxyInfo != nil && proxyInfo.HasProxy {
+		result["proxy"] = map[string]any{
+			"file":     s.relPath(proxyInfo.FilePath),
+			"uri":      routesURI + "/proxy.go",
+			"matchers": proxyInfo.Matchers,
+		}
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: routesURI, MIMEType: "application/json", Text: string(output)},
+	}, nil
+}
+
+func (s *Server) readRouteFiles(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	appDir := filepath.Join(s.workdir, "app")
+
+	rel := strings.Trim(strings.TrimPrefix(req.Params.URI, routesURI), "/")
+	if unescaped, err := url.PathUnescape(rel); err == nil {
+		rel = unescaped
+	}
+	rel = path.Clean("/" + rel)[1:]
+	if rel == "" {
+		return nil, fmt.Errorf("no route path in %s (read %s for the route table)", req.Params.URI, routesURI)
+	}
+
+	target := filepath.Join(appDir, filepath.FromSlash(rel))
+	info, err := os.Stat(target)
+	if os.IsNotExist(err) {
+		// Not a directory in app/, so try it as a URL pattern
+		if dir := s.routeDirForPattern(appDir, "/"+rel); dir != "" {
+			target = dir
+			info, err = os.Stat(target)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no route found for %s", req.Params.URI)
+	}
+
+	if !info.IsDir() {
+		content, err := os.ReadFile(target)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: routeURI(appDir, target), MIMEType: fileMIMEType(target), Text: string(content)},
+		}, nil
+	}
+
+	// route_<method>.go files come right after the route.go they split
+	names := routeFiles
+	if entries, err := os.ReadDir(target); err == nil {
+		var methodFiles []string
+		for _, entry := range entries {
+			if entry.Name() != "route.go" && nexo.IsRouteFile(entry.Name()) {
+				methodFiles = append(methodFiles, entry.Name())
+			}
+		}
+		names = slices.Concat(routeFiles[:1], methodFiles, routeFiles[1:])
+	}
+
+	var contents []mcp.ResourceContents
+	for _, name := range names {
+		file := filepath.Join(target, name)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		contents = append(contents, mcp.TextResourceContents{
+			URI:      routeURI(appDir, file),
+			MIMEType: fileMIMEType(file),
+			Text:     string(content),
+		})
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("no route files in %s", s.relPath(target))
+	}
+	return contents, nil
+}
+
+// routeDirForPattern returns the directory of the route or page that serves
+// pattern, or "" if there is none.
+func (s *Server) routeDirForPattern(appDir, pattern string) string {
+	scanner := nexo.NewScanner(appDir)
+	routes, _, _ := scanner.ScanRouteInfo()
+	for _, r := range routes {
+		if r.Pattern == pattern {
+			return filepath.Dir(r.FilePath)
+		}
+	}
+	pages, _ := scanner.ScanPageInfo()
+	for _, p := range pages {
+		if p.Pattern == pattern {
+			return filepath.Dir(p.FilePath)
+		}
+	}
+	return ""
+}
+
+// relPath returns path relative to the workdir.
+func (s *Server) relPath(path string) string {
+	if rel, err := filepath.Rel(s.workdir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// routeURI returns the resource URI of a file in appDir.
+func routeURI(appDir, file string) string {
+	rel, err := filepath.Rel(appDir, file)
+	if err != nil {
+		return ""
+	}
+	return routesURI + "/" + filepath.ToSlash(rel)
+}
+
+// fileMIMEType returns the MIME type for a route file.
+func fileMIMEType(file string) string {
+	if filepath.Ext(file) == ".go" {
+		return "text/x-go"
+	}
+	return "text/plain"
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// readResource reads uri through the MCP server, so template matching is
+// included.
+func readResource(t *testing.T, s *Server, uri string) ([]mcp.TextResourceContents, string) {
+	t.Helper()
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/read",
+		"params":  map[string]any{"uri": uri},
+	})
+	resp := s.mcpServer.HandleMessage(context.Background(), msg)
+
+	switch r := resp.(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := r.Result.(mcp.ReadResourceResult)
+		if !ok {
+			t.Fatalf("unexpected result type %T", r.Result)
+		}
+		var contents []mcp.TextResourceContents
+		for _, c := range result.Contents {
+			text, ok := c.(mcp.TextResourceContents)
+			if !ok {
+				t.Fatalf("unexpected contents type %T", c)
+			}
+			contents = append(contents, text)
+		}
+		return contents, ""
+	case mcp.JSONRPCError:
+		return nil, r.Error.Message
+	default:
+		t.Fatalf("unexpected response type %T", resp)
+		return nil, ""
+	}
+}
+
+func writeProjectFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResources(t *testing.T) {
+	dir := t.TempDir()
+	route := "package users\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error {\n\treturn nil\n}\n"
+	writeProjectFiles(t, dir, map[string]string{
+		"nexo.yaml":                    "port: 3000\n",
+		"app/api/users/route.go":       route,
+		"app/api/users/middleware.go":  "package users\n",
+		"app/api/users/[id]/route.go":  strings.Replace(route, "package users", "package id", 1),
+		"app/api/users/[id]/notes.txt": "not a route file",
+	})
+	s := NewServer(dir)
+
+	t.Run("config", func(t *testing.T) {
+		contents, errMsg := readResource(t, s, "nexo://config")
+		if errMsg != "" || len(contents) != 1 || contents[0].Text != "port: 3000\n" {
+			t.Errorf("config = %v, error %q", contents, errMsg)
+		}
+	})
+
+	t.Run("route table", func(t *testing.T) {
+		contents, errMsg := readResource(t, s, "nexo://routes")
+		if errMsg != "" || len(contents) != 1 {
+			t.Fatalf("routes = %v, error %q", contents, errMsg)
+		}
+		var table struct {
+			Routes []struct {
+				Pattern string `json:"pattern"`
+				File    string `json:"file"`
+				URI     string `json:"uri"`
+			} `json:"routes"`
+		}
+		if err := json.Unmarshal([]byte(contents[0].Text), &table); err != nil {
+			t.Fatal(err)
+		}
+		uris := map[string]string{}
+		for _, r := range table.Routes {
+			uris[r.Pattern] = r.URI
+		}
+		if uris["/api/users"] != "nexo://routes/api/users/route.go" || uris["/api/users/{id}"] != "nexo://routes/api/users/[id]/route.go" {
+			t.Errorf("route URIs = %v", uris)
+		}
+	})
+
+	t.Run("route directory", func(t *testing.T) {
+		contents, errMsg := readResource(t, s, "nexo://routes/api/users")
+		if errMsg != "" || len(contents) != 2 {
+			t.Fatalf("contents = %v, error %q", contents, errMsg)
+		}
+		if contents[0].URI != "nexo://routes/api/users/route.go" || contents[0].Text != route || contents[0].MIMEType != "text/x-go" {
+			t.Errorf("first file = %+v", contents[0])
+		}
+		if contents[1].URI != "nexo://routes/api/users/middleware.go" {
+			t.Errorf("second file = %+v", contents[1])
+		}
+	})
+
+	t.Run("url pattern", func(t *testing.T) {
+		contents, errMsg := readResource(t, s, "nexo://routes/api/users/%7Bid%7D")
+		if errMsg != "" || len(contents) != 1 || contents[0].URI != "nexo://routes/api/users/[id]/route.go" {
+			t.Errorf("contents = %v, error %q", contents, errMsg)
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		contents, errMsg := readResource(t, s, "nexo://routes/api/users/[id]/notes.txt")
+		if errMsg != "" || len(contents) != 1 || contents[0].Text != "not a route file" {
+			t.Errorf("contents = %v, error %q", contents, errMsg)
+		}
+	})
+
+	t.Run("outside app", func(t *testing.T) {
+		if _, errMsg := readResource(t, s, "nexo://routes/../nexo.yaml"); errMsg == "" {
+			t.Error("expected an error reading outside app/")
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, errMsg := readResource(t, s, "nexo://routes/api/posts"); errMsg == "" {
+			t.Error("expected an error for a missing route")
+		}
+	})
+}
+
+func TestResources_NoConfig(t *testing.T) {
+	s := NewServer(t.TempDir())
+	if _, errMsg := readResource(t, s, "nexo://config"); !strings.Contains(errMsg, "nexo.yaml not found") {
+		t.Errorf("error = %q", errMsg)
+	}
+}
@@ -2,14 +2,20 @@
 package mcp
 
 import (
+	"sync"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // Server is the Nexo MCP server.
 type Server struct {
-	mcpServer *server.MCPServer
-	workdir   string
+	mcpServer  *server.MCPServer
+	workdir    string
+	nexoBinary string // CLI run by tools that shell out, e.g. nexo dev
+
+	devMu sync.Mutex
+	dev   *devServer // Started by the nexo_dev tool
 }
 
 // NewServer creates a new Nexo MCP server.
@@ -18,14 +24,17 @@ func NewServer(workdir string) *Server {
 		"nexo",
 		"0.2.1",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
 	)
 
 	srv := &Server{
-		mcpServer: s,
-		workdir:   workdir,
+		mcpServer:  s,
+		workdir:    workdir,
+		nexoBinary: "nexo",
 	}
 
 	srv.registerTools()
+	srv.registerResources()
 	return srv
 }
 
@@ -47,6 +56,7 @@ func (s *Server) registerTools() {
 			mcp.WithDescription("Generate a new route file with handler functions"),
 			mcp.WithString("path", mcp.Required(), mcp.Description("Route path (e.g., 'users/[id]', 'posts/[...slug]')")),
 			mcp.WithString("methods", mcp.Description("HTTP methods comma-separated (default: GET)")),
+			mcp.WithBoolean("append", mcp.Description("Add missing handlers to an existing route.go instead of failing")),
 		),
 		s.handleGenerateRoute,
 	)
@@ -104,9 +114,40 @@ func (s *Server) registerTools() {
 		),
 		s.handleValidate,
 	)
+
+	// nexo_dev - Run the dev server and read its output
+	s.mcpServer.AddTool(
+		mcp.NewTool("nexo_dev",
+			mcp.WithDescription("Start or stop the dev server in the project and read its output. "+
+				"start waits for the first build and returns its output, including compile errors. "+
+				"logs returns the build errors, panics and request logs written since the previous call, so call it after editing code to see whether the rebuild succeeded."),
+			mcp.WithString("action", mcp.Required(), mcp.Enum("start", "stop", "status", "logs"), mcp.Description("start, stop, status or logs")),
+			mcp.WithString("port", mcp.Description("Port for start (default: 3000)")),
+			mcp.WithNumber("timeout", mcp.Description("Seconds start waits for the first build (default: 60)")),
+			mcp.WithNumber("wait", mcp.Description("Seconds logs waits for new output when there is none (default: 0)")),
+			mcp.WithNumber("tail", mcp.Description("Return the last N lines of output instead of new output")),
+		),
+		s.handleDev,
+	)
+
+	// nexo_request - Send a request to the app
+	s.mcpServer.AddTool(
+		mcp.NewTool("nexo_request",
+			mcp.WithDescription("Send an HTTP request to the app and return the status, headers and body. "+
+				"Uses the dev server started by nexo_dev, including the logs it wrote for the request. "+
+				"Otherwise the request is sent with nexo call, which builds and starts the app if no dev server is running. "+
+				"Use it to check that generated routes work."),
+			mcp.WithString("method", mcp.Required(), mcp.Description("HTTP method, e.g. GET or POST")),
+			mcp.WithString("path", mcp.Required(), mcp.Description("Request path including the query string, e.g. /api/users?page=2")),
+			mcp.WithObject("headers", mcp.Description("Request headers as name/value pairs")),
+			mcp.WithString("body", mcp.Description("Request body; JSON bodies are sent as application/json unless Content-Type is set")),
+		),
+		s.handleRequest,
+	)
 }
 
 // ServeStdio starts the MCP server over stdio.
 func (s *Server) ServeStdio() error {
+	defer s.closeDev()
 	return server.ServeStdio(s.mcpServer)
 }
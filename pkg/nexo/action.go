@@ -0,0 +1,255 @@
+package nexo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// CSRFFieldName is the form field actions read the CSRF token from.
+// CSRFField renders it.
+const CSRFFieldName = "_csrf"
+
+// CSRFHeader is the header actions read the CSRF token from when the form
+// field is missing, e.g. for HTMX or fetch requests.
+const CSRFHeader = "X-CSRF-Token"
+
+// ActionFieldName is the form field naming the action a form submits to,
+// on pages with more than one.
+const ActionFieldName = "_action"
+
+// csrfCookie is the cookie holding a client's CSRF token.
+const csrfCookie = "_nexo_csrf"
+
+// csrfTokenLen is the length of an encoded CSRF token.
+var csrfTokenLen = base64.RawURLEncoding.EncodedLen(32)
+
+// Actions are the form actions of a page, by name: "" for the Action
+// function of an action.go and e.g. "delete" for ActionDelete. A form picks
+// one with its _action field, matched ignoring case, and gets the default
+// action without it.
+type Actions map[string]HandlerFunc
+
+// ActionHandler returns the POST handler for the form actions of the page
+// at pattern. The generated routes register one for every action.go.
+//
+// Requests without the client's CSRF token get a 403, and requests naming
+// an action that doesn't exist a 400. An action responds like any handler,
+// typically with a redirect; one that returns nil without responding
+// redirects back to the page with 303 See Other. One that returns a
+// *ValidationError, such as the error from c.BindForm, renders the page's
+// GET handler again with a 422, and the page shows the errors and the
+// submitted values with ActionError and ActionValue.
+//
+// Example:
+//
+//	app.Post("/contact", app.ActionHandler("/contact", nexo.Actions{
+//	    "": func(c *nexo.Context) error {
+//	        var form ContactForm
+//	        if err := c.BindForm(&form); err != nil {
+//	            return err
+//	        }
+//	        return c.Redirect("/contact/sent", http.StatusSeeOther)
+//	    },
+//	}))
+func (a *App) ActionHandler(pattern string, actions Actions) HandlerFunc {
+	a.actions = true
+	return func(c *Context) error {
+		if !validCSRFToken(c) {
+			return NewHTTPError(http.StatusForbidden, "invalid CSRF token")
+		}
+		action, ok := actions[strings.ToLower(c.FormValue(ActionFieldName))]
+		if !ok {
+			return NewHTTPError(http.StatusBadRequest, "unknown action")
+		}
+
+		err := action(c)
+		if err == nil && !c.Written() {
+			return c.Redirect(c.Request.URL.RequestURI(), http.StatusSeeOther)
+		}
+		valErr, ok := IsValidationError(err)
+		if !ok || c.Written() {
+			return err
+		}
+		page := a.routeTree.handler(http.MethodGet, pattern)
+		if page == nil {
+			return err
+		}
+
+		// Render the page again, with a 422 unless htmx, which doesn't
+		// swap error responses, made the request
+		scope := actionScopeFrom(c.Context())
+		if scope == nil {
+			scope = &actionScope{c: c}
+			c.WithContext(context.WithValue(c.Context(), actionScopeKey{}, scope))
+		}
+		scope.errors = valErr
+		c.VaryHTMX()
+		if !c.IsHTMX() {
+			c.Response = &failedActionWriter{ResponseWriter: c.Response, status: http.StatusUnprocessableEntity}
+		}
+		return page(c)
+	}
+}
+
+// actionScope is what the action helpers need for the current request.
+type actionScope struct {
+	c      *Context
+	token  string           // The client's CSRF token
+	errors *ValidationError // Set when a failed action renders the page
+}
+
+// actionScopeKey is the request context key for the actionScope.
+type actionScopeKey struct{}
+
+// actionScopeFrom returns the request's actionScope, or nil outside of apps
+// with actions.
+func actionScopeFrom(ctx context.Context) *actionScope {
+	scope, _ := ctx.Value(actionScopeKey{}).(*actionScope)
+	return scope
+}
+
+// actionsMiddleware gives clients a CSRF token, in a cookie before the
+// handler writes the response, and makes it available to CSRFField.
+func actionsMiddleware(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		scope := &actionScope{c: c}
+		if cookie, err := c.Request.Cookie(csrfCookie); err == nil && len(cookie.Value) == csrfTokenLen {
+			scope.token = cookie.Value
+		} else {
+			b := make([]byte, 32)
+			_, _ = rand.Read(b)
+			scope.token = base64.RawURLEncoding.EncodeToString(b)
+			c.SetCookie(&http.Cookie{
+				Name:     csrfCookie,
+				Value:    scope.token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   c.Request.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		c.WithContext(context.WithValue(c.Context(), actionScopeKey{}, scope))
+		return next(c)
+	}
+}
+
+// validCSRFToken reports whether the request carries the CSRF token in its
+// cookie, in the form field or the header.
+func validCSRFToken(c *Context) bool {
+	cookie, err := c.Request.Cookie(csrfCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	token := c.FormValue(CSRFFieldName)
+	if token == "" {
+		token = c.Header(CSRFHeader)
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) == 1
+}
+
+// CSRFToken returns the client's CSRF token, which requests to actions
+// must send in the _csrf form field or the X-CSRF-Token header. It is empty
+// in apps without actions.
+//
+// Example:
+//
+//	<body hx-headers={ `{"X-CSRF-Token": "` + nexo.CSRFToken(ctx) + `"}` }>
+func CSRFToken(ctx context.Context) string {
+	if scope := actionScopeFrom(ctx); scope != nil {
+		return scope.token
+	}
+	return ""
+}
+
+// CSRFField renders the hidden input with the CSRF token that forms
+// posting to actions need:
+//
+//	<form method="post">
+//	    @nexo.CSRFField()
+//	    <input name="email" value={ nexo.ActionValue(ctx, "email") }/>
+//	    <button>Subscribe</button>
+//	</form>
+func CSRFField() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<input type="hidden" name="`+CSRFFieldName+`" value="`+templ.EscapeString(CSRFToken(ctx))+`">`)
+		return err
+	})
+}
+
+// ActionErrors returns the violations of the action that failed, for a page
+// rendered after a *ValidationError. It is nil otherwise.
+func ActionErrors(ctx context.Context) []FieldError {
+	if scope := actionScopeFrom(ctx); scope != nil && scope.errors != nil {
+		return scope.errors.Fields
+	}
+	return nil
+}
+
+// ActionError returns the first violation for field of the action that
+// failed, or "" if there is none.
+//
+// Example:
+//
+//	if msg := nexo.ActionError(ctx, "email"); msg != "" {
+//	    <p class="error">{ msg }</p>
+//	}
+func ActionError(ctx context.Context, field string) string {
+	for _, f := range ActionErrors(ctx) {
+		if f.Field == field {
+			return f.Message
+		}
+	}
+	return ""
+}
+
+// ActionValue returns the value submitted for field to the action that
+// failed, so the form can keep what the user typed. It is "" when the page
+// isn't rendered for a failed action.
+func ActionValue(ctx context.Context, field string) string {
+	if scope := actionScopeFrom(ctx); scope != nil && scope.errors != nil {
+		return scope.c.Request.PostFormValue(field)
+	}
+	return ""
+}
+
+// failedActionWriter sends status in place of 200, so a page rendered for
+// a failed action isn't mistaken for a success.
+type failedActionWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *failedActionWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		code = w.status
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *failedActionWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for pages that stream their loading state.
+func (w *failedActionWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *failedActionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
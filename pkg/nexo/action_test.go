@@ -0,0 +1,153 @@
+package nexo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestActionHandler(t *testing.T) {
+	type contactForm struct {
+		Email string `form:"email"`
+	}
+
+	app := New(WithValidator(ValidatorFunc(func(v any) error {
+		if form, ok := v.(*contactForm); ok && !strings.Contains(form.Email, "@") {
+			return &ValidationError{Fields: []FieldError{{Field: "email", Message: "must be a valid email"}}}
+		}
+		return nil
+	})))
+	app.DisableLogger()
+	app.Get("/contact", func(c *Context) error {
+		page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if err := CSRFField().Render(ctx, w); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "|"+ActionValue(ctx, "email")+"|"+ActionError(ctx, "email"))
+			return err
+		})
+		return TemplComponent(c, http.StatusOK, page)
+	})
+	app.Post("/contact", app.ActionHandler("/contact", Actions{
+		"": func(c *Context) error {
+			var form contactForm
+			if err := c.BindForm(&form); err != nil {
+				return err
+			}
+			return c.Redirect("/contact/sent", http.StatusSeeOther)
+		},
+		"archive": func(c *Context) error {
+			return nil
+		},
+	}))
+	app.Mount()
+
+	// The page gives the client a CSRF token, in a cookie and the form
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/contact", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != csrfCookie || len(cookies[0].Value) != csrfTokenLen {
+		t.Fatalf("expected a CSRF cookie, got %v", cookies)
+	}
+	token := cookies[0].Value
+	if !strings.Contains(w.Body.String(), `<input type="hidden" name="_csrf" value="`+token+`">`) {
+		t.Errorf("expected the CSRF field in the page, got %q", w.Body.String())
+	}
+
+	post := func(form url.Values, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/contact?from=nav", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: csrfCookie, Value: token})
+		for k, v := range header {
+			req.Header[http.CanonicalHeaderKey(k)] = v
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		form     url.Values
+		header   http.Header
+		status   int
+		location string
+		body     string
+	}{
+		{
+			name:   "missing token",
+			form:   url.Values{"email": {"ada@example.com"}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "wrong token",
+			form:   url.Values{CSRFFieldName: {strings.Repeat("x", csrfTokenLen)}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:     "default action",
+			form:     url.Values{CSRFFieldName: {token}, "email": {"ada@example.com"}},
+			status:   http.StatusSeeOther,
+			location: "/contact/sent",
+		},
+		{
+			name:     "token in header",
+			form:     url.Values{"email": {"ada@example.com"}},
+			header:   http.Header{CSRFHeader: {token}},
+			status:   http.StatusSeeOther,
+			location: "/contact/sent",
+		},
+		{
+			name:     "named action without a response",
+			form:     url.Values{CSRFFieldName: {token}, ActionFieldName: {"Archive"}},
+			status:   http.StatusSeeOther,
+			location: "/contact?from=nav",
+		},
+		{
+			name:   "unknown action",
+			form:   url.Values{CSRFFieldName: {token}, ActionFieldName: {"delete"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "validation error",
+			form:   url.Values{CSRFFieldName: {token}, "email": {"ada"}},
+			status: http.StatusUnprocessableEntity,
+			body:   "|ada|must be a valid email",
+		},
+		{
+			name:   "validation error from htmx",
+			form:   url.Values{CSRFFieldName: {token}, "email": {"ada"}},
+			header: http.Header{"Hx-Request": {"true"}},
+			status: http.StatusOK,
+			body:   "|ada|must be a valid email",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(tt.form, tt.header)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Location"); got != tt.location {
+				t.Errorf("expected Location %q, got %q", tt.location, got)
+			}
+			if tt.body != "" && !strings.HasSuffix(w.Body.String(), tt.body) {
+				t.Errorf("expected body ending in %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestActionHelpers_WithoutActions(t *testing.T) {
+	ctx := context.Background()
+	if CSRFToken(ctx) != "" || ActionErrors(ctx) != nil || ActionError(ctx, "email") != "" || ActionValue(ctx, "email") != "" {
+		t.Error("expected the action helpers to be empty outside of apps with actions")
+	}
+}
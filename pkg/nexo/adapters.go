@@ -0,0 +1,76 @@
+package nexo
+
+import "net/http"
+
+// WrapHandler adapts an http.Handler to a HandlerFunc, so existing
+// net/http handlers such as pprof, Prometheus or third-party webhooks can be
+// mounted on an app. Route parameters stay available through chi.URLParam.
+//
+// Example:
+//
+//	app.Get("/metrics", nexo.WrapHandler(promhttp.Handler()))
+func WrapHandler(h http.Handler) HandlerFunc {
+	return func(c *Context) error {
+		rw := newResponseWriter(c.Response)
+		h.ServeHTTP(rw, c.Request)
+		if rw.Written() {
+			c.written = true
+			c.status = rw.Status()
+		}
+		return nil
+	}
+}
+
+// ToHTTPHandler adapts a HandlerFunc to an http.Handler, so Nexo handlers
+// can be used with net/http servers, muxes and middleware. Returned errors
+// are written like they are for app routes.
+//
+// Example:
+//
+//	mux.Handle("/health", nexo.ToHTTPHandler(func(c *nexo.Context) error {
+//	    return c.String(200, "ok")
+//	}))
+func ToHTTPHandler(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := NewContext(w, r)
+		defer c.finish()
+		if err := h(c); err != nil {
+			handleError(c, err)
+		}
+	})
+}
+
+// WrapMiddleware adapts standard func(http.Handler) http.Handler middleware,
+// such as chi or gorilla middleware, to a MiddlewareFunc for App.Use and
+// middleware.go files. Request and response writer changes the middleware
+// makes are visible to later handlers. Their errors are written inside the
+// middleware, so middleware that rewrites responses, such as compression,
+// sees error responses too.
+//
+// Example:
+//
+//	app.Use(nexo.WrapMiddleware(middleware.RealIP))
+func WrapMiddleware(mw func(http.Handler) http.Handler) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			req, res := c.Request, c.Response
+			defer func() { c.Request, c.Response = req, res }()
+
+			var err error
+			rw := newResponseWriter(res)
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.Request, c.Response = r, w
+				if err = next(c); err != nil {
+					handleError(c, err)
+				}
+			})).ServeHTTP(rw, req)
+
+			// The middleware may respond without calling next
+			if rw.Written() && !c.written {
+				c.written = true
+				c.status = rw.Status()
+			}
+			return err
+		}
+	}
+}
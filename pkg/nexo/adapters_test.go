@@ -0,0 +1,148 @@
+package nexo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestWrapHandler(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/hooks/{provider}", WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Provider", chi.URLParam(r, "provider"))
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("queued"))
+	})))
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hooks/github", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", w.Code)
+	}
+	if w.Body.String() != "queued" {
+		t.Errorf("expected body %q, got %q", "queued", w.Body.String())
+	}
+	if got := w.Header().Get("X-Provider"); got != "github" {
+		t.Errorf("expected X-Provider github, got %q", got)
+	}
+}
+
+func TestWrapHandler_MarksWritten(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	h := WrapHandler(http.NotFoundHandler())
+	if err := h(c); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Written() {
+		t.Error("expected the context to be marked written")
+	}
+
+	// Errors after the wrapped handler responded don't write a second body
+	handleError(c, NewHTTPError(http.StatusInternalServerError, "boom"))
+	if strings.Contains(w.Body.String(), "boom") {
+		t.Errorf("unexpected error body %q", w.Body.String())
+	}
+}
+
+func TestToHTTPHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/health", ToHTTPHandler(func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	}))
+	mux.Handle("/fail", ToHTTPHandler(func(c *Context) error {
+		return BadRequest("missing name")
+	}))
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/health", http.StatusOK, "ok"},
+		{"/fail", http.StatusBadRequest, "missing name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("expected body to contain %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+type ctxKey string
+
+func TestWrapMiddleware(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Wrapped", "yes")
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey("user"), "alice")))
+		})
+	}))
+	app.Get("/me", func(c *Context) error {
+		return c.String(http.StatusOK, c.Context().Value(ctxKey("user")).(string))
+	})
+	app.Get("/fail", func(c *Context) error {
+		return NotFound("no such user")
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+	if w.Body.String() != "alice" || w.Header().Get("X-Wrapped") != "yes" {
+		t.Errorf("got body %q, X-Wrapped %q", w.Body.String(), w.Header().Get("X-Wrapped"))
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "no such user") {
+		t.Errorf("got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestWrapMiddleware_ShortCircuit(t *testing.T) {
+	requireKey := WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-API-Key") == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	called := false
+	h := requireKey(func(c *Context) error {
+		called = true
+		return c.NoContent()
+	})
+
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := h(c); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Error("expected the handler not to be called")
+	}
+	if w.Code != http.StatusUnauthorized || !c.Written() {
+		t.Errorf("expected a written 401, got %d (written %v)", w.Code, c.Written())
+	}
+}
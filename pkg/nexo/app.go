@@ -2,10 +2,19 @@ package nexo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/netip"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -19,9 +28,15 @@ type OpenAPIOptions struct {
 	Version     string // API version (default: "1.0.0")
 	Description string // API description
 
+	// AppFS is the app directory to document, e.g. an embedded copy of app/
+	// for binaries deployed without their source (default: the app
+	// directory on disk). See NewScannerFS.
+	AppFS fs.FS
+
 	// Endpoints
-	SpecPath string // Path for spec (default: "/openapi.json")
-	DocsPath string // Path for Swagger UI (default: "/docs")
+	SpecPath  string // Path for spec (default: "/openapi.json")
+	DocsPath  string // Path for Swagger UI (default: "/docs")
+	RedocPath string // Path for Redoc (default: DocsPath + "/redoc")
 
 	// Features
 	Enabled bool // Enable/disable (default: true when called)
@@ -47,6 +62,64 @@ type App struct {
 	// server is the HTTP server (set during Listen)
 	server *http.Server
 
+	// mu guards server, mounted and services, which may be read from other goroutines
+	mu sync.Mutex
+
+	// mounted is set once Mount has been called
+	mounted bool
+
+	// cacheStore holds the responses cached by the Cache middleware (set by WithCacheStore)
+	cacheStore CacheStore
+
+	// services holds the values registered with Provide
+	services *services
+
+	// metricsPath serves DefaultMetrics when set (see WithMetrics)
+	metricsPath string
+
+	// devDashboard serves the dev dashboard (set by WithDevDashboard, on under nexo dev)
+	devDashboard bool
+
+	// devRequests holds the recent requests shown on the dev dashboard
+	devRequests *requestLog
+
+	// latencySummary collects per-route latencies (set by WithLatencySummary, on under nexo dev)
+	latencySummary bool
+
+	// latencyInterval is how often the latency summary is printed; zero prints it on demand
+	latencyInterval time.Duration
+
+	// latencyStats holds the latencies for the latency summary
+	latencyStats *latencyStats
+
+	// testMount mounts the app on the first call to Test
+	testMount    sync.Once
+	testMountErr error
+
+	// unixSocket is the socket path to listen on (set by WithUnixSocket)
+	unixSocket string
+
+	// unixSocketPerm is the socket file's permissions (set by WithUnixSocket)
+	unixSocketPerm os.FileMode
+
+	// gracefulRestart hands the listener to a new process on SIGUSR2 (set by WithGracefulRestart)
+	gracefulRestart bool
+
+	// restartReady reports readiness to the process this one replaces
+	restartReady *os.File
+
+	// shutdownTimeout is how long shutdown waits for in-flight requests
+	shutdownTimeout time.Duration
+
+	// startHooks run before the server starts (set by OnStart)
+	startHooks []func(ctx context.Context) error
+
+	// shutdownHooks run once during Shutdown (set by OnShutdown)
+	shutdownHooks []func(ctx context.Context) error
+
+	// shutdownOnce ensures the shutdown hooks run only once
+	shutdownOnce sync.Once
+
 	// logger is the app-level request logger (captures all requests including proxy)
 	logger *RequestLogger
 
@@ -55,6 +128,39 @@ type App struct {
 
 	// openAPIConfig holds OpenAPI configuration
 	openAPIConfig *OpenAPIOptions
+
+	// liveReloadScript is injected into HTML responses when running under nexo dev
+	liveReloadScript []byte
+
+	// cookieCodec signs and encrypts cookies (set by WithCookieSecret)
+	cookieCodec *cookieCodec
+
+	// actions gives clients CSRF tokens (set by ActionHandler)
+	actions bool
+
+	// validator validates bound request data (set by WithValidator)
+	validator Validator
+
+	// maintenance turns requests away while maintenance mode is on (see SetMaintenance)
+	maintenance *maintenance
+
+	// trustedProxies may set X-Forwarded-For (set by WithTrustedProxies)
+	trustedProxies []netip.Prefix
+
+	// handlerTimeout is the default request deadline (set by WithHandlerTimeout)
+	handlerTimeout time.Duration
+
+	// notFound handles requests that match no route (set by NotFound)
+	notFound HandlerFunc
+
+	// errorHandler handles errors returned by handlers (set by OnError)
+	errorHandler ErrorHandler
+
+	// authenticator checks routes with RouteConfig.RequireAuth (set by WithAuthenticator)
+	authenticator Authenticator
+
+	// renderer holds the error and loading components of the app's pages (see Renderer)
+	renderer *StreamingRenderer
 }
 
 // New creates a new Nexo application with the given options.
@@ -66,6 +172,15 @@ func New(opts ...Option) *App {
 		routeTree:     NewRouteTree(),
 		logger:        NewRequestLogger(DefaultRequestLoggerConfig()),
 		loggerEnabled: true, // Enabled by default
+
+		shutdownTimeout: DefaultShutdownTimeout,
+		cacheStore:      NewMemoryCacheStore(),
+		maintenance:     newMaintenance(MaintenanceConfig{}),
+		renderer:        NewStreamingRenderer(),
+
+		liveReloadScript: liveReloadScriptFromEnv(),
+		devDashboard:     devModeFromEnv(),
+		latencySummary:   devModeFromEnv(),
 	}
 
 	// Apply options
@@ -73,8 +188,18 @@ func New(opts ...Option) *App {
 		opt(app)
 	}
 
+	if app.devDashboard {
+		app.devRequests = newRequestLog(devDashboardRequests)
+	}
+	if app.latencySummary {
+		app.latencyStats = newLatencyStats()
+	}
+
 	// Create scanner with app directory
 	app.scanner = NewScanner(app.config.AppDir)
+	if devModeFromEnv() {
+		app.scanner.SetCacheDir(DefaultScanCacheDir)
+	}
 
 	return app
 }
@@ -131,12 +256,93 @@ func (a *App) RouteTree() *RouteTree {
 
 // Scan scans the app directory and registers all routes.
 func (a *App) Scan() error {
-	return a.scanner.Scan(a.routeTree)
+	_, err := a.scanner.Scan(a.routeTree)
+	return err
 }
 
 // Mount registers all routes with the chi router.
 func (a *App) Mount() {
-	a.routeTree.Mount(a.router, a.middlewares)
+	a.mu.Lock()
+	first := !a.mounted
+	a.mounted = true
+	a.mu.Unlock()
+
+	middlewares := a.middlewares
+	if a.renderer.hasSlots() {
+		middlewares = append(slices.Clone(middlewares), a.renderer.slotsMiddleware)
+	}
+	if a.actions {
+		middlewares = append(slices.Clone(middlewares), actionsMiddleware)
+	}
+	a.routeTree.Mount(a.router, middlewares)
+	if first && a.config.Dev.Debug {
+		a.mountDebug()
+	}
+	if first && a.devDashboard {
+		a.mountDevDashboard()
+	}
+	if first && a.metricsPath != "" {
+		a.router.Handle(a.metricsPath, DefaultMetrics)
+	}
+
+	notFound := a.notFound
+	if notFound == nil {
+		notFound = func(c *Context) error {
+			if renderNotFoundPage(c, a.renderer.Renderer) {
+				return nil
+			}
+			return NewHTTPError(http.StatusNotFound, "not found")
+		}
+	}
+	a.router.NotFound(a.routeTree.wrapHandler(&Route{Handler: notFound}, a.middlewares))
+}
+
+// NotFound sets the handler for requests that match no route. Global
+// middleware runs before it, and errors it returns go through OnError.
+// Without one, unmatched requests get the nearest 404 component registered
+// on the Renderer (see not-found.templ) if they accept HTML, and a 404 error
+// from the error handler otherwise. Call it before Mount.
+//
+// Example:
+//
+//	app.NotFound(func(c *nexo.Context) error {
+//	    return c.Render(404, pages.NotFound(c.Path()))
+//	})
+func (a *App) NotFound(handler HandlerFunc) {
+	a.notFound = handler
+}
+
+// OnError sets the handler for errors returned by handlers and middleware,
+// including the 404 and 405 errors for unmatched requests. It replaces the
+// default JSON error response; return an error, or call
+// DefaultErrorHandler, to fall back to it.
+//
+// Example:
+//
+//	app.OnError(func(c *nexo.Context, err error) error {
+//	    if httpErr, ok := nexo.IsHTTPError(err); ok {
+//	        return c.JSON(httpErr.Code, Envelope{OK: false, Error: httpErr.Message})
+//	    }
+//	    slog.Error("request failed", "path", c.Path(), "err", err)
+//	    return c.JSON(500, Envelope{OK: false, Error: "something went wrong"})
+//	})
+func (a *App) OnError(handler ErrorHandler) {
+	a.errorHandler = handler
+}
+
+// Renderer returns the app's renderer. Error components registered on it,
+// usually by the generated routes for error.templ files, render errors
+// returned by handlers and loaders under their path prefix as HTML pages
+// instead of the JSON error, for requests that accept HTML. Loading
+// components, from loading.templ files, are streamed by RenderWithLoading
+// while slow pages load.
+//
+// Example:
+//
+//	app.Renderer().SetErrorComponent("/dashboard", dashboard.Error)
+//	app.Renderer().SetLoadingComponent("/dashboard", dashboard.Loading())
+func (a *App) Renderer() *StreamingRenderer {
+	return a.renderer
 }
 
 // ServeHTTP implements http.Handler interface.
@@ -144,9 +350,52 @@ func (a *App) Mount() {
 func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 
+	if a.cookieCodec != nil {
+		r = withCookieCodec(r, a.cookieCodec)
+	}
+	if a.validator != nil {
+		r = withValidator(r, a.validator)
+	}
+	if a.authenticator != nil {
+		r = withAuthenticator(r, a.authenticator)
+	}
+	if a.errorHandler != nil {
+		r = withErrorHandler(r, a.errorHandler)
+	}
+	if a.services != nil {
+		r = withServices(r, a.services)
+	}
+	if a.renderer.hasErrorComponents() {
+		r = withRenderer(r, a.renderer.Renderer)
+	}
+	r = withCacheStore(r, a.cacheStore)
+	if a.trustedProxies != nil {
+		r = withTrustedProxies(r, a.trustedProxies)
+	}
+	if l := a.slogger(); l != nil {
+		r = withSlog(r, l)
+	}
+	if a.handlerTimeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), a.handlerTimeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	// Inject the live reload script into HTML pages under nexo dev
+	if a.liveReloadScript != nil && wantsLiveReload(r) {
+		lw := newLiveReloadWriter(w, a.liveReloadScript)
+		defer lw.finish()
+		w = lw
+	}
+
 	// Wrap response writer to capture status and size
 	rw := newResponseWriter(w)
 
+	if a.maintenance.serve(rw, r) {
+		a.logRequest(r, rw, start, nil, nil)
+		return
+	}
+
 	var proxyAction *ProxyAction
 
 	// Execute proxy if configured
@@ -173,6 +422,14 @@ func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r = ctx.Request
 	}
 
+	// Give the router a route context up front, so the matched route
+	// pattern is still available for the latency summary once it returns
+	if a.latencyStats != nil {
+		rctx := chi.NewRouteContext()
+		rctx.Routes = a.router
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
 	// Continue to router
 	a.router.ServeHTTP(rw, r)
 
@@ -180,29 +437,135 @@ func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	a.logRequest(r, rw, start, proxyAction, nil)
 }
 
+// slogger returns the slog.Logger of the app-level request logger, or nil
+// if it doesn't have one.
+func (a *App) slogger() *slog.Logger {
+	if a.logger == nil {
+		return nil
+	}
+	return a.logger.config.Slog
+}
+
 // logRequest logs a request using the app-level logger if enabled.
 func (a *App) logRequest(r *http.Request, rw *responseWriter, start time.Time, proxyAction *ProxyAction, err error) {
+	a.recordRequest(r, rw, start)
+
 	if !a.loggerEnabled || a.logger == nil {
 		return
 	}
 
 	latency := time.Since(start)
-	a.logger.Log(r, rw.Status(), rw.Size(), latency, proxyAction, err)
+	stream := strings.HasPrefix(rw.Header().Get("Content-Type"), "text/event-stream")
+	requestID := rw.Header().Get(a.logger.config.RequestIDHeader)
+	a.logger.log(r, rw.Status(), rw.Size(), latency, proxyAction, err, requestID, stream)
 }
 
+// DevProxyEnv is set by `nexo dev` when the app runs behind its dev proxy.
+const DevProxyEnv = "NEXO_DEV_PROXY"
+
+// DefaultShutdownTimeout is how long shutdown waits for in-flight requests
+// unless WithShutdownTimeout is used.
+const DefaultShutdownTimeout = 10 * time.Second
+
 // Listen starts the HTTP server and listens for requests.
 // It handles graceful shutdown on SIGINT and SIGTERM.
 func (a *App) Listen(addr ...string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return a.ListenWithContext(ctx, addr...)
+}
+
+// ListenWithContext starts the HTTP server and shuts it down gracefully
+// when ctx is done: it stops accepting connections, waits for in-flight
+// requests for up to the shutdown timeout and runs the OnShutdown hooks.
+// It returns nil once the server has stopped.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	if err := app.ListenWithContext(ctx, ":3000"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (a *App) ListenWithContext(ctx context.Context, addr ...string) error {
 	address := a.config.ListenAddress()
 	if len(addr) > 0 {
 		address = addr[0]
 	}
 
+	var ln net.Listener
+	if a.gracefulRestart {
+		inherited, ready, err := inheritedListener()
+		if err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+		ln, a.restartReady = inherited, ready
+	}
+	if ln == nil {
+		var err error
+		if ln, err = a.listen(address); err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+	}
+	return a.serve(ctx, ln)
+}
+
+// Listener serves the app on ln instead of opening a TCP port, e.g. a
+// socket-activated or sandbox-provided listener. Like Listen, it shuts down
+// gracefully on SIGINT and SIGTERM.
+//
+// Example:
+//
+//	ln, err := net.Listen("unix", "/run/app.sock")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	log.Fatal(app.Listener(ln))
+func (a *App) Listener(ln net.Listener) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return a.serve(ctx, ln)
+}
+
+// listen opens a TCP listener on address, or the Unix socket set by
+// WithUnixSocket.
+func (a *App) listen(address string) (net.Listener, error) {
+	if a.unixSocket == "" {
+		return net.Listen("tcp", address)
+	}
+
+	// Remove a socket left behind by a previous run, unless it's in use
+	if info, err := os.Stat(a.unixSocket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", a.unixSocket); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("socket %s is in use", a.unixSocket)
+		}
+		if err := os.Remove(a.unixSocket); err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", a.unixSocket)
+	if err != nil {
+		return nil, err
+	}
+	if a.unixSocketPerm != 0 {
+		if err := os.Chmod(a.unixSocket, a.unixSocketPerm); err != nil {
+			_ = ln.Close()
+			return nil, err
+		}
+	}
+	return ln, nil
+}
+
+// serve serves the app on ln until ctx is done, then shuts down gracefully.
+func (a *App) serve(ctx context.Context, ln net.Listener) error {
 	// Only scan if no routes have been registered yet
 	// This allows RegisterRoutes() to be called before Listen() to register
 	// the actual handlers instead of placeholders
 	if len(a.routeTree.routes) == 0 {
 		if err := a.Scan(); err != nil {
+			_ = ln.Close()
 			return fmt.Errorf("failed to scan routes: %w", err)
 		}
 	}
@@ -210,62 +573,204 @@ func (a *App) Listen(addr ...string) error {
 	// Mount routes to router
 	a.Mount()
 
+	for _, hook := range a.startHooks {
+		if err := hook(ctx); err != nil {
+			_ = ln.Close()
+			return fmt.Errorf("start hook failed: %w", err)
+		}
+	}
+
+	a.maintenance.watchSignal(ctx, a.slogger())
+	a.watchLatencySummary(ctx)
+
+	if a.gracefulRestart {
+		var restarted context.CancelFunc
+		ctx, restarted = context.WithCancel(ctx)
+		defer restarted()
+		a.watchRestart(ctx, ln, restarted)
+	}
+
 	// Create server - use App as handler to enable proxy
-	a.server = &http.Server{
-		Addr:              address,
+	cfg := a.config.Server.withDefaults()
+	server := &http.Server{
+		Addr:              ln.Addr().String(),
 		Handler:           a,
-		ReadTimeout:       15 * time.Second,
-		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      15 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
 	}
-
-	// Channel for shutdown signals
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	a.mu.Lock()
+	a.server = server
+	a.mu.Unlock()
 
 	// Channel for server errors
 	serverErr := make(chan error, 1)
 
 	// Start server in goroutine
 	go func() {
-		fmt.Printf("\n  Nexo running at http://localhost%s\n\n", address)
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			serverErr <- err
+		// Under the nexo dev proxy the app port is internal; the proxy prints the address to open
+		if os.Getenv(DevProxyEnv) == "" {
+			url := listenURL(ln.Addr())
+			diagnostic(a.slogger(), slog.LevelInfo, fmt.Sprintf("\n  Nexo running at %s\n\n", url), "server started", "url", url)
 		}
+		serverErr <- server.Serve(ln)
 	}()
 
-	// Wait for shutdown signal or server error
+	// Let the process this one replaces shut down
+	if a.restartReady != nil {
+		_, _ = a.restartReady.Write([]byte{1})
+		_ = a.restartReady.Close()
+		a.restartReady = nil
+	}
+
+	// Wait for the context to end or the server to stop
 	select {
 	case err := <-serverErr:
+		// Shutdown was called directly
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
 		return fmt.Errorf("server error: %w", err)
-	case <-stop:
-		fmt.Println("\n  Shutting down gracefully...")
+	case <-ctx.Done():
+		diagnostic(a.slogger(), slog.LevelInfo, "\n  Shutting down gracefully...\n", "server shutting down")
 	}
 
 	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
 	defer cancel()
 
-	if err := a.server.Shutdown(ctx); err != nil {
+	if err := a.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("failed to shutdown gracefully: %w", err)
 	}
 
-	fmt.Println("  Server stopped")
+	diagnostic(a.slogger(), slog.LevelInfo, "  Server stopped\n", "server stopped")
 	return nil
 }
 
-// Shutdown gracefully shuts down the server.
+// listenURL returns the URL to print for a listener address.
+func listenURL(addr net.Addr) string {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return addr.Network() + ":" + addr.String()
+	}
+	if tcp.IP == nil || tcp.IP.IsUnspecified() {
+		return fmt.Sprintf("http://localhost:%d", tcp.Port)
+	}
+	return "http://" + tcp.String()
+}
+
+// Shutdown gracefully shuts down the server: it stops accepting
+// connections and waits for in-flight requests until ctx is done, then
+// closes the remaining connections and runs the OnShutdown hooks.
 func (a *App) Shutdown(ctx context.Context) error {
-	if a.server == nil {
-		return nil
+	a.mu.Lock()
+	server := a.server
+	a.mu.Unlock()
+
+	var errs []error
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			// The grace period ran out; drop the connections still open
+			_ = server.Close()
+			errs = append(errs, err)
+		}
 	}
-	return a.server.Shutdown(ctx)
+
+	a.shutdownOnce.Do(func() {
+		// Hooks run in reverse order, like deferred calls
+		for i := len(a.shutdownHooks) - 1; i >= 0; i-- {
+			if err := a.shutdownHooks[i](ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// OnStart registers a hook that runs when the app starts listening, after
+// routes are mounted and before connections are accepted, e.g. to open
+// database pools or warm caches. Hooks run in registration order; if one
+// fails, the server isn't started and Listen returns its error.
+//
+// Example:
+//
+//	app.OnStart(func(ctx context.Context) error {
+//	    return db.PingContext(ctx)
+//	})
+func (a *App) OnStart(hook func(ctx context.Context) error) {
+	a.startHooks = append(a.startHooks, hook)
+}
+
+// OnShutdown registers a hook that runs once during Shutdown, after
+// in-flight requests have finished, e.g. to close database pools. Hooks run
+// in reverse registration order and share Shutdown's context.
+//
+// Example:
+//
+//	app.OnShutdown(func(ctx context.Context) error {
+//	    return db.Close()
+//	})
+func (a *App) OnShutdown(hook func(ctx context.Context) error) {
+	a.shutdownHooks = append(a.shutdownHooks, hook)
+}
+
+// Test runs req through the app, including the proxy and all middleware,
+// and returns the response without opening a socket. On the first call the
+// app is mounted, after scanning the app directory if no routes were
+// registered, unless Mount was already called.
+//
+// Example:
+//
+//	resp, err := app.Test(httptest.NewRequest("GET", "/api/users", nil))
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	if resp.StatusCode != 200 {
+//	    t.Errorf("expected 200, got %d", resp.StatusCode)
+//	}
+func (a *App) Test(req *http.Request) (*http.Response, error) {
+	a.testMount.Do(func() {
+		a.mu.Lock()
+		mounted := a.mounted
+		a.mu.Unlock()
+		if mounted {
+			return
+		}
+
+		if len(a.routeTree.routes) == 0 {
+			if err := a.Scan(); err != nil {
+				a.testMountErr = fmt.Errorf("failed to scan routes: %w", err)
+				return
+			}
+		}
+		a.Mount()
+	})
+	if a.testMountErr != nil {
+		return nil, a.testMountErr
+	}
+
+	// Requests made with http.NewRequest lack the server-side fields
+	req = req.Clone(req.Context())
+	if req.RemoteAddr == "" {
+		req.RemoteAddr = "192.0.2.1:1234"
+	}
+	if req.RequestURI == "" {
+		req.RequestURI = req.URL.RequestURI()
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	return rec.Result(), nil
 }
 
 // Addr returns the address the server is listening on.
 // Returns empty string if server hasn't started.
 func (a *App) Addr() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	if a.server == nil {
 		return ""
 	}
@@ -292,6 +797,20 @@ func (a *App) HasProxy() bool {
 	return a.routeTree.HasProxy()
 }
 
+// OnRouteRegistered registers a callback for every route the app has,
+// including routes registered before it, whether they come from route.go
+// files, generated code or App methods. Use it to build route indexes or
+// register endpoints with service discovery.
+//
+// Example:
+//
+//	app.OnRouteRegistered(func(r nexo.RouteInfo) {
+//	    log.Printf("route %s %s", r.Method, r.Pattern)
+//	})
+func (a *App) OnRouteRegistered(fn func(route RouteInfo)) {
+	a.routeTree.OnRoute(fn)
+}
+
 // RegisterRoute manually registers a route (useful for testing or custom routes).
 func (a *App) RegisterRoute(method, pattern string, handler HandlerFunc) {
 	a.routeTree.AddRoute(&Route{
@@ -302,6 +821,21 @@ func (a *App) RegisterRoute(method, pattern string, handler HandlerFunc) {
 	})
 }
 
+// RegisterScopedRoute registers a route defined in a route group
+// directory, so middleware from the group's middleware.go applies to it
+// and middleware from other groups doesn't. scope is the route's directory
+// relative to the app directory, e.g. "(marketing)/about" for
+// app/(marketing)/about. Generated routes use it for route groups.
+func (a *App) RegisterScopedRoute(method, pattern, scope string, handler HandlerFunc) {
+	a.routeTree.AddRoute(&Route{
+		Method:   method,
+		Pattern:  pattern,
+		Handler:  handler,
+		Scope:    scope,
+		Priority: CalculatePriority(pattern),
+	})
+}
+
 // Get registers a GET route.
 func (a *App) Get(pattern string, handler HandlerFunc) {
 	a.RegisterRoute(http.MethodGet, pattern, handler)
@@ -337,9 +871,42 @@ func (a *App) Options(pattern string, handler HandlerFunc) {
 	a.RegisterRoute(http.MethodOptions, pattern, handler)
 }
 
+// Any registers a route for every standard HTTP method, e.g. for webhook
+// receivers and catch-all proxies. It has the lowest priority: routes
+// registered for a method on the same pattern, before or after, take
+// precedence, so Any only handles the methods left.
+//
+// Example:
+//
+//	app.Any("/webhooks", forward)
+//	app.Head("/webhooks", ping) // HEAD isn't forwarded
+func (a *App) Any(pattern string, handler HandlerFunc) {
+	for _, method := range methodOrder {
+		a.routeTree.AddRoute(&Route{
+			Method:   method,
+			Pattern:  pattern,
+			Handler:  handler,
+			Priority: CalculatePriority(pattern),
+			Fallback: true,
+		})
+	}
+}
+
+// Match registers a route for each of the given HTTP methods.
+//
+// Example:
+//
+//	app.Match([]string{"GET", "POST"}, "/search", search)
+func (a *App) Match(methods []string, pattern string, handler HandlerFunc) {
+	for _, method := range methods {
+		a.RegisterRoute(strings.ToUpper(method), pattern, handler)
+	}
+}
+
 // ServeOpenAPI enables OpenAPI specification and Swagger UI endpoints.
 // GET /openapi.json - Returns the OpenAPI specification
 // GET /docs - Serves Swagger UI
+// GET /docs/redoc - Serves Redoc
 //
 // Example:
 //
@@ -355,6 +922,9 @@ func (a *App) ServeOpenAPI(opts OpenAPIOptions) {
 	if opts.DocsPath == "" {
 		opts.DocsPath = "/docs"
 	}
+	if opts.RedocPath == "" {
+		opts.RedocPath = strings.TrimSuffix(opts.DocsPath, "/") + "/redoc"
+	}
 	if opts.Version == "" {
 		opts.Version = "1.0.0"
 	}
@@ -367,15 +937,39 @@ func (a *App) ServeOpenAPI(opts OpenAPIOptions) {
 	// Register /docs endpoint (Swagger UI)
 	a.router.Get(opts.DocsPath, a.handleSwaggerUI)
 	a.router.Get(opts.DocsPath+"/", a.handleSwaggerUI)
+
+	// Register /docs/redoc endpoint (Redoc)
+	a.router.Get(opts.RedocPath, a.handleRedoc)
+}
+
+// EnableDocs serves API documentation under path: Swagger UI at path, Redoc
+// at path/redoc and the OpenAPI spec at path/openapi.json. The spec is
+// generated from the route sources in the app directory on every request, so
+// it is meant for development; deployed binaries should serve a spec written
+// by `nexo openapi generate`, or use ServeOpenAPI with an embedded AppFS.
+//
+// Example:
+//
+//	app.EnableDocs("/docs")
+func (a *App) EnableDocs(path string) {
+	path = "/" + strings.Trim(path, "/")
+	a.ServeOpenAPI(OpenAPIOptions{
+		SpecPath: strings.TrimSuffix(path, "/") + "/openapi.json",
+		DocsPath: path,
+	})
 }
 
 // handleOpenAPISpec serves the OpenAPI specification as JSON.
 func (a *App) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
-	generator := NewOpenAPIGenerator(a.config.AppDir, OpenAPIConfig{
+	config := OpenAPIConfig{
 		Title:       a.openAPIConfig.Title,
 		Version:     a.openAPIConfig.Version,
 		Description: a.openAPIConfig.Description,
-	})
+	}
+	generator := NewOpenAPIGenerator(a.config.AppDir, config)
+	if a.openAPIConfig.AppFS != nil {
+		generator = NewOpenAPIGeneratorFS(a.openAPIConfig.AppFS, config)
+	}
 
 	jsonBytes, err := generator.GenerateJSON()
 	if err != nil {
@@ -396,6 +990,32 @@ func (a *App) handleSwaggerUI(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(html))
 }
 
+// handleRedoc serves the Redoc HTML page.
+func (a *App) handleRedoc(w http.ResponseWriter, r *http.Request) {
+	html := getRedocHTML(a.openAPIConfig.SpecPath)
+	w.Header().Set("Content-Type", "text/html")
+	_, _ = w.Write([]byte(html))
+}
+
+// getRedocHTML returns the HTML for Redoc.
+func getRedocHTML(specURL string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>API Documentation</title>
+    <style>
+        body { margin: 0; padding: 0; }
+    </style>
+</head>
+<body>
+    <redoc spec-url="%s"></redoc>
+    <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`, specURL)
+}
+
 // getSwaggerUIHTML returns the HTML for Swagger UI.
 func getSwaggerUIHTML(specURL string) string {
 	return fmt.Sprintf(`<!DOCTYPE html>
@@ -432,32 +1052,6 @@ func getSwaggerUIHTML(specURL string) string {
 </html>`, specURL)
 }
 
-// Static serves static files from a directory.
-// The path is the URL path prefix, and dir is the file system directory.
-func (a *App) Static(path string, dir string) {
-	if path == "" {
-		path = "/"
-	}
-	if path[0] != '/' {
-		path = "/" + path
-	}
-
-	// Ensure path ends with /* for catch-all matching
-	pattern := path
-	if pattern[len(pattern)-1] != '/' {
-		pattern += "/"
-	}
-	pattern += "*"
-
-	// Create a file server
-	fs := http.StripPrefix(path, http.FileServer(http.Dir(dir)))
-
-	// Register the handler directly with chi
-	a.router.Get(pattern, func(w http.ResponseWriter, r *http.Request) {
-		fs.ServeHTTP(w, r)
-	})
-}
-
 // Group creates a route group with shared middleware.
 func (a *App) Group(pattern string, fn func(g *RouteGroup)) {
 	g := &RouteGroup{
@@ -534,3 +1128,21 @@ func (g *RouteGroup) Delete(pattern string, handler HandlerFunc) {
 		Middlewares: g.middlewares,
 	})
 }
+
+// Any registers a route in the group for every standard HTTP method.
+func (g *RouteGroup) Any(pattern string, handler HandlerFunc) {
+	g.Match(methodOrder, pattern, handler)
+}
+
+// Match registers a route in the group for each of the given HTTP methods.
+func (g *RouteGroup) Match(methods []string, pattern string, handler HandlerFunc) {
+	for _, method := range methods {
+		g.app.routeTree.AddRoute(&Route{
+			Method:      strings.ToUpper(method),
+			Pattern:     g.prefix + pattern,
+			Handler:     handler,
+			Priority:    CalculatePriority(g.prefix + pattern),
+			Middlewares: g.middlewares,
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package nexo
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 // ---------- App Construction Tests ----------
@@ -288,6 +294,65 @@ func TestApp_AllHTTPMethods(t *testing.T) {
 	}
 }
 
+func TestApp_Any(t *testing.T) {
+	app := New()
+	app.Any("/echo", func(c *Context) error {
+		return c.String(200, c.Method())
+	})
+	app.Mount()
+
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(method, "/echo", nil))
+		if w.Code != 200 || w.Body.String() != method {
+			t.Errorf("%s: got %d %q", method, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestApp_Any_LowestPriority(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Post("/hooks", func(c *Context) error {
+		return c.String(200, "post")
+	})
+	app.Any("/hooks", func(c *Context) error {
+		return c.String(200, "any")
+	})
+	app.Delete("/hooks", func(c *Context) error {
+		return c.String(200, "delete")
+	})
+	app.Mount()
+
+	for method, want := range map[string]string{"GET": "any", "POST": "post", "PUT": "any", "DELETE": "delete"} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(method, "/hooks", nil))
+		if w.Body.String() != want {
+			t.Errorf("%s: expected %q, got %q", method, want, w.Body.String())
+		}
+	}
+	if n := len(app.RouteTree().Routes()); n != 7 {
+		t.Errorf("expected one route per method, got %d", n)
+	}
+}
+
+func TestApp_Match(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Match([]string{"GET", "post"}, "/search", func(c *Context) error {
+		return c.String(200, "results")
+	})
+	app.Mount()
+
+	for method, status := range map[string]int{"GET": 200, "POST": 200, "DELETE": 405} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(method, "/search", nil))
+		if w.Code != status {
+			t.Errorf("%s: expected %d, got %d", method, status, w.Code)
+		}
+	}
+}
+
 // ---------- Static File Tests ----------
 
 func TestApp_Static(t *testing.T) {
@@ -330,6 +395,28 @@ func TestApp_Static_WithoutLeadingSlash(t *testing.T) {
 	}
 }
 
+func TestApp_Static_Embedded(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "static")
+	EmbedStatic(dir, fstest.MapFS{
+		"css/output.css": &fstest.MapFile{Data: []byte("body{}")},
+	})
+	t.Cleanup(func() { EmbedStatic(dir, nil) })
+
+	app := New()
+	app.Static("/static", dir+"/") // Directory paths are matched after cleaning
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/static/css/output.css", nil)
+	app.ServeHTTP(w, r)
+
+	if w.Code != 200 {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+	if w.Body.String() != "body{}" {
+		t.Errorf("expected embedded file, got %q", w.Body.String())
+	}
+}
+
 func TestApp_Static_EmptyPath(t *testing.T) {
 	tmpDir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("hello"), 0644); err != nil {
@@ -496,6 +583,42 @@ func TestRouteGroup_AllMethods(t *testing.T) {
 	}
 }
 
+func TestRouteGroup_AnyAndMatch(t *testing.T) {
+	app := New()
+
+	app.Group("/api", func(g *RouteGroup) {
+		g.Use(func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) error {
+				c.SetHeader("X-Group", "api")
+				return next(c)
+			}
+		})
+		g.Any("/proxy", func(c *Context) error { return c.String(200, "any") })
+		g.Match([]string{"GET", "PUT"}, "/items", func(c *Context) error { return c.String(200, "match") })
+	})
+
+	app.Mount()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{"PATCH", "/api/proxy", "any"},
+		{"DELETE", "/api/proxy", "any"},
+		{"GET", "/api/items", "match"},
+		{"PUT", "/api/items", "match"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Body.String() != tt.body || w.Header().Get("X-Group") != "api" {
+			t.Errorf("%s %s: got body %q, X-Group %q", tt.method, tt.path, w.Body.String(), w.Header().Get("X-Group"))
+		}
+	}
+}
+
 // ---------- Proxy Error Handling Tests ----------
 
 func TestApp_ServeHTTP_ProxyError(t *testing.T) {
@@ -530,3 +653,339 @@ func TestApp_Addr_BeforeStart(t *testing.T) {
 		t.Errorf("expected empty addr before start, got %q", addr)
 	}
 }
+
+func TestApp_EnableDocs(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "api", "health"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	route := `package health
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+// Get reports service health
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "api", "health", "route.go"), []byte(route), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := New(WithAppDir(tmpDir))
+	app.EnableDocs("/docs/")
+
+	tests := []struct {
+		path     string
+		contains string
+	}{
+		{"/docs", "swagger-ui"},
+		{"/docs/redoc", "redoc"},
+		{"/docs/openapi.json", "/api/health"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.contains) {
+				t.Errorf("expected body to contain %q", tt.contains)
+			}
+		})
+	}
+
+	if !strings.Contains(getRedocHTML("/docs/openapi.json"), `spec-url="/docs/openapi.json"`) {
+		t.Error("expected Redoc page to load the spec URL")
+	}
+}
+
+func TestApp_ServeOpenAPI_AppFS(t *testing.T) {
+	app := New(WithAppDir(t.TempDir()))
+	app.ServeOpenAPI(OpenAPIOptions{
+		Title: "Embedded API",
+		AppFS: fstest.MapFS{
+			"api/health/route.go": {Data: []byte(`package health
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+`)},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "/api/health") {
+		t.Errorf("expected the spec to document the embedded routes, got %s", w.Body.String())
+	}
+}
+
+// ---------- NotFound and OnError Tests ----------
+
+func TestApp_DefaultNotFound(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/users", func(c *Context) error { return c.NoContent() })
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"message":"not found"`) {
+		t.Errorf("expected JSON error body, got %q", w.Body.String())
+	}
+}
+
+func TestApp_NotFound(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			c.SetHeader("X-Global", "true")
+			return next(c)
+		}
+	})
+	app.NotFound(func(c *Context) error {
+		return c.HTML(http.StatusNotFound, "<h1>No page at "+c.Path()+"</h1>")
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+	if w.Body.String() != "<h1>No page at /missing</h1>" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+	if w.Header().Get("X-Global") != "true" {
+		t.Error("expected global middleware to run for the NotFound handler")
+	}
+}
+
+func TestApp_OnError(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/fail", func(c *Context) error {
+		return NewHTTPError(http.StatusConflict, "already exists")
+	})
+	app.Get("/users", func(c *Context) error { return c.NoContent() })
+	app.OnError(func(c *Context, err error) error {
+		code := http.StatusInternalServerError
+		if httpErr, ok := IsHTTPError(err); ok {
+			code = httpErr.Code
+		}
+		return c.JSON(code, map[string]any{"ok": false, "reason": err.Error()})
+	})
+	app.Mount()
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		reason string
+	}{
+		{http.MethodGet, "/fail", http.StatusConflict, "409: already exists"},
+		{http.MethodGet, "/missing", http.StatusNotFound, "404: not found"},
+		{http.MethodDelete, "/users", http.StatusMethodNotAllowed, "405: method not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), `"reason":"`+tt.reason+`"`) {
+				t.Errorf("expected the custom envelope, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestApp_OnErrorFallsBack(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/fail", func(c *Context) error {
+		return BadRequest("bad input")
+	})
+	app.OnError(func(c *Context, err error) error {
+		return err
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"message":"bad input"`) {
+		t.Errorf("expected the default error body, got %q", w.Body.String())
+	}
+}
+
+// ---------- Lifecycle Hook Tests ----------
+
+func TestApp_OnStart(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/health", func(c *Context) error { return c.NoContent() })
+
+	var calls []string
+	app.OnStart(func(ctx context.Context) error {
+		calls = append(calls, "start")
+		return nil
+	})
+	app.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "shutdown")
+		return nil
+	})
+
+	// A canceled context starts the server and shuts it down right away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := app.ListenWithContext(ctx, "127.0.0.1:0"); err != nil {
+		t.Fatalf("ListenWithContext failed: %v", err)
+	}
+
+	if strings.Join(calls, ",") != "start,shutdown" {
+		t.Errorf("expected start then shutdown, got %v", calls)
+	}
+}
+
+func TestApp_OnStart_Error(t *testing.T) {
+	app := New()
+	app.Get("/health", func(c *Context) error { return c.NoContent() })
+
+	errDB := errors.New("database unreachable")
+	app.OnStart(func(ctx context.Context) error { return errDB })
+	app.OnStart(func(ctx context.Context) error {
+		t.Error("expected later hooks not to run")
+		return nil
+	})
+
+	err := app.ListenWithContext(context.Background(), "127.0.0.1:0")
+	if !errors.Is(err, errDB) {
+		t.Fatalf("expected the hook error, got %v", err)
+	}
+	if app.Addr() != "" {
+		t.Error("expected the server not to be started")
+	}
+}
+
+func TestApp_OnRouteRegistered(t *testing.T) {
+	app := New()
+	app.Get("/users", func(c *Context) error { return nil })
+
+	var routes []string
+	app.OnRouteRegistered(func(r RouteInfo) {
+		routes = append(routes, r.Method+" "+r.Pattern)
+	})
+
+	app.Post("/users", func(c *Context) error { return nil })
+	app.Group("/api", func(g *RouteGroup) {
+		g.Delete("/items/{id}", func(c *Context) error { return nil })
+	})
+	app.RouteTree().AddRoute(&Route{Method: http.MethodGet, Pattern: "/generated"})
+
+	want := "GET /users,POST /users,DELETE /api/items/{id},GET /generated"
+	if got := strings.Join(routes, ","); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// ---------- Test Client Tests ----------
+
+func TestApp_Test(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			c.SetHeader("X-Middleware", "ran")
+			return next(c)
+		}
+	})
+	_ = app.SetProxy(func(c *Context) (*ProxyResult, error) {
+		if c.Path() == "/old" {
+			return Rewrite("/users/7"), nil
+		}
+		return Continue(), nil
+	}, nil)
+	app.Get("/users/{id}", func(c *Context) error {
+		return c.JSON(200, map[string]string{"id": c.Param("id")})
+	})
+
+	// Requests from http.NewRequest work too
+	req, err := http.NewRequest(http.MethodGet, "/old", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Test failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Middleware") != "ran" {
+		t.Error("expected global middleware to run")
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `"id":"7"`) {
+		t.Errorf("expected the rewritten route, got %s", body)
+	}
+	if req.RequestURI != "" {
+		t.Error("expected the caller's request to be left unchanged")
+	}
+
+	// Later calls reuse the mounted app
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if err != nil {
+		t.Fatalf("Test failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestApp_Test_ScansAppDir(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(filepath.Join(appDir, "users"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	route := "package users\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error { return nil }\n"
+	if err := os.WriteFile(filepath.Join(appDir, "users", "route.go"), []byte(route), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := New(WithAppDir(appDir))
+	app.DisableLogger()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users", nil))
+	if err != nil {
+		t.Fatalf("Test failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	// Scanned routes are placeholders until handlers are generated
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expected the placeholder's 501, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,431 @@
+package nexo
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxMultipartMemory is how much of a multipart body is kept in memory;
+// larger files are stored in temporary files.
+const maxMultipartMemory = 32 << 20
+
+var (
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind decodes the request into the struct v points to, choosing the
+// source from the request:
+//
+//   - Fields with a `query` tag are filled from the query string (see
+//     BindQuery).
+//   - Form and multipart bodies are decoded with BindForm.
+//   - application/xml and text/xml bodies are decoded with BindXML.
+//   - Other bodies are decoded as JSON.
+//
+// GET, HEAD and DELETE requests without a Content-Type only read the query
+// string. Targets other than structs, such as maps, are decoded from JSON.
+// Invalid input returns a 400 HTTPError, and bodies over a size limit (see
+// http.MaxBytesReader and Decompress) a 413. If the app has a Validator (see
+// WithValidator), the result is then validated and failures return a 422
+// HTTPError wrapping a *ValidationError.
+func (c *Context) Bind(v any) error {
+	if err := c.bind(v); err != nil {
+		return err
+	}
+	return c.validate(v)
+}
+
+// bind decodes the request like Bind, without validating.
+func (c *Context) bind(v any) error {
+	if isStructPointer(v) {
+		if err := c.bindQuery(v); err != nil {
+			return err
+		}
+	}
+
+	switch mediaType(c.ContentType()) {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		return c.bindForm(v)
+	case "application/xml", "text/xml":
+		return c.bindXML(v)
+	case "":
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodDelete:
+			return nil
+		}
+	}
+
+	if c.Request.Body == nil {
+		return NewHTTPError(http.StatusBadRequest, "empty request body")
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(v); err != nil {
+		return bodyError(err, "invalid JSON")
+	}
+	return nil
+}
+
+// BindQuery decodes the query string into the struct v points to. Fields
+// are matched by their `query` tag; untagged fields and fields tagged "-"
+// are skipped, and fields of embedded structs are included.
+//
+// Supported field types are strings, bools, integers, floats, time.Time,
+// time.Duration, types implementing encoding.TextUnmarshaler, pointers to
+// these, and slices of these, which collect repeated parameters
+// (?tag=a&tag=b). time.Time values are parsed as RFC 3339 or 2006-01-02,
+// or with the layout in a `layout` tag.
+//
+// Parameters that are missing or empty leave the field unchanged, so set
+// defaults before calling BindQuery. Values that can't be converted return
+// a 400 HTTPError naming the parameter. The result is validated like Bind.
+//
+// Example:
+//
+//	type ListParams struct {
+//	    Page   int       `query:"page"`
+//	    Active *bool     `query:"active"`
+//	    Tags   []string  `query:"tag"`
+//	    Since  time.Time `query:"since" layout:"2006-01-02"`
+//	}
+//
+//	params := ListParams{Page: 1}
+//	if err := c.BindQuery(&params); err != nil {
+//	    return err
+//	}
+func (c *Context) BindQuery(v any) error {
+	if err := c.bindQuery(v); err != nil {
+		return err
+	}
+	return c.validate(v)
+}
+
+func (c *Context) bindQuery(v any) error {
+	return bindValues(v, c.query, nil, "query", "query parameter")
+}
+
+// BindForm decodes an application/x-www-form-urlencoded or
+// multipart/form-data body into the struct v points to. Fields are matched
+// by their `form` tag and converted like BindQuery; uploaded files are
+// bound to *multipart.FileHeader and []*multipart.FileHeader fields. The
+// result is validated like Bind.
+//
+// Example:
+//
+//	type SignupForm struct {
+//	    Email  string                `form:"email"`
+//	    Age    int                   `form:"age"`
+//	    Avatar *multipart.FileHeader `form:"avatar"`
+//	}
+func (c *Context) BindForm(v any) error {
+	if err := c.bindForm(v); err != nil {
+		return err
+	}
+	return c.validate(v)
+}
+
+func (c *Context) bindForm(v any) error {
+	var files map[string][]*multipart.FileHeader
+	if mediaType(c.ContentType()) == "multipart/form-data" {
+		if err := c.Request.ParseMultipartForm(maxMultipartMemory); err != nil {
+			return bodyError(err, "invalid multipart form")
+		}
+		files = c.Request.MultipartForm.File
+	} else if err := c.Request.ParseForm(); err != nil {
+		return bodyError(err, "invalid form")
+	}
+	return bindValues(v, c.Request.PostForm, files, "form", "form field")
+}
+
+// BindXML decodes an XML body into v using encoding/xml, so fields are
+// matched by their `xml` tags. UTF-8, US-ASCII and ISO-8859-1 bodies are
+// supported, whether the charset is set in the Content-Type or in the XML
+// declaration. Malformed XML returns a 400 HTTPError and other charsets a
+// 415. The result is validated like Bind.
+func (c *Context) BindXML(v any) error {
+	if err := c.bindXML(v); err != nil {
+		return err
+	}
+	return c.validate(v)
+}
+
+func (c *Context) bindXML(v any) error {
+	if c.Request.Body == nil {
+		return NewHTTPError(http.StatusBadRequest, "empty request body")
+	}
+	body := io.Reader(c.Request.Body)
+	readCharset := charsetReader
+	// A charset in the Content-Type overrides the XML declaration
+	if _, params, err := mime.ParseMediaType(c.ContentType()); err == nil && params["charset"] != "" {
+		if body, err = charsetReader(params["charset"], body); err != nil {
+			return NewHTTPErrorWithCause(http.StatusUnsupportedMediaType, "unsupported charset", err)
+		}
+		readCharset = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	}
+
+	dec := xml.NewDecoder(body)
+	dec.CharsetReader = readCharset
+	if err := dec.Decode(v); err != nil {
+		switch {
+		case errors.Is(err, io.EOF):
+			return NewHTTPError(http.StatusBadRequest, "empty request body")
+		case errors.Is(err, errUnsupportedCharset):
+			return NewHTTPErrorWithCause(http.StatusUnsupportedMediaType, "unsupported charset", err)
+		}
+		return bodyError(err, "invalid XML")
+	}
+	return nil
+}
+
+var errUnsupportedCharset = errors.New("unsupported charset")
+
+// bodyError returns the HTTPError for a body that couldn't be decoded: 413
+// if it was over its size limit, 400 otherwise.
+func bodyError(err error, message string) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return NewHTTPErrorWithCause(http.StatusRequestEntityTooLarge, "request body too large", err)
+	}
+	return NewHTTPErrorWithCause(http.StatusBadRequest, message, err)
+}
+
+// charsetReader converts input in the named charset to UTF-8.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "latin-1":
+		return &latin1Reader{r: bufio.NewReader(input)}, nil
+	}
+	return nil, fmt.Errorf("%w %q", errUnsupportedCharset, charset)
+}
+
+// latin1Reader converts ISO-8859-1 to UTF-8.
+type latin1Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	for len(l.buf) < len(p) {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if len(l.buf) > 0 {
+				break
+			}
+			return 0, err
+		}
+		l.buf = utf8.AppendRune(l.buf, rune(b))
+	}
+	n := copy(p, l.buf)
+	l.buf = l.buf[n:]
+	return n, nil
+}
+
+// mediaType returns the media type of a Content-Type header, without
+// parameters.
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+// isStructPointer reports whether v is a non-nil pointer to a struct.
+func isStructPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct
+}
+
+// bindValues decodes values and files into the struct v points to,
+// matching fields by tag. kind names the values in error messages, e.g.
+// "query parameter".
+func bindValues(v any, values map[string][]string, files map[string][]*multipart.FileHeader, tag, kind string) error {
+	if !isStructPointer(v) {
+		return fmt.Errorf("nexo: bind target must be a non-nil pointer to a struct, got %T", v)
+	}
+	return bindStruct(reflect.ValueOf(v).Elem(), values, files, tag, kind)
+}
+
+func bindStruct(rv reflect.Value, values map[string][]string, files map[string][]*multipart.FileHeader, tag, kind string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, ok := field.Tag.Lookup(tag)
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := bindStruct(rv.Field(i), values, files, tag, kind); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		switch field.Type {
+		case fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				rv.Field(i).Set(reflect.ValueOf(fhs[0]))
+			}
+			continue
+		case fileHeadersType:
+			if fhs := files[name]; len(fhs) > 0 {
+				rv.Field(i).Set(reflect.ValueOf(fhs))
+			}
+			continue
+		}
+
+		raw := nonEmpty(values[name])
+		if len(raw) == 0 {
+			continue
+		}
+		if err := setField(rv.Field(i), raw, field.Tag.Get("layout")); err != nil {
+			return NewHTTPErrorWithCause(http.StatusBadRequest,
+				fmt.Sprintf("invalid %s %q: %v", kind, name, err), err)
+		}
+	}
+	return nil
+}
+
+// nonEmpty returns the non-empty values.
+func nonEmpty(values []string) []string {
+	out := values[:0:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// setField sets a field from one or more raw values. Slices take every
+// value; other fields take the first.
+func setField(field reflect.Value, raw []string, layout string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 && !isTextType(field.Type()) {
+		slice := reflect.MakeSlice(field.Type(), len(raw), len(raw))
+		for i, s := range raw {
+			if err := setValue(slice.Index(i), s, layout); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, raw[0], layout)
+}
+
+// setValue converts s and stores it in v.
+func setValue(v reflect.Value, s, layout string) error {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setValue(ptr.Elem(), s, layout); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	switch {
+	case v.Type() == timeType:
+		t, err := parseTime(s, layout)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case isTextType(v.Type()):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return numError(s, "an integer", err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return numError(s, "a non-negative integer", err)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return numError(s, "a number", err)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// isTextType reports whether a pointer to t implements
+// encoding.TextUnmarshaler.
+func isTextType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// numError describes a failed number conversion.
+func numError(s, want string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%s is out of range", s)
+	}
+	return fmt.Errorf("%q is not %s", s, want)
+}
+
+// parseTime parses s with layout, or as RFC 3339 or a date without one.
+func parseTime(s, layout string) (time.Time, error) {
+	if layout != "" {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q does not match the layout %s", s, layout)
+		}
+		return t, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time or a date", s)
+}
@@ -0,0 +1,353 @@
+package nexo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type pageParams struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type listParams struct {
+	pageParams
+	Search   string        `query:"q"`
+	Active   *bool         `query:"active"`
+	Tags     []string      `query:"tag"`
+	IDs      []int64       `query:"id"`
+	Min      float64       `query:"min"`
+	Count    uint8         `query:"count"`
+	Since    time.Time     `query:"since"`
+	Until    time.Time     `query:"until" layout:"02/01/2006"`
+	Timeout  time.Duration `query:"timeout"`
+	IP       net.IP        `query:"ip"`
+	Ignored  string        `query:"-"`
+	Untagged string
+}
+
+func queryContext(rawQuery string) *Context {
+	return NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil))
+}
+
+func TestBindQuery(t *testing.T) {
+	c := queryContext("page=3&q=go&active=false&tag=a&tag=b&id=1&id=2&min=1.5&count=7" +
+		"&since=2024-05-01&until=31/12/2024&timeout=1m30s&ip=10.0.0.1&Ignored=x&Untagged=y&limit=")
+
+	params := listParams{pageParams: pageParams{Limit: 20}}
+	if err := c.BindQuery(&params); err != nil {
+		t.Fatalf("BindQuery() error = %v", err)
+	}
+
+	active := false
+	want := listParams{
+		pageParams: pageParams{Page: 3, Limit: 20},
+		Search:     "go",
+		Active:     &active,
+		Tags:       []string{"a", "b"},
+		IDs:        []int64{1, 2},
+		Min:        1.5,
+		Count:      7,
+		Since:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Until:      time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Timeout:    90 * time.Second,
+		IP:         net.ParseIP("10.0.0.1"),
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("BindQuery() = %+v\nwant %+v", params, want)
+	}
+}
+
+func TestBindQuery_MissingLeavesDefaults(t *testing.T) {
+	params := listParams{Search: "default"}
+	if err := queryContext("").BindQuery(&params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Search != "default" || params.Active != nil || params.Tags != nil {
+		t.Errorf("BindQuery() changed fields without parameters: %+v", params)
+	}
+}
+
+func TestBindQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"page=abc", `invalid query parameter "page": "abc" is not an integer`},
+		{"active=maybe", `invalid query parameter "active": "maybe" is not a boolean`},
+		{"id=1&id=x", `invalid query parameter "id": "x" is not an integer`},
+		{"count=300", `invalid query parameter "count": 300 is out of range`},
+		{"count=-1", `invalid query parameter "count": "-1" is not a non-negative integer`},
+		{"since=yesterday", `invalid query parameter "since"`},
+		{"until=2024-12-31", `does not match the layout 02/01/2006`},
+		{"timeout=5", `"5" is not a duration`},
+		{"ip=nope", `invalid query parameter "ip"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var params listParams
+			err := queryContext(tt.query).BindQuery(&params)
+			httpErr, ok := IsHTTPError(err)
+			if !ok {
+				t.Fatalf("BindQuery() error = %v, want an HTTPError", err)
+			}
+			if httpErr.Code != http.StatusBadRequest || !strings.Contains(httpErr.Message, tt.want) {
+				t.Errorf("BindQuery() error = %d %q, want 400 containing %q", httpErr.Code, httpErr.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindQuery_InvalidTarget(t *testing.T) {
+	c := queryContext("page=1")
+	var n int
+	var params *pageParams
+	for _, target := range []any{nil, pageParams{}, &n, params} {
+		err := c.BindQuery(target)
+		if err == nil {
+			t.Errorf("BindQuery(%T) expected an error", target)
+		}
+		if _, ok := IsHTTPError(err); ok {
+			t.Errorf("BindQuery(%T) should not return a 400 for a programming error", target)
+		}
+	}
+}
+
+type signupForm struct {
+	Email     string                  `form:"email"`
+	Age       int                     `form:"age"`
+	Interests []string                `form:"interest"`
+	Avatar    *multipart.FileHeader   `form:"avatar"`
+	Documents []*multipart.FileHeader `form:"doc"`
+	Notify    bool                    `query:"notify"`
+}
+
+func multipartRequest(t *testing.T, fields map[string][]string, files map[string][]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, v := range values {
+			_ = mw.WriteField(name, v)
+		}
+	}
+	for name, filenames := range files {
+		for _, filename := range filenames {
+			fw, err := mw.CreateFormFile(name, filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = fw.Write([]byte("content of " + filename))
+		}
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/signup?notify=true", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestBindForm_URLEncoded(t *testing.T) {
+	form := url.Values{"email": {"ada@example.com"}, "age": {"36"}, "interest": {"math", "engines"}}
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var got signupForm
+	if err := NewContext(httptest.NewRecorder(), req).BindForm(&got); err != nil {
+		t.Fatalf("BindForm() error = %v", err)
+	}
+	want := signupForm{Email: "ada@example.com", Age: 36, Interests: []string{"math", "engines"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BindForm() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBindForm_Multipart(t *testing.T) {
+	req := multipartRequest(t,
+		map[string][]string{"email": {"ada@example.com"}, "age": {"36"}},
+		map[string][]string{"avatar": {"ada.png"}, "doc": {"a.pdf", "b.pdf"}})
+
+	var got signupForm
+	if err := NewContext(httptest.NewRecorder(), req).BindForm(&got); err != nil {
+		t.Fatalf("BindForm() error = %v", err)
+	}
+	if got.Email != "ada@example.com" || got.Age != 36 {
+		t.Errorf("BindForm() fields = %+v", got)
+	}
+	if got.Avatar == nil || got.Avatar.Filename != "ada.png" {
+		t.Errorf("Avatar = %+v", got.Avatar)
+	}
+	if len(got.Documents) != 2 || got.Documents[1].Filename != "b.pdf" {
+		t.Errorf("Documents = %+v", got.Documents)
+	}
+	if got.Notify {
+		t.Error("BindForm should not read the query string")
+	}
+
+	f, err := got.Avatar.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	content, _ := io.ReadAll(f)
+	if string(content) != "content of ada.png" {
+		t.Errorf("avatar content = %q", content)
+	}
+}
+
+func TestBindForm_InvalidValue(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("age=old"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var got signupForm
+	err := NewContext(httptest.NewRecorder(), req).BindForm(&got)
+	if httpErr, ok := IsHTTPError(err); !ok || httpErr.Code != http.StatusBadRequest ||
+		httpErr.Message != `invalid form field "age": "old" is not an integer` {
+		t.Errorf("BindForm() error = %v", err)
+	}
+}
+
+func TestBind_ContentTypes(t *testing.T) {
+	type input struct {
+		Name   string `json:"name" form:"name"`
+		Notify bool   `query:"notify"`
+		Page   int    `query:"page"`
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		want        input
+	}{
+		{"json", http.MethodPost, "application/json", `{"name":"ada"}`, input{Name: "ada", Notify: true}},
+		{"json charset", http.MethodPut, "application/json; charset=utf-8", `{"name":"ada"}`, input{Name: "ada", Notify: true}},
+		{"no content type defaults to json", http.MethodPost, "", `{"name":"ada"}`, input{Name: "ada", Notify: true}},
+		{"form", http.MethodPost, "application/x-www-form-urlencoded", "name=ada", input{Name: "ada", Notify: true}},
+		{"query only", http.MethodGet, "", "", input{Notify: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/?notify=true", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			var got input
+			if err := NewContext(httptest.NewRecorder(), req).Bind(&got); err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Bind() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("multipart", func(t *testing.T) {
+		req := multipartRequest(t, map[string][]string{"email": {"ada@example.com"}}, nil)
+		var got signupForm
+		if err := NewContext(httptest.NewRecorder(), req).Bind(&got); err != nil {
+			t.Fatalf("Bind() error = %v", err)
+		}
+		if got.Email != "ada@example.com" || !got.Notify {
+			t.Errorf("Bind() = %+v", got)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/?page=x", strings.NewReader(`{"name":"ada"}`))
+		var got input
+		if _, ok := IsHTTPError(NewContext(httptest.NewRecorder(), req).Bind(&got)); !ok {
+			t.Error("expected a 400 for an invalid query parameter")
+		}
+	})
+
+	t.Run("map target", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/?notify=true", strings.NewReader(`{"name":"ada"}`))
+		var got map[string]string
+		if err := NewContext(httptest.NewRecorder(), req).Bind(&got); err != nil || got["name"] != "ada" {
+			t.Errorf("Bind() = %v, %v", got, err)
+		}
+	})
+}
+
+type xmlOrder struct {
+	XMLName  xml.Name `xml:"order"`
+	ID       int      `xml:"id,attr"`
+	Customer string   `xml:"customer"`
+	Items    []string `xml:"items>item"`
+	Rush     bool     `query:"rush"`
+}
+
+func TestBind_XML(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"application/xml", "application/xml", `<order id="7"><customer>Ada</customer><items><item>a</item><item>b</item></items></order>`, "Ada"},
+		{"text/xml", "text/xml; charset=utf-8", `<order id="7"><customer>Ada</customer></order>`, "Ada"},
+		{"latin1 declaration", "application/xml", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><order id=\"7\"><customer>Jos\xe9</customer></order>", "José"},
+		{"latin1 header", "application/xml; charset=iso-8859-1", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><order id=\"7\"><customer>Jos\xe9</customer></order>", "José"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/?rush=true", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			var got xmlOrder
+			if err := NewContext(httptest.NewRecorder(), req).Bind(&got); err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if got.ID != 7 || got.Customer != tt.want || !got.Rush {
+				t.Errorf("Bind() = %+v", got)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`<order id="7"><items><item>a</item><item>b</item></items></order>`))
+	req.Header.Set("Content-Type", "application/xml")
+	var got xmlOrder
+	if err := NewContext(httptest.NewRecorder(), req).BindXML(&got); err != nil || !reflect.DeepEqual(got.Items, []string{"a", "b"}) {
+		t.Errorf("BindXML() = %+v, %v", got, err)
+	}
+}
+
+func TestBind_XMLErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantCode    int
+		wantMessage string
+	}{
+		{"malformed", "application/xml", "<order><customer>Ada</order>", http.StatusBadRequest, "invalid XML"},
+		{"empty", "application/xml", "", http.StatusBadRequest, "empty request body"},
+		{"charset header", "application/xml; charset=shift_jis", "<order/>", http.StatusUnsupportedMediaType, "unsupported charset"},
+		{"charset declaration", "application/xml", `<?xml version="1.0" encoding="Shift_JIS"?><order/>`, http.StatusUnsupportedMediaType, "unsupported charset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			var got xmlOrder
+			err := NewContext(httptest.NewRecorder(), req).Bind(&got)
+			httpErr, ok := IsHTTPError(err)
+			if !ok || httpErr.Code != tt.wantCode || httpErr.Message != tt.wantMessage {
+				t.Errorf("Bind() error = %v, want %d %s", err, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
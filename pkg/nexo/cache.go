@@ -0,0 +1,415 @@
+package nexo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheStoreKey is the request context key for the app's cache store.
+type cacheStoreKey struct{}
+
+// CacheStore stores cached responses for the Cache middleware. Values are
+// opaque bytes, so stores such as Redis only need to keep them until the
+// TTL passes. Set one for the whole app with WithCacheStore.
+type CacheStore interface {
+	// Get returns the value stored under key. ok is false if there is
+	// none or it expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value stored under key, if any.
+	Delete(ctx context.Context, key string) error
+
+	// DeletePrefix removes the values stored under keys starting with
+	// prefix. An empty prefix removes everything.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// MemoryCacheStore is an in-memory CacheStore, the default. Entries are
+// kept per process, so every instance of an app has its own cache.
+type MemoryCacheStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryCacheEntry
+	lastSweep time.Time
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCacheStore creates an empty in-memory cache store.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{entries: make(map[string]memoryCacheEntry)}
+}
+
+// Get implements CacheStore.
+func (s *MemoryCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implements CacheStore. Expired entries are swept at most once a
+// minute.
+func (s *MemoryCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.entries {
+			if now.After(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[key] = memoryCacheEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+// Delete implements CacheStore.
+func (s *MemoryCacheStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// DeletePrefix implements CacheStore.
+func (s *MemoryCacheStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// withCacheStore makes the app's cache store available to the Cache middleware.
+func withCacheStore(r *http.Request, s CacheStore) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), cacheStoreKey{}, s))
+}
+
+// CacheStore returns the app's cache store.
+func (a *App) CacheStore() CacheStore {
+	return a.cacheStore
+}
+
+// InvalidateCache removes the cached responses stored under keys, and
+// their variants by Vary header. With the default key, a response's key is
+// its path and query string.
+//
+// Example:
+//
+//	app.InvalidateCache(ctx, "/api/products/42")
+func (a *App) InvalidateCache(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if err := a.cacheStore.Delete(ctx, key); err != nil {
+			return err
+		}
+		if err := a.cacheStore.DeletePrefix(ctx, key+varySeparator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidateCachePrefix removes the cached responses whose keys start with
+// prefix, e.g. a path and all its query strings and subpaths. An empty
+// prefix clears the cache.
+//
+// Example:
+//
+//	app.InvalidateCachePrefix(ctx, "/api/products")
+func (a *App) InvalidateCachePrefix(ctx context.Context, prefix string) error {
+	return a.cacheStore.DeletePrefix(ctx, prefix)
+}
+
+// ---------- Cache Middleware ----------
+
+// CacheConfig holds configuration for the cache middleware.
+type CacheConfig struct {
+	// TTL is how long responses are cached. Default is 1 minute.
+	TTL time.Duration
+
+	// KeyFunc returns the cache key for a request. Default is the path
+	// and query string, e.g. "/products?page=2".
+	KeyFunc func(c *Context) string
+
+	// Store holds the cached responses. Default is the app's cache store
+	// (see WithCacheStore), which the App's invalidation helpers use.
+	Store CacheStore
+
+	// Skip returns true for requests that shouldn't be cached.
+	Skip func(c *Context) bool
+
+	// IncludeCredentialed caches requests sending an Authorization or
+	// Cookie header too. They are skipped by default because their
+	// responses usually belong to one user, e.g. pages with a CSRFField.
+	// Only set it with a KeyFunc that tells users apart, or for responses
+	// that don't depend on who asks.
+	IncludeCredentialed bool
+}
+
+// varySeparator separates a key from the request headers a response
+// varies on in the keys of its variants.
+const varySeparator = "#vary:"
+
+// cachedResponse is a response as stored in a CacheStore. Responses with a
+// Vary header are stored under a key per variant, and their key holds only
+// the Vary fields to look them up.
+type cachedResponse struct {
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	Created time.Time   `json:"created"`
+	Vary    []string    `json:"vary,omitempty"`
+}
+
+// Cache returns a middleware that caches successful GET responses for ttl
+// in the app's cache store. Responses are sent with an X-Cache header of
+// HIT or MISS, and cached per value of the request headers their Vary
+// header lists.
+//
+// Requests sending an Authorization or Cookie header, and responses
+// setting cookies, marked Cache-Control: private or no-store,
+// already compressed, or flushed as a stream are not cached. Add Compress
+// before Cache so cached responses are compressed per request.
+func Cache(ttl time.Duration) MiddlewareFunc {
+	return CacheWithConfig(CacheConfig{TTL: ttl})
+}
+
+// CacheWithConfig returns a cache middleware with custom configuration.
+//
+// Example:
+//
+//	app.Use(nexo.CacheWithConfig(nexo.CacheConfig{
+//	    TTL: 5 * time.Minute,
+//	    KeyFunc: func(c *nexo.Context) string {
+//	        return c.Header("Accept-Language") + ":" + c.Request.URL.RequestURI()
+//	    },
+//	}))
+func CacheWithConfig(config CacheConfig) MiddlewareFunc {
+	if config.TTL <= 0 {
+		config.TTL = time.Minute
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(c *Context) string {
+			return c.Request.URL.RequestURI()
+		}
+	}
+	// Handlers running outside an app get their own store
+	fallback := NewMemoryCacheStore()
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if c.Method() != http.MethodGet || (config.Skip != nil && config.Skip(c)) {
+				return next(c)
+			}
+			if !config.IncludeCredentialed && (c.Header("Authorization") != "" || c.Header("Cookie") != "") {
+				return next(c)
+			}
+
+			store := config.Store
+			if store == nil {
+				store, _ = c.Request.Context().Value(cacheStoreKey{}).(CacheStore)
+			}
+			if store == nil {
+				store = fallback
+			}
+
+			ctx := c.Context()
+			key := config.KeyFunc(c)
+			if cached := getCached(ctx, store, key); cached != nil {
+				if cached.Vary != nil {
+					cached = getCached(ctx, store, varyKey(key, cached.Vary, c.Request))
+				}
+				if cached != nil && cached.Vary == nil {
+					return writeCached(c, cached)
+				}
+			}
+
+			c.SetHeader("X-Cache", "MISS")
+			// Headers set by outer middleware, such as request IDs, aren't cached
+			before := c.Response.Header().Clone()
+			res := c.Response
+			defer func() { c.Response = res }()
+			cw := &cacheWriter{ResponseWriter: res, status: http.StatusOK}
+			c.Response = cw
+
+			err := next(c)
+			if err == nil && cw.cacheable() {
+				header := make(http.Header)
+				for name, values := range cw.Header() {
+					if !slices.Equal(before[name], values) {
+						header[name] = values
+					}
+				}
+				header.Del("Content-Length")
+				data, _ := json.Marshal(cachedResponse{
+					Status:  cw.status,
+					Header:  header,
+					Body:    cw.buf.Bytes(),
+					Created: time.Now(),
+				})
+				// Vary fields set by outer middleware, such as Compress, are
+				// handled per request
+				vary := varyFields(header["Vary"])
+				vary = slices.DeleteFunc(vary, func(field string) bool {
+					return slices.Contains(varyFields(before["Vary"]), field)
+				})
+				switch {
+				case slices.Contains(vary, "*"):
+				case len(vary) == 0:
+					_ = store.Set(ctx, key, data, config.TTL)
+				default:
+					marker, _ := json.Marshal(cachedResponse{Vary: vary})
+					if store.Set(ctx, key, marker, config.TTL) == nil {
+						_ = store.Set(ctx, varyKey(key, vary, c.Request), data, config.TTL)
+					}
+				}
+			}
+			return err
+		}
+	}
+}
+
+// getCached returns the response stored under key, or nil.
+func getCached(ctx context.Context, store CacheStore, key string) *cachedResponse {
+	data, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil
+	}
+	var cached cachedResponse
+	if json.Unmarshal(data, &cached) != nil {
+		return nil
+	}
+	return &cached
+}
+
+// varyFields returns the canonical header names listed in Vary values,
+// sorted and without duplicates.
+func varyFields(values []string) []string {
+	var fields []string
+	for _, v := range values {
+		for _, field := range strings.Split(v, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, http.CanonicalHeaderKey(field))
+			}
+		}
+	}
+	slices.Sort(fields)
+	return slices.Compact(fields)
+}
+
+// varyKey returns the key of the variant of the response under key for
+// the values r sends for the vary headers.
+func varyKey(key string, vary []string, r *http.Request) string {
+	q := make(url.Values, len(vary))
+	for _, name := range vary {
+		q[name] = r.Header.Values(name)
+	}
+	return key + varySeparator + q.Encode()
+}
+
+// writeCached sends a cached response.
+func writeCached(c *Context, cached *cachedResponse) error {
+	h := c.Response.Header()
+	for name, values := range cached.Header {
+		h[name] = values
+	}
+	h.Set("X-Cache", "HIT")
+	h.Set("Age", strconv.Itoa(int(time.Since(cached.Created).Seconds())))
+	h.Set("Content-Length", strconv.Itoa(len(cached.Body)))
+	return c.Blob(cached.Status, h.Get("Content-Type"), cached.Body)
+}
+
+// cacheWriter sends the response and keeps a copy of it to cache.
+type cacheWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	streamed    bool
+	buf         bytes.Buffer
+}
+
+func (cw *cacheWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		cw.status = code
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cacheWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	n, err := cw.ResponseWriter.Write(b)
+	if !cw.streamed {
+		cw.buf.Write(b[:n])
+	}
+	return n, err
+}
+
+// cacheable reports whether the response can be cached.
+func (cw *cacheWriter) cacheable() bool {
+	if !cw.wroteHeader || cw.streamed || cw.status != http.StatusOK {
+		return false
+	}
+	h := cw.Header()
+	cc := strings.ToLower(h.Get("Cache-Control"))
+	// Encoded responses depend on the request's Accept-Encoding
+	return h.Get("Set-Cookie") == "" && h.Get("Content-Encoding") == "" &&
+		!strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
+
+// Flush implements http.Flusher. Flushed responses are not cached.
+func (cw *cacheWriter) Flush() {
+	cw.streamed = true
+	cw.buf.Reset()
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface for WebSocket support.
+func (cw *cacheWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	cw.streamed = true
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (cw *cacheWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
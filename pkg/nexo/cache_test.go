@@ -0,0 +1,232 @@
+package nexo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	calls := 0
+	app := New()
+	app.DisableLogger()
+	app.Use(RequestID())
+	app.Use(Cache(time.Minute))
+	app.Get("/products", func(c *Context) error {
+		calls++
+		c.SetHeader("X-Calls", strconv.Itoa(calls))
+		return c.JSON(http.StatusOK, map[string]int{"calls": calls})
+	})
+	app.Mount()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	first := get("/products")
+	if first.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected a MISS, got %q", first.Header().Get("X-Cache"))
+	}
+
+	second := get("/products")
+	if second.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expected a HIT, got %q", second.Header().Get("X-Cache"))
+	}
+	if second.Body.String() != first.Body.String() || calls != 1 {
+		t.Errorf("expected the cached body, got %q after %d calls", second.Body.String(), calls)
+	}
+	if second.Header().Get("X-Calls") != "1" || second.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("expected the handler's headers to be cached, got %v", second.Header())
+	}
+	if id := second.Header().Get("X-Request-ID"); id == "" || id == first.Header().Get("X-Request-ID") {
+		t.Errorf("expected a new request ID on a hit, got %q", id)
+	}
+
+	// Query strings are part of the default key
+	if w := get("/products?page=2"); w.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected a MISS for another query, got %q", w.Header().Get("X-Cache"))
+	}
+
+	if err := app.InvalidateCache(context.Background(), "/products"); err != nil {
+		t.Fatal(err)
+	}
+	if w := get("/products"); w.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected a MISS after invalidation, got %q", w.Header().Get("X-Cache"))
+	}
+	if w := get("/products?page=2"); w.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("expected other keys to stay cached, got %q", w.Header().Get("X-Cache"))
+	}
+
+	if err := app.InvalidateCachePrefix(context.Background(), "/products"); err != nil {
+		t.Fatal(err)
+	}
+	if w := get("/products?page=2"); w.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected a MISS after prefix invalidation, got %q", w.Header().Get("X-Cache"))
+	}
+}
+
+func TestCache_NotCached(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		handler HandlerFunc
+	}{
+		{"post", http.MethodPost, func(c *Context) error { return c.String(http.StatusOK, "ok") }},
+		{"error status", http.MethodGet, func(c *Context) error { return c.String(http.StatusNotFound, "missing") }},
+		{"returned error", http.MethodGet, func(c *Context) error { return BadRequest("bad") }},
+		{"private", http.MethodGet, func(c *Context) error {
+			c.SetHeader("Cache-Control", "private")
+			return c.String(http.StatusOK, "mine")
+		}},
+		{"cookie", http.MethodGet, func(c *Context) error {
+			c.SetCookie(&http.Cookie{Name: "session", Value: "abc"})
+			return c.String(http.StatusOK, "hello")
+		}},
+		{"flushed", http.MethodGet, func(c *Context) error {
+			_, _ = c.Response.Write([]byte("chunk"))
+			c.Response.(http.Flusher).Flush()
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryCacheStore()
+			h := CacheWithConfig(CacheConfig{Store: store})(tt.handler)
+			w := httptest.NewRecorder()
+			_ = h(NewContext(w, httptest.NewRequest(tt.method, "/", nil)))
+			if _, ok, _ := store.Get(context.Background(), "/"); ok {
+				t.Error("expected the response not to be cached")
+			}
+		})
+	}
+}
+
+func TestCache_KeyFuncAndSkip(t *testing.T) {
+	store := NewMemoryCacheStore()
+	h := CacheWithConfig(CacheConfig{
+		Store:   store,
+		KeyFunc: func(c *Context) string { return "lang:" + c.Header("Accept-Language") },
+		Skip:    func(c *Context) bool { return c.Query("fresh") != "" },
+	})(func(c *Context) error {
+		return c.String(http.StatusOK, "hola")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "es")
+	_ = h(NewContext(httptest.NewRecorder(), req))
+	if _, ok, _ := store.Get(context.Background(), "lang:es"); !ok {
+		t.Error("expected the response under the custom key")
+	}
+
+	w := httptest.NewRecorder()
+	_ = h(NewContext(w, httptest.NewRequest(http.MethodGet, "/?fresh=1", nil)))
+	if w.Header().Get("X-Cache") != "" {
+		t.Errorf("expected skipped requests to bypass the cache, got X-Cache %q", w.Header().Get("X-Cache"))
+	}
+}
+
+func TestCache_Credentialed(t *testing.T) {
+	handler := func(c *Context) error {
+		return c.String(http.StatusOK, "hello")
+	}
+	get := func(h HandlerFunc, header, value string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(header, value)
+		_ = h(NewContext(httptest.NewRecorder(), req))
+	}
+
+	for _, header := range []string{"Authorization", "Cookie"} {
+		store := NewMemoryCacheStore()
+		get(CacheWithConfig(CacheConfig{Store: store})(handler), header, "x")
+		if _, ok, _ := store.Get(context.Background(), "/"); ok {
+			t.Errorf("expected requests with %s not to be cached", header)
+		}
+	}
+
+	store := NewMemoryCacheStore()
+	h := CacheWithConfig(CacheConfig{Store: store, IncludeCredentialed: true})(handler)
+	get(h, "Cookie", "theme=dark")
+	if _, ok, _ := store.Get(context.Background(), "/"); !ok {
+		t.Error("expected IncludeCredentialed to cache requests with cookies")
+	}
+}
+
+func TestCache_Vary(t *testing.T) {
+	calls := 0
+	app := New()
+	app.DisableLogger()
+	app.Use(Compress())
+	app.Use(Cache(time.Minute))
+	app.Get("/products", func(c *Context) error {
+		calls++
+		c.VaryHTMX()
+		if c.IsHTMX() {
+			return c.String(http.StatusOK, "rows")
+		}
+		return c.String(http.StatusOK, "page")
+	})
+	app.Mount()
+
+	get := func(htmx bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	get(false)
+	if w := get(true); w.Body.String() != "rows" || w.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected a MISS with the htmx fragment, got %q (%s)", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	if w := get(false); w.Body.String() != "page" || w.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("expected a HIT with the full page, got %q (%s)", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	if w := get(true); w.Body.String() != "rows" || w.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("expected a HIT with the htmx fragment, got %q (%s)", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	if calls != 2 {
+		t.Errorf("expected a call per variant, got %d", calls)
+	}
+
+	if err := app.InvalidateCache(context.Background(), "/products"); err != nil {
+		t.Fatal(err)
+	}
+	if w := get(true); w.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected InvalidateCache to drop the variants, got %q", w.Header().Get("X-Cache"))
+	}
+}
+
+func TestMemoryCacheStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCacheStore()
+	_ = store.Set(ctx, "a", []byte("1"), -time.Second)
+	_ = store.Set(ctx, "b", []byte("2"), time.Minute)
+
+	if _, ok, _ := store.Get(ctx, "a"); ok {
+		t.Error("expected the expired entry to be gone")
+	}
+	if v, ok, _ := store.Get(ctx, "b"); !ok || string(v) != "2" {
+		t.Errorf("Get(b) = %q, %v", v, ok)
+	}
+	_ = store.DeletePrefix(ctx, "")
+	if _, ok, _ := store.Get(ctx, "b"); ok {
+		t.Error("expected an empty prefix to clear the store")
+	}
+}
+
+func TestWithCacheStore(t *testing.T) {
+	store := NewMemoryCacheStore()
+	app := New(WithCacheStore(store))
+	if app.CacheStore() != store {
+		t.Error("expected the configured store")
+	}
+}
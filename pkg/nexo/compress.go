@@ -0,0 +1,404 @@
+package nexo
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressEncoder is a content coding the compress middleware can use.
+type CompressEncoder struct {
+	// Encoding is the Content-Encoding token, e.g. "gzip" or "br".
+	Encoding string
+
+	// NewWriter returns a writer that compresses to w. level is
+	// CompressConfig.Level, where 0 means the encoder's default. Writers
+	// with a Reset(io.Writer) method are reused; writers with a
+	// Flush() error method support streaming responses.
+	NewWriter func(w io.Writer, level int) io.WriteCloser
+}
+
+// BrotliEncoder compresses responses with brotli. Levels go from 0 to 11.
+var BrotliEncoder = CompressEncoder{
+	Encoding: "br",
+	NewWriter: func(w io.Writer, level int) io.WriteCloser {
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		return brotli.NewWriterLevel(w, level)
+	},
+}
+
+// ZstdEncoder compresses responses with zstd. Levels are zstd's, from 1 to
+// 22, mapped to the closest level the encoder supports.
+var ZstdEncoder = CompressEncoder{
+	Encoding: "zstd",
+	NewWriter: func(w io.Writer, level int) io.WriteCloser {
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		zw, err := zstd.NewWriter(w, opts...)
+		if err != nil {
+			zw, _ = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		}
+		return zw
+	},
+}
+
+// GzipEncoder compresses responses with gzip.
+var GzipEncoder = CompressEncoder{
+	Encoding: "gzip",
+	NewWriter: func(w io.Writer, level int) io.WriteCloser {
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		gw, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return gzip.NewWriter(w)
+		}
+		return gw
+	},
+}
+
+// DeflateEncoder compresses responses with deflate (the zlib format).
+var DeflateEncoder = CompressEncoder{
+	Encoding: "deflate",
+	NewWriter: func(w io.Writer, level int) io.WriteCloser {
+		if level == 0 {
+			level = zlib.DefaultCompression
+		}
+		zw, err := zlib.NewWriterLevel(w, level)
+		if err != nil {
+			return zlib.NewWriter(w)
+		}
+		return zw
+	},
+}
+
+// DefaultCompressContentTypes are the media types compressed by default.
+var DefaultCompressContentTypes = []string{
+	"text/html",
+	"text/css",
+	"text/plain",
+	"text/javascript",
+	"text/xml",
+	"text/csv",
+	"application/javascript",
+	"application/json",
+	"application/problem+json",
+	"application/ld+json",
+	"application/xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// CompressConfig holds configuration for the compress middleware.
+type CompressConfig struct {
+	// Level is the compression level passed to the encoders. Default is
+	// each encoder's default.
+	Level int
+
+	// MinSize is the smallest response body, in bytes, that is compressed.
+	// Default is 1024.
+	MinSize int
+
+	// ContentTypes are the media types to compress. Entries such as
+	// "text/*" match a whole type. Default is DefaultCompressContentTypes.
+	ContentTypes []string
+
+	// Encoders are the content codings offered, in order of preference
+	// when the client accepts several equally. Default is brotli, zstd,
+	// gzip, then deflate.
+	Encoders []CompressEncoder
+
+	// Skip returns true for requests that shouldn't be compressed.
+	Skip func(c *Context) bool
+}
+
+// Compress returns a middleware that compresses responses with brotli,
+// zstd, gzip or deflate, whichever the client prefers, for compressible
+// content types of at least 1KB.
+func Compress() MiddlewareFunc {
+	return CompressWithConfig(CompressConfig{})
+}
+
+// CompressWithConfig returns a compress middleware with custom configuration.
+//
+// To offer fewer codings, or prefer another, list the encoders:
+//
+//	app.Use(nexo.CompressWithConfig(nexo.CompressConfig{
+//	    Encoders: []nexo.CompressEncoder{nexo.ZstdEncoder, nexo.GzipEncoder},
+//	}))
+//
+// Handler errors are written inside the middleware, so error responses are
+// compressed too. Logged response sizes are the compressed sizes.
+func CompressWithConfig(config CompressConfig) MiddlewareFunc {
+	if config.MinSize == 0 {
+		config.MinSize = 1024
+	}
+	if config.ContentTypes == nil {
+		config.ContentTypes = DefaultCompressContentTypes
+	}
+	if config.Encoders == nil {
+		config.Encoders = []CompressEncoder{BrotliEncoder, ZstdEncoder, GzipEncoder, DeflateEncoder}
+	}
+	pools := make([]sync.Pool, len(config.Encoders))
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			addVary(c.Response.Header(), "Accept-Encoding")
+			i := negotiateEncoding(c.Header("Accept-Encoding"), config.Encoders)
+			if i < 0 {
+				return next(c)
+			}
+
+			res := c.Response
+			defer func() { c.Response = res }()
+			cw := &compressWriter{
+				ResponseWriter: res,
+				config:         &config,
+				encoder:        config.Encoders[i],
+				pool:           &pools[i],
+				status:         http.StatusOK,
+			}
+			c.Response = cw
+
+			// The error is written here, so it's handled rather than returned
+			if err := next(c); err != nil {
+				handleError(c, err)
+			}
+			_ = cw.close()
+			return nil
+		}
+	}
+}
+
+// negotiateEncoding returns the index of the encoder to use for an
+// Accept-Encoding header, or -1 to send the response uncompressed.
+func negotiateEncoding(header string, encoders []CompressEncoder) int {
+	if header == "" {
+		return -1
+	}
+
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(coding))] = q
+	}
+
+	best, bestQ := -1, 0.0
+	for i, enc := range encoders {
+		q, ok := accepted[enc.Encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	return best
+}
+
+// addVary adds value to the Vary header unless it's already listed.
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values("Vary") {
+		for _, field := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), value) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", value)
+}
+
+// compressWriter buffers the start of a response until it knows whether to
+// compress it: once MinSize bytes are written, the handler flushes, or the
+// handler returns.
+type compressWriter struct {
+	http.ResponseWriter
+	config  *CompressConfig
+	encoder CompressEncoder
+	pool    *sync.Pool
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	w           io.WriteCloser
+}
+
+// WriteHeader records the status. Responses without a body, or with a
+// partial one, are sent uncompressed.
+func (cw *compressWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusPartialContent {
+		cw.decide(false)
+	}
+}
+
+// Write buffers the body until the compression decision is made.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.w != nil {
+			return cw.w.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.config.MinSize {
+		cw.decide(true)
+		if err := cw.writeBuffer(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide sends the headers, compressing the body if compress is set and the
+// response is compressible.
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	h := cw.Header()
+
+	// net/http would sniff the compressed bytes instead
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+
+	if compress && h.Get("Content-Encoding") == "" && cw.compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", cw.encoder.Encoding)
+		h.Del("Content-Length")
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		cw.w = cw.newWriter()
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+func (cw *compressWriter) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range cw.config.ContentTypes {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// newWriter returns a pooled or new encoder writer.
+func (cw *compressWriter) newWriter() io.WriteCloser {
+	if w, ok := cw.pool.Get().(io.WriteCloser); ok {
+		w.(interface{ Reset(io.Writer) }).Reset(cw.ResponseWriter)
+		return w
+	}
+	return cw.encoder.NewWriter(cw.ResponseWriter, cw.config.Level)
+}
+
+func (cw *compressWriter) writeBuffer() error {
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.w != nil {
+		_, err = cw.w.Write(cw.buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+// Flush implements http.Flusher. Flushed responses are compressed whatever
+// their size, so streams of a compressible type are compressed.
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.decide(true)
+		_ = cw.writeBuffer()
+	}
+	if f, ok := cw.w.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface for WebSocket support.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// close sends a buffered response and finishes the compressed stream.
+func (cw *compressWriter) close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			return nil
+		}
+		// Bodies reaching MinSize were decided in Write
+		cw.decide(false)
+		if err := cw.writeBuffer(); err != nil {
+			return err
+		}
+	}
+	if cw.w == nil {
+		return nil
+	}
+	err := cw.w.Close()
+	if _, ok := cw.w.(interface{ Reset(io.Writer) }); ok {
+		cw.pool.Put(cw.w)
+	}
+	cw.w = nil
+	return err
+}
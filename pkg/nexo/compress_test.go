@@ -0,0 +1,267 @@
+package nexo
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func decompress(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var r io.Reader
+	var err error
+	switch enc := w.Header().Get("Content-Encoding"); enc {
+	case "gzip":
+		r, err = gzip.NewReader(w.Body)
+	case "deflate":
+		r, err = zlib.NewReader(w.Body)
+	case "br":
+		r = brotli.NewReader(w.Body)
+	case "zstd":
+		var zr *zstd.Decoder
+		zr, err = zstd.NewReader(w.Body)
+		if err == nil {
+			defer zr.Close()
+		}
+		r = zr
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", enc)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("nexo compresses this response. ", 100)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		handler        HandlerFunc
+		encoding       string
+	}{
+		{"gzip", "gzip, deflate", func(c *Context) error { return c.String(200, large) }, "gzip"},
+		{"preferred deflate", "gzip;q=0.5, deflate", func(c *Context) error { return c.String(200, large) }, "deflate"},
+		{"brotli", "gzip, deflate, br, zstd", func(c *Context) error { return c.String(200, large) }, "br"},
+		{"zstd", "gzip, zstd", func(c *Context) error { return c.String(200, large) }, "zstd"},
+		{"not accepted", "compress", func(c *Context) error { return c.String(200, large) }, ""},
+		{"no header", "", func(c *Context) error { return c.String(200, large) }, ""},
+		{"too small", "gzip", func(c *Context) error { return c.String(200, "ok") }, ""},
+		{"not compressible", "gzip", func(c *Context) error { return c.Blob(200, "image/png", []byte(large)) }, ""},
+		{"already encoded", "gzip", func(c *Context) error {
+			c.SetHeader("Content-Encoding", "br")
+			return c.Blob(200, "text/plain", []byte(large))
+		}, "br"},
+		{"no content", "gzip", func(c *Context) error { return c.NoContent() }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			if err := Compress()(tt.handler)(NewContext(w, req)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.encoding)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if tt.encoding != "" && tt.name != "already encoded" {
+				if body := decompress(t, w); body != large {
+					t.Errorf("decompressed body has %d bytes, want %d", len(body), len(large))
+				}
+				if w.Header().Get("Content-Length") != "" {
+					t.Error("expected no Content-Length on compressed responses")
+				}
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	encoders := []CompressEncoder{GzipEncoder, DeflateEncoder}
+	tests := map[string]int{
+		"gzip":                  0,
+		"deflate":               1,
+		"deflate, gzip":         0,
+		"gzip;q=0.2, deflate":   1,
+		"GZIP":                  0,
+		"*":                     0,
+		"gzip;q=0, *;q=0.1":     1,
+		"gzip;q=0, deflate;q=0": -1,
+		"identity":              -1,
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header, encoders); got != want {
+			t.Errorf("negotiateEncoding(%q) = %d, want %d", header, got, want)
+		}
+	}
+}
+
+func TestCompress_ErrorsAndSizeAccounting(t *testing.T) {
+	registry := NewMetricsRegistry("")
+	app := New()
+	app.DisableLogger()
+	app.Use(MetricsWithConfig(MetricsConfig{Registry: registry}))
+	app.Use(CompressWithConfig(CompressConfig{MinSize: 1}))
+	app.Get("/fail", func(c *Context) error {
+		return BadRequest(strings.Repeat("invalid ", 200))
+	})
+	app.Mount()
+
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	compressed := w.Body.Len()
+	if body := decompress(t, w); !strings.Contains(body, "invalid invalid") {
+		t.Errorf("unexpected error body %q", body)
+	}
+
+	var b strings.Builder
+	_ = registry.Write(&b)
+	want := `http_response_size_bytes_sum{method="GET",route="/fail",status="400"} ` + formatFloat(float64(compressed))
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("expected the compressed size to be recorded (%s), got:\n%s", want, b.String())
+	}
+}
+
+func TestCompress_Flush(t *testing.T) {
+	h := Compress()(func(c *Context) error {
+		c.SetHeader("Content-Type", "text/html")
+		_, _ = c.Response.Write([]byte("<p>first</p>"))
+		c.Response.(http.Flusher).Flush()
+		_, _ = c.Response.Write([]byte("<p>second</p>"))
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	if err := h(NewContext(w, req)); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+	if body := decompress(t, w); body != "<p>first</p><p>second</p>" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestCompress_WeakensETag(t *testing.T) {
+	h := Compress()(func(c *Context) error {
+		c.SetHeader("ETag", `"v1"`)
+		return c.String(200, strings.Repeat("a", 2048))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	if err := h(NewContext(w, req)); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Header().Get("ETag"); got != `W/"v1"` {
+		t.Errorf("ETag = %q, want W/\"v1\"", got)
+	}
+}
+
+func TestCompress_Encoders(t *testing.T) {
+	large := strings.Repeat("nexo compresses this response. ", 100)
+
+	for _, enc := range []CompressEncoder{BrotliEncoder, ZstdEncoder, GzipEncoder, DeflateEncoder} {
+		t.Run(enc.Encoding, func(t *testing.T) {
+			h := CompressWithConfig(CompressConfig{Encoders: []CompressEncoder{enc}, Level: 5})(func(c *Context) error {
+				c.SetHeader("Content-Type", "text/plain")
+				_, _ = c.Response.Write([]byte("first "))
+				c.Response.(http.Flusher).Flush()
+				_, _ = c.Response.Write([]byte(large))
+				return nil
+			})
+
+			// The second request reuses the pooled writer
+			for range 2 {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Accept-Encoding", enc.Encoding)
+				w := httptest.NewRecorder()
+				if err := h(NewContext(w, req)); err != nil {
+					t.Fatal(err)
+				}
+				if body := decompress(t, w); body != "first "+large {
+					t.Errorf("decompressed body has %d bytes, want %d", len(body), len("first "+large))
+				}
+			}
+		})
+	}
+}
+
+func TestCompress_HandlesErrors(t *testing.T) {
+	h := Compress()(func(c *Context) error {
+		return BadRequest("invalid")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	if err := h(NewContext(w, req)); err != nil {
+		t.Errorf("expected the handled error not to be returned, got %v", err)
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestCompress_CustomEncoderAndReuse(t *testing.T) {
+	created := 0
+	custom := CompressEncoder{
+		Encoding: "gzip",
+		NewWriter: func(w io.Writer, level int) io.WriteCloser {
+			created++
+			return GzipEncoder.NewWriter(w, level)
+		},
+	}
+	h := CompressWithConfig(CompressConfig{
+		Encoders:     []CompressEncoder{custom},
+		ContentTypes: []string{"text/*"},
+	})(func(c *Context) error {
+		return c.String(200, strings.Repeat("b", 4096))
+	})
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		if err := h(NewContext(w, req)); err != nil {
+			t.Fatal(err)
+		}
+		if body := decompress(t, w); len(body) != 4096 {
+			t.Errorf("unexpected body length %d", len(body))
+		}
+	}
+	if created != 1 {
+		t.Errorf("expected the writer to be reused, created %d", created)
+	}
+}
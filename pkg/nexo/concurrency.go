@@ -0,0 +1,156 @@
+package nexo
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ---------- MaxConcurrency Middleware ----------
+
+// MaxConcurrencyConfig holds configuration for the concurrency limiter.
+type MaxConcurrencyConfig struct {
+	// Limit is the number of requests handled at the same time (default: 100).
+	Limit int
+
+	// QueueSize is the number of requests that may wait for a slot once Limit
+	// is reached (default: Limit). Requests beyond it are shed immediately.
+	QueueSize int
+
+	// QueueTimeout is how long a queued request waits for a slot before it's
+	// shed. Zero sheds requests as soon as Limit is reached.
+	QueueTimeout time.Duration
+
+	// KeyFunc splits requests into groups that are limited separately, such
+	// as path prefixes. It should return a small, fixed set of keys. All
+	// requests share one limit if nil.
+	KeyFunc func(c *Context) string
+
+	// StatusCode is the status of shed requests (default: 503).
+	StatusCode int
+
+	// RetryAfter is sent in the Retry-After header of shed requests
+	// (default: 1 second).
+	RetryAfter time.Duration
+
+	// Skip returns true for requests that aren't limited.
+	Skip func(c *Context) bool
+}
+
+// concurrencyLimiter is a semaphore with a bounded wait queue.
+type concurrencyLimiter struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+// acquire takes a slot, waiting up to timeout if queueing is allowed. It
+// reports whether a slot was taken.
+func (l *concurrencyLimiter) acquire(c *Context, queueSize int, timeout time.Duration) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if timeout <= 0 {
+		return false
+	}
+	if l.waiting.Add(1) > int64(queueSize) {
+		l.waiting.Add(-1)
+		return false
+	}
+	defer l.waiting.Add(-1)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Context().Done():
+		return false
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// MaxConcurrency returns a middleware that handles at most n requests at
+// the same time. Requests beyond n wait up to queueTimeout for a slot, with
+// at most n waiting; the rest get a 503 with Retry-After.
+//
+// Example:
+//
+//	app.Use(nexo.MaxConcurrency(200, 2*time.Second))
+func MaxConcurrency(n int, queueTimeout time.Duration) MiddlewareFunc {
+	return MaxConcurrencyWithConfig(MaxConcurrencyConfig{Limit: n, QueueTimeout: queueTimeout})
+}
+
+// MaxConcurrencyWithConfig returns a concurrency limiter with custom config.
+// Shed requests get StatusCode with a Retry-After header.
+//
+// Example:
+//
+//	app.Use(nexo.MaxConcurrencyWithConfig(nexo.MaxConcurrencyConfig{
+//	    Limit:        20,
+//	    QueueTimeout: time.Second,
+//	    StatusCode:   http.StatusTooManyRequests,
+//	    KeyFunc: func(c *nexo.Context) string {
+//	        if strings.HasPrefix(c.Path(), "/api/reports") {
+//	            return "reports"
+//	        }
+//	        return "default"
+//	    },
+//	}))
+func MaxConcurrencyWithConfig(config MaxConcurrencyConfig) MiddlewareFunc {
+	if config.Limit <= 0 {
+		config.Limit = 100
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = config.Limit
+	}
+	if config.StatusCode == 0 {
+		config.StatusCode = http.StatusServiceUnavailable
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = time.Second
+	}
+	retryAfter := strconv.Itoa(max(ceilSeconds(config.RetryAfter), 1))
+
+	var mu sync.Mutex
+	limiters := make(map[string]*concurrencyLimiter)
+	limiterFor := func(key string) *concurrencyLimiter {
+		mu.Lock()
+		defer mu.Unlock()
+		l, ok := limiters[key]
+		if !ok {
+			l = &concurrencyLimiter{slots: make(chan struct{}, config.Limit)}
+			limiters[key] = l
+		}
+		return l
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			var key string
+			if config.KeyFunc != nil {
+				key = config.KeyFunc(c)
+			}
+			l := limiterFor(key)
+			if !l.acquire(c, config.QueueSize, config.QueueTimeout) {
+				c.SetHeader("Retry-After", retryAfter)
+				handleError(c, NewHTTPError(config.StatusCode, "server is busy"))
+				return nil
+			}
+			defer l.release()
+			return next(c)
+		}
+	}
+}
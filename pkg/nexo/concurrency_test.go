@@ -0,0 +1,190 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newBlockingApp returns an app whose /slow handler blocks until release is
+// closed, signalling started each time a request begins.
+func newBlockingApp(mw MiddlewareFunc) (app *App, started chan struct{}, release chan struct{}) {
+	started = make(chan struct{}, 16)
+	release = make(chan struct{})
+	app = New()
+	app.DisableLogger()
+	app.Use(mw)
+	handler := func(c *Context) error {
+		started <- struct{}{}
+		<-release
+		return c.String(http.StatusOK, "ok")
+	}
+	app.Get("/slow", handler)
+	app.Get("/other/slow", handler)
+	app.Get("/fast", func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	app.Mount()
+	return app, started, release
+}
+
+func serve(app *App, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestMaxConcurrency_Sheds(t *testing.T) {
+	app, started, release := newBlockingApp(MaxConcurrency(1, 0))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve(app, "/slow")
+	}()
+	<-started
+
+	w := serve(app, "/fast")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected Retry-After 1, got %q", w.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	wg.Wait()
+	if w := serve(app, "/fast"); w.Code != http.StatusOK {
+		t.Errorf("expected 200 once the slot is free, got %d", w.Code)
+	}
+}
+
+func TestMaxConcurrency_Queue(t *testing.T) {
+	app, started, release := newBlockingApp(MaxConcurrency(1, 5*time.Second))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve(app, "/slow")
+	}()
+	<-started
+
+	done := make(chan int)
+	go func() {
+		done <- serve(app, "/fast").Code
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("expected the queued request to succeed, got %d", code)
+	}
+	wg.Wait()
+}
+
+func TestMaxConcurrency_QueueTimeout(t *testing.T) {
+	app, started, release := newBlockingApp(MaxConcurrency(1, 50*time.Millisecond))
+	defer close(release)
+
+	go serve(app, "/slow")
+	<-started
+
+	start := time.Now()
+	if w := serve(app, "/fast"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the request to wait in the queue, returned after %v", elapsed)
+	}
+}
+
+func TestMaxConcurrency_QueueFull(t *testing.T) {
+	app, started, release := newBlockingApp(MaxConcurrencyWithConfig(MaxConcurrencyConfig{
+		Limit:        1,
+		QueueSize:    1,
+		QueueTimeout: 5 * time.Second,
+		StatusCode:   http.StatusTooManyRequests,
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		serve(app, "/slow")
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		serve(app, "/slow")
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	if w := serve(app, "/fast"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", w.Code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected a full queue to shed immediately, took %v", elapsed)
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestMaxConcurrency_KeyFunc(t *testing.T) {
+	app, started, release := newBlockingApp(MaxConcurrencyWithConfig(MaxConcurrencyConfig{
+		Limit: 1,
+		KeyFunc: func(c *Context) string {
+			if strings.HasPrefix(c.Path(), "/other") {
+				return "other"
+			}
+			return "default"
+		},
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		serve(app, "/slow")
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		serve(app, "/other/slow")
+	}()
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a separate limit for /other")
+	}
+
+	if w := serve(app, "/fast"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for the default group, got %d", w.Code)
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestMaxConcurrency_Skip(t *testing.T) {
+	app, started, release := newBlockingApp(MaxConcurrencyWithConfig(MaxConcurrencyConfig{
+		Limit: 1,
+		Skip: func(c *Context) bool {
+			return c.Path() == "/fast"
+		},
+	}))
+	defer close(release)
+
+	go serve(app, "/slow")
+	<-started
+
+	if w := serve(app, "/fast"); w.Code != http.StatusOK {
+		t.Errorf("expected skipped requests to pass, got %d", w.Code)
+	}
+}
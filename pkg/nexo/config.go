@@ -2,8 +2,10 @@ package nexo
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -24,6 +26,9 @@ type Config struct {
 
 	// Middleware configuration
 	Middleware MiddlewareConfig `mapstructure:"middleware"`
+
+	// HTTP server tuning
+	Server ServerConfig `mapstructure:"server"`
 }
 
 // DevConfig holds development-specific configuration.
@@ -31,6 +36,11 @@ type DevConfig struct {
 	HotReload       bool     `mapstructure:"hot_reload"`
 	WatchExtensions []string `mapstructure:"watch_extensions"`
 	ExcludeDirs     []string `mapstructure:"exclude_dirs"`
+
+	// Debug mounts the pprof and expvar endpoints under /debug
+	Debug bool `mapstructure:"debug"`
+	// DebugToken, if set, is required to access the debug endpoints
+	DebugToken string `mapstructure:"debug_token"`
 }
 
 // MiddlewareConfig holds middleware-specific configuration.
@@ -39,6 +49,48 @@ type MiddlewareConfig struct {
 	Recover bool `mapstructure:"recover"`
 }
 
+// ServerConfig holds the HTTP server's timeouts and limits. Zero values use
+// the defaults; negative timeouts disable the timeout.
+type ServerConfig struct {
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+}
+
+// DefaultServerConfig returns the default server timeouts and limits.
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+	}
+}
+
+// withDefaults returns c with zero values replaced by the defaults.
+func (c ServerConfig) withDefaults() ServerConfig {
+	def := DefaultServerConfig()
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = def.ReadTimeout
+	}
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = def.ReadHeaderTimeout
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = def.WriteTimeout
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = def.IdleTimeout
+	}
+	if c.MaxHeaderBytes == 0 {
+		c.MaxHeaderBytes = def.MaxHeaderBytes
+	}
+	return c
+}
+
 // DefaultConfig returns the default configuration.
 func DefaultConfig() *Config {
 	return &Config{
@@ -49,13 +101,14 @@ func DefaultConfig() *Config {
 		StaticURL: "/static",
 		Dev: DevConfig{
 			HotReload:       true,
-			WatchExtensions: []string{".go", ".templ"},
+			WatchExtensions: []string{".go", ".templ", ".css"},
 			ExcludeDirs:     []string{"node_modules", ".git"},
 		},
 		Middleware: MiddlewareConfig{
 			Logger:  true,
 			Recover: true,
 		},
+		Server: DefaultServerConfig(),
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -158,9 +159,13 @@ static_dir: "public"
 static_path: "/assets"
 dev:
   hot_reload: false
+  debug: true
 middleware:
   logger: false
   recover: false
+server:
+  read_timeout: 30s
+  max_header_bytes: 65536
 `
 	configPath := filepath.Join(tmpDir, "nexo.yaml")
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
@@ -190,12 +195,24 @@ middleware:
 	if config.Dev.HotReload {
 		t.Error("expected hot_reload to be false")
 	}
+	if !config.Dev.Debug {
+		t.Error("expected dev.debug to be true")
+	}
 	if config.Middleware.Logger {
 		t.Error("expected middleware.logger to be false")
 	}
 	if config.Middleware.Recover {
 		t.Error("expected middleware.recover to be false")
 	}
+	if config.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("expected server.read_timeout 30s, got %v", config.Server.ReadTimeout)
+	}
+	if config.Server.MaxHeaderBytes != 65536 {
+		t.Errorf("expected server.max_header_bytes 65536, got %d", config.Server.MaxHeaderBytes)
+	}
+	if config.Server.WriteTimeout != 15*time.Second {
+		t.Errorf("expected the default server.write_timeout, got %v", config.Server.WriteTimeout)
+	}
 }
 
 func TestLoadConfig_InvalidYAML(t *testing.T) {
@@ -0,0 +1,58 @@
+package nexo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParamConstraints maps the constraint names allowed in dynamic segments,
+// like [id:int], to the regular expressions they match.
+var ParamConstraints = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"alpha": `[a-zA-Z]+`,
+	"alnum": `[a-zA-Z0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+// RouteParam returns the route pattern segment for a dynamic parameter
+// with an optional constraint: a name from ParamConstraints or
+// regexp(...). Requests whose segment doesn't match a constraint fall
+// through to other routes, or 404.
+//
+// Example:
+//
+//	RouteParam("id", "")                   // {id}
+//	RouteParam("id", "int")                // {id:-?[0-9]+}
+//	RouteParam("code", "regexp([A-Z]{3})") // {code:[A-Z]{3}}
+func RouteParam(name, constraint string) (string, error) {
+	if constraint == "" {
+		return "{" + name + "}", nil
+	}
+
+	expr, ok := ParamConstraints[constraint]
+	if !ok {
+		inner, isRegexp := strings.CutPrefix(constraint, "regexp(")
+		inner, closed := strings.CutSuffix(inner, ")")
+		if !isRegexp || !closed || inner == "" {
+			return "", fmt.Errorf("unknown constraint %q on parameter %q", constraint, name)
+		}
+		if _, err := regexp.Compile(inner); err != nil {
+			return "", fmt.Errorf("invalid regexp constraint on parameter %q: %w", name, err)
+		}
+		expr = inner
+	}
+
+	return "{" + name + ":" + expr + "}", nil
+}
+
+// splitRouteParam splits a pattern segment like {id} or {id:[0-9]+} into
+// the parameter name and its regular expression.
+func splitRouteParam(seg string) (name, expr string, ok bool) {
+	if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+		return "", "", false
+	}
+	name, expr, _ = strings.Cut(seg[1:len(seg)-1], ":")
+	return name, expr, true
+}
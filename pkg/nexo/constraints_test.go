@@ -0,0 +1,73 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteParam(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{"id", "", "{id}", false},
+		{"id", "int", "{id:-?[0-9]+}", false},
+		{"id", "uint", "{id:[0-9]+}", false},
+		{"slug", "alpha", "{slug:[a-zA-Z]+}", false},
+		{"code", "regexp([A-Z]{3})", "{code:[A-Z]{3}}", false},
+		{"id", "number", "", true},
+		{"code", "regexp()", "", true},
+		{"code", "regexp([A-Z)", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+":"+tt.constraint, func(t *testing.T) {
+			got, err := RouteParam(tt.name, tt.constraint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RouteParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RouteParam() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteParam_Matching(t *testing.T) {
+	app := New()
+	id, _ := RouteParam("id", "int")
+	slug, _ := RouteParam("slug", "alpha")
+	app.Get("/posts/"+id, func(c *Context) error {
+		return c.String(200, "id "+c.Param("id"))
+	})
+	app.Get("/posts/"+slug, func(c *Context) error {
+		return c.String(200, "slug "+c.Param("slug"))
+	})
+	app.Mount()
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/posts/42", http.StatusOK, "id 42"},
+		{"/posts/hello", http.StatusOK, "slug hello"},
+		{"/posts/hello-42", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
@@ -3,12 +3,16 @@ package nexo
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
@@ -31,11 +35,20 @@ type Context struct {
 	// store holds request-scoped values.
 	store map[string]any
 
+	// keyed holds values stored with typed keys (see Key).
+	keyed map[any]any
+
 	// written tracks if a response has been written.
 	written bool
 
 	// status holds the response status code.
 	status int
+
+	// sse is the stream opened by SSE, closed when the handler returns.
+	sse *SSEWriter
+
+	// cancels releases contexts created by WithTimeout.
+	cancels []context.CancelFunc
 }
 
 // NewContext creates a new Context from an HTTP request and response.
@@ -61,6 +74,29 @@ func (c *Context) WithContext(ctx context.Context) *Context {
 	return c
 }
 
+// WithTimeout replaces the request's context with one that is canceled
+// after d, so Context() and everything derived from it stop slow downstream
+// calls. It returns the cancel function; the context is also canceled when
+// the handler returns. A deadline can only be shortened: an earlier
+// deadline, such as one from WithHandlerTimeout, still applies.
+//
+// Example:
+//
+//	func Get(c *nexo.Context) error {
+//	    c.WithTimeout(2 * time.Second)
+//	    rows, err := db.QueryContext(c.Context(), query)
+//	    if err != nil {
+//	        return err // 504 if the deadline passed
+//	    }
+//	    ...
+//	}
+func (c *Context) WithTimeout(d time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+	c.Request = c.Request.WithContext(ctx)
+	c.cancels = append(c.cancels, cancel)
+	return cancel
+}
+
 // ---------- URL Parameters ----------
 
 // Param returns a URL parameter by name.
@@ -84,6 +120,25 @@ func (c *Context) ParamInt(key string, def int) int {
 	return def
 }
 
+// ParamInt64 returns a URL parameter as an int64 with a default value.
+func (c *Context) ParamInt64(key string, def int64) int64 {
+	if i, err := strconv.ParseInt(c.Param(key), 10, 64); err == nil {
+		return i
+	}
+	return def
+}
+
+// ParamUUID returns a URL parameter that must be a UUID, in lowercase
+// canonical form. Missing or malformed values return a 400 HTTPError.
+func (c *Context) ParamUUID(key string) (string, error) {
+	val := c.Param(key)
+	id, ok := parseUUID(val)
+	if !ok {
+		return "", BadRequest(fmt.Sprintf("invalid path parameter %q: %q is not a UUID", key, val))
+	}
+	return id, nil
+}
+
 // ParamAll returns all segments for catch-all routes.
 // For a catch-all param like [...slug], this returns the segments split by "/".
 func (c *Context) ParamAll(key string) []string {
@@ -118,6 +173,31 @@ func (c *Context) QueryInt(key string, def int) int {
 	return def
 }
 
+// QueryInt64 returns a query param as an int64 with a default value.
+func (c *Context) QueryInt64(key string, def int64) int64 {
+	if i, err := strconv.ParseInt(c.query.Get(key), 10, 64); err == nil {
+		return i
+	}
+	return def
+}
+
+// QueryFloat returns a query param as a float64 with a default value.
+func (c *Context) QueryFloat(key string, def float64) float64 {
+	if f, err := strconv.ParseFloat(c.query.Get(key), 64); err == nil {
+		return f
+	}
+	return def
+}
+
+// QueryTime returns a query param parsed with layout, such as
+// time.RFC3339 or time.DateOnly, with a default value.
+func (c *Context) QueryTime(key, layout string, def time.Time) time.Time {
+	if t, err := time.Parse(layout, c.query.Get(key)); err == nil {
+		return t
+	}
+	return def
+}
+
 // QueryBool returns a query param as a bool with a default value.
 func (c *Context) QueryBool(key string, def bool) bool {
 	val := c.query.Get(key)
@@ -179,17 +259,6 @@ func (c *Context) FormFile(key string) (*multipart.FileHeader, error) {
 	return fh, err
 }
 
-// Bind parses the JSON request body into the provided struct.
-func (c *Context) Bind(v any) error {
-	if c.Request.Body == nil {
-		return NewHTTPError(http.StatusBadRequest, "empty request body")
-	}
-	if err := json.NewDecoder(c.Request.Body).Decode(v); err != nil {
-		return NewHTTPErrorWithCause(http.StatusBadRequest, "invalid JSON", err)
-	}
-	return nil
-}
-
 // ---------- Response Methods ----------
 
 // Status sets the response status code.
@@ -207,6 +276,85 @@ func (c *Context) JSON(status int, data any) error {
 	return json.NewEncoder(c.Response).Encode(data)
 }
 
+// JSONPretty sends a JSON response indented with indent, e.g. "  ", for
+// debug endpoints and human readers. Values that can't be encoded return a
+// 500 HTTPError before anything is written.
+func (c *Context) JSONPretty(status int, data any, indent string) error {
+	body, err := json.MarshalIndent(data, "", indent)
+	if err != nil {
+		return NewHTTPErrorWithCause(http.StatusInternalServerError, "failed to encode JSON", err)
+	}
+	return c.JSONBlob(status, append(body, '\n'))
+}
+
+// JSONBlob sends already-encoded JSON, such as a cached or upstream
+// payload, without decoding and re-encoding it.
+func (c *Context) JSONBlob(status int, data []byte) error {
+	return c.Blob(status, "application/json; charset=utf-8", data)
+}
+
+// JSONP sends data as a JSONP response wrapping it in a call to callback.
+// Callbacks that aren't JavaScript identifiers or dotted paths such as
+// "app.handlers.done" return a 400 HTTPError, so the callback can be taken
+// from the query string.
+//
+// Example:
+//
+//	return c.JSONP(200, c.QueryDefault("callback", "cb"), data)
+func (c *Context) JSONP(status int, callback string, data any) error {
+	if !validJSONPCallback(callback) {
+		return BadRequest("invalid JSONP callback")
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return NewHTTPErrorWithCause(http.StatusInternalServerError, "failed to encode JSON", err)
+	}
+	c.SetHeader("X-Content-Type-Options", "nosniff")
+	// The leading comment stops the response being read as another file type
+	js := make([]byte, 0, len(body)+len(callback)+8)
+	js = append(js, "/**/"+callback+"("...)
+	js = append(js, body...)
+	js = append(js, ");"...)
+	return c.Blob(status, "application/javascript; charset=utf-8", js)
+}
+
+// validJSONPCallback reports whether callback is a JavaScript identifier
+// or a dotted path of identifiers.
+func validJSONPCallback(callback string) bool {
+	if callback == "" || len(callback) > 128 {
+		return false
+	}
+	for _, part := range strings.Split(callback, ".") {
+		if part == "" || ('0' <= part[0] && part[0] <= '9') {
+			return false
+		}
+		for _, r := range part {
+			if !(r == '_' || r == '$' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// XML sends an XML response with an XML declaration. Values that can't be
+// encoded return a 500 HTTPError before anything is written.
+func (c *Context) XML(status int, data any) error {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		return NewHTTPErrorWithCause(http.StatusInternalServerError, "failed to encode XML", err)
+	}
+	c.SetHeader("Content-Type", "application/xml; charset=utf-8")
+	c.Response.WriteHeader(status)
+	c.written = true
+	c.status = status
+	if _, err := io.WriteString(c.Response, xml.Header); err != nil {
+		return err
+	}
+	_, err = c.Response.Write(body)
+	return err
+}
+
 // String sends a plain text response.
 func (c *Context) String(status int, s string) error {
 	c.SetHeader("Content-Type", "text/plain; charset=utf-8")
@@ -257,14 +405,17 @@ func (c *Context) Redirect(url string, status ...int) error {
 	return nil
 }
 
-// Error sends a JSON error response.
+// Error sends a JSON error response. With the RequestID middleware, the
+// error includes the request ID so it can be matched with the logs.
 func (c *Context) Error(status int, message string) error {
-	return c.JSON(status, map[string]any{
-		"error": map[string]any{
-			"code":    status,
-			"message": message,
-		},
-	})
+	body := map[string]any{
+		"code":    status,
+		"message": message,
+	}
+	if id := c.RequestID(); id != "" {
+		body["request_id"] = id
+	}
+	return c.JSON(status, map[string]any{"error": body})
 }
 
 // ---------- Context Store ----------
@@ -321,7 +472,8 @@ func (c *Context) IsJSON() bool {
 	return strings.Contains(accept, "application/json") || strings.Contains(accept, "*/*")
 }
 
-// IsHTMX checks if this is an HTMX request.
+// IsHTMX checks if this is an HTMX request. Responses that differ for
+// HTMX requests should call VaryHTMX too.
 func (c *Context) IsHTMX() bool {
 	return c.Request.Header.Get("HX-Request") == "true"
 }
@@ -332,8 +484,16 @@ func (c *Context) IsWebSocket() bool {
 	return strings.EqualFold(upgrade, "websocket")
 }
 
-// ClientIP returns the client's IP address.
+// ClientIP returns the client's IP address. With WithTrustedProxies, it is
+// read from X-Forwarded-For only for requests from those proxies; otherwise
+// the X-Forwarded-For and X-Real-IP headers are used as sent.
 func (c *Context) ClientIP() string {
+	if trusted, _ := c.Request.Context().Value(trustedProxiesKey{}).([]netip.Prefix); len(trusted) > 0 {
+		if addr, ok := forwardedAddr(c.Request, trusted); ok {
+			return addr.String()
+		}
+		return c.Request.RemoteAddr
+	}
 	// Check X-Forwarded-For header first
 	if ip := c.Request.Header.Get("X-Forwarded-For"); ip != "" {
 		return strings.TrimSpace(strings.Split(ip, ",")[0])
@@ -402,7 +562,10 @@ func (c *Context) SetCookie(cookie *http.Cookie) {
 // ---------- SSE (Server-Sent Events) ----------
 
 // SSE returns an SSEWriter for streaming Server-Sent Events.
-// Sets appropriate headers and returns a writer for sending events.
+// Sets appropriate headers and returns a writer for sending events. The
+// writer sends a keep-alive comment every DefaultSSEHeartbeat, its Done
+// channel is closed when the client disconnects, and it is closed when the
+// handler returns.
 //
 // Example:
 //
@@ -412,14 +575,14 @@ func (c *Context) SetCookie(cookie *http.Cookie) {
 //	        return err
 //	    }
 //
-//	    for i := 0; i < 10; i++ {
-//	        if sse.IsClosed() {
-//	            break // Client disconnected
+//	    for {
+//	        select {
+//	        case <-sse.Done():
+//	            return nil // Client disconnected
+//	        case msg := <-messages:
+//	            sse.SendJSON("message", msg)
 //	        }
-//	        sse.Send("message", fmt.Sprintf("Event %d", i))
-//	        time.Sleep(time.Second)
 //	    }
-//	    return nil
 //	}
 func (c *Context) SSE() (*SSEWriter, error) {
 	flusher, ok := c.Response.(http.Flusher)
@@ -433,7 +596,30 @@ func (c *Context) SSE() (*SSEWriter, error) {
 	c.SetHeader("X-Accel-Buffering", "no") // Disable nginx buffering
 	c.written = true
 
-	return &SSEWriter{w: c.Response, flusher: flusher}, nil
+	// Streams outlive the server's WriteTimeout
+	_ = http.NewResponseController(c.Response).SetWriteDeadline(time.Time{})
+
+	// Send the headers now so clients see the stream open
+	c.Response.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	c.sse = newSSEWriter(c.Response, flusher, c.Request.Context())
+	c.sse.lastEventID = c.Header("Last-Event-ID")
+	if c.sse.lastEventID == "" {
+		c.sse.lastEventID = c.Query("lastEventId")
+	}
+	c.sse.Heartbeat(DefaultSSEHeartbeat)
+	return c.sse, nil
+}
+
+// finish releases resources held by the context once the handler returns.
+func (c *Context) finish() {
+	if c.sse != nil {
+		c.sse.Close()
+	}
+	for _, cancel := range c.cancels {
+		cancel()
+	}
 }
 
 // ---------- Additional Context Helpers ----------
@@ -446,3 +632,39 @@ func (c *Context) GetBool(key string) bool {
 	}
 	return false
 }
+
+// GetInt64 retrieves an int64 value from the request context.
+// Returns 0 if the key doesn't exist or is not an int64.
+func (c *Context) GetInt64(key string) int64 {
+	if val, ok := c.store[key].(int64); ok {
+		return val
+	}
+	return 0
+}
+
+// GetFloat64 retrieves a float64 value from the request context.
+// Returns 0 if the key doesn't exist or is not a float64.
+func (c *Context) GetFloat64(key string) float64 {
+	if val, ok := c.store[key].(float64); ok {
+		return val
+	}
+	return 0
+}
+
+// GetTime retrieves a time.Time value from the request context.
+// Returns the zero time if the key doesn't exist or is not a time.Time.
+func (c *Context) GetTime(key string) time.Time {
+	if val, ok := c.store[key].(time.Time); ok {
+		return val
+	}
+	return time.Time{}
+}
+
+// GetDuration retrieves a time.Duration value from the request context.
+// Returns 0 if the key doesn't exist or is not a time.Duration.
+func (c *Context) GetDuration(key string) time.Duration {
+	if val, ok := c.store[key].(time.Duration); ok {
+		return val
+	}
+	return 0
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewContext(t *testing.T) {
@@ -216,6 +219,103 @@ func TestContext_JSON(t *testing.T) {
 	}
 }
 
+func TestContext_JSONPretty(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.JSONPretty(http.StatusOK, map[string]any{"a": 1, "b": []int{2}}, "  "); err != nil {
+		t.Fatalf("JSONPretty failed: %v", err)
+	}
+
+	want := "{\n  \"a\": 1,\n  \"b\": [\n    2\n  ]\n}\n"
+	if w.Body.String() != want {
+		t.Errorf("Expected %q, got %q", want, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Expected JSON content type, got '%s'", got)
+	}
+
+	c = NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if httpErr, ok := IsHTTPError(c.JSONPretty(http.StatusOK, make(chan int), "  ")); !ok || httpErr.Code != http.StatusInternalServerError || c.Written() {
+		t.Error("Expected a 500 HTTPError and nothing written for an unencodable value")
+	}
+}
+
+func TestContext_JSONBlob(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	raw := []byte(`{"cached":true}`)
+	if err := c.JSONBlob(http.StatusAccepted, raw); err != nil {
+		t.Fatalf("JSONBlob failed: %v", err)
+	}
+	if w.Code != http.StatusAccepted || w.Body.String() != string(raw) {
+		t.Errorf("Expected 202 %s, got %d %s", raw, w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Expected JSON content type, got '%s'", got)
+	}
+}
+
+func TestContext_JSONP(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.JSONP(http.StatusOK, "app.handlers.done_1", map[string]string{"ok": "yes"}); err != nil {
+		t.Fatalf("JSONP failed: %v", err)
+	}
+	if want := `/**/app.handlers.done_1({"ok":"yes"});`; w.Body.String() != want {
+		t.Errorf("Expected %q, got %q", want, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/javascript; charset=utf-8" {
+		t.Errorf("Expected JavaScript content type, got '%s'", got)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("Expected nosniff, got '%s'", got)
+	}
+
+	for _, callback := range []string{"", "alert(1)//", "a..b", "1cb", "cb;evil", "<script>"} {
+		c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		err := c.JSONP(http.StatusOK, callback, nil)
+		if httpErr, ok := IsHTTPError(err); !ok || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("JSONP(%q) error = %v, want a 400", callback, err)
+		}
+	}
+}
+
+func TestContext_XML(t *testing.T) {
+	type user struct {
+		XMLName xml.Name `xml:"user"`
+		Name    string   `xml:"name"`
+	}
+
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.XML(http.StatusCreated, user{Name: "Ada"}); err != nil {
+		t.Fatalf("XML failed: %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/xml; charset=utf-8" {
+		t.Errorf("Expected XML content type, got '%s'", got)
+	}
+	if want := xml.Header + "<user><name>Ada</name></user>"; w.Body.String() != want {
+		t.Errorf("Expected %q, got %q", want, w.Body.String())
+	}
+}
+
+func TestContext_XML_EncodeError(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	err := c.XML(http.StatusOK, map[string]string{"a": "b"})
+	if httpErr, ok := IsHTTPError(err); !ok || httpErr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected a 500 HTTPError, got %v", err)
+	}
+	if c.Written() {
+		t.Error("Expected nothing to be written when encoding fails")
+	}
+}
+
 func TestContext_String(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
@@ -810,3 +910,58 @@ func TestContext_GetBool(t *testing.T) {
 		t.Error("expected GetBool('text') to be false for non-bool value")
 	}
 }
+
+func TestContext_WithTimeout(t *testing.T) {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cancel := c.WithTimeout(time.Hour)
+	deadline, ok := c.Context().Deadline()
+	if !ok || time.Until(deadline) < 59*time.Minute {
+		t.Fatalf("Expected a deadline about an hour away, got %v %v", deadline, ok)
+	}
+
+	// A longer timeout doesn't extend the deadline
+	c.WithTimeout(2 * time.Hour)
+	if d, _ := c.Context().Deadline(); !d.Equal(deadline) {
+		t.Errorf("Expected deadline %v to be kept, got %v", deadline, d)
+	}
+
+	cancel()
+	if c.Context().Err() == nil {
+		t.Error("Expected the context to be canceled")
+	}
+}
+
+func TestContext_WithTimeout_CanceledWhenHandlerReturns(t *testing.T) {
+	var ctx context.Context
+	app := New()
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error {
+		c.WithTimeout(time.Hour)
+		ctx = c.Context()
+		return c.NoContent()
+	})
+	app.Mount()
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if ctx == nil || ctx.Err() == nil {
+		t.Error("Expected the timeout context to be canceled after the handler returned")
+	}
+}
+
+func TestContext_DeadlineExceededIs504(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error {
+		c.WithTimeout(time.Millisecond)
+		<-c.Context().Done()
+		return fmt.Errorf("querying users: %w", c.Context().Err())
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504, got %d", w.Code)
+	}
+}
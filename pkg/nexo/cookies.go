@@ -0,0 +1,260 @@
+package nexo
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCookieSize is the largest cookie browsers are guaranteed to store.
+const maxCookieSize = 4096
+
+// cookieCodecKey is the request context key for the app's cookie codec.
+type cookieCodecKey struct{}
+
+// cookieCodec signs and encrypts cookie values with keys derived from the
+// app's cookie secrets. The first secret is used for new cookies; the rest
+// are still accepted so secrets can be rotated.
+type cookieCodec struct {
+	keys []cookieKeys
+}
+
+// cookieKeys are the keys derived from one secret.
+type cookieKeys struct {
+	sign []byte      // HMAC-SHA256 key for signed cookies
+	aead cipher.AEAD // AES-256-GCM for encrypted cookies
+}
+
+// newCookieCodec returns a codec for the non-empty secrets, or nil if there
+// are none.
+func newCookieCodec(secrets []string) *cookieCodec {
+	codec := &cookieCodec{}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		block, _ := aes.NewCipher(deriveKey(secret, "nexo cookie encryption"))
+		aead, _ := cipher.NewGCM(block)
+		codec.keys = append(codec.keys, cookieKeys{
+			sign: deriveKey(secret, "nexo cookie signing"),
+			aead: aead,
+		})
+	}
+	if len(codec.keys) == 0 {
+		return nil
+	}
+	return codec
+}
+
+// deriveKey derives a 32-byte key for purpose from secret, so signing and
+// encryption never share a key.
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// signMAC returns the MAC of a payload for the named cookie. The name is
+// included so a value can't be moved to another cookie.
+func signMAC(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "|" + payload))
+	return mac.Sum(nil)
+}
+
+// sign returns value with its expiry and a MAC, readable but tamper-proof.
+func (cc *cookieCodec) sign(name, value string, expires int64) string {
+	payload := cookiePayload(value, expires)
+	mac := signMAC(cc.keys[0].sign, name, payload)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac)
+}
+
+// verify checks a value created by sign and returns the original value.
+func (cc *cookieCodec) verify(name, encoded string) (string, error) {
+	data, sig, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, keys := range cc.keys {
+		if hmac.Equal(mac, signMAC(keys.sign, name, string(payload))) {
+			return parseCookiePayload(string(payload))
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+// encrypt returns value and its expiry encrypted, so clients can neither
+// read nor change it.
+func (cc *cookieCodec) encrypt(name, value string, expires int64) (string, error) {
+	aead := cc.keys[0].aead
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(cookiePayload(value, expires)), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt decrypts a value created by encrypt.
+func (cc *cookieCodec) decrypt(name, encoded string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, keys := range cc.keys {
+		size := keys.aead.NonceSize()
+		if len(sealed) < size {
+			return "", ErrInvalidCookie
+		}
+		payload, err := keys.aead.Open(nil, sealed[:size], sealed[size:], []byte(name))
+		if err == nil {
+			return parseCookiePayload(string(payload))
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+// cookiePayload joins an expiry (unix seconds, 0 for none) and a value.
+// The expiry is checked on read, so a copied cookie stops working when the
+// original would have expired.
+func cookiePayload(value string, expires int64) string {
+	return strconv.FormatInt(expires, 10) + "|" + value
+}
+
+// parseCookiePayload returns the value of an unexpired payload.
+func parseCookiePayload(payload string) (string, error) {
+	exp, value, ok := strings.Cut(payload, "|")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	if expires != 0 && time.Now().Unix() > expires {
+		return "", ErrInvalidCookie
+	}
+	return value, nil
+}
+
+// cookieExpiry returns when cookie expires in unix seconds, or 0 for a
+// session cookie.
+func cookieExpiry(cookie *http.Cookie) int64 {
+	switch {
+	case cookie.MaxAge > 0:
+		return time.Now().Add(time.Duration(cookie.MaxAge) * time.Second).Unix()
+	case !cookie.Expires.IsZero():
+		return cookie.Expires.Unix()
+	default:
+		return 0
+	}
+}
+
+// withCookieCodec adds the codec to the request context, where Context's
+// cookie methods find it.
+func withCookieCodec(r *http.Request, codec *cookieCodec) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), cookieCodecKey{}, codec))
+}
+
+// cookieCodec returns the app's cookie codec.
+func (c *Context) cookieCodec() (*cookieCodec, error) {
+	codec, ok := c.Request.Context().Value(cookieCodecKey{}).(*cookieCodec)
+	if !ok || codec == nil {
+		return nil, ErrNoCookieSecret
+	}
+	return codec, nil
+}
+
+// setEncodedCookie sets a copy of cookie with the encoded value.
+func (c *Context) setEncodedCookie(cookie *http.Cookie, value string) error {
+	encoded := *cookie
+	encoded.Value = value
+	if size := len(encoded.String()); size > maxCookieSize {
+		return fmt.Errorf("cookie %s is %d bytes, more than the %d browsers store", cookie.Name, size, maxCookieSize)
+	}
+	c.SetCookie(&encoded)
+	return nil
+}
+
+// SetSignedCookie sets a cookie whose value is signed with the app's cookie
+// secret (see WithCookieSecret). Clients can read the value but not change
+// it. Read it back with SignedCookie.
+//
+// Example:
+//
+//	err := c.SetSignedCookie(&http.Cookie{
+//	    Name:     "user_id",
+//	    Value:    user.ID,
+//	    Path:     "/",
+//	    MaxAge:   86400,
+//	    HttpOnly: true,
+//	})
+func (c *Context) SetSignedCookie(cookie *http.Cookie) error {
+	codec, err := c.cookieCodec()
+	if err != nil {
+		return err
+	}
+	return c.setEncodedCookie(cookie, codec.sign(cookie.Name, cookie.Value, cookieExpiry(cookie)))
+}
+
+// SignedCookie returns the value of a cookie set with SetSignedCookie. It
+// returns http.ErrNoCookie if the cookie is missing and ErrInvalidCookie if
+// it was changed, has expired, or was signed with an unknown secret.
+func (c *Context) SignedCookie(name string) (string, error) {
+	codec, err := c.cookieCodec()
+	if err != nil {
+		return "", err
+	}
+	cookie, err := c.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return codec.verify(name, cookie.Value)
+}
+
+// SetEncryptedCookie sets a cookie whose value is encrypted with the app's
+// cookie secret (see WithCookieSecret), so clients can neither read nor
+// change it. Read it back with EncryptedCookie.
+func (c *Context) SetEncryptedCookie(cookie *http.Cookie) error {
+	codec, err := c.cookieCodec()
+	if err != nil {
+		return err
+	}
+	value, err := codec.encrypt(cookie.Name, cookie.Value, cookieExpiry(cookie))
+	if err != nil {
+		return err
+	}
+	return c.setEncodedCookie(cookie, value)
+}
+
+// EncryptedCookie returns the value of a cookie set with SetEncryptedCookie.
+// It returns http.ErrNoCookie if the cookie is missing and ErrInvalidCookie
+// if it was changed, has expired, or was encrypted with an unknown secret.
+func (c *Context) EncryptedCookie(name string) (string, error) {
+	codec, err := c.cookieCodec()
+	if err != nil {
+		return "", err
+	}
+	cookie, err := c.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return codec.decrypt(name, cookie.Value)
+}
@@ -0,0 +1,197 @@
+package nexo
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCookieApp returns an app with routes that set and read signed and
+// encrypted cookies.
+func newCookieApp(secrets ...string) *App {
+	app := New(WithCookieSecret(secrets...))
+	app.DisableLogger()
+	app.Get("/set", func(c *Context) error {
+		cookie := &http.Cookie{Name: "user", Value: "ada|admin", Path: "/", MaxAge: 3600}
+		if err := c.SetSignedCookie(cookie); err != nil {
+			return err
+		}
+		cookie = &http.Cookie{Name: "secret", Value: "42", Path: "/"}
+		if err := c.SetEncryptedCookie(cookie); err != nil {
+			return err
+		}
+		return c.NoContent()
+	})
+	app.Get("/get", func(c *Context) error {
+		user, err := c.SignedCookie("user")
+		if err != nil {
+			return c.String(http.StatusUnauthorized, "user: "+err.Error())
+		}
+		secret, err := c.EncryptedCookie("secret")
+		if err != nil {
+			return c.String(http.StatusUnauthorized, "secret: "+err.Error())
+		}
+		return c.String(http.StatusOK, user+" "+secret)
+	})
+	app.Mount()
+	return app
+}
+
+func setCookies(t *testing.T, app *App) map[string]*http.Cookie {
+	t.Helper()
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/set", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("GET /set status = %d: %s", w.Code, w.Body.String())
+	}
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func getWithCookies(app *App, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/get", nil)
+	for _, cookie := range cookies {
+		r.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	return w
+}
+
+func TestSignedAndEncryptedCookies(t *testing.T) {
+	app := newCookieApp("a-very-long-secret-used-for-testing-only")
+	cookies := setCookies(t, app)
+
+	user, secret := cookies["user"], cookies["secret"]
+	if user == nil || secret == nil {
+		t.Fatalf("cookies = %v", cookies)
+	}
+	if user.MaxAge != 3600 || user.Path != "/" {
+		t.Errorf("signed cookie attributes not kept: %+v", user)
+	}
+	if raw, _ := base64.RawURLEncoding.DecodeString(secret.Value); strings.Contains(string(raw), "|42") {
+		t.Errorf("encrypted cookie value is readable: %q", secret.Value)
+	}
+
+	w := getWithCookies(app, user, secret)
+	if w.Code != http.StatusOK || w.Body.String() != "ada|admin 42" {
+		t.Errorf("GET /get = %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestSignedCookie_Tampering(t *testing.T) {
+	app := newCookieApp("a-very-long-secret-used-for-testing-only")
+	cookies := setCookies(t, app)
+	user, secret := cookies["user"], cookies["secret"]
+
+	tampered := func(c *http.Cookie, value string) *http.Cookie {
+		return &http.Cookie{Name: c.Name, Value: value}
+	}
+	flip := func(s string) string {
+		b := []byte(s)
+		if b[0] == 'A' {
+			b[0] = 'B'
+		} else {
+			b[0] = 'A'
+		}
+		return string(b)
+	}
+
+	tests := map[string][]*http.Cookie{
+		"changed signed value":     {tampered(user, flip(user.Value)), secret},
+		"changed encrypted value":  {user, tampered(secret, flip(secret.Value))},
+		"signed value moved":       {tampered(user, user.Value), tampered(secret, user.Value)},
+		"signed without signature": {tampered(user, strings.Split(user.Value, ".")[0]), secret},
+		"missing cookie":           {secret},
+	}
+	for name, cookies := range tests {
+		t.Run(name, func(t *testing.T) {
+			if w := getWithCookies(app, cookies...); w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d %q, want 401", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Cookies from another secret are rejected
+	other := setCookies(t, newCookieApp("another-secret-entirely-different"))
+	if w := getWithCookies(app, other["user"], other["secret"]); w.Code != http.StatusUnauthorized {
+		t.Errorf("cookies from another secret: status = %d, want 401", w.Code)
+	}
+}
+
+func TestCookieSecretRotation(t *testing.T) {
+	old := setCookies(t, newCookieApp("old-secret-old-secret-old-secret"))
+
+	rotated := newCookieApp("new-secret-new-secret-new-secret", "old-secret-old-secret-old-secret")
+	if w := getWithCookies(rotated, old["user"], old["secret"]); w.Code != http.StatusOK {
+		t.Errorf("cookies from the old secret: status = %d %q, want 200", w.Code, w.Body.String())
+	}
+
+	current := setCookies(t, rotated)
+	newOnly := newCookieApp("new-secret-new-secret-new-secret")
+	if w := getWithCookies(newOnly, current["user"], current["secret"]); w.Code != http.StatusOK {
+		t.Errorf("new cookies should use the first secret: status = %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestCookieCodec_Expiry(t *testing.T) {
+	codec := newCookieCodec([]string{"secret"})
+	past := time.Now().Add(-time.Minute).Unix()
+
+	if _, err := codec.verify("n", codec.sign("n", "v", past)); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf("verify(expired) error = %v, want ErrInvalidCookie", err)
+	}
+	encrypted, err := codec.encrypt("n", "v", past)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := codec.decrypt("n", encrypted); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf("decrypt(expired) error = %v, want ErrInvalidCookie", err)
+	}
+}
+
+func TestCookieExpiry(t *testing.T) {
+	now := time.Now().Unix()
+	if got := cookieExpiry(&http.Cookie{MaxAge: 60}); got < now+59 || got > now+61 {
+		t.Errorf("cookieExpiry(MaxAge 60) = %d, want about %d", got, now+60)
+	}
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	if got := cookieExpiry(&http.Cookie{Expires: expires}); got != expires.Unix() {
+		t.Errorf("cookieExpiry(Expires) = %d, want %d", got, expires.Unix())
+	}
+	if got := cookieExpiry(&http.Cookie{}); got != 0 {
+		t.Errorf("cookieExpiry(session) = %d, want 0", got)
+	}
+}
+
+func TestSignedCookie_NoSecret(t *testing.T) {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.SetSignedCookie(&http.Cookie{Name: "a", Value: "b"}); !errors.Is(err, ErrNoCookieSecret) {
+		t.Errorf("SetSignedCookie() error = %v, want ErrNoCookieSecret", err)
+	}
+	if _, err := c.EncryptedCookie("a"); !errors.Is(err, ErrNoCookieSecret) {
+		t.Errorf("EncryptedCookie() error = %v, want ErrNoCookieSecret", err)
+	}
+	if newCookieCodec([]string{""}) != nil {
+		t.Error("newCookieCodec should ignore empty secrets")
+	}
+}
+
+func TestSetSignedCookie_TooLarge(t *testing.T) {
+	r := withCookieCodec(httptest.NewRequest(http.MethodGet, "/", nil), newCookieCodec([]string{"secret"}))
+	w := httptest.NewRecorder()
+	c := NewContext(w, r)
+	if err := c.SetSignedCookie(&http.Cookie{Name: "big", Value: strings.Repeat("x", maxCookieSize)}); err == nil {
+		t.Error("expected an error for a cookie over 4096 bytes")
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("oversized cookie should not be set")
+	}
+}
@@ -0,0 +1,37 @@
+package nexo
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// mountDebug mounts the pprof profiles under /debug/pprof and the expvar
+// variables at /debug/vars (see WithDebugEndpoints).
+func (a *App) mountDebug() {
+	var h http.Handler = middleware.Profiler()
+	if token := a.config.Dev.DebugToken; token != "" {
+		h = debugAuth(token, h)
+	}
+	a.router.Mount("/debug", h)
+}
+
+// debugAuth rejects requests without the token, sent as
+// "Authorization: Bearer <token>" or, for go tool pprof and browsers, as
+// the token query parameter.
+func debugAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			got = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="debug"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,71 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugEndpoints_Disabled(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestDebugEndpoints(t *testing.T) {
+	app := New(WithDebugEndpoints(true))
+	app.DisableLogger()
+	app.Mount()
+	app.Mount() // mounting again doesn't mount the endpoints twice
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"memstats"`) {
+		t.Errorf("expected expvar output, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine") {
+		t.Errorf("expected the pprof index, got %d", w.Code)
+	}
+}
+
+func TestDebugEndpoints_Token(t *testing.T) {
+	app := New(WithDebugEndpoints(true), WithDebugToken("s3cret"))
+	app.DisableLogger()
+	app.Mount()
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		status int
+	}{
+		{"no token", "/debug/vars", "", http.StatusUnauthorized},
+		{"wrong token", "/debug/vars", "Bearer nope", http.StatusUnauthorized},
+		{"bearer", "/debug/vars", "Bearer s3cret", http.StatusOK},
+		{"query", "/debug/pprof/cmdline?token=s3cret", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
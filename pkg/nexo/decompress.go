@@ -0,0 +1,129 @@
+package nexo
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DecompressDecoder is a content coding the decompress middleware can read.
+type DecompressDecoder struct {
+	// Encoding is the Content-Encoding token, e.g. "gzip" or "br".
+	Encoding string
+
+	// NewReader returns a reader that decompresses r. An error means the
+	// body isn't valid in this encoding.
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipDecoder decompresses gzip request bodies.
+var GzipDecoder = DecompressDecoder{
+	Encoding: "gzip",
+	NewReader: func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+}
+
+// DeflateDecoder decompresses deflate (the zlib format) request bodies.
+var DeflateDecoder = DecompressDecoder{
+	Encoding: "deflate",
+	NewReader: func(r io.Reader) (io.ReadCloser, error) {
+		return zlib.NewReader(r)
+	},
+}
+
+// DecompressConfig holds configuration for the decompress middleware.
+type DecompressConfig struct {
+	// MaxSize is the largest decompressed body, in bytes. Reading past it
+	// fails, and Bind returns a 413. Default is 10MB.
+	MaxSize int64
+
+	// Decoders are the content codings accepted. Default is gzip and
+	// deflate.
+	Decoders []DecompressDecoder
+
+	// Skip returns true for requests whose bodies are left as sent.
+	Skip func(c *Context) bool
+}
+
+// Decompress returns a middleware that decompresses gzip and deflate
+// request bodies, up to 10MB decompressed, so handlers and Bind read them
+// as if they were sent uncompressed.
+func Decompress() MiddlewareFunc {
+	return DecompressWithConfig(DecompressConfig{})
+}
+
+// DecompressWithConfig returns a decompress middleware with custom
+// configuration.
+//
+// Requests with an encoding that isn't accepted get a 415 Unsupported Media
+// Type, and bodies that aren't valid in their encoding a 400. The
+// Content-Encoding and Content-Length headers are removed from decompressed
+// requests.
+//
+// Example:
+//
+//	app.Use(nexo.DecompressWithConfig(nexo.DecompressConfig{
+//	    MaxSize: 50 << 20, // 50MB log batches
+//	}))
+func DecompressWithConfig(config DecompressConfig) MiddlewareFunc {
+	if config.MaxSize <= 0 {
+		config.MaxSize = 10 << 20
+	}
+	if config.Decoders == nil {
+		config.Decoders = []DecompressDecoder{GzipDecoder, DeflateDecoder}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			encoding := strings.ToLower(strings.TrimSpace(c.Header("Content-Encoding")))
+			if encoding == "" || encoding == "identity" || c.Request.Body == nil || c.Request.Body == http.NoBody {
+				return next(c)
+			}
+
+			var decoder *DecompressDecoder
+			for i := range config.Decoders {
+				if config.Decoders[i].Encoding == encoding {
+					decoder = &config.Decoders[i]
+					break
+				}
+			}
+			if decoder == nil {
+				return NewHTTPError(http.StatusUnsupportedMediaType, "unsupported content encoding")
+			}
+
+			body := c.Request.Body
+			reader, err := decoder.NewReader(body)
+			if err != nil {
+				return NewHTTPErrorWithCause(http.StatusBadRequest, "invalid "+encoding+" body", err)
+			}
+
+			c.Request = c.Request.Clone(c.Request.Context())
+			c.Request.Body = http.MaxBytesReader(c.Response, &decompressBody{ReadCloser: reader, body: body}, config.MaxSize)
+			c.Request.ContentLength = -1
+			c.Request.Header.Del("Content-Encoding")
+			c.Request.Header.Del("Content-Length")
+			return next(c)
+		}
+	}
+}
+
+// decompressBody closes the request body along with its decompressor.
+type decompressBody struct {
+	io.ReadCloser
+	body io.Closer
+}
+
+func (d *decompressBody) Close() error {
+	err := d.ReadCloser.Close()
+	if berr := d.body.Close(); err == nil {
+		err = berr
+	}
+	return err
+}
@@ -0,0 +1,143 @@
+package nexo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newDecompressApp(mw MiddlewareFunc) *App {
+	app := New()
+	app.DisableLogger()
+	app.Use(mw)
+	app.Post("/", func(c *Context) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind(&body); err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, body.Name+"|"+c.Header("Content-Encoding"))
+	})
+	app.Mount()
+	return app
+}
+
+func postEncoded(app *App, encoding string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func TestDecompress(t *testing.T) {
+	payload := []byte(`{"name":"nexo"}`)
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	_, _ = zw.Write(payload)
+	_ = zw.Close()
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		status   int
+		want     string
+	}{
+		{"gzip", "gzip", gzipBytes(t, payload), http.StatusOK, "nexo|"},
+		{"deflate", "deflate", deflated.Bytes(), http.StatusOK, "nexo|"},
+		{"uppercase token", "GZIP", gzipBytes(t, payload), http.StatusOK, "nexo|"},
+		{"identity", "identity", payload, http.StatusOK, "nexo|identity"},
+		{"uncompressed", "", payload, http.StatusOK, "nexo|"},
+		{"unsupported", "br", payload, http.StatusUnsupportedMediaType, ""},
+		{"corrupt", "gzip", payload, http.StatusBadRequest, ""},
+	}
+
+	app := newDecompressApp(Decompress())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postEncoded(app, tt.encoding, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.want != "" && w.Body.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDecompress_MaxSize(t *testing.T) {
+	// A small gzip body that expands well past the limit
+	bomb := gzipBytes(t, []byte(`{"name":"`+strings.Repeat("a", 1<<20)+`"}`))
+	app := newDecompressApp(DecompressWithConfig(DecompressConfig{MaxSize: 1024}))
+
+	w := postEncoded(app, "gzip", bomb)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+	if w := postEncoded(app, "gzip", gzipBytes(t, []byte(`{"name":"small"}`))); w.Code != http.StatusOK {
+		t.Errorf("expected bodies under the limit to pass, got %d", w.Code)
+	}
+}
+
+func TestDecompress_CustomDecoder(t *testing.T) {
+	app := newDecompressApp(DecompressWithConfig(DecompressConfig{
+		Decoders: []DecompressDecoder{{
+			Encoding: "x-upper",
+			NewReader: func(r io.Reader) (io.ReadCloser, error) {
+				data, err := io.ReadAll(r)
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(strings.NewReader(strings.ToLower(string(data)))), nil
+			},
+		}},
+	}))
+
+	if w := postEncoded(app, "x-upper", []byte(`{"NAME":"NEXO"}`)); w.Body.String() != "nexo|" {
+		t.Errorf("expected the custom decoder to be used, got %d %q", w.Code, w.Body.String())
+	}
+	if w := postEncoded(app, "gzip", gzipBytes(t, []byte(`{}`))); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected gzip to be unsupported, got %d", w.Code)
+	}
+}
+
+func TestDecompress_Skip(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(DecompressWithConfig(DecompressConfig{
+		Skip: func(c *Context) bool { return true },
+	}))
+	app.Post("/", func(c *Context) error {
+		data, _ := io.ReadAll(c.Request.Body)
+		return c.String(http.StatusOK, c.Header("Content-Encoding")+"|"+string(data))
+	})
+	app.Mount()
+
+	if w := postEncoded(app, "br", []byte("raw")); w.Body.String() != "br|raw" {
+		t.Errorf("expected the body to be left as sent, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,261 @@
+package nexo
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DevDashboardPath is where the dev dashboard is served. The same data is
+// available as JSON at DevDashboardPath + "/api".
+const DevDashboardPath = "/_nexo"
+
+// devDashboardRequests is how many recent requests the dashboard shows.
+const devDashboardRequests = 100
+
+// devModeFromEnv reports whether the app runs in development, e.g. under
+// nexo dev, which enables the dev dashboard by default.
+func devModeFromEnv() bool {
+	return os.Getenv(LiveReloadPortEnv) != "" ||
+		os.Getenv(DevProxyEnv) != "" ||
+		os.Getenv("NEXO_DEV") == "true"
+}
+
+// dashboardRequest is a request shown on the dev dashboard.
+type dashboardRequest struct {
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method"`
+	Path     string        `json:"path"`
+	Status   int           `json:"status"`
+	Duration time.Duration `json:"duration"`
+}
+
+// requestLog keeps the most recent requests in a ring buffer.
+type requestLog struct {
+	mu      sync.Mutex
+	entries []dashboardRequest
+	next    int
+}
+
+func newRequestLog(size int) *requestLog {
+	return &requestLog{entries: make([]dashboardRequest, 0, size)}
+}
+
+// add records a request, replacing the oldest once the log is full.
+func (l *requestLog) add(req dashboardRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, req)
+		return
+	}
+	l.entries[l.next] = req
+	l.next = (l.next + 1) % len(l.entries)
+}
+
+// recent returns the recorded requests, newest first.
+func (l *requestLog) recent() []dashboardRequest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]dashboardRequest, 0, len(l.entries))
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		out = append(out, l.entries[(l.next+i)%len(l.entries)])
+	}
+	return out
+}
+
+// dashboardRoute is a route shown on the dev dashboard.
+type dashboardRoute struct {
+	Method     string   `json:"method"`
+	Pattern    string   `json:"pattern"`
+	FilePath   string   `json:"file,omitempty"`
+	Priority   int      `json:"priority"`
+	Middleware []string `json:"middleware"`
+}
+
+// dashboardMiddleware is a middleware.go registration shown on the dev dashboard.
+type dashboardMiddleware struct {
+	Path  string `json:"path"`
+	Scope string `json:"scope,omitempty"`
+	Count int    `json:"count"`
+}
+
+// dashboardData is everything the dev dashboard shows.
+type dashboardData struct {
+	Routes           []dashboardRoute      `json:"routes"`
+	Middleware       []dashboardMiddleware `json:"middleware"`
+	GlobalMiddleware int                   `json:"global_middleware"`
+	Proxy            bool                  `json:"proxy"`
+	ProxyMatchers    []string              `json:"proxy_matchers"`
+	Requests         []dashboardRequest    `json:"requests"`
+	Config           Config                `json:"config"`
+}
+
+// dashboardData collects the dev dashboard's data.
+func (a *App) dashboardData() dashboardData {
+	rt := a.routeTree
+	data := dashboardData{
+		Routes:           make([]dashboardRoute, 0, len(rt.routes)),
+		GlobalMiddleware: len(a.middlewares),
+		Proxy:            rt.HasProxy(),
+		ProxyMatchers:    []string{},
+		Requests:         a.devRequests.recent(),
+		Config:           *a.config,
+	}
+	data.Config.Dev.DebugToken = ""
+
+	for _, route := range rt.Routes() {
+		paths := rt.MiddlewarePaths(route.Pattern, route.Scope)
+		if paths == nil {
+			paths = []string{}
+		}
+		data.Routes = append(data.Routes, dashboardRoute{
+			Method:     route.Method,
+			Pattern:    route.Pattern,
+			FilePath:   route.FilePath,
+			Priority:   route.Priority,
+			Middleware: paths,
+		})
+	}
+
+	for key, mws := range rt.middlewares {
+		data.Middleware = append(data.Middleware, dashboardMiddleware{
+			Path:  middlewarePath(key),
+			Scope: rt.middlewareScopes[key],
+			Count: len(mws),
+		})
+	}
+	sort.Slice(data.Middleware, func(i, j int) bool {
+		if data.Middleware[i].Path != data.Middleware[j].Path {
+			return data.Middleware[i].Path < data.Middleware[j].Path
+		}
+		return data.Middleware[i].Scope < data.Middleware[j].Scope
+	})
+
+	if cfg := rt.ProxyConfiguration(); cfg != nil {
+		data.ProxyMatchers = append(data.ProxyMatchers, cfg.Matcher...)
+	}
+
+	return data
+}
+
+// mountDevDashboard serves the dev dashboard at DevDashboardPath.
+func (a *App) mountDevDashboard() {
+	a.router.Get(DevDashboardPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := devDashboardTemplate.Execute(w, a.dashboardData()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	a.router.Get(DevDashboardPath+"/api", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(a.dashboardData())
+	})
+	if a.latencyStats != nil {
+		a.router.Get(DevDashboardPath+"/latency", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			_ = a.latencyStats.write(w)
+		})
+	}
+}
+
+// recordRequest adds a request to the dev dashboard's recent requests and
+// the latency summary.
+func (a *App) recordRequest(r *http.Request, rw *responseWriter, start time.Time) {
+	if strings.HasPrefix(r.URL.Path, DevDashboardPath) {
+		return
+	}
+	duration := time.Since(start)
+	if a.devRequests != nil {
+		a.devRequests.add(dashboardRequest{
+			Time:     start,
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Status:   rw.Status(),
+			Duration: duration,
+		})
+	}
+	if a.latencyStats != nil {
+		key := routeKey{method: r.Method}
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			key.route = rctx.RoutePattern()
+		}
+		a.latencyStats.observe(key, duration, rw.Status())
+	}
+}
+
+var devDashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+	"ms": func(d time.Duration) string {
+		return d.Round(10 * time.Microsecond).String()
+	},
+	"clock": func(t time.Time) string {
+		return t.Format("15:04:05")
+	},
+	"statusClass": func(status int) int {
+		return status / 100
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Nexo dev dashboard</title>
+<style>
+body{font:14px/1.5 system-ui,sans-serif;margin:2rem;color:#1f2937}
+h1{font-size:1.4rem}h2{font-size:1.1rem;margin-top:2rem}
+table{border-collapse:collapse;width:100%}
+th,td{text-align:left;padding:.3rem .6rem;border-bottom:1px solid #e5e7eb;vertical-align:top}
+th{background:#f9fafb}code,pre{font-family:ui-monospace,monospace;font-size:13px}
+pre{background:#f9fafb;padding:1rem;overflow:auto}
+.m{font-weight:600}.s2{color:#15803d}.s3{color:#1d4ed8}.s4{color:#b45309}.s5{color:#b91c1c}
+.muted{color:#6b7280}
+</style>
+</head>
+<body>
+<h1>Nexo dev dashboard</h1>
+<p class="muted">Reload to refresh. JSON: <a href="` + DevDashboardPath + `/api">` + DevDashboardPath + `/api</a></p>
+
+<h2>Routes ({{len .Routes}})</h2>
+<table>
+<tr><th>Method</th><th>Pattern</th><th>Middleware</th><th>File</th><th>Priority</th></tr>
+{{range .Routes}}<tr><td class="m">{{.Method}}</td><td><code>{{.Pattern}}</code></td><td>{{if $.GlobalMiddleware}}<code>global ({{$.GlobalMiddleware}})</code> {{end}}{{range .Middleware}}<code>{{if .}}{{.}}{{else}}/{{end}}</code> {{end}}</td><td class="muted">{{.FilePath}}</td><td>{{.Priority}}</td></tr>
+{{else}}<tr><td colspan="5" class="muted">No routes registered</td></tr>
+{{end}}</table>
+
+<h2>Middleware</h2>
+<table>
+<tr><th>Path</th><th>Scope</th><th>Functions</th></tr>
+<tr><td><code>global (app.Use)</code></td><td></td><td>{{.GlobalMiddleware}}</td></tr>
+{{range .Middleware}}<tr><td><code>{{if .Path}}{{.Path}}{{else}}/{{end}}</code></td><td class="muted">{{.Scope}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+
+<h2>Proxy</h2>
+{{if .Proxy}}<p>Runs on {{if .ProxyMatchers}}{{range .ProxyMatchers}}<code>{{.}}</code> {{end}}{{else}}all paths{{end}}</p>{{else}}<p class="muted">No proxy configured</p>{{end}}
+
+<h2>Recent Requests</h2>
+<table>
+<tr><th>Time</th><th>Method</th><th>Path</th><th>Status</th><th>Duration</th></tr>
+{{range .Requests}}<tr><td class="muted">{{clock .Time}}</td><td class="m">{{.Method}}</td><td><code>{{.Path}}</code></td><td class="s{{statusClass .Status}}">{{.Status}}</td><td>{{ms .Duration}}</td></tr>
+{{else}}<tr><td colspan="5" class="muted">No requests yet</td></tr>
+{{end}}</table>
+
+<h2>Config</h2>
+<pre>{{json .Config}}</pre>
+</body>
+</html>
+`))
@@ -0,0 +1,103 @@
+package nexo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDevDashboard_DisabledByDefault(t *testing.T) {
+	t.Setenv(LiveReloadPortEnv, "")
+	t.Setenv(DevProxyEnv, "")
+	t.Setenv("NEXO_DEV", "")
+
+	app := New()
+	app.DisableLogger()
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DevDashboardPath, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestDevDashboard_EnabledByEnv(t *testing.T) {
+	t.Setenv("NEXO_DEV", "true")
+
+	if app := New(); !app.devDashboard {
+		t.Error("expected the dev dashboard to be enabled under NEXO_DEV")
+	}
+	if app := New(WithDevDashboard(false)); app.devDashboard {
+		t.Error("expected WithDevDashboard(false) to win over NEXO_DEV")
+	}
+}
+
+func TestDevDashboard(t *testing.T) {
+	app := New(WithDevDashboard(true), WithDebugToken("s3cret"))
+	app.DisableLogger()
+	app.Use(func(next HandlerFunc) HandlerFunc { return next })
+	app.RouteTree().AddMiddleware("/api", "", func(next HandlerFunc) HandlerFunc { return next })
+	app.Get("/api/users/{id}", func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users/7", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DevDashboardPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"/api/users/{id}", "/api/users/7", `class="s2"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "s3cret") {
+		t.Error("expected the debug token to be redacted")
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DevDashboardPath+"/api", nil))
+	var data dashboardData
+	if err := json.Unmarshal(w.Body.Bytes(), &data); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if data.GlobalMiddleware != 1 {
+		t.Errorf("expected 1 global middleware, got %d", data.GlobalMiddleware)
+	}
+	if len(data.Routes) != 1 || strings.Join(data.Routes[0].Middleware, ",") != "/api" {
+		t.Errorf("unexpected routes %+v", data.Routes)
+	}
+	// Dashboard requests aren't recorded
+	if len(data.Requests) != 1 || data.Requests[0].Path != "/api/users/7" || data.Requests[0].Status != http.StatusOK {
+		t.Errorf("unexpected requests %+v", data.Requests)
+	}
+	if data.Config.Dev.DebugToken != "" {
+		t.Error("expected the debug token to be redacted")
+	}
+}
+
+func TestRequestLog(t *testing.T) {
+	l := newRequestLog(3)
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/e"} {
+		l.add(dashboardRequest{Path: path})
+	}
+
+	var got []string
+	for _, req := range l.recent() {
+		got = append(got, req.Path)
+	}
+	if strings.Join(got, ",") != "/e,/d,/c" {
+		t.Errorf("recent() = %v, want newest first /e,/d,/c", got)
+	}
+}
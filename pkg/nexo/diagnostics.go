@@ -0,0 +1,137 @@
+package nexo
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goscanner "go/scanner"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// Rules of the diagnostics the scanner reports. Route linting (see
+// pkg/scanner) adds its own.
+const (
+	// RuleInvalidSignature flags functions that aren't registered because
+	// of their signature.
+	RuleInvalidSignature = "invalid-signature"
+	// RuleMisnamed flags functions that aren't registered because their
+	// name is off, e.g. GET instead of Get.
+	RuleMisnamed = "misnamed"
+	// RuleParseError flags routing files that can't be read or parsed.
+	RuleParseError = "parse-error"
+)
+
+// Diagnostic severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in the app directory, such as a handler
+// the scanner skipped because of its signature or a misspelled name like
+// GET, which would otherwise show up as a 404.
+type Diagnostic struct {
+	Rule     string `json:"rule"`     // e.g. RuleMisnamed
+	Severity string `json:"severity"` // SeverityError or SeverityWarning
+	Pattern  string `json:"pattern,omitempty"`
+	FilePath string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Symbol   string `json:"symbol,omitempty"` // The function, e.g. "GET"
+	Message  string `json:"message"`
+}
+
+// String formats the diagnostic as "file:line: symbol: message".
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	b.WriteString(d.FilePath)
+	if d.Line > 0 {
+		fmt.Fprintf(&b, ":%d", d.Line)
+	}
+	if d.Symbol != "" {
+		b.WriteString(": " + d.Symbol)
+	}
+	b.WriteString(": " + d.Message)
+	return b.String()
+}
+
+// Diagnose returns the diagnostics Scan reports for a parsed routing file:
+// a route.go or route_<method>.go, ws.go, sse.go, action.go or
+// middleware.go, told apart by its name. Tools that parse the files
+// themselves, like pkg/scanner, use it to report the same problems without
+// parsing them again. The diagnostics have no Pattern.
+func Diagnose(fset *token.FileSet, file *ast.File) []Diagnostic {
+	s := &Scanner{fset: fset}
+	filePath := fset.Position(file.Package).Filename
+	name := filepath.Base(filePath)
+	switch {
+	case IsRouteFile(name):
+		var diags []Diagnostic
+		s.routeHandlers([]*ast.File{file}, func(d Diagnostic) {
+			diags = append(diags, d)
+		})
+		return diags
+	case name == "middleware.go":
+		return s.middlewareDecls(file).Diagnostics
+	case name == "action.go":
+		_, diags := s.actionHandlers(file, filePath)
+		return diags
+	}
+	if cf, ok := connFiles[name]; ok {
+		_, diags := s.connHandler(file, filePath, cf)
+		return diags
+	}
+	return nil
+}
+
+// diagnostic reports a function the scanner skipped.
+func (s *Scanner) diagnostic(fn *ast.FuncDecl, rule, message string) Diagnostic {
+	pos := s.fset.Position(fn.Name.Pos())
+	return Diagnostic{
+		Rule:     rule,
+		Severity: SeverityError,
+		FilePath: pos.Filename,
+		Line:     pos.Line,
+		Symbol:   fn.Name.Name,
+		Message:  message,
+	}
+}
+
+// errorDiagnostic reports a file the scanner couldn't read or parse.
+func errorDiagnostic(filePath string, err error) Diagnostic {
+	d := Diagnostic{Rule: RuleParseError, Severity: SeverityError, FilePath: filePath, Message: err.Error()}
+	var list goscanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		d.FilePath, d.Line, d.Message = list[0].Pos.Filename, list[0].Pos.Line, list[0].Msg
+	}
+	return d
+}
+
+// withPattern returns a copy of diags with their URL pattern set, leaving
+// the cached ones as they are.
+func withPattern(diags []Diagnostic, pattern string) []Diagnostic {
+	if len(diags) == 0 {
+		return nil
+	}
+	out := make([]Diagnostic, len(diags))
+	for i, d := range diags {
+		d.Pattern = pattern
+		out[i] = d
+	}
+	return out
+}
+
+// handlerNameFold returns the handler name that name matches ignoring
+// case, e.g. Get for GET, or "" if there is none.
+func handlerNameFold(name string) string {
+	for handler := range httpMethods {
+		if strings.EqualFold(name, handler) {
+			return handler
+		}
+	}
+	if strings.EqualFold(name, anyHandler) {
+		return anyHandler
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+package nexo
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanner_ScanRouteInfoDiagnostics(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	files := map[string]string{
+		"api/users/route.go": `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func GET(c *nexo.Context) error {
+	return nil
+}
+
+func Post() error {
+	return nil
+}
+
+func get(id string) string {
+	return id
+}
+
+func Delete(c *nexo.Context) error {
+	return nil
+}
+`,
+		"api/middleware.go": `package api
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Middleware(next nexo.HandlerFunc) nexo.HandlerFunc {
+	return next
+}
+`,
+		"admin/middleware.go": `package admin
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func MIDDLEWARE() nexo.MiddlewareFunc {
+	return nil
+}
+`,
+		"chat/ws.go": `package chat
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func SOCKET(c *nexo.Context, ws *nexo.WebSocketConn) error {
+	return nil
+}
+`,
+		"contact/action.go": `package contact
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func ACTIONSend(c *nexo.Context) error {
+	return nil
+}
+
+func ActionDelete(id string) error {
+	return nil
+}
+
+func Action(c *nexo.Context) error {
+	return nil
+}
+`,
+		"feed/sse.go": `package feed
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Stream(c *nexo.Context, w http.ResponseWriter) error {
+	return nil
+}
+`,
+		"live/ws.go": `package live
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Socket(c *nexo.Context) error {
+	return nil
+}
+`,
+		"broken/route.go": `package broken
+
+func Get(c *nexo.Context) error {
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(appDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, diags, err := NewScanner(appDir).ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+
+	path := func(name string) string { return filepath.Join(appDir, filepath.FromSlash(name)) }
+	want := []Diagnostic{
+		{Rule: RuleMisnamed, Severity: SeverityError, Pattern: "/admin", FilePath: path("admin/middleware.go"), Line: 5, Symbol: "MIDDLEWARE", Message: "not a middleware name, did you mean Middleware?"},
+		{Rule: RuleInvalidSignature, Severity: SeverityError, Pattern: "/api", FilePath: path("api/middleware.go"), Line: 5, Symbol: "Middleware", Message: "invalid signature, middleware must be func() nexo.MiddlewareFunc"},
+		{Rule: RuleMisnamed, Severity: SeverityError, Pattern: "/api/users", FilePath: path("api/users/route.go"), Line: 5, Symbol: "GET", Message: "not a handler name, did you mean Get?"},
+		{Rule: RuleInvalidSignature, Severity: SeverityError, Pattern: "/api/users", FilePath: path("api/users/route.go"), Line: 9, Symbol: "Post", Message: "invalid signature, handlers must be func(c *nexo.Context) error"},
+		{Rule: RuleParseError, Severity: SeverityError, FilePath: path("broken/route.go"), Line: 3, Message: "expected '}', found 'EOF'"},
+		{Rule: RuleMisnamed, Severity: SeverityError, Pattern: "/chat", FilePath: path("chat/ws.go"), Line: 5, Symbol: "SOCKET", Message: "not a socket name, did you mean Socket?"},
+		{Rule: RuleMisnamed, Severity: SeverityError, Pattern: "/contact", FilePath: path("contact/action.go"), Line: 5, Symbol: "ACTIONSend", Message: "not an action name, did you mean ActionSend?"},
+		{Rule: RuleInvalidSignature, Severity: SeverityError, Pattern: "/contact", FilePath: path("contact/action.go"), Line: 9, Symbol: "ActionDelete", Message: "invalid signature, actions must be func(c *nexo.Context) error"},
+		{Rule: RuleInvalidSignature, Severity: SeverityError, Pattern: "/feed", FilePath: path("feed/sse.go"), Line: 5, Symbol: "Stream", Message: "invalid signature, streams must be func(c *nexo.Context, sse *nexo.SSEWriter) error"},
+		{Rule: RuleInvalidSignature, Severity: SeverityError, Pattern: "/live", FilePath: path("live/ws.go"), Line: 5, Symbol: "Socket", Message: "invalid signature, sockets must be func(c *nexo.Context, ws *nexo.WebSocketConn) error"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("ScanRouteInfo() diagnostics = %v, want %v", diags, want)
+	}
+	for i := range want {
+		if diags[i] != want[i] {
+			t.Errorf("diagnostic %d = %+v, want %+v", i, diags[i], want[i])
+		}
+	}
+}
+
+func TestScanner_ScanDiagnostics(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	path := filepath.Join(appDir, "api", "users", "route.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	src := "package users\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc GET(c *nexo.Context) error { return nil }\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewRouteTree()
+	diags, err := NewScanner(appDir).Scan(tree)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	want := Diagnostic{Rule: RuleMisnamed, Severity: SeverityError, Pattern: "/api/users", FilePath: path, Line: 5, Symbol: "GET", Message: "not a handler name, did you mean Get?"}
+	if len(diags) != 1 || diags[0] != want {
+		t.Errorf("Scan() diagnostics = %+v, want [%+v]", diags, want)
+	}
+	if len(tree.Routes()) != 0 {
+		t.Errorf("expected GET not to be registered, got %+v", tree.Routes())
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want []Diagnostic
+	}{
+		"app/api/route_get.go": {
+			src:  "package api\n\nfunc GET(c *nexo.Context) error { return nil }\n",
+			want: []Diagnostic{{Rule: RuleMisnamed, Severity: SeverityError, FilePath: "app/api/route_get.go", Line: 3, Symbol: "GET", Message: "not a handler name, did you mean Get?"}},
+		},
+		"app/chat/sse.go": {
+			src:  "package chat\n\nfunc Stream(c *nexo.Context) error { return nil }\n",
+			want: []Diagnostic{{Rule: RuleInvalidSignature, Severity: SeverityError, FilePath: "app/chat/sse.go", Line: 3, Symbol: "Stream", Message: "invalid signature, streams must be func(c *nexo.Context, sse *nexo.SSEWriter) error"}},
+		},
+		"app/helpers.go": {
+			src: "package app\n\nfunc GET() {}\n",
+		},
+	}
+	for name, tt := range tests {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, name, tt.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := Diagnose(fset, file)
+		if len(got) != len(tt.want) {
+			t.Errorf("Diagnose(%s) = %+v, want %+v", name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Diagnose(%s)[%d] = %+v, want %+v", name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	tests := []struct {
+		d    Diagnostic
+		want string
+	}{
+		{Diagnostic{FilePath: "app/route.go", Line: 5, Symbol: "GET", Message: "not a handler name, did you mean Get?"}, "app/route.go:5: GET: not a handler name, did you mean Get?"},
+		{Diagnostic{FilePath: "app/route.go", Message: "permission denied"}, "app/route.go: permission denied"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
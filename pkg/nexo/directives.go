@@ -0,0 +1,68 @@
+package nexo
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// RouteDirectives document a route handler with //nexo: directives in its
+// doc comment. nexo routes lists them and the OpenAPI spec includes them:
+//
+//	// Get returns the user with the given ID.
+//	//
+//	//nexo:summary Get a user
+//	//nexo:tags users admin
+//	//nexo:auth bearer
+//	//nexo:deprecated
+//	func Get(c *nexo.Context) error {
+//
+// Like other Go directives, they have no space after the slashes and aren't
+// part of the comment's text. The directives of an Any handler apply to
+// every method it handles. Unknown directives are ignored.
+type RouteDirectives struct {
+	// Summary is a short description of the route (//nexo:summary).
+	Summary string
+
+	// Tags group the route in the OpenAPI spec (//nexo:tags, separated by
+	// spaces).
+	Tags []string
+
+	// Auth is the authentication the route requires, "bearer" or "basic"
+	// (//nexo:auth).
+	Auth string
+
+	// Deprecated marks the route as deprecated (//nexo:deprecated).
+	Deprecated bool
+}
+
+// directivePrefix starts the directives in handler doc comments.
+const directivePrefix = "//nexo:"
+
+// parseDirectives returns the directives in a handler's doc comment.
+func parseDirectives(doc *ast.CommentGroup) RouteDirectives {
+	var d RouteDirectives
+	if doc == nil {
+		return d
+	}
+
+	for _, comment := range doc.List {
+		directive, ok := strings.CutPrefix(comment.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+		name, arg, _ := strings.Cut(directive, " ")
+		arg = strings.TrimSpace(arg)
+
+		switch name {
+		case "summary":
+			d.Summary = arg
+		case "tags":
+			d.Tags = append(d.Tags, strings.Fields(arg)...)
+		case "auth":
+			d.Auth = arg
+		case "deprecated":
+			d.Deprecated = true
+		}
+	}
+	return d
+}
@@ -0,0 +1,95 @@
+package nexo
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseDirectives(t *testing.T) {
+	src := `package users
+
+// Get returns a user.
+//
+//nexo:summary Get a user
+//nexo:tags users  admin
+//nexo:tags internal
+//nexo:auth bearer
+//nexo:deprecated
+//nexo:unknown value
+// nexo:summary Not a directive
+func Get() {}
+
+func Post() {}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "route.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := parseDirectives(findFunc(file, "Get").Doc)
+	if d.Summary != "Get a user" {
+		t.Errorf("Summary = %q, want %q", d.Summary, "Get a user")
+	}
+	if want := []string{"users", "admin", "internal"}; !slices.Equal(d.Tags, want) {
+		t.Errorf("Tags = %v, want %v", d.Tags, want)
+	}
+	if d.Auth != "bearer" {
+		t.Errorf("Auth = %q, want bearer", d.Auth)
+	}
+	if !d.Deprecated {
+		t.Error("expected Deprecated")
+	}
+
+	if d := parseDirectives(findFunc(file, "Post").Doc); d.Summary != "" || d.Tags != nil || d.Auth != "" || d.Deprecated {
+		t.Errorf("expected no directives without a doc comment, got %+v", d)
+	}
+}
+
+func TestScanner_ScanRouteInfo_Directives(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	hooksDir := filepath.Join(appDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	routeContent := `package hooks
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+//nexo:summary Receive a webhook
+//nexo:auth basic
+func Any(c *nexo.Context) error {
+	return nil
+}
+
+//nexo:summary Check the receiver
+func Get(c *nexo.Context) error {
+	return nil
+}
+`
+	if err := os.WriteFile(filepath.Join(hooksDir, "route.go"), []byte(routeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, _, err := NewScanner(appDir).ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	if len(routes) != 7 {
+		t.Fatalf("Expected 7 routes, got %d", len(routes))
+	}
+	for _, r := range routes {
+		want := RouteDirectives{Summary: "Receive a webhook", Auth: "basic"}
+		if r.Method == "GET" {
+			want = RouteDirectives{Summary: "Check the receiver"}
+		}
+		if r.Directives.Summary != want.Summary || r.Directives.Auth != want.Auth {
+			t.Errorf("%s: Directives = %+v, want %+v", r.Method, r.Directives, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package nexo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -14,6 +15,8 @@ var (
 	ErrInvalidHandler   = errors.New("invalid handler signature")
 	ErrScanFailed       = errors.New("failed to scan routes")
 	ErrNoAppDir         = errors.New("app directory not found")
+	ErrNoCookieSecret   = errors.New("no cookie secret configured (use WithCookieSecret)")
+	ErrInvalidCookie    = errors.New("invalid cookie")
 )
 
 // HTTPError represents an HTTP error with a status code and message.
@@ -63,6 +66,19 @@ var (
 	ErrInternalServerError = NewHTTPError(http.StatusInternalServerError, "internal server error")
 )
 
+// ErrorHandler handles an error returned by a handler or middleware by
+// writing a response. Set one with App.OnError. An error it returns is
+// written by DefaultErrorHandler instead.
+type ErrorHandler func(c *Context, err error) error
+
+// errorHandlerKey is the request context key for the app's ErrorHandler.
+type errorHandlerKey struct{}
+
+// withErrorHandler returns r with the app's ErrorHandler in its context.
+func withErrorHandler(r *http.Request, h ErrorHandler) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), errorHandlerKey{}, h))
+}
+
 // WrapError wraps an error with additional context.
 func WrapError(err error, message string) error {
 	if err == nil {
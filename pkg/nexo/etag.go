@@ -0,0 +1,219 @@
+package nexo
+
+import (
+	"bufio"
+	"bytes"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ETagConfig holds configuration for the ETag middleware.
+type ETagConfig struct {
+	// Weak sends weak ETags (W/"..."), for responses that are equivalent
+	// but not byte-for-byte identical. Default is strong ETags.
+	Weak bool
+
+	// Skip returns true for requests that shouldn't get an ETag.
+	Skip func(c *Context) bool
+}
+
+// etagSkipped is set by NoETag for the routes that opt out.
+var etagSkipped = NewKey[bool]("nexo.etag.skip")
+
+// ETag returns a middleware that adds a strong ETag to successful GET and
+// HEAD responses and answers conditional requests: a matching
+// If-None-Match, or an If-Modified-Since no older than the response's
+// Last-Modified header, gets a 304 Not Modified with an empty body.
+//
+// Responses are buffered to hash them. Flushed responses, responses with
+// Cache-Control: no-store and routes using NoETag are sent unchanged. An
+// ETag set by the handler is kept.
+func ETag() MiddlewareFunc {
+	return ETagWithConfig(ETagConfig{})
+}
+
+// ETagWithConfig returns an ETag middleware with custom configuration.
+func ETagWithConfig(config ETagConfig) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if c.Method() != http.MethodGet && c.Method() != http.MethodHead {
+				return next(c)
+			}
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			res := c.Response
+			defer func() { c.Response = res }()
+			ew := &etagWriter{ResponseWriter: res, status: http.StatusOK}
+			c.Response = ew
+
+			err := next(c)
+			skip, _ := etagSkipped.Get(c)
+			ew.finish(c.Request, config.Weak, skip)
+			return err
+		}
+	}
+}
+
+// NoETag returns a middleware that opts routes out of the ETag middleware,
+// e.g. in a middleware.go file or on a route group.
+//
+// Example:
+//
+//	live := app.Group("/live")
+//	live.Use(nexo.NoETag())
+func NoETag() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			etagSkipped.Set(c, true)
+			return next(c)
+		}
+	}
+}
+
+// etagWriter buffers successful responses so they can be hashed. Other
+// statuses and flushed responses pass through.
+type etagWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	passthrough bool
+	buf         bytes.Buffer
+}
+
+// WriteHeader records the status. Only 200 responses are buffered.
+func (ew *etagWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 {
+		ew.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if ew.wroteHeader {
+		return
+	}
+	ew.wroteHeader = true
+	ew.status = code
+	if code != http.StatusOK {
+		ew.passthrough = true
+		ew.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write buffers the body of 200 responses.
+func (ew *etagWriter) Write(b []byte) (int, error) {
+	if !ew.wroteHeader {
+		ew.WriteHeader(http.StatusOK)
+	}
+	if ew.passthrough {
+		return ew.ResponseWriter.Write(b)
+	}
+	return ew.buf.Write(b)
+}
+
+// Flush implements http.Flusher. Flushed responses are streamed without
+// an ETag.
+func (ew *etagWriter) Flush() {
+	if !ew.wroteHeader {
+		ew.WriteHeader(http.StatusOK)
+	}
+	if !ew.passthrough {
+		ew.passthrough = true
+		ew.ResponseWriter.WriteHeader(ew.status)
+		_, _ = ew.ResponseWriter.Write(ew.buf.Bytes())
+		ew.buf.Reset()
+	}
+	if flusher, ok := ew.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface for WebSocket support.
+func (ew *etagWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := ew.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (ew *etagWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+// finish sends a buffered response, or a 304 if the request's
+// preconditions match it.
+func (ew *etagWriter) finish(r *http.Request, weak, skip bool) {
+	if ew.passthrough || !ew.wroteHeader {
+		return
+	}
+
+	h := ew.Header()
+	if !skip && ew.buf.Len() > 0 && !strings.Contains(h.Get("Cache-Control"), "no-store") {
+		etag := h.Get("ETag")
+		if etag == "" {
+			etag = computeETag(ew.buf.Bytes(), weak)
+			h.Set("ETag", etag)
+		}
+		if notModified(r, etag, h.Get("Last-Modified")) {
+			h.Del("Content-Type")
+			h.Del("Content-Length")
+			ew.ResponseWriter.WriteHeader(http.StatusNotModified)
+			return
+		}
+		h.Set("Content-Length", strconv.Itoa(ew.buf.Len()))
+	}
+
+	ew.ResponseWriter.WriteHeader(ew.status)
+	_, _ = ew.ResponseWriter.Write(ew.buf.Bytes())
+}
+
+// computeETag returns an ETag for body from its length and FNV-1a hash.
+func computeETag(body []byte, weak bool) string {
+	h := fnv.New64a()
+	_, _ = h.Write(body)
+	etag := `"` + strconv.FormatInt(int64(len(body)), 16) + "-" + strconv.FormatUint(h.Sum64(), 16) + `"`
+	if weak {
+		return "W/" + etag
+	}
+	return etag
+}
+
+// notModified reports whether a response with etag and lastModified
+// satisfies the request's If-None-Match or, without one, its
+// If-Modified-Since header.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using
+// the weak comparison.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
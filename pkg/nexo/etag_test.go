@@ -0,0 +1,167 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	h := ETag()(func(c *Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"name": "nexo"})
+	})
+
+	w := httptest.NewRecorder()
+	if err := h(NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))); err != nil {
+		t.Fatal(err)
+	}
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || etag[0] != '"' {
+		t.Fatalf("expected a 200 with a strong ETag, got %d %q", w.Code, etag)
+	}
+	if w.Body.String() != "{\"name\":\"nexo\"}\n" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+	if w.Header().Get("Content-Length") != "16" {
+		t.Errorf("Content-Length = %q, want 16", w.Header().Get("Content-Length"))
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{"match", etag, http.StatusNotModified},
+		{"weak match", "W/" + etag, http.StatusNotModified},
+		{"list", `"other", ` + etag, http.StatusNotModified},
+		{"any", "*", http.StatusNotModified},
+		{"mismatch", `"other"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			w := httptest.NewRecorder()
+			if err := h(NewContext(w, req)); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, w.Code)
+			}
+			if tt.status == http.StatusNotModified {
+				if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+					t.Errorf("expected an empty 304, got %q (Content-Type %q)", w.Body.String(), w.Header().Get("Content-Type"))
+				}
+				if w.Header().Get("ETag") != etag {
+					t.Errorf("expected the ETag on the 304, got %q", w.Header().Get("ETag"))
+				}
+			}
+		})
+	}
+}
+
+func TestETag_IfModifiedSince(t *testing.T) {
+	modified := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	h := ETag()(func(c *Context) error {
+		c.SetHeader("Last-Modified", modified.Format(http.TimeFormat))
+		return c.String(http.StatusOK, "report")
+	})
+
+	tests := map[string]int{
+		modified.Format(http.TimeFormat):                 http.StatusNotModified,
+		modified.Add(time.Hour).Format(http.TimeFormat):  http.StatusNotModified,
+		modified.Add(-time.Hour).Format(http.TimeFormat): http.StatusOK,
+		"not a date": http.StatusOK,
+	}
+	for since, status := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-Modified-Since", since)
+		w := httptest.NewRecorder()
+		if err := h(NewContext(w, req)); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != status {
+			t.Errorf("If-Modified-Since %q: expected %d, got %d", since, status, w.Code)
+		}
+	}
+}
+
+func TestETag_Passthrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		mw      []MiddlewareFunc
+		handler HandlerFunc
+	}{
+		{"post", http.MethodPost, nil, func(c *Context) error { return c.String(http.StatusOK, "created") }},
+		{"error status", http.MethodGet, nil, func(c *Context) error { return c.String(http.StatusNotFound, "missing") }},
+		{"no-store", http.MethodGet, nil, func(c *Context) error {
+			c.SetHeader("Cache-Control", "no-store")
+			return c.String(http.StatusOK, "secret")
+		}},
+		{"opted out", http.MethodGet, []MiddlewareFunc{NoETag()}, func(c *Context) error { return c.String(http.StatusOK, "live") }},
+		{"flushed", http.MethodGet, nil, func(c *Context) error {
+			_, _ = c.Response.Write([]byte("chunk"))
+			c.Response.(http.Flusher).Flush()
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.handler
+			for i := len(tt.mw) - 1; i >= 0; i-- {
+				h = tt.mw[i](h)
+			}
+			h = ETag()(h)
+
+			w := httptest.NewRecorder()
+			if err := h(NewContext(w, httptest.NewRequest(tt.method, "/", nil))); err != nil {
+				t.Fatal(err)
+			}
+			if got := w.Header().Get("ETag"); got != "" {
+				t.Errorf("expected no ETag, got %q", got)
+			}
+			if w.Body.Len() == 0 {
+				t.Error("expected the body to be sent")
+			}
+		})
+	}
+}
+
+func TestETag_WeakAndHandlerETag(t *testing.T) {
+	h := ETagWithConfig(ETagConfig{Weak: true})(func(c *Context) error {
+		return c.String(http.StatusOK, "hello")
+	})
+	w := httptest.NewRecorder()
+	_ = h(NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)))
+	if etag := w.Header().Get("ETag"); len(etag) < 3 || etag[:3] != `W/"` {
+		t.Errorf("expected a weak ETag, got %q", etag)
+	}
+
+	h = ETag()(func(c *Context) error {
+		c.SetHeader("ETag", `"v42"`)
+		return c.String(http.StatusOK, "hello")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"v42"`)
+	w = httptest.NewRecorder()
+	_ = h(NewContext(w, req))
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected the handler's ETag to be honored, got %d", w.Code)
+	}
+}
+
+func TestComputeETag(t *testing.T) {
+	a := computeETag([]byte("hello"), false)
+	if a != computeETag([]byte("hello"), false) {
+		t.Error("expected the ETag to be deterministic")
+	}
+	if a == computeETag([]byte("hellp"), false) {
+		t.Error("expected different bodies to get different ETags")
+	}
+	if computeETag([]byte("hello"), true) != "W/"+a {
+		t.Error("expected the weak ETag to wrap the strong one")
+	}
+}
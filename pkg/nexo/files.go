@@ -0,0 +1,108 @@
+package nexo
+
+import (
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// File sends the file at path. It uses http.ServeContent, so Range,
+// If-Modified-Since and If-None-Match requests are handled, and the
+// Content-Type is set from the file extension or content. Missing files
+// and directories return a 404 HTTPError.
+//
+// path is opened as given: clean and restrict paths built from request
+// data, or use the static file server instead.
+//
+// Example:
+//
+//	func Get(c *nexo.Context) error {
+//	    return c.File("reports/latest.pdf")
+//	}
+func (c *Context) File(path string) error {
+	return c.serveFile(path, "")
+}
+
+// Attachment sends the file at path like File, with a Content-Disposition
+// that makes browsers download it as filename. An empty filename uses the
+// file's base name.
+//
+// Example:
+//
+//	return c.Attachment("exports/2024.csv", "sales-2024.csv")
+func (c *Context) Attachment(path, filename string) error {
+	return c.serveFile(path, contentDisposition("attachment", path, filename))
+}
+
+// Inline sends the file at path like File, with a Content-Disposition that
+// asks browsers to display it, keeping the base name if it is saved.
+func (c *Context) Inline(path string) error {
+	return c.serveFile(path, contentDisposition("inline", path, ""))
+}
+
+// serveFile serves the file at path with an optional Content-Disposition.
+func (c *Context) serveFile(path, disposition string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileError(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fileError(err)
+	}
+	if info.IsDir() {
+		return NotFound("file not found")
+	}
+
+	if disposition != "" {
+		c.SetHeader("Content-Disposition", disposition)
+	}
+	w := &statusWriter{ResponseWriter: c.Response, status: http.StatusOK}
+	http.ServeContent(w, c.Request, info.Name(), info.ModTime(), f)
+	c.written = true
+	c.status = w.status
+	return nil
+}
+
+// fileError maps a file system error to an HTTPError.
+func fileError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return NewHTTPErrorWithCause(http.StatusNotFound, "file not found", err)
+	case errors.Is(err, fs.ErrPermission):
+		return NewHTTPErrorWithCause(http.StatusForbidden, "forbidden", err)
+	default:
+		return NewHTTPErrorWithCause(http.StatusInternalServerError, "failed to open file", err)
+	}
+}
+
+// contentDisposition returns a Content-Disposition header for the file at
+// path, named filename or the path's base name. Non-ASCII names are
+// encoded as RFC 2231 requires.
+func contentDisposition(disposition, path, filename string) string {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	return mime.FormatMediaType(disposition, map[string]string{"filename": filename})
+}
+
+// statusWriter records the status code written through it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,129 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestContext_File(t *testing.T) {
+	path := writeTestFile(t, "hello.txt", "hello, world")
+
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.File(path); err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+
+	if w.Code != http.StatusOK || w.Body.String() != "hello, world" {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "" {
+		t.Errorf("Content-Disposition = %q, want none", got)
+	}
+	if w.Header().Get("Last-Modified") == "" || w.Header().Get("Accept-Ranges") != "bytes" {
+		t.Errorf("headers = %v", w.Header())
+	}
+	if !c.Written() || c.StatusCode() != http.StatusOK {
+		t.Errorf("Written() = %v, StatusCode() = %d", c.Written(), c.StatusCode())
+	}
+}
+
+func TestContext_File_Range(t *testing.T) {
+	path := writeTestFile(t, "hello.txt", "hello, world")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Range", "bytes=7-")
+	w := httptest.NewRecorder()
+	c := NewContext(w, r)
+	if err := c.File(path); err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+
+	if w.Code != http.StatusPartialContent || w.Body.String() != "world" {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 7-11/12" {
+		t.Errorf("Content-Range = %q", got)
+	}
+	if c.StatusCode() != http.StatusPartialContent {
+		t.Errorf("StatusCode() = %d, want 206", c.StatusCode())
+	}
+}
+
+func TestContext_File_NotModified(t *testing.T) {
+	path := writeTestFile(t, "hello.txt", "hello, world")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	w := httptest.NewRecorder()
+	if err := NewContext(w, r).File(path); err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+}
+
+func TestContext_File_NotFound(t *testing.T) {
+	for name, path := range map[string]string{
+		"missing":   filepath.Join(t.TempDir(), "missing.txt"),
+		"directory": t.TempDir(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			err := c.File(path)
+			if httpErr, ok := IsHTTPError(err); !ok || httpErr.Code != http.StatusNotFound {
+				t.Errorf("File() error = %v, want a 404", err)
+			}
+			if c.Written() {
+				t.Error("Expected nothing to be written")
+			}
+		})
+	}
+}
+
+func TestContext_AttachmentAndInline(t *testing.T) {
+	path := writeTestFile(t, "report.pdf", "%PDF-1.4")
+
+	tests := []struct {
+		name string
+		call func(c *Context) error
+		want string
+	}{
+		{"attachment", func(c *Context) error { return c.Attachment(path, "sales 2024.pdf") }, `attachment; filename="sales 2024.pdf"`},
+		{"attachment default name", func(c *Context) error { return c.Attachment(path, "") }, "attachment; filename=report.pdf"},
+		{"attachment non-ascii", func(c *Context) error { return c.Attachment(path, "résumé.pdf") }, "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf"},
+		{"inline", func(c *Context) error { return c.Inline(path) }, "inline; filename=report.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := tt.call(NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.want {
+				t.Errorf("Content-Disposition = %q, want %q", got, tt.want)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/pdf" {
+				t.Errorf("Content-Type = %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package nexo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// HTMX response headers. See https://htmx.org/reference/#response_headers.
+const (
+	HXTriggerHeader            = "HX-Trigger"
+	HXTriggerAfterSwapHeader   = "HX-Trigger-After-Swap"
+	HXTriggerAfterSettleHeader = "HX-Trigger-After-Settle"
+	HXRedirectHeader           = "HX-Redirect"
+	HXLocationHeader           = "HX-Location"
+	HXPushURLHeader            = "HX-Push-Url"
+	HXReplaceURLHeader         = "HX-Replace-Url"
+	HXRefreshHeader            = "HX-Refresh"
+	HXRetargetHeader           = "HX-Retarget"
+	HXReswapHeader             = "HX-Reswap"
+	HXReselectHeader           = "HX-Reselect"
+)
+
+// hxEvents holds the events triggered with each trigger header.
+var hxEvents = NewKey[map[string]*hxEventList]("nexo.htmx.events")
+
+// hxEventList is an ordered list of events with optional details.
+type hxEventList struct {
+	names   []string
+	details map[string]any
+}
+
+// ---------- HTMX Request Headers ----------
+
+// VaryHTMX marks the response as varying on the HX-Request header, so
+// caches keep full pages and htmx fragments apart. Call it in handlers
+// that respond differently when IsHTMX is true.
+func (c *Context) VaryHTMX() {
+	addVary(c.Response.Header(), "HX-Request")
+}
+
+// IsHXBoosted reports whether the request came from an element using
+// hx-boost.
+func (c *Context) IsHXBoosted() bool {
+	return c.Request.Header.Get("HX-Boosted") == "true"
+}
+
+// IsHXHistoryRestore reports whether the request restores history after a
+// cache miss.
+func (c *Context) IsHXHistoryRestore() bool {
+	return c.Request.Header.Get("HX-History-Restore-Request") == "true"
+}
+
+// HXCurrentURL returns the browser URL when the request was made.
+func (c *Context) HXCurrentURL() string {
+	return c.Request.Header.Get("HX-Current-URL")
+}
+
+// HXTargetID returns the id of the target element, if it has one.
+func (c *Context) HXTargetID() string {
+	return c.Request.Header.Get("HX-Target")
+}
+
+// HXTriggerID returns the id of the element that triggered the request, if
+// it has one.
+func (c *Context) HXTriggerID() string {
+	return c.Request.Header.Get("HX-Trigger")
+}
+
+// HXTriggerName returns the name of the element that triggered the request,
+// if it has one.
+func (c *Context) HXTriggerName() string {
+	return c.Request.Header.Get("HX-Trigger-Name")
+}
+
+// HXPrompt returns the user's response to an hx-prompt.
+func (c *Context) HXPrompt() string {
+	return c.Request.Header.Get("HX-Prompt")
+}
+
+// ---------- HTMX Response Headers ----------
+
+// HXTrigger triggers a client-side event when the response is received,
+// with an optional detail that is sent as JSON. Call it several times to
+// trigger several events.
+//
+// Example:
+//
+//	c.HXTrigger("cart-updated", map[string]int{"count": 3})
+//	c.HXTrigger("close-modal")
+func (c *Context) HXTrigger(event string, detail ...any) error {
+	return c.hxTrigger(HXTriggerHeader, event, detail)
+}
+
+// HXTriggerAfterSwap triggers a client-side event after the swap, like
+// HXTrigger.
+func (c *Context) HXTriggerAfterSwap(event string, detail ...any) error {
+	return c.hxTrigger(HXTriggerAfterSwapHeader, event, detail)
+}
+
+// HXTriggerAfterSettle triggers a client-side event after the settle step,
+// like HXTrigger.
+func (c *Context) HXTriggerAfterSettle(event string, detail ...any) error {
+	return c.hxTrigger(HXTriggerAfterSettleHeader, event, detail)
+}
+
+// hxTrigger adds an event to a trigger header. Events without details are
+// sent as a comma-separated list; once any event has a detail, the header
+// is a JSON object.
+func (c *Context) hxTrigger(header, event string, detail []any) error {
+	all, _ := hxEvents.Get(c)
+	if all == nil {
+		all = make(map[string]*hxEventList)
+		hxEvents.Set(c, all)
+	}
+	list := all[header]
+	if list == nil {
+		list = &hxEventList{details: make(map[string]any)}
+		all[header] = list
+	}
+	if _, ok := list.details[event]; !ok {
+		list.names = append(list.names, event)
+	}
+	list.details[event] = nil
+	if len(detail) > 0 {
+		list.details[event] = detail[0]
+	}
+
+	value, err := list.encode()
+	if err != nil {
+		return fmt.Errorf("htmx: encoding %s detail: %w", event, err)
+	}
+	c.SetHeader(header, value)
+	return nil
+}
+
+// encode returns the header value for the events.
+func (l *hxEventList) encode() (string, error) {
+	hasDetail := false
+	for _, d := range l.details {
+		hasDetail = hasDetail || d != nil
+	}
+	if !hasDetail {
+		return strings.Join(l.names, ", "), nil
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range l.names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(l.details[name])
+		if err != nil {
+			return "", err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// HXRedirect makes HTMX do a full page redirect to url. It sends a 200
+// response, since HTMX doesn't follow 3xx redirects to other pages.
+func (c *Context) HXRedirect(url string) error {
+	c.SetHeader(HXRedirectHeader, url)
+	return c.hxEmpty()
+}
+
+// HXLocation makes HTMX load url without a full page reload, as if a
+// boosted link was followed. It sends a 200 response.
+func (c *Context) HXLocation(url string) error {
+	c.SetHeader(HXLocationHeader, url)
+	return c.hxEmpty()
+}
+
+// HXRefresh makes HTMX reload the whole page. It sends a 200 response.
+func (c *Context) HXRefresh() error {
+	c.SetHeader(HXRefreshHeader, "true")
+	return c.hxEmpty()
+}
+
+// hxEmpty sends an empty 200 response. HTMX skips the headers of 204
+// responses in some versions, so 200 is used.
+func (c *Context) hxEmpty() error {
+	c.Response.WriteHeader(http.StatusOK)
+	c.written = true
+	c.status = http.StatusOK
+	return nil
+}
+
+// HXPushURL pushes url into the browser history. Pass "false" to prevent
+// a push requested by hx-push-url.
+func (c *Context) HXPushURL(url string) {
+	c.SetHeader(HXPushURLHeader, url)
+}
+
+// HXReplaceURL replaces the current URL in the browser location bar.
+func (c *Context) HXReplaceURL(url string) {
+	c.SetHeader(HXReplaceURLHeader, url)
+}
+
+// HXRetarget swaps the response into the elements matching selector
+// instead of the request's target.
+func (c *Context) HXRetarget(selector string) {
+	c.SetHeader(HXRetargetHeader, selector)
+}
+
+// HXReswap overrides the request's hx-swap, e.g. "outerHTML" or
+// "beforeend scroll:bottom".
+func (c *Context) HXReswap(swap string) {
+	c.SetHeader(HXReswapHeader, swap)
+}
+
+// HXReselect selects the part of the response to swap, overriding
+// hx-select.
+func (c *Context) HXReselect(selector string) {
+	c.SetHeader(HXReselectHeader, selector)
+}
+
+// ---------- Out-of-Band Swaps ----------
+
+// RenderOOB renders main followed by out-of-band components, which HTMX
+// swaps into other parts of the page. Each out-of-band component must have
+// an hx-swap-oob attribute on its root element, or be wrapped with OOB.
+//
+// Example:
+//
+//	return c.RenderOOB(200, TodoItem(todo),
+//	    nexo.OOB("innerHTML:#todo-count", TodoCount(len(todos))))
+func (c *Context) RenderOOB(status int, main templ.Component, oob ...templ.Component) error {
+	c.VaryHTMX()
+	return c.Render(status, templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if main != nil {
+			if err := main.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		for _, component := range oob {
+			if err := component.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// OOB wraps component in a div with hx-swap-oob set to swap, such as
+// "innerHTML:#count" or "beforeend:#messages", so it is swapped out of
+// band. The div itself is discarded for these strategies; components
+// swapped with outerHTML should set hx-swap-oob on their own root element.
+func OOB(swap string, component templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, `<div hx-swap-oob="`+html.EscapeString(swap)+`">`); err != nil {
+			return err
+		}
+		if err := component.Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "</div>")
+		return err
+	})
+}
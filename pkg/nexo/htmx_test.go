@@ -0,0 +1,227 @@
+package nexo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func textComponent(s string) templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	})
+}
+
+func TestContext_VaryHTMX(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	c := NewContext(w, req)
+
+	if !c.IsHTMX() {
+		t.Fatal("IsHTMX() = false")
+	}
+	if got := w.Header().Get("Vary"); got != "" {
+		t.Errorf("expected IsHTMX not to set Vary, got %q", got)
+	}
+	c.VaryHTMX()
+	c.VaryHTMX()
+	if got := w.Header().Values("Vary"); len(got) != 1 || got[0] != "HX-Request" {
+		t.Errorf("Vary = %q, want [HX-Request]", got)
+	}
+}
+
+func TestContext_HTMXRequestHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("HX-Request", "true")
+	req.Header.Set("HX-Boosted", "true")
+	req.Header.Set("HX-Target", "list")
+	req.Header.Set("HX-Trigger", "save-btn")
+	req.Header.Set("HX-Trigger-Name", "save")
+	req.Header.Set("HX-Current-URL", "http://example.com/todos")
+	req.Header.Set("HX-Prompt", "yes")
+	c := NewContext(httptest.NewRecorder(), req)
+
+	if !c.IsHXBoosted() {
+		t.Error("IsHXBoosted() = false")
+	}
+	if c.IsHXHistoryRestore() {
+		t.Error("IsHXHistoryRestore() = true")
+	}
+	for name, got := range map[string][2]string{
+		"HXTargetID":    {c.HXTargetID(), "list"},
+		"HXTriggerID":   {c.HXTriggerID(), "save-btn"},
+		"HXTriggerName": {c.HXTriggerName(), "save"},
+		"HXCurrentURL":  {c.HXCurrentURL(), "http://example.com/todos"},
+		"HXPrompt":      {c.HXPrompt(), "yes"},
+	} {
+		if got[0] != got[1] {
+			t.Errorf("%s() = %q, want %q", name, got[0], got[1])
+		}
+	}
+}
+
+func TestContext_HXTrigger(t *testing.T) {
+	tests := []struct {
+		name   string
+		events func(c *Context) error
+		want   string
+	}{
+		{
+			name:   "single event",
+			events: func(c *Context) error { return c.HXTrigger("saved") },
+			want:   "saved",
+		},
+		{
+			name: "several events",
+			events: func(c *Context) error {
+				_ = c.HXTrigger("saved")
+				return c.HXTrigger("close-modal")
+			},
+			want: "saved, close-modal",
+		},
+		{
+			name: "event with detail",
+			events: func(c *Context) error {
+				_ = c.HXTrigger("saved")
+				return c.HXTrigger("cart", map[string]int{"count": 3})
+			},
+			want: `{"saved":null,"cart":{"count":3}}`,
+		},
+		{
+			name: "repeated event replaces detail",
+			events: func(c *Context) error {
+				_ = c.HXTrigger("cart", 1)
+				return c.HXTrigger("cart", 2)
+			},
+			want: `{"cart":2}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest(http.MethodPost, "/", nil))
+			if err := tt.events(c); err != nil {
+				t.Fatalf("HXTrigger() error = %v", err)
+			}
+			if got := w.Header().Get(HXTriggerHeader); got != tt.want {
+				t.Errorf("HX-Trigger = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext_HXTriggerHeadersAreSeparate(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	_ = c.HXTrigger("a")
+	_ = c.HXTriggerAfterSwap("b")
+	_ = c.HXTriggerAfterSettle("c")
+
+	for header, want := range map[string]string{
+		HXTriggerHeader:            "a",
+		HXTriggerAfterSwapHeader:   "b",
+		HXTriggerAfterSettleHeader: "c",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestContext_HXTriggerBadDetail(t *testing.T) {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	if err := c.HXTrigger("bad", make(chan int)); err == nil {
+		t.Error("HXTrigger() with an unencodable detail should fail")
+	}
+}
+
+func TestContext_HXRedirect(t *testing.T) {
+	tests := []struct {
+		name   string
+		call   func(c *Context) error
+		header string
+		want   string
+	}{
+		{"redirect", func(c *Context) error { return c.HXRedirect("/login") }, HXRedirectHeader, "/login"},
+		{"location", func(c *Context) error { return c.HXLocation("/todos") }, HXLocationHeader, "/todos"},
+		{"refresh", func(c *Context) error { return c.HXRefresh() }, HXRefreshHeader, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest(http.MethodPost, "/", nil))
+			if err := tt.call(c); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200", w.Code)
+			}
+			if got := w.Header().Get(tt.header); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+			if !c.Written() {
+				t.Error("response should be marked written")
+			}
+		})
+	}
+}
+
+func TestContext_HXSwapHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	c.HXPushURL("/todos/1")
+	c.HXReplaceURL("/todos")
+	c.HXRetarget("#errors")
+	c.HXReswap("outerHTML")
+	c.HXReselect("#main")
+
+	for header, want := range map[string]string{
+		HXPushURLHeader:    "/todos/1",
+		HXReplaceURLHeader: "/todos",
+		HXRetargetHeader:   "#errors",
+		HXReswapHeader:     "outerHTML",
+		HXReselectHeader:   "#main",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestContext_RenderOOB(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	err := c.RenderOOB(http.StatusCreated, textComponent("<li>todo</li>"),
+		OOB("innerHTML:#count", textComponent("3")),
+		textComponent(`<p id="flash" hx-swap-oob="true">saved</p>`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("status = %d, want 201", w.Code)
+	}
+	want := `<li>todo</li><div hx-swap-oob="innerHTML:#count">3</div><p id="flash" hx-swap-oob="true">saved</p>`
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestOOB_EscapesSwap(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := OOB(`innerHTML:#a"b`, textComponent("x")).Render(context.Background(), w); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div hx-swap-oob="innerHTML:#a&#34;b">x</div>`; w.Body.String() != want {
+		t.Errorf("OOB() = %q, want %q", w.Body.String(), want)
+	}
+}
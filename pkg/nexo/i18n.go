@@ -0,0 +1,373 @@
+package nexo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ---------- Translations ----------
+
+// Translations holds messages by locale and key.
+type Translations struct {
+	messages map[string]map[string]string
+	locales  []string
+}
+
+// LoadTranslations reads one translation file per locale from the root of
+// fsys, named after the locale: en.json, fr.yaml, pt-BR.yml. Nested
+// objects are flattened into dotted keys, so {"nav": {"home": "Home"}}
+// defines "nav.home".
+func LoadTranslations(fsys fs.FS) (*Translations, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Translations{messages: make(map[string]map[string]string)}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		var tree map[string]any
+		if ext == ".json" {
+			err = json.Unmarshal(data, &tree)
+		} else {
+			err = yaml.Unmarshal(data, &tree)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), ext)
+		if t.messages[locale] == nil {
+			t.messages[locale] = make(map[string]string)
+			t.locales = append(t.locales, locale)
+		}
+		flattenMessages(t.messages[locale], "", tree)
+	}
+	sort.Strings(t.locales)
+	return t, nil
+}
+
+// flattenMessages adds the strings in tree to messages under dotted keys.
+func flattenMessages(messages map[string]string, prefix string, tree map[string]any) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenMessages(messages, key, v)
+		case string:
+			messages[key] = v
+		case nil:
+		default:
+			messages[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// Locales returns the locales with translations, sorted.
+func (t *Translations) Locales() []string {
+	return t.locales
+}
+
+// Translate returns the message for key in locale, formatted with args
+// like fmt.Sprintf. Keys missing from locale are looked up in its base
+// language, so "fr-CA" falls back to "fr". If there's no message, the key
+// is returned.
+func (t *Translations) Translate(locale, key string, args ...any) string {
+	return t.translate([]string{locale, baseLanguage(locale)}, key, args)
+}
+
+// translate returns the message for key in the first of locales that has
+// it.
+func (t *Translations) translate(locales []string, key string, args []any) string {
+	for _, locale := range locales {
+		if msg, ok := t.messages[t.match(locale)][key]; ok {
+			if len(args) > 0 {
+				return fmt.Sprintf(msg, args...)
+			}
+			return msg
+		}
+	}
+	return key
+}
+
+// match returns the supported locale equal to locale, ignoring case and
+// "-" versus "_", or "".
+func (t *Translations) match(locale string) string {
+	if _, ok := t.messages[locale]; ok {
+		return locale
+	}
+	normalized := normalizeLocale(locale)
+	for _, l := range t.locales {
+		if normalizeLocale(l) == normalized {
+			return l
+		}
+	}
+	return ""
+}
+
+// negotiate returns the supported locale best matching locale: the same
+// locale, its base language, or a regional variant of it.
+func (t *Translations) negotiate(locale string) string {
+	if l := t.match(locale); l != "" {
+		return l
+	}
+	base := baseLanguage(locale)
+	if l := t.match(base); l != "" {
+		return l
+	}
+	for _, l := range t.locales {
+		if baseLanguage(l) == base {
+			return l
+		}
+	}
+	return ""
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// baseLanguage returns the language of locale without its region, e.g.
+// "fr" for "fr-CA".
+func baseLanguage(locale string) string {
+	locale = normalizeLocale(locale)
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// parseAcceptLanguage returns the languages in an Accept-Language header,
+// most preferred first. Languages with q=0 are left out.
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if tag = strings.TrimSpace(tag); tag != "" && tag != "*" && q > 0 {
+			languages = append(languages, language{tag, q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+// ---------- Localizer ----------
+
+// localizerKey is the request context key for the request's Localizer.
+type localizerKey struct{}
+
+// Localizer translates messages into a request's locale, falling back to
+// the default locale.
+type Localizer struct {
+	translations *Translations
+	locale       string
+	fallbacks    []string
+}
+
+// Locale returns the localizer's locale.
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T returns the message for key, formatted with args like fmt.Sprintf.
+// Keys missing from the locale are looked up in the default locale; if
+// there's still no message, the key is returned.
+func (l *Localizer) T(key string, args ...any) string {
+	return l.translations.translate(l.fallbacks, key, args)
+}
+
+// localizerFrom returns the Localizer I18n added to ctx, or nil.
+func localizerFrom(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return l
+}
+
+// T translates key into the request's locale in templ templates, like
+// Context.T. Without the I18n middleware, it returns the key.
+//
+// Example:
+//
+//	<h1>{ nexo.T(ctx, "home.title") }</h1>
+//	<p>{ nexo.T(ctx, "home.greeting", user.Name) }</p>
+func T(ctx context.Context, key string, args ...any) string {
+	if l := localizerFrom(ctx); l != nil {
+		return l.T(key, args...)
+	}
+	return key
+}
+
+// Locale returns the request's locale in templ templates, like
+// Context.Locale.
+//
+// Example:
+//
+//	<html lang={ nexo.Locale(ctx) }>
+func Locale(ctx context.Context) string {
+	if l := localizerFrom(ctx); l != nil {
+		return l.locale
+	}
+	return ""
+}
+
+// T translates key into the request's locale. See Localizer.T.
+//
+// Example:
+//
+//	return c.JSON(200, map[string]string{
+//	    "message": c.T("orders.created", order.ID),
+//	})
+func (c *Context) T(key string, args ...any) string {
+	return T(c.Context(), key, args...)
+}
+
+// Locale returns the locale chosen by the I18n middleware, or "" without
+// it.
+func (c *Context) Locale() string {
+	return Locale(c.Context())
+}
+
+// ---------- I18n Middleware ----------
+
+// I18nConfig holds configuration for the i18n middleware.
+type I18nConfig struct {
+	// Dir is the directory holding the translation files, one per locale
+	// (see LoadTranslations). Default is "locales".
+	Dir string
+
+	// FS reads Dir from fsys instead of the disk, e.g. an embed.FS.
+	FS fs.FS
+
+	// Translations are used instead of loading Dir.
+	Translations *Translations
+
+	// DefaultLocale is used when no supported locale is requested, and
+	// for keys missing from a locale. Default is "en".
+	DefaultLocale string
+
+	// CookieName is the cookie holding a locale the user picked. Default
+	// is "locale".
+	CookieName string
+
+	// Skip returns true for requests that aren't localized.
+	Skip func(c *Context) bool
+}
+
+// I18n returns a middleware that picks a locale for each request and
+// translates messages with c.T and nexo.T. The locale is the first
+// supported one of:
+//
+//  1. The first path segment, e.g. /fr/about. Put pages under
+//     app/[lang]/ to serve localized paths.
+//  2. The CookieName cookie.
+//  3. The Accept-Language header, matching regional variants and base
+//     languages, so "fr-CA" gets "fr".
+//  4. DefaultLocale.
+//
+// The response gets a Content-Language header. I18n panics if the
+// translations can't be loaded.
+//
+// Example:
+//
+//	app.Use(nexo.I18n(nexo.I18nConfig{DefaultLocale: "en"}))
+func I18n(config I18nConfig) MiddlewareFunc {
+	if config.Dir == "" {
+		config.Dir = "locales"
+	}
+	if config.DefaultLocale == "" {
+		config.DefaultLocale = "en"
+	}
+	if config.CookieName == "" {
+		config.CookieName = "locale"
+	}
+	translations := config.Translations
+	if translations == nil {
+		fsys := config.FS
+		if fsys == nil {
+			fsys = os.DirFS(config.Dir)
+		} else if sub, err := fs.Sub(fsys, config.Dir); err == nil {
+			fsys = sub
+		}
+		var err error
+		if translations, err = LoadTranslations(fsys); err != nil {
+			panic(fmt.Sprintf("nexo: failed to load translations from %s: %v", config.Dir, err))
+		}
+	}
+	defaultLocale := config.DefaultLocale
+	if l := translations.negotiate(defaultLocale); l != "" {
+		defaultLocale = l
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			locale := ""
+			segment, _, _ := strings.Cut(strings.TrimPrefix(c.Path(), "/"), "/")
+			if segment != "" {
+				locale = translations.match(segment)
+			}
+			if locale == "" {
+				addVary(c.Response.Header(), "Accept-Language")
+				if cookie, err := c.Request.Cookie(config.CookieName); err == nil {
+					locale = translations.negotiate(cookie.Value)
+				}
+			}
+			if locale == "" {
+				for _, tag := range parseAcceptLanguage(c.Header("Accept-Language")) {
+					if locale = translations.negotiate(tag); locale != "" {
+						break
+					}
+				}
+			}
+			if locale == "" {
+				locale = defaultLocale
+			}
+
+			c.WithContext(context.WithValue(c.Context(), localizerKey{}, &Localizer{
+				translations: translations,
+				locale:       locale,
+				fallbacks:    []string{locale, baseLanguage(locale), defaultLocale},
+			}))
+			c.SetHeader("Content-Language", locale)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/a-h/templ"
+)
+
+var testLocales = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{
+		"nav": {"home": "Home", "about": "About"},
+		"greeting": "Hello, %s!",
+		"items": 3
+	}`)},
+	"locales/fr.yaml":   {Data: []byte("nav:\n  home: Accueil\ngreeting: Bonjour, %s !\n")},
+	"locales/pt-BR.yml": {Data: []byte("nav:\n  home: Início\n")},
+	"locales/README.md": {Data: []byte("not a locale")},
+}
+
+func newI18nApp(t *testing.T) *App {
+	t.Helper()
+	app := New()
+	app.DisableLogger()
+	app.Use(I18n(I18nConfig{FS: testLocales}))
+	handler := func(c *Context) error {
+		return c.String(http.StatusOK, c.Locale()+"|"+c.T("nav.home"))
+	}
+	app.Get("/", handler)
+	app.Get("/{lang}/about", handler)
+	app.Mount()
+	return app
+}
+
+func TestLoadTranslations(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"a": {"b": {"c": "tief"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	translations, err := LoadTranslations(os.DirFS(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := translations.Translate("de", "a.b.c"); got != "tief" {
+		t.Errorf("expected nested keys to be flattened, got %q", got)
+	}
+	if got := translations.Translate("de-AT", "a.b.c"); got != "tief" {
+		t.Errorf("expected the base language to be used, got %q", got)
+	}
+	if got := translations.Translate("de", "missing"); got != "missing" {
+		t.Errorf("expected the key for a missing message, got %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "en.yaml"), []byte("a: [unclosed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTranslations(os.DirFS(dir)); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
+
+func TestI18n_Negotiation(t *testing.T) {
+	app := newI18nApp(t)
+
+	tests := []struct {
+		name           string
+		path           string
+		cookie         string
+		acceptLanguage string
+		want           string
+	}{
+		{"default", "/", "", "", "en|Home"},
+		{"accept language", "/", "", "fr-FR,fr;q=0.9,en;q=0.8", "fr|Accueil"},
+		{"quality order", "/", "", "en;q=0.5, fr;q=0.9", "fr|Accueil"},
+		{"regional variant", "/", "", "pt", "pt-BR|Início"},
+		{"case insensitive", "/", "", "PT-br", "pt-BR|Início"},
+		{"unsupported", "/", "", "ja, zh;q=0.8", "en|Home"},
+		{"cookie beats header", "/", "fr", "en", "fr|Accueil"},
+		{"invalid cookie", "/", "xx", "fr", "fr|Accueil"},
+		{"path prefix beats cookie", "/pt-BR/about", "fr", "en", "pt-BR|Início"},
+		{"unsupported path prefix", "/de/about", "", "fr", "fr|Accueil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "locale", Value: tt.cookie})
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Body.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestI18n_Fallback(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(I18n(I18nConfig{FS: testLocales}))
+	app.Get("/", func(c *Context) error {
+		return c.String(http.StatusOK, c.T("greeting", "Ana")+"|"+c.T("nav.about")+"|"+c.T("items")+"|"+c.T("nope"))
+	})
+	app.Mount()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "fr")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if want := "Bonjour, Ana !|About|3|nope"; w.Body.String() != want {
+		t.Errorf("expected %q, got %q", want, w.Body.String())
+	}
+	if w.Header().Get("Content-Language") != "fr" {
+		t.Errorf("expected Content-Language fr, got %q", w.Header().Get("Content-Language"))
+	}
+	if w.Header().Get("Vary") != "Accept-Language" {
+		t.Errorf("expected Vary: Accept-Language, got %q", w.Header().Get("Vary"))
+	}
+}
+
+func TestI18n_Templ(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(I18n(I18nConfig{FS: testLocales, DefaultLocale: "fr"}))
+	app.Get("/", func(c *Context) error {
+		return c.Render(http.StatusOK, templ.Raw(Locale(c.Context())+":"+T(c.Context(), "nav.home")))
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Body.String() != "fr:Accueil" {
+		t.Errorf("expected the templ helpers to translate, got %q", w.Body.String())
+	}
+}
+
+func TestI18n_WithoutMiddleware(t *testing.T) {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if c.T("nav.home") != "nav.home" || c.Locale() != "" {
+		t.Error("expected keys to be returned without the middleware")
+	}
+}
+
+func TestI18n_InvalidDir(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing directory")
+		}
+	}()
+	I18n(I18nConfig{Dir: filepath.Join(t.TempDir(), "missing")})
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := parseAcceptLanguage("da, en-GB;q=0.8, en;q=0.7, *;q=0.5, de;q=0")
+	want := []string{"da", "en-GB", "en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
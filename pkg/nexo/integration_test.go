@@ -2,10 +2,13 @@ package nexo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -119,6 +122,277 @@ func TestApp_Shutdown_NotStarted(t *testing.T) {
 	}
 }
 
+func TestApp_ListenWithContext_DrainsRequests(t *testing.T) {
+	port, err := getAvailablePort()
+	if err != nil {
+		t.Fatalf("failed to get available port: %v", err)
+	}
+
+	app := New()
+	app.DisableLogger()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app.Get("/slow", func(c *Context) error {
+		close(started)
+		<-release
+		return c.String(200, "done")
+	})
+
+	var hooks []string
+	app.OnShutdown(func(ctx context.Context) error {
+		hooks = append(hooks, "first")
+		return nil
+	})
+	app.OnShutdown(func(ctx context.Context) error {
+		hooks = append(hooks, "second")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.ListenWithContext(ctx, fmt.Sprintf("127.0.0.1:%d", port))
+	}()
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", port)
+	if err := waitForServer(baseURL+"/missing", 2*time.Second); err != nil {
+		t.Fatalf("server failed to start: %v", err)
+	}
+	// Connections the client dialed but never used would hold up shutdown
+	http.DefaultClient.CloseIdleConnections()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	// Start a request, then shut down while it is in flight
+	type result struct {
+		body string
+		err  error
+	}
+	respCh := make(chan result, 1)
+	go func() {
+		resp, err := client.Get(baseURL + "/slow")
+		if err != nil {
+			respCh <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		respCh <- result{string(body), err}
+	}()
+
+	<-started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if res := <-respCh; res.err != nil || res.body != "done" {
+		t.Errorf("in-flight request: body %q, err %v", res.body, res.err)
+	}
+
+	select {
+	case err := <-listenErr:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server didn't shutdown in time")
+	}
+
+	if strings.Join(hooks, ",") != "second,first" {
+		t.Errorf("expected hooks to run in reverse order, got %v", hooks)
+	}
+}
+
+func TestApp_Shutdown_GracePeriod(t *testing.T) {
+	port, err := getAvailablePort()
+	if err != nil {
+		t.Fatalf("failed to get available port: %v", err)
+	}
+
+	app := New(WithShutdownTimeout(50 * time.Millisecond))
+	app.DisableLogger()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	app.Get("/hang", func(c *Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	hookRan := false
+	app.OnShutdown(func(ctx context.Context) error {
+		hookRan = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.ListenWithContext(ctx, fmt.Sprintf("127.0.0.1:%d", port))
+	}()
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", port)
+	if err := waitForServer(baseURL+"/missing", 2*time.Second); err != nil {
+		t.Fatalf("server failed to start: %v", err)
+	}
+	// Connections the client dialed but never used would hold up shutdown
+	http.DefaultClient.CloseIdleConnections()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	go func() {
+		if resp, err := client.Get(baseURL + "/hang"); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+
+	select {
+	case err := <-listenErr:
+		if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a deadline error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server didn't shutdown after the grace period")
+	}
+
+	if !hookRan {
+		t.Error("expected the shutdown hook to run")
+	}
+}
+
+func TestApp_Listener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	app := New()
+	app.DisableLogger()
+	app.Get("/health", func(c *Context) error {
+		return c.String(200, "ok")
+	})
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- app.Listener(ln)
+	}()
+
+	baseURL := "http://" + ln.Addr().String()
+	if err := waitForServer(baseURL+"/health", 2*time.Second); err != nil {
+		t.Fatalf("server failed to start: %v", err)
+	}
+	if app.Addr() != ln.Addr().String() {
+		t.Errorf("expected Addr %q, got %q", ln.Addr().String(), app.Addr())
+	}
+
+	http.DefaultClient.CloseIdleConnections()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := app.Shutdown(ctx); err != nil {
+		t.Errorf("shutdown failed: %v", err)
+	}
+	if err := <-serveErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestApp_ListenUnixSocket(t *testing.T) {
+	// Socket paths are limited to about 100 bytes, so avoid long temp dirs
+	dir, err := os.MkdirTemp("", "nexo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	socket := filepath.Join(dir, "app.sock")
+
+	// A stale socket from a previous run is replaced
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	app := New(WithUnixSocket(socket, 0660))
+	app.DisableLogger()
+	app.Get("/health", func(c *Context) error {
+		return c.String(200, "ok")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.ListenWithContext(ctx)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+		DisableKeepAlives: true,
+	}}
+
+	var resp *http.Response
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if resp, err = client.Get("http://unix/health"); err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("expected body ok, got %q", body)
+	}
+
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0660 {
+		t.Errorf("expected socket permissions 0660, got %o", perm)
+	}
+
+	cancel()
+	if err := <-listenErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("expected the socket to be removed, got %v", err)
+	}
+}
+
+func TestApp_ListenUnixSocket_InUse(t *testing.T) {
+	dir, err := os.MkdirTemp("", "nexo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	socket := filepath.Join(dir, "app.sock")
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	app := New(WithUnixSocket(socket, 0))
+	err = app.ListenWithContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("expected an in-use error, got %v", err)
+	}
+}
+
 func TestApp_ListenWithProxy(t *testing.T) {
 	port, err := getAvailablePort()
 	if err != nil {
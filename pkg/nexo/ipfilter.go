@@ -0,0 +1,155 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// trustedProxiesKey is the request context key for the app's trusted proxies.
+type trustedProxiesKey struct{}
+
+// withTrustedProxies makes the app's trusted proxies available to ClientIP
+// and IPFilter.
+func withTrustedProxies(r *http.Request, proxies []netip.Prefix) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), trustedProxiesKey{}, proxies))
+}
+
+// parsePrefixes parses CIDRs and single IPs. It panics on invalid entries,
+// which are configuration mistakes.
+func parsePrefixes(entries []string, setting string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			panic(fmt.Sprintf("nexo: invalid %s entry %q: not an IP or CIDR", setting, entry))
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes
+}
+
+// containsAddr reports whether any of prefixes contains addr.
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr returns the address of the peer that sent r.
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// forwardedAddr returns the client address of a request passed on by
+// trusted proxies: X-Forwarded-For is read from the right, skipping the
+// trusted hops, so clients can't spoof it by sending their own header.
+// Requests from untrusted peers get their peer's address.
+func forwardedAddr(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	addr, ok := remoteAddr(r)
+	if !ok || !containsAddr(trusted, addr) {
+		return addr, ok
+	}
+
+	var hops []string
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops = strings.Split(strings.Join(values, ","), ",")
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Anything left of a malformed hop can't be trusted
+			return addr, true
+		}
+		addr = hop.Unmap()
+		if !containsAddr(trusted, addr) {
+			return addr, true
+		}
+	}
+
+	if hop, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return hop.Unmap(), true
+	}
+	return addr, true
+}
+
+// clientAddr returns the request's client address, trusting forwarding
+// headers only from the app's trusted proxies.
+func clientAddr(r *http.Request) (netip.Addr, bool) {
+	trusted, _ := r.Context().Value(trustedProxiesKey{}).([]netip.Prefix)
+	if len(trusted) == 0 {
+		return remoteAddr(r)
+	}
+	return forwardedAddr(r, trusted)
+}
+
+// ---------- IPFilter Middleware ----------
+
+// IPFilterConfig holds configuration for the IP filter middleware. Entries
+// are CIDRs such as "10.0.0.0/8" or single IPs.
+type IPFilterConfig struct {
+	// Allow lists the clients that may make requests. Empty allows every
+	// client not denied.
+	Allow []string
+
+	// Deny lists the clients that may not make requests. It takes
+	// precedence over Allow.
+	Deny []string
+
+	// Skip returns true for requests that aren't filtered.
+	Skip func(c *Context) bool
+}
+
+// IPFilter returns a middleware that only lets clients in the Allow list,
+// and not in the Deny list, through. Others get a 403 Forbidden. It panics
+// if an entry isn't a valid IP or CIDR.
+//
+// The client is the connection's peer. Behind a load balancer, list it with
+// WithTrustedProxies so the client is read from X-Forwarded-For instead;
+// the header is ignored otherwise, since clients can set it themselves.
+//
+// Example:
+//
+//	app.Group("/admin", func(g *nexo.RouteGroup) {
+//	    g.Use(nexo.IPFilter(nexo.IPFilterConfig{
+//	        Allow: []string{"10.0.0.0/8", "192.168.1.20"},
+//	    }))
+//	})
+func IPFilter(config IPFilterConfig) MiddlewareFunc {
+	allow := parsePrefixes(config.Allow, "IPFilter Allow")
+	deny := parsePrefixes(config.Deny, "IPFilter Deny")
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			addr, ok := clientAddr(c.Request)
+			if !ok || containsAddr(deny, addr) || (len(allow) > 0 && !containsAddr(allow, addr)) {
+				return Forbidden("access denied")
+			}
+			return next(c)
+		}
+	}
+}
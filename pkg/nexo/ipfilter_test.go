@@ -0,0 +1,128 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		config IPFilterConfig
+		remote string
+		status int
+	}{
+		{"allowed CIDR", IPFilterConfig{Allow: []string{"10.0.0.0/8"}}, "10.1.2.3:5000", http.StatusOK},
+		{"outside allow list", IPFilterConfig{Allow: []string{"10.0.0.0/8"}}, "192.168.1.1:5000", http.StatusForbidden},
+		{"allowed single IP", IPFilterConfig{Allow: []string{"192.168.1.1"}}, "192.168.1.1:5000", http.StatusOK},
+		{"denied", IPFilterConfig{Deny: []string{"192.168.0.0/16"}}, "192.168.1.1:5000", http.StatusForbidden},
+		{"not denied", IPFilterConfig{Deny: []string{"192.168.0.0/16"}}, "10.0.0.1:5000", http.StatusOK},
+		{"deny beats allow", IPFilterConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.66"}}, "10.0.0.66:5000", http.StatusForbidden},
+		{"IPv6", IPFilterConfig{Allow: []string{"2001:db8::/32"}}, "[2001:db8::1]:5000", http.StatusOK},
+		{"IPv4-mapped IPv6", IPFilterConfig{Allow: []string{"10.0.0.0/8"}}, "[::ffff:10.0.0.1]:5000", http.StatusOK},
+		{"skip", IPFilterConfig{Deny: []string{"10.0.0.1"}, Skip: func(c *Context) bool { return true }}, "10.0.0.1:5000", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.DisableLogger()
+			app.Use(IPFilter(tt.config))
+			app.Get("/", func(c *Context) error {
+				return c.String(http.StatusOK, "ok")
+			})
+			app.Mount()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestIPFilter_ForwardedFor(t *testing.T) {
+	newApp := func(opts ...Option) *App {
+		app := New(opts...)
+		app.DisableLogger()
+		app.Use(IPFilter(IPFilterConfig{Allow: []string{"10.0.0.0/8"}}))
+		app.Get("/", func(c *Context) error {
+			return c.String(http.StatusOK, c.ClientIP())
+		})
+		app.Mount()
+		return app
+	}
+
+	do := func(app *App, remote string, forwardedFor ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remote
+		for _, v := range forwardedFor {
+			req.Header.Add("X-Forwarded-For", v)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without trusted proxies the header is ignored
+	if w := do(newApp(), "203.0.113.7:5000", "10.0.0.1"); w.Code != http.StatusForbidden {
+		t.Errorf("expected a spoofed header to be ignored, got %d", w.Code)
+	}
+
+	app := newApp(WithTrustedProxies("172.16.0.0/12"))
+	if w := do(app, "172.16.0.2:5000", "10.0.0.1"); w.Code != http.StatusOK || w.Body.String() != "10.0.0.1" {
+		t.Errorf("expected the forwarded client to be allowed, got %d %q", w.Code, w.Body.String())
+	}
+	// The client's own header is left of the proxy's entry
+	if w := do(app, "172.16.0.2:5000", "10.0.0.1, 203.0.113.7"); w.Code != http.StatusForbidden {
+		t.Errorf("expected the hop the proxy saw to be used, got %d", w.Code)
+	}
+	// Trusted hops are skipped, across several headers
+	if w := do(app, "172.16.0.2:5000", "10.0.0.1", "172.16.0.9"); w.Code != http.StatusOK {
+		t.Errorf("expected trusted hops to be skipped, got %d", w.Code)
+	}
+	// Untrusted peers can't set the header
+	if w := do(app, "203.0.113.7:5000", "10.0.0.1"); w.Code != http.StatusForbidden {
+		t.Errorf("expected an untrusted peer's header to be ignored, got %d", w.Code)
+	}
+}
+
+func TestIPFilter_InvalidEntry(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an invalid entry")
+		}
+	}()
+	IPFilter(IPFilterConfig{Allow: []string{"10.0.0.0/33"}})
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	app := New(WithTrustedProxies("127.0.0.1"))
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error {
+		return c.String(http.StatusOK, c.ClientIP())
+	})
+	app.Mount()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.Header.Set("X-Real-IP", "198.51.100.4")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Body.String() != "198.51.100.4" {
+		t.Errorf("expected X-Real-IP from a trusted proxy, got %q", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "198.51.100.9:5000"
+	req.Header.Set("X-Real-IP", "1.2.3.4")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Body.String() != "198.51.100.9" {
+		t.Errorf("expected the peer's address, got %q", w.Body.String())
+	}
+}
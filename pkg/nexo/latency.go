@@ -0,0 +1,201 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// latencySamples is how many latencies are kept per route to estimate the
+// percentiles in the latency summary.
+const latencySamples = 1000
+
+// routeLatency holds the requests to one route for the latency summary.
+type routeLatency struct {
+	count   uint64
+	errors  uint64
+	samples []time.Duration
+}
+
+// latencyStats accumulates per-route request counts, latencies and errors
+// since the app started.
+type latencyStats struct {
+	start time.Time
+
+	mu     sync.Mutex
+	routes map[routeKey]*routeLatency
+}
+
+func newLatencyStats() *latencyStats {
+	return &latencyStats{
+		start:  time.Now(),
+		routes: make(map[routeKey]*routeLatency),
+	}
+}
+
+// observe records a request. Once a route has latencySamples samples, new
+// ones replace random old ones, so the samples stay representative of the
+// whole run.
+func (s *latencyStats) observe(key routeKey, d time.Duration, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.routes[key]
+	if r == nil {
+		r = &routeLatency{}
+		s.routes[key] = r
+	}
+	r.count++
+	if status >= 500 {
+		r.errors++
+	}
+	if len(r.samples) < latencySamples {
+		r.samples = append(r.samples, d)
+	} else if i := rand.Uint64N(r.count); i < latencySamples {
+		r.samples[i] = d
+	}
+}
+
+// latencyRow is a route's line in the latency summary.
+type latencyRow struct {
+	key           routeKey
+	count         uint64
+	errorRate     float64
+	p50, p95, p99 time.Duration
+}
+
+// rows returns the summary of each route, slowest (by p95) first.
+func (s *latencyStats) rows() []latencyRow {
+	s.mu.Lock()
+	rows := make([]latencyRow, 0, len(s.routes))
+	samples := make([][]time.Duration, 0, len(s.routes))
+	for key, r := range s.routes {
+		rows = append(rows, latencyRow{
+			key:       key,
+			count:     r.count,
+			errorRate: float64(r.errors) / float64(r.count),
+		})
+		samples = append(samples, append([]time.Duration(nil), r.samples...))
+	}
+	s.mu.Unlock()
+
+	for i, sorted := range samples {
+		sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+		rows[i].p50 = percentile(sorted, 0.50)
+		rows[i].p95 = percentile(sorted, 0.95)
+		rows[i].p99 = percentile(sorted, 0.99)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].p95 != rows[j].p95 {
+			return rows[i].p95 > rows[j].p95
+		}
+		return rows[i].key.less(rows[j].key)
+	})
+	return rows
+}
+
+// percentile returns the p-th percentile (0-1) of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// write prints the summary as a table.
+func (s *latencyStats) write(w io.Writer) error {
+	rows := s.rows()
+	uptime := time.Since(s.start).Round(time.Second)
+
+	if _, err := fmt.Fprintf(w, "\n  Latency summary (%s since %s)\n\n", uptime, s.start.Format("15:04:05")); err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		_, err := fmt.Fprint(w, "  No requests yet\n\n")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprint(tw, "  METHOD\tROUTE\tCOUNT\tP50\tP95\tP99\tERRORS\n")
+	for _, row := range rows {
+		route := row.key.route
+		if route == "" {
+			route = "(unmatched)"
+		}
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%d\t%s\t%s\t%s\t%.1f%%\n",
+			row.key.method, route, row.count,
+			formatSummaryLatency(row.p50), formatSummaryLatency(row.p95), formatSummaryLatency(row.p99),
+			row.errorRate*100)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// formatSummaryLatency formats a latency for the summary table.
+func formatSummaryLatency(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
+
+// WriteLatencySummary writes a table of the routes served since the app
+// started, with request counts, p50/p95/p99 latency and 5xx error rates,
+// slowest first. Latencies are collected under nexo dev or with
+// WithLatencySummary; otherwise it returns an error.
+func (a *App) WriteLatencySummary(w io.Writer) error {
+	if a.latencyStats == nil {
+		return fmt.Errorf("latency summary is not enabled")
+	}
+	return a.latencyStats.write(w)
+}
+
+// watchLatencySummary prints the latency summary every latencyInterval,
+// if set, and on latencySignals until ctx is done.
+func (a *App) watchLatencySummary(ctx context.Context) {
+	if a.latencyStats == nil || (a.latencyInterval <= 0 && len(latencySignals) == 0) {
+		return
+	}
+
+	sig := make(chan os.Signal, 1)
+	if len(latencySignals) > 0 {
+		signal.Notify(sig, latencySignals...)
+	}
+
+	go func() {
+		defer signal.Stop(sig)
+
+		var tick <-chan time.Time
+		if a.latencyInterval > 0 {
+			ticker := time.NewTicker(a.latencyInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			case <-sig:
+			}
+			_ = a.latencyStats.write(os.Stdout)
+		}
+	}()
+}
@@ -0,0 +1,8 @@
+//go:build !(darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package nexo
+
+import "os"
+
+// latencySignals print the latency summary. There's no SIGINFO here.
+var latencySignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package nexo
+
+import (
+	"os"
+	"syscall"
+)
+
+// latencySignals print the latency summary, e.g. Ctrl+T in a terminal.
+var latencySignals = []os.Signal{syscall.SIGINFO}
@@ -0,0 +1,93 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0.50, 50 * time.Millisecond},
+		{0.95, 95 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("percentile of no samples = %v, want 0", got)
+	}
+}
+
+func TestLatencySummary(t *testing.T) {
+	app := New(WithLatencySummary(0), WithDevDashboard(true))
+	app.DisableLogger()
+	app.Get("/users/{id}", func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	app.Get("/fail", func(c *Context) error {
+		return NewHTTPError(http.StatusInternalServerError, "boom")
+	})
+	app.Mount()
+
+	for _, path := range []string{"/users/1", "/users/2", "/fail", "/missing"} {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var b strings.Builder
+	if err := app.WriteLatencySummary(&b); err != nil {
+		t.Fatalf("WriteLatencySummary failed: %v", err)
+	}
+	summary := b.String()
+
+	for _, want := range []string{"METHOD", "/users/{id}", "(unmatched)"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected %q in the summary, got:\n%s", want, summary)
+		}
+	}
+	for _, line := range strings.Split(summary, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[1] {
+		case "/users/{id}":
+			if fields[2] != "2" || fields[6] != "0.0%" {
+				t.Errorf("Expected 2 requests without errors, got %q", line)
+			}
+		case "/fail":
+			if fields[2] != "1" || fields[6] != "100.0%" {
+				t.Errorf("Expected 1 failed request, got %q", line)
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DevDashboardPath+"/latency", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/users/{id}") {
+		t.Errorf("Expected the summary from the dashboard, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestLatencySummary_Disabled(t *testing.T) {
+	t.Setenv("NEXO_DEV", "")
+	t.Setenv(DevProxyEnv, "")
+	t.Setenv(LiveReloadPortEnv, "")
+
+	if err := New().WriteLatencySummary(&strings.Builder{}); err == nil {
+		t.Error("Expected an error when the latency summary is not enabled")
+	}
+}
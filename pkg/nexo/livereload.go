@@ -0,0 +1,160 @@
+package nexo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// LiveReloadPortEnv is the environment variable set by `nexo dev` to the port
+// of its live reload server. When it is set, the app injects a script into
+// HTML responses that reloads the page after each successful rebuild.
+const LiveReloadPortEnv = "NEXO_LIVE_RELOAD_PORT"
+
+// LiveReloadScript returns the script that reloads the page when the live
+// reload server on port sends a reload event.
+func LiveReloadScript(port string) string {
+	return fmt.Sprintf(`<script>(function(){var es=new EventSource(location.protocol+"//"+location.hostname+":%s/events");es.addEventListener("reload",function(){location.reload()})})()</script>`, port)
+}
+
+// liveReloadScriptFromEnv returns the live reload script for the port in
+// LiveReloadPortEnv, or nil if live reload is disabled.
+func liveReloadScriptFromEnv() []byte {
+	port := os.Getenv(LiveReloadPortEnv)
+	if port == "" {
+		return nil
+	}
+	return []byte(LiveReloadScript(port))
+}
+
+// wantsLiveReload reports whether the live reload script should be injected
+// into the response to r. HTMX requests are skipped so swapped fragments
+// don't open additional connections.
+func wantsLiveReload(r *http.Request) bool {
+	return r.Method != http.MethodHead && r.Header.Get("HX-Request") == ""
+}
+
+// liveReloadWriter buffers HTML responses so the live reload script can be
+// injected before </body>. Flushing sends what is buffered, so streamed
+// pages still arrive in chunks. Other responses, including compressed
+// HTML, are passed through unchanged.
+type liveReloadWriter struct {
+	http.ResponseWriter
+	script    []byte
+	status    int
+	decided   bool
+	html      bool
+	streaming bool // The header is sent; buf holds what was written since the last flush
+	injected  bool
+	buf       bytes.Buffer
+}
+
+func newLiveReloadWriter(w http.ResponseWriter, script []byte) *liveReloadWriter {
+	return &liveReloadWriter{
+		ResponseWriter: w,
+		script:         script,
+		status:         http.StatusOK,
+	}
+}
+
+// WriteHeader decides whether the response is buffered based on its content
+// type and encoding. The script can't be added to an encoded body, such as
+// one compressed by Compress or a precompressed static file.
+func (w *liveReloadWriter) WriteHeader(code int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.status = code
+	encoding := w.Header().Get("Content-Encoding")
+	w.html = strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") &&
+		(encoding == "" || strings.EqualFold(encoding, "identity")) &&
+		code != http.StatusNoContent && code != http.StatusNotModified
+	if !w.html {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write buffers HTML bodies and passes everything else through.
+func (w *liveReloadWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.html {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, sending buffered HTML first.
+func (w *liveReloadWriter) Flush() {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.html {
+		w.writeBuffered(false)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface for WebSocket support.
+func (w *liveReloadWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *liveReloadWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the rest of a buffered HTML response, injecting the script
+// if no flushed chunk had a </body>.
+func (w *liveReloadWriter) finish() {
+	if w.html {
+		w.writeBuffered(true)
+	}
+}
+
+// writeBuffered sends the buffered HTML, with the script injected before
+// its </body>, or at its end when final.
+func (w *liveReloadWriter) writeBuffered(final bool) {
+	if !w.streaming {
+		w.streaming = true
+		w.Header().Del("Content-Length")
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	body := w.buf.Bytes()
+	if !w.injected && (final || bytes.Contains(bytes.ToLower(body), []byte("</body>"))) {
+		body = injectLiveReloadScript(body, w.script)
+		w.injected = true
+	}
+	if len(body) > 0 {
+		_, _ = w.ResponseWriter.Write(body)
+	}
+	w.buf.Reset()
+}
+
+// injectLiveReloadScript inserts script before the last </body> tag, or
+// appends it when the document has none.
+func injectLiveReloadScript(body, script []byte) []byte {
+	idx := bytes.LastIndex(bytes.ToLower(body), []byte("</body>"))
+	if idx == -1 {
+		return append(body, script...)
+	}
+	out := make([]byte, 0, len(body)+len(script))
+	out = append(out, body[:idx]...)
+	out = append(out, script...)
+	return append(out, body[idx:]...)
+}
@@ -0,0 +1,156 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newLiveReloadTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv(LiveReloadPortEnv, "35729")
+
+	app := New()
+	app.DisableLogger()
+	app.Get("/page", func(c *Context) error {
+		c.Response.Header().Set("Content-Type", "text/html; charset=utf-8")
+		c.Response.Header().Set("Content-Length", "39")
+		_, err := c.Response.Write([]byte("<html><body><h1>Hi</h1></body></html>"))
+		return err
+	})
+	app.Get("/api", func(c *Context) error {
+		return c.JSON(200, map[string]string{"ok": "true"})
+	})
+	app.Mount()
+	return app
+}
+
+func TestLiveReload_InjectsIntoHTML(t *testing.T) {
+	app := newLiveReloadTestApp(t)
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/page", nil))
+
+	body := w.Body.String()
+	if !strings.Contains(body, `":35729/events"`) {
+		t.Fatalf("expected live reload script, got: %s", body)
+	}
+	if !strings.HasSuffix(body, "</script></body></html>") {
+		t.Errorf("expected script before </body>, got: %s", body)
+	}
+	if w.Header().Get("Content-Length") != "" {
+		t.Error("expected stale Content-Length to be removed")
+	}
+}
+
+func TestLiveReload_SkipsNonHTMLAndHTMX(t *testing.T) {
+	app := newLiveReloadTestApp(t)
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/api", nil))
+	if strings.Contains(w.Body.String(), "<script>") {
+		t.Errorf("expected JSON response untouched, got: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/page", nil)
+	r.Header.Set("HX-Request", "true")
+	app.ServeHTTP(w, r)
+	if strings.Contains(w.Body.String(), "<script>") {
+		t.Errorf("expected HTMX response untouched, got: %s", w.Body.String())
+	}
+}
+
+func TestLiveReload_SkipsCompressedHTML(t *testing.T) {
+	t.Setenv(LiveReloadPortEnv, "35729")
+	page := "<html><body>" + strings.Repeat("<p>compressed</p>", 200) + "</body></html>"
+
+	app := New()
+	app.DisableLogger()
+	app.Use(Compress())
+	app.Get("/page", func(c *Context) error {
+		return c.HTML(http.StatusOK, page)
+	})
+	app.Mount()
+
+	r := httptest.NewRequest("GET", "/page", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if body := decompress(t, w); body != page {
+		t.Errorf("expected the compressed page untouched, got %d bytes: %.80q", len(body), body)
+	}
+}
+
+func TestLiveReload_DisabledWithoutEnv(t *testing.T) {
+	t.Setenv(LiveReloadPortEnv, "")
+	if app := New(); app.liveReloadScript != nil {
+		t.Error("expected live reload to be disabled")
+	}
+}
+
+func TestInjectLiveReloadScript(t *testing.T) {
+	script := []byte("<script></script>")
+	tests := map[string]string{
+		"<body>a</body>":      "<body>a<script></script></body>",
+		"<BODY>a</BODY>":      "<BODY>a<script></script></BODY>",
+		"<div>fragment</div>": "<div>fragment</div><script></script>",
+	}
+	for in, want := range tests {
+		if got := string(injectLiveReloadScript([]byte(in), script)); got != want {
+			t.Errorf("injectLiveReloadScript(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLiveReload_FlushedHTML(t *testing.T) {
+	t.Setenv(LiveReloadPortEnv, "35729")
+
+	app := New()
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error {
+		c.Response.Header().Set("Content-Type", "text/html")
+		_, _ = c.Response.Write([]byte("<body>streamed</body>"))
+		c.Response.(http.Flusher).Flush()
+		return nil
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(w.Body.String(), "streamed<script>") {
+		t.Errorf("expected script injected after flush, got: %s", w.Body.String())
+	}
+}
+
+func TestLiveReload_StreamsFlushedHTML(t *testing.T) {
+	t.Setenv(LiveReloadPortEnv, "35729")
+
+	w := httptest.NewRecorder()
+	var flushed string
+	app := New()
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error {
+		c.Response.Header().Set("Content-Type", "text/html")
+		_, _ = c.Response.Write([]byte("<html><body><p>loading</p>"))
+		c.Response.(http.Flusher).Flush()
+		flushed = w.Body.String()
+		_, _ = c.Response.Write([]byte("<p>done</p></body></html>"))
+		return nil
+	})
+	app.Mount()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if flushed != "<html><body><p>loading</p>" {
+		t.Errorf("expected the flushed chunk to reach the client, got %q", flushed)
+	}
+	if body := w.Body.String(); !strings.HasSuffix(body, "<p>done</p>"+LiveReloadScript("35729")+"</body></html>") {
+		t.Errorf("expected the script before </body> of the last chunk, got %q", body)
+	}
+}
@@ -0,0 +1,239 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LoaderConfig caches the data a page's loader returns, per URL by default,
+// so pages backed by slow upstreams render from memory. A loader.go exports
+// one as LoaderConfig to have the generated routes cache its Loader.
+//
+// Example:
+//
+//	var LoaderConfig = nexo.LoaderConfig{
+//	    TTL:                  time.Minute,
+//	    StaleWhileRevalidate: true,
+//	}
+type LoaderConfig struct {
+	// TTL is how long loaded data is fresh. Default is 1 minute.
+	TTL time.Duration
+
+	// StaleWhileRevalidate serves data that went stale less than a TTL ago
+	// right away and runs the loader in the background to refresh it.
+	// Without it, requests for stale data wait for the loader.
+	StaleWhileRevalidate bool
+
+	// Key returns the cache key for a request. Default is the path and
+	// query string, e.g. "/products?page=2". Data that depends on anything
+	// else, such as the user, needs it in the key.
+	Key func(c *Context) string
+}
+
+// loaderCaches holds the cache of each LoaderConfig, by its address.
+var loaderCaches sync.Map // *LoaderConfig -> *loaderCache
+
+// loaderCache is the data a loader returned, by key.
+type loaderCache struct {
+	mu        sync.Mutex
+	entries   map[string]*loaderEntry
+	lastSweep time.Time
+}
+
+// loaderEntry is the data cached under a key, and the load refreshing it.
+type loaderEntry struct {
+	value  any
+	loaded time.Time // Zero until a load succeeds
+	call   *loaderCall
+}
+
+// loaderCall is a load in flight, which requests for the same key wait on
+// instead of running the loader again.
+type loaderCall struct {
+	done       chan struct{}
+	value      any
+	err        error
+	background bool
+}
+
+// LoadWithConfig returns the data loader returns for the request, cached
+// as config says. Loads for the same key run once, however many requests
+// wait on them, and failed loads aren't cached. Routes generated for a
+// loader.go with a LoaderConfig use it, and pages registered by hand can
+// too. config must be a package-level variable: its address identifies the
+// cache.
+//
+// Background refreshes run the loader with a copy of the request's Context
+// that outlives the request. Headers and cookies the loader sets there are
+// dropped.
+//
+// Example:
+//
+//	app.Get("/products", func(c *nexo.Context) error {
+//	    data, err := nexo.LoadWithConfig(c, &products.LoaderConfig, products.Loader)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return nexo.TemplComponent(c, 200, products.Page(data))
+//	})
+func LoadWithConfig[T any](c *Context, config *LoaderConfig, loader func(c *Context) (T, error)) (T, error) {
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	key := c.Request.URL.RequestURI()
+	if config.Key != nil {
+		key = config.Key(c)
+	}
+	load := func(c *Context) (any, error) {
+		return loader(c)
+	}
+
+	cache := loaderCacheFor(config)
+	cache.mu.Lock()
+	now := time.Now()
+	entry := cache.entries[key]
+	if entry == nil {
+		cache.sweep(now, ttl, config.StaleWhileRevalidate)
+		entry = &loaderEntry{}
+		cache.entries[key] = entry
+	}
+
+	if !entry.loaded.IsZero() {
+		age := now.Sub(entry.loaded)
+		if age < ttl || (config.StaleWhileRevalidate && age < 2*ttl) {
+			if age >= ttl && entry.call == nil {
+				entry.call = &loaderCall{done: make(chan struct{}), background: true}
+				go cache.run(key, entry.call, detachedContext(c), load)
+			}
+			value, _ := entry.value.(T)
+			cache.mu.Unlock()
+			return value, nil
+		}
+	}
+
+	call := entry.call
+	if call == nil {
+		call = &loaderCall{done: make(chan struct{})}
+		entry.call = call
+		cache.mu.Unlock()
+		cache.run(key, call, c, load)
+	} else {
+		cache.mu.Unlock()
+		<-call.done
+	}
+
+	var zero T
+	if call.err != nil {
+		return zero, call.err
+	}
+	value, _ := call.value.(T)
+	return value, nil
+}
+
+// Invalidate drops the data cached under keys, or all of it without keys,
+// so the next requests run the loader. Actions call it after changing what
+// a page shows.
+//
+// Example:
+//
+//	products.LoaderConfig.Invalidate("/products")
+func (config *LoaderConfig) Invalidate(keys ...string) {
+	cache := loaderCacheFor(config)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	// Loads in flight finish for the requests waiting on them, but their
+	// data isn't cached
+	if len(keys) == 0 {
+		clear(cache.entries)
+	}
+	for _, key := range keys {
+		delete(cache.entries, key)
+	}
+}
+
+// loaderCacheFor returns the cache of config.
+func loaderCacheFor(config *LoaderConfig) *loaderCache {
+	if cache, ok := loaderCaches.Load(config); ok {
+		return cache.(*loaderCache)
+	}
+	cache, _ := loaderCaches.LoadOrStore(config, &loaderCache{entries: make(map[string]*loaderEntry)})
+	return cache.(*loaderCache)
+}
+
+// run runs load for call and caches its result. A panic fails the call,
+// and is passed on unless the call runs in the background.
+func (lc *loaderCache) run(key string, call *loaderCall, c *Context, load func(c *Context) (any, error)) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			call.err = fmt.Errorf("loader panic: %v", r)
+		}
+		lc.finish(key, call)
+		if r != nil && !call.background {
+			panic(r)
+		}
+	}()
+	call.value, call.err = load(c)
+}
+
+// finish caches the result of call and releases the requests waiting on it.
+func (lc *loaderCache) finish(key string, call *loaderCall) {
+	lc.mu.Lock()
+	if entry := lc.entries[key]; entry != nil && entry.call == call {
+		entry.call = nil
+		switch {
+		case call.err == nil:
+			entry.value = call.value
+			entry.loaded = time.Now()
+		case entry.loaded.IsZero():
+			delete(lc.entries, key)
+		}
+	}
+	lc.mu.Unlock()
+	close(call.done)
+}
+
+// sweep drops the entries that can't be served anymore, at most once a
+// minute. The caller holds lc.mu.
+func (lc *loaderCache) sweep(now time.Time, ttl time.Duration, stale bool) {
+	if now.Sub(lc.lastSweep) <= time.Minute {
+		return
+	}
+	maxAge := ttl
+	if stale {
+		maxAge = 2 * ttl
+	}
+	for key, entry := range lc.entries {
+		if entry.call == nil && now.Sub(entry.loaded) >= maxAge {
+			delete(lc.entries, key)
+		}
+	}
+	lc.lastSweep = now
+}
+
+// detachedContext returns a copy of c for loaders refreshing in the
+// background: its request isn't canceled when c's is, and its response is
+// discarded.
+func detachedContext(c *Context) *Context {
+	bg := NewContext(discardResponse{header: make(http.Header)}, c.Request.Clone(context.WithoutCancel(c.Context())))
+	maps.Copy(bg.params, c.params)
+	maps.Copy(bg.store, c.store)
+	if c.keyed != nil {
+		bg.keyed = maps.Clone(c.keyed)
+	}
+	return bg
+}
+
+// discardResponse is the response of a detachedContext.
+type discardResponse struct {
+	header http.Header
+}
+
+func (w discardResponse) Header() http.Header         { return w.header }
+func (w discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (w discardResponse) WriteHeader(int)             {}
@@ -0,0 +1,170 @@
+package nexo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// age makes the data cached under key for config look loaded d ago.
+func age(config *LoaderConfig, key string, d time.Duration) {
+	cache := loaderCacheFor(config)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[key].loaded = time.Now().Add(-d)
+}
+
+func TestLoadWithConfig(t *testing.T) {
+	var calls atomic.Int32
+	loader := func(c *Context) (int, error) {
+		return int(calls.Add(1)), nil
+	}
+	load := func(config *LoaderConfig, target string) int {
+		t.Helper()
+		c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		v, err := LoadWithConfig(c, config, loader)
+		if err != nil {
+			t.Fatalf("LoadWithConfig(%s) error = %v", target, err)
+		}
+		return v
+	}
+
+	t.Run("per URL", func(t *testing.T) {
+		calls.Store(0)
+		config := &LoaderConfig{}
+		if v := load(config, "/products?page=1"); v != 1 {
+			t.Errorf("first load = %d, want 1", v)
+		}
+		if v := load(config, "/products?page=1"); v != 1 {
+			t.Errorf("cached load = %d, want 1", v)
+		}
+		if v := load(config, "/products?page=2"); v != 2 {
+			t.Errorf("load for another URL = %d, want 2", v)
+		}
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		calls.Store(0)
+		config := &LoaderConfig{Key: func(c *Context) string { return c.Request.URL.Path }}
+		load(config, "/products?page=1")
+		if v := load(config, "/products?page=2"); v != 1 {
+			t.Errorf("load with the same key = %d, want 1", v)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		calls.Store(0)
+		config := &LoaderConfig{TTL: time.Minute}
+		load(config, "/products")
+		age(config, "/products", time.Minute)
+		if v := load(config, "/products"); v != 2 {
+			t.Errorf("load of stale data = %d, want 2", v)
+		}
+	})
+
+	t.Run("stale while revalidate", func(t *testing.T) {
+		calls.Store(0)
+		config := &LoaderConfig{TTL: time.Minute, StaleWhileRevalidate: true}
+		load(config, "/products")
+		age(config, "/products", 90*time.Second)
+		if v := load(config, "/products"); v != 1 {
+			t.Errorf("load of stale data = %d, want the stale 1", v)
+		}
+
+		// Wait for the refresh in the background
+		cache := loaderCacheFor(config)
+		cache.mu.Lock()
+		call := cache.entries["/products"].call
+		cache.mu.Unlock()
+		if call == nil {
+			t.Fatal("expected a refresh in the background")
+		}
+		<-call.done
+		if v := load(config, "/products"); v != 2 {
+			t.Errorf("load after the refresh = %d, want 2", v)
+		}
+
+		// Data stale for more than another TTL isn't served
+		age(config, "/products", 2*time.Minute)
+		if v := load(config, "/products"); v != 3 {
+			t.Errorf("load of expired data = %d, want 3", v)
+		}
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		calls.Store(0)
+		config := &LoaderConfig{}
+		load(config, "/a")
+		load(config, "/b")
+		config.Invalidate("/a")
+		if v := load(config, "/a"); v != 3 {
+			t.Errorf("load after Invalidate = %d, want 3", v)
+		}
+		if v := load(config, "/b"); v != 2 {
+			t.Errorf("load of another key after Invalidate = %d, want 2", v)
+		}
+		config.Invalidate()
+		if v := load(config, "/b"); v != 4 {
+			t.Errorf("load after Invalidate() = %d, want 4", v)
+		}
+	})
+}
+
+func TestLoadWithConfig_Errors(t *testing.T) {
+	config := &LoaderConfig{}
+	fail := true
+	loader := func(c *Context) (string, error) {
+		if fail {
+			return "", errors.New("upstream down")
+		}
+		return "ok", nil
+	}
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if _, err := LoadWithConfig(c, config, loader); err == nil {
+		t.Fatal("expected the loader's error")
+	}
+	fail = false
+	if v, err := LoadWithConfig(c, config, loader); err != nil || v != "ok" {
+		t.Errorf("LoadWithConfig() = %q, %v; want the error not to be cached", v, err)
+	}
+}
+
+func TestLoadWithConfig_Concurrent(t *testing.T) {
+	config := &LoaderConfig{}
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(c *Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+			results[i], _ = LoadWithConfig(c, config, loader)
+		}()
+	}
+	// Let the requests pile up on the first load
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader ran %d times, want 1", n)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("result %d = %d, want 42", i, v)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package nexo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLogFileMaxSize is the size at which a RotatingFile is rotated
+// unless RotatingFileConfig.MaxSize is set.
+const DefaultLogFileMaxSize = 100 << 20 // 100MB
+
+// backupTimeFormat is the timestamp in the names of rotated log files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFileConfig holds configuration for a RotatingFile.
+type RotatingFileConfig struct {
+	// Filename is the file to write logs to. Its directory is created if
+	// needed. Rotated files are kept next to it as name-<timestamp>.ext.
+	Filename string
+
+	// MaxSize is the size in bytes at which the file is rotated.
+	// Default: DefaultLogFileMaxSize.
+	MaxSize int64
+
+	// MaxAge removes rotated files older than this. Zero keeps them
+	// regardless of age.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files to keep. Zero keeps all.
+	MaxBackups int
+}
+
+// RotatingFile is a log file that is rotated when it reaches a maximum
+// size. Use it as RequestLoggerConfig.Output to log to disk:
+//
+//	file, err := nexo.NewRotatingFile(nexo.RotatingFileConfig{
+//	    Filename:   "logs/access.log",
+//	    MaxSize:    50 << 20,
+//	    MaxAge:     7 * 24 * time.Hour,
+//	    MaxBackups: 10,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer file.Close()
+//
+//	app.SetLogger(nexo.RequestLoggerConfig{Output: file})
+//
+// It's safe for concurrent use.
+type RotatingFile struct {
+	config RotatingFileConfig
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens config.Filename for appending, creating it if it
+// doesn't exist.
+func NewRotatingFile(config RotatingFileConfig) (*RotatingFile, error) {
+	if config.Filename == "" {
+		return nil, fmt.Errorf("log file name cannot be empty")
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultLogFileMaxSize
+	}
+
+	f := &RotatingFile{config: config}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write writes p to the file, rotating it first if p would take it past
+// MaxSize.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current file, renames it with a timestamp and opens a
+// new one, e.g. in response to SIGHUP.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the log file for appending.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.config.Filename), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and opens a new one. The caller
+// must hold f.mu.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	if err := os.Rename(f.config.Filename, f.backupName(time.Now())); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.removeOldBackups()
+	return nil
+}
+
+// backupName returns the name of the file rotated at t.
+func (f *RotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.config.Filename)
+	base := strings.TrimSuffix(f.config.Filename, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.Format(backupTimeFormat), ext)
+}
+
+// removeOldBackups deletes rotated files beyond MaxBackups or older than
+// MaxAge. Errors are ignored; they'll be retried on the next rotation.
+func (f *RotatingFile) removeOldBackups() {
+	if f.config.MaxBackups <= 0 && f.config.MaxAge <= 0 {
+		return
+	}
+
+	dir := filepath.Dir(f.config.Filename)
+	ext := filepath.Ext(f.config.Filename)
+	prefix := strings.TrimSuffix(filepath.Base(f.config.Filename), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		name string
+		time time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{name: name, time: t})
+	}
+
+	// Newest first
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	cutoff := time.Now().Add(-f.config.MaxAge)
+	for i, b := range backups {
+		tooMany := f.config.MaxBackups > 0 && i >= f.config.MaxBackups
+		tooOld := f.config.MaxAge > 0 && b.time.Before(cutoff)
+		if tooMany || tooOld {
+			_ = os.Remove(filepath.Join(dir, b.name))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package nexo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_RotatesAtMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "logs", "access.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Filename: name, MaxSize: 10})
+	if err != nil {
+		t.Fatalf("NewRotatingFile failed: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second\n" {
+		t.Errorf("Expected the current file to hold the last write, got %q", data)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "logs", "access-*.log"))
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup, got %v", backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != "first\n" {
+		t.Errorf("Expected the backup to hold the first write, got %q", data)
+	}
+}
+
+func TestRotatingFile_Appends(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(name, []byte("existing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewRotatingFile(RotatingFileConfig{Filename: name})
+	if err != nil {
+		t.Fatalf("NewRotatingFile failed: %v", err)
+	}
+	_, _ = f.Write([]byte("new\n"))
+	_ = f.Close()
+
+	if data, _ := os.ReadFile(name); string(data) != "existing\nnew\n" {
+		t.Errorf("Expected writes to be appended, got %q", data)
+	}
+	if _, err := f.Write([]byte("closed\n")); err == nil {
+		t.Error("Expected an error writing to a closed file")
+	}
+}
+
+func TestRotatingFile_RemovesOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+
+	old := time.Now().Add(-48 * time.Hour)
+	for i := 0; i < 3; i++ {
+		backup := filepath.Join(dir, "app-"+old.Add(time.Duration(i)*time.Minute).Format(backupTimeFormat)+".log")
+		if err := os.WriteFile(backup, []byte("old\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recent := filepath.Join(dir, "app-"+time.Now().Add(-time.Hour).Format(backupTimeFormat)+".log")
+	if err := os.WriteFile(recent, []byte("recent\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Not a backup: must be left alone
+	other := filepath.Join(dir, "app-notes.log")
+	if err := os.WriteFile(other, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewRotatingFile(RotatingFileConfig{Filename: name, MaxAge: 24 * time.Hour, MaxBackups: 1})
+	if err != nil {
+		t.Fatalf("NewRotatingFile failed: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.Write([]byte("current\n"))
+	if err := f.Rotate(); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	var kept []string
+	for _, b := range backups {
+		kept = append(kept, filepath.Base(b))
+	}
+	if len(kept) != 2 || !strings.Contains(strings.Join(kept, " "), "app-notes.log") {
+		t.Errorf("Expected the newest backup and app-notes.log to remain, got %v", kept)
+	}
+	if _, err := os.Stat(recent); !os.IsNotExist(err) {
+		t.Error("Expected the older backup beyond MaxBackups to be removed")
+	}
+}
@@ -1,15 +1,21 @@
 package nexo
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/go-chi/chi/v5"
 	"github.com/mattn/go-isatty"
 )
 
@@ -72,11 +78,26 @@ type RequestLoggerConfig struct {
 	ShowTimestamp   bool // Show [HH:MM:SS] timestamp (default: true)
 	ShowIP          bool // Show client IP (default: false)
 	ShowUserAgent   bool // Show user agent (default: false)
+	ShowQuery       bool // Show the query string after the path (default: false)
 	ShowErrors      bool // Show error details inline (default: true)
 	ShowProxyAction bool // Show proxy action tags (default: true)
 	ShowSize        bool // Show response size (default: true)
+	ShowRequestID   bool // Show the request ID set by the RequestID middleware (default: true)
+
+	// ShowHeaders lists request headers to show, e.g. "Referer" (default: none).
+	ShowHeaders []string
+
+	// Redact lists the header, query parameter and JSON error fields whose
+	// values are replaced before logging. Empty lists use the ones in
+	// DefaultRedactConfig, which cover common credentials.
+	Redact RedactConfig
+
+	// RequestIDHeader is the response header holding the request ID.
+	// Default: "X-Request-ID".
+	RequestIDHeader string
 
 	// Formatting
+	Format          string // "text" (default) or "json"
 	TimeUnit        string // "ms" (default), "us", or "auto"
 	TimestampFormat string // "15:04:05" (default)
 
@@ -86,12 +107,35 @@ type RequestLoggerConfig struct {
 	SkipStatic  bool     // Skip static files (default: false)
 	StaticPaths []string // Paths considered static
 
+	// Sampling
+	SampleRate    int           // Log 1 in SampleRate successful (1xx-3xx) requests (default: 0, log all)
+	SlowThreshold time.Duration // Always log requests at least this slow, even if not sampled (default: 0)
+
+	// Output is where request logs are written, e.g. a RotatingFile.
+	// Default: the log package's output.
+	Output io.Writer
+
 	// Colors
 	DisableColors bool // Force disable colors (default: false, auto-detected)
 
 	// MaxErrorLength is the maximum length for error messages in logs.
 	// Messages longer than this are truncated. Default: 100.
 	MaxErrorLength int
+
+	// Formatter, if set, formats each request log line instead of Format.
+	// The line is written to Output as is; return "" to skip the request.
+	// Ignored if Slog is set.
+	Formatter func(LogEntry) string
+
+	// OnLog, if set, is called with each request that is logged, e.g. to
+	// feed an audit trail. Requests filtered out by Level, SkipPaths,
+	// SkipStatic or SampleRate are not passed to it.
+	OnLog func(LogEntry)
+
+	// Slog, if set, receives request logs as structured records instead of
+	// Format, along with the app's diagnostics (server start and shutdown,
+	// recovered panics). Use slog.New to wrap a slog.Handler.
+	Slog *slog.Logger
 }
 
 // DefaultRequestLoggerConfig returns sensible defaults for the request logger.
@@ -110,12 +154,19 @@ func DefaultRequestLoggerConfig() RequestLoggerConfig {
 		}
 	}
 
+	format := "text"
+	if envFormat := os.Getenv("NEXO_LOG_FORMAT"); envFormat != "" {
+		format = strings.ToLower(envFormat)
+	}
+
 	return RequestLoggerConfig{
+		Format:          format,
 		Compact:         true,
 		ShowTimestamp:   true,
 		ShowErrors:      true,
 		ShowProxyAction: true,
 		ShowSize:        true,
+		ShowRequestID:   true,
 		TimeUnit:        "ms",
 		TimestampFormat: "15:04:05",
 		Level:           level,
@@ -128,6 +179,12 @@ func DefaultRequestLoggerConfig() RequestLoggerConfig {
 type RequestLogger struct {
 	config RequestLoggerConfig
 
+	// out writes text logs to config.Output; nil uses the log package.
+	out *log.Logger
+
+	// sampled counts successful requests for SampleRate.
+	sampled atomic.Uint64
+
 	// Color functions
 	methodColors map[string]func(a ...interface{}) string
 	statusColors map[int]func(a ...interface{}) string
@@ -140,18 +197,27 @@ type RequestLogger struct {
 func NewRequestLogger(config RequestLoggerConfig) *RequestLogger {
 	// Auto-detect TTY for color support
 	if !config.DisableColors {
-		config.DisableColors = !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd())
+		config.DisableColors = !isTerminal(config.Output)
 	}
 
 	if config.DisableColors {
 		color.NoColor = true
 	}
 
+	if config.RequestIDHeader == "" {
+		config.RequestIDHeader = "X-Request-ID"
+	}
+	config.Redact = config.Redact.withDefaults()
+
 	rl := &RequestLogger{
 		config:       config,
 		methodColors: make(map[string]func(a ...interface{}) string),
 	}
 
+	if config.Output != nil {
+		rl.out = log.New(config.Output, "", log.LstdFlags)
+	}
+
 	// Set up method colors
 	rl.methodColors[http.MethodGet] = color.New(color.FgBlue).SprintFunc()
 	rl.methodColors[http.MethodPost] = color.New(color.FgGreen).SprintFunc()
@@ -172,6 +238,15 @@ func NewRequestLogger(config RequestLoggerConfig) *RequestLogger {
 	return rl
 }
 
+// isTerminal reports whether w is a terminal. A nil w stands for stdout.
+func isTerminal(w io.Writer) bool {
+	if w == nil {
+		w = os.Stdout
+	}
+	f, ok := w.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
+
 // getMethodColor returns the color function for a given HTTP method.
 func (rl *RequestLogger) getMethodColor(method string) func(a ...interface{}) string {
 	if colorFunc, ok := rl.methodColors[method]; ok {
@@ -227,6 +302,18 @@ func (rl *RequestLogger) ShouldLog(path string, status int) bool {
 	return true
 }
 
+// sample reports whether a request passes SampleRate. Errors (4xx and 5xx)
+// and requests slower than SlowThreshold are always logged.
+func (rl *RequestLogger) sample(status int, latency time.Duration) bool {
+	if rl.config.SampleRate <= 1 || status >= 400 {
+		return true
+	}
+	if rl.config.SlowThreshold > 0 && latency >= rl.config.SlowThreshold {
+		return true
+	}
+	return (rl.sampled.Add(1)-1)%uint64(rl.config.SampleRate) == 0
+}
+
 // isStaticPath checks if a path is a static file path.
 func (rl *RequestLogger) isStaticPath(path string) bool {
 	// Check configured static paths
@@ -278,6 +365,20 @@ func (rl *RequestLogger) formatLatency(d time.Duration) string {
 	}
 }
 
+// formatStreamDuration formats how long a stream was open, e.g. "850ms",
+// "12.4s" or "3m05s".
+func formatStreamDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+}
+
 // formatSize formats the response size in a human-readable format.
 func (rl *RequestLogger) formatSize(size int64) string {
 	if size < 1024 {
@@ -332,6 +433,9 @@ func (rl *RequestLogger) formatError(err error) string {
 		msg = err.Error()
 	}
 
+	// Redact credentials in JSON error bodies
+	msg = string(rl.config.Redact.RedactJSON([]byte(msg)))
+
 	// Skip if it looks like body content
 	if looksLikeBody(msg) {
 		return ""
@@ -355,12 +459,78 @@ type ProxyAction struct {
 	Target string // URL for rewrite/redirect
 }
 
-// Log logs a request with the given parameters.
+// LogEntry is a logged request, passed to RequestLoggerConfig.Formatter
+// and OnLog.
+type LogEntry struct {
+	Time        time.Time
+	Method      string
+	Path        string
+	Query       string      // Raw query string, with Redact.QueryParams redacted
+	Header      http.Header // Request headers, with Redact.Headers redacted
+	Status      int
+	Size        int64
+	Latency     time.Duration // How long a stream was open, if Stream is true
+	IP          string
+	UserAgent   string
+	RequestID   string
+	ProxyAction *ProxyAction // nil if there's no proxy
+	Stream      bool         // Whether the response was a Server-Sent Events stream
+	Error       error
+	Request     *http.Request
+}
+
+// Log logs a request with the given parameters. The request ID is read
+// from the request's RequestIDHeader, if any.
 func (rl *RequestLogger) Log(r *http.Request, status int, size int64, latency time.Duration, proxyAction *ProxyAction, err error) {
+	rl.log(r, status, size, latency, proxyAction, err, r.Header.Get(rl.config.RequestIDHeader), false)
+}
+
+// log logs a request. Streams (Server-Sent Events) are tagged [sse] and
+// logged with how long they stayed open.
+func (rl *RequestLogger) log(r *http.Request, status int, size int64, latency time.Duration, proxyAction *ProxyAction, err error, requestID string, stream bool) {
 	path := r.URL.Path
 
 	// Check if we should log this request
-	if !rl.ShouldLog(path, status) {
+	if !rl.ShouldLog(path, status) || !rl.sample(status, latency) {
+		return
+	}
+
+	if rl.config.OnLog != nil || rl.config.Formatter != nil {
+		entry := LogEntry{
+			Time:        time.Now(),
+			Method:      r.Method,
+			Path:        path,
+			Query:       rl.config.Redact.RedactQuery(r.URL.RawQuery),
+			Header:      rl.config.Redact.RedactHeader(r.Header),
+			Status:      status,
+			Size:        size,
+			Latency:     latency,
+			IP:          getClientIP(r),
+			UserAgent:   r.UserAgent(),
+			RequestID:   requestID,
+			ProxyAction: proxyAction,
+			Stream:      stream,
+			Error:       err,
+			Request:     r,
+		}
+		if rl.config.OnLog != nil {
+			rl.config.OnLog(entry)
+		}
+		if rl.config.Formatter != nil && rl.config.Slog == nil {
+			if line := rl.config.Formatter(entry); line != "" {
+				rl.writeLine(line)
+			}
+			return
+		}
+	}
+
+	if rl.config.Slog != nil {
+		rl.logSlog(r, status, size, latency, proxyAction, err, requestID, stream)
+		return
+	}
+
+	if rl.config.Format == "json" {
+		rl.logJSON(r, status, size, latency, proxyAction, err, requestID, stream)
 		return
 	}
 
@@ -381,13 +551,13 @@ func (rl *RequestLogger) Log(r *http.Request, status int, size int64, latency ti
 	// Path (with optional rewrite indicator)
 	if proxyAction != nil && proxyAction.Type == "rewrite" && proxyAction.Target != "" {
 		// Show original path → rewritten path
-		msg.WriteString(path)
+		msg.WriteString(rl.logPath(r))
 		msg.WriteString(" ")
 		msg.WriteString(rl.dim("→"))
 		msg.WriteString(" ")
 		msg.WriteString(proxyAction.Target)
 	} else {
-		msg.WriteString(path)
+		msg.WriteString(rl.logPath(r))
 	}
 	msg.WriteString(" ")
 
@@ -396,9 +566,14 @@ func (rl *RequestLogger) Log(r *http.Request, status int, size int64, latency ti
 	msg.WriteString(statusColor(fmt.Sprintf("%d", status)))
 	msg.WriteString(" ")
 
-	// Latency
-	msg.WriteString(rl.dim("in "))
-	msg.WriteString(rl.formatLatency(latency))
+	// Latency, or how long a stream was open
+	if stream {
+		msg.WriteString(rl.dim("open "))
+		msg.WriteString(formatStreamDuration(latency))
+	} else {
+		msg.WriteString(rl.dim("in "))
+		msg.WriteString(rl.formatLatency(latency))
+	}
 
 	// Size (optional)
 	if rl.config.ShowSize && size > 0 {
@@ -406,6 +581,11 @@ func (rl *RequestLogger) Log(r *http.Request, status int, size int64, latency ti
 		msg.WriteString(rl.dim(fmt.Sprintf("(%s)", rl.formatSize(size))))
 	}
 
+	if stream {
+		msg.WriteString(" ")
+		msg.WriteString(rl.cyan("[sse]"))
+	}
+
 	// Proxy action tag (optional)
 	if rl.config.ShowProxyAction && proxyAction != nil {
 		switch proxyAction.Type {
@@ -438,6 +618,22 @@ func (rl *RequestLogger) Log(r *http.Request, status int, size int64, latency ti
 		msg.WriteString(rl.dim(fmt.Sprintf("[%s]", ua)))
 	}
 
+	// Request headers (optional)
+	headers := rl.logHeaders(r)
+	for _, name := range rl.config.ShowHeaders {
+		name = http.CanonicalHeaderKey(name)
+		if value, ok := headers[name]; ok {
+			msg.WriteString(" ")
+			msg.WriteString(rl.dim(fmt.Sprintf("[%s: %s]", name, value)))
+		}
+	}
+
+	// Request ID (optional)
+	if rl.config.ShowRequestID && requestID != "" {
+		msg.WriteString(" ")
+		msg.WriteString(rl.dim(fmt.Sprintf("[%s]", requestID)))
+	}
+
 	// Error (optional)
 	if rl.config.ShowErrors && err != nil {
 		errMsg := rl.formatError(err)
@@ -448,7 +644,197 @@ func (rl *RequestLogger) Log(r *http.Request, status int, size int64, latency ti
 	}
 
 	// Print the log message
-	log.Println(msg.String())
+	if rl.out != nil {
+		rl.out.Println(msg.String())
+	} else {
+		log.Println(msg.String())
+	}
+}
+
+// jsonLogEntry is a request log line in the "json" format.
+type jsonLogEntry struct {
+	Time        string            `json:"ts"`
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	Status      int               `json:"status"`
+	Bytes       int64             `json:"bytes"`
+	LatencyMS   float64           `json:"latency_ms"`
+	IP          string            `json:"ip"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	RequestID   string            `json:"request_id,omitempty"`
+	ProxyAction string            `json:"proxy_action,omitempty"`
+	ProxyTarget string            `json:"proxy_target,omitempty"`
+	Stream      bool              `json:"stream,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// logJSON writes a request as a single-line JSON object, for log
+// collectors such as Loki or Datadog.
+func (rl *RequestLogger) logJSON(r *http.Request, status int, size int64, latency time.Duration, proxyAction *ProxyAction, err error, requestID string, stream bool) {
+	entry := jsonLogEntry{
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Method:    r.Method,
+		Path:      rl.logPath(r),
+		Status:    status,
+		Bytes:     size,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+		IP:        getClientIP(r),
+		Headers:   rl.logHeaders(r),
+		RequestID: requestID,
+		Stream:    stream,
+	}
+	if proxyAction != nil && proxyAction.Type != "continue" {
+		entry.ProxyAction = proxyAction.Type
+		entry.ProxyTarget = proxyAction.Target
+	}
+	if err != nil {
+		entry.Error = rl.formatError(err)
+	}
+
+	line, jsonErr := json.Marshal(entry)
+	if jsonErr != nil {
+		return
+	}
+	rl.writeLine(string(line))
+}
+
+// logPath returns the path to log for r, with the redacted query string if
+// ShowQuery is set.
+func (rl *RequestLogger) logPath(r *http.Request) string {
+	if !rl.config.ShowQuery || r.URL.RawQuery == "" {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + rl.config.Redact.RedactQuery(r.URL.RawQuery)
+}
+
+// logHeaders returns the ShowHeaders that r has, redacted, or nil.
+func (rl *RequestLogger) logHeaders(r *http.Request) map[string]string {
+	var headers map[string]string
+	for _, name := range rl.config.ShowHeaders {
+		name = http.CanonicalHeaderKey(name)
+		values := r.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		if containsFold(rl.config.Redact.Headers, name) {
+			headers[name] = rl.config.Redact.Replacement
+		} else {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	return headers
+}
+
+// writeLine writes a log line to Output, or the log package's output,
+// without the log prefix.
+func (rl *RequestLogger) writeLine(line string) {
+	out := rl.config.Output
+	if out == nil {
+		out = log.Writer()
+	}
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	_, _ = io.WriteString(out, line)
+}
+
+// statusLevel returns the slog level for a request log: LevelError for 5xx
+// responses, LevelWarn for 4xx and LevelInfo otherwise.
+func statusLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// logSlog writes a request to the configured slog.Logger.
+func (rl *RequestLogger) logSlog(r *http.Request, status int, size int64, latency time.Duration, proxyAction *ProxyAction, err error, requestID string, stream bool) {
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", rl.logPath(r)),
+		slog.Int("status", status),
+		slog.Int64("bytes", size),
+		slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+		slog.String("ip", getClientIP(r)),
+	}
+	if headers := rl.logHeaders(r); headers != nil {
+		attrs = append(attrs, slog.Any("headers", headers))
+	}
+	if requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if proxyAction != nil && proxyAction.Type != "continue" {
+		attrs = append(attrs, slog.String("proxy_action", proxyAction.Type))
+		if proxyAction.Target != "" {
+			attrs = append(attrs, slog.String("proxy_target", proxyAction.Target))
+		}
+	}
+	if stream {
+		attrs = append(attrs, slog.Bool("stream", true))
+	}
+	if errMsg := rl.formatError(err); errMsg != "" {
+		attrs = append(attrs, slog.String("error", errMsg))
+	}
+
+	rl.config.Slog.LogAttrs(r.Context(), statusLevel(status), "request", attrs...)
+}
+
+// slogKey is the request context key for the app's slog.Logger.
+type slogKey struct{}
+
+// withSlog returns r with the app's slog.Logger in its context.
+func withSlog(r *http.Request, l *slog.Logger) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), slogKey{}, l))
+}
+
+// slogFromRequest returns the app's slog.Logger, or nil if it has none.
+func slogFromRequest(r *http.Request) *slog.Logger {
+	l, _ := r.Context().Value(slogKey{}).(*slog.Logger)
+	return l
+}
+
+// Logger returns a slog.Logger for the request, tagged with its request ID
+// (with the RequestID middleware), method and route pattern, so log lines
+// written by handlers can be matched to the request log. It writes to the
+// app's RequestLoggerConfig.Slog, or slog.Default if there's none.
+//
+// Example:
+//
+//	c.Logger().Info("user created", "user_id", user.ID)
+//	// level=INFO msg="user created" request_id=0192f1c4-... method=POST route=/api/users user_id=42
+func (c *Context) Logger() *slog.Logger {
+	l := slogFromRequest(c.Request)
+	if l == nil {
+		l = slog.Default()
+	}
+
+	args := make([]any, 0, 6)
+	if id := c.RequestID(); id != "" {
+		args = append(args, "request_id", id)
+	}
+	args = append(args, "method", c.Method())
+	if rctx := chi.RouteContext(c.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		args = append(args, "route", rctx.RoutePattern())
+	}
+	return l.With(args...)
+}
+
+// diagnostic reports a framework event, such as the server stopping. It's
+// logged as msg with args if l is set, and otherwise text is printed to
+// stdout.
+func diagnostic(l *slog.Logger, level slog.Level, text string, msg string, args ...any) {
+	if l != nil {
+		l.Log(context.Background(), level, msg, args...)
+		return
+	}
+	fmt.Print(text)
 }
 
 // getClientIP extracts the client IP from the request.
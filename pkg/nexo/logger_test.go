@@ -2,8 +2,10 @@ package nexo
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -130,6 +132,18 @@ func TestDefaultRequestLoggerConfig(t *testing.T) {
 			t.Errorf("Expected error level from env, got %v", config.Level)
 		}
 	})
+
+	t.Run("NEXO_LOG_FORMAT sets format", func(t *testing.T) {
+		if config := DefaultRequestLoggerConfig(); config.Format != "text" {
+			t.Errorf("Expected text format by default, got %q", config.Format)
+		}
+
+		t.Setenv("NEXO_LOG_FORMAT", "JSON")
+
+		if config := DefaultRequestLoggerConfig(); config.Format != "json" {
+			t.Errorf("Expected json format from env, got %q", config.Format)
+		}
+	})
 }
 
 func TestRequestLogger_ShouldLog(t *testing.T) {
@@ -664,3 +678,207 @@ func TestRequestLogger_Log_SmallJSONIsLogged(t *testing.T) {
 		t.Error("Log output should contain small JSON error message")
 	}
 }
+
+func TestRequestLogger_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.SetLogger(RequestLoggerConfig{DisableColors: true, Level: LogLevelInfo})
+	app.Get("/events", func(c *Context) error {
+		sse, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		return sse.Send("message", "hello")
+	})
+	app.Mount()
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	output := buf.String()
+	if !strings.Contains(output, "GET /events 200 open ") || !strings.Contains(output, "[sse]") {
+		t.Errorf("Expected SSE log line, got: %s", output)
+	}
+}
+
+func TestRequestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	rl := NewRequestLogger(RequestLoggerConfig{Format: "json", Level: LogLevelInfo})
+
+	r := httptest.NewRequest(http.MethodGet, "/old", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Request-ID", "req-1")
+	rl.Log(r, 500, 1234, 1500*time.Microsecond, &ProxyAction{Type: "rewrite", Target: "/new"}, fmt.Errorf("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected one log line, got %d: %s", len(lines), buf.String())
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected a JSON object, got %q: %v", lines[0], err)
+	}
+
+	want := map[string]any{
+		"method":       "GET",
+		"path":         "/old",
+		"status":       float64(500),
+		"bytes":        float64(1234),
+		"latency_ms":   1.5,
+		"ip":           "10.0.0.1",
+		"request_id":   "req-1",
+		"proxy_action": "rewrite",
+		"proxy_target": "/new",
+		"error":        "boom",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, entry[key])
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, fmt.Sprint(entry["ts"])); err != nil {
+		t.Errorf("Expected an RFC 3339 ts, got %v", entry["ts"])
+	}
+}
+
+func TestRequestLogger_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	rl := NewRequestLogger(RequestLoggerConfig{
+		Level:         LogLevelInfo,
+		Output:        &buf,
+		SampleRate:    3,
+		SlowThreshold: time.Second,
+	})
+
+	for i := 0; i < 6; i++ {
+		rl.Log(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/ok/%d", i), nil), 200, 0, time.Millisecond, nil, nil)
+	}
+	rl.Log(httptest.NewRequest(http.MethodGet, "/missing", nil), 404, 0, time.Millisecond, nil, nil)
+	rl.Log(httptest.NewRequest(http.MethodGet, "/fail", nil), 500, 0, time.Millisecond, nil, nil)
+	rl.Log(httptest.NewRequest(http.MethodGet, "/slow", nil), 200, 0, 2*time.Second, nil, nil)
+
+	output := buf.String()
+	for _, want := range []string{"/ok/0 ", "/ok/3 ", "/missing", "/fail", "/slow"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s to be logged, got:\n%s", want, output)
+		}
+	}
+	for _, skipped := range []string{"/ok/1 ", "/ok/2 ", "/ok/4 ", "/ok/5 "} {
+		if strings.Contains(output, skipped) {
+			t.Errorf("Expected %s to be sampled out, got:\n%s", skipped, output)
+		}
+	}
+}
+
+func TestRequestLogger_Formatter(t *testing.T) {
+	var buf bytes.Buffer
+	var entries []LogEntry
+	rl := NewRequestLogger(RequestLoggerConfig{
+		Level:  LogLevelInfo,
+		Output: &buf,
+		Formatter: func(e LogEntry) string {
+			if e.Path == "/health" {
+				return ""
+			}
+			return fmt.Sprintf("%s %s status=%d rid=%s", e.Method, e.Path, e.Status, e.RequestID)
+		},
+		OnLog: func(e LogEntry) {
+			entries = append(entries, e)
+		},
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r.Header.Set("X-Request-ID", "req-1")
+	rl.Log(r, 201, 10, time.Millisecond, nil, nil)
+	rl.Log(httptest.NewRequest(http.MethodGet, "/health", nil), 200, 0, time.Millisecond, nil, nil)
+
+	if buf.String() != "POST /users status=201 rid=req-1\n" {
+		t.Errorf("Expected the formatted line only, got %q", buf.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected OnLog to see 2 entries, got %d", len(entries))
+	}
+	if e := entries[0]; e.Size != 10 || e.Latency != time.Millisecond || e.Request != r {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+}
+
+func TestRequestLogger_Output(t *testing.T) {
+	var buf bytes.Buffer
+	rl := NewRequestLogger(RequestLoggerConfig{Level: LogLevelInfo, Output: &buf})
+
+	rl.Log(httptest.NewRequest(http.MethodGet, "/users", nil), 200, 0, time.Millisecond, nil, nil)
+
+	if !strings.Contains(buf.String(), "GET /users 200") {
+		t.Errorf("Expected the log line in Output, got: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("Expected no colors for a non-terminal Output, got: %q", buf.String())
+	}
+}
+
+func TestRequestLogger_Slog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	app := New()
+	app.SetLogger(RequestLoggerConfig{Level: LogLevelInfo, Slog: logger})
+	app.Use(RecoverWithConfig(RecoverConfig{LogStackTrace: true}))
+	app.Get("/users", func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	app.Get("/panic", func(c *Context) error {
+		panic("boom")
+	})
+	app.Mount()
+
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("X-Request-ID", "req-1")
+	app.ServeHTTP(httptest.NewRecorder(), r)
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected a slog JSON record, got %q", line)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d: %s", len(records), buf.String())
+	}
+
+	if r := records[0]; r["msg"] != "request" || r["level"] != "INFO" || r["path"] != "/users" || r["status"] != float64(200) {
+		t.Errorf("Unexpected request record: %v", r)
+	}
+	if r := records[1]; r["msg"] != "panic recovered" || r["panic"] != "boom" {
+		t.Errorf("Expected the panic to be logged, got %v", r)
+	}
+	if r := records[2]; r["level"] != "ERROR" || r["status"] != float64(500) {
+		t.Errorf("Expected a 500 request logged at ERROR, got %v", r)
+	}
+}
+
+func TestFormatStreamDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{850 * time.Millisecond, "850ms"},
+		{12400 * time.Millisecond, "12.4s"},
+		{3*time.Minute + 5*time.Second, "3m05s"},
+		{90 * time.Minute, "90m00s"},
+	}
+	for _, tt := range tests {
+		if got := formatStreamDuration(tt.d); got != tt.want {
+			t.Errorf("formatStreamDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package nexo
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaintenanceMessage is the message of maintenance responses unless
+// MaintenanceConfig.Message is set.
+const DefaultMaintenanceMessage = "service is under maintenance"
+
+// MaintenanceConfig configures maintenance mode (see WithMaintenance).
+type MaintenanceConfig struct {
+	// Enabled starts the app in maintenance mode.
+	Enabled bool
+
+	// Message is the error message of maintenance responses. Default is
+	// DefaultMaintenanceMessage.
+	Message string
+
+	// RetryAfter is sent in the Retry-After header of maintenance
+	// responses. Default is 5 minutes.
+	RetryAfter time.Duration
+
+	// AllowIPs lists the clients, as CIDRs or IPs, that are served as
+	// usual during maintenance. Behind a proxy, list it with
+	// WithTrustedProxies.
+	AllowIPs []string
+
+	// AllowPaths lists the path prefixes served as usual during
+	// maintenance, e.g. "/health" or "/static".
+	AllowPaths []string
+
+	// AdminPath serves an endpoint that switches maintenance mode: GET
+	// returns the state, POST enables it, with an optional
+	// {"message": "..."} body, and DELETE disables it. Requests must send
+	// AdminToken as a bearer token.
+	AdminPath string
+
+	// AdminToken authenticates requests to AdminPath.
+	AdminToken string
+
+	// Signal toggles maintenance mode when the process receives it while
+	// the app is listening, e.g. syscall.SIGUSR1.
+	Signal os.Signal
+}
+
+// maintenanceState is whether maintenance mode is on, and its message.
+type maintenanceState struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
+
+// maintenance holds the app's maintenance mode.
+type maintenance struct {
+	config     MaintenanceConfig
+	allowIPs   []netip.Prefix
+	retryAfter string
+	state      atomic.Pointer[maintenanceState]
+}
+
+func newMaintenance(config MaintenanceConfig) *maintenance {
+	if config.Message == "" {
+		config.Message = DefaultMaintenanceMessage
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = 5 * time.Minute
+	}
+	if config.AdminPath != "" && config.AdminToken == "" {
+		panic("nexo: WithMaintenance AdminPath requires an AdminToken")
+	}
+
+	m := &maintenance{
+		config:     config,
+		allowIPs:   parsePrefixes(config.AllowIPs, "WithMaintenance AllowIPs"),
+		retryAfter: strconv.Itoa(max(ceilSeconds(config.RetryAfter), 1)),
+	}
+	m.set(config.Enabled, "")
+	return m
+}
+
+// set switches maintenance mode, with the configured message if message
+// is empty.
+func (m *maintenance) set(enabled bool, message string) {
+	if message == "" {
+		message = m.config.Message
+	}
+	m.state.Store(&maintenanceState{Enabled: enabled, Message: message})
+}
+
+// allowed reports whether r is served as usual during maintenance.
+func (m *maintenance) allowed(r *http.Request) bool {
+	for _, prefix := range m.config.AllowPaths {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, prefix+"/") {
+			return true
+		}
+	}
+	if len(m.allowIPs) > 0 {
+		if addr, ok := clientAddr(r); ok && containsAddr(m.allowIPs, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// serve responds to r if it's for the admin endpoint or if maintenance
+// mode turns it away, and reports whether it did.
+func (m *maintenance) serve(w http.ResponseWriter, r *http.Request) bool {
+	if m.config.AdminPath != "" && r.URL.Path == m.config.AdminPath {
+		m.serveAdmin(NewContext(w, r))
+		return true
+	}
+
+	state := m.state.Load()
+	if !state.Enabled || m.allowed(r) {
+		return false
+	}
+	c := NewContext(w, r)
+	c.SetHeader("Retry-After", m.retryAfter)
+	handleError(c, NewHTTPError(http.StatusServiceUnavailable, state.Message))
+	return true
+}
+
+// serveAdmin handles requests to the admin endpoint.
+func (m *maintenance) serveAdmin(c *Context) {
+	token, ok := strings.CutPrefix(c.Header("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(m.config.AdminToken)) != 1 {
+		c.SetHeader("WWW-Authenticate", "Bearer")
+		handleError(c, Unauthorized("invalid maintenance token"))
+		return
+	}
+
+	switch c.Method() {
+	case http.MethodGet:
+	case http.MethodPost:
+		var body struct {
+			Message string `json:"message"`
+		}
+		if c.Request.Body != nil {
+			if err := json.NewDecoder(io.LimitReader(c.Request.Body, 64<<10)).Decode(&body); err != nil && err != io.EOF {
+				handleError(c, NewHTTPErrorWithCause(http.StatusBadRequest, "invalid JSON", err))
+				return
+			}
+		}
+		m.set(true, body.Message)
+	case http.MethodDelete:
+		m.set(false, "")
+	default:
+		c.SetHeader("Allow", "GET, POST, DELETE")
+		handleError(c, NewHTTPError(http.StatusMethodNotAllowed, "method not allowed"))
+		return
+	}
+	_ = c.JSON(http.StatusOK, m.state.Load())
+}
+
+// watchSignal toggles maintenance mode on the configured signal until ctx
+// is done, reporting each toggle to l.
+func (m *maintenance) watchSignal(ctx context.Context, l *slog.Logger) {
+	if m.config.Signal == nil {
+		return
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, m.config.Signal)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sig:
+				enabled := !m.state.Load().Enabled
+				m.set(enabled, "")
+				if enabled {
+					diagnostic(l, slog.LevelWarn, "\n  Maintenance mode on\n", "maintenance mode on")
+				} else {
+					diagnostic(l, slog.LevelInfo, "\n  Maintenance mode off\n", "maintenance mode off")
+				}
+			}
+		}
+	}()
+}
+
+// SetMaintenance switches maintenance mode at runtime. While it's on, every
+// request not allowed by WithMaintenance gets a 503 error with message, or
+// the configured message if message is empty, and a Retry-After header.
+// It's safe to call from any goroutine.
+//
+// Example:
+//
+//	app.SetMaintenance(true, "back at 10:00 UTC")
+//	defer app.SetMaintenance(false, "")
+//	runMigrations()
+func (a *App) SetMaintenance(enabled bool, message string) {
+	a.maintenance.set(enabled, message)
+}
+
+// Maintenance reports whether maintenance mode is on, and its message.
+func (a *App) Maintenance() (enabled bool, message string) {
+	state := a.maintenance.state.Load()
+	return state.Enabled, state.Message
+}
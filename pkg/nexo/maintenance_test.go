@@ -0,0 +1,168 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newMaintenanceApp(opts ...Option) *App {
+	app := New(opts...)
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	app.Get("/health", func(c *Context) error {
+		return c.String(http.StatusOK, "healthy")
+	})
+	app.Mount()
+	return app
+}
+
+func doMaintenance(app *App, method, path, remote, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if remote != "" {
+		req.RemoteAddr = remote
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func TestSetMaintenance(t *testing.T) {
+	app := newMaintenanceApp()
+
+	if w := doMaintenance(app, http.MethodGet, "/", "", "", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	app.SetMaintenance(true, "back at 10:00 UTC")
+	if enabled, message := app.Maintenance(); !enabled || message != "back at 10:00 UTC" {
+		t.Errorf("unexpected state %v %q", enabled, message)
+	}
+	w := doMaintenance(app, http.MethodGet, "/", "", "", "")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "300" {
+		t.Errorf("expected Retry-After 300, got %q", w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), "back at 10:00 UTC") {
+		t.Errorf("expected the message in the body, got %q", w.Body.String())
+	}
+
+	app.SetMaintenance(true, "")
+	if _, message := app.Maintenance(); message != DefaultMaintenanceMessage {
+		t.Errorf("expected the default message, got %q", message)
+	}
+
+	app.SetMaintenance(false, "")
+	if w := doMaintenance(app, http.MethodGet, "/", "", "", ""); w.Code != http.StatusOK {
+		t.Errorf("expected 200 after maintenance, got %d", w.Code)
+	}
+}
+
+func TestMaintenance_Allowlist(t *testing.T) {
+	app := newMaintenanceApp(WithMaintenance(MaintenanceConfig{
+		Enabled:    true,
+		Message:    "upgrading",
+		RetryAfter: time.Minute,
+		AllowIPs:   []string{"10.0.0.0/8"},
+		AllowPaths: []string{"/health/"},
+	}))
+
+	tests := []struct {
+		name   string
+		path   string
+		remote string
+		status int
+	}{
+		{"blocked", "/", "203.0.113.7:1234", http.StatusServiceUnavailable},
+		{"allowed IP", "/", "10.1.2.3:1234", http.StatusOK},
+		{"allowed path", "/health", "203.0.113.7:1234", http.StatusOK},
+		{"prefix boundary", "/healthz", "203.0.113.7:1234", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doMaintenance(app, http.MethodGet, tt.path, tt.remote, "", "")
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+
+	if w := doMaintenance(app, http.MethodGet, "/", "203.0.113.7:1234", "", ""); w.Header().Get("Retry-After") != "60" {
+		t.Errorf("expected Retry-After 60, got %q", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestMaintenance_ErrorHandler(t *testing.T) {
+	app := New(WithMaintenance(MaintenanceConfig{Enabled: true}))
+	app.DisableLogger()
+	app.OnError(func(c *Context, err error) error {
+		return c.HTML(http.StatusServiceUnavailable, "<h1>Down for maintenance</h1>")
+	})
+	app.Mount()
+
+	w := doMaintenance(app, http.MethodGet, "/", "", "", "")
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "Down for maintenance") {
+		t.Errorf("expected the error handler's page, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestMaintenance_AdminEndpoint(t *testing.T) {
+	app := newMaintenanceApp(WithMaintenance(MaintenanceConfig{
+		AdminPath:  "/_maintenance",
+		AdminToken: "secret",
+	}))
+
+	if w := doMaintenance(app, http.MethodPost, "/_maintenance", "", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", w.Code)
+	}
+	if w := doMaintenance(app, http.MethodPost, "/_maintenance", "", "wrong", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a wrong token, got %d", w.Code)
+	}
+
+	w := doMaintenance(app, http.MethodPost, "/_maintenance", "", "secret", `{"message":"migrating"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"enabled":true`) {
+		t.Fatalf("expected maintenance to be enabled, got %d %q", w.Code, w.Body.String())
+	}
+	if enabled, message := app.Maintenance(); !enabled || message != "migrating" {
+		t.Errorf("unexpected state %v %q", enabled, message)
+	}
+	if w := doMaintenance(app, http.MethodGet, "/", "", "", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+
+	// The endpoint stays reachable during maintenance
+	if w := doMaintenance(app, http.MethodGet, "/_maintenance", "", "secret", ""); w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+	if w := doMaintenance(app, http.MethodPost, "/_maintenance", "", "secret", ""); w.Code != http.StatusOK {
+		t.Errorf("expected an empty body to be accepted, got %d", w.Code)
+	}
+	if w := doMaintenance(app, http.MethodPut, "/_maintenance", "", "secret", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+
+	if w := doMaintenance(app, http.MethodDelete, "/_maintenance", "", "secret", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if w := doMaintenance(app, http.MethodGet, "/", "", "", ""); w.Code != http.StatusOK {
+		t.Errorf("expected 200 after disabling, got %d", w.Code)
+	}
+}
+
+func TestWithMaintenance_Invalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an AdminPath without a token")
+		}
+	}()
+	New(WithMaintenance(MaintenanceConfig{AdminPath: "/_maintenance"}))
+}
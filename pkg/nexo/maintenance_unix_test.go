@@ -0,0 +1,28 @@
+//go:build unix
+
+package nexo
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMaintenance_Signal(t *testing.T) {
+	m := newMaintenance(MaintenanceConfig{Signal: syscall.SIGUSR1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.watchSignal(ctx, nil)
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !m.state.Load().Enabled {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the signal to enable maintenance mode")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,277 @@
+package nexo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DefaultMetricsPath is where WithMetrics serves the metrics by default.
+const DefaultMetricsPath = "/metrics"
+
+// DefaultDurationBuckets are the request duration histogram buckets, in seconds.
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are the response size histogram buckets, in bytes.
+var DefaultSizeBuckets = []float64{100, 1_000, 10_000, 100_000, 1_000_000, 10_000_000}
+
+// DefaultMetrics is the registry the Metrics middleware records to and
+// WithMetrics exposes.
+var DefaultMetrics = NewMetricsRegistry("")
+
+// MetricsRegistry collects HTTP request metrics and serves them in the
+// Prometheus text format. It implements http.Handler.
+type MetricsRegistry struct {
+	namespace string
+
+	mu       sync.Mutex
+	requests map[requestKey]*requestMetrics
+	inFlight map[routeKey]int64
+}
+
+// routeKey identifies the requests to a route.
+type routeKey struct {
+	method string
+	route  string
+}
+
+// requestKey identifies the requests to a route with a response status.
+type requestKey struct {
+	routeKey
+	status int
+}
+
+// requestMetrics holds the histograms for one requestKey. The request count
+// is the duration histogram's count.
+type requestMetrics struct {
+	duration histogram
+	size     histogram
+}
+
+// histogram counts observations per bucket, not cumulatively.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+	if i := sort.SearchFloat64s(buckets, v); i < len(buckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+// NewMetricsRegistry creates an empty registry. The namespace, if any,
+// prefixes the metric names, e.g. "myapp" gives myapp_http_requests_total.
+func NewMetricsRegistry(namespace string) *MetricsRegistry {
+	return &MetricsRegistry{
+		namespace: namespace,
+		requests:  make(map[requestKey]*requestMetrics),
+		inFlight:  make(map[routeKey]int64),
+	}
+}
+
+func (m *MetricsRegistry) addInFlight(key routeKey, delta int64) {
+	m.mu.Lock()
+	m.inFlight[key] += delta
+	m.mu.Unlock()
+}
+
+func (m *MetricsRegistry) observe(key requestKey, d time.Duration, size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rm, ok := m.requests[key]
+	if !ok {
+		rm = &requestMetrics{}
+		m.requests[key] = rm
+	}
+	rm.duration.observe(DefaultDurationBuckets, d.Seconds())
+	rm.size.observe(DefaultSizeBuckets, float64(size))
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *MetricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.Write(w)
+}
+
+// Write writes the metrics in the Prometheus text format.
+func (m *MetricsRegistry) Write(w io.Writer) error {
+	m.mu.Lock()
+	keys := make([]requestKey, 0, len(m.requests))
+	requests := make(map[requestKey]requestMetrics, len(m.requests))
+	for key, rm := range m.requests {
+		keys = append(keys, key)
+		requests[key] = requestMetrics{
+			duration: histogram{counts: append([]uint64(nil), rm.duration.counts...), count: rm.duration.count, sum: rm.duration.sum},
+			size:     histogram{counts: append([]uint64(nil), rm.size.counts...), count: rm.size.count, sum: rm.size.sum},
+		}
+	}
+	routes := make([]routeKey, 0, len(m.inFlight))
+	inFlight := make(map[routeKey]int64, len(m.inFlight))
+	for key, n := range m.inFlight {
+		routes = append(routes, key)
+		inFlight[key] = n
+	}
+	m.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].routeKey != keys[j].routeKey {
+			return keys[i].routeKey.less(keys[j].routeKey)
+		}
+		return keys[i].status < keys[j].status
+	})
+	sort.Slice(routes, func(i, j int) bool { return routes[i].less(routes[j]) })
+
+	var b strings.Builder
+
+	name := m.name("http_requests_total")
+	fmt.Fprintf(&b, "# HELP %s Total number of HTTP requests.\n# TYPE %s counter\n", name, name)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s{%s} %d\n", name, key.labels(), requests[key].duration.count)
+	}
+
+	name = m.name("http_request_duration_seconds")
+	fmt.Fprintf(&b, "# HELP %s HTTP request duration in seconds.\n# TYPE %s histogram\n", name, name)
+	for _, key := range keys {
+		writeHistogram(&b, name, key.labels(), DefaultDurationBuckets, requests[key].duration)
+	}
+
+	name = m.name("http_response_size_bytes")
+	fmt.Fprintf(&b, "# HELP %s HTTP response body size in bytes.\n# TYPE %s histogram\n", name, name)
+	for _, key := range keys {
+		writeHistogram(&b, name, key.labels(), DefaultSizeBuckets, requests[key].size)
+	}
+
+	name = m.name("http_requests_in_flight")
+	fmt.Fprintf(&b, "# HELP %s Number of HTTP requests being served.\n# TYPE %s gauge\n", name, name)
+	for _, key := range routes {
+		fmt.Fprintf(&b, "%s{%s} %d\n", name, key.labels(), inFlight[key])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m *MetricsRegistry) name(metric string) string {
+	if m.namespace == "" {
+		return metric
+	}
+	return m.namespace + "_" + metric
+}
+
+func writeHistogram(b *strings.Builder, name, labels string, buckets []float64, h histogram) {
+	var cumulative uint64
+	for i, le := range buckets {
+		if i < len(h.counts) {
+			cumulative += h.counts[i]
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(le), cumulative)
+	}
+	fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+	fmt.Fprintf(b, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+	fmt.Fprintf(b, "%s_count{%s} %d\n", name, labels, h.count)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func (k routeKey) less(o routeKey) bool {
+	if k.route != o.route {
+		return k.route < o.route
+	}
+	return k.method < o.method
+}
+
+func (k routeKey) labels() string {
+	return fmt.Sprintf(`method="%s",route="%s"`, escapeLabel(k.method), escapeLabel(k.route))
+}
+
+func (k requestKey) labels() string {
+	return fmt.Sprintf(`%s,status="%d"`, k.routeKey.labels(), k.status)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// ---------- Metrics Middleware ----------
+
+// MetricsConfig holds configuration for the metrics middleware.
+type MetricsConfig struct {
+	// Registry records the metrics. Default is DefaultMetrics.
+	Registry *MetricsRegistry
+
+	// Skip returns true for requests that shouldn't be recorded.
+	Skip func(c *Context) bool
+}
+
+// Metrics returns a middleware that records request counts, duration and
+// response size histograms and in-flight requests to DefaultMetrics,
+// labeled by method, route pattern and status. Expose them with
+// WithMetrics.
+//
+// Handler errors are written inside the middleware, so the recorded status
+// is the one sent to the client.
+func Metrics() MiddlewareFunc {
+	return MetricsWithConfig(MetricsConfig{})
+}
+
+// MetricsWithConfig returns a metrics middleware with custom configuration.
+func MetricsWithConfig(config MetricsConfig) MiddlewareFunc {
+	registry := config.Registry
+	if registry == nil {
+		registry = DefaultMetrics
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			// Route patterns keep the label cardinality bounded. Requests
+			// that match no route get an empty route label.
+			key := routeKey{method: c.Method()}
+			if rctx := chi.RouteContext(c.Request.Context()); rctx != nil {
+				key.route = rctx.RoutePattern()
+			}
+
+			registry.addInFlight(key, 1)
+			defer registry.addInFlight(key, -1)
+
+			res := c.Response
+			defer func() { c.Response = res }()
+			rw := newResponseWriter(res)
+			c.Response = rw
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				handleError(c, err)
+			}
+
+			status := rw.Status()
+			if c.Written() && !rw.Written() {
+				status = c.StatusCode()
+			}
+			registry.observe(requestKey{routeKey: key, status: status}, time.Since(start), rw.Size())
+			return err
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := NewMetricsRegistry("shop")
+
+	app := New()
+	app.DisableLogger()
+	app.Use(MetricsWithConfig(MetricsConfig{Registry: registry}))
+	app.Get("/users/{id}", func(c *Context) error {
+		return c.String(http.StatusOK, "hello")
+	})
+	app.Post("/users", func(c *Context) error {
+		return BadRequest("missing name")
+	})
+	app.Mount()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/users/1", nil),
+		httptest.NewRequest(http.MethodGet, "/users/2", nil),
+		httptest.NewRequest(http.MethodPost, "/users", nil),
+		httptest.NewRequest(http.MethodGet, "/missing/123", nil),
+	} {
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	registry.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE shop_http_requests_total counter",
+		`shop_http_requests_total{method="GET",route="/users/{id}",status="200"} 2`,
+		`shop_http_requests_total{method="POST",route="/users",status="400"} 1`,
+		"# TYPE shop_http_request_duration_seconds histogram",
+		`shop_http_request_duration_seconds_count{method="GET",route="/users/{id}",status="200"} 2`,
+		`shop_http_request_duration_seconds_bucket{method="GET",route="/users/{id}",status="200",le="+Inf"} 2`,
+		`shop_http_response_size_bytes_bucket{method="GET",route="/users/{id}",status="200",le="100"} 2`,
+		`shop_http_response_size_bytes_sum{method="GET",route="/users/{id}",status="200"} 10`,
+		`shop_http_requests_in_flight{method="GET",route="/users/{id}"} 0`,
+		`shop_http_requests_total{method="GET",route="",status="404"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "/missing/123") {
+		t.Error("expected unmatched paths not to be used as route labels")
+	}
+}
+
+func TestMetrics_Skip(t *testing.T) {
+	registry := NewMetricsRegistry("")
+	mw := MetricsWithConfig(MetricsConfig{
+		Registry: registry,
+		Skip:     func(c *Context) bool { return c.Path() == "/health" },
+	})
+	h := mw(func(c *Context) error { return c.NoContent() })
+
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if err := h(c); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := registry.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "/health") {
+		t.Errorf("expected skipped requests not to be recorded, got:\n%s", b.String())
+	}
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	var h histogram
+	buckets := []float64{1, 5, 10}
+	for _, v := range []float64{0.5, 1, 3, 10, 50} {
+		h.observe(buckets, v)
+	}
+
+	var b strings.Builder
+	writeHistogram(&b, "x", `a="b"`, buckets, h)
+	want := `x_bucket{a="b",le="1"} 2
+x_bucket{a="b",le="5"} 3
+x_bucket{a="b",le="10"} 4
+x_bucket{a="b",le="+Inf"} 5
+x_sum{a="b"} 64.5
+x_count{a="b"} 5
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	app := New(WithMetrics(""))
+	app.DisableLogger()
+	app.Use(Metrics())
+	app.Get("/orders", func(c *Context) error {
+		return c.JSON(http.StatusOK, []string{})
+	})
+	app.Mount()
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DefaultMetricsPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if want := `http_requests_total{method="GET",route="/orders",status="200"}`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("expected metrics to contain %q, got:\n%s", want, w.Body.String())
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabel() = %q", got)
+	}
+}
@@ -3,6 +3,7 @@ package nexo
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"runtime/debug"
 	"strconv"
@@ -118,6 +119,23 @@ func LoggerWithConfig(config LoggerConfig) MiddlewareFunc {
 				}
 			}
 
+			if l := slogFromRequest(c.Request); l != nil {
+				attrs := []slog.Attr{
+					slog.String("method", c.Method()),
+					slog.String("path", c.Path()),
+					slog.Int("status", status),
+					slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+				}
+				if id := c.RequestID(); id != "" {
+					attrs = append(attrs, slog.String("request_id", id))
+				}
+				if errMsg := formatErrorForLog(err); errMsg != "" {
+					attrs = append(attrs, slog.String("error", errMsg))
+				}
+				l.LogAttrs(c.Context(), statusLevel(status), "request", attrs...)
+				return err
+			}
+
 			// Color-coded status
 			var statusColor func(a ...interface{}) string
 			switch {
@@ -149,23 +167,19 @@ func LoggerWithConfig(config LoggerConfig) MiddlewareFunc {
 			}
 
 			// Log the request
-			errMsg := formatErrorForLog(err)
-			if errMsg != "" {
-				log.Printf("%s %s %s %s %s",
-					statusColor(fmt.Sprintf("%d", status)),
-					methodColor(fmt.Sprintf("%-7s", c.Method())),
-					c.Path(),
-					color.New(color.Faint).Sprint(latency.Round(time.Microsecond)),
-					color.New(color.FgYellow).Sprintf("[%s]", errMsg),
-				)
-			} else {
-				log.Printf("%s %s %s %s",
-					statusColor(fmt.Sprintf("%d", status)),
-					methodColor(fmt.Sprintf("%-7s", c.Method())),
-					c.Path(),
-					color.New(color.Faint).Sprint(latency.Round(time.Microsecond)),
-				)
+			line := fmt.Sprintf("%s %s %s %s",
+				statusColor(fmt.Sprintf("%d", status)),
+				methodColor(fmt.Sprintf("%-7s", c.Method())),
+				c.Path(),
+				color.New(color.Faint).Sprint(latency.Round(time.Microsecond)),
+			)
+			if id := c.RequestID(); id != "" {
+				line += " " + color.New(color.Faint).Sprintf("[%s]", id)
+			}
+			if errMsg := formatErrorForLog(err); errMsg != "" {
+				line += " " + color.New(color.FgYellow).Sprintf("[%s]", errMsg)
 			}
+			log.Println(line)
 
 			return err
 		}
@@ -202,7 +216,11 @@ func RecoverWithConfig(config RecoverConfig) MiddlewareFunc {
 			defer func() {
 				if r := recover(); r != nil {
 					if config.LogStackTrace {
-						log.Printf("[PANIC] %v\n%s", r, debug.Stack())
+						if l := slogFromRequest(c.Request); l != nil {
+							l.ErrorContext(c.Context(), "panic recovered", "panic", r, "stack", string(debug.Stack()))
+						} else {
+							log.Printf("[PANIC] %v\n%s", r, debug.Stack())
+						}
 					}
 
 					config.ErrorHandler(c, r)
@@ -221,62 +239,18 @@ func defaultPanicHandler(c *Context, err any) {
 	}
 }
 
-// ---------- RequestID Middleware ----------
-
-// RequestID returns a middleware that adds a unique request ID to each request.
-func RequestID() MiddlewareFunc {
-	return RequestIDWithConfig(RequestIDConfig{})
-}
-
-// RequestIDConfig holds configuration for the request ID middleware.
-type RequestIDConfig struct {
-	// Header is the header name to use. Default is "X-Request-ID".
-	Header string
-
-	// Generator is a custom ID generator. Default generates a simple unique ID.
-	Generator func() string
-}
-
-// RequestIDWithConfig returns a request ID middleware with custom configuration.
-func RequestIDWithConfig(config RequestIDConfig) MiddlewareFunc {
-	if config.Header == "" {
-		config.Header = "X-Request-ID"
-	}
-	if config.Generator == nil {
-		config.Generator = generateRequestID
-	}
-
-	return func(next HandlerFunc) HandlerFunc {
-		return func(c *Context) error {
-			// Check if request already has an ID
-			id := c.Header(config.Header)
-			if id == "" {
-				id = config.Generator()
-			}
-
-			// Store in context and set response header
-			c.Set("requestId", id)
-			c.SetHeader(config.Header, id)
-
-			return next(c)
-		}
-	}
-}
-
-var requestIDCounter uint64
-
-func generateRequestID() string {
-	requestIDCounter++
-	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), requestIDCounter)
-}
-
 // ---------- CORS Middleware ----------
 
 // CORSConfig holds configuration for the CORS middleware.
 type CORSConfig struct {
-	// AllowOrigins is a list of allowed origins. Use "*" to allow all.
+	// AllowOrigins is a list of allowed origins. Use "*" to allow all, or
+	// a pattern such as "https://*.example.com" to allow all subdomains.
 	AllowOrigins []string
 
+	// AllowOriginFunc reports whether an origin not in AllowOrigins is
+	// allowed, e.g. by looking up per-customer domains.
+	AllowOriginFunc func(origin string) bool
+
 	// AllowMethods is a list of allowed HTTP methods.
 	AllowMethods []string
 
@@ -289,7 +263,8 @@ type CORSConfig struct {
 	// AllowCredentials indicates whether credentials are allowed.
 	AllowCredentials bool
 
-	// MaxAge is the max age for preflight cache in seconds.
+	// MaxAge is how long browsers may cache preflight responses, in
+	// seconds. Zero leaves it to the browser; negative disables caching.
 	MaxAge int
 }
 
@@ -322,23 +297,53 @@ func CORS() MiddlewareFunc {
 }
 
 // CORSWithConfig returns a CORS middleware with custom configuration.
+// Allowed origins are echoed in Access-Control-Allow-Origin, and responses
+// get Vary: Origin so caches keep them apart.
+//
+// Example:
+//
+//	app.Use(nexo.CORSWithConfig(nexo.CORSConfig{
+//	    AllowOrigins: []string{"https://app.example.com", "https://*.example.com"},
+//	    AllowOriginFunc: func(origin string) bool {
+//	        return tenants.HasDomain(origin)
+//	    },
+//	    AllowMethods:     []string{http.MethodGet, http.MethodPost},
+//	    AllowCredentials: true,
+//	}))
 func CORSWithConfig(config CORSConfig) MiddlewareFunc {
 	allowOrigins := make(map[string]bool)
+	var wildcards [][2]string
 	for _, origin := range config.AllowOrigins {
+		if prefix, suffix, ok := strings.Cut(origin, "*."); ok && origin != "*" {
+			wildcards = append(wildcards, [2]string{prefix, "." + suffix})
+			continue
+		}
 		allowOrigins[origin] = true
 	}
+	originAllowed := func(origin string) bool {
+		if allowOrigins["*"] || allowOrigins[origin] {
+			return true
+		}
+		for _, w := range wildcards {
+			if matchOriginWildcard(origin, w[0], w[1]) {
+				return true
+			}
+		}
+		return config.AllowOriginFunc != nil && origin != "" && config.AllowOriginFunc(origin)
+	}
 
 	allowMethods := strings.Join(config.AllowMethods, ", ")
 	allowHeaders := strings.Join(config.AllowHeaders, ", ")
 	exposeHeaders := strings.Join(config.ExposeHeaders, ", ")
-	maxAge := strconv.Itoa(config.MaxAge)
+	maxAge := strconv.Itoa(max(config.MaxAge, 0))
 
 	return func(next HandlerFunc) HandlerFunc {
 		return func(c *Context) error {
 			origin := c.Header("Origin")
+			addVary(c.Response.Header(), "Origin")
 
 			// Check if origin is allowed
-			allowed := allowOrigins["*"] || allowOrigins[origin]
+			allowed := originAllowed(origin)
 
 			if allowed && origin != "" {
 				c.SetHeader("Access-Control-Allow-Origin", origin)
@@ -357,7 +362,9 @@ func CORSWithConfig(config CORSConfig) MiddlewareFunc {
 				if allowed {
 					c.SetHeader("Access-Control-Allow-Methods", allowMethods)
 					c.SetHeader("Access-Control-Allow-Headers", allowHeaders)
-					c.SetHeader("Access-Control-Max-Age", maxAge)
+					if config.MaxAge != 0 {
+						c.SetHeader("Access-Control-Max-Age", maxAge)
+					}
 				}
 				return c.NoContent()
 			}
@@ -367,15 +374,34 @@ func CORSWithConfig(config CORSConfig) MiddlewareFunc {
 	}
 }
 
+// matchOriginWildcard reports whether origin is prefix, then one or more
+// subdomain labels, then suffix, so "https://*.example.com" matches
+// "https://api.example.com" but not "https://example.com" or
+// "https://evil.com/.example.com".
+func matchOriginWildcard(origin, prefix, suffix string) bool {
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	for _, r := range origin[len(prefix) : len(origin)-len(suffix)] {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 // ---------- Timeout Middleware ----------
 
-// Timeout returns a middleware that sets a request timeout.
+// Timeout returns a middleware that sets a request timeout. The handler's
+// context is canceled when the timeout passes.
 func Timeout(d time.Duration) MiddlewareFunc {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(c *Context) error {
 			if d <= 0 {
 				return next(c)
 			}
+			cancel := c.WithTimeout(d)
+			defer cancel()
 
 			// Channel for handler result
 			done := make(chan error, 1)
@@ -443,37 +469,11 @@ func BasicAuthWithConfig(config BasicAuthConfig) MiddlewareFunc {
 	}
 }
 
-// ---------- Gzip Middleware ----------
-
-// Note: Gzip compression would require wrapping the response writer.
-// For now, we provide a placeholder. Full implementation would need
-// a response writer wrapper that compresses on Write().
-
-// Compress returns a middleware that compresses responses.
-// This is a basic implementation - for production use, consider
-// using a dedicated compression library.
-func Compress() MiddlewareFunc {
-	return func(next HandlerFunc) HandlerFunc {
-		return func(c *Context) error {
-			// Check if client accepts gzip
-			acceptEncoding := c.Header("Accept-Encoding")
-			if !strings.Contains(acceptEncoding, "gzip") {
-				return next(c)
-			}
-
-			// For now, just pass through
-			// Full implementation would wrap the response writer
-			return next(c)
-		}
-	}
-}
-
 // ---------- RateLimiter Middleware (Simple) ----------
 
-// Note: This is a simple in-memory rate limiter.
-// For production, use a distributed rate limiter like Redis.
-
 // RateLimiterConfig holds configuration for rate limiting.
+//
+// Deprecated: Use RateLimitConfig.
 type RateLimiterConfig struct {
 	// Max requests per window
 	Max int
@@ -482,53 +482,9 @@ type RateLimiterConfig struct {
 	Window time.Duration
 }
 
-// RateLimiter returns a simple rate limiting middleware.
-// Note: This is per-process and not suitable for distributed systems.
+// RateLimiter returns a rate limiting middleware allowing max requests per
+// window from each client IP, with an in-memory sliding window. Use
+// RateLimit for other algorithms, keys or a shared store.
 func RateLimiter(max int, window time.Duration) MiddlewareFunc {
-	requests := make(map[string][]time.Time)
-
-	return func(next HandlerFunc) HandlerFunc {
-		return func(c *Context) error {
-			ip := c.ClientIP()
-			now := time.Now()
-			windowStart := now.Add(-window)
-
-			// Clean old requests
-			var validRequests []time.Time
-			for _, t := range requests[ip] {
-				if t.After(windowStart) {
-					validRequests = append(validRequests, t)
-				}
-			}
-			requests[ip] = validRequests
-
-			// Check rate limit
-			if len(validRequests) >= max {
-				c.SetHeader("Retry-After", strconv.Itoa(int(window.Seconds())))
-				return c.Error(http.StatusTooManyRequests, "rate limit exceeded")
-			}
-
-			// Add current request
-			requests[ip] = append(requests[ip], now)
-
-			return next(c)
-		}
-	}
-}
-
-// ---------- Secure Headers Middleware ----------
-
-// SecureHeaders returns a middleware that sets security-related headers.
-func SecureHeaders() MiddlewareFunc {
-	return func(next HandlerFunc) HandlerFunc {
-		return func(c *Context) error {
-			// Set security headers
-			c.SetHeader("X-Content-Type-Options", "nosniff")
-			c.SetHeader("X-Frame-Options", "SAMEORIGIN")
-			c.SetHeader("X-XSS-Protection", "1; mode=block")
-			c.SetHeader("Referrer-Policy", "strict-origin-when-cross-origin")
-
-			return next(c)
-		}
-	}
+	return RateLimit(RateLimitConfig{Limit: max, Window: window})
 }
@@ -215,6 +215,75 @@ func TestCORSWithConfig_Credentials(t *testing.T) {
 	}
 }
 
+func TestCORSWithConfig_Origins(t *testing.T) {
+	mw := CORSWithConfig(CORSConfig{
+		AllowOrigins: []string{"https://app.example.com", "https://*.tenants.example.com"},
+		AllowOriginFunc: func(origin string) bool {
+			return origin == "https://customer.test"
+		},
+	})
+	handler := mw(func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://acme.tenants.example.com", true},
+		{"https://eu.acme.tenants.example.com", true},
+		{"https://customer.test", true},
+		{"https://tenants.example.com", false},
+		{"http://acme.tenants.example.com", false},
+		{"https://acme.tenants.example.com:8443", false},
+		{"https://evil.com/.tenants.example.com", false},
+		{"https://evil.test", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			if err := handler(NewContext(w, req)); err != nil {
+				t.Fatal(err)
+			}
+
+			got := w.Header().Get("Access-Control-Allow-Origin")
+			if tt.allowed && got != tt.origin {
+				t.Errorf("expected %q to be allowed, got %q", tt.origin, got)
+			}
+			if !tt.allowed && got != "" {
+				t.Errorf("expected %q to be rejected, got %q", tt.origin, got)
+			}
+			if w.Header().Get("Vary") != "Origin" {
+				t.Errorf("expected Vary: Origin, got %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSWithConfig_MaxAge(t *testing.T) {
+	tests := []struct {
+		maxAge int
+		want   string
+	}{
+		{0, ""},
+		{600, "600"},
+		{-1, "0"},
+	}
+	for _, tt := range tests {
+		mw := CORSWithConfig(CORSConfig{AllowOrigins: []string{"*"}, MaxAge: tt.maxAge})
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", "http://example.com")
+		w := httptest.NewRecorder()
+		_ = mw(func(c *Context) error { return nil })(NewContext(w, req))
+		if got := w.Header().Get("Access-Control-Max-Age"); got != tt.want {
+			t.Errorf("MaxAge %d: expected %q, got %q", tt.maxAge, tt.want, got)
+		}
+	}
+}
+
 func TestTimeout_NoTimeout(t *testing.T) {
 	handler := func(c *Context) error {
 		return c.String(http.StatusOK, "ok")
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
+	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -57,11 +59,32 @@ type OpenAPILicense struct {
 	URL  string
 }
 
+// RouteMeta documents the handlers of a route.go file in the OpenAPI spec.
+// Declare it as Meta to apply it to every handler in the file, or as GetMeta,
+// PostMeta, etc. for a single handler. The spec is generated from source, so
+// fields must be literals.
+//
+// Example:
+//
+//	var PostMeta = nexo.RouteMeta{
+//	    Summary: "Create a user",
+//	    Tags:    []string{"users"},
+//	}
+type RouteMeta struct {
+	Summary     string
+	Description string
+	Tags        []string
+	OperationID string
+	Deprecated  bool
+}
+
 // OpenAPIGenerator generates OpenAPI specs from Nexo routes.
 type OpenAPIGenerator struct {
-	appDir  string
-	config  OpenAPIConfig
-	scanner *Scanner
+	appDir   string
+	config   OpenAPIConfig
+	scanner  *Scanner
+	schemas  *schemaBuilder
+	security openapi3.SecuritySchemes // schemes used by //nexo:auth directives
 }
 
 // ExtendedRouteInfo includes schema information extracted from handlers.
@@ -70,10 +93,26 @@ type ExtendedRouteInfo struct {
 	Summary     string
 	Description string
 	Tags        []string
+	OperationID string
+	Deprecated  bool
+
+	// handler holds the request and response types inferred from the handler
+	handler handlerSchema
 }
 
 // NewOpenAPIGenerator creates a new OpenAPI generator.
 func NewOpenAPIGenerator(appDir string, config OpenAPIConfig) *OpenAPIGenerator {
+	return newOpenAPIGenerator(appDir, NewScanner(appDir), config)
+}
+
+// NewOpenAPIGeneratorFS creates an OpenAPI generator that reads the app
+// directory from fsys, e.g. a copy of app/ embedded in the binary (see
+// NewScannerFS).
+func NewOpenAPIGeneratorFS(fsys fs.FS, config OpenAPIConfig) *OpenAPIGenerator {
+	return newOpenAPIGenerator(".", NewScannerFS(fsys), config)
+}
+
+func newOpenAPIGenerator(appDir string, scanner *Scanner, config OpenAPIConfig) *OpenAPIGenerator {
 	// Set defaults
 	if config.Version == "" {
 		config.Version = "1.0.0"
@@ -88,13 +127,15 @@ func NewOpenAPIGenerator(appDir string, config OpenAPIConfig) *OpenAPIGenerator
 	return &OpenAPIGenerator{
 		appDir:  appDir,
 		config:  config,
-		scanner: NewScanner(appDir),
+		scanner: scanner,
 	}
 }
 
 // Generate creates an OpenAPI spec from discovered routes.
 func (g *OpenAPIGenerator) Generate() (*openapi3.T, error) {
 	// Scan for routes with extended info
+	g.schemas = newSchemaBuilder(g.scanner)
+	g.security = make(openapi3.SecuritySchemes)
 	routes, err := g.scanExtendedRouteInfo()
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan routes: %w", err)
@@ -142,7 +183,8 @@ func (g *OpenAPIGenerator) Generate() (*openapi3.T, error) {
 	// Group routes by path
 	pathRoutes := make(map[string][]ExtendedRouteInfo)
 	for _, route := range routes {
-		pathRoutes[route.Pattern] = append(pathRoutes[route.Pattern], route)
+		path := openAPIPath(route.Pattern)
+		pathRoutes[path] = append(pathRoutes[path], route)
 	}
 
 	// Build paths
@@ -151,6 +193,18 @@ func (g *OpenAPIGenerator) Generate() (*openapi3.T, error) {
 		doc.Paths.Set(pattern, pathItem)
 	}
 
+	// Add schemas of the structs handlers bind and respond with, and the
+	// security schemes routes require
+	if len(g.schemas.schemas) > 0 || len(g.security) > 0 {
+		doc.Components = &openapi3.Components{}
+		if len(g.schemas.schemas) > 0 {
+			doc.Components.Schemas = g.schemas.schemas
+		}
+		if len(g.security) > 0 {
+			doc.Components.SecuritySchemes = g.security
+		}
+	}
+
 	return doc, nil
 }
 
@@ -174,9 +228,11 @@ func (g *OpenAPIGenerator) GenerateYAML() ([]byte, error) {
 	return yaml.Marshal(doc)
 }
 
-// scanExtendedRouteInfo scans routes and extracts documentation from comments.
+// scanExtendedRouteInfo scans routes and extracts documentation from comments,
+// directives, RouteMeta declarations and the types handlers bind and respond
+// with.
 func (g *OpenAPIGenerator) scanExtendedRouteInfo() ([]ExtendedRouteInfo, error) {
-	routes, err := g.scanner.ScanRouteInfo()
+	routes, _, err := g.scanner.ScanRouteInfo()
 	if err != nil {
 		return nil, err
 	}
@@ -184,15 +240,46 @@ func (g *OpenAPIGenerator) scanExtendedRouteInfo() ([]ExtendedRouteInfo, error)
 	extended := make([]ExtendedRouteInfo, 0, len(routes))
 
 	for _, route := range routes {
+		// WebSocket upgrades have no request and response for OpenAPI to
+		// describe
+		if route.WebSocket {
+			continue
+		}
+		// Form actions post back to pages, which aren't part of the API
+		if route.Action {
+			continue
+		}
+
 		ext := ExtendedRouteInfo{
 			RouteInfo: route,
+			Tags:      []string{g.deriveTag(route.FilePath)},
+		}
+
+		pkg := g.schemas.loadPackage(filepath.Dir(route.FilePath))
+		file := pkg.files[route.FilePath]
+		if file == nil {
+			extended = append(extended, ext)
+			continue
 		}
 
-		// Extract comments and tags
-		summary, description := g.extractComments(route.FilePath, route.Method)
-		ext.Summary = summary
-		ext.Description = description
-		ext.Tags = []string{g.deriveTag(route.FilePath)}
+		funcName := handlerFuncName(route.Method)
+		if route.SSE {
+			funcName = connFiles["sse.go"].funcName
+		} else if findFunc(file, funcName) == nil && findFunc(file, anyHandler) != nil {
+			funcName = anyHandler
+		}
+		if fn := findFunc(file, funcName); fn != nil {
+			ext.Summary, ext.Description = extractComments(fn)
+			ext.handler = g.schemas.analyzeHandler(pkg, fn)
+		}
+		ext.applyDirectives(route.Directives)
+
+		// File-wide Meta first, then the handler's own, e.g. GetMeta
+		for _, name := range []string{"Meta", funcName + "Meta"} {
+			if meta, ok := extractRouteMeta(file, name); ok {
+				ext.applyMeta(meta)
+			}
+		}
 
 		extended = append(extended, ext)
 	}
@@ -200,78 +287,158 @@ func (g *OpenAPIGenerator) scanExtendedRouteInfo() ([]ExtendedRouteInfo, error)
 	return extended, nil
 }
 
-// extractComments extracts summary and description from handler function comments.
-func (g *OpenAPIGenerator) extractComments(filePath, methodName string) (summary, description string) {
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
-	if err != nil {
-		return "", ""
+// applyDirectives overrides documentation with the handler's directives.
+func (r *ExtendedRouteInfo) applyDirectives(d RouteDirectives) {
+	if d.Summary != "" {
+		r.Summary = d.Summary
+	}
+	if len(d.Tags) > 0 {
+		r.Tags = d.Tags
+	}
+	if d.Deprecated {
+		r.Deprecated = true
+	}
+}
+
+// applyMeta overrides documentation with the non-empty fields of meta.
+func (r *ExtendedRouteInfo) applyMeta(meta RouteMeta) {
+	if meta.Summary != "" {
+		r.Summary = meta.Summary
+	}
+	if meta.Description != "" {
+		r.Description = meta.Description
+	}
+	if len(meta.Tags) > 0 {
+		r.Tags = meta.Tags
+	}
+	if meta.OperationID != "" {
+		r.OperationID = meta.OperationID
+	}
+	if meta.Deprecated {
+		r.Deprecated = true
+	}
+}
+
+// handlerFuncName returns the handler function name for an HTTP method,
+// e.g. "GET" -> "Get".
+func handlerFuncName(method string) string {
+	for name, m := range httpMethods {
+		if m == method {
+			return name
+		}
 	}
+	return ""
+}
 
-	// Find the handler function by method name
+// findFunc returns the top-level function named name, or nil.
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
 	for _, decl := range file.Decls {
-		fn, ok := decl.(*ast.FuncDecl)
-		if !ok {
-			continue
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
 		}
+	}
+	return nil
+}
 
-		// Map HTTP method to function name
-		var funcName string
-		switch methodName {
-		case "GET":
-			funcName = "Get"
-		case "POST":
-			funcName = "Post"
-		case "PUT":
-			funcName = "Put"
-		case "PATCH":
-			funcName = "Patch"
-		case "DELETE":
-			funcName = "Delete"
-		case "HEAD":
-			funcName = "Head"
-		case "OPTIONS":
-			funcName = "Options"
-		default:
-			continue
+// extractComments extracts summary and description from handler function
+// comments, leaving out directives.
+func extractComments(fn *ast.FuncDecl) (summary, description string) {
+	// Extract doc comments
+	if fn.Doc == nil || len(fn.Doc.List) == 0 {
+		return "", ""
+	}
+
+	var lines []string
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		if text := strings.TrimSpace(line); text != "" {
+			lines = append(lines, text)
 		}
+	}
+
+	if len(lines) == 0 {
+		return "", ""
+	}
+
+	// First line is summary
+	summary = lines[0]
+
+	// Remaining lines are description
+	if len(lines) > 1 {
+		description = strings.Join(lines[1:], "\n")
+	}
+
+	return summary, description
+}
+
+// extractRouteMeta reads a RouteMeta variable declaration from a route file.
+func extractRouteMeta(file *ast.File, varName string) (RouteMeta, bool) {
+	var meta RouteMeta
 
-		if fn.Name.Name != funcName {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
 			continue
 		}
 
-		// Extract doc comments
-		if fn.Doc == nil || len(fn.Doc.List) == 0 {
-			return "", ""
-		}
+		for _, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok || len(vs.Names) != 1 || vs.Names[0].Name != varName || len(vs.Values) != 1 {
+				continue
+			}
 
-		var lines []string
-		for _, comment := range fn.Doc.List {
-			text := strings.TrimPrefix(comment.Text, "//")
-			text = strings.TrimPrefix(text, "/*")
-			text = strings.TrimSuffix(text, "*/")
-			text = strings.TrimSpace(text)
-			if text != "" {
-				lines = append(lines, text)
+			compLit, ok := vs.Values[0].(*ast.CompositeLit)
+			if !ok {
+				return meta, false
 			}
-		}
 
-		if len(lines) == 0 {
-			return "", ""
+			for _, elt := range compLit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				switch key.Name {
+				case "Summary":
+					meta.Summary = stringLit(kv.Value)
+				case "Description":
+					meta.Description = stringLit(kv.Value)
+				case "OperationID":
+					meta.OperationID = stringLit(kv.Value)
+				case "Deprecated":
+					ident, ok := kv.Value.(*ast.Ident)
+					meta.Deprecated = ok && ident.Name == "true"
+				case "Tags":
+					if list, ok := kv.Value.(*ast.CompositeLit); ok {
+						for _, tag := range list.Elts {
+							if t := stringLit(tag); t != "" {
+								meta.Tags = append(meta.Tags, t)
+							}
+						}
+					}
+				}
+			}
+			return meta, true
 		}
+	}
 
-		// First line is summary
-		summary = lines[0]
-
-		// Remaining lines are description
-		if len(lines) > 1 {
-			description = strings.Join(lines[1:], "\n")
-		}
+	return meta, false
+}
 
-		return summary, description
+// stringLit returns the value of a string literal, or "" for other expressions.
+func stringLit(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
 	}
-
-	return "", ""
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
 }
 
 // deriveTag derives a tag from the file path.
@@ -350,33 +517,69 @@ func (g *OpenAPIGenerator) buildOperation(route ExtendedRouteInfo) *openapi3.Ope
 		Summary:     route.Summary,
 		Description: route.Description,
 		Tags:        route.Tags,
-		Responses:   openapi3.NewResponses(),
+		OperationID: route.OperationID,
+		Deprecated:  route.Deprecated,
+		Responses:   openapi3.NewResponsesWithCapacity(4),
 	}
 
-	// Add path parameters
+	// Add the security scheme of //nexo:auth
+	if scheme, ok := authSecuritySchemes[route.Directives.Auth]; ok {
+		op.Security = openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate(route.Directives.Auth))
+		g.security[route.Directives.Auth] = &openapi3.SecuritySchemeRef{Value: scheme}
+	}
+
+	// Add path and query parameters
 	params := g.buildParameters(route.Pattern)
+	params = append(params, route.handler.query...)
+	if route.SSE {
+		lastEventID := openapi3.NewHeaderParameter("Last-Event-ID").WithSchema(openapi3.NewStringSchema())
+		lastEventID.Description = "ID of the last event received, sent by clients when they reconnect"
+		params = append(params, &openapi3.ParameterRef{Value: lastEventID})
+	}
 	if len(params) > 0 {
 		op.Parameters = params
 	}
 
-	// Add default responses
-	op.Responses.Set("200", &openapi3.ResponseRef{
-		Value: &openapi3.Response{
-			Description: openapi3.Ptr("Success"),
-		},
-	})
+	// Add responses written by the handler
+	hasSuccess := false
+	for _, resp := range route.handler.responses {
+		status := strconv.Itoa(resp.status)
+		if op.Responses.Value(status) != nil {
+			continue
+		}
+		response := openapi3.NewResponse().WithDescription(http.StatusText(resp.status))
+		if resp.schema != nil {
+			response.Content = openapi3.NewContentWithSchemaRef(resp.schema, []string{resp.contentType})
+		}
+		op.Responses.Set(status, &openapi3.ResponseRef{Value: response})
+		if resp.status < 300 {
+			hasSuccess = true
+		}
+	}
+
+	// Add default responses, an event stream for sse.go
+	if !hasSuccess {
+		response := &openapi3.Response{Description: openapi3.Ptr("Success")}
+		if route.SSE {
+			response.Description = openapi3.Ptr("Server-Sent Events stream")
+			response.Content = openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/event-stream"})
+		}
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: response})
+	}
 
 	// Add 400 for methods with request bodies
 	if route.Method == "POST" || route.Method == "PUT" || route.Method == "PATCH" {
-		op.Responses.Set("400", &openapi3.ResponseRef{
-			Value: &openapi3.Response{
-				Description: openapi3.Ptr("Bad Request"),
-			},
-		})
+		if op.Responses.Value("400") == nil {
+			op.Responses.Set("400", &openapi3.ResponseRef{
+				Value: &openapi3.Response{
+					Description: openapi3.Ptr("Bad Request"),
+				},
+			})
+		}
 	}
 
 	// Add 404 for methods with path parameters
-	if len(params) > 0 && route.Method != "POST" {
+	if len(g.buildParameters(route.Pattern)) > 0 && route.Method != "POST" && op.Responses.Value("404") == nil {
 		op.Responses.Set("404", &openapi3.ResponseRef{
 			Value: &openapi3.Response{
 				Description: openapi3.Ptr("Not Found"),
@@ -384,15 +587,18 @@ func (g *OpenAPIGenerator) buildOperation(route ExtendedRouteInfo) *openapi3.Ope
 		})
 	}
 
-	// Add request body for POST/PUT/PATCH
-	if route.Method == "POST" || route.Method == "PUT" || route.Method == "PATCH" {
+	// Add request body: the type bound with c.Bind, or any object for
+	// POST/PUT/PATCH handlers that read the body some other way
+	requestSchema := route.handler.requestBody
+	if requestSchema == nil && (route.Method == "POST" || route.Method == "PUT" || route.Method == "PATCH") {
+		requestSchema = openapi3.NewObjectSchema().NewRef()
+	}
+	if requestSchema != nil {
 		op.RequestBody = &openapi3.RequestBodyRef{
 			Value: &openapi3.RequestBody{
 				Description: "Request body",
 				Required:    true,
-				Content: openapi3.NewContentWithJSONSchema(&openapi3.Schema{
-					Type: &openapi3.Types{"object"},
-				}),
+				Content:     openapi3.NewContentWithJSONSchemaRef(requestSchema),
 			},
 		}
 	}
@@ -400,6 +606,12 @@ func (g *OpenAPIGenerator) buildOperation(route ExtendedRouteInfo) *openapi3.Ope
 	return op
 }
 
+// authSecuritySchemes are the security schemes of the //nexo:auth values.
+var authSecuritySchemes = map[string]*openapi3.SecurityScheme{
+	"bearer": {Type: "http", Scheme: "bearer"},
+	"basic":  {Type: "http", Scheme: "basic"},
+}
+
 // buildParameters extracts path parameters from a pattern.
 // Example: /users/{id} -> [Parameter{name: "id", in: "path"}]
 func (g *OpenAPIGenerator) buildParameters(pattern string) openapi3.Parameters {
@@ -408,10 +620,7 @@ func (g *OpenAPIGenerator) buildParameters(pattern string) openapi3.Parameters {
 	// Find all {param} patterns
 	segments := strings.Split(pattern, "/")
 	for _, seg := range segments {
-		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
-			paramName := strings.TrimPrefix(seg, "{")
-			paramName = strings.TrimSuffix(paramName, "}")
-
+		if paramName, expr, ok := splitRouteParam(seg); ok {
 			// Skip catch-all parameters (*)
 			if paramName == "*" || strings.Contains(paramName, "...") {
 				continue
@@ -423,9 +632,7 @@ func (g *OpenAPIGenerator) buildParameters(pattern string) openapi3.Parameters {
 				Required:    true,
 				Description: fmt.Sprintf("%s parameter", paramName),
 				Schema: &openapi3.SchemaRef{
-					Value: &openapi3.Schema{
-						Type: &openapi3.Types{"string"},
-					},
+					Value: constraintSchema(expr),
 				},
 			}
 
@@ -436,6 +643,34 @@ func (g *OpenAPIGenerator) buildParameters(pattern string) openapi3.Parameters {
 	return params
 }
 
+// constraintSchema returns the schema for a path parameter matching expr,
+// the regular expression of a route constraint, if any.
+func constraintSchema(expr string) *openapi3.Schema {
+	switch expr {
+	case "":
+		return &openapi3.Schema{Type: &openapi3.Types{"string"}}
+	case ParamConstraints["int"], ParamConstraints["uint"]:
+		return &openapi3.Schema{Type: &openapi3.Types{"integer"}}
+	case ParamConstraints["uuid"]:
+		return &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}
+	default:
+		return &openapi3.Schema{Type: &openapi3.Types{"string"}, Pattern: "^" + expr + "$"}
+	}
+}
+
+// openAPIPath returns pattern with route constraints removed, since OpenAPI
+// paths only name their parameters.
+// Example: /users/{id:[0-9]+} -> /users/{id}
+func openAPIPath(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		if name, _, ok := splitRouteParam(seg); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
 // WriteToFile writes the spec to a file.
 func (g *OpenAPIGenerator) WriteToFile(filepath, format string) error {
 	var data []byte
@@ -0,0 +1,434 @@
+package nexo
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// statusCodes maps http.StatusXxx constant names to their codes, so
+// c.JSON(http.StatusCreated, ...) can be resolved without type checking.
+var statusCodes = func() map[string]int {
+	codes := make(map[string]int)
+	for code := 100; code < 600; code++ {
+		text := http.StatusText(code)
+		if text == "" {
+			continue
+		}
+		name := strings.NewReplacer(" ", "", "-", "", "'", "").Replace(text)
+		codes["Status"+name] = code
+	}
+	return codes
+}()
+
+// routePackage holds the parsed Go files of a route directory.
+type routePackage struct {
+	name  string
+	dir   string
+	files map[string]*ast.File
+	types map[string]*ast.TypeSpec
+}
+
+// handlerSchema is what a handler's body reveals about its request and
+// responses.
+type handlerSchema struct {
+	requestBody *openapi3.SchemaRef
+	responses   []inferredResponse
+	query       openapi3.Parameters
+}
+
+// inferredResponse is a response written by a handler. A nil schema means
+// the response has no body.
+type inferredResponse struct {
+	status      int
+	contentType string
+	schema      *openapi3.SchemaRef
+}
+
+// schemaBuilder turns handler and struct declarations into OpenAPI schemas.
+// Named structs become component schemas referenced by $ref.
+type schemaBuilder struct {
+	fset     *token.FileSet
+	scanner  *Scanner // reads the app directory
+	packages map[string]*routePackage
+	schemas  openapi3.Schemas
+	owners   map[string]string // Component name -> "dir.Type" it was built from
+}
+
+func newSchemaBuilder(scanner *Scanner) *schemaBuilder {
+	return &schemaBuilder{
+		fset:     token.NewFileSet(),
+		scanner:  scanner,
+		packages: make(map[string]*routePackage),
+		schemas:  make(openapi3.Schemas),
+		owners:   make(map[string]string),
+	}
+}
+
+// loadPackage parses the non-test Go files in dir. Results are cached.
+func (b *schemaBuilder) loadPackage(dir string) *routePackage {
+	if pkg, ok := b.packages[dir]; ok {
+		return pkg
+	}
+
+	pkg := &routePackage{
+		dir:   dir,
+		files: make(map[string]*ast.File),
+		types: make(map[string]*ast.TypeSpec),
+	}
+	b.packages[dir] = pkg
+
+	entries, err := b.scanner.readDir(dir)
+	if err != nil {
+		return pkg
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		src, err := b.scanner.readFile(path)
+		if err != nil {
+			continue
+		}
+		file, err := parser.ParseFile(b.fset, path, src, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		pkg.name = file.Name.Name
+		pkg.files[path] = file
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					pkg.types[ts.Name.Name] = ts
+				}
+			}
+		}
+	}
+	return pkg
+}
+
+// analyzeHandler inspects a handler's body for c.Bind, c.JSON, c.String,
+// c.HTML, c.NoContent and c.Query* calls on its context parameter.
+func (b *schemaBuilder) analyzeHandler(pkg *routePackage, fn *ast.FuncDecl) handlerSchema {
+	var hs handlerSchema
+	if fn.Body == nil || len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		return hs
+	}
+	ctxName := fn.Type.Params.List[0].Names[0].Name
+	locals := make(map[string]ast.Expr)
+	seenQuery := make(map[string]bool)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				switch {
+				case n.Type != nil:
+					locals[name.Name] = n.Type
+				case i < len(n.Values):
+					locals[name.Name] = exprType(n.Values[i], locals)
+				}
+			}
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						locals[ident.Name] = exprType(n.Rhs[i], locals)
+					}
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != ctxName {
+				return true
+			}
+			b.inspectContextCall(pkg, sel.Sel.Name, n.Args, locals, seenQuery, &hs)
+		}
+		return true
+	})
+	return hs
+}
+
+func (b *schemaBuilder) inspectContextCall(pkg *routePackage, method string, args []ast.Expr, locals map[string]ast.Expr, seenQuery map[string]bool, hs *handlerSchema) {
+	switch method {
+	case "Bind":
+		if len(args) == 1 {
+			if typ := exprType(args[0], locals); typ != nil {
+				hs.requestBody = b.schemaFor(pkg, typ)
+			}
+		}
+	case "JSON":
+		if len(args) != 2 {
+			return
+		}
+		resp := inferredResponse{status: statusCode(args[0]), contentType: "application/json"}
+		if ident, ok := args[1].(*ast.Ident); !ok || ident.Name != "nil" {
+			if typ := exprType(args[1], locals); typ != nil {
+				resp.schema = b.schemaFor(pkg, typ)
+			} else {
+				resp.schema = openapi3.NewSchemaRef("", &openapi3.Schema{})
+			}
+		}
+		hs.responses = append(hs.responses, resp)
+	case "String", "HTML":
+		if len(args) != 2 {
+			return
+		}
+		contentType := "text/plain"
+		if method == "HTML" {
+			contentType = "text/html"
+		}
+		hs.responses = append(hs.responses, inferredResponse{
+			status:      statusCode(args[0]),
+			contentType: contentType,
+			schema:      openapi3.NewStringSchema().NewRef(),
+		})
+	case "NoContent":
+		hs.responses = append(hs.responses, inferredResponse{status: http.StatusNoContent})
+	case "Query", "QueryDefault", "QueryInt", "QueryBool", "QueryAll":
+		if len(args) == 0 {
+			return
+		}
+		lit, ok := args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil || seenQuery[name] {
+			return
+		}
+		seenQuery[name] = true
+
+		var schema *openapi3.Schema
+		switch method {
+		case "QueryInt":
+			schema = openapi3.NewIntegerSchema()
+		case "QueryBool":
+			schema = openapi3.NewBoolSchema()
+		case "QueryAll":
+			schema = openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+		default:
+			schema = openapi3.NewStringSchema()
+		}
+		hs.query = append(hs.query, &openapi3.ParameterRef{Value: &openapi3.Parameter{
+			Name:   name,
+			In:     "query",
+			Schema: schema.NewRef(),
+		}})
+	}
+}
+
+// statusCode resolves a status argument: an integer literal or an
+// http.StatusXxx constant. Anything else is assumed to be 200.
+func statusCode(expr ast.Expr) int {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if code, err := strconv.Atoi(e.Value); err == nil {
+			return code
+		}
+	case *ast.SelectorExpr:
+		if code, ok := statusCodes[e.Sel.Name]; ok {
+			return code
+		}
+	}
+	return http.StatusOK
+}
+
+// exprType returns the type expression of a value, as far as it can be read
+// from the syntax: composite literals, &T{}, new(T), make(T) and locals.
+func exprType(expr ast.Expr, locals map[string]ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return e.Type
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return exprType(e.X, locals)
+		}
+	case *ast.ParenExpr:
+		return exprType(e.X, locals)
+	case *ast.Ident:
+		return locals[e.Name]
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && (ident.Name == "new" || ident.Name == "make") && len(e.Args) > 0 {
+			return e.Args[0]
+		}
+	}
+	return nil
+}
+
+// schemaFor builds the schema of a type expression declared in pkg.
+func (b *schemaBuilder) schemaFor(pkg *routePackage, expr ast.Expr) *openapi3.SchemaRef {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if schema := basicSchema(t.Name); schema != nil {
+			return schema.NewRef()
+		}
+		if ts, ok := pkg.types[t.Name]; ok {
+			return b.namedSchema(pkg, ts)
+		}
+	case *ast.StarExpr:
+		return b.schemaFor(pkg, t.X)
+	case *ast.ParenExpr:
+		return b.schemaFor(pkg, t.X)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return openapi3.NewBytesSchema().NewRef()
+		}
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type:  &openapi3.Types{openapi3.TypeArray},
+			Items: b.schemaFor(pkg, t.Elt),
+		}}
+	case *ast.MapType:
+		schema := openapi3.NewObjectSchema()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: b.schemaFor(pkg, t.Value)}
+		return schema.NewRef()
+	case *ast.StructType:
+		return b.structSchema(pkg, t).NewRef()
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			switch x.Name + "." + t.Sel.Name {
+			case "time.Time":
+				return openapi3.NewDateTimeSchema().NewRef()
+			case "time.Duration":
+				return openapi3.NewInt64Schema().NewRef()
+			case "uuid.UUID":
+				return openapi3.NewUUIDSchema().NewRef()
+			}
+		}
+	}
+	return openapi3.NewSchemaRef("", &openapi3.Schema{})
+}
+
+// basicSchema returns the schema of a predeclared type, or nil.
+func basicSchema(name string) *openapi3.Schema {
+	switch name {
+	case "string":
+		return openapi3.NewStringSchema()
+	case "bool":
+		return openapi3.NewBoolSchema()
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32", "byte", "rune":
+		return openapi3.NewIntegerSchema()
+	case "int64", "uint64":
+		return openapi3.NewInt64Schema()
+	case "float32", "float64":
+		return openapi3.NewFloat64Schema()
+	case "any":
+		return &openapi3.Schema{}
+	}
+	return nil
+}
+
+// namedSchema registers a named type as a component schema and returns a
+// reference to it. Types with the same name in different packages are
+// prefixed with their package name.
+func (b *schemaBuilder) namedSchema(pkg *routePackage, ts *ast.TypeSpec) *openapi3.SchemaRef {
+	if _, ok := ts.Type.(*ast.StructType); !ok {
+		return b.schemaFor(pkg, ts.Type)
+	}
+
+	owner := pkg.dir + "." + ts.Name.Name
+	name := ts.Name.Name
+	if existing, ok := b.owners[name]; ok && existing != owner {
+		name = toTitleCase(pkg.name) + ts.Name.Name
+	}
+	ref := "#/components/schemas/" + name
+	if _, ok := b.owners[name]; ok {
+		return openapi3.NewSchemaRef(ref, nil)
+	}
+
+	// Register before building so recursive types terminate
+	b.owners[name] = owner
+	b.schemas[name] = openapi3.NewSchemaRef("", nil)
+	b.schemas[name].Value = b.structSchema(pkg, ts.Type.(*ast.StructType))
+	return openapi3.NewSchemaRef(ref, nil)
+}
+
+// structSchema builds an object schema from struct fields, following
+// encoding/json: json tags rename or skip fields, embedded structs are
+// flattened, and fields without omitempty that aren't pointers are required.
+func (b *schemaBuilder) structSchema(pkg *routePackage, st *ast.StructType) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		var propNames []string
+		switch {
+		case len(field.Names) == 0 && name == "":
+			b.embedFields(pkg, field.Type, schema)
+			continue
+		case len(field.Names) == 0:
+			propNames = []string{name}
+		default:
+			for _, ident := range field.Names {
+				if !ident.IsExported() {
+					continue
+				}
+				if name != "" {
+					propNames = append(propNames, name)
+				} else {
+					propNames = append(propNames, ident.Name)
+				}
+			}
+		}
+
+		_, pointer := field.Type.(*ast.StarExpr)
+		for _, propName := range propNames {
+			schema.WithPropertyRef(propName, b.schemaFor(pkg, field.Type))
+			if !pointer && !strings.Contains(opts, "omitempty") {
+				schema.Required = append(schema.Required, propName)
+			}
+		}
+	}
+	return schema
+}
+
+// embedFields copies the properties of an embedded struct into schema.
+func (b *schemaBuilder) embedFields(pkg *routePackage, expr ast.Expr, schema *openapi3.Schema) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return
+	}
+	ts, ok := pkg.types[ident.Name]
+	if !ok {
+		return
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		return
+	}
+
+	embedded := b.structSchema(pkg, st)
+	for name, prop := range embedded.Properties {
+		if _, exists := schema.Properties[name]; !exists {
+			schema.WithPropertyRef(name, prop)
+		}
+	}
+	schema.Required = append(schema.Required, embedded.Required...)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 )
 
 func TestOpenAPIGenerator_BasicRoutes(t *testing.T) {
@@ -134,6 +135,43 @@ func Get(c *nexo.Context) error {
 	}
 }
 
+func TestOpenAPIGenerator_ConstrainedParameters(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	routeDir := filepath.Join(appDir, "api", "users", "[id:int]")
+	if err := os.MkdirAll(routeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	routeContent := `package id
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+`
+	if err := os.WriteFile(filepath.Join(routeDir, "route.go"), []byte(routeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := NewOpenAPIGenerator(appDir, OpenAPIConfig{Title: "Test API", Version: "1.0.0"}).Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	pathItem := doc.Paths.Find("/api/users/{id}")
+	if pathItem == nil || pathItem.Get == nil {
+		t.Fatal("Expected GET /api/users/{id} without the constraint in the path")
+	}
+	param := pathItem.Get.Parameters[0].Value
+	if param.Name != "id" {
+		t.Errorf("Expected parameter name 'id', got '%s'", param.Name)
+	}
+	if !param.Schema.Value.Type.Is("integer") {
+		t.Errorf("Expected integer schema, got %v", param.Schema.Value.Type)
+	}
+}
+
 func TestOpenAPIGenerator_MultipleMethods(t *testing.T) {
 	// Create temp app directory
 	tmpDir := t.TempDir()
@@ -517,3 +555,377 @@ func containsMiddle(s, substr string) bool {
 	}
 	return false
 }
+
+func TestOpenAPIGenerator_HandlerTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	if err := os.MkdirAll(filepath.Join(appDir, "api", "users"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	routeContent := `package users
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+type Timestamps struct {
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+type User struct {
+	Timestamps
+	ID     int      ` + "`json:\"id\"`" + `
+	Name   string   ` + "`json:\"name\"`" + `
+	Email  *string  ` + "`json:\"email\"`" + `
+	Roles  []string ` + "`json:\"roles,omitempty\"`" + `
+	secret string
+	Hash   string ` + "`json:\"-\"`" + `
+}
+
+type CreateUserRequest struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// Get lists users
+func Get(c *nexo.Context) error {
+	_ = c.QueryInt("page", 1)
+	_ = c.Query("q")
+	users := []User{}
+	return c.JSON(http.StatusOK, users)
+}
+
+// Post creates a user
+func Post(c *nexo.Context) error {
+	var req CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "invalid"})
+	}
+	return c.JSON(http.StatusCreated, &User{Name: req.Name})
+}
+
+func Delete(c *nexo.Context) error {
+	return c.NoContent()
+}
+`
+	if err := os.WriteFile(filepath.Join(appDir, "api", "users", "route.go"), []byte(routeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewOpenAPIGenerator(appDir, OpenAPIConfig{Title: "Test API"})
+	doc, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	// Component schemas
+	if doc.Components == nil {
+		t.Fatal("Expected components to be set")
+	}
+	user := doc.Components.Schemas["User"]
+	if user == nil || user.Value == nil {
+		t.Fatal("Expected User schema")
+	}
+	for _, prop := range []string{"id", "name", "email", "roles", "created_at"} {
+		if user.Value.Properties[prop] == nil {
+			t.Errorf("Expected User.%s property", prop)
+		}
+	}
+	for _, prop := range []string{"secret", "Hash", "Timestamps"} {
+		if user.Value.Properties[prop] != nil {
+			t.Errorf("Did not expect User.%s property", prop)
+		}
+	}
+	if got := user.Value.Properties["created_at"].Value.Format; got != "date-time" {
+		t.Errorf("Expected created_at format date-time, got %q", got)
+	}
+	required := map[string]bool{}
+	for _, r := range user.Value.Required {
+		required[r] = true
+	}
+	if !required["id"] || !required["name"] || required["email"] || required["roles"] {
+		t.Errorf("Unexpected required fields: %v", user.Value.Required)
+	}
+
+	pathItem := doc.Paths.Find("/api/users")
+	if pathItem == nil {
+		t.Fatal("Expected /api/users path to exist")
+	}
+
+	// GET: array of users and query parameters
+	getResp := pathItem.Get.Responses.Value("200")
+	if getResp == nil {
+		t.Fatal("Expected GET 200 response")
+	}
+	schema := getResp.Value.Content.Get("application/json").Schema.Value
+	if !schema.Type.Is("array") || schema.Items.Ref != "#/components/schemas/User" {
+		t.Errorf("Expected array of User, got %+v", schema)
+	}
+	if len(pathItem.Get.Parameters) != 2 {
+		t.Fatalf("Expected 2 query parameters, got %d", len(pathItem.Get.Parameters))
+	}
+	page := pathItem.Get.Parameters[0].Value
+	if page.Name != "page" || page.In != "query" || !page.Schema.Value.Type.Is("integer") {
+		t.Errorf("Unexpected page parameter: %+v", page)
+	}
+
+	// POST: typed request body and 201/400 responses
+	body := pathItem.Post.RequestBody.Value.Content.Get("application/json").Schema
+	if body.Ref != "#/components/schemas/CreateUserRequest" {
+		t.Errorf("Expected CreateUserRequest body, got %q", body.Ref)
+	}
+	created := pathItem.Post.Responses.Value("201")
+	if created == nil || created.Value.Content.Get("application/json").Schema.Ref != "#/components/schemas/User" {
+		t.Error("Expected 201 response with User schema")
+	}
+	if pathItem.Post.Responses.Value("200") != nil {
+		t.Error("Did not expect a default 200 response when the handler responds with 201")
+	}
+	if pathItem.Post.Responses.Value("400") == nil {
+		t.Error("Expected 400 response")
+	}
+
+	// DELETE: no content
+	if pathItem.Delete.Responses.Value("204") == nil {
+		t.Error("Expected DELETE 204 response")
+	}
+}
+
+func TestOpenAPIGenerator_RouteMeta(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	if err := os.MkdirAll(filepath.Join(appDir, "api", "orders"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	routeContent := `package orders
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+var Meta = nexo.RouteMeta{
+	Tags: []string{"billing"},
+}
+
+var PostMeta = nexo.RouteMeta{
+	Summary:     "Place an order",
+	OperationID: "placeOrder",
+	Deprecated:  true,
+}
+
+// Get lists orders
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+
+// Post creates an order
+func Post(c *nexo.Context) error {
+	return c.JSON(201, nil)
+}
+`
+	if err := os.WriteFile(filepath.Join(appDir, "api", "orders", "route.go"), []byte(routeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewOpenAPIGenerator(appDir, OpenAPIConfig{Title: "Test API"})
+	doc, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	pathItem := doc.Paths.Find("/api/orders")
+	if pathItem == nil {
+		t.Fatal("Expected /api/orders path to exist")
+	}
+
+	if pathItem.Get.Summary != "Get lists orders" {
+		t.Errorf("Expected comment summary, got %q", pathItem.Get.Summary)
+	}
+	if len(pathItem.Get.Tags) != 1 || pathItem.Get.Tags[0] != "billing" {
+		t.Errorf("Expected tags [billing], got %v", pathItem.Get.Tags)
+	}
+
+	post := pathItem.Post
+	if post.Summary != "Place an order" {
+		t.Errorf("Expected meta summary, got %q", post.Summary)
+	}
+	if post.OperationID != "placeOrder" {
+		t.Errorf("Expected operationId placeOrder, got %q", post.OperationID)
+	}
+	if !post.Deprecated {
+		t.Error("Expected POST to be deprecated")
+	}
+	if len(post.Tags) != 1 || post.Tags[0] != "billing" {
+		t.Errorf("Expected file-wide tags on POST, got %v", post.Tags)
+	}
+}
+
+func TestOpenAPIGenerator_Directives(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	if err := os.MkdirAll(filepath.Join(appDir, "api", "users"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	routeContent := `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+// Get returns a user.
+// It includes the user's teams.
+//
+//nexo:summary Get a user
+//nexo:tags users admin
+//nexo:auth bearer
+func Get(c *nexo.Context) error {
+	return c.JSON(200, nil)
+}
+
+// Delete removes a user.
+//
+//nexo:deprecated
+//nexo:auth basic
+func Delete(c *nexo.Context) error {
+	return c.NoContent()
+}
+
+// Put replaces a user.
+//nexo:auth oauth
+func Put(c *nexo.Context) error {
+	return c.NoContent()
+}
+`
+	if err := os.WriteFile(filepath.Join(appDir, "api", "users", "route.go"), []byte(routeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewOpenAPIGenerator(appDir, OpenAPIConfig{Title: "Test API"})
+	doc, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	pathItem := doc.Paths.Find("/api/users")
+	if pathItem == nil {
+		t.Fatal("Expected /api/users path to exist")
+	}
+
+	get := pathItem.Get
+	if get.Summary != "Get a user" {
+		t.Errorf("Expected directive summary, got %q", get.Summary)
+	}
+	if get.Description != "It includes the user's teams." {
+		t.Errorf("Expected description without directives, got %q", get.Description)
+	}
+	if len(get.Tags) != 2 || get.Tags[0] != "users" || get.Tags[1] != "admin" {
+		t.Errorf("Expected tags [users admin], got %v", get.Tags)
+	}
+	if get.Security == nil || len(*get.Security) != 1 || (*get.Security)[0]["bearer"] == nil {
+		t.Errorf("Expected bearer security, got %v", get.Security)
+	}
+
+	del := pathItem.Delete
+	if !del.Deprecated {
+		t.Error("Expected DELETE to be deprecated")
+	}
+	if del.Summary != "Delete removes a user." {
+		t.Errorf("Expected comment summary, got %q", del.Summary)
+	}
+
+	// Unknown auth schemes are left out of the spec
+	if pathItem.Put.Security != nil {
+		t.Errorf("Expected no security for an unknown scheme, got %v", pathItem.Put.Security)
+	}
+
+	schemes := doc.Components.SecuritySchemes
+	if len(schemes) != 2 || schemes["bearer"].Value.Scheme != "bearer" || schemes["basic"].Value.Scheme != "basic" {
+		t.Errorf("Expected bearer and basic security schemes, got %v", schemes)
+	}
+}
+
+func TestNewOpenAPIGeneratorFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/users/route.go": {Data: []byte(`package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// Get returns the current user.
+func Get(c *nexo.Context) error {
+	return c.JSON(200, User{})
+}
+`)},
+	}
+
+	doc, err := NewOpenAPIGeneratorFS(fsys, OpenAPIConfig{Title: "Test API"}).Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	pathItem := doc.Paths.Find("/api/users")
+	if pathItem == nil || pathItem.Get == nil {
+		t.Fatal("Expected GET /api/users")
+	}
+	if pathItem.Get.Summary != "Get returns the current user." {
+		t.Errorf("Expected the summary from the comment, got %q", pathItem.Get.Summary)
+	}
+	if len(pathItem.Get.Tags) != 1 || pathItem.Get.Tags[0] != "users" {
+		t.Errorf("Expected tag users, got %v", pathItem.Get.Tags)
+	}
+	if doc.Components.Schemas["User"] == nil {
+		t.Errorf("Expected the User schema, got %v", doc.Components.Schemas)
+	}
+}
+
+func TestOpenAPIGenerator_ConnFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"events/sse.go": {Data: []byte(`package events
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+// Stream sends order updates.
+//
+//nexo:tags orders
+func Stream(c *nexo.Context, sse *nexo.SSEWriter) error {
+	return nil
+}
+`)},
+		"chat/ws.go": {Data: []byte(`package chat
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Socket(c *nexo.Context, ws *nexo.WebSocketConn) error {
+	return nil
+}
+`)},
+	}
+
+	doc, err := NewOpenAPIGeneratorFS(fsys, OpenAPIConfig{Title: "Test API"}).Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	if doc.Paths.Find("/chat") != nil {
+		t.Error("Expected WebSocket routes to be left out")
+	}
+	pathItem := doc.Paths.Find("/events")
+	if pathItem == nil || pathItem.Get == nil {
+		t.Fatal("Expected GET /events")
+	}
+	op := pathItem.Get
+	if op.Summary != "Stream sends order updates." || len(op.Tags) != 1 || op.Tags[0] != "orders" {
+		t.Errorf("Expected the summary and tags of Stream, got %q %v", op.Summary, op.Tags)
+	}
+	if op.Parameters.GetByInAndName("header", "Last-Event-ID") == nil {
+		t.Error("Expected the Last-Event-ID header")
+	}
+	resp := op.Responses.Value("200")
+	if resp == nil || resp.Value.Content.Get("text/event-stream") == nil {
+		t.Errorf("Expected a text/event-stream response, got %+v", resp)
+	}
+}
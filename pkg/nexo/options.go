@@ -1,5 +1,10 @@
 package nexo
 
+import (
+	"os"
+	"time"
+)
+
 // Option is a functional option for configuring the App.
 type Option func(*App)
 
@@ -88,3 +93,273 @@ func WithHotReload(enabled bool) Option {
 		a.config.Dev.HotReload = enabled
 	}
 }
+
+// WithCookieSecret sets the secret used by SetSignedCookie and
+// SetEncryptedCookie. Use a random string of at least 32 bytes, kept out of
+// source control.
+//
+// To rotate the secret, pass the new one first followed by the old ones:
+// new cookies use the first secret, and cookies made with any of them are
+// still accepted.
+func WithCookieSecret(secrets ...string) Option {
+	return func(a *App) {
+		a.cookieCodec = newCookieCodec(secrets)
+	}
+}
+
+// WithValidator sets the validator that Bind, BindQuery and BindForm run on
+// the decoded value. Failures are sent as 422 responses listing the invalid
+// fields.
+//
+// Example with a custom validator:
+//
+//	app := nexo.New(nexo.WithValidator(nexo.ValidatorFunc(func(v any) error {
+//	    if u, ok := v.(*CreateUser); ok {
+//	        errs := &nexo.ValidationError{}
+//	        if u.Name == "" {
+//	            errs.Add("name", "is required")
+//	        }
+//	        return errs.Err()
+//	    }
+//	    return nil
+//	})))
+func WithValidator(v Validator) Option {
+	return func(a *App) {
+		a.validator = v
+	}
+}
+
+// WithAuthenticator sets the Authenticator that routes with
+// RouteConfig.RequireAuth run before their handlers.
+//
+// Example:
+//
+//	app := nexo.New(nexo.WithAuthenticator(func(c *nexo.Context) error {
+//	    user, err := sessions.User(c.Request)
+//	    if err != nil {
+//	        return nexo.Unauthorized("login required")
+//	    }
+//	    CurrentUser.Set(c, user)
+//	    return nil
+//	}))
+func WithAuthenticator(auth Authenticator) Option {
+	return func(a *App) {
+		a.authenticator = auth
+	}
+}
+
+// WithAutoHead sets whether routes with a GET handler but no HEAD handler
+// answer HEAD requests with the GET response's headers (default: true).
+func WithAutoHead(enabled bool) Option {
+	return func(a *App) {
+		a.routeTree.SetAutoHead(enabled)
+	}
+}
+
+// WithAutoOptions sets whether routes without an OPTIONS handler answer
+// OPTIONS requests with a 204 and an Allow header listing their methods
+// (default: true). With it off, CORS preflights need an Options handler
+// or middleware that answers them.
+func WithAutoOptions(enabled bool) Option {
+	return func(a *App) {
+		a.routeTree.SetAutoOptions(enabled)
+	}
+}
+
+// WithCacheStore sets the store the Cache middleware keeps responses in,
+// e.g. a Redis-backed store shared by all instances of the app. Default is
+// an in-memory store.
+func WithCacheStore(store CacheStore) Option {
+	return func(a *App) {
+		a.cacheStore = store
+	}
+}
+
+// WithTrustedProxies lists the load balancers and reverse proxies, as CIDRs
+// or IPs, whose X-Forwarded-For headers are trusted. ClientIP and IPFilter
+// then read the client from the header, skipping the trusted hops. Requests
+// from other peers are identified by their own address. It panics if an
+// entry isn't a valid IP or CIDR.
+//
+// Example:
+//
+//	app := nexo.New(nexo.WithTrustedProxies("10.0.0.0/8"))
+func WithTrustedProxies(proxies ...string) Option {
+	return func(a *App) {
+		a.trustedProxies = parsePrefixes(proxies, "WithTrustedProxies")
+	}
+}
+
+// WithHandlerTimeout sets a deadline for every request's context, so slow
+// downstream calls made with c.Context() are canceled instead of hanging.
+// Handlers that return context.DeadlineExceeded get a 504 response. Use
+// c.WithTimeout to shorten the deadline for a single handler.
+func WithHandlerTimeout(d time.Duration) Option {
+	return func(a *App) {
+		a.handlerTimeout = d
+	}
+}
+
+// WithShutdownTimeout sets how long shutdown waits for in-flight requests
+// before closing their connections (default: DefaultShutdownTimeout).
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(a *App) {
+		a.shutdownTimeout = d
+	}
+}
+
+// WithReadTimeout sets the maximum duration for reading an entire request,
+// including the body (default: 15s).
+func WithReadTimeout(d time.Duration) Option {
+	return func(a *App) {
+		a.config.Server.ReadTimeout = d
+	}
+}
+
+// WithReadHeaderTimeout sets the maximum duration for reading request
+// headers, which protects against slowloris attacks (default: 5s).
+func WithReadHeaderTimeout(d time.Duration) Option {
+	return func(a *App) {
+		a.config.Server.ReadHeaderTimeout = d
+	}
+}
+
+// WithWriteTimeout sets the maximum duration before timing out writes of
+// the response (default: 15s). Use a negative value for long-lived
+// streams such as SSE.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(a *App) {
+		a.config.Server.WriteTimeout = d
+	}
+}
+
+// WithIdleTimeout sets how long keep-alive connections wait for the next
+// request (default: 60s).
+func WithIdleTimeout(d time.Duration) Option {
+	return func(a *App) {
+		a.config.Server.IdleTimeout = d
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of request headers, including
+// the request line (default: 1 MB).
+func WithMaxHeaderBytes(n int) Option {
+	return func(a *App) {
+		a.config.Server.MaxHeaderBytes = n
+	}
+}
+
+// WithGracefulRestart enables zero-downtime restarts on Unix systems. On
+// SIGUSR2, the app starts its binary again with the same arguments and
+// passes it the listening socket. Once the new process serves, the old one
+// stops accepting connections and shuts down gracefully, so deploys can
+// replace the binary in place without refusing a connection. If the new
+// process fails to start, the old one keeps serving.
+//
+// Example:
+//
+//	app := nexo.New(nexo.WithGracefulRestart())
+//
+//	// After replacing the binary:
+//	// kill -USR2 <pid>
+func WithGracefulRestart() Option {
+	return func(a *App) {
+		a.gracefulRestart = true
+	}
+}
+
+// WithUnixSocket makes Listen serve on a Unix domain socket at path instead
+// of a TCP port, for deployments behind a local reverse proxy. The socket
+// file gets perm, e.g. 0660, unless it is zero. A stale socket from a
+// previous run is replaced.
+func WithUnixSocket(path string, perm os.FileMode) Option {
+	return func(a *App) {
+		a.unixSocket = path
+		a.unixSocketPerm = perm
+	}
+}
+
+// WithMetrics serves the metrics the Metrics middleware records at path,
+// or DefaultMetricsPath if path is empty, in the Prometheus text format.
+//
+// Example:
+//
+//	app := nexo.New(nexo.WithMetrics(""))
+//	app.Use(nexo.Metrics())
+func WithMetrics(path string) Option {
+	return func(a *App) {
+		if path == "" {
+			path = DefaultMetricsPath
+		}
+		a.metricsPath = path
+	}
+}
+
+// WithDevDashboard enables or disables the dev dashboard at
+// DevDashboardPath, which shows the registered routes, the middleware that
+// applies to each, proxy matchers, recent requests and the config. It is
+// enabled by default under nexo dev.
+func WithDevDashboard(enabled bool) Option {
+	return func(a *App) {
+		a.devDashboard = enabled
+	}
+}
+
+// WithLatencySummary collects the latency of each route and prints a
+// summary table of request counts, p50/p95/p99 latencies and error rates
+// every interval, or only on demand if interval is zero: on SIGINFO
+// (Ctrl+T) where available, from App.WriteLatencySummary, or at
+// DevDashboardPath + "/latency". It is enabled by default under nexo dev.
+func WithLatencySummary(interval time.Duration) Option {
+	return func(a *App) {
+		a.latencySummary = true
+		a.latencyInterval = interval
+	}
+}
+
+// WithDebugEndpoints mounts the pprof profiles under /debug/pprof and the
+// expvar variables at /debug/vars, so production issues can be profiled
+// without code changes. Protect them with WithDebugToken.
+func WithDebugEndpoints(enabled bool) Option {
+	return func(a *App) {
+		a.config.Dev.Debug = enabled
+	}
+}
+
+// WithDebugToken requires token to access the debug endpoints, sent as
+// "Authorization: Bearer <token>" or the token query parameter, e.g.
+// go tool pprof "https://example.com/debug/pprof/heap?token=...".
+func WithDebugToken(token string) Option {
+	return func(a *App) {
+		a.config.Dev.DebugToken = token
+	}
+}
+
+// WithProblemDetails writes errors returned by handlers as RFC 7807
+// application/problem+json documents. It is the same as
+// app.OnError(nexo.ProblemErrorHandler).
+func WithProblemDetails() Option {
+	return func(a *App) {
+		a.errorHandler = ProblemErrorHandler
+	}
+}
+
+// WithMaintenance configures maintenance mode: the clients and paths still
+// served while it's on, the response, and ways to switch it without a
+// deploy. Switch it in code with App.SetMaintenance.
+//
+// Example:
+//
+//	app := nexo.New(nexo.WithMaintenance(nexo.MaintenanceConfig{
+//	    Enabled:    os.Getenv("MAINTENANCE") == "1",
+//	    AllowIPs:   []string{"10.0.0.0/8"},
+//	    AllowPaths: []string{"/health"},
+//	    AdminPath:  "/_maintenance",
+//	    AdminToken: os.Getenv("MAINTENANCE_TOKEN"),
+//	    Signal:     syscall.SIGUSR1,
+//	}))
+func WithMaintenance(config MaintenanceConfig) Option {
+	return func(a *App) {
+		a.maintenance = newMaintenance(config)
+	}
+}
@@ -1,7 +1,13 @@
 package nexo
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestWithPort(t *testing.T) {
@@ -203,3 +209,92 @@ func TestNewWithMultipleOptions(t *testing.T) {
 		t.Error("expected hot_reload to be false")
 	}
 }
+
+func TestWithHandlerTimeout(t *testing.T) {
+	app := New(WithHandlerTimeout(20 * time.Millisecond))
+	app.DisableLogger()
+	app.Get("/slow", func(c *Context) error {
+		select {
+		case <-c.Context().Done():
+			return c.Context().Err()
+		case <-time.After(time.Second):
+			return c.String(http.StatusOK, "too late")
+		}
+	})
+	app.Get("/deadline", func(c *Context) error {
+		if _, ok := c.Context().Deadline(); !ok {
+			return c.String(http.StatusOK, "no deadline")
+		}
+		return c.String(http.StatusOK, "deadline")
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout || !strings.Contains(w.Body.String(), "request timeout") {
+		t.Errorf("expected 504, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deadline", nil))
+	if w.Body.String() != "deadline" {
+		t.Errorf("expected the request context to have a deadline, got %q", w.Body.String())
+	}
+}
+
+func TestServerOptions(t *testing.T) {
+	app := New(
+		WithReadTimeout(30*time.Second),
+		WithReadHeaderTimeout(2*time.Second),
+		WithWriteTimeout(-1),
+		WithIdleTimeout(2*time.Minute),
+		WithMaxHeaderBytes(8<<10),
+	)
+
+	want := ServerConfig{
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      -1,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    8 << 10,
+	}
+	if app.config.Server != want {
+		t.Errorf("expected %+v, got %+v", want, app.config.Server)
+	}
+
+	// Zero values, e.g. from WithConfig, use the defaults
+	if got := (ServerConfig{WriteTimeout: -1}).withDefaults(); got.ReadTimeout != 15*time.Second || got.WriteTimeout != -1 {
+		t.Errorf("unexpected defaults %+v", got)
+	}
+}
+
+func TestWithMaxHeaderBytes(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	app := New(WithMaxHeaderBytes(1 << 10))
+	app.DisableLogger()
+	app.Get("/", func(c *Context) error { return c.NoContent() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.serve(ctx, ln) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	req, _ := http.NewRequest(http.MethodGet, "http://"+ln.Addr().String()+"/", nil)
+	req.Header.Set("X-Large", strings.Repeat("a", 8<<10))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("expected 431, got %d", resp.StatusCode)
+	}
+}
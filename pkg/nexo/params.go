@@ -0,0 +1,77 @@
+package nexo
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Param returns the URL parameter key converted to T. T may be any type
+// BindQuery supports: strings, bools, integers, floats, time.Time,
+// time.Duration and types implementing encoding.TextUnmarshaler. Missing
+// or unconvertible values return a 400 HTTPError naming the parameter.
+//
+// Example:
+//
+//	id, err := nexo.Param[int64](c, "id")
+//	if err != nil {
+//	    return err // 400: invalid path parameter "id": "abc" is not an integer
+//	}
+func Param[T any](c *Context, key string) (T, error) {
+	return parseParam[T](key, c.Param(key), "path parameter")
+}
+
+// Query returns the query parameter key converted to T, like Param. Use
+// BindQuery for optional parameters with defaults.
+//
+// Example:
+//
+//	since, err := nexo.Query[time.Time](c, "since")
+func Query[T any](c *Context, key string) (T, error) {
+	return parseParam[T](key, c.query.Get(key), "query parameter")
+}
+
+// parseParam converts raw to T, returning a 400 HTTPError on failure. kind
+// names the parameter in error messages.
+func parseParam[T any](key, raw, kind string) (T, error) {
+	var v T
+	if raw == "" {
+		return v, BadRequest(fmt.Sprintf("missing %s %q", kind, key))
+	}
+	if err := setValue(reflect.ValueOf(&v).Elem(), raw, ""); err != nil {
+		return v, NewHTTPErrorWithCause(http.StatusBadRequest,
+			fmt.Sprintf("invalid %s %q: %v", kind, key, err), err)
+	}
+	return v, nil
+}
+
+// parseUUID reports whether s is a UUID in the 8-4-4-4-12 hex form, with
+// or without braces or a urn:uuid: prefix, and returns it in lowercase
+// canonical form.
+func parseUUID(s string) (string, bool) {
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	if len(s) == 38 && s[0] == '{' && s[37] == '}' {
+		s = s[1:37]
+	}
+	if len(s) != 36 {
+		return "", false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return "", false
+			}
+		default:
+			if !isHex(s[i]) {
+				return "", false
+			}
+		}
+	}
+	return s, true
+}
+
+func isHex(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'f'
+}
@@ -0,0 +1,116 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newParamContext(target string, params map[string]string) *Context {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	for k, v := range params {
+		c.SetParam(k, v)
+	}
+	return c
+}
+
+func TestContext_TypedAccessors(t *testing.T) {
+	c := newParamContext("/?big=9007199254740993&price=9.99&since=2024-03-01&bad=x", map[string]string{
+		"id":  "9007199254740993",
+		"bad": "x",
+	})
+
+	if got := c.ParamInt64("id", 0); got != 9007199254740993 {
+		t.Errorf("ParamInt64(id) = %d", got)
+	}
+	if got := c.ParamInt64("bad", -1); got != -1 {
+		t.Errorf("ParamInt64(bad) = %d, want default", got)
+	}
+	if got := c.QueryInt64("big", 0); got != 9007199254740993 {
+		t.Errorf("QueryInt64(big) = %d", got)
+	}
+	if got := c.QueryInt64("missing", 7); got != 7 {
+		t.Errorf("QueryInt64(missing) = %d, want default", got)
+	}
+	if got := c.QueryFloat("price", 0); got != 9.99 {
+		t.Errorf("QueryFloat(price) = %v", got)
+	}
+	if got := c.QueryFloat("bad", 1.5); got != 1.5 {
+		t.Errorf("QueryFloat(bad) = %v, want default", got)
+	}
+	if got := c.QueryTime("since", time.DateOnly, time.Time{}); !got.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("QueryTime(since) = %v", got)
+	}
+	def := time.Unix(0, 0)
+	if got := c.QueryTime("bad", time.DateOnly, def); !got.Equal(def) {
+		t.Errorf("QueryTime(bad) = %v, want default", got)
+	}
+}
+
+func TestContext_ParamUUID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", "7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", true},
+		{"7F1B2C3D-4E5F-6A7B-8C9D-0E1F2A3B4C5D", "7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", true},
+		{"{7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d}", "7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", true},
+		{"urn:uuid:7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", "7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", true},
+		{"7f1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d", "", false},
+		{"7f1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5g", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, err := newParamContext("/", map[string]string{"id": tt.value}).ParamUUID("id")
+		if tt.ok {
+			if err != nil || got != tt.want {
+				t.Errorf("ParamUUID(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+			continue
+		}
+		if httpErr, ok := IsHTTPError(err); !ok || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("ParamUUID(%q) error = %v, want a 400", tt.value, err)
+		}
+	}
+}
+
+func TestParamAndQueryGeneric(t *testing.T) {
+	c := newParamContext("/?since=2024-03-01T10:00:00Z&ttl=90s&limit=abc&active=true", map[string]string{
+		"id":   "42",
+		"slug": "hello-world",
+	})
+
+	if id, err := Param[int64](c, "id"); err != nil || id != 42 {
+		t.Errorf("Param[int64](id) = %d, %v", id, err)
+	}
+	if slug, err := Param[string](c, "slug"); err != nil || slug != "hello-world" {
+		t.Errorf("Param[string](slug) = %q, %v", slug, err)
+	}
+	if since, err := Query[time.Time](c, "since"); err != nil || !since.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Query[time.Time](since) = %v, %v", since, err)
+	}
+	if ttl, err := Query[time.Duration](c, "ttl"); err != nil || ttl != 90*time.Second {
+		t.Errorf("Query[time.Duration](ttl) = %v, %v", ttl, err)
+	}
+	if active, err := Query[*bool](c, "active"); err != nil || active == nil || !*active {
+		t.Errorf("Query[*bool](active) = %v, %v", active, err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"invalid", func() error { _, err := Query[int](c, "limit"); return err }(), `invalid query parameter "limit": "abc" is not an integer`},
+		{"missing query", func() error { _, err := Query[int](c, "page"); return err }(), `missing query parameter "page"`},
+		{"missing param", func() error { _, err := Param[uint](c, "id2"); return err }(), `missing path parameter "id2"`},
+	}
+	for _, tt := range tests {
+		httpErr, ok := IsHTTPError(tt.err)
+		if !ok || httpErr.Code != http.StatusBadRequest || httpErr.Message != tt.want {
+			t.Errorf("%s: error = %v, want 400 %q", tt.name, tt.err, tt.want)
+		}
+	}
+}
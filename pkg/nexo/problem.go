@@ -0,0 +1,199 @@
+package nexo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// ProblemError is an RFC 7807 problem details document. Return one from a
+// handler to control every member of the response, including extension
+// members.
+//
+// Example:
+//
+//	return nexo.NewProblem(403, "Your balance is 30, but that costs 50.").
+//	    WithType("https://example.com/probs/out-of-credit").
+//	    WithTitle("You do not have enough credit.").
+//	    With("balance", 30)
+type ProblemError struct {
+	// Type is a URI identifying the problem type (default: "about:blank")
+	Type string
+	// Title is a short summary of the problem type (default: the status text)
+	Title string
+	// Status is the HTTP status code
+	Status int
+	// Detail explains this occurrence of the problem
+	Detail string
+	// Instance is a URI identifying this occurrence (default: the request path)
+	Instance string
+	// Extensions are additional members of the document
+	Extensions map[string]any
+	// Err is the underlying cause, which is not sent to clients
+	Err error
+}
+
+// NewProblem creates a ProblemError with status and detail. The title
+// defaults to the status text.
+func NewProblem(status int, detail string) *ProblemError {
+	return &ProblemError{
+		Status: status,
+		Title:  http.StatusText(status),
+		Detail: detail,
+	}
+}
+
+// WithType sets the problem type URI.
+func (p *ProblemError) WithType(uri string) *ProblemError {
+	p.Type = uri
+	return p
+}
+
+// WithTitle sets the problem title.
+func (p *ProblemError) WithTitle(title string) *ProblemError {
+	p.Title = title
+	return p
+}
+
+// WithInstance sets the URI identifying this occurrence of the problem.
+func (p *ProblemError) WithInstance(uri string) *ProblemError {
+	p.Instance = uri
+	return p
+}
+
+// WithCause sets the underlying error.
+func (p *ProblemError) WithCause(err error) *ProblemError {
+	p.Err = err
+	return p
+}
+
+// With adds an extension member. The standard members can't be overridden.
+func (p *ProblemError) With(key string, value any) *ProblemError {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// Error implements the error interface.
+func (p *ProblemError) Error() string {
+	msg := p.Title
+	if p.Detail != "" {
+		msg = p.Detail
+	}
+	if p.Err != nil {
+		return fmt.Sprintf("%d: %s: %v", p.Status, msg, p.Err)
+	}
+	return fmt.Sprintf("%d: %s", p.Status, msg)
+}
+
+// Unwrap returns the underlying error.
+func (p *ProblemError) Unwrap() error {
+	return p.Err
+}
+
+// MarshalJSON encodes the problem as a single object, with the extension
+// members alongside the standard ones.
+func (p *ProblemError) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		doc[k] = v
+	}
+	doc["type"] = p.Type
+	if p.Type == "" {
+		doc["type"] = "about:blank"
+	}
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	} else {
+		delete(doc, "detail")
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	} else {
+		delete(doc, "instance")
+	}
+	return json.Marshal(doc)
+}
+
+// IsProblemError checks if an error is a ProblemError and returns it.
+func IsProblemError(err error) (*ProblemError, bool) {
+	var p *ProblemError
+	if errors.As(err, &p) {
+		return p, true
+	}
+	return nil, false
+}
+
+// Problem sends p as an application/problem+json response. The instance
+// defaults to the request path. With the RequestID middleware, the request
+// ID is added as a "request_id" extension member.
+func (c *Context) Problem(p *ProblemError) error {
+	doc := *p
+	if doc.Title == "" {
+		doc.Title = http.StatusText(doc.Status)
+	}
+	if doc.Instance == "" {
+		doc.Instance = c.Request.URL.Path
+	}
+	if id := c.RequestID(); id != "" {
+		if _, ok := doc.Extensions["request_id"]; !ok {
+			doc.Extensions = make(map[string]any, len(p.Extensions)+1)
+			for k, v := range p.Extensions {
+				doc.Extensions[k] = v
+			}
+			doc.Extensions["request_id"] = id
+		}
+	}
+
+	body, err := json.Marshal(&doc)
+	if err != nil {
+		return NewHTTPErrorWithCause(http.StatusInternalServerError, "failed to encode problem", err)
+	}
+	return c.Blob(doc.Status, ProblemContentType, body)
+}
+
+// ProblemErrorHandler is an ErrorHandler that writes every error as an
+// RFC 7807 problem details document. Use it with App.OnError or
+// WithProblemDetails.
+//
+// ProblemErrors are sent as they are. HTTPErrors use their code and
+// message as the status and detail, validation errors list the invalid
+// fields in an "errors" member, and other errors become a 500 without
+// details, so internal messages aren't leaked.
+func ProblemErrorHandler(c *Context, err error) error {
+	return c.Problem(toProblem(err))
+}
+
+// toProblem converts err to a ProblemError.
+func toProblem(err error) *ProblemError {
+	if p, ok := IsProblemError(err); ok {
+		return p
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewProblem(http.StatusGatewayTimeout, "request timeout")
+	}
+
+	if valErr, ok := IsValidationError(err); ok {
+		p := NewProblem(http.StatusUnprocessableEntity, "validation failed")
+		if httpErr, ok := IsHTTPError(err); ok {
+			p = NewProblem(httpErr.Code, httpErr.Message)
+		}
+		return p.With("errors", valErr.Fields)
+	}
+
+	if httpErr, ok := IsHTTPError(err); ok {
+		return NewProblem(httpErr.Code, httpErr.Message)
+	}
+
+	return NewProblem(http.StatusInternalServerError, "")
+}
@@ -0,0 +1,161 @@
+package nexo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid problem document %q: %v", w.Body.String(), err)
+	}
+	return doc
+}
+
+func TestProblemError_MarshalJSON(t *testing.T) {
+	p := NewProblem(http.StatusForbidden, "Your balance is 30, but that costs 50.").
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithInstance("/account/12345/msgs/abc").
+		With("balance", 30).
+		With("status", 500)
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"balance":30,"detail":"Your balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","status":403,"title":"You do not have enough credit.","type":"https://example.com/probs/out-of-credit"}`
+	if string(data) != want {
+		t.Errorf("MarshalJSON() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestProblemError_Defaults(t *testing.T) {
+	data, err := json.Marshal(NewProblem(http.StatusNotFound, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"status":404,"title":"Not Found","type":"about:blank"}`; string(data) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", data, want)
+	}
+}
+
+func TestProblemError_Unwrap(t *testing.T) {
+	cause := errors.New("db down")
+	err := fmt.Errorf("loading: %w", NewProblem(503, "try later").WithCause(cause))
+
+	p, ok := IsProblemError(err)
+	if !ok || p.Status != 503 {
+		t.Fatalf("IsProblemError() = %v, %v", p, ok)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected the cause to be unwrapped")
+	}
+	if p.Error() != "503: try later: db down" {
+		t.Errorf("Error() = %q", p.Error())
+	}
+}
+
+func TestContext_Problem(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/orders/7", nil))
+
+	if err := c.Problem(&ProblemError{Status: http.StatusConflict, Detail: "already shipped"}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", w.Code)
+	}
+	doc := decodeProblem(t, w)
+	if doc["title"] != "Conflict" || doc["instance"] != "/orders/7" || doc["detail"] != "already shipped" {
+		t.Errorf("unexpected document %v", doc)
+	}
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	valErr := &ValidationError{}
+	valErr.Add("email", "is required")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		detail any
+	}{
+		{"http error", NotFound("user not found"), http.StatusNotFound, "user not found"},
+		{"problem", NewProblem(http.StatusPaymentRequired, "out of credit"), http.StatusPaymentRequired, "out of credit"},
+		{"timeout", context.DeadlineExceeded, http.StatusGatewayTimeout, "request timeout"},
+		{"validation", valErr, http.StatusUnprocessableEntity, "validation failed"},
+		{"generic", errors.New("secret internals"), http.StatusInternalServerError, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			if err := ProblemErrorHandler(c, tt.err); err != nil {
+				t.Fatal(err)
+			}
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			doc := decodeProblem(t, w)
+			if doc["status"] != float64(tt.status) {
+				t.Errorf("status member = %v, want %d", doc["status"], tt.status)
+			}
+			if doc["detail"] != tt.detail {
+				t.Errorf("detail = %v, want %v", doc["detail"], tt.detail)
+			}
+			if doc["instance"] != "/users/1" {
+				t.Errorf("instance = %v, want /users/1", doc["instance"])
+			}
+		})
+	}
+}
+
+func TestWithProblemDetails(t *testing.T) {
+	app := New(WithProblemDetails())
+	app.DisableLogger()
+	app.Get("/users/{id}", func(c *Context) error {
+		return NotFound("user not found")
+	})
+	app.Mount()
+
+	for path, status := range map[string]int{"/users/1": 404, "/missing": 404} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != status {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, status)
+		}
+		decodeProblem(t, w)
+	}
+}
+
+func TestDefaultErrorHandler_Problem(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/pay", func(c *Context) error {
+		return NewProblem(http.StatusPaymentRequired, "out of credit").With("balance", 30)
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pay", nil))
+	if w.Code != http.StatusPaymentRequired {
+		t.Errorf("status = %d, want 402", w.Code)
+	}
+	if doc := decodeProblem(t, w); doc["balance"] != float64(30) {
+		t.Errorf("expected the extension member, got %v", doc)
+	}
+}
@@ -0,0 +1,278 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitAlgorithm selects how the rate limit middleware counts requests.
+type RateLimitAlgorithm int
+
+const (
+	// SlidingWindow allows Limit requests in any Window, weighting the
+	// previous window's count by how much of it still overlaps.
+	SlidingWindow RateLimitAlgorithm = iota
+
+	// TokenBucket allows bursts of up to Limit requests and refills at
+	// Limit requests per Window.
+	TokenBucket
+)
+
+// RateLimitRule is the limit a RateLimitStore applies to a key.
+type RateLimitRule struct {
+	Limit     int
+	Window    time.Duration
+	Algorithm RateLimitAlgorithm
+}
+
+// RateLimitResult is the outcome of counting a request.
+type RateLimitResult struct {
+	// Allowed is false if the request exceeds the limit.
+	Allowed bool
+
+	// Limit is the rule's limit.
+	Limit int
+
+	// Remaining is how many more requests are allowed right now.
+	Remaining int
+
+	// Reset is how long until the full limit is available again.
+	Reset time.Duration
+
+	// RetryAfter is how long until a request is allowed again, if it
+	// wasn't.
+	RetryAfter time.Duration
+}
+
+// RateLimitStore counts requests for the rate limit middleware. Take must
+// count the request and decide atomically, so a store shared by several
+// instances of an app, e.g. backed by Redis, enforces one limit for all.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error)
+}
+
+// MemoryRateLimitStore is an in-memory RateLimitStore, the default.
+// Counters are kept per process.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	entries   map[string]*rateLimitEntry
+	lastSweep time.Time
+}
+
+// rateLimitEntry is the state of one key: window counts for SlidingWindow,
+// tokens for TokenBucket.
+type rateLimitEntry struct {
+	windowStart time.Time
+	current     int
+	previous    int
+	tokens      float64
+	last        time.Time
+	window      time.Duration
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory rate limit store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{entries: make(map[string]*rateLimitEntry)}
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &rateLimitEntry{windowStart: now, tokens: float64(rule.Limit), last: now}
+		s.entries[key] = entry
+	}
+	entry.window = rule.Window
+
+	if rule.Algorithm == TokenBucket {
+		return entry.takeToken(now, rule), nil
+	}
+	return entry.takeSliding(now, rule), nil
+}
+
+// sweep drops keys idle for two windows, at most once a minute.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if now.Sub(entry.last) > 2*entry.window {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (e *rateLimitEntry) takeSliding(now time.Time, rule RateLimitRule) RateLimitResult {
+	// Move the windows forward
+	if elapsed := now.Sub(e.windowStart); elapsed >= rule.Window {
+		windows := int(elapsed / rule.Window)
+		if windows == 1 {
+			e.previous = e.current
+		} else {
+			e.previous = 0
+		}
+		e.current = 0
+		e.windowStart = e.windowStart.Add(time.Duration(windows) * rule.Window)
+	}
+	e.last = now
+
+	elapsed := now.Sub(e.windowStart)
+	weight := 1 - float64(elapsed)/float64(rule.Window)
+	count := float64(e.previous)*weight + float64(e.current)
+	reset := rule.Window - elapsed
+
+	if count+1 > float64(rule.Limit) {
+		// Wait until enough of the previous window has slid out or, if the
+		// current one is full, enough of it has once it becomes previous
+		var retry time.Duration
+		if e.current < rule.Limit {
+			needed := 1 - float64(rule.Limit-1-e.current)/float64(e.previous)
+			retry = time.Duration(needed*float64(rule.Window)) - elapsed
+		} else {
+			needed := 1 - float64(rule.Limit-1)/float64(e.current)
+			retry = reset + time.Duration(needed*float64(rule.Window))
+		}
+		return RateLimitResult{Limit: rule.Limit, Reset: reset, RetryAfter: max(retry, 0)}
+	}
+
+	e.current++
+	remaining := rule.Limit - int(math.Ceil(count+1))
+	return RateLimitResult{Allowed: true, Limit: rule.Limit, Remaining: max(remaining, 0), Reset: reset}
+}
+
+func (e *rateLimitEntry) takeToken(now time.Time, rule RateLimitRule) RateLimitResult {
+	rate := float64(rule.Limit) / float64(rule.Window)
+	e.tokens = math.Min(float64(rule.Limit), e.tokens+float64(now.Sub(e.last))*rate)
+	e.last = now
+
+	result := RateLimitResult{Limit: rule.Limit}
+	if e.tokens >= 1 {
+		e.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - e.tokens) / rate)
+	}
+	result.Remaining = int(e.tokens)
+	result.Reset = time.Duration((float64(rule.Limit) - e.tokens) / rate)
+	return result
+}
+
+// ---------- RateLimit Middleware ----------
+
+// RateLimitConfig holds configuration for the rate limit middleware.
+type RateLimitConfig struct {
+	// Limit is how many requests a key may make per Window. Default is 100.
+	Limit int
+
+	// Window is the period Limit applies to. Default is 1 minute.
+	Window time.Duration
+
+	// Algorithm is SlidingWindow (default) or TokenBucket.
+	Algorithm RateLimitAlgorithm
+
+	// KeyFunc returns the key requests are counted under. Default is the
+	// client IP, read from X-Forwarded-For only for requests from the
+	// app's trusted proxies (see WithTrustedProxies).
+	KeyFunc func(c *Context) string
+
+	// Store counts the requests. Default is an in-memory store.
+	Store RateLimitStore
+
+	// Skip returns true for requests that aren't counted.
+	Skip func(c *Context) bool
+
+	// OnLimit responds to requests over the limit. Default is a 429
+	// error through the app's error handler.
+	OnLimit func(c *Context, result RateLimitResult) error
+}
+
+// RateLimit returns a middleware that limits how many requests each client
+// makes. Responses carry the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers, and limited ones a Retry-After header.
+//
+// Example:
+//
+//	app.Use(nexo.RateLimit(nexo.RateLimitConfig{
+//	    Limit:     10,
+//	    Window:    time.Second,
+//	    Algorithm: nexo.TokenBucket,
+//	    KeyFunc: func(c *nexo.Context) string {
+//	        return c.Header("X-API-Key")
+//	    },
+//	}))
+//
+// If the store fails, requests are allowed.
+func RateLimit(config RateLimitConfig) MiddlewareFunc {
+	if config.Limit <= 0 {
+		config.Limit = 100
+	}
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = clientIPKey
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore()
+	}
+	rule := RateLimitRule{Limit: config.Limit, Window: config.Window, Algorithm: config.Algorithm}
+	policy := fmt.Sprintf("%d;w=%d", config.Limit, int(math.Ceil(config.Window.Seconds())))
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			result, err := config.Store.Take(c.Context(), config.KeyFunc(c), rule)
+			if err != nil {
+				return next(c)
+			}
+
+			c.SetHeader("RateLimit-Policy", policy)
+			c.SetHeader("RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.SetHeader("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.SetHeader("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+			if result.Allowed {
+				return next(c)
+			}
+
+			c.SetHeader("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+			if config.OnLimit != nil {
+				return config.OnLimit(c, result)
+			}
+			handleError(c, NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded"))
+			return nil
+		}
+	}
+}
+
+// clientIPKey returns the client IP without the port. Forwarding headers
+// are only read from the app's trusted proxies, so clients can't pick
+// their own key.
+func clientIPKey(c *Context) string {
+	if addr, ok := clientAddr(c.Request); ok {
+		return addr.String()
+	}
+	if host, _, err := net.SplitHostPort(c.Request.RemoteAddr); err == nil {
+		return host
+	}
+	return c.Request.RemoteAddr
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,295 @@
+package nexo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_Headers(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(RateLimit(RateLimitConfig{Limit: 2, Window: time.Minute}))
+	app.Get("/", func(c *Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	app.Mount()
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w
+	}
+
+	for i, remaining := range []string{"1", "0"} {
+		w := get()
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != remaining {
+			t.Errorf("request %d: expected RateLimit-Remaining %s, got %q", i+1, remaining, got)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("expected RateLimit-Limit 2, got %q", got)
+		}
+		if got := w.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+			t.Errorf("expected RateLimit-Policy 2;w=60, got %q", got)
+		}
+	}
+
+	w := get()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	if w.Header().Get("RateLimit-Reset") == "" {
+		t.Error("expected a RateLimit-Reset header")
+	}
+}
+
+func TestRateLimit_ErrorHandler(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.OnError(func(c *Context, err error) error {
+		httpErr, _ := IsHTTPError(err)
+		return c.String(httpErr.Code, "slow down")
+	})
+	app.Use(RateLimit(RateLimitConfig{Limit: 1}))
+	app.Get("/", func(c *Context) error {
+		return c.NoContent()
+	})
+	app.Mount()
+
+	for range 2 {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusTooManyRequests || w.Body.String() != "slow down" {
+		t.Errorf("expected the error handler's 429, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRateLimit_KeyFunc(t *testing.T) {
+	mw := RateLimit(RateLimitConfig{
+		Limit: 1,
+		KeyFunc: func(c *Context) string {
+			return c.Header("X-API-Key")
+		},
+	})
+	handler := mw(func(c *Context) error {
+		return c.NoContent()
+	})
+
+	do := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		_ = handler(NewContext(w, req))
+		return w.Code
+	}
+
+	if do("a") != http.StatusNoContent || do("b") != http.StatusNoContent {
+		t.Error("expected the first request of each key to be allowed")
+	}
+	if do("a") != http.StatusTooManyRequests {
+		t.Error("expected key a to be limited")
+	}
+}
+
+func TestRateLimit_IgnoresPort(t *testing.T) {
+	handler := RateLimit(RateLimitConfig{Limit: 1})(func(c *Context) error {
+		return c.NoContent()
+	})
+
+	for i, addr := range []string{"10.0.0.1:1000", "10.0.0.1:2000"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		_ = handler(NewContext(w, req))
+		if i == 1 && w.Code != http.StatusTooManyRequests {
+			t.Errorf("expected a new connection from the same IP to be limited, got %d", w.Code)
+		}
+	}
+}
+
+func TestRateLimit_IgnoresForwardedFor(t *testing.T) {
+	handler := RateLimit(RateLimitConfig{Limit: 1})(func(c *Context) error {
+		return c.NoContent()
+	})
+
+	// Without trusted proxies, clients can't dodge the limit with new headers
+	for i, forwarded := range []string{"1.1.1.1", "2.2.2.2"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1000"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.Header.Set("X-Real-IP", forwarded)
+		w := httptest.NewRecorder()
+		_ = handler(NewContext(w, req))
+		if i == 1 && w.Code != http.StatusTooManyRequests {
+			t.Errorf("expected a spoofed X-Forwarded-For to be ignored, got %d", w.Code)
+		}
+	}
+}
+
+func TestRateLimit_Skip(t *testing.T) {
+	handler := RateLimit(RateLimitConfig{
+		Limit: 1,
+		Skip: func(c *Context) bool {
+			return c.Path() == "/health"
+		},
+	})(func(c *Context) error {
+		return c.NoContent()
+	})
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		_ = handler(NewContext(w, httptest.NewRequest(http.MethodGet, "/health", nil)))
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("expected skipped requests to be allowed, got %d", w.Code)
+		}
+		if w.Header().Get("RateLimit-Limit") != "" {
+			t.Error("expected no rate limit headers on skipped requests")
+		}
+	}
+}
+
+func TestRateLimit_OnLimit(t *testing.T) {
+	handler := RateLimit(RateLimitConfig{
+		Limit: 1,
+		OnLimit: func(c *Context, result RateLimitResult) error {
+			return c.String(http.StatusServiceUnavailable, "busy")
+		},
+	})(func(c *Context) error {
+		return c.NoContent()
+	})
+
+	var w *httptest.ResponseRecorder
+	for range 2 {
+		w = httptest.NewRecorder()
+		_ = handler(NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)))
+	}
+	if w.Code != http.StatusServiceUnavailable || w.Body.String() != "busy" {
+		t.Errorf("expected OnLimit's response, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+type countingRateLimitStore struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (s *countingRateLimitStore) Take(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return RateLimitResult{}, s.err
+	}
+	return RateLimitResult{Allowed: false, Limit: rule.Limit, RetryAfter: 3 * time.Second}, nil
+}
+
+func TestRateLimit_Store(t *testing.T) {
+	store := &countingRateLimitStore{}
+	handler := RateLimit(RateLimitConfig{Limit: 5, Store: store})(func(c *Context) error {
+		return c.NoContent()
+	})
+
+	w := httptest.NewRecorder()
+	_ = handler(NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)))
+	if store.calls.Load() != 1 {
+		t.Errorf("expected the store to be used, got %d calls", store.calls.Load())
+	}
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "3" {
+		t.Errorf("expected the store's decision, got %d Retry-After %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Requests are allowed if the store fails
+	store.err = errors.New("store down")
+	w = httptest.NewRecorder()
+	_ = handler(NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected the request to be allowed, got %d", w.Code)
+	}
+}
+
+func TestMemoryRateLimitStore_SlidingWindow(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	rule := RateLimitRule{Limit: 3, Window: 200 * time.Millisecond}
+	ctx := context.Background()
+
+	for i := range 3 {
+		result, _ := store.Take(ctx, "k", rule)
+		if !result.Allowed || result.Remaining != 2-i {
+			t.Fatalf("request %d: expected allowed with %d remaining, got %+v", i+1, 2-i, result)
+		}
+	}
+	result, _ := store.Take(ctx, "k", rule)
+	if result.Allowed || result.RetryAfter <= 0 {
+		t.Fatalf("expected the fourth request to be limited with a retry time, got %+v", result)
+	}
+
+	// The previous window still counts right after it ends
+	time.Sleep(rule.Window - time.Since(store.entries["k"].windowStart) + 5*time.Millisecond)
+	if result, _ := store.Take(ctx, "k", rule); result.Allowed {
+		t.Error("expected the previous window to be weighted in")
+	}
+
+	// Two windows later it doesn't
+	time.Sleep(2 * rule.Window)
+	if result, _ := store.Take(ctx, "k", rule); !result.Allowed {
+		t.Error("expected requests to be allowed again")
+	}
+}
+
+func TestMemoryRateLimitStore_TokenBucket(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	rule := RateLimitRule{Limit: 2, Window: 400 * time.Millisecond, Algorithm: TokenBucket}
+	ctx := context.Background()
+
+	for range 2 {
+		if result, _ := store.Take(ctx, "k", rule); !result.Allowed {
+			t.Fatal("expected a burst up to the limit to be allowed")
+		}
+	}
+	result, _ := store.Take(ctx, "k", rule)
+	if result.Allowed {
+		t.Fatal("expected the bucket to be empty")
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > rule.Window/2 {
+		t.Errorf("expected to retry within one token's refill, got %v", result.RetryAfter)
+	}
+
+	// One token refills every 200ms
+	time.Sleep(250 * time.Millisecond)
+	if result, _ := store.Take(ctx, "k", rule); !result.Allowed {
+		t.Error("expected a refilled token to be allowed")
+	}
+}
+
+func TestMemoryRateLimitStore_Concurrent(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	rule := RateLimitRule{Limit: 50, Window: time.Minute}
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result, _ := store.Take(context.Background(), "k", rule); result.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed.Load() != 50 {
+		t.Errorf("expected exactly 50 requests to be allowed, got %d", allowed.Load())
+	}
+}
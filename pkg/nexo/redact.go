@@ -0,0 +1,153 @@
+package nexo
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultRedactReplacement replaces redacted values in logs.
+const DefaultRedactReplacement = "[REDACTED]"
+
+// RedactConfig lists the values the request logger removes before writing
+// a log line. Names are matched case-insensitively.
+type RedactConfig struct {
+	// Headers are request header names, e.g. "Authorization".
+	Headers []string
+
+	// QueryParams are query parameter names, e.g. "token".
+	QueryParams []string
+
+	// JSONFields are JSON object keys, matched at any depth, e.g. "password".
+	JSONFields []string
+
+	// Replacement replaces redacted values. Default: DefaultRedactReplacement.
+	Replacement string
+}
+
+// DefaultRedactConfig returns the credentials redacted by default.
+func DefaultRedactConfig() RedactConfig {
+	return RedactConfig{
+		Headers: []string{
+			"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+			"X-Api-Key", "X-Auth-Token", "X-CSRF-Token",
+		},
+		QueryParams: []string{
+			"token", "access_token", "refresh_token", "id_token", "api_key",
+			"apikey", "key", "password", "secret", "client_secret", "code", "signature",
+		},
+		JSONFields: []string{
+			"password", "password_confirmation", "token", "access_token",
+			"refresh_token", "secret", "client_secret", "api_key", "credit_card", "cvv",
+		},
+		Replacement: DefaultRedactReplacement,
+	}
+}
+
+// withDefaults returns rc with empty lists replaced by the defaults.
+func (rc RedactConfig) withDefaults() RedactConfig {
+	def := DefaultRedactConfig()
+	if rc.Headers == nil {
+		rc.Headers = def.Headers
+	}
+	if rc.QueryParams == nil {
+		rc.QueryParams = def.QueryParams
+	}
+	if rc.JSONFields == nil {
+		rc.JSONFields = def.JSONFields
+	}
+	if rc.Replacement == "" {
+		rc.Replacement = def.Replacement
+	}
+	return rc
+}
+
+// RedactHeader returns a copy of h with the values of Headers replaced.
+func (rc RedactConfig) RedactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for name, values := range out {
+		if !containsFold(rc.Headers, name) {
+			continue
+		}
+		for i := range values {
+			values[i] = rc.Replacement
+		}
+	}
+	return out
+}
+
+// RedactQuery returns the raw query string with the values of QueryParams
+// replaced. Parameter order is kept.
+func (rc RedactConfig) RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	parts := strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		name, _, hasValue := strings.Cut(part, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if hasValue && containsFold(rc.QueryParams, name) {
+			parts[i] = part[:strings.Index(part, "=")+1] + url.QueryEscape(rc.Replacement)
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// RedactJSON returns data with the values of JSONFields replaced. Data that
+// isn't valid JSON is returned unchanged.
+func (rc RedactConfig) RedactJSON(data []byte) []byte {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return data
+	}
+	var v any
+	if err := json.Unmarshal(trimmed, &v); err != nil {
+		return data
+	}
+	if !rc.redactValue(v) {
+		return data
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// redactValue replaces JSONFields in v in place and reports whether any
+// were found.
+func (rc RedactConfig) redactValue(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if containsFold(rc.JSONFields, key) {
+				v[key] = rc.Replacement
+				found = true
+			} else if rc.redactValue(value) {
+				found = true
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if rc.redactValue(value) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
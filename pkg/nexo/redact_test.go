@@ -0,0 +1,101 @@
+package nexo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactConfig_RedactQuery(t *testing.T) {
+	rc := DefaultRedactConfig()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"page=2&sort=name", "page=2&sort=name"},
+		{"token=abc&page=2", "token=%5BREDACTED%5D&page=2"},
+		{"PASSWORD=hunter2", "PASSWORD=%5BREDACTED%5D"},
+		{"api%5Fkey=abc", "api%5Fkey=%5BREDACTED%5D"},
+		{"token", "token"},
+	}
+	for _, tt := range tests {
+		if got := rc.RedactQuery(tt.in); got != tt.want {
+			t.Errorf("RedactQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactConfig_RedactHeader(t *testing.T) {
+	rc := DefaultRedactConfig()
+	h := http.Header{}
+	h.Set("Authorization", "Bearer secret")
+	h.Set("Accept", "text/html")
+
+	got := rc.RedactHeader(h)
+	if got.Get("Authorization") != DefaultRedactReplacement || got.Get("Accept") != "text/html" {
+		t.Errorf("Unexpected headers: %v", got)
+	}
+	if h.Get("Authorization") != "Bearer secret" {
+		t.Error("Expected the original headers to be left unchanged")
+	}
+}
+
+func TestRedactConfig_RedactJSON(t *testing.T) {
+	rc := RedactConfig{JSONFields: []string{"password", "cvv"}, Replacement: "***"}
+
+	got := rc.RedactJSON([]byte(`{"user":{"name":"ana","Password":"hunter2"},"cards":[{"cvv":"123"}]}`))
+	var v map[string]any
+	if err := json.Unmarshal(got, &v); err != nil {
+		t.Fatalf("Expected valid JSON, got %q", got)
+	}
+	if s := string(got); strings.Contains(s, "hunter2") || strings.Contains(s, "123") || !strings.Contains(s, "ana") {
+		t.Errorf("Unexpected redacted JSON: %s", got)
+	}
+
+	for _, in := range []string{"not json", `{"name":"ana"}`, `{"password":`} {
+		if got := rc.RedactJSON([]byte(in)); string(got) != in {
+			t.Errorf("RedactJSON(%q) = %q, want it unchanged", in, got)
+		}
+	}
+}
+
+func TestRequestLogger_Redaction(t *testing.T) {
+	var buf bytes.Buffer
+	var entry LogEntry
+	rl := NewRequestLogger(RequestLoggerConfig{
+		Level:       LogLevelInfo,
+		Output:      &buf,
+		ShowQuery:   true,
+		ShowHeaders: []string{"authorization", "Referer"},
+		ShowErrors:  true,
+		OnLog:       func(e LogEntry) { entry = e },
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/login?next=/home&password=hunter2", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Referer", "https://example.com/")
+	rl.Log(r, 400, 0, time.Millisecond, nil, fmt.Errorf(`{"error":"invalid","token":"abc123"}`))
+
+	output := buf.String()
+	for _, leaked := range []string{"hunter2", "Bearer secret", "abc123"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("Expected %q to be redacted, got: %s", leaked, output)
+		}
+	}
+	for _, want := range []string{"/login?next=/home&password=%5BREDACTED%5D", "[Authorization: [REDACTED]]", "[Referer: https://example.com/]", "invalid"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the log, got: %s", want, output)
+		}
+	}
+
+	if entry.Header.Get("Authorization") != DefaultRedactReplacement || strings.Contains(entry.Query, "hunter2") {
+		t.Errorf("Expected a redacted LogEntry, got header %v and query %q", entry.Header, entry.Query)
+	}
+}
@@ -1,9 +1,16 @@
 package nexo
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/a-h/templ"
 )
@@ -19,8 +26,14 @@ type Renderer struct {
 	// notFoundComponent is the global 404 component
 	notFoundComponent templ.Component
 
+	// notFoundComponents stores 404 components by path prefix
+	notFoundComponents map[string]templ.Component
+
 	// loadingComponents stores loading skeleton components by path prefix
 	loadingComponents map[string]templ.Component
+
+	// slots stores parallel route slots by path prefix and name
+	slots map[string]map[string]SlotFunc
 }
 
 // LayoutFunc is a function that wraps content with a layout.
@@ -43,9 +56,11 @@ type PageHandler struct {
 // NewRenderer creates a new Renderer.
 func NewRenderer() *Renderer {
 	return &Renderer{
-		layouts:           make(map[string]LayoutFunc),
-		errorComponents:   make(map[string]ErrorComponent),
-		loadingComponents: make(map[string]templ.Component),
+		layouts:            make(map[string]LayoutFunc),
+		errorComponents:    make(map[string]ErrorComponent),
+		notFoundComponents: make(map[string]templ.Component),
+		loadingComponents:  make(map[string]templ.Component),
+		slots:              make(map[string]map[string]SlotFunc),
 	}
 }
 
@@ -64,6 +79,12 @@ func (r *Renderer) SetNotFoundComponent(comp templ.Component) {
 	r.notFoundComponent = comp
 }
 
+// SetNotFoundComponentFor registers a 404 component for a path prefix. It
+// takes over from the global one for unmatched paths under the prefix.
+func (r *Renderer) SetNotFoundComponentFor(pathPrefix string, comp templ.Component) {
+	r.notFoundComponents[pathPrefix] = comp
+}
+
 // SetLoadingComponent registers a loading component for a path prefix.
 func (r *Renderer) SetLoadingComponent(pathPrefix string, comp templ.Component) {
 	r.loadingComponents[pathPrefix] = comp
@@ -100,20 +121,76 @@ func (r *Renderer) GetErrorComponent(path string) ErrorComponent {
 	return bestComp
 }
 
+// GetNotFoundComponent returns the most specific 404 component for a path,
+// or the global one.
+func (r *Renderer) GetNotFoundComponent(path string) templ.Component {
+	var bestMatch string
+	bestComp := r.notFoundComponent
+
+	for prefix, comp := range r.notFoundComponents {
+		if len(prefix) > len(bestMatch) && matchesPrefix(path, prefix) {
+			bestMatch = prefix
+			bestComp = comp
+		}
+	}
+
+	return bestComp
+}
+
+// GetLoadingComponent returns the most specific loading component for a path.
+func (r *Renderer) GetLoadingComponent(path string) templ.Component {
+	var bestMatch string
+	var bestComp templ.Component
+
+	for prefix, comp := range r.loadingComponents {
+		if len(prefix) > len(bestMatch) && matchesPrefix(path, prefix) {
+			bestMatch = prefix
+			bestComp = comp
+		}
+	}
+
+	return bestComp
+}
+
 // matchesPrefix checks if path starts with prefix (with proper path boundary handling).
+// Prefixes of dynamic directories match any value of their parameters:
+// "/posts/{slug}" matches "/posts/hello/edit", and "*" matches the rest of
+// the path.
 func matchesPrefix(path, prefix string) bool {
 	if prefix == "/" || prefix == "" {
 		return true
 	}
-	if len(path) < len(prefix) {
-		return false
-	}
-	if path[:len(prefix)] != prefix {
-		return false
+	if !strings.ContainsAny(prefix, "{*") {
+		if len(path) < len(prefix) {
+			return false
+		}
+		if path[:len(prefix)] != prefix {
+			return false
+		}
+		// Ensure we match at a path boundary
+		if len(path) > len(prefix) && path[len(prefix)] != '/' {
+			return false
+		}
+		return true
 	}
-	// Ensure we match at a path boundary
-	if len(path) > len(prefix) && path[len(prefix)] != '/' {
-		return false
+
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range strings.Split(strings.Trim(prefix, "/"), "/") {
+		if seg == "*" {
+			return true
+		}
+		if i >= len(pathSegs) {
+			return false
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if pathSegs[i] == "" {
+				return false
+			}
+			continue
+		}
+		if seg != pathSegs[i] {
+			return false
+		}
 	}
 	return true
 }
@@ -141,26 +218,88 @@ func (r *Renderer) RenderWithLayout(c *Context, status int, title string, comp t
 
 // RenderError renders an error using the appropriate error component.
 func (r *Renderer) RenderError(c *Context, err error) error {
-	status := http.StatusInternalServerError
+	errComp := r.GetErrorComponent(c.Path())
+	if errComp != nil {
+		return r.Render(c, errorStatus(err), errComp(err))
+	}
 
-	// Check if it's an HTTP error
+	// Default error response
+	return c.Error(errorStatus(err), err.Error())
+}
+
+// hasErrorComponents reports whether any error components are registered.
+func (r *Renderer) hasErrorComponents() bool {
+	return len(r.errorComponents) > 0
+}
+
+// errorStatus returns the status code DefaultErrorHandler sends for err.
+func errorStatus(err error) int {
+	if p, ok := IsProblemError(err); ok && p.Status != 0 {
+		return p.Status
+	}
 	if httpErr, ok := IsHTTPError(err); ok {
-		status = httpErr.Code
+		return httpErr.Code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
 	}
+	if _, ok := IsValidationError(err); ok {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// rendererKey is the request context key for the app's Renderer.
+type rendererKey struct{}
 
+// withRenderer returns r with the app's Renderer in its context.
+func withRenderer(r *http.Request, renderer *Renderer) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), rendererKey{}, renderer))
+}
+
+// renderErrorBoundary renders err with the nearest error component
+// registered on the app's Renderer (see error.templ) and reports whether
+// it did. Only page requests get error pages (see wantsHTMLPage); API
+// clients keep getting the JSON error.
+func renderErrorBoundary(c *Context, err error) bool {
+	r, ok := c.Request.Context().Value(rendererKey{}).(*Renderer)
+	if !ok || r == nil || !wantsHTMLPage(c.Request) {
+		return false
+	}
 	errComp := r.GetErrorComponent(c.Path())
-	if errComp != nil {
-		return r.Render(c, status, errComp(err))
+	if errComp == nil {
+		return false
 	}
+	_ = r.Render(c, errorStatus(err), errComp(err))
+	return true
+}
 
-	// Default error response
-	return c.Error(status, err.Error())
+// renderNotFoundPage renders the nearest 404 component registered on r
+// (see not-found.templ) for an unmatched request and reports whether it
+// did. Like error pages, 404 pages are only sent to page requests.
+func renderNotFoundPage(c *Context, r *Renderer) bool {
+	if !wantsHTMLPage(c.Request) {
+		return false
+	}
+	comp := r.GetNotFoundComponent(c.Path())
+	if comp == nil {
+		return false
+	}
+	_ = r.Render(c, http.StatusNotFound, comp)
+	return true
+}
+
+// wantsHTMLPage reports whether a request gets HTML error and 404 pages:
+// its Accept header asks for HTML, as browsers do for page loads, and it
+// isn't for an API route under /api.
+func wantsHTMLPage(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html") && !matchesPrefix(r.URL.Path, "/api")
 }
 
-// RenderNotFound renders the 404 page.
+// RenderNotFound renders the most specific 404 page for the request path.
 func (r *Renderer) RenderNotFound(c *Context) error {
-	if r.notFoundComponent != nil {
-		return r.Render(c, http.StatusNotFound, r.notFoundComponent)
+	if comp := r.GetNotFoundComponent(c.Path()); comp != nil {
+		return r.Render(c, http.StatusNotFound, comp)
 	}
 
 	return c.Error(http.StatusNotFound, "page not found")
@@ -187,6 +326,15 @@ func TemplWithLayout(c *Context, status int, layout LayoutFunc, title string, co
 	return finalComp.Render(c.Context(), c.Response)
 }
 
+// WithLayout returns a component rendering layout with children in place
+// of its { children... }. Generated routes use it to wrap the pages of a
+// route group in the group's layout.templ.
+func WithLayout(layout, children templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return layout.Render(templ.WithChildren(ctx, children), w)
+	})
+}
+
 // WrapLayout is a helper to create a layout wrapper component.
 // This is useful for creating layout functions that work with templ's { children... } pattern.
 type WrapLayout struct {
@@ -200,15 +348,24 @@ func (w WrapLayout) Render(ctx context.Context, wr io.Writer) error {
 	return w.Layout(w.Title).Render(ctx, wr)
 }
 
+// DefaultLoadingDelay is how long RenderWithLoading waits for a page before
+// streaming its loading component, so fast pages don't flash it.
+const DefaultLoadingDelay = 100 * time.Millisecond
+
 // StreamingRenderer provides support for streaming HTML responses.
 type StreamingRenderer struct {
 	*Renderer
+
+	// LoadingDelay is how long RenderWithLoading waits for a page before
+	// streaming its loading component. Default: DefaultLoadingDelay.
+	LoadingDelay time.Duration
 }
 
 // NewStreamingRenderer creates a streaming-capable renderer.
 func NewStreamingRenderer() *StreamingRenderer {
 	return &StreamingRenderer{
-		Renderer: NewRenderer(),
+		Renderer:     NewRenderer(),
+		LoadingDelay: DefaultLoadingDelay,
 	}
 }
 
@@ -225,3 +382,128 @@ func (sr *StreamingRenderer) RenderStreaming(c *Context, comp templ.Component) e
 
 	return comp.Render(c.Context(), c.Response)
 }
+
+// RenderWithLoading renders the page returned by load, which usually runs
+// the page's loader. If load takes longer than LoadingDelay and a loading
+// component is registered for the request path (see loading.templ), the
+// loading component is streamed right away and the page replaces it once
+// load returns.
+//
+// Errors from load are returned as usual if nothing was sent yet. Once the
+// loading component is streamed, they're rendered in its place with the
+// path's error component instead. load runs in its own goroutine, so it
+// must not set response headers.
+func (sr *StreamingRenderer) RenderWithLoading(c *Context, load func() (templ.Component, error)) error {
+	loading := sr.GetLoadingComponent(c.Path())
+	if loading == nil {
+		page, err := load()
+		if err != nil {
+			return err
+		}
+		return sr.Render(c, http.StatusOK, page)
+	}
+
+	done := make(chan loadResult, 1)
+	go func() {
+		var res loadResult
+		defer func() {
+			res.panicked = recover()
+			done <- res
+		}()
+		res.page, res.err = load()
+	}()
+
+	delay := sr.LoadingDelay
+	if delay <= 0 {
+		delay = DefaultLoadingDelay
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		if res.panicked != nil {
+			panic(res.panicked)
+		}
+		if res.err != nil {
+			return res.err
+		}
+		return sr.Render(c, http.StatusOK, res.page)
+	case <-timer.C:
+	}
+
+	return sr.RenderStreaming(c, loadingBoundary{renderer: sr.Renderer, path: c.Path(), loading: loading, done: done})
+}
+
+// loadResult is the outcome of a RenderWithLoading load function.
+type loadResult struct {
+	page     templ.Component
+	err      error
+	panicked any
+}
+
+// loadingBoundary streams a loading component, then the page that replaces
+// it once it's loaded.
+type loadingBoundary struct {
+	renderer *Renderer
+	path     string
+	loading  templ.Component
+	done     <-chan loadResult
+}
+
+// Render implements templ.Component.
+func (b loadingBoundary) Render(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, "<!--nexo-loading-->"); err != nil {
+		return err
+	}
+	if err := b.loading.Render(ctx, w); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "<!--/nexo-loading-->"); err != nil {
+		return err
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	var res loadResult
+	select {
+	case res = <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if res.panicked != nil {
+		panic(res.panicked)
+	}
+
+	page := res.page
+	if res.err != nil {
+		page = templ.Raw(html.EscapeString(res.err.Error()))
+		if errComp := b.renderer.GetErrorComponent(b.path); errComp != nil {
+			page = errComp(res.err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := page.Render(ctx, &buf); err != nil {
+		return err
+	}
+	content, err := json.Marshal(buf.String())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "<script>%s(%s)</script>", loadingSwapScript, content)
+	return err
+}
+
+// loadingSwapScript replaces the streamed loading component with the page
+// HTML it's called with. A loading component rendered inside a page is
+// replaced in place, running the page's scripts; one that is a whole
+// document has its body replaced.
+const loadingSwapScript = `(function(html){` +
+	`var d=document,s=d.currentScript,w=d.createTreeWalker(d,NodeFilter.SHOW_COMMENT),a,b;` +
+	`while(w.nextNode()){if(w.currentNode.data==="nexo-loading")a=w.currentNode;else if(w.currentNode.data==="/nexo-loading")b=w.currentNode}` +
+	`if(a&&b&&a.parentNode===b.parentNode&&a.parentNode!==d){var r=d.createRange();r.setStartBefore(a);r.setEndAfter(b);r.deleteContents();r.insertNode(r.createContextualFragment(html))}` +
+	`else{var n=new DOMParser().parseFromString(html,"text/html");if(n.title)d.title=n.title;d.body.replaceChildren.apply(d.body,Array.from(n.body.childNodes))}` +
+	`if(s)s.remove();if(window.htmx)htmx.process(d.body)` +
+	`})`
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/a-h/templ"
 )
@@ -242,6 +244,11 @@ func TestMatchesPrefix(t *testing.T) {
 		{"/any/path", "/", true},
 		{"/any/path", "", true},
 		{"/api", "/api/v1", false},
+		{"/posts/hello", "/posts/{slug}", true},
+		{"/posts/hello/edit", "/posts/{slug}", true},
+		{"/posts", "/posts/{slug}", false},
+		{"/pages/hello", "/posts/{slug}", false},
+		{"/docs/a/b/c", "/docs/*", true},
 	}
 
 	for _, tt := range tests {
@@ -415,6 +422,22 @@ func TestRenderer_RenderNotFound(t *testing.T) {
 		}
 	})
 
+	t.Run("scoped not found component", func(t *testing.T) {
+		r := NewRenderer()
+		r.SetNotFoundComponent(mockComponent{content: "<h1>Page Not Found</h1>"})
+		r.SetNotFoundComponentFor("/dashboard", mockComponent{content: "<h1>No such dashboard page</h1>"})
+
+		w := httptest.NewRecorder()
+		c := NewContext(w, httptest.NewRequest("GET", "/dashboard/missing", nil))
+
+		if err := r.RenderNotFound(c); err != nil {
+			t.Fatalf("RenderNotFound() error = %v", err)
+		}
+		if body := w.Body.String(); !strings.Contains(body, "No such dashboard page") {
+			t.Errorf("body = %q, want the dashboard 404 page", body)
+		}
+	})
+
 	t.Run("without not found component", func(t *testing.T) {
 		r := NewRenderer()
 		// No not found component registered
@@ -540,3 +563,213 @@ func TestStreamingRenderer_RenderStreaming(t *testing.T) {
 		t.Errorf("body = %q, want %q", body, "<div>Streaming Content</div>")
 	}
 }
+
+// flushRecorder is a ResponseRecorder that closes flushed on the first Flush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+	once    sync.Once
+}
+
+func newFlushRecorder() *flushRecorder {
+	return &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+}
+
+func (w *flushRecorder) Flush() {
+	w.ResponseRecorder.Flush()
+	w.once.Do(func() { close(w.flushed) })
+}
+
+func TestStreamingRenderer_RenderWithLoading(t *testing.T) {
+	loading := mockComponent{content: "<p>Loading...</p>"}
+
+	t.Run("fast page skips loading", func(t *testing.T) {
+		sr := NewStreamingRenderer()
+		sr.SetLoadingComponent("/dashboard", loading)
+
+		w := httptest.NewRecorder()
+		c := NewContext(w, httptest.NewRequest("GET", "/dashboard", nil))
+		err := sr.RenderWithLoading(c, func() (templ.Component, error) {
+			return mockComponent{content: "<h1>Dashboard</h1>"}, nil
+		})
+		if err != nil {
+			t.Fatalf("RenderWithLoading() error = %v", err)
+		}
+		if body := w.Body.String(); body != "<h1>Dashboard</h1>" {
+			t.Errorf("body = %q, want the page only", body)
+		}
+	})
+
+	t.Run("slow page streams loading first", func(t *testing.T) {
+		sr := NewStreamingRenderer()
+		sr.LoadingDelay = time.Millisecond
+		sr.SetLoadingComponent("/dashboard", loading)
+
+		w := newFlushRecorder()
+		c := NewContext(w, httptest.NewRequest("GET", "/dashboard/stats", nil))
+		err := sr.RenderWithLoading(c, func() (templ.Component, error) {
+			<-w.flushed // Wait until the loading component was sent
+			return mockComponent{content: "<h1>Stats</h1>"}, nil
+		})
+		if err != nil {
+			t.Fatalf("RenderWithLoading() error = %v", err)
+		}
+
+		body := w.Body.String()
+		if !strings.HasPrefix(body, "<!--nexo-loading--><p>Loading...</p><!--/nexo-loading--><script>") {
+			t.Errorf("body = %q, want the loading component first", body)
+		}
+		if !strings.Contains(body, `("\u003ch1\u003eStats\u003c/h1\u003e")</script>`) {
+			t.Errorf("body = %q, want the page after it", body)
+		}
+	})
+
+	t.Run("slow page error renders error component", func(t *testing.T) {
+		sr := NewStreamingRenderer()
+		sr.LoadingDelay = time.Millisecond
+		sr.SetLoadingComponent("/dashboard", loading)
+		sr.SetErrorComponent("/dashboard", mockErrorComponent)
+
+		w := newFlushRecorder()
+		c := NewContext(w, httptest.NewRequest("GET", "/dashboard", nil))
+		err := sr.RenderWithLoading(c, func() (templ.Component, error) {
+			<-w.flushed
+			return nil, errors.New("database unavailable")
+		})
+		if err != nil {
+			t.Fatalf("RenderWithLoading() error = %v", err)
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("status code = %d, want %d", w.Code, http.StatusOK)
+		}
+		if !strings.Contains(w.Body.String(), "database unavailable") {
+			t.Errorf("body = %q, want the error component", w.Body.String())
+		}
+	})
+
+	t.Run("without loading component", func(t *testing.T) {
+		sr := NewStreamingRenderer()
+		sr.SetLoadingComponent("/dashboard", loading)
+
+		w := httptest.NewRecorder()
+		c := NewContext(w, httptest.NewRequest("GET", "/blog", nil))
+		wantErr := errors.New("not found")
+		err := sr.RenderWithLoading(c, func() (templ.Component, error) {
+			return nil, wantErr
+		})
+		if err != wantErr {
+			t.Errorf("RenderWithLoading() error = %v, want %v", err, wantErr)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("body = %q, want nothing written", w.Body.String())
+		}
+	})
+}
+
+func TestApp_NotFoundPages(t *testing.T) {
+	app := New(WithLogger(false))
+	app.Renderer().SetNotFoundComponent(mockComponent{content: "<h1>Page Not Found</h1>"})
+	app.Renderer().SetNotFoundComponentFor("/dashboard", mockComponent{content: "<h1>No such dashboard page</h1>"})
+	app.Get("/dashboard", func(c *Context) error {
+		return c.String(200, "dashboard")
+	})
+	app.Mount()
+
+	tests := []struct {
+		name     string
+		path     string
+		accept   string
+		wantBody string
+		wantType string
+	}{
+		{"section page", "/dashboard/missing", "text/html", "No such dashboard page", "text/html"},
+		{"root page", "/missing", "text/html", "Page Not Found", "text/html"},
+		{"API paths keep JSON", "/api/missing", "text/html", "not found", "application/json"},
+		{"JSON clients keep JSON", "/dashboard/missing", "application/json", "not found", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.wantBody)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestApp_ErrorBoundary(t *testing.T) {
+	app := New(WithLogger(false))
+	app.Renderer().SetErrorComponent("/", mockErrorComponent)
+	app.Renderer().SetErrorComponent("/dashboard", func(err error) templ.Component {
+		return mockComponent{content: "<div class=\"dashboard-error\">" + err.Error() + "</div>"}
+	})
+	app.Get("/dashboard/stats", func(c *Context) error {
+		return errors.New("loader failed")
+	})
+	app.Get("/missing", func(c *Context) error {
+		return NewHTTPError(http.StatusNotFound, "no such thing")
+	})
+	app.Mount()
+
+	tests := []struct {
+		name     string
+		path     string
+		accept   string
+		wantCode int
+		wantBody string
+		wantType string
+	}{
+		{"nearest boundary", "/dashboard/stats", "text/html", 500, "dashboard-error", "text/html"},
+		{"root boundary keeps status", "/missing", "text/html,application/xhtml+xml", 404, "no such thing", "text/html"},
+		{"API clients get JSON", "/dashboard/stats", "application/json", 500, "internal server error", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.wantBody)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestWithLayout(t *testing.T) {
+	layout := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, "<main>"); err != nil {
+			return err
+		}
+		if err := templ.GetChildren(ctx).Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "</main>")
+		return err
+	})
+
+	var b strings.Builder
+	if err := WithLayout(layout, mockComponent{content: "<p>page</p>"}).Render(context.Background(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "<main><p>page</p></main>"; b.String() != want {
+		t.Errorf("expected %q, got %q", want, b.String())
+	}
+}
@@ -0,0 +1,165 @@
+package nexo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// ---------- RequestID Middleware ----------
+
+// requestIDKey is the request context key for the request ID.
+type requestIDKey struct{}
+
+// RequestID returns a middleware that adds a unique request ID to each request.
+func RequestID() MiddlewareFunc {
+	return RequestIDWithConfig(RequestIDConfig{})
+}
+
+// RequestIDConfig holds configuration for the request ID middleware.
+type RequestIDConfig struct {
+	// Header is the header name to use. Default is "X-Request-ID".
+	Header string
+
+	// Generator is a custom ID generator. Default is NewUUIDv7; NewULID is
+	// also provided.
+	Generator func() string
+
+	// IgnoreIncoming always generates a new ID instead of reusing the one
+	// sent in Header, e.g. when clients aren't trusted to pick it.
+	IgnoreIncoming bool
+}
+
+// RequestIDWithConfig returns a request ID middleware with custom configuration.
+//
+// An ID already set in the response Header, e.g. by proxy.go, is kept. An
+// ID sent by the client or an upstream proxy in Header is reused if it's
+// at most 128 characters of letters, digits and "-._:/+=@", so it can be
+// followed across services; otherwise a new one is generated. The ID is
+// echoed in the response header, available with c.RequestID() and
+// RequestIDFromContext, and added to error responses and the request log.
+//
+// Example:
+//
+//	app.Use(nexo.RequestIDWithConfig(nexo.RequestIDConfig{
+//	    Generator: nexo.NewULID,
+//	}))
+func RequestIDWithConfig(config RequestIDConfig) MiddlewareFunc {
+	if config.Header == "" {
+		config.Header = "X-Request-ID"
+	}
+	if config.Generator == nil {
+		config.Generator = NewUUIDv7
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			// Keep an ID assigned earlier in the request, e.g. by the proxy,
+			// or reuse the incoming ID if it's safe to
+			id := c.Response.Header().Get(config.Header)
+			if id == "" && !config.IgnoreIncoming {
+				if incoming := c.Header(config.Header); validRequestID(incoming) {
+					id = incoming
+				}
+			}
+			if id == "" {
+				id = config.Generator()
+			}
+
+			// Store in context and set response header
+			c.Set("requestId", id)
+			c.WithContext(context.WithValue(c.Context(), requestIDKey{}, id))
+			c.SetHeader(config.Header, id)
+
+			return next(c)
+		}
+	}
+}
+
+// validRequestID reports whether an incoming request ID is safe to reuse
+// in headers and logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch b := id[i]; {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		case b == '-', b == '.', b == '_', b == ':', b == '/', b == '+', b == '=', b == '@':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDFromContext returns the ID the RequestID middleware assigned to
+// the request ctx belongs to, or "". Use it in services and templates that
+// only get a context.Context.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID returns the ID assigned by the RequestID middleware, or "" without
+// it. c.Logger tags its records with it.
+func (c *Context) RequestID() string {
+	return RequestIDFromContext(c.Context())
+}
+
+// ---------- ID Generators ----------
+
+// NewUUIDv7 returns a random, time-ordered UUID (RFC 9562 version 7), e.g.
+// "01928c4e-5d3a-7b2c-9f1e-3a4b5c6d7e8f". It's the default request ID.
+func NewUUIDv7() string {
+	var b [16]byte
+	_, _ = rand.Read(b[6:])
+	putTimestamp48(b[:6], time.Now())
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a random, time-ordered ULID, 26 characters of Crockford
+// base32, e.g. "01J9A7Q3ZK8M4T6V2X5B7N9C1D".
+func NewULID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[6:])
+	putTimestamp48(b[:6], time.Now())
+
+	// 128 bits as 26 characters of 5 bits, the first one holding 3
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var s [26]byte
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// putTimestamp48 writes t as 48-bit big-endian Unix milliseconds.
+func putTimestamp48(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
@@ -0,0 +1,205 @@
+package nexo
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUUIDv7(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	prev := ""
+	for i := 0; i < 100; i++ {
+		id := NewUUIDv7()
+		if !pattern.MatchString(id) {
+			t.Fatalf("invalid UUIDv7 %q", id)
+		}
+		if id == prev {
+			t.Fatalf("duplicate ID %q", id)
+		}
+		prev = id
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	first := NewULID()
+	time.Sleep(2 * time.Millisecond)
+	second := NewULID()
+	for _, id := range []string{first, second} {
+		if !pattern.MatchString(id) {
+			t.Fatalf("invalid ULID %q", id)
+		}
+	}
+	if first[:10] >= second[:10] {
+		t.Errorf("expected ULIDs to sort by time, got %q then %q", first, second)
+	}
+}
+
+func TestRequestID_Incoming(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		config   RequestIDConfig
+		reused   bool
+	}{
+		{"trusted", "req-1234.abc", RequestIDConfig{}, true},
+		{"trace header", "00-4bf92f3577b34da6-00f067aa0ba902b7-01", RequestIDConfig{}, true},
+		{"control characters", "abc\r\nX-Evil: 1", RequestIDConfig{}, false},
+		{"spaces", "abc def", RequestIDConfig{}, false},
+		{"too long", strings.Repeat("a", 129), RequestIDConfig{}, false},
+		{"ignored", "req-1234", RequestIDConfig{IgnoreIncoming: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := RequestIDWithConfig(tt.config)(func(c *Context) error {
+				got = c.RequestID()
+				return c.NoContent()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header["X-Request-Id"] = []string{tt.incoming}
+			w := httptest.NewRecorder()
+			if err := handler(NewContext(w, req)); err != nil {
+				t.Fatal(err)
+			}
+
+			if (got == tt.incoming) != tt.reused {
+				t.Errorf("expected reused=%v, got ID %q", tt.reused, got)
+			}
+			if got == "" || w.Header().Get("X-Request-ID") != got {
+				t.Errorf("expected the response header to echo %q, got %q", got, w.Header().Get("X-Request-ID"))
+			}
+		})
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(RequestIDWithConfig(RequestIDConfig{Generator: func() string { return "fixed-id" }}))
+	app.Get("/", func(c *Context) error {
+		return c.String(http.StatusOK, RequestIDFromContext(c.Context()))
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Body.String() != "fixed-id" {
+		t.Errorf("expected the ID in the request context, got %q", w.Body.String())
+	}
+
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if c.RequestID() != "" {
+		t.Error("expected no ID without the middleware")
+	}
+}
+
+func TestRequestID_ErrorResponses(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(RequestID())
+	app.Get("/error", func(c *Context) error {
+		return NotFound("no such user")
+	})
+	app.Get("/problem", func(c *Context) error {
+		return NewProblem(http.StatusConflict, "already exists").With("field", "email")
+	})
+	app.Mount()
+
+	for _, path := range []string{"/error", "/problem"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Request-ID", "trace-42")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if !strings.Contains(w.Body.String(), `"request_id":"trace-42"`) {
+			t.Errorf("%s: expected the request ID in the body, got %s", path, w.Body.String())
+		}
+	}
+}
+
+func TestRequestID_Logged(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.SetLogger(RequestLoggerConfig{ShowRequestID: true, DisableColors: true, Level: LogLevelInfo})
+	app.Use(RequestID())
+	app.Get("/", func(c *Context) error {
+		return c.NoContent()
+	})
+	app.Mount()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "trace-42")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	if !strings.Contains(buf.String(), "[trace-42]") {
+		t.Errorf("expected the request ID in the log, got %q", buf.String())
+	}
+}
+
+func TestRequestID_AssignedByProxy(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.SetLogger(RequestLoggerConfig{ShowRequestID: true, DisableColors: true, Level: LogLevelInfo})
+	if err := app.SetProxy(func(c *Context) (*ProxyResult, error) {
+		c.SetHeader("X-Request-ID", "from-proxy")
+		return Continue(), nil
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	app.Use(RequestID())
+
+	var got string
+	app.Get("/", func(c *Context) error {
+		got = c.RequestID()
+		return c.NoContent()
+	})
+	app.Mount()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "from-client")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if got != "from-proxy" || w.Header().Get("X-Request-ID") != "from-proxy" {
+		t.Errorf("expected the proxy's ID to be kept, got %q and header %q", got, w.Header().Get("X-Request-ID"))
+	}
+	if !strings.Contains(buf.String(), "[from-proxy]") {
+		t.Errorf("expected the proxy's ID in the log, got %q", buf.String())
+	}
+}
+
+func TestContext_Logger(t *testing.T) {
+	var buf bytes.Buffer
+
+	app := New()
+	app.SetLogger(RequestLoggerConfig{Level: LogLevelOff, Slog: slog.New(slog.NewTextHandler(&buf, nil))})
+	app.Use(RequestIDWithConfig(RequestIDConfig{Generator: func() string { return "fixed-id" }}))
+	app.Post("/users/{id}", func(c *Context) error {
+		c.Logger().Info("user updated", "user_id", c.Param("id"))
+		return c.NoContent()
+	})
+	app.Mount()
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/42", nil))
+
+	line := buf.String()
+	for _, want := range []string{`msg="user updated"`, "request_id=fixed-id", "method=POST", "route=/users/{id}", "user_id=42"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %s in %q", want, line)
+		}
+	}
+}
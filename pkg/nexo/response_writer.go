@@ -58,9 +58,15 @@ func (rw *responseWriter) Written() bool {
 }
 
 // Hijack implements the http.Hijacker interface for WebSocket support.
+// A hijacked connection is logged as 101 Switching Protocols.
 func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	if hijacker, ok := rw.ResponseWriter.(http.Hijacker); ok {
-		return hijacker.Hijack()
+		conn, brw, err := hijacker.Hijack()
+		if err == nil && !rw.wroteHeader {
+			rw.status = http.StatusSwitchingProtocols
+			rw.wroteHeader = true
+		}
+		return conn, brw, err
 	}
 	return nil, nil, http.ErrNotSupported
 }
@@ -72,6 +78,11 @@ func (rw *responseWriter) Flush() {
 	}
 }
 
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Push implements the http.Pusher interface for HTTP/2 server push.
 func (rw *responseWriter) Push(target string, opts *http.PushOptions) error {
 	if pusher, ok := rw.ResponseWriter.(http.Pusher); ok {
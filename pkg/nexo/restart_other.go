@@ -0,0 +1,17 @@
+//go:build !unix
+
+package nexo
+
+import (
+	"context"
+	"net"
+	"os"
+)
+
+// inheritedListener returns nil: graceful restarts need a Unix system.
+func inheritedListener() (net.Listener, *os.File, error) {
+	return nil, nil, nil
+}
+
+// watchRestart does nothing: graceful restarts need a Unix system.
+func (a *App) watchRestart(ctx context.Context, ln net.Listener, restarted func()) {}
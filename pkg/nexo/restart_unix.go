@@ -0,0 +1,125 @@
+//go:build unix
+
+package nexo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// inheritListenerEnv is set for a process started by a graceful restart.
+// Its listener is file descriptor 3 and it reports readiness on 4.
+const inheritListenerEnv = "NEXO_INHERIT_LISTENER"
+
+// restartArgs returns the arguments the new process is started with.
+var restartArgs = func() []string { return os.Args[1:] }
+
+// inheritedListener returns the listener passed on by the process this one
+// replaces, and the pipe to report readiness on, or nil if there is none.
+func inheritedListener() (net.Listener, *os.File, error) {
+	if os.Getenv(inheritListenerEnv) == "" {
+		return nil, nil, nil
+	}
+	_ = os.Unsetenv(inheritListenerEnv)
+
+	f := os.NewFile(3, "listener")
+	defer f.Close()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("inherited listener: %w", err)
+	}
+	return ln, os.NewFile(4, "ready"), nil
+}
+
+// watchRestart calls restarted after SIGUSR2 once a new process has taken
+// over ln, so this one can shut down gracefully.
+func (a *App) watchRestart(ctx context.Context, ln net.Listener, restarted func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sig:
+				diagnostic(a.slogger(), slog.LevelInfo, "\n  Restarting...\n", "server restarting")
+				if err := a.restart(ln); err != nil {
+					diagnostic(a.slogger(), slog.LevelError, fmt.Sprintf("  Restart failed: %v\n", err), "restart failed", "error", err)
+					continue
+				}
+				restarted()
+				return
+			}
+		}
+	}()
+}
+
+// restart starts a new process of the same binary with ln and waits, for up
+// to the shutdown timeout, until it is ready to serve. Connections keep
+// being accepted on the shared socket throughout.
+func (a *App) restart(ln net.Listener) error {
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return fmt.Errorf("listener %T can't be passed on", ln)
+	}
+	f, err := filer.File()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+
+	cmd := exec.Command(exe, restartArgs()...)
+	cmd.Env = append(os.Environ(), inheritListenerEnv+"=1")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{f, readyW}
+	err = cmd.Start()
+	_ = readyW.Close()
+	if err != nil {
+		return err
+	}
+
+	// The new process writes a byte once it serves; EOF means it exited
+	result := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		result <- err
+	}()
+
+	select {
+	case err = <-result:
+	case <-time.After(a.shutdownTimeout):
+		err = errors.New("timed out")
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("new process didn't start: %w", err)
+	}
+
+	// The socket file now belongs to the new process
+	if ul, ok := ln.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+	return cmd.Process.Release()
+}
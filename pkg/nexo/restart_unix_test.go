@@ -0,0 +1,91 @@
+//go:build unix
+
+package nexo
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestApp_GracefulRestart(t *testing.T) {
+	// The restarted process runs this test again and serves until killed
+	if os.Getenv(inheritListenerEnv) != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		app := New(WithGracefulRestart())
+		app.DisableLogger()
+		app.Get("/pid", func(c *Context) error {
+			return c.String(200, strconv.Itoa(os.Getpid()))
+		})
+		if err := app.ListenWithContext(ctx); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	defer func(args func() []string) { restartArgs = args }(restartArgs)
+	restartArgs = func() []string { return []string{"-test.run=^TestApp_GracefulRestart$"} }
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	app := New(WithGracefulRestart())
+	app.DisableLogger()
+	app.Get("/pid", func(c *Context) error {
+		return c.String(200, strconv.Itoa(os.Getpid()))
+	})
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- app.serve(context.Background(), ln)
+	}()
+
+	baseURL := "http://" + ln.Addr().String()
+	if err := waitForServer(baseURL+"/pid", 2*time.Second); err != nil {
+		t.Fatalf("server failed to start: %v", err)
+	}
+	http.DefaultClient.CloseIdleConnections()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the old process didn't shut down")
+	}
+
+	// The new process serves on the same socket
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(baseURL + "/pid")
+	if err != nil {
+		t.Fatalf("request after restart failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	pid, err := strconv.Atoi(string(body))
+	if err != nil {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if pid == os.Getpid() {
+		t.Error("expected the request to be served by the new process")
+	}
+	_ = syscall.Kill(pid, syscall.SIGTERM)
+}
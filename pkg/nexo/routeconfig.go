@@ -0,0 +1,122 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RouteConfig holds settings for a single route, exported by its route.go
+// so they live next to the handlers:
+//
+//	var Config = nexo.RouteConfig{
+//	    Timeout:     5 * time.Second,
+//	    MaxBodySize: 1 << 20,
+//	    RateLimit:   &nexo.RateLimitConfig{Limit: 10, Window: time.Minute},
+//	    RequireAuth: true,
+//	}
+//
+// The settings apply to every method of the route, after the global and
+// directory middleware. Zero values leave a setting off.
+type RouteConfig struct {
+	// Timeout sets a deadline for the request's context, like
+	// WithHandlerTimeout for this route only. Handlers that return
+	// context.DeadlineExceeded get a 504 response.
+	Timeout time.Duration
+
+	// MaxBodySize is the largest request body in bytes. Larger bodies get a
+	// 413 response.
+	MaxBodySize int64
+
+	// Cache caches successful GET responses for this long (see Cache).
+	Cache time.Duration
+
+	// RateLimit limits how many requests each client makes to the route
+	// (see RateLimit). All methods of the route share the limit.
+	RateLimit *RateLimitConfig
+
+	// RequireAuth rejects requests the app's Authenticator doesn't accept
+	// (see WithAuthenticator).
+	RequireAuth bool
+}
+
+// middlewares returns the middleware applying the config, outermost first:
+// requests are rate limited before they're authenticated, so the limit
+// also protects the Authenticator.
+func (rc RouteConfig) middlewares() []MiddlewareFunc {
+	var mws []MiddlewareFunc
+	if rc.RateLimit != nil {
+		mws = append(mws, RateLimit(*rc.RateLimit))
+	}
+	if rc.RequireAuth {
+		mws = append(mws, requireAuth)
+	}
+	if rc.MaxBodySize > 0 {
+		mws = append(mws, maxBodySize(rc.MaxBodySize))
+	}
+	if rc.Timeout > 0 {
+		mws = append(mws, routeTimeout(rc.Timeout))
+	}
+	if rc.Cache > 0 {
+		mws = append(mws, Cache(rc.Cache))
+	}
+	return mws
+}
+
+// Authenticator checks that a request is authenticated, e.g. by verifying
+// a session cookie or bearer token, and returns an error to reject it,
+// usually Unauthorized. It can store the user in the request for the
+// handler (see Key). Set one with WithAuthenticator.
+type Authenticator func(c *Context) error
+
+// authenticatorKey is the request context key for the app's Authenticator.
+type authenticatorKey struct{}
+
+// withAuthenticator returns r with the app's Authenticator in its context.
+func withAuthenticator(r *http.Request, auth Authenticator) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), authenticatorKey{}, auth))
+}
+
+// requireAuth runs the app's Authenticator before the handler. Without
+// one, requests are rejected rather than served unauthenticated.
+func requireAuth(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		auth, _ := c.Request.Context().Value(authenticatorKey{}).(Authenticator)
+		if auth == nil {
+			return fmt.Errorf("route %s requires auth, but the app has no Authenticator (see WithAuthenticator)", c.Path())
+		}
+		if err := auth(c); err != nil {
+			return err
+		}
+		return next(c)
+	}
+}
+
+// maxBodySize rejects request bodies larger than n bytes with a 413.
+// Bodies without a Content-Length fail with a 413 from Bind once they pass
+// the limit.
+func maxBodySize(n int64) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if c.Request.ContentLength > n {
+				return NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large")
+			}
+			if c.Request.Body != nil {
+				c.Request.Body = http.MaxBytesReader(c.Response, c.Request.Body, n)
+			}
+			return next(c)
+		}
+	}
+}
+
+// routeTimeout sets a deadline of d for the request's context.
+func routeTimeout(d time.Duration) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			cancel := c.WithTimeout(d)
+			defer cancel()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteConfig_RateLimitSharedByMethods(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/api/users", func(c *Context) error {
+		return c.String(http.StatusOK, "list")
+	})
+	app.Post("/api/users", func(c *Context) error {
+		return c.String(http.StatusCreated, "created")
+	})
+	app.Get("/api/other", func(c *Context) error {
+		return c.String(http.StatusOK, "other")
+	})
+	app.RouteTree().SetRouteConfig("/api/users", RouteConfig{
+		RateLimit: &RateLimitConfig{Limit: 2, Window: time.Minute},
+	})
+	app.Mount()
+
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	if code := serve(http.MethodGet, "/api/users"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := serve(http.MethodPost, "/api/users"); code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	if code := serve(http.MethodGet, "/api/users"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 once the methods used up the limit, got %d", code)
+	}
+	if code := serve(http.MethodGet, "/api/other"); code != http.StatusOK {
+		t.Errorf("expected other routes to be unaffected, got %d", code)
+	}
+	// CORS preflights skip the route config
+	if code := serve(http.MethodOptions, "/api/users"); code != http.StatusNoContent {
+		t.Errorf("expected OPTIONS to be answered, got %d", code)
+	}
+}
+
+func TestRouteConfig_RequireAuth(t *testing.T) {
+	newApp := func(opts ...Option) *App {
+		app := New(opts...)
+		app.DisableLogger()
+		app.Get("/account", func(c *Context) error {
+			return c.String(http.StatusOK, "account")
+		})
+		app.RouteTree().SetRouteConfig("/account", RouteConfig{RequireAuth: true})
+		app.Mount()
+		return app
+	}
+
+	app := newApp(WithAuthenticator(func(c *Context) error {
+		if c.Header("Authorization") != "Bearer secret" {
+			return Unauthorized("login required")
+		}
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/account", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without credentials, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/account", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	app.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 with credentials, got %d", w.Code)
+	}
+
+	// Without an Authenticator, requests are rejected
+	w = httptest.NewRecorder()
+	newApp().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/account", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 without an Authenticator, got %d", w.Code)
+	}
+}
+
+func TestRouteConfig_MaxBodySize(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Post("/upload", func(c *Context) error {
+		var body map[string]any
+		if err := c.Bind(&body); err != nil {
+			return err
+		}
+		return c.NoContent()
+	})
+	app.RouteTree().SetRouteConfig("/upload", RouteConfig{MaxBodySize: 16})
+	app.Mount()
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"small body", `{"a":1}`, http.StatusNoContent},
+		{"large body", `{"a":"` + strings.Repeat("x", 32) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+
+	// Bodies without a Content-Length are cut off at the limit
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(tests[1].body))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for a chunked body, got %d", w.Code)
+	}
+}
+
+func TestRouteConfig_Timeout(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/slow", func(c *Context) error {
+		<-c.Context().Done()
+		return c.Context().Err()
+	})
+	app.RouteTree().SetRouteConfig("/slow", RouteConfig{Timeout: 10 * time.Millisecond})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected 504, got %d", w.Code)
+	}
+}
+
+func TestRouteConfig_Cache(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	calls := 0
+	app.Get("/report", func(c *Context) error {
+		calls++
+		return c.String(http.StatusOK, "report")
+	})
+	app.RouteTree().SetRouteConfig("/report", RouteConfig{Cache: time.Minute})
+	app.Mount()
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", calls)
+	}
+}
+
+func TestRouteConfig_Middlewares(t *testing.T) {
+	if mws := (RouteConfig{}).middlewares(); len(mws) != 0 {
+		t.Errorf("expected no middleware for an empty config, got %d", len(mws))
+	}
+}
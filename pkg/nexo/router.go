@@ -1,8 +1,12 @@
 package nexo
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -43,29 +47,136 @@ type Route struct {
 
 	// Middlewares specific to this route
 	Middlewares []MiddlewareFunc
+
+	// Fallback marks a route that only handles its method and pattern
+	// until another route does, like those added by App.Any. Routes added
+	// for the same method and pattern replace it, before or after.
+	Fallback bool
+
+	// autoHead marks a HEAD route answered by a GET handler, whose body is
+	// discarded
+	autoHead bool
 }
 
 // RouteTree holds all discovered routes and middleware.
 type RouteTree struct {
 	routes           []*Route
-	middlewares      map[string][]MiddlewareFunc // path -> middlewares
-	middlewareScopes map[string]string           // path -> filesystem scope for route groups
+	methods          map[string][]string         // pattern -> methods with a handler
+	middlewares      map[string][]MiddlewareFunc // key (see middlewareKey) -> middlewares
+	middlewareScopes map[string]string           // key -> filesystem scope for route groups
+	middlewareOrder  map[string]int              // key -> MiddlewareOptions.Priority
 	proxy            ProxyFunc                   // proxy function (from app/proxy.go)
 	proxyConfig      *ProxyConfig                // proxy configuration (optional)
+	routeHooks       []func(RouteInfo)           // called for every added route
+	routeConfigs     map[string][]MiddlewareFunc // pattern -> middlewares applying its RouteConfig
+	autoHead         bool                        // answer HEAD for patterns with only GET
+	autoOptions      bool                        // answer OPTIONS for patterns without it
 }
 
 // NewRouteTree creates a new RouteTree.
 func NewRouteTree() *RouteTree {
 	return &RouteTree{
 		routes:           make([]*Route, 0),
+		methods:          make(map[string][]string),
 		middlewares:      make(map[string][]MiddlewareFunc),
 		middlewareScopes: make(map[string]string),
+		middlewareOrder:  make(map[string]int),
+		routeConfigs:     make(map[string][]MiddlewareFunc),
+		autoHead:         true,
+		autoOptions:      true,
 	}
 }
 
+// SetAutoHead sets whether patterns with a GET handler but no HEAD handler
+// answer HEAD requests by running the GET handler without sending its
+// body. Enabled by default.
+func (rt *RouteTree) SetAutoHead(enabled bool) {
+	rt.autoHead = enabled
+}
+
+// SetAutoOptions sets whether patterns without an OPTIONS handler answer
+// OPTIONS requests with a 204 listing their methods in the Allow header.
+// Enabled by default.
+func (rt *RouteTree) SetAutoOptions(enabled bool) {
+	rt.autoOptions = enabled
+}
+
 // AddRoute adds a route to the tree.
 func (rt *RouteTree) AddRoute(route *Route) {
+	for i, r := range rt.routes {
+		if r.Method != route.Method || r.Pattern != route.Pattern {
+			continue
+		}
+		if route.Fallback && !r.Fallback {
+			return
+		}
+		if r.Fallback {
+			rt.routes[i] = route
+			for _, hook := range rt.routeHooks {
+				hook(route.info())
+			}
+			return
+		}
+	}
+
 	rt.routes = append(rt.routes, route)
+	if !slices.Contains(rt.methods[route.Pattern], route.Method) {
+		rt.methods[route.Pattern] = append(rt.methods[route.Pattern], route.Method)
+	}
+	for _, hook := range rt.routeHooks {
+		hook(route.info())
+	}
+}
+
+// OnRoute registers hook to be called for every route in the tree,
+// including those added before it.
+func (rt *RouteTree) OnRoute(hook func(RouteInfo)) {
+	rt.routeHooks = append(rt.routeHooks, hook)
+	for _, route := range rt.routes {
+		hook(route.info())
+	}
+}
+
+// info returns the route's RouteInfo.
+func (r *Route) info() RouteInfo {
+	return RouteInfo{
+		Method:   r.Method,
+		Pattern:  r.Pattern,
+		FilePath: r.FilePath,
+		Priority: r.Priority,
+	}
+}
+
+// Methods returns the methods with a handler for pattern, in the order
+// they are listed in Allow headers.
+func (rt *RouteTree) Methods(pattern string) []string {
+	methods := slices.Clone(rt.methods[pattern])
+	sortMethods(methods)
+	return methods
+}
+
+// methodOrder is the order methods are listed in Allow headers.
+var methodOrder = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// sortMethods sorts methods by methodOrder, with unknown methods last.
+func sortMethods(methods []string) {
+	rank := func(m string) int {
+		if i := slices.Index(methodOrder, m); i >= 0 {
+			return i
+		}
+		return len(methodOrder)
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		return rank(methods[i]) < rank(methods[j])
+	})
 }
 
 // AddMiddleware adds middleware for a path prefix with filesystem scope.
@@ -77,12 +188,89 @@ func (rt *RouteTree) AddRoute(route *Route) {
 //   - scope: The filesystem scope preserving route groups (e.g., "(dashboard)", "api")
 //   - mw: The middleware function
 func (rt *RouteTree) AddMiddleware(path, scope string, mw MiddlewareFunc) {
-	rt.middlewares[path] = append(rt.middlewares[path], mw)
+	key := middlewareKey(path, scope)
+	rt.middlewares[key] = append(rt.middlewares[key], mw)
 	if scope != "" {
-		rt.middlewareScopes[path] = scope
+		rt.middlewareScopes[key] = scope
+	}
+}
+
+// middlewareKey returns the key middleware for path is stored under.
+// Middleware from a route group is kept apart from middleware for the same
+// path elsewhere, so app/(marketing)/middleware.go and
+// app/(shop)/middleware.go can both apply to "/".
+func middlewareKey(path, scope string) string {
+	if !strings.Contains(scope, "(") {
+		return path
+	}
+	return path + " " + scope
+}
+
+// middlewarePath returns the path prefix of a middleware key.
+func middlewarePath(key string) string {
+	path, _, _ := strings.Cut(key, " ")
+	return path
+}
+
+// inScope reports whether a route with routeScope is in scope, the
+// directory a middleware or layout was defined in.
+func inScope(routeScope, scope string) bool {
+	return scope == "" || routeScope == scope || strings.HasPrefix(routeScope, scope+"/")
+}
+
+// MiddlewareOptions controls where directory middleware runs. A
+// middleware.go exports it next to its Middleware:
+//
+//	var Config = nexo.MiddlewareOptions{
+//	    Skip:     []string{"/api/health"},
+//	    Priority: 10,
+//	}
+type MiddlewareOptions struct {
+	// Skip lists paths, and the paths below them, the middleware doesn't
+	// run for, e.g. "/api/health" or "/api/webhooks/{provider}".
+	Skip []string
+
+	// Priority orders the middleware that applies to a route: higher
+	// priorities run first. Middleware with the same priority, 0 by
+	// default, runs from the root directory to the most specific one.
+	Priority int
+}
+
+// AddMiddlewareWithOptions adds middleware for a path prefix, like
+// AddMiddleware, skipping the paths in opts.Skip and ordered by
+// opts.Priority.
+func (rt *RouteTree) AddMiddlewareWithOptions(path, scope string, mw MiddlewareFunc, opts MiddlewareOptions) {
+	if len(opts.Skip) > 0 {
+		mw = skipPaths(mw, opts.Skip)
+	}
+	rt.AddMiddleware(path, scope, mw)
+	if opts.Priority != 0 {
+		rt.middlewareOrder[middlewareKey(path, scope)] = opts.Priority
 	}
 }
 
+// skipPaths returns mw that passes requests under any of paths straight to
+// the next handler.
+func skipPaths(mw MiddlewareFunc, paths []string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		wrapped := mw(next)
+		return func(c *Context) error {
+			for _, p := range paths {
+				if matchesPrefix(c.Path(), p) {
+					return next(c)
+				}
+			}
+			return wrapped(c)
+		}
+	}
+}
+
+// SetRouteConfig applies config to every method of the route with pattern.
+// Generated routes call it for route.go files that export a Config.
+func (rt *RouteTree) SetRouteConfig(pattern string, config RouteConfig) {
+	rt.routeConfigs[pattern] = config.middlewares()
+}
+
 // SetProxy sets the proxy function and optional configuration.
 func (rt *RouteTree) SetProxy(proxy ProxyFunc, config *ProxyConfig) error {
 	rt.proxy = proxy
@@ -113,6 +301,17 @@ func (rt *RouteTree) ProxyConfiguration() *ProxyConfig {
 	return rt.proxyConfig
 }
 
+// handler returns the handler registered last for method and pattern, or
+// nil if there is none.
+func (rt *RouteTree) handler(method, pattern string) HandlerFunc {
+	for _, r := range slices.Backward(rt.routes) {
+		if r.Method == method && r.Pattern == pattern {
+			return r.Handler
+		}
+	}
+	return nil
+}
+
 // Routes returns all registered routes (sorted by priority).
 func (rt *RouteTree) Routes() []*Route {
 	sorted := make([]*Route, len(rt.routes))
@@ -139,18 +338,44 @@ func (rt *RouteTree) Routes() []*Route {
 //   - routeScope: The filesystem scope of the route (e.g., "(dashboard)/apps", "api/users")
 func (rt *RouteTree) GetMiddlewareChain(pattern string, routeScope string) []MiddlewareFunc {
 	var chain []MiddlewareFunc
+	for _, path := range rt.MiddlewarePaths(pattern, routeScope) {
+		chain = append(chain, rt.middlewares[path]...)
+	}
+	return chain
+}
 
-	// First, check for root-level middleware (empty string or "/" key)
-	for _, rootKey := range []string{"", "/"} {
-		if mws, ok := rt.middlewares[rootKey]; ok {
-			scope := rt.middlewareScopes[rootKey]
-			// Root middleware applies if: no scope OR route is under that scope
-			if scope == "" || strings.HasPrefix(routeScope, scope) {
-				chain = append(chain, mws...)
+// MiddlewarePaths returns the paths of the middleware that applies to a
+// route in the order it runs: by priority (see MiddlewareOptions), then
+// from the root to the most specific. Middleware from a route group is
+// listed with its scope, e.g. "/ (marketing)".
+func (rt *RouteTree) MiddlewarePaths(pattern string, routeScope string) []string {
+	var paths []string
+	add := func(path string) {
+		// Middleware applies if: no scope OR route is under that scope
+		if _, ok := rt.middlewares[path]; ok && inScope(routeScope, rt.middlewareScopes[path]) {
+			paths = append(paths, path)
+		}
+		// Route group middleware for the same path, outer groups first
+		var groupKeys []string
+		for key := range rt.middlewares {
+			if key != path && middlewarePath(key) == path {
+				groupKeys = append(groupKeys, key)
+			}
+		}
+		sort.Slice(groupKeys, func(i, j int) bool {
+			return len(groupKeys[i]) < len(groupKeys[j]) || (len(groupKeys[i]) == len(groupKeys[j]) && groupKeys[i] < groupKeys[j])
+		})
+		for _, key := range groupKeys {
+			if inScope(routeScope, rt.middlewareScopes[key]) {
+				paths = append(paths, key)
 			}
 		}
 	}
 
+	// First, check for root-level middleware (empty string or "/" key)
+	add("")
+	add("/")
+
 	// Build chain from root to specific route
 	segments := strings.Split(pattern, "/")
 	currentPath := ""
@@ -160,27 +385,35 @@ func (rt *RouteTree) GetMiddlewareChain(pattern string, routeScope string) []Mid
 			continue
 		}
 		currentPath += "/" + seg
-
-		if mws, ok := rt.middlewares[currentPath]; ok {
-			scope := rt.middlewareScopes[currentPath]
-			// Middleware applies if: no scope OR route is under that scope
-			if scope == "" || strings.HasPrefix(routeScope, scope) {
-				chain = append(chain, mws...)
-			}
-		}
+		add(currentPath)
 	}
 
-	return chain
+	sort.SliceStable(paths, func(i, j int) bool {
+		return rt.middlewareOrder[paths[i]] > rt.middlewareOrder[paths[j]]
+	})
+	return paths
 }
 
 // Mount registers all routes with the chi router.
+// Patterns with only a GET handler answer HEAD with it, patterns without an
+// OPTIONS handler answer OPTIONS with their allowed methods (see SetAutoHead
+// and SetAutoOptions), and requests with a method a matched pattern doesn't
+// handle get a 405 with an Allow header.
 func (rt *RouteTree) Mount(router chi.Router, globalMiddlewares []MiddlewareFunc) {
 	routes := rt.Routes()
+	routes = append(routes, rt.headRoutes()...)
+	handlerRoutes := len(routes)
+	routes = append(routes, rt.optionsRoutes()...)
 
-	for _, route := range routes {
-		// Build middleware chain: global -> path-based -> route-specific
+	for i, route := range routes {
+		// Build middleware chain: global -> path-based -> route config -> route-specific
 		middlewares := append([]MiddlewareFunc{}, globalMiddlewares...)
 		middlewares = append(middlewares, rt.GetMiddlewareChain(route.Pattern, route.Scope)...)
+		// The automatic OPTIONS answers skip the route config, so CORS
+		// preflights aren't rate limited or asked to authenticate
+		if i < handlerRoutes {
+			middlewares = append(middlewares, rt.routeConfigs[route.Pattern]...)
+		}
 		middlewares = append(middlewares, route.Middlewares...)
 
 		handler := rt.wrapHandler(route, middlewares)
@@ -202,11 +435,190 @@ func (rt *RouteTree) Mount(router chi.Router, globalMiddlewares []MiddlewareFunc
 			router.Options(route.Pattern, handler)
 		}
 	}
+
+	router.MethodNotAllowed(rt.methodNotAllowed(router))
+}
+
+// headRoutes returns routes that answer HEAD with the GET handler for the
+// patterns that don't handle HEAD. They run the same middleware and route
+// config as the GET route, and the body is discarded once it's through the
+// middleware, so headers such as ETag and Content-Length match.
+func (rt *RouteTree) headRoutes() []*Route {
+	if !rt.autoHead {
+		return nil
+	}
+	var routes []*Route
+	for _, route := range rt.Routes() {
+		if route.Method != http.MethodGet || slices.Contains(rt.methods[route.Pattern], http.MethodHead) {
+			continue
+		}
+		head := *route
+		head.Method = http.MethodHead
+		head.autoHead = true
+		routes = append(routes, &head)
+	}
+	return routes
+}
+
+// headWriter discards the body of a response to a HEAD request. It holds
+// the header back until the handler returns, so the Content-Length of the
+// discarded body can be sent, as for GET.
+type headWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	sent        bool
+	size        int
+}
+
+// WriteHeader records the status until the response is finished or flushed.
+func (w *headWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+}
+
+// Write counts and discards the body.
+func (w *headWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.size += len(b)
+	return len(b), nil
+}
+
+// Flush implements http.Flusher. The length of a flushed body isn't known,
+// so no Content-Length is sent.
+func (w *headWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.send(false)
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish sends the header with the length of the discarded body.
+func (w *headWriter) finish() {
+	w.send(true)
+}
+
+func (w *headWriter) send(done bool) {
+	if w.sent || !w.wroteHeader {
+		return
+	}
+	w.sent = true
+	h := w.Header()
+	if done && w.size > 0 && h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+		h.Set("Content-Length", strconv.Itoa(w.size))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// allowedMethods returns the methods pattern answers, including the ones
+// answered automatically, in the order they are listed in Allow headers.
+func (rt *RouteTree) allowedMethods(pattern string) []string {
+	methods := rt.Methods(pattern)
+	if len(methods) == 0 {
+		return nil
+	}
+	if rt.autoHead && slices.Contains(methods, http.MethodGet) && !slices.Contains(methods, http.MethodHead) {
+		methods = append(methods, http.MethodHead)
+	}
+	if rt.autoOptions && !slices.Contains(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+	sortMethods(methods)
+	return methods
+}
+
+// optionsRoutes returns routes that answer OPTIONS for the patterns that
+// don't handle it, listing the allowed methods in the Allow header. They
+// run the same middleware as the pattern's other routes, so CORS preflight
+// requests are answered too.
+func (rt *RouteTree) optionsRoutes() []*Route {
+	if !rt.autoOptions {
+		return nil
+	}
+	var routes []*Route
+	seen := make(map[string]bool)
+	for _, route := range rt.Routes() {
+		if seen[route.Pattern] || slices.Contains(rt.methods[route.Pattern], http.MethodOptions) {
+			continue
+		}
+		seen[route.Pattern] = true
+
+		allow := strings.Join(rt.allowedMethods(route.Pattern), ", ")
+		routes = append(routes, &Route{
+			Pattern:       route.Pattern,
+			Method:        http.MethodOptions,
+			Scope:         route.Scope,
+			Priority:      route.Priority,
+			CatchAllParam: route.CatchAllParam,
+			Handler: func(c *Context) error {
+				c.SetHeader("Allow", allow)
+				return c.NoContent()
+			},
+		})
+	}
+	return routes
+}
+
+// methodNotAllowed returns the handler for requests whose path matches a
+// route but whose method doesn't. It responds 405 with the methods that
+// path does handle in the Allow header.
+func (rt *RouteTree) methodNotAllowed(router chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range rt.allMethods() {
+			if router.Match(chi.NewRouteContext(), method, r.URL.Path) {
+				allowed = append(allowed, method)
+			}
+		}
+
+		c := NewContext(w, r)
+		if len(allowed) > 0 {
+			c.SetHeader("Allow", strings.Join(allowed, ", "))
+		}
+		handleError(c, NewHTTPError(http.StatusMethodNotAllowed, "method not allowed"))
+	}
+}
+
+// allMethods returns every method some pattern answers.
+func (rt *RouteTree) allMethods() []string {
+	var methods []string
+	for pattern := range rt.methods {
+		for _, m := range rt.allowedMethods(pattern) {
+			if !slices.Contains(methods, m) {
+				methods = append(methods, m)
+			}
+		}
+	}
+	sortMethods(methods)
+	return methods
 }
 
 // wrapHandler converts a HandlerFunc with middleware chain to http.HandlerFunc.
 func (rt *RouteTree) wrapHandler(route *Route, middlewares []MiddlewareFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// Discard the body of automatic HEAD responses outside the middleware
+		if route.autoHead {
+			hw := &headWriter{ResponseWriter: w, status: http.StatusOK}
+			defer hw.finish()
+			w = hw
+		}
 		ctx := NewContext(w, r)
 
 		// For catch-all routes, map the "*" param to the original param name
@@ -223,31 +635,86 @@ func (rt *RouteTree) wrapHandler(route *Route, middlewares []MiddlewareFunc) htt
 		}
 
 		// Execute the handler chain
+		defer ctx.finish()
 		if err := h(ctx); err != nil {
 			handleError(ctx, err)
 		}
 	}
 }
 
-// handleError handles errors returned by handlers.
+// handleError handles errors returned by handlers, with the app's OnError
+// handler if one is set, then the nearest error component for HTML
+// requests, and DefaultErrorHandler otherwise.
 func handleError(c *Context, err error) {
 	// Don't write if response already sent
 	if c.Written() {
 		return
 	}
 
+	if onError, ok := c.Request.Context().Value(errorHandlerKey{}).(ErrorHandler); ok && onError != nil {
+		// Errors the handler returns instead of responding get the default response
+		if err = onError(c, err); err == nil || c.Written() {
+			return
+		}
+	}
+
+	if renderErrorBoundary(c, err) {
+		return
+	}
+
+	_ = DefaultErrorHandler(c, err)
+}
+
+// DefaultErrorHandler writes err as a JSON error response: HTTPErrors with
+// their code and message, validation errors with the invalid fields,
+// timeouts as 504 and anything else as a 500. ProblemErrors are sent as
+// problem details. OnError handlers can call it
+// for the errors they don't handle themselves.
+func DefaultErrorHandler(c *Context, err error) error {
+	// Don't write if response already sent
+	if c.Written() {
+		return nil
+	}
+
+	// Problem details are always sent as they are
+	if p, ok := IsProblemError(err); ok {
+		return c.Problem(p)
+	}
+
+	// Handlers that ran out of time (see WithTimeout and WithHandlerTimeout)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return c.Error(http.StatusGatewayTimeout, "request timeout")
+	}
+
+	// Validation errors list the invalid fields
+	if valErr, ok := IsValidationError(err); ok {
+		code, message := http.StatusUnprocessableEntity, "validation failed"
+		if httpErr, ok := IsHTTPError(err); ok {
+			code, message = httpErr.Code, httpErr.Message
+		}
+		body := map[string]any{
+			"code":    code,
+			"message": message,
+			"fields":  valErr.Fields,
+		}
+		if id := c.RequestID(); id != "" {
+			body["request_id"] = id
+		}
+		return c.JSON(code, map[string]any{"error": body})
+	}
+
 	// Check if it's an HTTPError
 	if httpErr, ok := IsHTTPError(err); ok {
-		_ = c.Error(httpErr.Code, httpErr.Message)
-		return
+		return c.Error(httpErr.Code, httpErr.Message)
 	}
 
 	// Default to internal server error
-	_ = c.Error(http.StatusInternalServerError, "internal server error")
+	return c.Error(http.StatusInternalServerError, "internal server error")
 }
 
 // CalculatePriority calculates the priority for a route pattern.
-// Static routes have highest priority, catch-all lowest.
+// Static routes have highest priority, then constrained dynamic segments
+// like {id:[0-9]+}, then plain dynamic segments, and catch-all lowest.
 func CalculatePriority(pattern string) int {
 	priority := 100
 
@@ -262,9 +729,13 @@ func CalculatePriority(pattern string) int {
 			return 5
 		}
 
-		// Dynamic segment
-		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
-			priority = min(priority, 50)
+		// Dynamic segment, ranked higher when constrained
+		if _, expr, ok := splitRouteParam(seg); ok {
+			if expr != "" {
+				priority = min(priority, 75)
+			} else {
+				priority = min(priority, 50)
+			}
 		}
 	}
 
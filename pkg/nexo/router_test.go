@@ -3,6 +3,8 @@ package nexo
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -302,6 +304,195 @@ func TestRouteTree_AllHTTPMethods(t *testing.T) {
 	}
 }
 
+func TestRouteTree_Methods(t *testing.T) {
+	tree := NewRouteTree()
+	for _, method := range []string{http.MethodDelete, http.MethodGet, http.MethodPost, http.MethodGet} {
+		tree.AddRoute(&Route{Pattern: "/users", Method: method, Handler: func(c *Context) error { return nil }})
+	}
+
+	got := strings.Join(tree.Methods("/users"), ", ")
+	if got != "GET, POST, DELETE" {
+		t.Errorf("Methods() = %q, want %q", got, "GET, POST, DELETE")
+	}
+	if methods := tree.Methods("/missing"); len(methods) != 0 {
+		t.Errorf("Methods() for an unknown pattern = %v, want none", methods)
+	}
+}
+
+func TestRouteTree_MethodNotAllowed(t *testing.T) {
+	tree := NewRouteTree()
+	handler := func(c *Context) error { return c.NoContent() }
+	tree.AddRoute(&Route{Pattern: "/users", Method: http.MethodGet, Handler: handler, Priority: 100})
+	tree.AddRoute(&Route{Pattern: "/users", Method: http.MethodPost, Handler: handler, Priority: 100})
+	tree.AddRoute(&Route{Pattern: "/users/{id}", Method: http.MethodDelete, Handler: handler, Priority: 50})
+
+	router := chi.NewRouter()
+	tree.Mount(router, nil)
+
+	tests := []struct {
+		path  string
+		allow string
+	}{
+		{"/users", "GET, HEAD, POST, OPTIONS"},
+		{"/users/42", "DELETE, OPTIONS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, tt.path, nil))
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("Expected status 405, got %d", w.Code)
+			}
+			if got := w.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Allow = %q, want %q", got, tt.allow)
+			}
+			if !strings.Contains(w.Body.String(), "method not allowed") {
+				t.Errorf("Expected error body, got %q", w.Body.String())
+			}
+		})
+	}
+
+	// Unknown paths still 404
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/posts", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown path, got %d", w.Code)
+	}
+}
+
+func TestRouteTree_AutoHead(t *testing.T) {
+	tree := NewRouteTree()
+	tree.AddRoute(&Route{Pattern: "/users", Method: http.MethodGet, Handler: func(c *Context) error {
+		c.SetHeader("X-Total", "2")
+		return c.JSON(http.StatusOK, []string{"ada", "grace"})
+	}})
+	tree.AddRoute(&Route{Pattern: "/custom", Method: http.MethodGet, Handler: func(c *Context) error { return c.NoContent() }})
+	tree.AddRoute(&Route{Pattern: "/custom", Method: http.MethodHead, Handler: func(c *Context) error {
+		c.SetHeader("X-Custom", "true")
+		return c.NoContent()
+	}})
+	tree.AddRoute(&Route{Pattern: "/users", Method: http.MethodPost, Handler: func(c *Context) error { return c.NoContent() }})
+
+	router := chi.NewRouter()
+	tree.Mount(router, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/users", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("X-Total") != "2" || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("Expected the GET handler's headers, got %v", w.Header())
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no body, got %q", w.Body.String())
+	}
+
+	// An explicit HEAD handler is kept
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/custom", nil))
+	if w.Header().Get("X-Custom") != "true" {
+		t.Error("Expected the custom HEAD handler")
+	}
+
+	// Disabled, HEAD gets a 405
+	tree.SetAutoHead(false)
+	router = chi.NewRouter()
+	tree.Mount(router, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/users", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 with auto HEAD disabled, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, POST, OPTIONS" {
+		t.Errorf("Allow = %q, want %q", got, "GET, POST, OPTIONS")
+	}
+}
+
+func TestRouteTree_AutoHeadMatchesGetHeaders(t *testing.T) {
+	tree := NewRouteTree()
+	tree.AddRoute(&Route{Pattern: "/etag", Method: http.MethodGet, Middlewares: []MiddlewareFunc{ETag()}, Handler: func(c *Context) error {
+		return c.String(http.StatusOK, "hello")
+	}})
+	tree.AddRoute(&Route{Pattern: "/plain", Method: http.MethodGet, Handler: func(c *Context) error {
+		return c.String(http.StatusOK, "hello")
+	}})
+
+	router := chi.NewRouter()
+	tree.Mount(router, nil)
+
+	for _, path := range []string{"/etag", "/plain"} {
+		get := httptest.NewRecorder()
+		router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, path, nil))
+		head := httptest.NewRecorder()
+		router.ServeHTTP(head, httptest.NewRequest(http.MethodHead, path, nil))
+
+		if head.Body.Len() != 0 {
+			t.Errorf("%s: expected no body, got %q", path, head.Body.String())
+		}
+		if got := head.Header().Get("Content-Length"); got != "5" {
+			t.Errorf("%s: HEAD Content-Length = %q, want 5", path, got)
+		}
+		if got, want := head.Header().Get("ETag"), get.Header().Get("ETag"); got != want || path == "/etag" && got == "" {
+			t.Errorf("%s: HEAD ETag = %q, want the GET ETag %q", path, got, want)
+		}
+	}
+}
+
+func TestApp_WithAutoOptions(t *testing.T) {
+	app := New(WithAutoOptions(false))
+	app.DisableLogger()
+	app.Get("/users", func(c *Context) error { return c.NoContent() })
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/users", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 with auto OPTIONS disabled, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, HEAD" {
+		t.Errorf("Allow = %q, want %q", got, "GET, HEAD")
+	}
+}
+
+func TestRouteTree_AutoOptions(t *testing.T) {
+	tree := NewRouteTree()
+	tree.AddMiddleware("/api", "api", func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			c.SetHeader("X-API", "true")
+			return next(c)
+		}
+	})
+	tree.AddRoute(&Route{Pattern: "/api/users", Method: http.MethodGet, Scope: "api/users", Handler: func(c *Context) error { return c.NoContent() }})
+	tree.AddRoute(&Route{Pattern: "/api/users", Method: http.MethodPost, Scope: "api/users", Handler: func(c *Context) error { return c.NoContent() }})
+	tree.AddRoute(&Route{Pattern: "/custom", Method: http.MethodGet, Handler: func(c *Context) error { return c.NoContent() }})
+	tree.AddRoute(&Route{Pattern: "/custom", Method: http.MethodOptions, Handler: func(c *Context) error { return c.String(200, "custom") }})
+
+	router := chi.NewRouter()
+	tree.Mount(router, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/api/users", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, HEAD, POST, OPTIONS" {
+		t.Errorf("Allow = %q, want %q", got, "GET, HEAD, POST, OPTIONS")
+	}
+	if w.Header().Get("X-API") != "true" {
+		t.Error("Expected the automatic OPTIONS route to run path middleware")
+	}
+
+	// An explicit OPTIONS handler is kept
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/custom", nil))
+	if w.Body.String() != "custom" {
+		t.Errorf("Expected the custom OPTIONS handler, got %q", w.Body.String())
+	}
+}
+
 func TestGetMiddlewareChain_RootMiddleware(t *testing.T) {
 	tree := NewRouteTree()
 
@@ -456,3 +647,164 @@ func TestGetMiddlewareChain_NestedWithRouteGroup(t *testing.T) {
 		t.Errorf("expected 2 middleware for api/users, got %d", len(chain))
 	}
 }
+
+func TestRouteTree_AddMiddlewareWithOptions_Skip(t *testing.T) {
+	tree := NewRouteTree()
+	tree.AddMiddlewareWithOptions("/api", "api", func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			c.SetHeader("X-API", "true")
+			return next(c)
+		}
+	}, MiddlewareOptions{Skip: []string{"/api/health", "/api/webhooks/{provider}"}})
+
+	for _, pattern := range []string{"/api/users", "/api/health", "/api/health/db", "/api/webhooks/{provider}"} {
+		tree.AddRoute(&Route{
+			Pattern:  pattern,
+			Method:   http.MethodGet,
+			Handler:  func(c *Context) error { return c.NoContent() },
+			Scope:    strings.TrimPrefix(pattern, "/"),
+			Priority: 100,
+		})
+	}
+
+	router := chi.NewRouter()
+	tree.Mount(router, nil)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/users", "true"},
+		{"/api/health", ""},
+		{"/api/health/db", ""},
+		{"/api/webhooks/stripe", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if got := w.Header().Get("X-API"); got != tt.want {
+			t.Errorf("%s: expected X-API %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestRouteTree_AddMiddlewareWithOptions_Priority(t *testing.T) {
+	tree := NewRouteTree()
+	var order []string
+	record := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) error {
+				order = append(order, name)
+				return next(c)
+			}
+		}
+	}
+
+	tree.AddMiddleware("/", "", record("root"))
+	tree.AddMiddleware("/api", "api", record("api"))
+	tree.AddMiddlewareWithOptions("/api/admin", "api/admin", record("admin"), MiddlewareOptions{Priority: 10})
+
+	paths := tree.MiddlewarePaths("/api/admin/users", "api/admin/users")
+	if want := []string{"/api/admin", "/", "/api"}; !slices.Equal(paths, want) {
+		t.Errorf("expected middleware paths %v, got %v", want, paths)
+	}
+
+	tree.AddRoute(&Route{
+		Pattern:  "/api/admin/users",
+		Method:   http.MethodGet,
+		Handler:  func(c *Context) error { return c.NoContent() },
+		Scope:    "api/admin/users",
+		Priority: 100,
+	})
+	router := chi.NewRouter()
+	tree.Mount(router, nil)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/admin/users", nil))
+
+	if want := []string{"admin", "root", "api"}; !slices.Equal(order, want) {
+		t.Errorf("expected middleware to run in order %v, got %v", want, order)
+	}
+}
+
+func TestGetMiddlewareChain_RouteGroupsAtSamePath(t *testing.T) {
+	tree := NewRouteTree()
+	var ran []string
+	record := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) error {
+				ran = append(ran, name)
+				return next(c)
+			}
+		}
+	}
+
+	// app/middleware.go, app/(marketing)/middleware.go and app/(shop)/middleware.go all apply to "/"
+	tree.AddMiddleware("/", "", record("root"))
+	tree.AddMiddleware("/", "(marketing)", record("marketing"))
+	tree.AddMiddleware("/", "(shop)", record("shop"))
+	tree.AddMiddleware("/", "(shop)/(account)", record("account"))
+
+	tests := []struct {
+		pattern string
+		scope   string
+		want    []string
+	}{
+		{"/about", "(marketing)/about", []string{"root", "marketing"}},
+		{"/products", "(shop)/products", []string{"root", "shop"}},
+		{"/orders", "(shop)/(account)/orders", []string{"root", "shop", "account"}},
+		{"/blog", "blog", []string{"root"}},
+		{"/shopping", "(shopping)/cart", []string{"root"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			ran = nil
+			chain := tree.GetMiddlewareChain(tt.pattern, tt.scope)
+			h := func(c *Context) error { return nil }
+			for i := len(chain) - 1; i >= 0; i-- {
+				h = chain[i](h)
+			}
+			_ = h(nil)
+			if !slices.Equal(ran, tt.want) {
+				t.Errorf("expected middleware %v, got %v", tt.want, ran)
+			}
+		})
+	}
+
+	paths := tree.MiddlewarePaths("/about", "(marketing)/about")
+	if want := []string{"/", "/ (marketing)"}; !slices.Equal(paths, want) {
+		t.Errorf("expected middleware paths %v, got %v", want, paths)
+	}
+}
+
+func TestApp_RegisterScopedRoute(t *testing.T) {
+	app := New(WithLogger(false))
+	app.RouteTree().AddMiddleware("/", "(app)", func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if c.Header("Authorization") == "" {
+				return Unauthorized("login required")
+			}
+			return next(c)
+		}
+	})
+	app.RegisterScopedRoute(http.MethodGet, "/dashboard", "(app)/dashboard", func(c *Context) error {
+		return c.String(http.StatusOK, "dashboard")
+	})
+	app.RegisterScopedRoute(http.MethodGet, "/pricing", "(marketing)/pricing", func(c *Context) error {
+		return c.String(http.StatusOK, "pricing")
+	})
+	app.Mount()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/dashboard", http.StatusUnauthorized},
+		{"/pricing", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.want, w.Code)
+		}
+	}
+}
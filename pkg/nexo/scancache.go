@@ -0,0 +1,202 @@
+package nexo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DefaultScanCacheDir is where nexo routes and apps under nexo dev keep the
+// scanner's cache, relative to the project root.
+const DefaultScanCacheDir = ".nexo/cache"
+
+// scanCacheFile is the name of the scanner's cache in its directory.
+const scanCacheFile = "scanner.json"
+
+// scanCacheVersion is bumped whenever the cached results change, so caches
+// written by other versions are discarded instead of misread.
+const scanCacheVersion = 3
+
+// scanCache keeps what the scanner learned from each route directory and
+// middleware.go, keyed by a hash of the files' content, so unchanged files
+// aren't parsed again.
+type scanCache struct {
+	Version    int                       `json:"version"`
+	Routes     map[string]cachedRoute    `json:"routes"`     // by route directory
+	Middleware map[string]middlewareFile `json:"middleware"` // by middleware.go path
+
+	path  string
+	dirty bool
+	seen  map[string]bool
+}
+
+// cachedRoute is a route directory's handlers and the diagnostics for the
+// functions skipped.
+type cachedRoute struct {
+	Hash        string          `json:"hash"`
+	Handlers    []cachedHandler `json:"handlers"`
+	Diagnostics []Diagnostic    `json:"diagnostics,omitempty"`
+}
+
+// cachedHandler is a routeHandler as stored in the cache.
+type cachedHandler struct {
+	Method     string          `json:"method,omitempty"`
+	Func       string          `json:"func"`
+	File       string          `json:"file"`
+	Directives RouteDirectives `json:"directives"`
+}
+
+// middlewareFile is what a middleware.go declares: whether it has a
+// Middleware function with a valid signature, and diagnostics for the
+// functions skipped.
+type middlewareFile struct {
+	Hash        string       `json:"hash"`
+	Valid       bool         `json:"valid"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// loadScanCache reads the cache in dir. A missing, unreadable or outdated
+// cache is replaced by an empty one.
+func loadScanCache(dir string) *scanCache {
+	c := &scanCache{path: filepath.Join(dir, scanCacheFile)}
+	if data, err := os.ReadFile(c.path); err == nil {
+		_ = json.Unmarshal(data, c)
+	}
+	if c.Version != scanCacheVersion {
+		c.Version = scanCacheVersion
+		c.Routes = nil
+		c.Middleware = nil
+	}
+	if c.Routes == nil {
+		c.Routes = make(map[string]cachedRoute)
+	}
+	if c.Middleware == nil {
+		c.Middleware = make(map[string]middlewareFile)
+	}
+	c.seen = make(map[string]bool)
+	return c
+}
+
+// route returns the cached handlers of the route in dir if its files still
+// hash to hash.
+func (c *scanCache) route(dir, hash string) (handlers []routeHandler, diags []Diagnostic, ok bool) {
+	c.seen[dir] = true
+	entry, ok := c.Routes[dir]
+	if !ok || entry.Hash != hash {
+		return nil, nil, false
+	}
+	return fromCachedHandlers(entry.Handlers), entry.Diagnostics, true
+}
+
+// setRoute caches the handlers of the route in dir.
+func (c *scanCache) setRoute(dir, hash string, handlers []routeHandler, diags []Diagnostic) {
+	c.seen[dir] = true
+	c.Routes[dir] = cachedRoute{
+		Hash:        hash,
+		Handlers:    toCachedHandlers(handlers),
+		Diagnostics: diags,
+	}
+	c.dirty = true
+}
+
+// middleware returns the cached result for a middleware.go if it still
+// hashes to hash.
+func (c *scanCache) middleware(filePath, hash string) (middlewareFile, bool) {
+	c.seen[filePath] = true
+	entry, ok := c.Middleware[filePath]
+	return entry, ok && entry.Hash == hash
+}
+
+// setMiddleware caches the result for a middleware.go.
+func (c *scanCache) setMiddleware(filePath string, entry middlewareFile) {
+	c.seen[filePath] = true
+	c.Middleware[filePath] = entry
+	c.dirty = true
+}
+
+// save drops the entries the scan just finished didn't see, for the kinds
+// of files it walked, then writes the cache if it changed. The file is
+// replaced atomically, since nexo routes and the app may share it.
+func (c *scanCache) save(routes, middleware bool) error {
+	if routes {
+		for dir := range c.Routes {
+			if !c.seen[dir] {
+				delete(c.Routes, dir)
+				c.dirty = true
+			}
+		}
+	}
+	if middleware {
+		for filePath := range c.Middleware {
+			if !c.seen[filePath] {
+				delete(c.Middleware, filePath)
+				c.dirty = true
+			}
+		}
+	}
+	c.seen = make(map[string]bool)
+
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), scanCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// hashSources returns the fingerprint of a set of files, in order.
+func hashSources(paths []string, sources [][]byte) string {
+	h := sha256.New()
+	for i, path := range paths {
+		h.Write([]byte(filepath.Base(path)))
+		h.Write([]byte{0})
+		h.Write(sources[i])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func toCachedHandlers(handlers []routeHandler) []cachedHandler {
+	if len(handlers) == 0 {
+		return nil
+	}
+	cached := make([]cachedHandler, len(handlers))
+	for i, h := range handlers {
+		cached[i] = cachedHandler{Method: h.method, Func: h.funcName, File: h.filePath, Directives: h.directives}
+	}
+	return cached
+}
+
+func fromCachedHandlers(cached []cachedHandler) []routeHandler {
+	if len(cached) == 0 {
+		return nil
+	}
+	handlers := make([]routeHandler, len(cached))
+	for i, h := range cached {
+		handlers[i] = routeHandler{method: h.Method, funcName: h.Func, filePath: h.File, directives: h.Directives}
+	}
+	return handlers
+}
@@ -0,0 +1,126 @@
+package nexo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanner_Cache(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	cacheDir := filepath.Join(tmpDir, ".nexo", "cache")
+	usersDir := filepath.Join(appDir, "api", "users")
+	if err := os.MkdirAll(usersDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(usersDir, "route.go"), `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+//nexo:summary List users
+func Get(c *nexo.Context) error {
+	return nil
+}
+`)
+	write(filepath.Join(appDir, "api", "middleware.go"), `package api
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Middleware() nexo.MiddlewareFunc {
+	return nil
+}
+`)
+
+	scan := func() []RouteInfo {
+		t.Helper()
+		s := NewScanner(appDir)
+		s.SetCacheDir(cacheDir)
+		routes, _, err := s.ScanRouteInfo()
+		if err != nil {
+			t.Fatalf("ScanRouteInfo failed: %v", err)
+		}
+		return routes
+	}
+
+	if routes := scan(); len(routes) != 1 || routes[0].Directives.Summary != "List users" {
+		t.Fatalf("Expected GET /api/users, got %+v", routes)
+	}
+
+	// Unchanged files are read from the cache instead of parsed
+	cachePath := filepath.Join(cacheDir, scanCacheFile)
+	cache := loadScanCache(cacheDir)
+	entry, ok := cache.Routes[usersDir]
+	if !ok {
+		t.Fatalf("Expected %s to be cached", usersDir)
+	}
+	entry.Handlers[0].Directives.Summary = "from cache"
+	cache.Routes[usersDir] = entry
+	data, _ := json.Marshal(cache)
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if routes := scan(); len(routes) != 1 || routes[0].Directives.Summary != "from cache" {
+		t.Errorf("Expected the cached route, got %+v", routes)
+	}
+
+	// Changed files are parsed again
+	write(filepath.Join(usersDir, "route_post.go"), `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Post(c *nexo.Context) error {
+	return nil
+}
+`)
+	routes := scan()
+	if len(routes) != 2 || routes[0].Directives.Summary != "List users" {
+		t.Errorf("Expected the changed route to be parsed again, got %+v", routes)
+	}
+
+	// Scan caches middleware too
+	s := NewScanner(appDir)
+	s.SetCacheDir(cacheDir)
+	tree := NewRouteTree()
+	if _, err := s.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(tree.Routes()) != 2 {
+		t.Errorf("Expected 2 routes, got %d", len(tree.Routes()))
+	}
+	if mw := loadScanCache(cacheDir).Middleware[filepath.Join(appDir, "api", "middleware.go")]; !mw.Valid {
+		t.Errorf("Expected the middleware to be cached, got %+v", mw)
+	}
+
+	// Deleted routes are dropped from the cache
+	if err := os.RemoveAll(usersDir); err != nil {
+		t.Fatal(err)
+	}
+	if routes := scan(); len(routes) != 0 {
+		t.Errorf("Expected no routes, got %+v", routes)
+	}
+	if _, ok := loadScanCache(cacheDir).Routes[usersDir]; ok {
+		t.Error("Expected the deleted route to be dropped from the cache")
+	}
+}
+
+func TestLoadScanCache_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for _, content := range []string{"not json", `{"version": 0, "routes": {"app": {"hash": "x"}}}`} {
+		if err := os.WriteFile(filepath.Join(dir, scanCacheFile), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		c := loadScanCache(dir)
+		if c.Version != scanCacheVersion || len(c.Routes) != 0 || len(c.Middleware) != 0 {
+			t.Errorf("loadScanCache(%q) = %+v, want an empty cache", content, c)
+		}
+	}
+}
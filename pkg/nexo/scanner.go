@@ -1,10 +1,12 @@
 package nexo
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,34 +17,141 @@ import (
 // Scanner scans the app directory for routes and middleware.
 type Scanner struct {
 	appDir  string
+	fsys    fs.FS
 	fset    *token.FileSet
 	verbose bool
+	cache   *scanCache
 }
 
 // NewScanner creates a new Scanner for the given app directory.
 func NewScanner(appDir string) *Scanner {
 	return &Scanner{
 		appDir:  appDir,
+		fsys:    os.DirFS(appDir),
 		fset:    token.NewFileSet(),
 		verbose: false,
 	}
 }
 
+// NewScannerFS creates a Scanner that reads the app directory from fsys, so
+// routes can be listed from a copy of app/ embedded in the binary, where
+// the source tree isn't on disk. File paths are reported relative to fsys.
+//
+// Example:
+//
+//	//go:embed app
+//	var files embed.FS
+//
+//	appFS, _ := fs.Sub(files, "app")
+//	routes, diags, err := nexo.NewScannerFS(appFS).ScanRouteInfo()
+func NewScannerFS(fsys fs.FS) *Scanner {
+	return &Scanner{
+		appDir: ".",
+		fsys:   fsys,
+		fset:   token.NewFileSet(),
+	}
+}
+
 // SetVerbose enables verbose logging during scanning.
 func (s *Scanner) SetVerbose(v bool) {
 	s.verbose = v
 }
 
+// SetCacheDir keeps what the scanner learns from route.go and middleware.go
+// files in dir, e.g. DefaultScanCacheDir, keyed by a hash of their content.
+// Later scans, in this process or the next, only parse the files that
+// changed. An empty dir disables the cache.
+func (s *Scanner) SetCacheDir(dir string) {
+	if dir == "" {
+		s.cache = nil
+		return
+	}
+	s.cache = loadScanCache(dir)
+}
+
+// saveCache writes the cache after a scan that walked routes and/or
+// middleware. Failed scans leave it as it was, and the cache only speeds
+// scans up, so failing to write it isn't an error.
+func (s *Scanner) saveCache(routes, middleware bool, scanErr error) {
+	if s.cache == nil {
+		return
+	}
+	if scanErr != nil {
+		s.cache.seen = make(map[string]bool)
+		return
+	}
+	if err := s.cache.save(routes, middleware); err != nil && s.verbose {
+		fmt.Printf("  Warning: failed to write the scanner cache: %v\n", err)
+	}
+}
+
+// walk calls fn for each file in the app directory, skipping hidden files
+// and directories and private folders. Paths are joined to the app
+// directory, as the scanner reports them. A missing app directory has no
+// files.
+func (s *Scanner) walk(fn func(path, name string) error) error {
+	return fs.WalkDir(s.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if p == "." {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Not an error if app dir doesn't exist - just no routes
+				return fs.SkipAll
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		// Skip hidden files and directories
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if isPrivateFolder(d.Name(), p) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		return fn(filepath.Join(s.appDir, filepath.FromSlash(p)), d.Name())
+	})
+}
+
+// fsPath returns the path in the scanner's file system of a path the
+// scanner reports.
+func (s *Scanner) fsPath(path string) string {
+	rel, err := filepath.Rel(s.appDir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// readFile reads a file in the app directory.
+func (s *Scanner) readFile(path string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, s.fsPath(path))
+}
+
+// readDir reads a directory in the app directory.
+func (s *Scanner) readDir(dir string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(s.fsys, s.fsPath(dir))
+}
+
 // Regular expressions for matching route segment patterns
 // Using Next.js-style bracket convention:
 //   - [param]       -> dynamic segment
+//   - [param:int]   -> dynamic segment with a constraint (see RouteParam)
 //   - [...param]    -> catch-all segment
 //   - [[...param]]  -> optional catch-all segment
 //   - (group)       -> route group (doesn't affect URL)
 var (
-	// [param] - dynamic segment
-	// Matches: [id], [userId], [post_id]
-	dynamicSegmentRe = regexp.MustCompile(`^\[([a-zA-Z_][a-zA-Z0-9_]*)\]$`)
+	// [param] - dynamic segment, with an optional constraint
+	// Matches: [id], [userId], [post_id], [id:int], [code:regexp([A-Z]{3})]
+	dynamicSegmentRe = regexp.MustCompile(`^\[([a-zA-Z_][a-zA-Z0-9_]*)(?::(.+))?\]$`)
 
 	// [...param] - catch-all segment
 	// Matches: [...slug], [...path], [...segments]
@@ -97,168 +206,530 @@ var httpMethods = map[string]string{
 	"Options": http.MethodOptions,
 }
 
-// Scan walks the app directory and registers routes with the RouteTree.
-func (s *Scanner) Scan(tree *RouteTree) error {
-	// Check if app directory exists
-	if _, err := os.Stat(s.appDir); os.IsNotExist(err) {
-		// Not an error if app dir doesn't exist - just no routes
-		return nil
+// anyHandler is the route.go function that handles every HTTP method
+// without a function of its own.
+const anyHandler = "Any"
+
+// connFile is a file whose one function serves long-lived connections
+// over GET at its directory's path, like ws.go and sse.go.
+type connFile struct {
+	funcName  string // Function serving the connections, e.g. "Socket"
+	connType  string // nexo type of its second parameter, e.g. "WebSocketConn"
+	kind      string // What to call the function in diagnostics, e.g. "socket"
+	signature string // Signature it must have, for diagnostics
+}
+
+// connFiles are the connection files by name.
+var connFiles = map[string]connFile{
+	"ws.go": {
+		funcName:  "Socket",
+		connType:  "WebSocketConn",
+		kind:      "socket",
+		signature: "func(c *nexo.Context, ws *nexo.WebSocketConn) error",
+	},
+	"sse.go": {
+		funcName:  "Stream",
+		connType:  "SSEWriter",
+		kind:      "stream",
+		signature: "func(c *nexo.Context, sse *nexo.SSEWriter) error",
+	},
+}
+
+// routeMethodFileRe matches the files a route.go can be split into, one
+// per method: route_get.go, route_post.go, ...
+var routeMethodFileRe = regexp.MustCompile(`^route_(get|post|put|patch|delete|head|options|any)\.go$`)
+
+// IsRouteFile reports whether name is a route.go, or one of the
+// route_<method>.go files a large route.go can be split into, e.g.
+// route_get.go and route_post.go. The files in a directory are merged into
+// one route.
+func IsRouteFile(name string) bool {
+	return name == "route.go" || routeMethodFileRe.MatchString(name)
+}
+
+// scanRouteDir returns the handlers declared by the route files in dir,
+// which together make up one route, and diagnostics for the functions it
+// skipped. With a cache, files that haven't changed since the last scan
+// aren't parsed again.
+func (s *Scanner) scanRouteDir(dir string) (handlers []routeHandler, diags []Diagnostic, err error) {
+	entries, err := s.readDir(dir)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return filepath.Walk(s.appDir, func(path string, info os.FileInfo, err error) error {
+	var paths []string
+	var sources [][]byte
+	for _, entry := range entries {
+		if entry.IsDir() || !IsRouteFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		src, err := s.readFile(path)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
+		paths = append(paths, path)
+		sources = append(sources, src)
+	}
 
-		// Skip hidden files and directories
-		if strings.HasPrefix(info.Name(), ".") {
-			if info.IsDir() {
-				return filepath.SkipDir
+	var hash string
+	if s.cache != nil {
+		hash = hashSources(paths, sources)
+		if handlers, diags, ok := s.cache.route(dir, hash); ok {
+			return handlers, diags, nil
+		}
+	}
+
+	files := make([]*ast.File, len(paths))
+	for i, path := range paths {
+		files[i], err = parser.ParseFile(s.fset, path, sources[i], parser.ParseComments)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	handlers = s.routeHandlers(files, func(d Diagnostic) {
+		diags = append(diags, d)
+	})
+
+	if s.cache != nil {
+		s.cache.setRoute(dir, hash, handlers, diags)
+	}
+	return handlers, diags, nil
+}
+
+// routeHandler pairs an HTTP method with the function that handles it, the
+// file declaring the function and its directives.
+type routeHandler struct {
+	method     string
+	funcName   string
+	filePath   string
+	directives RouteDirectives
+}
+
+// routeHandlers returns the handlers declared in a route's files, in
+// declaration order, followed by the methods left to Any. Functions with an
+// invalid signature or a misspelled name, e.g. GET, are reported to report
+// and skipped.
+func (s *Scanner) routeHandlers(files []*ast.File, report func(Diagnostic)) []routeHandler {
+	var handlers []routeHandler
+	handled := make(map[string]bool)
+	var anyFunc *routeHandler
+
+	for _, file := range files {
+		filePath := s.fset.Position(file.Package).Filename
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
 			}
-			return nil
+
+			method, ok := httpMethods[fn.Name.Name]
+			if !ok && fn.Name.Name != anyHandler {
+				if name := handlerNameFold(fn.Name.Name); name != "" && fn.Name.IsExported() && report != nil {
+					report(s.diagnostic(fn, RuleMisnamed, fmt.Sprintf("not a handler name, did you mean %s?", name)))
+				}
+				continue
+			}
+
+			// Validate the function signature: func(c *nexo.Context) error
+			if !s.isValidHandlerSignature(fn) {
+				if report != nil {
+					report(s.diagnostic(fn, RuleInvalidSignature, "invalid signature, handlers must be func(c *nexo.Context) error"))
+				}
+				continue
+			}
+
+			h := routeHandler{method: method, funcName: fn.Name.Name, filePath: filePath, directives: parseDirectives(fn.Doc)}
+			if !ok {
+				anyFunc = &h
+				continue
+			}
+			handled[method] = true
+			handlers = append(handlers, h)
 		}
+	}
 
-		// Skip private folders
-		if info.IsDir() && isPrivateFolder(info.Name(), path) {
-			return filepath.SkipDir
+	if anyFunc != nil {
+		for _, method := range methodOrder {
+			if !handled[method] {
+				h := *anyFunc
+				h.method = method
+				handlers = append(handlers, h)
+			}
 		}
+	}
 
-		// Skip non-routing files
-		if info.IsDir() {
-			return nil
+	return handlers
+}
+
+// Scan walks the app directory and registers routes with the RouteTree.
+// It returns diagnostics for the handlers, sockets, streams, actions and
+// middleware it skipped because of an invalid signature or a misspelled
+// name, such as GET instead of Get.
+func (s *Scanner) Scan(tree *RouteTree) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	report := func(d []Diagnostic, err error) error {
+		diags = append(diags, d...)
+		return err
+	}
+
+	routeDirs := make(map[string]bool)
+	err := s.walk(func(path, name string) error {
+		// Register each route once, however many files it's split into
+		if IsRouteFile(name) {
+			dir := filepath.Dir(path)
+			if routeDirs[dir] {
+				return nil
+			}
+			routeDirs[dir] = true
+			return report(s.registerAPIRoute(tree, dir))
 		}
 
 		// Process routing files
-		switch info.Name() {
-		case "route.go":
-			return s.registerAPIRoute(tree, path)
+		switch name {
 		case "middleware.go":
-			return s.registerMiddleware(tree, path)
+			return report(s.registerMiddleware(tree, path))
+		case "ws.go", "sse.go":
+			return report(s.registerConnFile(tree, path, connFiles[name]))
+		case "action.go":
+			return report(s.registerActionFile(tree, path))
 			// Future: page.templ, layout.templ, etc.
 		}
 
 		return nil
 	})
+	s.saveCache(true, true, err)
+	return diags, err
 }
 
-// registerAPIRoute discovers and registers handlers from a route.go file.
-func (s *Scanner) registerAPIRoute(tree *RouteTree, filePath string) error {
-	// Parse the Go file
-	file, err := parser.ParseFile(s.fset, filePath, nil, parser.ParseComments)
+// registerAPIRoute discovers and registers handlers from the route files in
+// dir: route.go and the route_<method>.go files it may be split into.
+func (s *Scanner) registerAPIRoute(tree *RouteTree, dir string) ([]Diagnostic, error) {
+	handlers, diags, err := s.scanRouteDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+		return nil, err
 	}
+	filePath := filepath.Join(dir, "route.go")
 
 	// Get the URL route pattern from the file path
-	pattern := s.pathToRoute(filePath)
+	pattern, err := s.pathToRoute(filePath)
+	if err != nil {
+		return nil, err
+	}
+	diags = withPattern(diags, pattern)
 
 	// Get the filesystem scope (preserves route groups for middleware matching)
 	scope := s.pathToScope(filePath)
 
-	// Find all exported functions that match HTTP method names
-	for _, decl := range file.Decls {
-		fn, ok := decl.(*ast.FuncDecl)
-		if !ok {
-			continue
-		}
-
-		// Check if it's an exported function
-		if !fn.Name.IsExported() {
-			continue
-		}
-
-		// Check if the function name matches an HTTP method
-		method, ok := httpMethods[fn.Name.Name]
-		if !ok {
-			continue
-		}
-
-		// Validate the function signature: func(c *nexo.Context) error
-		if !s.isValidHandlerSignature(fn) {
-			if s.verbose {
-				fmt.Printf("  Warning: %s.%s has invalid signature, skipping\n", filePath, fn.Name.Name)
-			}
-			continue
+	if s.verbose {
+		for _, d := range diags {
+			fmt.Printf("  Warning: %s, skipping\n", d)
 		}
+	}
 
+	// Register a route for each handler function, including those left to Any
+	for _, h := range handlers {
 		// Create a handler that will be replaced at runtime
 		// For now, we register a placeholder that the plugin system will replace
 		route := &Route{
 			Pattern:  pattern,
-			Method:   method,
-			FilePath: filePath,
+			Method:   h.method,
+			FilePath: h.filePath,
 			Scope:    scope,
 			Priority: CalculatePriority(pattern),
-			Handler:  s.createPlaceholderHandler(filePath, fn.Name.Name),
+			Handler:  s.createPlaceholderHandler(h.filePath, h.funcName),
 		}
 
 		tree.AddRoute(route)
 
 		if s.verbose {
-			fmt.Printf("  Registered: %s %s (scope: %s, file: %s)\n", method, pattern, scope, filePath)
+			fmt.Printf("  Registered: %s %s (scope: %s, file: %s)\n", h.method, pattern, scope, h.filePath)
 		}
 	}
 
-	return nil
+	return diags, nil
 }
 
-// registerMiddleware discovers and registers middleware from a middleware.go file.
-func (s *Scanner) registerMiddleware(tree *RouteTree, filePath string) error {
-	// Parse the Go file
-	file, err := parser.ParseFile(s.fset, filePath, nil, parser.ParseComments)
+// registerConnFile registers a GET route for the function of a ws.go or
+// sse.go file, which upgrades requests to WebSocket or streams events.
+func (s *Scanner) registerConnFile(tree *RouteTree, filePath string, cf connFile) ([]Diagnostic, error) {
+	handler, diags, err := s.scanConnFile(filePath, cf)
 	if err != nil {
-		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+		return nil, err
 	}
 
-	// Get the URL path prefix (without route groups)
-	pathPrefix := s.pathToRoute(filePath)
-	if pathPrefix == "/" {
-		pathPrefix = ""
+	pattern, err := s.pathToRoute(filePath)
+	if err != nil {
+		return nil, err
 	}
+	diags = withPattern(diags, pattern)
 
-	// Get the filesystem scope (preserves route groups for middleware matching)
+	if s.verbose {
+		for _, d := range diags {
+			fmt.Printf("  Warning: %s, skipping\n", d)
+		}
+	}
+	if handler == nil {
+		return diags, nil
+	}
 	scope := s.pathToScope(filePath)
 
-	// Look for the Middleware function
+	tree.AddRoute(&Route{
+		Pattern:  pattern,
+		Method:   http.MethodGet,
+		FilePath: filePath,
+		Scope:    scope,
+		Priority: CalculatePriority(pattern),
+		Handler:  s.createPlaceholderHandler(filePath, cf.funcName),
+	})
+
+	if s.verbose {
+		fmt.Printf("  Registered %s: %s (scope: %s, file: %s)\n", cf.kind, pattern, scope, filePath)
+	}
+
+	return diags, nil
+}
+
+// scanConnFile returns the function of a ws.go or sse.go file, or nil if
+// it has none with a valid signature, and diagnostics for the functions it
+// skipped.
+func (s *Scanner) scanConnFile(filePath string, cf connFile) (*routeHandler, []Diagnostic, error) {
+	src, err := s.readFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := parser.ParseFile(s.fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	handler, diags := s.connHandler(file, filePath, cf)
+	return handler, diags, nil
+}
+
+// connHandler returns the function of a parsed ws.go or sse.go, like
+// scanConnFile.
+func (s *Scanner) connHandler(file *ast.File, filePath string, cf connFile) (*routeHandler, []Diagnostic) {
+	var handler *routeHandler
+	var diags []Diagnostic
 	for _, decl := range file.Decls {
 		fn, ok := decl.(*ast.FuncDecl)
-		if !ok {
+		if !ok || fn.Recv != nil {
 			continue
 		}
+		switch {
+		case fn.Name.Name != cf.funcName:
+			if strings.EqualFold(fn.Name.Name, cf.funcName) && fn.Name.IsExported() {
+				diags = append(diags, s.diagnostic(fn, RuleMisnamed, fmt.Sprintf("not a %s name, did you mean %s?", cf.kind, cf.funcName)))
+			}
+		case s.isValidConnSignature(fn, cf.connType):
+			handler = &routeHandler{
+				method:     http.MethodGet,
+				funcName:   cf.funcName,
+				filePath:   filePath,
+				directives: parseDirectives(fn.Doc),
+			}
+		default:
+			diags = append(diags, s.diagnostic(fn, RuleInvalidSignature, fmt.Sprintf("invalid signature, %ss must be %s", cf.kind, cf.signature)))
+		}
+	}
+	return handler, diags
+}
 
-		// Look for Middleware function
-		if fn.Name.Name != "Middleware" {
-			continue
+// actionFunc is the prefix of the form action functions of an action.go:
+// Action, and e.g. ActionDelete.
+const actionFunc = "Action"
+
+// registerActionFile registers the POST route for the form actions of an
+// action.go file.
+func (s *Scanner) registerActionFile(tree *RouteTree, filePath string) ([]Diagnostic, error) {
+	handlers, diags, err := s.scanActionFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := s.pathToRoute(filePath)
+	if err != nil {
+		return nil, err
+	}
+	diags = withPattern(diags, pattern)
+
+	if s.verbose {
+		for _, d := range diags {
+			fmt.Printf("  Warning: %s, skipping\n", d)
 		}
+	}
+	if len(handlers) == 0 {
+		return diags, nil
+	}
+	scope := s.pathToScope(filePath)
 
-		// Validate signature
-		if !s.isValidMiddlewareSignature(fn) {
-			if s.verbose {
-				fmt.Printf("  Warning: %s.Middleware has invalid signature, skipping\n", filePath)
-			}
+	tree.AddRoute(&Route{
+		Pattern:  pattern,
+		Method:   http.MethodPost,
+		FilePath: filePath,
+		Scope:    scope,
+		Priority: CalculatePriority(pattern),
+		Handler:  s.createPlaceholderHandler(filePath, handlers[0].funcName),
+	})
+
+	if s.verbose {
+		fmt.Printf("  Registered actions: %s (scope: %s, file: %s)\n", pattern, scope, filePath)
+	}
+
+	return diags, nil
+}
+
+// scanActionFile returns the form actions of an action.go file, the
+// Action function first, and diagnostics for the functions it skipped.
+func (s *Scanner) scanActionFile(filePath string) ([]routeHandler, []Diagnostic, error) {
+	src, err := s.readFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := parser.ParseFile(s.fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	handlers, diags := s.actionHandlers(file, filePath)
+	return handlers, diags, nil
+}
+
+// actionHandlers returns the form actions of a parsed action.go, like
+// scanActionFile.
+func (s *Scanner) actionHandlers(file *ast.File, filePath string) ([]routeHandler, []Diagnostic) {
+	var handlers []routeHandler
+	var diags []Diagnostic
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() {
 			continue
 		}
+		switch {
+		case !strings.HasPrefix(fn.Name.Name, actionFunc):
+			if len(fn.Name.Name) >= len(actionFunc) && strings.EqualFold(fn.Name.Name[:len(actionFunc)], actionFunc) {
+				diags = append(diags, s.diagnostic(fn, RuleMisnamed, "not an action name, did you mean "+actionFunc+fn.Name.Name[len(actionFunc):]+"?"))
+			}
+		case s.isValidHandlerSignature(fn):
+			h := routeHandler{
+				method:     http.MethodPost,
+				funcName:   fn.Name.Name,
+				filePath:   filePath,
+				directives: parseDirectives(fn.Doc),
+			}
+			if fn.Name.Name == actionFunc {
+				handlers = append([]routeHandler{h}, handlers...)
+			} else {
+				handlers = append(handlers, h)
+			}
+		default:
+			diags = append(diags, s.diagnostic(fn, RuleInvalidSignature, "invalid signature, actions must be func(c *nexo.Context) error"))
+		}
+	}
+	return handlers, diags
+}
+
+// registerMiddleware discovers and registers middleware from a middleware.go file.
+func (s *Scanner) registerMiddleware(tree *RouteTree, filePath string) ([]Diagnostic, error) {
+	mw, err := s.scanMiddleware(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the URL path prefix (without route groups)
+	pathPrefix, err := s.pathToRoute(filePath)
+	if err != nil {
+		return nil, err
+	}
+	diags := withPattern(mw.Diagnostics, pathPrefix)
+	if pathPrefix == "/" {
+		pathPrefix = ""
+	}
+
+	// Get the filesystem scope (preserves route groups for middleware matching)
+	scope := s.pathToScope(filePath)
+
+	if s.verbose {
+		for _, d := range diags {
+			fmt.Printf("  Warning: %s, skipping\n", d)
+		}
+	}
+	if !mw.Valid {
+		return diags, nil
+	}
 
-		// Register middleware with scope for proper route group isolation
-		tree.AddMiddleware(pathPrefix, scope, s.createPlaceholderMiddleware(filePath))
+	// Register middleware with scope for proper route group isolation
+	tree.AddMiddleware(pathPrefix, scope, s.createPlaceholderMiddleware(filePath))
 
-		if s.verbose {
-			fmt.Printf("  Registered middleware: %s (scope: %s, file: %s)\n", pathPrefix, scope, filePath)
+	if s.verbose {
+		fmt.Printf("  Registered middleware: %s (scope: %s, file: %s)\n", pathPrefix, scope, filePath)
+	}
+
+	return diags, nil
+}
+
+// scanMiddleware reports whether a middleware.go declares a Middleware
+// function with a valid signature, with diagnostics for the functions it
+// skipped. With a cache, the file isn't parsed again until it changes.
+func (s *Scanner) scanMiddleware(filePath string) (middlewareFile, error) {
+	src, err := s.readFile(filePath)
+	if err != nil {
+		return middlewareFile{}, err
+	}
+
+	var hash string
+	if s.cache != nil {
+		hash = hashSources([]string{filePath}, [][]byte{src})
+		if mw, ok := s.cache.middleware(filePath, hash); ok {
+			return mw, nil
 		}
 	}
 
-	return nil
+	file, err := parser.ParseFile(s.fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return middlewareFile{}, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	mw := s.middlewareDecls(file)
+	mw.Hash = hash
+	if s.cache != nil {
+		s.cache.setMiddleware(filePath, mw)
+	}
+	return mw, nil
+}
+
+// middlewareDecls looks for the Middleware function of a parsed
+// middleware.go, like scanMiddleware.
+func (s *Scanner) middlewareDecls(file *ast.File) middlewareFile {
+	var mw middlewareFile
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		switch {
+		case fn.Name.Name != "Middleware":
+			if strings.EqualFold(fn.Name.Name, "Middleware") && fn.Name.IsExported() && fn.Recv == nil {
+				mw.Diagnostics = append(mw.Diagnostics, s.diagnostic(fn, RuleMisnamed, "not a middleware name, did you mean Middleware?"))
+			}
+		case s.isValidMiddlewareSignature(fn):
+			mw.Valid = true
+		default:
+			mw.Diagnostics = append(mw.Diagnostics, s.diagnostic(fn, RuleInvalidSignature, "invalid signature, middleware must be func() nexo.MiddlewareFunc"))
+		}
+	}
+	return mw
 }
 
 // pathToRoute converts a file path to a route pattern.
 // Example: app/users/[id]/route.go -> /users/{id}
+// Example: app/users/[id:int]/route.go -> /users/{id:-?[0-9]+}
 // Example: app/docs/[...slug]/route.go -> /docs/*
 // Example: app/(admin)/settings/route.go -> /settings
-func (s *Scanner) pathToRoute(filePath string) string {
+func (s *Scanner) pathToRoute(filePath string) (string, error) {
 	// Get path relative to app directory
 	rel, err := filepath.Rel(s.appDir, filepath.Dir(filePath))
 	if err != nil || rel == "." {
-		return "/"
+		return "/", nil
 	}
 
 	segments := strings.Split(rel, string(filepath.Separator))
@@ -282,9 +753,13 @@ func (s *Scanner) pathToRoute(filePath string) string {
 			continue
 		}
 
-		// Handle dynamic segment [param]
+		// Handle dynamic segment [param] or [param:constraint]
 		if matches := dynamicSegmentRe.FindStringSubmatch(seg); len(matches) > 1 {
-			routeSegments = append(routeSegments, "{"+matches[1]+"}")
+			param, err := RouteParam(matches[1], matches[2])
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", filePath, err)
+			}
+			routeSegments = append(routeSegments, param)
 			continue
 		}
 
@@ -292,10 +767,10 @@ func (s *Scanner) pathToRoute(filePath string) string {
 	}
 
 	if len(routeSegments) == 0 {
-		return "/"
+		return "/", nil
 	}
 
-	return "/" + strings.Join(routeSegments, "/")
+	return "/" + strings.Join(routeSegments, "/"), nil
 }
 
 // pathToScope converts a file path to a middleware scope.
@@ -362,6 +837,46 @@ checkReturn:
 	return false
 }
 
+// isValidConnSignature checks if a function has the signature:
+// func(c *nexo.Context, conn *nexo.<connType>) error
+func (s *Scanner) isValidConnSignature(fn *ast.FuncDecl, connType string) bool {
+	// Flatten the parameters, e.g. (c *nexo.Context, ws *nexo.WebSocketConn)
+	var params []ast.Expr
+	for _, field := range fn.Type.Params.List {
+		for range max(len(field.Names), 1) {
+			params = append(params, field.Type)
+		}
+	}
+	if len(params) != 2 || !isNexoPointer(params[0], "Context") || !isNexoPointer(params[1], connType) {
+		return false
+	}
+
+	// Must return only an error
+	results := fn.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	ident, ok := results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// isNexoPointer reports whether expr is *nexo.<name>, or *<name> within
+// the nexo package.
+func isNexoPointer(expr ast.Expr, name string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	switch x := star.X.(type) {
+	case *ast.SelectorExpr:
+		ident, ok := x.X.(*ast.Ident)
+		return ok && ident.Name == "nexo" && x.Sel.Name == name
+	case *ast.Ident:
+		return x.Name == name
+	}
+	return false
+}
+
 // isValidMiddlewareSignature checks if a function has the signature:
 // func() nexo.MiddlewareFunc
 func (s *Scanner) isValidMiddlewareSignature(fn *ast.FuncDecl) bool {
@@ -419,6 +934,22 @@ type RouteInfo struct {
 	Pattern  string
 	FilePath string
 	Priority int
+
+	// Directives are the //nexo: directives of the handler, set by
+	// ScanRouteInfo
+	Directives RouteDirectives
+
+	// WebSocket is set for the GET routes of ws.go files, which upgrade
+	// requests to WebSocket
+	WebSocket bool
+
+	// SSE is set for the GET routes of sse.go files, which stream
+	// Server-Sent Events
+	SSE bool
+
+	// Action is set for the POST routes of action.go files, which run the
+	// form actions of the page at the same path
+	Action bool
 }
 
 // MiddlewareInfo holds information about discovered middleware (for CLI display).
@@ -440,124 +971,168 @@ type LayoutInfo struct {
 	FilePath   string // File path (e.g., "app/dashboard/layout.templ")
 }
 
-// ScanRouteInfo scans and returns route info without registering handlers.
-func (s *Scanner) ScanRouteInfo() ([]RouteInfo, error) {
+// ScanRouteInfo scans and returns route info without registering handlers,
+// with diagnostics for what Scan would skip: handlers, sockets, streams,
+// actions and middleware with an invalid signature or a misspelled name,
+// and routing files that don't parse.
+func (s *Scanner) ScanRouteInfo() ([]RouteInfo, []Diagnostic, error) {
 	var routes []RouteInfo
+	var diags []Diagnostic
 
-	if _, err := os.Stat(s.appDir); os.IsNotExist(err) {
-		return routes, nil
-	}
-
-	err := filepath.Walk(s.appDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	routeDirs := make(map[string]bool)
+	err := s.walk(func(path, name string) error {
+		if cf, ok := connFiles[name]; ok {
+			return s.appendConnInfo(&routes, &diags, path, cf)
 		}
-
-		if strings.HasPrefix(info.Name(), ".") {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
+		switch name {
+		case "action.go":
+			return s.appendActionInfo(&routes, &diags, path)
+		case "middleware.go":
+			return s.appendMiddlewareDiagnostics(&diags, path)
 		}
-
-		if info.IsDir() && isPrivateFolder(info.Name(), path) {
-			return filepath.SkipDir
+		if !IsRouteFile(name) {
+			return nil
 		}
-
-		if info.IsDir() || info.Name() != "route.go" {
+		dir := filepath.Dir(path)
+		if routeDirs[dir] {
 			return nil
 		}
+		routeDirs[dir] = true
 
-		file, err := parser.ParseFile(s.fset, path, nil, parser.ParseComments)
+		handlers, routeDiags, err := s.scanRouteDir(dir)
 		if err != nil {
+			diags = append(diags, errorDiagnostic(path, err))
 			return nil // Skip files that can't be parsed
 		}
 
-		pattern := s.pathToRoute(path)
-
-		for _, decl := range file.Decls {
-			fn, ok := decl.(*ast.FuncDecl)
-			if !ok || !fn.Name.IsExported() {
-				continue
-			}
-
-			method, ok := httpMethods[fn.Name.Name]
-			if !ok {
-				continue
-			}
+		pattern, err := s.pathToRoute(path)
+		if err != nil {
+			return err
+		}
+		diags = append(diags, withPattern(routeDiags, pattern)...)
 
-			if s.isValidHandlerSignature(fn) {
-				routes = append(routes, RouteInfo{
-					Method:   method,
-					Pattern:  pattern,
-					FilePath: path,
-					Priority: CalculatePriority(pattern),
-				})
-			}
+		for _, h := range handlers {
+			routes = append(routes, RouteInfo{
+				Method:     h.method,
+				Pattern:    pattern,
+				FilePath:   h.filePath,
+				Priority:   CalculatePriority(pattern),
+				Directives: h.directives,
+			})
 		}
 
 		return nil
 	})
 
-	return routes, err
+	s.saveCache(true, true, err)
+	return routes, diags, err
 }
 
-// ScanMiddlewareInfo scans and returns middleware info without registering handlers.
-func (s *Scanner) ScanMiddlewareInfo() ([]MiddlewareInfo, error) {
-	var middlewares []MiddlewareInfo
+// appendConnInfo appends the route of a ws.go or sse.go to routes, and what
+// it skipped to diags.
+func (s *Scanner) appendConnInfo(routes *[]RouteInfo, diags *[]Diagnostic, filePath string, cf connFile) error {
+	handler, connDiags, err := s.scanConnFile(filePath, cf)
+	if err != nil {
+		*diags = append(*diags, errorDiagnostic(filePath, err))
+		return nil // Skip files that can't be parsed
+	}
 
-	if _, err := os.Stat(s.appDir); os.IsNotExist(err) {
-		return middlewares, nil
+	pattern, err := s.pathToRoute(filePath)
+	if err != nil {
+		return err
+	}
+	*diags = append(*diags, withPattern(connDiags, pattern)...)
+	if handler == nil {
+		return nil
 	}
 
-	err := filepath.Walk(s.appDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	*routes = append(*routes, RouteInfo{
+		Method:     handler.method,
+		Pattern:    pattern,
+		FilePath:   handler.filePath,
+		Priority:   CalculatePriority(pattern),
+		Directives: handler.directives,
+		WebSocket:  cf.connType == "WebSocketConn",
+		SSE:        cf.connType == "SSEWriter",
+	})
+	return nil
+}
 
-		if strings.HasPrefix(info.Name(), ".") {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+// appendActionInfo appends the route of an action.go to routes, and what it
+// skipped to diags.
+func (s *Scanner) appendActionInfo(routes *[]RouteInfo, diags *[]Diagnostic, filePath string) error {
+	handlers, actionDiags, err := s.scanActionFile(filePath)
+	if err != nil {
+		*diags = append(*diags, errorDiagnostic(filePath, err))
+		return nil // Skip files that can't be parsed
+	}
 
-		if info.IsDir() && isPrivateFolder(info.Name(), path) {
-			return filepath.SkipDir
-		}
+	pattern, err := s.pathToRoute(filePath)
+	if err != nil {
+		return err
+	}
+	*diags = append(*diags, withPattern(actionDiags, pattern)...)
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	*routes = append(*routes, RouteInfo{
+		Method:     http.MethodPost,
+		Pattern:    pattern,
+		FilePath:   filePath,
+		Priority:   CalculatePriority(pattern),
+		Directives: handlers[0].directives,
+		Action:     true,
+	})
+	return nil
+}
+
+// appendMiddlewareDiagnostics appends what a middleware.go skipped to diags.
+func (s *Scanner) appendMiddlewareDiagnostics(diags *[]Diagnostic, filePath string) error {
+	mw, err := s.scanMiddleware(filePath)
+	if err != nil {
+		*diags = append(*diags, errorDiagnostic(filePath, err))
+		return nil // Skip files that can't be parsed
+	}
+
+	pathPrefix, err := s.pathToRoute(filePath)
+	if err != nil {
+		return err
+	}
+	*diags = append(*diags, withPattern(mw.Diagnostics, pathPrefix)...)
+	return nil
+}
 
-		if info.IsDir() || info.Name() != "middleware.go" {
+// ScanMiddlewareInfo scans and returns middleware info without registering handlers.
+func (s *Scanner) ScanMiddlewareInfo() ([]MiddlewareInfo, error) {
+	var middlewares []MiddlewareInfo
+
+	err := s.walk(func(path, name string) error {
+		if name != "middleware.go" {
 			return nil
 		}
 
-		file, err := parser.ParseFile(s.fset, path, nil, parser.ParseComments)
+		mw, err := s.scanMiddleware(path)
 		if err != nil {
 			return nil // Skip files that can't be parsed
 		}
 
-		pathPrefix := s.pathToRoute(path)
-
-		for _, decl := range file.Decls {
-			fn, ok := decl.(*ast.FuncDecl)
-			if !ok {
-				continue
-			}
-
-			if fn.Name.Name != "Middleware" {
-				continue
-			}
+		pathPrefix, err := s.pathToRoute(path)
+		if err != nil {
+			return err
+		}
 
-			if s.isValidMiddlewareSignature(fn) {
-				middlewares = append(middlewares, MiddlewareInfo{
-					Path:     pathPrefix,
-					FilePath: path,
-				})
-			}
+		if mw.Valid {
+			middlewares = append(middlewares, MiddlewareInfo{
+				Path:     pathPrefix,
+				FilePath: path,
+			})
 		}
 
 		return nil
 	})
 
+	s.saveCache(false, true, err)
 	return middlewares, err
 }
 
@@ -566,12 +1141,16 @@ func (s *Scanner) ScanProxyInfo() (*ProxyInfo, error) {
 	proxyPath := filepath.Join(s.appDir, "proxy.go")
 
 	// Check if proxy.go exists
-	if _, err := os.Stat(proxyPath); os.IsNotExist(err) {
+	src, err := s.readFile(proxyPath)
+	if errors.Is(err, fs.ErrNotExist) {
 		return &ProxyInfo{HasProxy: false}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse the file
-	file, err := parser.ParseFile(s.fset, proxyPath, nil, parser.ParseComments)
+	file, err := parser.ParseFile(s.fset, proxyPath, src, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse %s: %w", proxyPath, err)
 	}
@@ -735,32 +1314,16 @@ func (s *Scanner) extractMatchersFromSpec(vs *ast.ValueSpec) []string {
 func (s *Scanner) ScanPageInfo() ([]PageInfo, error) {
 	var pages []PageInfo
 
-	if _, err := os.Stat(s.appDir); os.IsNotExist(err) {
-		return pages, nil
-	}
-
-	err := filepath.Walk(s.appDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if strings.HasPrefix(info.Name(), ".") {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if info.IsDir() && isPrivateFolder(info.Name(), path) {
-			return filepath.SkipDir
-		}
-
-		if info.IsDir() || info.Name() != "page.templ" {
+	err := s.walk(func(path, name string) error {
+		if name != "page.templ" {
 			return nil
 		}
 
 		// Get route pattern from file path
-		pattern := s.pathToPageRoute(path)
+		pattern, err := s.pathToPageRoute(path)
+		if err != nil {
+			return err
+		}
 
 		// Derive title from the directory name
 		title := s.derivePageTitle(path)
@@ -788,27 +1351,8 @@ func (s *Scanner) ScanPageInfo() ([]PageInfo, error) {
 func (s *Scanner) ScanLayoutInfo() ([]LayoutInfo, error) {
 	var layouts []LayoutInfo
 
-	if _, err := os.Stat(s.appDir); os.IsNotExist(err) {
-		return layouts, nil
-	}
-
-	err := filepath.Walk(s.appDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if strings.HasPrefix(info.Name(), ".") {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if info.IsDir() && isPrivateFolder(info.Name(), path) {
-			return filepath.SkipDir
-		}
-
-		if info.IsDir() || info.Name() != "layout.templ" {
+	err := s.walk(func(path, name string) error {
+		if name != "layout.templ" {
 			return nil
 		}
 
@@ -837,11 +1381,11 @@ func (s *Scanner) ScanLayoutInfo() ([]LayoutInfo, error) {
 // Example: app/about/page.templ -> /about
 // Example: app/page.templ -> /
 // Example: app/users/[id]/page.templ -> /users/{id}
-func (s *Scanner) pathToPageRoute(filePath string) string {
+func (s *Scanner) pathToPageRoute(filePath string) (string, error) {
 	// Get path relative to app directory
 	rel, err := filepath.Rel(s.appDir, filepath.Dir(filePath))
 	if err != nil || rel == "." {
-		return "/"
+		return "/", nil
 	}
 
 	segments := strings.Split(rel, string(filepath.Separator))
@@ -881,7 +1425,11 @@ func (s *Scanner) pathToPageRoute(filePath string) string {
 				}
 			}
 			if !isPrivate {
-				routeSegments = append(routeSegments, "{"+matches[1]+"}")
+				param, err := RouteParam(matches[1], matches[2])
+				if err != nil {
+					return "", fmt.Errorf("%s: %w", filePath, err)
+				}
+				routeSegments = append(routeSegments, param)
 				continue
 			}
 		}
@@ -890,10 +1438,10 @@ func (s *Scanner) pathToPageRoute(filePath string) string {
 	}
 
 	if len(routeSegments) == 0 {
-		return "/"
+		return "/", nil
 	}
 
-	return "/" + strings.Join(routeSegments, "/")
+	return "/" + strings.Join(routeSegments, "/"), nil
 }
 
 // pathToLayoutPrefix converts a layout.templ file path to a path prefix.
@@ -984,7 +1532,7 @@ var templPageSignatureRe = regexp.MustCompile(`templ\s+Page\s*\(`)
 // hasValidPageFunction checks if a page.templ file has a valid Page() function.
 // A valid page must export a templ Page() component (with or without parameters).
 func (s *Scanner) hasValidPageFunction(filePath string) bool {
-	content, err := os.ReadFile(filePath)
+	content, err := s.readFile(filePath)
 	if err != nil {
 		return false
 	}
@@ -998,7 +1546,7 @@ func (s *Scanner) hasValidPageFunction(filePath string) bool {
 // hasValidLayoutFunction checks if a layout.templ file has a valid Layout() function.
 // A valid layout must export a templ Layout(title string) component with { children... }.
 func (s *Scanner) hasValidLayoutFunction(filePath string) bool {
-	content, err := os.ReadFile(filePath)
+	content, err := s.readFile(filePath)
 	if err != nil {
 		return false
 	}
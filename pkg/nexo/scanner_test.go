@@ -1,9 +1,13 @@
 package nexo
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestScanner_PathToRoute(t *testing.T) {
@@ -85,12 +89,27 @@ func TestScanner_PathToRoute(t *testing.T) {
 			filePath: "app/api/health/route.go",
 			want:     "/api/health",
 		},
+		{
+			name:     "int constraint",
+			appDir:   "app",
+			filePath: "app/api/users/[id:int]/route.go",
+			want:     "/api/users/{id:-?[0-9]+}",
+		},
+		{
+			name:     "regexp constraint",
+			appDir:   "app",
+			filePath: "app/api/airports/[code:regexp([A-Z]{3})]/route.go",
+			want:     "/api/airports/{code:[A-Z]{3}}",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewScanner(tt.appDir)
-			got := s.pathToRoute(tt.filePath)
+			got, err := s.pathToRoute(tt.filePath)
+			if err != nil {
+				t.Fatalf("pathToRoute() error = %v", err)
+			}
 			if got != tt.want {
 				t.Errorf("pathToRoute() = %q, want %q", got, tt.want)
 			}
@@ -130,7 +149,7 @@ func Post(c *nexo.Context) error {
 	scanner := NewScanner(appDir)
 	tree := NewRouteTree()
 
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Fatalf("Scan failed: %v", err)
 	}
 
@@ -186,7 +205,7 @@ func Get(c *nexo.Context) error {
 	scanner := NewScanner(appDir)
 	tree := NewRouteTree()
 
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Fatalf("Scan failed: %v", err)
 	}
 
@@ -242,7 +261,7 @@ func Get(c *nexo.Context) error {
 	scanner := NewScanner(appDir)
 	tree := NewRouteTree()
 
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Fatalf("Scan failed: %v", err)
 	}
 
@@ -284,7 +303,7 @@ func Post(c *nexo.Context) error {
 	scanner := NewScanner(appDir)
 	tree := NewRouteTree()
 
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Fatalf("Scan failed: %v", err)
 	}
 
@@ -346,7 +365,7 @@ func delete(c *nexo.Context) error {
 	scanner := NewScanner(appDir)
 	tree := NewRouteTree()
 
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Fatalf("Scan failed: %v", err)
 	}
 
@@ -361,12 +380,150 @@ func delete(c *nexo.Context) error {
 	}
 }
 
+func TestScanner_Scan_AnyHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	proxyDir := filepath.Join(appDir, "proxy")
+
+	if err := os.MkdirAll(proxyDir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+
+	routeContent := `package proxy
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Any(c *nexo.Context) error {
+	return nil
+}
+
+func Post(c *nexo.Context) error {
+	return nil
+}
+`
+	if err := os.WriteFile(filepath.Join(proxyDir, "route.go"), []byte(routeContent), 0644); err != nil {
+		t.Fatalf("Failed to write route.go: %v", err)
+	}
+
+	scanner := NewScanner(appDir)
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	if len(routes) != 7 {
+		t.Fatalf("Expected 7 routes, got %d", len(routes))
+	}
+
+	tree := NewRouteTree()
+	if _, err := scanner.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if got := tree.Methods("/proxy"); strings.Join(got, ",") != "GET,HEAD,POST,PUT,PATCH,DELETE,OPTIONS" {
+		t.Errorf("Methods() = %v", got)
+	}
+
+	// The explicit Post takes precedence over Any
+	for _, r := range tree.Routes() {
+		rec := httptest.NewRecorder()
+		if err := r.Handler(NewContext(rec, httptest.NewRequest(r.Method, "/proxy", nil))); err != nil {
+			t.Fatal(err)
+		}
+		want := "Any"
+		if r.Method == http.MethodPost {
+			want = "Post"
+		}
+		if !strings.Contains(rec.Body.String(), `"handler":"`+want+`"`) {
+			t.Errorf("%s handled by %s, want %s", r.Method, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestScanner_Scan_MethodFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	usersDir := filepath.Join(appDir, "users")
+
+	if err := os.MkdirAll(usersDir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+
+	files := map[string]string{
+		"route.go": `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Any(c *nexo.Context) error {
+	return nil
+}
+`,
+		"route_get.go": `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Get(c *nexo.Context) error {
+	return nil
+}
+`,
+		"route_post.go": `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Post(c *nexo.Context) error {
+	return nil
+}
+`,
+		// Not a method file
+		"route_helpers.go": `package users
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func Put(c *nexo.Context) error {
+	return nil
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(usersDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	scanner := NewScanner(appDir)
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	if len(routes) != 7 {
+		t.Fatalf("Expected 7 routes, got %d", len(routes))
+	}
+	for _, r := range routes {
+		want := "route.go"
+		switch r.Method {
+		case http.MethodGet:
+			want = "route_get.go"
+		case http.MethodPost:
+			want = "route_post.go"
+		}
+		if r.Pattern != "/users" || filepath.Base(r.FilePath) != want {
+			t.Errorf("%s %s declared in %s, want /users in %s", r.Method, r.Pattern, r.FilePath, want)
+		}
+	}
+
+	tree := NewRouteTree()
+	if _, err := scanner.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if got := tree.Methods("/users"); strings.Join(got, ",") != "GET,HEAD,POST,PUT,PATCH,DELETE,OPTIONS" {
+		t.Errorf("Methods() = %v", got)
+	}
+}
+
 func TestScanner_Scan_NonExistentDir(t *testing.T) {
 	scanner := NewScanner("/nonexistent/path")
 	tree := NewRouteTree()
 
 	// Should not return an error, just no routes
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Errorf("Expected no error for non-existent dir, got: %v", err)
 	}
 
@@ -375,6 +532,191 @@ func TestScanner_Scan_NonExistentDir(t *testing.T) {
 	}
 }
 
+func TestNewScannerFS(t *testing.T) {
+	handler := func(pkg, fn string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("package " + pkg + `
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+func ` + fn + `(c *nexo.Context) error {
+	return nil
+}
+`)}
+	}
+	fsys := fstest.MapFS{
+		"api/users/route.go":        handler("users", "Get"),
+		"api/users/route_post.go":   handler("users", "Post"),
+		"api/users/[id]/route.go":   handler("users", "Delete"),
+		"(admin)/settings/route.go": handler("settings", "Put"),
+		"_components/route.go":      handler("components", "Get"),
+		".hidden/route.go":          handler("hidden", "Get"),
+		"api/middleware.go":         {Data: []byte("package api\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Middleware() nexo.MiddlewareFunc { return nil }\n")},
+		"about/page.templ":          {Data: []byte("package about\n\ntempl Page() {}\n")},
+		"dashboard/layout.templ":    {Data: []byte("package dashboard\n\ntempl Layout(title string) {\n\t{ children... }\n}\n")},
+		"proxy.go":                  {Data: []byte("package app\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Proxy(c *nexo.Context) (*nexo.ProxyResult, error) { return nil, nil }\n")},
+	}
+	scanner := NewScannerFS(fsys)
+
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, r := range routes {
+		got[r.Method+" "+r.Pattern] = r.FilePath
+	}
+	want := map[string]string{
+		"GET /api/users":         filepath.FromSlash("api/users/route.go"),
+		"POST /api/users":        filepath.FromSlash("api/users/route_post.go"),
+		"DELETE /api/users/{id}": filepath.FromSlash("api/users/[id]/route.go"),
+		"PUT /settings":          filepath.FromSlash("(admin)/settings/route.go"),
+	}
+	if len(got) != len(want) {
+		t.Errorf("ScanRouteInfo() = %v, want %v", got, want)
+	}
+	for route, file := range want {
+		if got[route] != file {
+			t.Errorf("%s: FilePath = %q, want %q", route, got[route], file)
+		}
+	}
+
+	tree := NewRouteTree()
+	if _, err := scanner.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(tree.Routes()) != len(want) {
+		t.Errorf("Expected %d routes, got %d", len(want), len(tree.Routes()))
+	}
+
+	if mws, err := scanner.ScanMiddlewareInfo(); err != nil || len(mws) != 1 || mws[0].Path != "/api" {
+		t.Errorf("ScanMiddlewareInfo() = %v, %v", mws, err)
+	}
+	if pages, err := scanner.ScanPageInfo(); err != nil || len(pages) != 1 || pages[0].Pattern != "/about" {
+		t.Errorf("ScanPageInfo() = %v, %v", pages, err)
+	}
+	if layouts, err := scanner.ScanLayoutInfo(); err != nil || len(layouts) != 1 || layouts[0].PathPrefix != "/dashboard" {
+		t.Errorf("ScanLayoutInfo() = %v, %v", layouts, err)
+	}
+	if proxy, err := scanner.ScanProxyInfo(); err != nil || !proxy.HasProxy {
+		t.Errorf("ScanProxyInfo() = %v, %v", proxy, err)
+	}
+}
+
+func TestScanner_Sockets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"chat/[room]/ws.go": {Data: []byte(`package room
+
+import "github.com/abdul-hamid-achik/nexo/pkg/nexo"
+
+//nexo:summary Join a chat room
+func Socket(c *nexo.Context, ws *nexo.WebSocketConn) error {
+	return nil
+}
+`)},
+		"chat/[room]/route.go": {Data: []byte("package room\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Post(c *nexo.Context) error { return nil }\n")},
+		"(live)/feed/ws.go":    {Data: []byte("package feed\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Socket(c *nexo.Context, conn *nexo.WebSocketConn) error { return nil }\n")},
+		"broken/ws.go":         {Data: []byte("package broken\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Socket(ws *nexo.WebSocketConn) error { return nil }\n")},
+	}
+	scanner := NewScannerFS(fsys)
+
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	got := make(map[string]RouteInfo)
+	for _, r := range routes {
+		got[r.Method+" "+r.Pattern] = r
+	}
+	if len(got) != 3 {
+		t.Errorf("ScanRouteInfo() = %+v, want 3 routes", routes)
+	}
+	if r := got["GET /chat/{room}"]; !r.WebSocket || r.Directives.Summary != "Join a chat room" {
+		t.Errorf("GET /chat/{room} = %+v, want a WebSocket route with its directives", r)
+	}
+	if r := got["POST /chat/{room}"]; r.WebSocket {
+		t.Errorf("POST /chat/{room} = %+v, want a plain route", r)
+	}
+	if r := got["GET /feed"]; !r.WebSocket {
+		t.Errorf("GET /feed = %+v, want a WebSocket route", r)
+	}
+
+	tree := NewRouteTree()
+	if _, err := scanner.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(tree.Routes()) != 3 {
+		t.Errorf("Expected 3 routes, got %d", len(tree.Routes()))
+	}
+	for _, r := range tree.Routes() {
+		if r.Pattern == "/feed" && r.Scope != "(live)/feed" {
+			t.Errorf("GET /feed scope = %q, want (live)/feed", r.Scope)
+		}
+	}
+}
+
+func TestScanner_Streams(t *testing.T) {
+	fsys := fstest.MapFS{
+		"orders/[id]/sse.go":   {Data: []byte("package id\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Stream(c *nexo.Context, sse *nexo.SSEWriter) error { return nil }\n")},
+		"orders/[id]/route.go": {Data: []byte("package id\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Delete(c *nexo.Context) error { return nil }\n")},
+		"misnamed/sse.go":      {Data: []byte("package misnamed\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Socket(c *nexo.Context, ws *nexo.WebSocketConn) error { return nil }\n")},
+	}
+	scanner := NewScannerFS(fsys)
+
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("ScanRouteInfo() = %+v, want 2 routes", routes)
+	}
+	for _, r := range routes {
+		if wantSSE := r.Method == http.MethodGet; r.Pattern != "/orders/{id}" || r.SSE != wantSSE || r.WebSocket {
+			t.Errorf("route = %+v", r)
+		}
+	}
+
+	tree := NewRouteTree()
+	if _, err := scanner.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(tree.Routes()) != 2 {
+		t.Errorf("Expected 2 routes, got %d", len(tree.Routes()))
+	}
+}
+
+func TestScanner_Actions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"contact/action.go":  {Data: []byte("package contact\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc ActionDelete(c *nexo.Context) error { return nil }\n\n//nexo:summary Send a message\nfunc Action(c *nexo.Context) error { return nil }\n")},
+		"contact/route.go":   {Data: []byte("package contact\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Delete(c *nexo.Context) error { return nil }\n")},
+		"misnamed/action.go": {Data: []byte("package misnamed\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Post(c *nexo.Context) error { return nil }\n")},
+	}
+	scanner := NewScannerFS(fsys)
+
+	routes, _, err := scanner.ScanRouteInfo()
+	if err != nil {
+		t.Fatalf("ScanRouteInfo failed: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("ScanRouteInfo() = %+v, want 2 routes", routes)
+	}
+	for _, r := range routes {
+		if wantAction := r.Method == http.MethodPost; r.Pattern != "/contact" || r.Action != wantAction {
+			t.Errorf("route = %+v", r)
+		}
+		if r.Action && r.Directives.Summary != "Send a message" {
+			t.Errorf("Expected the directives of Action, got %+v", r.Directives)
+		}
+	}
+
+	tree := NewRouteTree()
+	if _, err := scanner.Scan(tree); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(tree.Routes()) != 2 {
+		t.Errorf("Expected 2 routes, got %d", len(tree.Routes()))
+	}
+}
+
 func TestScanner_ScanRouteInfo(t *testing.T) {
 	tmpDir := t.TempDir()
 	appDir := filepath.Join(tmpDir, "app")
@@ -405,7 +747,7 @@ func Delete(c *nexo.Context) error {
 	}
 
 	scanner := NewScanner(appDir)
-	routes, err := scanner.ScanRouteInfo()
+	routes, _, err := scanner.ScanRouteInfo()
 	if err != nil {
 		t.Fatalf("ScanRouteInfo failed: %v", err)
 	}
@@ -437,6 +779,8 @@ func TestCalculatePriority(t *testing.T) {
 		{"/api/health", 100},
 		{"/users/{id}", 50},
 		{"/orgs/{orgId}/teams/{teamId}", 50},
+		{"/users/{id:[0-9]+}", 75},
+		{"/orgs/{orgId:[a-z]+}/teams/{teamId}", 50},
 		{"/docs/*", 5},
 		{"/*", 5},
 	}
@@ -976,7 +1320,7 @@ func Get(c *nexo.Context) error {
 	scanner := NewScanner(appDir)
 	tree := NewRouteTree()
 
-	if err := scanner.Scan(tree); err != nil {
+	if _, err := scanner.Scan(tree); err != nil {
 		t.Fatalf("Scan failed: %v", err)
 	}
 
@@ -1049,12 +1393,20 @@ func TestScanner_PathToPageRoute(t *testing.T) {
 			filePath: "app/api/users/page.templ",
 			want:     "/users",
 		},
+		{
+			name:     "alpha constraint",
+			filePath: "app/tags/[tag:alpha]/page.templ",
+			want:     "/tags/{tag:[a-zA-Z]+}",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewScanner("app")
-			got := s.pathToPageRoute(tt.filePath)
+			got, err := s.pathToPageRoute(tt.filePath)
+			if err != nil {
+				t.Fatalf("pathToPageRoute() error = %v", err)
+			}
 			if got != tt.want {
 				t.Errorf("pathToPageRoute() = %q, want %q", got, tt.want)
 			}
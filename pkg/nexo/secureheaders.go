@@ -0,0 +1,296 @@
+package nexo
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// ---------- Content Security Policy ----------
+
+// Content-Security-Policy source keywords.
+const (
+	CSPSelf           = "'self'"
+	CSPNone           = "'none'"
+	CSPUnsafeInline   = "'unsafe-inline'"
+	CSPUnsafeEval     = "'unsafe-eval'"
+	CSPStrictDynamic  = "'strict-dynamic'"
+	CSPWasmUnsafeEval = "'wasm-unsafe-eval'"
+
+	// CSPNonce is replaced by a nonce generated for each request, e.g.
+	// 'nonce-rAnd0m'. See Context.CSPNonce.
+	CSPNonce = "'nonce'"
+)
+
+// CSP builds a Content-Security-Policy. Directives are written in the order
+// they are first added; adding to a directive again appends its sources.
+//
+// Example:
+//
+//	csp := nexo.NewCSP().
+//	    DefaultSrc(nexo.CSPSelf).
+//	    ScriptSrc(nexo.CSPSelf, nexo.CSPNonce, "https://unpkg.com").
+//	    ImgSrc(nexo.CSPSelf, "data:").
+//	    FrameAncestors(nexo.CSPNone)
+type CSP struct {
+	directives []cspDirective
+}
+
+type cspDirective struct {
+	name    string
+	sources []string
+}
+
+// NewCSP returns an empty policy.
+func NewCSP() *CSP {
+	return &CSP{}
+}
+
+// Directive adds sources to the named directive. Directives without
+// sources, such as upgrade-insecure-requests, are added with none.
+func (p *CSP) Directive(name string, sources ...string) *CSP {
+	for i := range p.directives {
+		if p.directives[i].name == name {
+			p.directives[i].sources = append(p.directives[i].sources, sources...)
+			return p
+		}
+	}
+	p.directives = append(p.directives, cspDirective{name: name, sources: sources})
+	return p
+}
+
+// DefaultSrc adds sources to default-src.
+func (p *CSP) DefaultSrc(sources ...string) *CSP { return p.Directive("default-src", sources...) }
+
+// ScriptSrc adds sources to script-src.
+func (p *CSP) ScriptSrc(sources ...string) *CSP { return p.Directive("script-src", sources...) }
+
+// StyleSrc adds sources to style-src.
+func (p *CSP) StyleSrc(sources ...string) *CSP { return p.Directive("style-src", sources...) }
+
+// ImgSrc adds sources to img-src.
+func (p *CSP) ImgSrc(sources ...string) *CSP { return p.Directive("img-src", sources...) }
+
+// FontSrc adds sources to font-src.
+func (p *CSP) FontSrc(sources ...string) *CSP { return p.Directive("font-src", sources...) }
+
+// ConnectSrc adds sources to connect-src.
+func (p *CSP) ConnectSrc(sources ...string) *CSP { return p.Directive("connect-src", sources...) }
+
+// MediaSrc adds sources to media-src.
+func (p *CSP) MediaSrc(sources ...string) *CSP { return p.Directive("media-src", sources...) }
+
+// ObjectSrc adds sources to object-src.
+func (p *CSP) ObjectSrc(sources ...string) *CSP { return p.Directive("object-src", sources...) }
+
+// FrameSrc adds sources to frame-src.
+func (p *CSP) FrameSrc(sources ...string) *CSP { return p.Directive("frame-src", sources...) }
+
+// WorkerSrc adds sources to worker-src.
+func (p *CSP) WorkerSrc(sources ...string) *CSP { return p.Directive("worker-src", sources...) }
+
+// ManifestSrc adds sources to manifest-src.
+func (p *CSP) ManifestSrc(sources ...string) *CSP { return p.Directive("manifest-src", sources...) }
+
+// FrameAncestors adds sources to frame-ancestors.
+func (p *CSP) FrameAncestors(sources ...string) *CSP {
+	return p.Directive("frame-ancestors", sources...)
+}
+
+// BaseURI adds sources to base-uri.
+func (p *CSP) BaseURI(sources ...string) *CSP { return p.Directive("base-uri", sources...) }
+
+// FormAction adds sources to form-action.
+func (p *CSP) FormAction(sources ...string) *CSP { return p.Directive("form-action", sources...) }
+
+// UpgradeInsecureRequests adds upgrade-insecure-requests.
+func (p *CSP) UpgradeInsecureRequests() *CSP { return p.Directive("upgrade-insecure-requests") }
+
+// ReportTo adds report-to, naming a Reporting-Endpoints group.
+func (p *CSP) ReportTo(group string) *CSP { return p.Directive("report-to", group) }
+
+// Build returns the policy, with CSPNonce sources replaced by nonce.
+func (p *CSP) Build(nonce string) string {
+	parts := make([]string, 0, len(p.directives))
+	for _, d := range p.directives {
+		part := d.name
+		for _, source := range d.sources {
+			if source == CSPNonce {
+				source = "'nonce-" + nonce + "'"
+			}
+			part += " " + source
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// usesNonce reports whether any directive has a CSPNonce source.
+func (p *CSP) usesNonce() bool {
+	for _, d := range p.directives {
+		for _, source := range d.sources {
+			if source == CSPNonce {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateNonce returns a random base64 nonce.
+func generateNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// CSPNonce returns the request's Content-Security-Policy nonce, or "" if
+// the policy set by SecureHeadersWithConfig has no CSPNonce source.
+//
+// templ adds the nonce to the <script> tags it renders, and templates can
+// read it with templ.GetNonce(ctx):
+//
+//	<script nonce={ templ.GetNonce(ctx) } src="/static/app.js"></script>
+func (c *Context) CSPNonce() string {
+	return templ.GetNonce(c.Context())
+}
+
+// ---------- Secure Headers Middleware ----------
+
+// SecureHeadersConfig holds configuration for the secure headers
+// middleware. Headers with an empty value aren't set.
+type SecureHeadersConfig struct {
+	// XContentTypeOptions is the X-Content-Type-Options header.
+	XContentTypeOptions string
+
+	// XFrameOptions is the X-Frame-Options header.
+	XFrameOptions string
+
+	// XSSProtection is the X-XSS-Protection header.
+	XSSProtection string
+
+	// ReferrerPolicy is the Referrer-Policy header.
+	ReferrerPolicy string
+
+	// PermissionsPolicy is the Permissions-Policy header, e.g.
+	// "camera=(), microphone=(), geolocation=(self)".
+	PermissionsPolicy string
+
+	// ContentSecurityPolicy is the Content-Security-Policy header.
+	ContentSecurityPolicy *CSP
+
+	// CSPReportOnly sends the policy as
+	// Content-Security-Policy-Report-Only, so violations are reported but
+	// not blocked.
+	CSPReportOnly bool
+
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header,
+	// in seconds. Zero doesn't send the header.
+	HSTSMaxAge int
+
+	// HSTSIncludeSubdomains adds includeSubDomains to the HSTS header.
+	HSTSIncludeSubdomains bool
+
+	// HSTSPreload adds preload to the HSTS header.
+	HSTSPreload bool
+
+	// Skip returns true for requests that don't get the headers.
+	Skip func(c *Context) bool
+}
+
+// DefaultSecureHeadersConfig returns the configuration used by
+// SecureHeaders.
+func DefaultSecureHeadersConfig() SecureHeadersConfig {
+	return SecureHeadersConfig{
+		XContentTypeOptions: "nosniff",
+		XFrameOptions:       "SAMEORIGIN",
+		XSSProtection:       "1; mode=block",
+		ReferrerPolicy:      "strict-origin-when-cross-origin",
+	}
+}
+
+// SecureHeaders returns a middleware that sets security-related headers.
+func SecureHeaders() MiddlewareFunc {
+	return SecureHeadersWithConfig(DefaultSecureHeadersConfig())
+}
+
+// SecureHeadersWithConfig returns a secure headers middleware with custom
+// configuration. Start from DefaultSecureHeadersConfig to keep the
+// defaults.
+//
+// Strict-Transport-Security is only sent on HTTPS requests, including those
+// a proxy forwards with X-Forwarded-Proto: https. When the policy has a
+// CSPNonce source, each request gets a new nonce (see Context.CSPNonce).
+//
+// Example:
+//
+//	config := nexo.DefaultSecureHeadersConfig()
+//	config.ContentSecurityPolicy = nexo.NewCSP().
+//	    DefaultSrc(nexo.CSPSelf).
+//	    ScriptSrc(nexo.CSPSelf, nexo.CSPNonce)
+//	config.HSTSMaxAge = 63072000 // 2 years
+//	config.HSTSIncludeSubdomains = true
+//	app.Use(nexo.SecureHeadersWithConfig(config))
+func SecureHeadersWithConfig(config SecureHeadersConfig) MiddlewareFunc {
+	var hsts string
+	if config.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	cspHeader := "Content-Security-Policy"
+	if config.CSPReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	var policy string
+	var nonced bool
+	if config.ContentSecurityPolicy != nil {
+		nonced = config.ContentSecurityPolicy.usesNonce()
+		if !nonced {
+			policy = config.ContentSecurityPolicy.Build("")
+		}
+	}
+
+	headers := [][2]string{
+		{"X-Content-Type-Options", config.XContentTypeOptions},
+		{"X-Frame-Options", config.XFrameOptions},
+		{"X-XSS-Protection", config.XSSProtection},
+		{"Referrer-Policy", config.ReferrerPolicy},
+		{"Permissions-Policy", config.PermissionsPolicy},
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if config.Skip != nil && config.Skip(c) {
+				return next(c)
+			}
+
+			for _, h := range headers {
+				if h[1] != "" {
+					c.SetHeader(h[0], h[1])
+				}
+			}
+			if hsts != "" && (c.Request.TLS != nil || strings.EqualFold(c.Header("X-Forwarded-Proto"), "https")) {
+				c.SetHeader("Strict-Transport-Security", hsts)
+			}
+
+			if nonced {
+				nonce := generateNonce()
+				c.WithContext(templ.WithNonce(c.Context(), nonce))
+				c.SetHeader(cspHeader, config.ContentSecurityPolicy.Build(nonce))
+			} else if policy != "" {
+				c.SetHeader(cspHeader, policy)
+			}
+
+			return next(c)
+		}
+	}
+}
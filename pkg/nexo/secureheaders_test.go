@@ -0,0 +1,127 @@
+package nexo
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestCSP_Build(t *testing.T) {
+	csp := NewCSP().
+		DefaultSrc(CSPSelf).
+		ScriptSrc(CSPSelf, CSPNonce).
+		ImgSrc(CSPSelf, "data:").
+		ScriptSrc("https://unpkg.com").
+		FrameAncestors(CSPNone).
+		UpgradeInsecureRequests()
+
+	want := "default-src 'self'; script-src 'self' 'nonce-abc' https://unpkg.com; img-src 'self' data:; frame-ancestors 'none'; upgrade-insecure-requests"
+	if got := csp.Build("abc"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSecureHeadersWithConfig(t *testing.T) {
+	config := DefaultSecureHeadersConfig()
+	config.XFrameOptions = ""
+	config.PermissionsPolicy = "camera=()"
+	config.ContentSecurityPolicy = NewCSP().DefaultSrc(CSPSelf)
+
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := SecureHeadersWithConfig(config)(func(c *Context) error {
+		if c.CSPNonce() != "" {
+			t.Error("expected no nonce without a CSPNonce source")
+		}
+		return nil
+	})(c); err != nil {
+		t.Fatal(err)
+	}
+
+	h := w.Header()
+	if h.Get("X-Frame-Options") != "" {
+		t.Error("expected an empty header not to be set")
+	}
+	if h.Get("X-Content-Type-Options") != "nosniff" {
+		t.Error("expected the default headers to be kept")
+	}
+	if h.Get("Permissions-Policy") != "camera=()" {
+		t.Errorf("unexpected Permissions-Policy %q", h.Get("Permissions-Policy"))
+	}
+	if h.Get("Content-Security-Policy") != "default-src 'self'" {
+		t.Errorf("unexpected Content-Security-Policy %q", h.Get("Content-Security-Policy"))
+	}
+	if h.Get("Strict-Transport-Security") != "" {
+		t.Error("expected no HSTS header by default")
+	}
+}
+
+func TestSecureHeaders_HSTS(t *testing.T) {
+	mw := SecureHeadersWithConfig(SecureHeadersConfig{
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           true,
+	})
+	handler := mw(func(c *Context) error { return nil })
+	want := "max-age=31536000; includeSubDomains; preload"
+
+	tests := []struct {
+		name  string
+		setup func(r *http.Request)
+		want  string
+	}{
+		{"plain HTTP", func(r *http.Request) {}, ""},
+		{"TLS", func(r *http.Request) { r.TLS = &tls.ConnectionState{} }, want},
+		{"forwarded HTTPS", func(r *http.Request) { r.Header.Set("X-Forwarded-Proto", "https") }, want},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			_ = handler(NewContext(w, req))
+			if got := w.Header().Get("Strict-Transport-Security"); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSecureHeaders_Nonce(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Use(SecureHeadersWithConfig(SecureHeadersConfig{
+		ContentSecurityPolicy: NewCSP().ScriptSrc(CSPSelf, CSPNonce),
+		CSPReportOnly:         true,
+	}))
+	app.Get("/", func(c *Context) error {
+		script := templ.ComponentScript{Name: "__hello", Function: "function __hello(){}", Call: "__hello()"}
+		return c.Render(http.StatusOK, templ.Join(templ.Raw(c.CSPNonce()+"\n"), script))
+	})
+	app.Mount()
+
+	get := func() (nonce, policy, body string) {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce, body, _ = strings.Cut(w.Body.String(), "\n")
+		return nonce, w.Header().Get("Content-Security-Policy-Report-Only"), body
+	}
+
+	nonce, policy, body := get()
+	if nonce == "" {
+		t.Fatal("expected a nonce")
+	}
+	if want := "script-src 'self' 'nonce-" + nonce + "'"; policy != want {
+		t.Errorf("expected %q, got %q", want, policy)
+	}
+	if !strings.Contains(body, `nonce="`+nonce+`"`) {
+		t.Errorf("expected templ scripts to get the nonce, got %q", body)
+	}
+	if next, _, _ := get(); next == nonce {
+		t.Error("expected a new nonce per request")
+	}
+}
@@ -0,0 +1,118 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// servicesKey is the request context key for the app's services.
+type servicesKey struct{}
+
+// services holds the values registered with Provide, keyed by name or by
+// type.
+type services struct {
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+func (s *services) set(key, value any) {
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+}
+
+func (s *services) get(key any) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// withServices makes the app's services available to handlers.
+func withServices(r *http.Request, s *services) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), servicesKey{}, s))
+}
+
+// services returns the app's services, or nil outside an app.
+func (c *Context) services() *services {
+	s, _ := c.Request.Context().Value(servicesKey{}).(*services)
+	return s
+}
+
+// appServices returns the app's services, creating them on first use.
+func (a *App) appServices() *services {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.services == nil {
+		a.services = &services{values: make(map[any]any)}
+	}
+	return a.services
+}
+
+// Provide registers a service under a name, for handlers to look up with
+// UseNamed. Register services before the app starts serving.
+//
+// Example:
+//
+//	app.Provide("replica", replicaPool)
+//
+//	// In a handler
+//	db, ok := nexo.UseNamed[*pgxpool.Pool](c, "replica")
+func (a *App) Provide(name string, value any) {
+	a.appServices().set(name, value)
+}
+
+// Provide registers a service by its type T, for handlers to look up with
+// Use, so handlers in route files can reach database pools and clients
+// without package-level globals. Registering another value for the same T
+// replaces it. Register services before the app starts serving.
+//
+// Example:
+//
+//	nexo.Provide(app, pool)                   // *pgxpool.Pool
+//	nexo.Provide[Mailer](app, &smtpMailer{})  // by interface
+//
+//	// In a handler
+//	pool := nexo.MustUse[*pgxpool.Pool](c)
+func Provide[T any](app *App, value T) {
+	app.appServices().set(reflect.TypeFor[T](), value)
+}
+
+// Use returns the service registered with Provide for type T. ok is false
+// if none was registered.
+func Use[T any](c *Context) (T, bool) {
+	return lookupService[T](c, reflect.TypeFor[T]())
+}
+
+// MustUse returns the service registered with Provide for type T or panics
+// if none was registered.
+func MustUse[T any](c *Context) T {
+	value, ok := Use[T](c)
+	if !ok {
+		panic(fmt.Sprintf("no service provided for %s", reflect.TypeFor[T]()))
+	}
+	return value
+}
+
+// UseNamed returns the service registered with App.Provide under name as T.
+// ok is false if none was registered or it holds another type.
+func UseNamed[T any](c *Context, name string) (T, bool) {
+	return lookupService[T](c, name)
+}
+
+func lookupService[T any](c *Context, key any) (T, bool) {
+	var zero T
+	s := c.services()
+	if s == nil {
+		return zero, false
+	}
+	value, ok := s.get(key)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := value.(T)
+	return typed, ok
+}
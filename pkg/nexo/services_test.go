@@ -0,0 +1,78 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testMailer interface {
+	Send(to string) string
+}
+
+type stubMailer struct{}
+
+func (stubMailer) Send(to string) string { return "sent to " + to }
+
+type testDB struct{ name string }
+
+func TestProvide(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	Provide(app, &testDB{name: "primary"})
+	Provide[testMailer](app, stubMailer{})
+	app.Provide("replica", &testDB{name: "replica"})
+
+	app.Get("/", func(c *Context) error {
+		db := MustUse[*testDB](c)
+		mailer, ok := Use[testMailer](c)
+		if !ok {
+			return InternalServerError("no mailer")
+		}
+		replica, ok := UseNamed[*testDB](c, "replica")
+		if !ok {
+			return InternalServerError("no replica")
+		}
+		return c.String(http.StatusOK, db.name+","+replica.name+","+mailer.Send("bob"))
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if want := "primary,replica,sent to bob"; w.Body.String() != want {
+		t.Errorf("got %d %q, want %q", w.Code, w.Body.String(), want)
+	}
+}
+
+func TestUse_Missing(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Provide("replica", "not a db")
+
+	app.Get("/", func(c *Context) error {
+		if _, ok := Use[*testDB](c); ok {
+			t.Error("expected no service for an unregistered type")
+		}
+		if _, ok := UseNamed[*testDB](c, "replica"); ok {
+			t.Error("expected no service for the wrong type")
+		}
+		if _, ok := UseNamed[string](c, "missing"); ok {
+			t.Error("expected no service for an unregistered name")
+		}
+		return c.NoContent()
+	})
+	app.Mount()
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Contexts outside an app have no services
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if _, ok := Use[*testDB](c); ok {
+		t.Error("expected no services outside an app")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustUse to panic")
+		}
+	}()
+	MustUse[*testDB](c)
+}
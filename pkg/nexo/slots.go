@@ -0,0 +1,100 @@
+package nexo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// SlotFunc renders a parallel route slot for a request, loading its data
+// first if needed. The generated routes register one for every
+// @slot/page.templ (see Renderer.SetSlot).
+type SlotFunc func(c *Context) (templ.Component, error)
+
+// SetSlot registers the slot called name for the pages under pathPrefix.
+// For app/dashboard/@analytics/page.templ, that's SetSlot("/dashboard",
+// "analytics", ...). A slot registered for a longer prefix, e.g. from
+// app/dashboard/@analytics/settings/page.templ, replaces it below that
+// prefix. Layouts render slots with Slot.
+func (r *Renderer) SetSlot(pathPrefix, name string, slot SlotFunc) {
+	if r.slots[pathPrefix] == nil {
+		r.slots[pathPrefix] = make(map[string]SlotFunc)
+	}
+	r.slots[pathPrefix][name] = slot
+}
+
+// GetSlot returns the most specific slot called name for a path.
+func (r *Renderer) GetSlot(path, name string) SlotFunc {
+	var bestMatch string
+	var bestSlot SlotFunc
+
+	for prefix, slots := range r.slots {
+		slot, ok := slots[name]
+		if ok && (bestSlot == nil || len(prefix) > len(bestMatch)) && matchesPrefix(path, prefix) {
+			bestMatch = prefix
+			bestSlot = slot
+		}
+	}
+
+	return bestSlot
+}
+
+// hasSlots reports whether any slots are registered.
+func (r *Renderer) hasSlots() bool {
+	return len(r.slots) > 0
+}
+
+// slotScope is what Slot needs to render a slot for the current request.
+type slotScope struct {
+	c        *Context
+	renderer *Renderer
+}
+
+// slotScopeKey is the request context key for the slotScope.
+type slotScopeKey struct{}
+
+// slotsMiddleware makes the request's slots available to Slot.
+func (r *Renderer) slotsMiddleware(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		scope := &slotScope{c: c, renderer: r}
+		c.WithContext(context.WithValue(c.Context(), slotScopeKey{}, scope))
+		return next(c)
+	}
+}
+
+// Slot renders the parallel route slot called name for the current page,
+// so a layout can place several independently loaded sections:
+//
+//	templ Layout() {
+//	    <main>{ children... }</main>
+//	    <aside>@nexo.Slot("analytics")</aside>
+//	    <aside>@nexo.Slot("team")</aside>
+//	}
+//
+// Each slot runs its own loader when it renders. A slot that fails renders
+// the nearest error component (see error.templ) in its place. Slots that
+// don't apply to the page render nothing.
+func Slot(name string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		scope, ok := ctx.Value(slotScopeKey{}).(*slotScope)
+		if !ok {
+			return nil
+		}
+		slot := scope.renderer.GetSlot(scope.c.Path(), name)
+		if slot == nil {
+			return nil
+		}
+
+		comp, err := slot(scope.c)
+		if err != nil {
+			errComp := scope.renderer.GetErrorComponent(scope.c.Path())
+			if errComp == nil {
+				return fmt.Errorf("slot %s: %w", name, err)
+			}
+			comp = errComp(err)
+		}
+		return comp.Render(ctx, w)
+	})
+}
@@ -0,0 +1,100 @@
+package nexo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestRenderer_GetSlot(t *testing.T) {
+	r := NewRenderer()
+	slot := func(content string) SlotFunc {
+		return func(c *Context) (templ.Component, error) {
+			return mockComponent{content: content}, nil
+		}
+	}
+	r.SetSlot("/dashboard", "analytics", slot("overview"))
+	r.SetSlot("/dashboard/settings", "analytics", slot("settings"))
+	r.SetSlot("/dashboard", "team", slot("team"))
+
+	tests := []struct {
+		path string
+		name string
+		want string
+	}{
+		{"/dashboard", "analytics", "overview"},
+		{"/dashboard/reports", "analytics", "overview"},
+		{"/dashboard/settings/profile", "analytics", "settings"},
+		{"/dashboard/settings", "team", "team"},
+		{"/blog", "analytics", ""},
+		{"/dashboard", "missing", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if slot := r.GetSlot(tt.path, tt.name); slot != nil {
+			comp, _ := slot(nil)
+			got = comp.(mockComponent).content
+		}
+		if got != tt.want {
+			t.Errorf("GetSlot(%q, %q) = %q, want %q", tt.path, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApp_Slots(t *testing.T) {
+	app := New(WithLogger(false))
+	app.Renderer().SetSlot("/dashboard", "analytics", func(c *Context) (templ.Component, error) {
+		return mockComponent{content: "<p>visits for " + c.Query("range") + "</p>"}, nil
+	})
+	app.Renderer().SetSlot("/dashboard", "team", func(c *Context) (templ.Component, error) {
+		return nil, errors.New("team service down")
+	})
+	layout := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, comp := range []templ.Component{Slot("analytics"), Slot("team"), Slot("unknown")} {
+			if err := comp.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	app.Get("/dashboard", func(c *Context) error {
+		return TemplComponent(c, http.StatusOK, layout)
+	})
+	app.Mount()
+
+	serve := func() string {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dashboard?range=week", nil))
+		return w.Body.String()
+	}
+
+	// Without an error component, the failing slot ends the render
+	if body := serve(); strings.Contains(body, "team service down") {
+		t.Errorf("expected the slot error not to be rendered, got %q", body)
+	}
+
+	app.Renderer().SetErrorComponent("/dashboard", mockErrorComponent)
+	body := serve()
+	if !strings.Contains(body, "visits for week") {
+		t.Errorf("expected the analytics slot, got %q", body)
+	}
+	if !strings.Contains(body, `<div class="error">team service down</div>`) {
+		t.Errorf("expected the failing slot to render the error component, got %q", body)
+	}
+}
+
+func TestSlot_OutsideRequest(t *testing.T) {
+	var b strings.Builder
+	if err := Slot("analytics").Render(context.Background(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected nothing rendered, got %q", b.String())
+	}
+}
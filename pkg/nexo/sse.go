@@ -1,11 +1,22 @@
 package nexo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultSSEHeartbeat is how often Context.SSE sends a keep-alive comment,
+// so proxies don't close idle streams. Change it with SSEWriter.Heartbeat.
+const DefaultSSEHeartbeat = 15 * time.Second
+
+// errSSEClosed is returned when sending on a closed stream.
+var errSSEClosed = fmt.Errorf("sse: connection closed")
+
 // SSEWriter provides methods for streaming Server-Sent Events.
 // Use Context.SSE() to obtain an SSEWriter.
 //
@@ -17,23 +28,58 @@ import (
 //	        return err
 //	    }
 //
-//	    for i := 0; i < 10; i++ {
-//	        if sse.IsClosed() {
-//	            break
+//	    ticker := time.NewTicker(time.Second)
+//	    defer ticker.Stop()
+//	    for i := 0; ; i++ {
+//	        select {
+//	        case <-sse.Done():
+//	            return nil // Client disconnected
+//	        case <-ticker.C:
+//	            sse.Send("message", fmt.Sprintf("Event %d", i))
 //	        }
-//	        sse.Send("message", fmt.Sprintf("Event %d", i))
-//	        time.Sleep(time.Second)
 //	    }
-//	    return nil
 //	}
+//
+// The writer is safe for concurrent use.
 type SSEWriter struct {
 	w       http.ResponseWriter
 	flusher http.Flusher
-	closed  bool
+	ctx     context.Context // Request context, done when the client disconnects
+
+	lastEventID string
+
+	mu            sync.Mutex
+	closed        bool
+	stopHeartbeat chan struct{}
+}
+
+// newSSEWriter returns a writer for a stream on w that ends with ctx.
+func newSSEWriter(w http.ResponseWriter, flusher http.Flusher, ctx context.Context) *SSEWriter {
+	return &SSEWriter{w: w, flusher: flusher, ctx: ctx}
+}
+
+// write writes msg, flushing it if flush is set. A failed write closes the
+// writer.
+func (s *SSEWriter) write(msg string, flush bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closedLocked() {
+		return errSSEClosed
+	}
+	if _, err := fmt.Fprint(s.w, msg); err != nil {
+		s.closeLocked()
+		return err
+	}
+	if flush {
+		s.flusher.Flush()
+	}
+	return nil
 }
 
 // Send sends an SSE event with an optional event type.
-// If event is empty, only the data field is sent.
+// If event is empty, only the data field is sent. Data containing newlines
+// is sent as multiple data lines, which clients join back together.
 // Returns an error if the connection is closed or write fails.
 //
 // Example:
@@ -41,25 +87,15 @@ type SSEWriter struct {
 //	sse.Send("message", "Hello, World!")
 //	// Output: event: message\ndata: Hello, World!\n\n
 func (s *SSEWriter) Send(event, data string) error {
-	if s.closed {
-		return fmt.Errorf("sse: connection closed")
-	}
-
-	var err error
+	var msg strings.Builder
 	if event != "" {
-		_, err = fmt.Fprintf(s.w, "event: %s\n", event)
-		if err != nil {
-			s.closed = true
-			return err
-		}
+		fmt.Fprintf(&msg, "event: %s\n", event)
 	}
-	_, err = fmt.Fprintf(s.w, "data: %s\n\n", data)
-	if err != nil {
-		s.closed = true
-		return err
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&msg, "data: %s\n", strings.TrimSuffix(line, "\r"))
 	}
-	s.flusher.Flush()
-	return nil
+	msg.WriteString("\n")
+	return s.write(msg.String(), true)
 }
 
 // SendData sends data without an event type.
@@ -76,8 +112,8 @@ func (s *SSEWriter) SendData(data string) error {
 //	sse.SendJSON("user", User{Name: "Alice"})
 //	// Output: event: user\ndata: {"name":"Alice"}\n\n
 func (s *SSEWriter) SendJSON(event string, data any) error {
-	if s.closed {
-		return fmt.Errorf("sse: connection closed")
+	if s.IsClosed() {
+		return errSSEClosed
 	}
 
 	jsonBytes, err := json.Marshal(data)
@@ -95,17 +131,7 @@ func (s *SSEWriter) SendJSON(event string, data any) error {
 //	sse.SendComment("keep-alive")
 //	// Output: : keep-alive\n\n
 func (s *SSEWriter) SendComment(comment string) error {
-	if s.closed {
-		return fmt.Errorf("sse: connection closed")
-	}
-
-	_, err := fmt.Fprintf(s.w, ": %s\n\n", comment)
-	if err != nil {
-		s.closed = true
-		return err
-	}
-	s.flusher.Flush()
-	return nil
+	return s.write(fmt.Sprintf(": %s\n\n", comment), true)
 }
 
 // SendRetry sets the reconnection time for the client in milliseconds.
@@ -116,17 +142,7 @@ func (s *SSEWriter) SendComment(comment string) error {
 //	sse.SendRetry(5000) // 5 seconds
 //	// Output: retry: 5000\n\n
 func (s *SSEWriter) SendRetry(milliseconds int) error {
-	if s.closed {
-		return fmt.Errorf("sse: connection closed")
-	}
-
-	_, err := fmt.Fprintf(s.w, "retry: %d\n\n", milliseconds)
-	if err != nil {
-		s.closed = true
-		return err
-	}
-	s.flusher.Flush()
-	return nil
+	return s.write(fmt.Sprintf("retry: %d\n\n", milliseconds), true)
 }
 
 // SendID sets the event ID.
@@ -138,26 +154,152 @@ func (s *SSEWriter) SendRetry(milliseconds int) error {
 //	sse.Send("message", "data")
 //	// Output: id: evt-123\nevent: message\ndata: data\n\n
 func (s *SSEWriter) SendID(id string) error {
-	if s.closed {
-		return fmt.Errorf("sse: connection closed")
+	return s.write(fmt.Sprintf("id: %s\n", id), false)
+}
+
+// Heartbeat sends a ": ping" comment every interval until the writer is
+// closed or the client disconnects, replacing any earlier heartbeat. An
+// interval of 0 stops the heartbeat.
+func (s *SSEWriter) Heartbeat(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopHeartbeat != nil {
+		close(s.stopHeartbeat)
+		s.stopHeartbeat = nil
+	}
+	if interval <= 0 || s.closedLocked() {
+		return
 	}
+	stop := make(chan struct{})
+	s.stopHeartbeat = stop
+	go s.heartbeat(interval, stop)
+}
 
-	_, err := fmt.Fprintf(s.w, "id: %s\n", id)
-	if err != nil {
-		s.closed = true
-		return err
+func (s *SSEWriter) heartbeat(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-s.Done():
+			return
+		case <-ticker.C:
+			if s.SendComment("ping") != nil {
+				return
+			}
+		}
 	}
-	return nil
+}
+
+// LastEventID returns the ID of the last event the client received before
+// it reconnected, from the Last-Event-ID header browsers send, or the
+// lastEventId query parameter for clients that can't set headers. It is
+// empty on the first connection. Resume the stream after it to replay the
+// events the client missed.
+//
+// Example:
+//
+//	for _, msg := range messagesAfter(sse.LastEventID()) {
+//	    sse.SendID(msg.ID)
+//	    sse.SendJSON("message", msg)
+//	}
+func (s *SSEWriter) LastEventID() string {
+	return s.lastEventID
+}
+
+// Done returns a channel that is closed when the client disconnects.
+func (s *SSEWriter) Done() <-chan struct{} {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Done()
 }
 
 // IsClosed returns true if the SSE connection has been closed.
 // This can happen if the client disconnects or a write error occurs.
 func (s *SSEWriter) IsClosed() bool {
-	return s.closed
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closedLocked()
 }
 
-// Close marks the SSE writer as closed.
-// Subsequent Send calls will return an error.
+// Close marks the SSE writer as closed and stops the heartbeat.
+// Subsequent Send calls will return an error. Streams from Context.SSE are
+// closed when the handler returns.
 func (s *SSEWriter) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+// closedLocked reports whether the writer is closed, closing it if the
+// client has disconnected. s.mu must be held.
+func (s *SSEWriter) closedLocked() bool {
+	if !s.closed && s.ctx != nil && s.ctx.Err() != nil {
+		s.closeLocked()
+	}
+	return s.closed
+}
+
+// closeLocked closes the writer. s.mu must be held.
+func (s *SSEWriter) closeLocked() {
 	s.closed = true
+	if s.stopHeartbeat != nil {
+		close(s.stopHeartbeat)
+		s.stopHeartbeat = nil
+	}
+}
+
+// SSEConfig configures the streams of StreamHandler.
+type SSEConfig struct {
+	// Heartbeat is how often a keep-alive comment is sent. Zero uses
+	// DefaultSSEHeartbeat and a negative interval disables it.
+	Heartbeat time.Duration
+
+	// Retry is how long clients wait before reconnecting once the stream
+	// ends or drops. Zero leaves it to the client, about 3 seconds in
+	// browsers.
+	Retry time.Duration
+}
+
+// StreamFunc streams Server-Sent Events to a client, like the Stream
+// function of an sse.go file.
+type StreamFunc func(c *Context, sse *SSEWriter) error
+
+// StreamHandler returns a handler that opens a Server-Sent Events stream
+// and runs stream with it. The stream is closed when stream returns, and
+// clients reconnect with the ID of the last event they got, which
+// SSEWriter.LastEventID returns. Routes generated for sse.go files use it.
+//
+// Example:
+//
+//	app.Get("/events", nexo.StreamHandler(func(c *nexo.Context, sse *nexo.SSEWriter) error {
+//	    for event := range events(c.Context(), sse.LastEventID()) {
+//	        sse.SendID(event.ID)
+//	        sse.SendJSON("event", event)
+//	    }
+//	    return nil
+//	}, nexo.SSEConfig{Retry: 5 * time.Second}))
+func StreamHandler(stream StreamFunc, config ...SSEConfig) HandlerFunc {
+	var cfg SSEConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return func(c *Context) error {
+		sse, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		if cfg.Heartbeat != 0 {
+			sse.Heartbeat(cfg.Heartbeat)
+		}
+		if cfg.Retry > 0 {
+			if err := sse.SendRetry(int(cfg.Retry.Milliseconds())); err != nil {
+				return nil // Client disconnected
+			}
+		}
+		return stream(c, sse)
+	}
 }
@@ -1,10 +1,15 @@
 package nexo
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSSEWriter_Send(t *testing.T) {
@@ -270,6 +275,40 @@ func TestContext_SSE(t *testing.T) {
 	}
 }
 
+func TestContext_SSE_OutlivesWriteTimeout(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/events", func(c *Context) error {
+		sse, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		for i := range 3 {
+			time.Sleep(50 * time.Millisecond)
+			_ = sse.SendData(strconv.Itoa(i))
+		}
+		return nil
+	})
+	app.Mount()
+	srv := httptest.NewUnstartedServer(app)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("stream cut off: %v (got %q)", err, body)
+	}
+	if !strings.Contains(string(body), "data: 2\n") {
+		t.Errorf("expected every event, got %q", body)
+	}
+}
+
 func TestSSEWriter_MultipleEvents(t *testing.T) {
 	w := httptest.NewRecorder()
 	sse := &SSEWriter{w: w, flusher: w}
@@ -296,3 +335,150 @@ func TestSSEWriter_MultipleEvents(t *testing.T) {
 		t.Errorf("Expected 'data: data3', got: %s", body)
 	}
 }
+
+func TestSSEWriter_SendMultiline(t *testing.T) {
+	w := httptest.NewRecorder()
+	sse := &SSEWriter{w: w, flusher: w}
+
+	_ = sse.Send("log", "line one\r\nline two\nline three")
+
+	want := "event: log\ndata: line one\ndata: line two\ndata: line three\n\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestSSEWriter_ClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	c := NewContext(w, req)
+
+	sse, err := c.SSE()
+	if err != nil {
+		t.Fatalf("SSE() failed: %v", err)
+	}
+	defer sse.Close()
+
+	if sse.IsClosed() {
+		t.Fatal("Expected stream to be open")
+	}
+	cancel()
+
+	select {
+	case <-sse.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected Done() to be closed after the client disconnects")
+	}
+	if !sse.IsClosed() {
+		t.Error("Expected IsClosed() after the client disconnects")
+	}
+	if err := sse.Send("message", "late"); err == nil {
+		t.Error("Expected Send() to fail after the client disconnects")
+	}
+}
+
+func TestSSEWriter_Heartbeat(t *testing.T) {
+	w := &lockedRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sse := &SSEWriter{w: w, flusher: w}
+
+	sse.Heartbeat(5 * time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(w.String(), ": ping\n\n") {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected a heartbeat comment")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	sse.Close()
+	size := len(w.String())
+	time.Sleep(20 * time.Millisecond)
+	if len(w.String()) != size {
+		t.Error("Expected the heartbeat to stop after Close()")
+	}
+}
+
+func TestSSEWriter_ClosedWhenHandlerReturns(t *testing.T) {
+	var sse *SSEWriter
+	app := New()
+	app.DisableLogger()
+	app.Get("/events", func(c *Context) error {
+		var err error
+		sse, err = c.SSE()
+		return err
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("status = %d, Content-Type = %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if sse == nil || !sse.IsClosed() {
+		t.Error("Expected the stream to be closed when the handler returns")
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	var lastEventID string
+	app := New()
+	app.DisableLogger()
+	app.Get("/events", StreamHandler(func(c *Context, sse *SSEWriter) error {
+		lastEventID = sse.LastEventID()
+		_ = sse.SendID("42")
+		return sse.SendData("hello")
+	}, SSEConfig{Retry: 5 * time.Second}))
+	app.Mount()
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		want   string
+	}{
+		{name: "first connection", target: "/events"},
+		{name: "reconnect", target: "/events", header: "41", want: "41"},
+		{name: "reconnect with query", target: "/events?lastEventId=40", want: "40"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Header().Get("Content-Type") != "text/event-stream" {
+				t.Errorf("Content-Type = %q", w.Header().Get("Content-Type"))
+			}
+			if want := "retry: 5000\n\nid: 42\ndata: hello\n\n"; w.Body.String() != want {
+				t.Errorf("body = %q, want %q", w.Body.String(), want)
+			}
+			if lastEventID != tt.want {
+				t.Errorf("LastEventID() = %q, want %q", lastEventID, tt.want)
+			}
+		})
+	}
+}
+
+// lockedRecorder is a ResponseRecorder that can be read while a heartbeat
+// writes to it.
+type lockedRecorder struct {
+	*httptest.ResponseRecorder
+	mu sync.Mutex
+}
+
+func (r *lockedRecorder) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Write(b)
+}
+
+func (r *lockedRecorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Body.String()
+}
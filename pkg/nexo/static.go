@@ -0,0 +1,320 @@
+package nexo
+
+import (
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CacheImmutable is a Cache-Control value for fingerprinted assets, whose
+// content never changes at a given URL.
+const CacheImmutable = "public, max-age=31536000, immutable"
+
+// StaticConfig holds configuration for serving static files.
+type StaticConfig struct {
+	// CacheControl sets the Cache-Control header by file extension, e.g.
+	// {".js": nexo.CacheImmutable, ".html": "no-cache"}. Directory
+	// indexes use the ".html" entry.
+	CacheControl map[string]string
+
+	// DefaultCacheControl is the Cache-Control header of files whose
+	// extension isn't in CacheControl. Default is none.
+	DefaultCacheControl string
+
+	// Precompressed serves a file's .br or .gz sibling, e.g. app.js.br for
+	// app.js, to clients that accept the encoding, so assets compressed at
+	// build time aren't compressed on every request.
+	Precompressed bool
+
+	// DisableListing responds 404 to directories without an index.html
+	// instead of listing their files.
+	DisableListing bool
+
+	// SPAIndex is a file, relative to the directory, served for paths
+	// without a file extension that match no file, e.g. "index.html", so a
+	// single-page app can route on the client. Missing assets, such as
+	// /app.js, still get a 404. See App.StaticSPA.
+	SPAIndex string
+
+	// SPAExclude lists the URL path prefixes that never get SPAIndex, so
+	// unknown API routes get a 404 instead of the app. Default is "/api".
+	SPAExclude []string
+}
+
+// embeddedStatic holds the static directories embedded into the binary,
+// keyed by their cleaned directory path.
+var (
+	embeddedStaticMu sync.RWMutex
+	embeddedStatic   = make(map[string]fs.FS)
+)
+
+// EmbedStatic makes Static serve dir from fsys instead of the file system.
+// It is called by the nexo_static.go file that `nexo build --embed-static`
+// generates, so the binary can be deployed without the static directory.
+func EmbedStatic(dir string, fsys fs.FS) {
+	embeddedStaticMu.Lock()
+	defer embeddedStaticMu.Unlock()
+	embeddedStatic[filepath.Clean(dir)] = fsys
+}
+
+// embeddedStaticFS returns the embedded file system for dir, or nil.
+func embeddedStaticFS(dir string) fs.FS {
+	embeddedStaticMu.RLock()
+	defer embeddedStaticMu.RUnlock()
+	return embeddedStatic[filepath.Clean(dir)]
+}
+
+// Static serves static files from a directory.
+// The path is the URL path prefix, and dir is the file system directory.
+// If dir was embedded with EmbedStatic, files are served from the binary.
+func (a *App) Static(path string, dir string) {
+	a.StaticWithConfig(path, dir, StaticConfig{})
+}
+
+// StaticWithConfig serves static files from a directory like Static, with
+// caching, precompression and directory listing options.
+//
+// Example:
+//
+//	app.StaticWithConfig("/static", "static", nexo.StaticConfig{
+//	    CacheControl: map[string]string{
+//	        ".js":  nexo.CacheImmutable,
+//	        ".css": nexo.CacheImmutable,
+//	    },
+//	    DefaultCacheControl: "public, max-age=3600",
+//	    Precompressed:       true,
+//	    DisableListing:      true,
+//	})
+func (a *App) StaticWithConfig(path string, dir string, config StaticConfig) {
+	var root http.FileSystem = http.Dir(dir)
+	if fsys := embeddedStaticFS(dir); fsys != nil {
+		root = http.FS(fsys)
+	}
+	a.static(path, root, config)
+}
+
+// StaticFS serves static files from fsys, such as an embed.FS, so they're
+// compiled into the binary. The path is the URL path prefix. Use fs.Sub to
+// serve a subdirectory of fsys.
+//
+// Example:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	assets, _ := fs.Sub(static, "static")
+//	app.StaticFS("/static", assets)
+func (a *App) StaticFS(path string, fsys fs.FS) {
+	a.StaticFSWithConfig(path, fsys, StaticConfig{})
+}
+
+// StaticFSWithConfig serves static files from fsys like StaticFS, with the
+// options of StaticWithConfig.
+func (a *App) StaticFSWithConfig(path string, fsys fs.FS, config StaticConfig) {
+	a.static(path, http.FS(fsys), config)
+}
+
+// StaticSPA serves a single-page app from dir at path: files are served as
+// usual, and other paths without a file extension get indexFile, so the
+// app's client-side router can handle them. Routes registered on the app,
+// such as /api handlers, take precedence, and unknown /api paths get a 404.
+// indexFile defaults to "index.html".
+//
+// Example:
+//
+//	app.Get("/api/users", listUsers)
+//	app.StaticSPA("/", "frontend/dist", "index.html")
+func (a *App) StaticSPA(path string, dir string, indexFile string) {
+	if indexFile == "" {
+		indexFile = "index.html"
+	}
+	a.StaticWithConfig(path, dir, StaticConfig{
+		SPAIndex:       indexFile,
+		DisableListing: true,
+	})
+}
+
+// static registers a handler serving root under path.
+func (a *App) static(path string, root http.FileSystem, config StaticConfig) {
+	if path == "" {
+		path = "/"
+	}
+	if path[0] != '/' {
+		path = "/" + path
+	}
+	if config.SPAIndex != "" && config.SPAExclude == nil {
+		config.SPAExclude = []string{"/api"}
+	}
+
+	// Ensure path ends with /* for catch-all matching
+	pattern := path
+	if pattern[len(pattern)-1] != '/' {
+		pattern += "/"
+	}
+	pattern += "*"
+
+	// Create a file server
+	fileServer := http.StripPrefix(path, &staticHandler{
+		prefix:     path,
+		root:       root,
+		config:     config,
+		fileServer: http.FileServer(root),
+	})
+
+	// Register the handler directly with chi
+	a.router.Get(pattern, func(w http.ResponseWriter, r *http.Request) {
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// precompressedEncodings are the encodings of precompressed siblings, in
+// order of preference, and their file extensions.
+var precompressedEncodings = []struct {
+	encoding, ext string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// staticHandler adds the StaticConfig options to an http.FileServer.
+type staticHandler struct {
+	prefix     string
+	root       http.FileSystem
+	config     StaticConfig
+	fileServer http.Handler
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)
+	info, err := h.stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && h.spaFallback(name) {
+			h.serveSPAIndex(w, r)
+			return
+		}
+		// Let the file server respond with the right error
+		h.fileServer.ServeHTTP(w, r)
+		return
+	}
+
+	if info.IsDir() {
+		index := path.Join(name, "index.html")
+		if _, err := h.stat(index); err == nil {
+			name = index
+		} else if h.spaFallback(name) {
+			h.serveSPAIndex(w, r)
+			return
+		} else if h.config.DisableListing {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	if cacheControl := h.cacheControl(name); cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+	if h.config.Precompressed && !info.IsDir() && h.servePrecompressed(w, r, name) {
+		return
+	}
+	h.fileServer.ServeHTTP(w, r)
+}
+
+// stat returns the file info of name in the root.
+func (h *staticHandler) stat(name string) (os.FileInfo, error) {
+	f, err := h.root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// spaFallback reports whether the missing file name gets the SPA index.
+func (h *staticHandler) spaFallback(name string) bool {
+	if h.config.SPAIndex == "" || path.Ext(name) != "" {
+		return false
+	}
+	full := path.Join(h.prefix, name)
+	for _, prefix := range h.config.SPAExclude {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if full == prefix || strings.HasPrefix(full, prefix+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// serveSPAIndex serves the SPA index file.
+func (h *staticHandler) serveSPAIndex(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + h.config.SPAIndex)
+	f, err := h.root.Open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if cacheControl := h.cacheControl(name); cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// cacheControl returns the Cache-Control header for the file name.
+func (h *staticHandler) cacheControl(name string) string {
+	if v, ok := h.config.CacheControl[path.Ext(name)]; ok {
+		return v
+	}
+	return h.config.DefaultCacheControl
+}
+
+// servePrecompressed serves the precompressed sibling of name the client
+// prefers, and reports whether there was one it accepts.
+func (h *staticHandler) servePrecompressed(w http.ResponseWriter, r *http.Request, name string) bool {
+	var available []CompressEncoder
+	var exts []string
+	for _, p := range precompressedEncodings {
+		if info, err := h.stat(name + p.ext); err == nil && !info.IsDir() {
+			available = append(available, CompressEncoder{Encoding: p.encoding})
+			exts = append(exts, p.ext)
+		}
+	}
+	if len(available) == 0 {
+		return false
+	}
+	addVary(w.Header(), "Accept-Encoding")
+
+	i := negotiateEncoding(r.Header.Get("Accept-Encoding"), available)
+	if i < 0 {
+		return false
+	}
+	f, err := h.root.Open(name + exts[i])
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Encoding", available[i].Encoding)
+	http.ServeContent(w, r, name, info.ModTime(), f)
+	return true
+}
@@ -0,0 +1,240 @@
+package nexo
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func writeStaticFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func getStatic(app *App, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func TestStaticWithConfig_CacheControl(t *testing.T) {
+	dir := writeStaticFiles(t, map[string]string{
+		"app.3f2a.js":     "console.log(1)",
+		"logo.png":        "png",
+		"docs/index.html": "<h1>Docs</h1>",
+	})
+	app := New()
+	app.DisableLogger()
+	app.StaticWithConfig("/static", dir, StaticConfig{
+		CacheControl: map[string]string{
+			".js":   CacheImmutable,
+			".html": "no-cache",
+		},
+		DefaultCacheControl: "public, max-age=3600",
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/static/app.3f2a.js", CacheImmutable},
+		{"/static/logo.png", "public, max-age=3600"},
+		{"/static/docs/", "no-cache"},
+		{"/static/missing.js", ""},
+	}
+	for _, tt := range tests {
+		w := getStatic(app, tt.path, "")
+		if got := w.Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: expected Cache-Control %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestStaticWithConfig_Precompressed(t *testing.T) {
+	dir := writeStaticFiles(t, map[string]string{
+		"app.js":       "plain",
+		"app.js.gz":    "gzipped",
+		"app.js.br":    "brotli",
+		"style.css":    "plain css",
+		"style.css.gz": "gzipped css",
+		"other.txt":    "no siblings",
+	})
+	app := New()
+	app.DisableLogger()
+	app.StaticWithConfig("/static", dir, StaticConfig{Precompressed: true})
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		body           string
+		encoding       string
+	}{
+		{"brotli preferred", "/static/app.js", "gzip, br", "brotli", "br"},
+		{"quality order", "/static/app.js", "br;q=0.5, gzip", "gzipped", "gzip"},
+		{"gzip only", "/static/style.css", "br, gzip", "gzipped css", "gzip"},
+		{"not accepted", "/static/app.js", "", "plain", ""},
+		{"no siblings", "/static/other.txt", "gzip", "no siblings", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getStatic(app, tt.path, tt.acceptEncoding)
+			if w.Code != http.StatusOK || w.Body.String() != tt.body {
+				t.Fatalf("expected 200 %q, got %d %q", tt.body, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("expected Content-Encoding %q, got %q", tt.encoding, got)
+			}
+		})
+	}
+
+	w := getStatic(app, "/static/app.js", "br")
+	if ct := w.Header().Get("Content-Type"); ct != mime.TypeByExtension(".js") {
+		t.Errorf("expected the original file's Content-Type, got %q", ct)
+	}
+	if w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %q", w.Header().Get("Vary"))
+	}
+}
+
+func TestStaticWithConfig_DisableListing(t *testing.T) {
+	dir := writeStaticFiles(t, map[string]string{
+		"images/logo.png": "png",
+		"site/index.html": "<h1>Site</h1>",
+	})
+
+	listing := New()
+	listing.DisableLogger()
+	listing.Static("/static", dir)
+	if w := getStatic(listing, "/static/images/", ""); w.Code != http.StatusOK {
+		t.Errorf("expected a listing by default, got %d", w.Code)
+	}
+
+	app := New()
+	app.DisableLogger()
+	app.StaticWithConfig("/static", dir, StaticConfig{DisableListing: true})
+	if w := getStatic(app, "/static/images/", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a directory, got %d", w.Code)
+	}
+	if w := getStatic(app, "/static/site/", ""); w.Code != http.StatusOK || w.Body.String() != "<h1>Site</h1>" {
+		t.Errorf("expected the index page, got %d %q", w.Code, w.Body.String())
+	}
+	if w := getStatic(app, "/static/images/logo.png", ""); w.Code != http.StatusOK {
+		t.Errorf("expected files to be served, got %d", w.Code)
+	}
+}
+
+func TestStaticSPA(t *testing.T) {
+	dir := writeStaticFiles(t, map[string]string{
+		"index.html":       "<div id=app></div>",
+		"assets/app.js":    "app",
+		"docs/index.html":  "<h1>Docs</h1>",
+		"images/empty.txt": "",
+	})
+	app := New()
+	app.DisableLogger()
+	app.Get("/api/users", func(c *Context) error {
+		return c.JSON(http.StatusOK, []string{"ana"})
+	})
+	app.StaticSPA("/", dir, "")
+	app.Mount()
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"index", "/", http.StatusOK, "<div id=app></div>"},
+		{"client route", "/settings/profile", http.StatusOK, "<div id=app></div>"},
+		{"asset", "/assets/app.js", http.StatusOK, "app"},
+		{"missing asset", "/assets/missing.js", http.StatusNotFound, ""},
+		{"directory index", "/docs/", http.StatusOK, "<h1>Docs</h1>"},
+		{"directory without index", "/images/", http.StatusOK, "<div id=app></div>"},
+		{"api route", "/api/users", http.StatusOK, `["ana"]`},
+		{"unknown api route", "/api/nope", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getStatic(app, tt.path, "")
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d %q", tt.status, w.Code, w.Body.String())
+			}
+			if tt.body != "" && strings.TrimSpace(w.Body.String()) != tt.body {
+				t.Errorf("expected %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+
+	if w := getStatic(app, "/settings", ""); !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("expected the index's Content-Type, got %q", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestStaticWithConfig_SPAExclude(t *testing.T) {
+	dir := writeStaticFiles(t, map[string]string{"index.html": "app"})
+	app := New()
+	app.DisableLogger()
+	app.StaticWithConfig("/app", dir, StaticConfig{
+		SPAIndex:   "index.html",
+		SPAExclude: []string{"/app/internal"},
+	})
+
+	if w := getStatic(app, "/app/dashboard", ""); w.Code != http.StatusOK || w.Body.String() != "app" {
+		t.Errorf("expected the index, got %d %q", w.Code, w.Body.String())
+	}
+	if w := getStatic(app, "/app/internal/x", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an excluded path, got %d", w.Code)
+	}
+}
+
+func TestStaticFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/css/app.css":    {Data: []byte("body{}")},
+		"static/css/app.css.gz": {Data: []byte("gzipped")},
+	}
+	assets, err := fs.Sub(fsys, "static")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := New()
+	app.DisableLogger()
+	app.StaticFS("/assets", assets)
+	app.StaticFSWithConfig("/cached", assets, StaticConfig{
+		DefaultCacheControl: CacheImmutable,
+		Precompressed:       true,
+	})
+
+	if w := getStatic(app, "/assets/css/app.css", "gzip"); w.Code != http.StatusOK || w.Body.String() != "body{}" {
+		t.Errorf("expected the embedded file, got %d %q", w.Code, w.Body.String())
+	}
+	if w := getStatic(app, "/assets/missing.css", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+
+	w := getStatic(app, "/cached/css/app.css", "gzip")
+	if w.Body.String() != "gzipped" || w.Header().Get("Cache-Control") != CacheImmutable {
+		t.Errorf("expected the config to apply, got %q with Cache-Control %q", w.Body.String(), w.Header().Get("Cache-Control"))
+	}
+}
@@ -0,0 +1,73 @@
+package nexo
+
+import "fmt"
+
+// GetAs retrieves a value stored with c.Set as T. ok is false if the key
+// doesn't exist or holds another type.
+//
+// Example:
+//
+//	user, ok := nexo.GetAs[*User](c, "user")
+//	if !ok {
+//	    return nexo.Unauthorized("login required")
+//	}
+func GetAs[T any](c *Context, key string) (T, bool) {
+	val, ok := c.store[key].(T)
+	return val, ok
+}
+
+// Key is a typed, collision-free key for the context store. Two keys never
+// share a value, even with the same name, so packages and middleware can
+// store values without agreeing on key strings. Create keys once, at
+// package level, with NewKey.
+//
+// Example:
+//
+//	var CurrentUser = nexo.NewKey[*User]("auth.user")
+//
+//	// In middleware
+//	CurrentUser.Set(c, user)
+//
+//	// In a handler
+//	user, ok := CurrentUser.Get(c)
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new key. name is only used in messages.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key's name.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// Set stores value under the key.
+func (k *Key[T]) Set(c *Context, value T) {
+	if c.keyed == nil {
+		c.keyed = make(map[any]any)
+	}
+	c.keyed[k] = value
+}
+
+// Get returns the value stored under the key. ok is false if none was set.
+func (k *Key[T]) Get(c *Context) (value T, ok bool) {
+	value, ok = c.keyed[k].(T)
+	return value, ok
+}
+
+// MustGet returns the value stored under the key or panics if none was set.
+func (k *Key[T]) MustGet(c *Context) T {
+	value, ok := k.Get(c)
+	if !ok {
+		panic(fmt.Sprintf("key %q not found in context", k.name))
+	}
+	return value
+}
+
+// Delete removes the value stored under the key.
+func (k *Key[T]) Delete(c *Context) {
+	delete(c.keyed, k)
+}
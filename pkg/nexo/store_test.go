@@ -0,0 +1,98 @@
+package nexo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type storeUser struct{ Name string }
+
+func newStoreContext() *Context {
+	return NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestGetAs(t *testing.T) {
+	c := newStoreContext()
+	c.Set("user", &storeUser{Name: "ada"})
+	c.Set("count", 3)
+
+	if user, ok := GetAs[*storeUser](c, "user"); !ok || user.Name != "ada" {
+		t.Errorf("GetAs[*storeUser] = %v, %v", user, ok)
+	}
+	if count, ok := GetAs[int](c, "count"); !ok || count != 3 {
+		t.Errorf("GetAs[int] = %v, %v", count, ok)
+	}
+	if _, ok := GetAs[string](c, "count"); ok {
+		t.Error("GetAs with the wrong type should not be ok")
+	}
+	if _, ok := GetAs[int](c, "missing"); ok {
+		t.Error("GetAs with a missing key should not be ok")
+	}
+}
+
+func TestContext_TypedGetters(t *testing.T) {
+	c := newStoreContext()
+	now := time.Now()
+	c.Set("id", int64(42))
+	c.Set("ratio", 0.5)
+	c.Set("at", now)
+	c.Set("ttl", time.Minute)
+	c.Set("wrong", "string")
+
+	if got := c.GetInt64("id"); got != 42 {
+		t.Errorf("GetInt64 = %d", got)
+	}
+	if got := c.GetFloat64("ratio"); got != 0.5 {
+		t.Errorf("GetFloat64 = %v", got)
+	}
+	if got := c.GetTime("at"); !got.Equal(now) {
+		t.Errorf("GetTime = %v", got)
+	}
+	if got := c.GetDuration("ttl"); got != time.Minute {
+		t.Errorf("GetDuration = %v", got)
+	}
+	if c.GetInt64("wrong") != 0 || c.GetFloat64("wrong") != 0 || !c.GetTime("wrong").IsZero() || c.GetDuration("wrong") != 0 {
+		t.Error("Expected zero values for mismatched types")
+	}
+}
+
+func TestKey(t *testing.T) {
+	c := newStoreContext()
+	authUser := NewKey[*storeUser]("user")
+	otherUser := NewKey[*storeUser]("user")
+
+	if _, ok := authUser.Get(c); ok {
+		t.Error("Get before Set should not be ok")
+	}
+
+	authUser.Set(c, &storeUser{Name: "ada"})
+	otherUser.Set(c, &storeUser{Name: "grace"})
+	c.Set("user", "plain")
+
+	if user, ok := authUser.Get(c); !ok || user.Name != "ada" {
+		t.Errorf("authUser.Get = %v, %v", user, ok)
+	}
+	if user := otherUser.MustGet(c); user.Name != "grace" {
+		t.Errorf("otherUser.MustGet = %v", user)
+	}
+	if c.GetString("user") != "plain" {
+		t.Error("typed keys should not collide with string keys")
+	}
+	if authUser.String() != "user" {
+		t.Errorf("String() = %q", authUser.String())
+	}
+
+	authUser.Delete(c)
+	if _, ok := authUser.Get(c); ok {
+		t.Error("Get after Delete should not be ok")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGet of a missing key should panic")
+		}
+	}()
+	authUser.MustGet(c)
+}
@@ -0,0 +1,102 @@
+package nexo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// validatorKey is the request context key for the app's validator.
+type validatorKey struct{}
+
+// Validator validates values decoded by Bind, BindQuery and BindForm. Set
+// one with WithValidator.
+//
+// Validate returns nil if v is valid. To report which fields are invalid,
+// return a *ValidationError; any other error is reported as a single
+// violation without a field.
+type Validator interface {
+	Validate(v any) error
+}
+
+// ValidatorFunc adapts a function to the Validator interface.
+type ValidatorFunc func(v any) error
+
+// Validate calls f(v).
+func (f ValidatorFunc) Validate(v any) error {
+	return f(v)
+}
+
+// FieldError describes one invalid field.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists the fields that failed validation. Returned from a
+// handler, it is sent as a 422 response with the violations under
+// "error.fields".
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		if f.Field == "" {
+			msgs[i] = f.Message
+		} else {
+			msgs[i] = f.Field + ": " + f.Message
+		}
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Add appends a violation for field.
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Err returns e if it has violations and nil otherwise, so a validator can
+// collect violations and return e.Err().
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// IsValidationError checks if an error is a ValidationError and returns it.
+func IsValidationError(err error) (*ValidationError, bool) {
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		return valErr, true
+	}
+	return nil, false
+}
+
+// withValidator adds the validator to the request context, where Context's
+// bind methods find it.
+func withValidator(r *http.Request, v Validator) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), validatorKey{}, v))
+}
+
+// validate runs the app's validator, if any, on v. Failures are returned as
+// a 422 HTTPError wrapping a *ValidationError.
+func (c *Context) validate(v any) error {
+	validator, ok := c.Request.Context().Value(validatorKey{}).(Validator)
+	if !ok || validator == nil {
+		return nil
+	}
+	err := validator.Validate(v)
+	if err == nil {
+		return nil
+	}
+	valErr, ok := IsValidationError(err)
+	if !ok {
+		valErr = &ValidationError{Fields: []FieldError{{Message: err.Error()}}}
+	}
+	return NewHTTPErrorWithCause(http.StatusUnprocessableEntity, "validation failed", valErr)
+}
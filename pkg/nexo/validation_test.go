@@ -0,0 +1,184 @@
+package nexo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type createUser struct {
+	Name  string `json:"name" form:"name"`
+	Email string `json:"email" form:"email"`
+	Page  int    `query:"page"`
+}
+
+// validateUser requires a name and an email containing "@".
+var validateUser = ValidatorFunc(func(v any) error {
+	u, ok := v.(*createUser)
+	if !ok {
+		return nil
+	}
+	errs := &ValidationError{}
+	if u.Name == "" {
+		errs.Add("name", "is required")
+	}
+	if !strings.Contains(u.Email, "@") {
+		errs.Add("email", "must be an email address")
+	}
+	if u.Page < 0 {
+		errs.Add("page", "must not be negative")
+	}
+	return errs.Err()
+})
+
+func newValidationApp(v Validator) *App {
+	app := New(WithValidator(v))
+	app.DisableLogger()
+	app.Post("/users", func(c *Context) error {
+		var u createUser
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, u)
+	})
+	app.Post("/form", func(c *Context) error {
+		var u createUser
+		if err := c.BindForm(&u); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, u)
+	})
+	app.Get("/users", func(c *Context) error {
+		var u createUser
+		if err := c.BindQuery(&u); err != nil {
+			return err
+		}
+		return c.NoContent()
+	})
+	app.Mount()
+	return app
+}
+
+type errorBody struct {
+	Error struct {
+		Code    int          `json:"code"`
+		Message string       `json:"message"`
+		Fields  []FieldError `json:"fields"`
+	} `json:"error"`
+}
+
+func TestValidation_Bind(t *testing.T) {
+	app := newValidationApp(validateUser)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"ada","email":"ada@example.com"}`))
+	r.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("valid body: status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"email":"nope"}`))
+	r.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(w, r)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid body: status = %d, want 422", w.Code)
+	}
+
+	var body errorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := []FieldError{{"name", "is required"}, {"email", "must be an email address"}}
+	if body.Error.Code != http.StatusUnprocessableEntity || body.Error.Message != "validation failed" {
+		t.Errorf("error = %+v", body.Error)
+	}
+	if len(body.Error.Fields) != len(want) || body.Error.Fields[0] != want[0] || body.Error.Fields[1] != want[1] {
+		t.Errorf("fields = %+v, want %+v", body.Error.Fields, want)
+	}
+}
+
+func TestValidation_BindFormAndQuery(t *testing.T) {
+	app := newValidationApp(validateUser)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader("name=ada"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.ServeHTTP(w, r)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"field":"email"`) {
+		t.Errorf("BindForm: status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?page=-1", nil))
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"field":"page"`) {
+		t.Errorf("BindQuery: status = %d: %s", w.Code, w.Body.String())
+	}
+
+	// Conversion errors are still 400s and skip validation
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?page=x", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("BindQuery conversion error: status = %d, want 400", w.Code)
+	}
+}
+
+func TestValidation_PlainError(t *testing.T) {
+	app := newValidationApp(ValidatorFunc(func(v any) error {
+		return errors.New("name is required")
+	}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+	app.ServeHTTP(w, r)
+
+	var body errorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusUnprocessableEntity || len(body.Error.Fields) != 1 ||
+		body.Error.Fields[0] != (FieldError{Message: "name is required"}) {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestValidation_NoValidator(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	var u createUser
+	if err := NewContext(httptest.NewRecorder(), r).Bind(&u); err != nil {
+		t.Errorf("Bind() without a validator error = %v", err)
+	}
+}
+
+func TestValidationError_ReturnedFromHandler(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Post("/check", func(c *Context) error {
+		errs := &ValidationError{}
+		errs.Add("slug", "is taken")
+		return errs.Err()
+	})
+	app.Mount()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/check", nil))
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"fields":[{"field":"slug","message":"is taken"}]`) {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	errs := &ValidationError{}
+	if errs.Err() != nil {
+		t.Error("Err() with no violations should be nil")
+	}
+	errs.Add("name", "is required")
+	errs.Fields = append(errs.Fields, FieldError{Message: "too many requests"})
+	if got, want := errs.Error(), "validation failed: name: is required; too many requests"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,531 @@
+package nexo
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// websocketGUID is appended to the client key to compute the accept key
+// (RFC 6455, section 1.3).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// DefaultWebSocketMaxMessageSize is the largest message a WebSocketConn
+// reads unless WebSocketConfig.MaxMessageSize is set.
+const DefaultWebSocketMaxMessageSize = 1 << 20
+
+// WebSocket message types.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// Frame opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// WebSocket close codes (RFC 6455, section 7.4.1).
+const (
+	CloseNormalClosure    = 1000
+	CloseGoingAway        = 1001
+	CloseProtocolError    = 1002
+	CloseUnsupportedData  = 1003
+	CloseNoStatusReceived = 1005
+	CloseInvalidPayload   = 1007
+	ClosePolicyViolation  = 1008
+	CloseMessageTooBig    = 1009
+	CloseInternalError    = 1011
+)
+
+// WebSocketHandler handles an upgraded WebSocket connection. The connection
+// is closed when the handler returns.
+type WebSocketHandler func(ws *WebSocketConn) error
+
+// WebSocketConfig configures a WebSocket upgrade.
+type WebSocketConfig struct {
+	// CheckOrigin reports whether the request's Origin is allowed. The
+	// default allows requests without an Origin header and requests whose
+	// Origin host matches the Host header.
+	CheckOrigin func(r *http.Request) bool
+
+	// Subprotocols lists the supported subprotocols in order of
+	// preference. The first one the client also offers is selected.
+	Subprotocols []string
+
+	// MaxMessageSize is the largest message to read, in bytes. Default:
+	// DefaultWebSocketMaxMessageSize.
+	MaxMessageSize int64
+}
+
+// CloseError is returned by the read methods when the peer closes the
+// connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: closed with code %d: %s", e.Code, e.Reason)
+}
+
+// IsCloseError reports whether err is a CloseError with one of the given
+// codes, or any CloseError if no codes are given.
+func IsCloseError(err error, codes ...int) bool {
+	var closeErr *CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+	if len(codes) == 0 {
+		return true
+	}
+	for _, code := range codes {
+		if closeErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// WebSocketConn is an upgraded WebSocket connection. Reads must come from
+// one goroutine; writes may come from several. Pings are answered
+// automatically while reading.
+type WebSocketConn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	request     *http.Request
+	subprotocol string
+	maxSize     int64
+
+	writeMu   sync.Mutex
+	bw        *bufio.Writer
+	closeSent bool
+}
+
+// WebSocket upgrades the request to a WebSocket connection and runs handler
+// with it. The connection is closed when handler returns: normally if it
+// returns nil, and with an internal error code otherwise.
+//
+// Requests that aren't valid WebSocket handshakes get a 400 HTTPError, and
+// requests from another origin a 403 (see WebSocketConfig.CheckOrigin).
+//
+// Example:
+//
+//	func Get(c *nexo.Context) error {
+//	    return c.WebSocket(func(ws *nexo.WebSocketConn) error {
+//	        for {
+//	            var msg Message
+//	            if err := ws.ReadJSON(&msg); err != nil {
+//	                if nexo.IsCloseError(err) {
+//	                    return nil // Client went away
+//	                }
+//	                return err
+//	            }
+//	            if err := ws.WriteJSON(msg); err != nil {
+//	                return err
+//	            }
+//	        }
+//	    })
+//	}
+func (c *Context) WebSocket(handler WebSocketHandler, config ...WebSocketConfig) error {
+	cfg := WebSocketConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.CheckOrigin == nil {
+		cfg.CheckOrigin = sameOrigin
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultWebSocketMaxMessageSize
+	}
+
+	r := c.Request
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") {
+		return BadRequest("websocket: not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		c.SetHeader("Sec-WebSocket-Version", "13")
+		return BadRequest("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return BadRequest("websocket: invalid Sec-WebSocket-Key")
+	}
+	if !cfg.CheckOrigin(r) {
+		return Forbidden("websocket: origin not allowed")
+	}
+
+	conn, brw, err := http.NewResponseController(c.Response).Hijack()
+	if err != nil {
+		return NewHTTPErrorWithCause(http.StatusInternalServerError, "websocket: upgrade not supported", err)
+	}
+	c.written = true
+	c.status = http.StatusSwitchingProtocols
+
+	ws := &WebSocketConn{
+		conn:        conn,
+		br:          brw.Reader,
+		bw:          brw.Writer,
+		request:     r,
+		subprotocol: selectSubprotocol(r, cfg.Subprotocols),
+		maxSize:     cfg.MaxMessageSize,
+	}
+
+	var resp strings.Builder
+	resp.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	resp.WriteString("Upgrade: websocket\r\n")
+	resp.WriteString("Connection: Upgrade\r\n")
+	resp.WriteString("Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n")
+	if ws.subprotocol != "" {
+		resp.WriteString("Sec-WebSocket-Protocol: " + ws.subprotocol + "\r\n")
+	}
+	resp.WriteString("\r\n")
+	_ = conn.SetDeadline(time.Time{})
+	if _, err := ws.bw.WriteString(resp.String()); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if err := ws.bw.Flush(); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	handlerErr := handler(ws)
+	if handlerErr != nil && !IsCloseError(handlerErr) {
+		_ = ws.CloseWithReason(CloseInternalError, "")
+		return handlerErr
+	}
+	_ = ws.Close()
+	return nil
+}
+
+// SocketFunc handles a WebSocket connection along with the request that
+// opened it, like the Socket function of a ws.go file.
+type SocketFunc func(c *Context, ws *WebSocketConn) error
+
+// SocketHandler returns a handler that upgrades requests to WebSocket and
+// runs socket with the connection. Routes generated for ws.go files use it.
+//
+// Example:
+//
+//	app.Get("/chat", nexo.SocketHandler(func(c *nexo.Context, ws *nexo.WebSocketConn) error {
+//	    return ws.WriteText("hello " + c.Query("name"))
+//	}))
+func SocketHandler(socket SocketFunc, config ...WebSocketConfig) HandlerFunc {
+	return func(c *Context) error {
+		return c.WebSocket(func(ws *WebSocketConn) error {
+			return socket(c, ws)
+		}, config...)
+	}
+}
+
+// Request returns the upgraded HTTP request.
+func (ws *WebSocketConn) Request() *http.Request {
+	return ws.request
+}
+
+// Subprotocol returns the negotiated subprotocol, or "".
+func (ws *WebSocketConn) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// RemoteAddr returns the client's network address.
+func (ws *WebSocketConn) RemoteAddr() net.Addr {
+	return ws.conn.RemoteAddr()
+}
+
+// SetReadDeadline sets the deadline for reads; zero means no deadline.
+func (ws *WebSocketConn) SetReadDeadline(t time.Time) error {
+	return ws.conn.SetReadDeadline(t)
+}
+
+// ReadMessage reads the next text or binary message. It returns a
+// *CloseError when the client closes the connection.
+func (ws *WebSocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			return 0, nil, ws.handleClose(payload)
+		case opText, opBinary:
+			if messageType != 0 {
+				return 0, nil, ws.fail(CloseProtocolError, "expected continuation frame")
+			}
+			messageType = int(opcode)
+		case opContinuation:
+			if messageType == 0 {
+				return 0, nil, ws.fail(CloseProtocolError, "unexpected continuation frame")
+			}
+		default:
+			return 0, nil, ws.fail(CloseProtocolError, "unknown opcode")
+		}
+
+		if int64(len(message)+len(payload)) > ws.maxSize {
+			return 0, nil, ws.fail(CloseMessageTooBig, "message too big")
+		}
+		message = append(message, payload...)
+
+		if fin {
+			if messageType == TextMessage && !utf8.Valid(message) {
+				return 0, nil, ws.fail(CloseInvalidPayload, "invalid UTF-8")
+			}
+			return messageType, message, nil
+		}
+	}
+}
+
+// ReadText reads the next message as a string.
+func (ws *WebSocketConn) ReadText() (string, error) {
+	_, data, err := ws.ReadMessage()
+	return string(data), err
+}
+
+// ReadJSON reads the next message and decodes it as JSON into v.
+func (ws *WebSocketConn) ReadJSON(v any) error {
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("websocket: invalid JSON message: %w", err)
+	}
+	return nil
+}
+
+// WriteMessage sends a text or binary message.
+func (ws *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", messageType)
+	}
+	return ws.writeFrame(byte(messageType), data)
+}
+
+// WriteText sends a text message.
+func (ws *WebSocketConn) WriteText(text string) error {
+	return ws.writeFrame(opText, []byte(text))
+}
+
+// WriteJSON sends v encoded as JSON in a text message.
+func (ws *WebSocketConn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("websocket: failed to marshal JSON: %w", err)
+	}
+	return ws.writeFrame(opText, data)
+}
+
+// Ping sends a ping; the client's pong is consumed by the read methods.
+func (ws *WebSocketConn) Ping(data []byte) error {
+	return ws.writeFrame(opPing, data)
+}
+
+// Close sends a normal close frame and closes the connection.
+func (ws *WebSocketConn) Close() error {
+	return ws.CloseWithReason(CloseNormalClosure, "")
+}
+
+// CloseWithReason sends a close frame with code and reason, then closes
+// the connection.
+func (ws *WebSocketConn) CloseWithReason(code int, reason string) error {
+	_ = ws.writeClose(code, reason)
+	return ws.conn.Close()
+}
+
+// fail closes the connection after a protocol violation and returns the
+// matching CloseError.
+func (ws *WebSocketConn) fail(code int, reason string) error {
+	_ = ws.CloseWithReason(code, reason)
+	return &CloseError{Code: code, Reason: reason}
+}
+
+// handleClose answers a close frame from the client and returns it as a
+// CloseError.
+func (ws *WebSocketConn) handleClose(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatusReceived}
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+	}
+	code := closeErr.Code
+	if code == CloseNoStatusReceived {
+		code = CloseNormalClosure
+	}
+	_ = ws.writeClose(code, "")
+	_ = ws.conn.Close()
+	return closeErr
+}
+
+// writeClose sends a close frame unless one was already sent.
+func (ws *WebSocketConn) writeClose(code int, reason string) error {
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	return ws.writeFrame(opClose, payload)
+}
+
+// readFrame reads one frame and unmasks its payload.
+func (ws *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, ws.fail(CloseProtocolError, "reserved bits set")
+	}
+	if !masked {
+		return false, 0, nil, ws.fail(CloseProtocolError, "client frames must be masked")
+	}
+	if opcode >= opClose && (!fin || length > 125) {
+		return false, 0, nil, ws.fail(CloseProtocolError, "invalid control frame")
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	if length < 0 || length > ws.maxSize {
+		return false, 0, nil, ws.fail(CloseMessageTooBig, "message too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(ws.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single unmasked frame.
+func (ws *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+
+	if ws.closeSent {
+		return &CloseError{Code: CloseNormalClosure, Reason: "connection closed"}
+	}
+	if opcode == opClose {
+		ws.closeSent = true
+	}
+
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if _, err := ws.bw.Write(header); err != nil {
+		return err
+	}
+	if _, err := ws.bw.Write(payload); err != nil {
+		return err
+	}
+	return ws.bw.Flush()
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept value for key.
+func websocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContainsToken reports whether a comma-separated header contains
+// token, ignoring case.
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectSubprotocol returns the first supported subprotocol the client
+// offers.
+func selectSubprotocol(r *http.Request, supported []string) string {
+	for _, s := range supported {
+		if headerContainsToken(r.Header, "Sec-WebSocket-Protocol", s) {
+			return s
+		}
+	}
+	return ""
+}
+
+// sameOrigin allows requests without an Origin header and requests whose
+// Origin host matches the Host header.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
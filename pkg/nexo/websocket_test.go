@@ -0,0 +1,348 @@
+package nexo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testWSClient is a minimal WebSocket client for tests.
+type testWSClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+	resp *http.Response
+}
+
+const testWSKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+func dialWebSocket(t *testing.T, server *httptest.Server, path string, header http.Header) *testWSClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", testWSKey)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testWSClient{conn: conn, br: br, resp: resp}
+}
+
+// write sends a masked frame.
+func (c *testWSClient) write(t *testing.T, fin bool, opcode byte, payload []byte) {
+	t.Helper()
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read reads one unmasked server frame.
+func (c *testWSClient) read(t *testing.T) (opcode byte, payload []byte) {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	length := int(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, _ = io.ReadFull(c.br, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, _ = io.ReadFull(c.br, ext[:])
+		length = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+func closePayload(code int, reason string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(code)), reason...)
+}
+
+func newWebSocketServer(t *testing.T, handler WebSocketHandler, config ...WebSocketConfig) *httptest.Server {
+	t.Helper()
+	app := New()
+	app.DisableLogger()
+	app.Get("/ws", func(c *Context) error {
+		return c.WebSocket(handler, config...)
+	})
+	app.Mount()
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocket_Echo(t *testing.T) {
+	server := newWebSocketServer(t, func(ws *WebSocketConn) error {
+		for {
+			var msg map[string]string
+			if err := ws.ReadJSON(&msg); err != nil {
+				if IsCloseError(err, CloseNormalClosure) {
+					return nil
+				}
+				return err
+			}
+			msg["echo"] = "true"
+			if err := ws.WriteJSON(msg); err != nil {
+				return err
+			}
+		}
+	})
+
+	client := dialWebSocket(t, server, "/ws", nil)
+	if client.resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", client.resp.StatusCode)
+	}
+	if got := client.resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept = %q", got)
+	}
+
+	client.write(t, true, opText, []byte(`{"name":"ada"}`))
+	opcode, payload := client.read(t)
+	if opcode != opText || string(payload) != `{"echo":"true","name":"ada"}` {
+		t.Errorf("got opcode %d payload %s", opcode, payload)
+	}
+
+	// Fragmented message with a ping in between
+	client.write(t, false, opText, []byte(`{"name":`))
+	client.write(t, true, opPing, []byte("hi"))
+	client.write(t, true, opContinuation, []byte(`"grace"}`))
+	if opcode, payload := client.read(t); opcode != opPong || string(payload) != "hi" {
+		t.Errorf("expected pong, got opcode %d payload %q", opcode, payload)
+	}
+	if _, payload := client.read(t); string(payload) != `{"echo":"true","name":"grace"}` {
+		t.Errorf("fragmented echo = %s", payload)
+	}
+
+	// Large message uses the extended length
+	large := `{"name":"` + strings.Repeat("x", 70000) + `"}`
+	client.write(t, true, opText, []byte(large))
+	if _, payload := client.read(t); len(payload) != len(large)+len(`"echo":"true",`) {
+		t.Errorf("large echo length = %d", len(payload))
+	}
+
+	client.write(t, true, opClose, closePayload(CloseNormalClosure, "bye"))
+	opcode, payload = client.read(t)
+	if opcode != opClose || binary.BigEndian.Uint16(payload) != CloseNormalClosure {
+		t.Errorf("expected normal close, got opcode %d payload %v", opcode, payload)
+	}
+}
+
+func TestSocketHandler(t *testing.T) {
+	app := New()
+	app.DisableLogger()
+	app.Get("/chat/{room}", SocketHandler(func(c *Context, ws *WebSocketConn) error {
+		return ws.WriteText("joined " + c.Param("room"))
+	}))
+	app.Mount()
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+
+	client := dialWebSocket(t, server, "/chat/general", nil)
+	if client.resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", client.resp.StatusCode)
+	}
+	if opcode, payload := client.read(t); opcode != opText || string(payload) != "joined general" {
+		t.Errorf("got opcode %d payload %q", opcode, payload)
+	}
+	if opcode, payload := client.read(t); opcode != opClose || binary.BigEndian.Uint16(payload) != CloseNormalClosure {
+		t.Errorf("expected normal close, got opcode %d payload %v", opcode, payload)
+	}
+}
+
+func TestWebSocket_HandlerErrorClosesWithInternalError(t *testing.T) {
+	server := newWebSocketServer(t, func(ws *WebSocketConn) error {
+		if err := ws.WriteText("hello"); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+
+	client := dialWebSocket(t, server, "/ws", nil)
+	if _, payload := client.read(t); string(payload) != "hello" {
+		t.Errorf("payload = %q", payload)
+	}
+	opcode, payload := client.read(t)
+	if opcode != opClose || binary.BigEndian.Uint16(payload) != CloseInternalError {
+		t.Errorf("expected internal error close, got opcode %d payload %v", opcode, payload)
+	}
+}
+
+func TestWebSocket_ProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		send     func(t *testing.T, c *testWSClient)
+		wantCode int
+	}{
+		{"invalid utf-8", func(t *testing.T, c *testWSClient) {
+			c.write(t, true, opText, []byte{0xff, 0xfe})
+		}, CloseInvalidPayload},
+		{"too big", func(t *testing.T, c *testWSClient) {
+			c.write(t, true, opBinary, make([]byte, 200))
+		}, CloseMessageTooBig},
+		{"unmasked", func(t *testing.T, c *testWSClient) {
+			_, _ = c.conn.Write([]byte{0x81, 0x01, 'a'})
+		}, CloseProtocolError},
+		{"unexpected continuation", func(t *testing.T, c *testWSClient) {
+			c.write(t, true, opContinuation, []byte("a"))
+		}, CloseProtocolError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readErr := make(chan error, 1)
+			server := newWebSocketServer(t, func(ws *WebSocketConn) error {
+				_, _, err := ws.ReadMessage()
+				readErr <- err
+				return err
+			}, WebSocketConfig{MaxMessageSize: 100})
+
+			client := dialWebSocket(t, server, "/ws", nil)
+			tt.send(t, client)
+
+			opcode, payload := client.read(t)
+			if opcode != opClose || int(binary.BigEndian.Uint16(payload)) != tt.wantCode {
+				t.Errorf("got opcode %d payload %v, want close %d", opcode, payload, tt.wantCode)
+			}
+			if err := <-readErr; !IsCloseError(err, tt.wantCode) {
+				t.Errorf("ReadMessage() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestWebSocket_Handshake(t *testing.T) {
+	server := newWebSocketServer(t, func(ws *WebSocketConn) error {
+		return ws.WriteText(ws.Subprotocol())
+	}, WebSocketConfig{Subprotocols: []string{"graphql-ws", "chat"}})
+
+	t.Run("subprotocol", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/ws", http.Header{"Sec-Websocket-Protocol": {"chat, graphql-ws"}})
+		if got := client.resp.Header.Get("Sec-WebSocket-Protocol"); got != "graphql-ws" {
+			t.Errorf("Sec-WebSocket-Protocol = %q", got)
+		}
+		if _, payload := client.read(t); string(payload) != "graphql-ws" {
+			t.Errorf("Subprotocol() = %q", payload)
+		}
+	})
+
+	t.Run("same origin", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/ws", http.Header{"Origin": {server.URL}})
+		if client.resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Errorf("status = %d, want 101", client.resp.StatusCode)
+		}
+	})
+
+	t.Run("cross origin", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/ws", http.Header{"Origin": {"https://evil.example"}})
+		if client.resp.StatusCode != http.StatusForbidden {
+			t.Errorf("status = %d, want 403", client.resp.StatusCode)
+		}
+	})
+
+	t.Run("bad version", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/ws", http.Header{"Sec-Websocket-Version": {"8"}})
+		if client.resp.StatusCode != http.StatusBadRequest || client.resp.Header.Get("Sec-WebSocket-Version") != "13" {
+			t.Errorf("status = %d, version header = %q", client.resp.StatusCode, client.resp.Header.Get("Sec-WebSocket-Version"))
+		}
+	})
+
+	t.Run("plain request", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/ws")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", resp.StatusCode)
+		}
+	})
+}
+
+func TestWebSocket_NotHijackable(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Sec-WebSocket-Version", "13")
+	r.Header.Set("Sec-WebSocket-Key", testWSKey)
+
+	err := NewContext(httptest.NewRecorder(), r).WebSocket(func(ws *WebSocketConn) error { return nil })
+	if httpErr, ok := IsHTTPError(err); !ok || httpErr.Code != http.StatusInternalServerError {
+		t.Errorf("WebSocket() error = %v, want a 500", err)
+	}
+}
+
+func TestResponseWriter_HijackStatus(t *testing.T) {
+	status := make(chan int, 1)
+	app := New()
+	app.DisableLogger()
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			err := next(c)
+			if rw, ok := c.Response.(*responseWriter); ok {
+				status <- rw.Status()
+			}
+			return err
+		}
+	})
+	app.Get("/ws", func(c *Context) error {
+		return c.WebSocket(func(ws *WebSocketConn) error { return nil })
+	})
+	app.Mount()
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	client := dialWebSocket(t, server, "/ws", nil)
+	client.read(t) // Close frame
+	if got := <-status; got != http.StatusSwitchingProtocols {
+		t.Errorf("logged status = %d, want 101", got)
+	}
+}
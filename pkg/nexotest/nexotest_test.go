@@ -0,0 +1,145 @@
+package nexotest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+	"github.com/abdul-hamid-achik/nexo/pkg/nexotest"
+)
+
+// recorder is a testing.TB that records failures instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newTestApp() *nexo.App {
+	app := nexo.New()
+	app.DisableLogger()
+	app.Get("/api/users", func(c *nexo.Context) error {
+		return c.JSON(http.StatusOK, map[string]any{
+			"page":  c.QueryInt("page", 1),
+			"users": []map[string]any{{"id": 1, "name": "Ada"}, {"id": 2, "name": "Grace"}},
+		})
+	})
+	app.Post("/api/users", func(c *nexo.Context) error {
+		var input struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind(&input); err != nil {
+			return nexo.BadRequest("invalid JSON")
+		}
+		return c.JSON(http.StatusCreated, map[string]string{"name": input.Name})
+	})
+	app.Post("/login", func(c *nexo.Context) error {
+		if c.FormValue("password") != "secret" {
+			return c.String(http.StatusUnauthorized, "wrong password")
+		}
+		c.SetCookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		return c.Redirect("/dashboard", http.StatusSeeOther)
+	})
+	app.Get("/me", func(c *nexo.Context) error {
+		session := c.Cookie("session")
+		if session == "" || c.Header("Authorization") != "Bearer token" {
+			return c.String(http.StatusUnauthorized, "unauthorized")
+		}
+		return c.String(http.StatusOK, "hello "+session)
+	})
+	app.Mount()
+	return app
+}
+
+func TestRequest_Run(t *testing.T) {
+	app := newTestApp()
+
+	nexotest.Get("/api/users").
+		WithQuery("page", "2").
+		Run(app).
+		AssertStatus(t, http.StatusOK).
+		AssertHeaderContains(t, "Content-Type", "application/json").
+		AssertJSONPath(t, "page", 2).
+		AssertJSONPath(t, "users.1.name", "Grace").
+		AssertJSONPath(t, "users[0].id", 1).
+		AssertJSONPath(t, "users.0", map[string]any{"id": 1, "name": "Ada"})
+
+	nexotest.Post("/api/users").
+		WithJSON(map[string]string{"name": "Ada"}).
+		Run(app).
+		AssertStatus(t, http.StatusCreated).
+		AssertJSON(t, `{"name": "Ada"}`).
+		AssertJSON(t, struct {
+			Name string `json:"name"`
+		}{"Ada"})
+
+	nexotest.Post("/login").
+		WithForm(url.Values{"password": {"secret"}}).
+		Run(app).
+		AssertStatus(t, http.StatusSeeOther).
+		AssertHeader(t, "Location", "/dashboard").
+		AssertCookie(t, "session", "abc")
+
+	nexotest.Get("/me").
+		WithCookie(&http.Cookie{Name: "session", Value: "abc"}).
+		WithBearer("token").
+		Run(app).
+		AssertStatus(t, http.StatusOK).
+		AssertBodyContains(t, "hello abc").
+		AssertNoCookie(t, "session")
+}
+
+func TestRequest_QueryAppendsToPath(t *testing.T) {
+	req := nexotest.Get("/api/users?sort=name").WithQuery("page", "2").HTTPRequest()
+	if got := req.URL.RequestURI(); got != "/api/users?sort=name&page=2" {
+		t.Errorf("RequestURI() = %q", got)
+	}
+}
+
+func TestResponse_AssertionFailures(t *testing.T) {
+	app := newTestApp()
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB, r *nexotest.Response)
+		want   string
+	}{
+		{"status", func(t testing.TB, r *nexotest.Response) { r.AssertStatus(t, http.StatusCreated) }, "GET /api/users?page=2: status = 200, want 201"},
+		{"header", func(t testing.TB, r *nexotest.Response) { r.AssertHeader(t, "X-Total", "2") }, `header X-Total = "", want "2"`},
+		{"body", func(t testing.TB, r *nexotest.Response) { r.AssertBodyContains(t, "Linus") }, `body does not contain "Linus"`},
+		{"json", func(t testing.TB, r *nexotest.Response) { r.AssertJSON(t, `{}`) }, "JSON body ="},
+		{"json path value", func(t testing.TB, r *nexotest.Response) { r.AssertJSONPath(t, "users.0.name", "Grace") }, `JSON users.0.name = "Ada", want "Grace"`},
+		{"json path missing", func(t testing.TB, r *nexotest.Response) { r.AssertJSONPath(t, "users.5.name", "Ada") }, `no index "5" in array of 2`},
+		{"cookie", func(t testing.TB, r *nexotest.Response) { r.AssertCookie(t, "session", "abc") }, "cookie session not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			resp := nexotest.Get("/api/users").WithQuery("page", "2").Run(app)
+			tt.assert(rec, resp)
+			if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], tt.want) {
+				t.Errorf("errors = %q, want one containing %q", rec.errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_InvalidJSONBody(t *testing.T) {
+	rec := &recorder{TB: t}
+	nexotest.Post("/api/users").
+		WithJSON(map[string]any{"bad": make(chan int)}).
+		Run(newTestApp()).
+		AssertStatus(rec, http.StatusCreated)
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "encoding JSON body") {
+		t.Errorf("errors = %q", rec.errors)
+	}
+}
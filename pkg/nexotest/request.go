@@ -0,0 +1,170 @@
+// Package nexotest provides a request builder and response assertions for
+// testing Nexo apps without the httptest boilerplate.
+//
+//	app := nexo.New()
+//	RegisterRoutes(app)
+//	app.Mount()
+//
+//	nexotest.Post("/api/users").
+//		WithJSON(map[string]string{"name": "Ada"}).
+//		Run(app).
+//		AssertStatus(t, http.StatusCreated).
+//		AssertJSONPath(t, "name", "Ada")
+//
+// Requests run in-process through the handler's ServeHTTP, so proxy,
+// middleware and handlers all run as they do in production.
+package nexotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+)
+
+// Request builds an HTTP request for a test. Build one with Get, Post or
+// another method function, then send it with Run.
+type Request struct {
+	method  string
+	path    string
+	header  http.Header
+	query   url.Values
+	cookies []*http.Cookie
+	body    []byte
+	err     error
+}
+
+// NewRequest starts a request with the given method and path. The path may
+// include a query string.
+func NewRequest(method, path string) *Request {
+	return &Request{
+		method: method,
+		path:   path,
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+}
+
+// Get starts a GET request.
+func Get(path string) *Request { return NewRequest(http.MethodGet, path) }
+
+// Post starts a POST request.
+func Post(path string) *Request { return NewRequest(http.MethodPost, path) }
+
+// Put starts a PUT request.
+func Put(path string) *Request { return NewRequest(http.MethodPut, path) }
+
+// Patch starts a PATCH request.
+func Patch(path string) *Request { return NewRequest(http.MethodPatch, path) }
+
+// Delete starts a DELETE request.
+func Delete(path string) *Request { return NewRequest(http.MethodDelete, path) }
+
+// Head starts a HEAD request.
+func Head(path string) *Request { return NewRequest(http.MethodHead, path) }
+
+// Options starts an OPTIONS request.
+func Options(path string) *Request { return NewRequest(http.MethodOptions, path) }
+
+// WithHeader sets a request header.
+func (r *Request) WithHeader(name, value string) *Request {
+	r.header.Set(name, value)
+	return r
+}
+
+// WithBearer sets the Authorization header to a bearer token.
+func (r *Request) WithBearer(token string) *Request {
+	return r.WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithQuery adds a query parameter.
+func (r *Request) WithQuery(name, value string) *Request {
+	r.query.Add(name, value)
+	return r
+}
+
+// WithCookie adds a cookie.
+func (r *Request) WithCookie(cookie *http.Cookie) *Request {
+	r.cookies = append(r.cookies, cookie)
+	return r
+}
+
+// WithJSON sets the body to v encoded as JSON and the Content-Type to
+// application/json. Strings and byte slices are sent as they are.
+func (r *Request) WithJSON(v any) *Request {
+	switch body := v.(type) {
+	case string:
+		r.body = []byte(body)
+	case []byte:
+		r.body = body
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			r.err = fmt.Errorf("nexotest: encoding JSON body: %w", err)
+		}
+		r.body = data
+	}
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// WithForm sets the body to URL-encoded form values.
+func (r *Request) WithForm(values url.Values) *Request {
+	r.body = []byte(values.Encode())
+	r.header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// WithBody sets a raw body. Set the Content-Type with WithHeader.
+func (r *Request) WithBody(body string) *Request {
+	r.body = []byte(body)
+	return r
+}
+
+// HTTPRequest returns the built *http.Request, for tests that need to call
+// a handler directly.
+func (r *Request) HTTPRequest() *http.Request {
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req := httptest.NewRequest(r.method, r.url(), body)
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+// Run sends the request to h, usually a mounted *nexo.App, and returns the
+// response.
+func (r *Request) Run(h http.Handler) *Response {
+	req := r.HTTPRequest()
+	rec := httptest.NewRecorder()
+	if r.err == nil {
+		h.ServeHTTP(rec, req)
+	}
+	return &Response{
+		Recorder: rec,
+		request:  req,
+		err:      r.err,
+	}
+}
+
+// url returns the path with the query parameters added.
+func (r *Request) url() string {
+	if len(r.query) == 0 {
+		return r.path
+	}
+	sep := "?"
+	if strings.Contains(r.path, "?") {
+		sep = "&"
+	}
+	return r.path + sep + r.query.Encode()
+}
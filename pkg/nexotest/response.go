@@ -0,0 +1,276 @@
+package nexotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// maxBodyInMessage limits how much of the body failure messages include.
+const maxBodyInMessage = 500
+
+// Response is the result of Request.Run. The Assert methods report
+// failures with t.Errorf and return the response, so they can be chained.
+type Response struct {
+	// Recorder holds the raw response.
+	Recorder *httptest.ResponseRecorder
+
+	request *http.Request
+	err     error // Error building the request
+}
+
+// Status returns the response status code.
+func (r *Response) Status() int {
+	return r.Recorder.Code
+}
+
+// Header returns the response headers.
+func (r *Response) Header() http.Header {
+	return r.Recorder.Header()
+}
+
+// Body returns the response body.
+func (r *Response) Body() string {
+	return r.Recorder.Body.String()
+}
+
+// DecodeJSON decodes the response body into v.
+func (r *Response) DecodeJSON(v any) error {
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), v); err != nil {
+		return fmt.Errorf("decoding JSON response: %w", err)
+	}
+	return nil
+}
+
+// JSONPath returns the value at path in the JSON response body. Path
+// segments are separated by dots, and array elements are selected by
+// index: "users.0.name" or "users[0].name". An empty path returns the
+// whole body. Numbers are returned as float64.
+func (r *Response) JSONPath(path string) (any, error) {
+	var value any
+	if err := r.DecodeJSON(&value); err != nil {
+		return nil, err
+	}
+
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("JSON path %q: no key %q", path, key)
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("JSON path %q: no index %q in array of %d", path, key, len(v))
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("JSON path %q: cannot select %q from %T", path, key, value)
+		}
+	}
+	return value, nil
+}
+
+// Cookie returns the cookie set by the response with the given name, or nil.
+func (r *Response) Cookie(name string) *http.Cookie {
+	for _, cookie := range r.Recorder.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// AssertStatus checks the status code.
+func (r *Response) AssertStatus(t testing.TB, want int) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	if got := r.Status(); got != want {
+		t.Errorf("%s: status = %d, want %d\nbody: %s", r.describe(), got, want, r.bodySnippet())
+	}
+	return r
+}
+
+// AssertHeader checks that the header has the given value.
+func (r *Response) AssertHeader(t testing.TB, name, want string) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	if got := r.Header().Get(name); got != want {
+		t.Errorf("%s: header %s = %q, want %q", r.describe(), name, got, want)
+	}
+	return r
+}
+
+// AssertHeaderContains checks that the header contains substr, e.g. a
+// Content-Type without its charset.
+func (r *Response) AssertHeaderContains(t testing.TB, name, substr string) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	if got := r.Header().Get(name); !strings.Contains(got, substr) {
+		t.Errorf("%s: header %s = %q, want it to contain %q", r.describe(), name, got, substr)
+	}
+	return r
+}
+
+// AssertBodyContains checks that the body contains substr.
+func (r *Response) AssertBodyContains(t testing.TB, substr string) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	if !strings.Contains(r.Body(), substr) {
+		t.Errorf("%s: body does not contain %q\nbody: %s", r.describe(), substr, r.bodySnippet())
+	}
+	return r
+}
+
+// AssertJSON checks that the JSON body equals want. want is either a JSON
+// document as a string or any value that encodes to JSON, such as a struct
+// or a map; key order and formatting don't matter.
+func (r *Response) AssertJSON(t testing.TB, want any) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	got, err := r.JSONPath("")
+	if err != nil {
+		t.Errorf("%s: %v\nbody: %s", r.describe(), err, r.bodySnippet())
+		return r
+	}
+	if s, ok := want.(string); ok {
+		want = []byte(s)
+	}
+	wantValue, err := normalizeJSON(want)
+	if err != nil {
+		t.Errorf("%s: %v", r.describe(), err)
+		return r
+	}
+	if !reflect.DeepEqual(got, wantValue) {
+		t.Errorf("%s: JSON body = %s, want %s", r.describe(), r.bodySnippet(), jsonString(wantValue))
+	}
+	return r
+}
+
+// AssertJSONPath checks the value at path in the JSON body (see JSONPath).
+// want is compared after encoding to JSON, so 2 matches the JSON number 2.
+func (r *Response) AssertJSONPath(t testing.TB, path string, want any) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	got, err := r.JSONPath(path)
+	if err != nil {
+		t.Errorf("%s: %v\nbody: %s", r.describe(), err, r.bodySnippet())
+		return r
+	}
+	wantValue, err := normalizeJSON(want)
+	if err != nil {
+		t.Errorf("%s: %v", r.describe(), err)
+		return r
+	}
+	if !reflect.DeepEqual(got, wantValue) {
+		t.Errorf("%s: JSON %s = %s, want %s", r.describe(), path, jsonString(got), jsonString(wantValue))
+	}
+	return r
+}
+
+// AssertCookie checks that the response sets the cookie to value.
+func (r *Response) AssertCookie(t testing.TB, name, value string) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	cookie := r.Cookie(name)
+	switch {
+	case cookie == nil:
+		t.Errorf("%s: cookie %s not set", r.describe(), name)
+	case cookie.Value != value:
+		t.Errorf("%s: cookie %s = %q, want %q", r.describe(), name, cookie.Value, value)
+	}
+	return r
+}
+
+// AssertNoCookie checks that the response does not set the cookie.
+func (r *Response) AssertNoCookie(t testing.TB, name string) *Response {
+	t.Helper()
+	if !r.ok(t) {
+		return r
+	}
+	if cookie := r.Cookie(name); cookie != nil {
+		t.Errorf("%s: cookie %s set to %q, want it not set", r.describe(), name, cookie.Value)
+	}
+	return r
+}
+
+// ok reports a request build error, which fails every assertion.
+func (r *Response) ok(t testing.TB) bool {
+	t.Helper()
+	if r.err != nil {
+		t.Errorf("%s: %v", r.describe(), r.err)
+		return false
+	}
+	return true
+}
+
+// describe names the request in failure messages, e.g. "GET /api/users".
+func (r *Response) describe() string {
+	return r.request.Method + " " + r.request.URL.RequestURI()
+}
+
+// bodySnippet returns the body for failure messages, truncated.
+func (r *Response) bodySnippet() string {
+	body := r.Body()
+	if len(body) > maxBodyInMessage {
+		return body[:maxBodyInMessage] + "..."
+	}
+	return body
+}
+
+// normalizeJSON converts v to the value json.Unmarshal would produce for its
+// encoding. Byte slices are parsed as JSON.
+func normalizeJSON(v any) (any, error) {
+	var data []byte
+	switch value := v.(type) {
+	case json.RawMessage:
+		data = value
+	case []byte:
+		data = value
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("encoding expected value: %w", err)
+		}
+	}
+
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decoding expected value: %w", err)
+	}
+	return out, nil
+}
+
+// jsonString encodes v for failure messages.
+func jsonString(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 )
 
 // GeneratorConfig holds configuration for code generation.
@@ -108,7 +110,7 @@ func (g *Generator) generateRoutesFile(result *ScanResult, outputPath string) er
 				Pattern:     rf.URLPattern,
 				Method:      h.Method,
 				HandlerName: MakeHandlerName(rf.URLPattern, h.Method),
-				FilePath:    rf.FilePath,
+				FilePath:    h.FilePath,
 				Scope:       rf.Scope,
 				Priority:    calculatePriority(rf.URLPattern),
 				Source:      h.Source,
@@ -175,7 +177,7 @@ func (g *Generator) generateRegisterFile(result *ScanResult, outputPath string)
 				rf.URLPattern,
 				h.Method,
 				handlerName,
-				rf.FilePath,
+				h.FilePath,
 				rf.Scope,
 				calculatePriority(rf.URLPattern),
 				catchAllParam,
@@ -211,16 +213,10 @@ func (g *Generator) generateRegisterFile(result *ScanResult, outputPath string)
 	return os.WriteFile(outputPath, buf.Bytes(), 0644)
 }
 
-// calculatePriority calculates route priority (higher = more specific)
+// calculatePriority calculates route priority (higher = more specific),
+// matching the runtime router.
 func calculatePriority(pattern string) int {
-	priority := 100
-	if strings.Contains(pattern, "{") {
-		priority -= 50 // Dynamic routes lower priority
-	}
-	if strings.Contains(pattern, "*") {
-		priority -= 90 // Catch-all lowest priority
-	}
-	return priority
+	return nexo.CalculatePriority(pattern)
 }
 
 const routesTemplate = `// Code generated by nexo. DO NOT EDIT.
@@ -0,0 +1,246 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
+)
+
+// Lint rules reported in Diagnostic.Rule, besides the nexo scanner's
+// RuleInvalidSignature, RuleMisnamed and RuleParseError.
+const (
+	// RuleConflict flags two routes or pages that handle the same requests.
+	RuleConflict = "conflict"
+	// RuleShadowedCatchAll flags catch-all segments that other routes take
+	// precedence over.
+	RuleShadowedCatchAll = "shadowed-catch-all"
+	// RuleUnreachable flags routes that can never be matched.
+	RuleUnreachable = "unreachable"
+	// RuleInvalidSignature flags functions that are not registered because
+	// of their signature.
+	RuleInvalidSignature = nexo.RuleInvalidSignature
+	// RuleMisnamed flags functions that are not registered because their
+	// name is misspelled, e.g. GET instead of Get.
+	RuleMisnamed = nexo.RuleMisnamed
+)
+
+// Diagnostic severities.
+const (
+	SeverityError   = nexo.SeverityError
+	SeverityWarning = nexo.SeverityWarning
+)
+
+// Diagnostic is a problem found by Lint. It's the type the nexo scanner
+// reports skipped functions with, so both share one output format.
+type Diagnostic = nexo.Diagnostic
+
+// endpoint is a method and pattern served by a route.go handler or page.templ.
+type endpoint struct {
+	method   string
+	pattern  string
+	shape    string // Pattern with parameter names removed, e.g. "/users/{}" or "/users/{int}"
+	segments []Segment
+	filePath string
+	page     bool
+	fallback bool // Served by Any, which only handles the methods nothing else does
+}
+
+// Lint checks a scan result for conflicting, shadowed and unreachable routes,
+// and reports the functions the scanner skipped because of an invalid
+// signature or a misspelled name. Diagnostics are sorted by file.
+func Lint(result *ScanResult) []Diagnostic {
+	diags := slices.Clone(result.Diagnostics)
+	var endpoints []endpoint
+
+	for _, r := range result.Routes {
+		for _, h := range r.Handlers {
+			e := newEndpoint(h.Method, r.URLPattern, r.Segments, h.FilePath, false)
+			e.fallback = h.Name == "Any"
+			endpoints = append(endpoints, e)
+		}
+	}
+	for _, p := range result.Pages {
+		endpoints = append(endpoints, newEndpoint(http.MethodGet, p.URLPattern, p.Segments, p.FilePath, true))
+	}
+
+	diags = append(diags, lintConflicts(result.Conflicts, endpoints)...)
+	diags = append(diags, lintUnreachable(endpoints)...)
+	diags = append(diags, lintCatchAlls(endpoints)...)
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].FilePath != diags[j].FilePath {
+			return diags[i].FilePath < diags[j].FilePath
+		}
+		return diags[i].Rule < diags[j].Rule
+	})
+	return diags
+}
+
+func newEndpoint(method, pattern string, segments []Segment, filePath string, page bool) endpoint {
+	return endpoint{
+		method:   method,
+		pattern:  pattern,
+		shape:    patternShape(segments),
+		segments: segments,
+		filePath: filePath,
+		page:     page,
+	}
+}
+
+// patternShape builds the URL pattern of segments without parameter names,
+// so patterns that match the same requests have the same shape.
+func patternShape(segments []Segment) string {
+	var parts []string
+	for _, seg := range segments {
+		switch seg.Type {
+		case SegmentStatic:
+			parts = append(parts, seg.Name)
+		case SegmentDynamic:
+			parts = append(parts, "{"+seg.Constraint+"}")
+		case SegmentCatchAll, SegmentOptionalCatchAll:
+			parts = append(parts, "*")
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// lintConflicts reports duplicate handlers found by the scanner and
+// endpoints that match the same requests under different patterns or files.
+func lintConflicts(conflicts []Conflict, endpoints []endpoint) []Diagnostic {
+	var diags []Diagnostic
+	for _, c := range conflicts {
+		diags = append(diags, Diagnostic{
+			Rule:     RuleConflict,
+			Severity: SeverityError,
+			Pattern:  c.Pattern,
+			FilePath: c.File2,
+			Message:  fmt.Sprintf("%s (also in %s)", c.Message, c.File1),
+		})
+	}
+
+	for i, a := range endpoints {
+		for _, b := range endpoints[i+1:] {
+			if a.method != b.method || a.shape != b.shape || a.filePath == b.filePath {
+				continue
+			}
+
+			d := Diagnostic{Rule: RuleConflict, Severity: SeverityError, Pattern: b.pattern, FilePath: b.filePath}
+			switch {
+			case a.pattern != b.pattern:
+				d.Message = fmt.Sprintf("%s %s matches the same requests as %s in %s", b.method, b.pattern, a.pattern, a.filePath)
+			case a.page && b.page:
+				d.Message = fmt.Sprintf("Duplicate page for %s (also in %s)", b.pattern, a.filePath)
+			case a.page != b.page:
+				page, route := a, b
+				if b.page {
+					page, route = b, a
+				}
+				if route.fallback {
+					continue // Any leaves GET to the page
+				}
+				d.Severity = SeverityWarning
+				d.FilePath = route.filePath
+				d.Message = fmt.Sprintf("GET %s is also served by %s, which takes precedence over this handler", b.pattern, page.filePath)
+			default:
+				continue // Duplicate handlers are reported by the scanner
+			}
+			diags = append(diags, d)
+		}
+	}
+	return diags
+}
+
+// lintUnreachable reports routes below a catch-all segment and directories
+// that look like dynamic segments or groups but don't parse as one.
+func lintUnreachable(endpoints []endpoint) []Diagnostic {
+	var diags []Diagnostic
+	seen := make(map[string]bool)
+	for _, e := range endpoints {
+		if seen[e.filePath] {
+			continue
+		}
+		seen[e.filePath] = true
+
+		catchAll := ""
+		for _, seg := range e.segments {
+			if seg.Type == SegmentGroup {
+				continue
+			}
+			if catchAll != "" {
+				diags = append(diags, Diagnostic{
+					Rule:     RuleUnreachable,
+					Severity: SeverityError,
+					Pattern:  e.pattern,
+					FilePath: e.filePath,
+					Message:  fmt.Sprintf("%s is below the catch-all segment %s, which matches the rest of the path", filepath.Base(filepath.Dir(e.filePath)), catchAll),
+				})
+				break
+			}
+			if seg.Type == SegmentCatchAll || seg.Type == SegmentOptionalCatchAll {
+				catchAll = seg.Raw
+			}
+		}
+
+		for _, seg := range e.segments {
+			if seg.Type == SegmentStatic && strings.ContainsAny(seg.Raw, "[]()") {
+				diags = append(diags, Diagnostic{
+					Rule:     RuleUnreachable,
+					Severity: SeverityWarning,
+					Pattern:  e.pattern,
+					FilePath: e.filePath,
+					Message:  fmt.Sprintf("Directory %q is not a valid dynamic segment or route group and is matched literally", seg.Raw),
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// lintCatchAlls reports catch-all routes that other routes take precedence
+// over: a dynamic sibling wins for single-segment paths, and an index route
+// wins for the empty path an optional catch-all also matches.
+func lintCatchAlls(endpoints []endpoint) []Diagnostic {
+	var diags []Diagnostic
+	for _, c := range endpoints {
+		if !strings.HasSuffix(c.shape, "/*") {
+			continue
+		}
+		optional := false
+		for _, seg := range c.segments {
+			if seg.Type == SegmentOptionalCatchAll {
+				optional = true
+			}
+		}
+
+		prefix := strings.TrimSuffix(c.shape, "*")
+		for _, o := range endpoints {
+			if o.method != c.method {
+				continue
+			}
+			switch {
+			case o.shape == prefix+"{}":
+				diags = append(diags, Diagnostic{
+					Rule:     RuleShadowedCatchAll,
+					Severity: SeverityWarning,
+					Pattern:  c.pattern,
+					FilePath: c.filePath,
+					Message:  fmt.Sprintf("%s %s takes precedence for single-segment paths (%s)", o.method, o.pattern, o.filePath),
+				})
+			case optional && (o.shape == strings.TrimSuffix(prefix, "/") || (prefix == "/" && o.shape == "/")):
+				diags = append(diags, Diagnostic{
+					Rule:     RuleShadowedCatchAll,
+					Severity: SeverityWarning,
+					Pattern:  c.pattern,
+					FilePath: c.filePath,
+					Message:  fmt.Sprintf("%s %s takes precedence over the optional catch-all's empty path (%s)", o.method, o.pattern, o.filePath),
+				})
+			}
+		}
+	}
+	return diags
+}
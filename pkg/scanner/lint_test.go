@@ -0,0 +1,232 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validRoute = "package x\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Get(c *nexo.Context) error { return nil }\n"
+
+// writeAppFiles creates files under a temporary app directory.
+func writeAppFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	appDir := filepath.Join(t.TempDir(), "app")
+	for name, content := range files {
+		path := filepath.Join(appDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return appDir
+}
+
+func lintApp(t *testing.T, files map[string]string) []Diagnostic {
+	t.Helper()
+	result, err := NewScanner(writeAppFiles(t, files)).Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	return Lint(result)
+}
+
+func findDiagnostic(diags []Diagnostic, rule, fileSuffix string) *Diagnostic {
+	for i, d := range diags {
+		if d.Rule == rule && strings.HasSuffix(filepath.ToSlash(d.FilePath), fileSuffix) {
+			return &diags[i]
+		}
+	}
+	return nil
+}
+
+func TestLint_Clean(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"api/users/route.go":      validRoute,
+		"api/users/[id]/route.go": validRoute,
+		"page.templ":              "package app\n\ntempl Page() {}\n",
+	})
+	if len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diags)
+	}
+}
+
+func TestLint_ConstrainedSiblings(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"posts/[id:int]/route.go":     validRoute,
+		"posts/[slug:alpha]/route.go": validRoute,
+	})
+	if len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diags)
+	}
+}
+
+func TestScan_InvalidConstraint(t *testing.T) {
+	result, err := NewScanner(writeAppFiles(t, map[string]string{
+		"posts/[id:number]/route.go": validRoute,
+	})).Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Routes) != 0 {
+		t.Errorf("expected the route to be skipped, got %+v", result.Routes)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0].Message, "unknown constraint") {
+		t.Errorf("expected an unknown constraint warning, got %+v", result.Warnings)
+	}
+}
+
+func TestScan_AnyHandler(t *testing.T) {
+	route := validRoute + "\nfunc Any(c *nexo.Context) error { return nil }\n"
+	result, err := NewScanner(writeAppFiles(t, map[string]string{
+		"proxy/route.go": route,
+	})).Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Routes) != 1 {
+		t.Fatalf("expected 1 route file, got %d", len(result.Routes))
+	}
+
+	handlers := make(map[string]string)
+	for _, h := range result.Routes[0].Handlers {
+		handlers[h.Method] = h.Name
+	}
+	if len(handlers) != 7 {
+		t.Errorf("expected 7 methods, got %v", handlers)
+	}
+	if handlers["GET"] != "Get" || handlers["DELETE"] != "Any" {
+		t.Errorf("expected Get to take precedence over Any, got %v", handlers)
+	}
+}
+
+func TestScan_MethodFiles(t *testing.T) {
+	result, err := NewScanner(writeAppFiles(t, map[string]string{
+		"users/route_get.go":  validRoute,
+		"users/route_post.go": "package x\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Post(c *nexo.Context) error { return nil }\n",
+		"users/helpers.go":    "package x\n",
+	})).Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Routes) != 1 {
+		t.Fatalf("expected the files to be merged into 1 route, got %d", len(result.Routes))
+	}
+
+	route := result.Routes[0]
+	if route.URLPattern != "/users" || filepath.Base(route.FilePath) != "route_get.go" {
+		t.Errorf("expected /users reported as route_get.go, got %s in %s", route.URLPattern, route.FilePath)
+	}
+	files := make(map[string]string)
+	for _, h := range route.Handlers {
+		files[h.Method] = filepath.Base(h.FilePath)
+	}
+	if len(files) != 2 || files["GET"] != "route_get.go" || files["POST"] != "route_post.go" {
+		t.Errorf("expected each handler from its own file, got %v", files)
+	}
+}
+
+func TestLint_Conflicts(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"users/[id]/route.go":     validRoute,
+		"users/[userId]/route.go": validRoute,
+		"(a)/about/page.templ":    "package about\n\ntempl Page() {}\n",
+		"(b)/about/page.templ":    "package about\n\ntempl Page() {}\n",
+		"(b)/about/route.go":      validRoute,
+	})
+
+	if d := findDiagnostic(diags, RuleConflict, "users/[userId]/route.go"); d == nil || d.Severity != SeverityError {
+		t.Errorf("expected conflict between [id] and [userId], got %+v", diags)
+	}
+	if d := findDiagnostic(diags, RuleConflict, "(b)/about/page.templ"); d == nil || !strings.Contains(d.Message, "Duplicate page") {
+		t.Errorf("expected duplicate page conflict, got %+v", diags)
+	}
+	if d := findDiagnostic(diags, RuleConflict, "(b)/about/route.go"); d == nil || d.Severity != SeverityWarning {
+		t.Errorf("expected page/route GET warning, got %+v", diags)
+	}
+}
+
+func TestLint_PageWithAnyHandler(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"contact/page.templ": "package contact\n\ntempl Page() {}\n",
+		"contact/route.go":   "package contact\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Any(c *nexo.Context) error { return nil }\n",
+	})
+	if len(diags) != 0 {
+		t.Errorf("expected Any to leave GET to the page without a warning, got %+v", diags)
+	}
+}
+
+func TestLint_ShadowedCatchAll(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"docs/[slug]/route.go":      validRoute,
+		"docs/[...path]/route.go":   validRoute,
+		"blog/route.go":             validRoute,
+		"blog/[[...rest]]/route.go": validRoute,
+	})
+
+	if findDiagnostic(diags, RuleShadowedCatchAll, "docs/[...path]/route.go") == nil {
+		t.Errorf("expected catch-all shadowed by [slug], got %+v", diags)
+	}
+	if findDiagnostic(diags, RuleShadowedCatchAll, "blog/[[...rest]]/route.go") == nil {
+		t.Errorf("expected optional catch-all shadowed by index route, got %+v", diags)
+	}
+}
+
+func TestLint_Unreachable(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"files/[...path]/edit/route.go": validRoute,
+		"posts/[id/route.go":            validRoute,
+	})
+
+	if d := findDiagnostic(diags, RuleUnreachable, "files/[...path]/edit/route.go"); d == nil || d.Severity != SeverityError {
+		t.Errorf("expected route below catch-all to be unreachable, got %+v", diags)
+	}
+	if d := findDiagnostic(diags, RuleUnreachable, "posts/[id/route.go"); d == nil || d.Severity != SeverityWarning {
+		t.Errorf("expected malformed segment warning, got %+v", diags)
+	}
+}
+
+func TestLint_InvalidSignature(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"api/items/route.go": "package items\n\nimport \"net/http\"\n\nfunc Get(w http.ResponseWriter, r *http.Request) {}\n",
+	})
+
+	d := findDiagnostic(diags, RuleInvalidSignature, "api/items/route.go")
+	if d == nil {
+		t.Fatalf("expected invalid signature diagnostic, got %+v", diags)
+	}
+	if d.Symbol != "Get" || d.Line != 5 || d.Pattern != "/api/items" || !strings.Contains(d.Message, "invalid signature") {
+		t.Errorf("unexpected diagnostic %+v", *d)
+	}
+}
+
+func TestLint_Misnamed(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"api/items/route.go": "package items\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc GET(c *nexo.Context) error { return nil }\n",
+	})
+
+	d := findDiagnostic(diags, RuleMisnamed, "api/items/route.go")
+	if d == nil {
+		t.Fatalf("expected misnamed diagnostic, got %+v", diags)
+	}
+	if d.Symbol != "GET" || d.Severity != SeverityError || !strings.Contains(d.Message, "did you mean Get?") {
+		t.Errorf("unexpected diagnostic %+v", *d)
+	}
+}
+
+func TestLint_SkippedConnectionsAndMiddleware(t *testing.T) {
+	diags := lintApp(t, map[string]string{
+		"chat/ws.go":          "package chat\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc SOCKET(c *nexo.Context, ws *nexo.WebSocketConn) error { return nil }\n",
+		"admin/middleware.go": "package admin\n\nimport \"github.com/abdul-hamid-achik/nexo/pkg/nexo\"\n\nfunc Middleware(next nexo.HandlerFunc) nexo.HandlerFunc { return next }\n",
+	})
+
+	if d := findDiagnostic(diags, RuleMisnamed, "chat/ws.go"); d == nil || d.Symbol != "SOCKET" || d.Pattern != "/chat" {
+		t.Errorf("expected a misnamed socket diagnostic for /chat, got %+v", diags)
+	}
+	if d := findDiagnostic(diags, RuleInvalidSignature, "admin/middleware.go"); d == nil || d.Symbol != "Middleware" || d.Pattern != "/admin" {
+		t.Errorf("expected an invalid middleware diagnostic for /admin, got %+v", diags)
+	}
+}
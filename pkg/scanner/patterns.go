@@ -1,15 +1,18 @@
 package scanner
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 )
 
 // Next.js-style pattern matchers
 var (
-	// [id] - dynamic segment
-	// Matches: [id], [userId], [post_id]
-	dynamicSegmentRe = regexp.MustCompile(`^\[([a-zA-Z_][a-zA-Z0-9_]*)\]$`)
+	// [id] - dynamic segment, with an optional constraint
+	// Matches: [id], [userId], [post_id], [id:int], [code:regexp([A-Z]{3})]
+	dynamicSegmentRe = regexp.MustCompile(`^\[([a-zA-Z_][a-zA-Z0-9_]*)(?::(.+))?\]$`)
 
 	// [...slug] - catch-all segment
 	// Matches: [...slug], [...path], [...segments]
@@ -38,7 +41,7 @@ var knownPrivateFolders = map[string]bool{
 }
 
 // ParseSegment parses a directory name into a Segment.
-// Supports Next.js-style naming: [id], [...slug], [[...slug]], (group).
+// Supports Next.js-style naming: [id], [id:int], [...slug], [[...slug]], (group).
 func ParseSegment(name string) Segment {
 	seg := Segment{Raw: name}
 
@@ -56,9 +59,10 @@ func ParseSegment(name string) Segment {
 		return seg
 	}
 
-	// Dynamic: [id]
+	// Dynamic: [id] or [id:constraint]
 	if matches := dynamicSegmentRe.FindStringSubmatch(name); len(matches) > 1 {
 		seg.Name = matches[1]
+		seg.Constraint = matches[2]
 		seg.Type = SegmentDynamic
 		return seg
 	}
@@ -87,7 +91,8 @@ func IsPrivateFolder(name string) bool {
 }
 
 // BuildURLPattern builds a URL pattern from segments.
-// Groups are excluded from the URL.
+// Groups are excluded from the URL, and constrained dynamic segments
+// become chi regexp parameters, e.g. [id:int] -> {id:-?[0-9]+}.
 func BuildURLPattern(segments []Segment) string {
 	var parts []string
 	for _, seg := range segments {
@@ -96,7 +101,12 @@ func BuildURLPattern(segments []Segment) string {
 			// Groups don't affect the URL
 			continue
 		case SegmentDynamic:
-			parts = append(parts, "{"+seg.Name+"}")
+			param, err := nexo.RouteParam(seg.Name, seg.Constraint)
+			if err != nil {
+				// Rejected by ValidateSegments during scanning
+				param = "{" + seg.Name + "}"
+			}
+			parts = append(parts, param)
 		case SegmentCatchAll, SegmentOptionalCatchAll:
 			parts = append(parts, "*")
 		case SegmentStatic:
@@ -110,6 +120,19 @@ func BuildURLPattern(segments []Segment) string {
 	return "/" + strings.Join(parts, "/")
 }
 
+// ValidateSegments checks the constraints of dynamic segments.
+func ValidateSegments(segments []Segment) error {
+	for _, seg := range segments {
+		if seg.Type != SegmentDynamic {
+			continue
+		}
+		if _, err := nexo.RouteParam(seg.Name, seg.Constraint); err != nil {
+			return fmt.Errorf("directory %q: %w", seg.Raw, err)
+		}
+	}
+	return nil
+}
+
 // BuildScope builds a middleware scope from segments.
 // Unlike URL pattern, this preserves group names for middleware matching.
 func BuildScope(segments []Segment) string {
@@ -134,9 +157,9 @@ func MakeHandlerName(pattern, method string) string {
 		if part == "" {
 			continue
 		}
-		// Handle {param}
+		// Handle {param} and {param:regexp}
 		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
-			param := strings.TrimPrefix(strings.TrimSuffix(part, "}"), "{")
+			param, _, _ := strings.Cut(part[1:len(part)-1], ":")
 			result.WriteString(toPascalCase(param))
 			continue
 		}
@@ -270,6 +293,11 @@ func toPascalCase(s string) string {
 
 // sanitizePackageName converts a directory name to a valid Go package name.
 func sanitizePackageName(name string) string {
+	// Drop the constraint of a dynamic segment: [id:int] -> [id
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+
 	// Remove brackets and parentheses
 	name = strings.ReplaceAll(name, "[", "")
 	name = strings.ReplaceAll(name, "]", "")
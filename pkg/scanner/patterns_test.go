@@ -14,6 +14,8 @@ func TestParseSegment(t *testing.T) {
 		// Next.js-style patterns
 		{"dynamic bracket", "[id]", SegmentDynamic, "id"},
 		{"dynamic bracket underscore", "[user_id]", SegmentDynamic, "user_id"},
+		{"dynamic with constraint", "[id:int]", SegmentDynamic, "id"},
+		{"dynamic with regexp constraint", "[code:regexp([A-Z]{3})]", SegmentDynamic, "code"},
 		{"catch-all", "[...slug]", SegmentCatchAll, "slug"},
 		{"optional catch-all", "[[...slug]]", SegmentOptionalCatchAll, "slug"},
 		{"route group", "(admin)", SegmentGroup, "admin"},
@@ -109,6 +111,15 @@ func TestBuildURLPattern(t *testing.T) {
 			},
 			want: "/api/users/{userId}/posts/{postId}",
 		},
+		{
+			name: "with constraints",
+			segments: []Segment{
+				{Raw: "users", Name: "users", Type: SegmentStatic},
+				{Raw: "[id:uint]", Name: "id", Constraint: "uint", Type: SegmentDynamic},
+				{Raw: "[code:regexp([A-Z]{3})]", Name: "code", Constraint: "regexp([A-Z]{3})", Type: SegmentDynamic},
+			},
+			want: "/users/{id:[0-9]+}/{code:[A-Z]{3}}",
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,6 +132,27 @@ func TestBuildURLPattern(t *testing.T) {
 	}
 }
 
+func TestParseSegment_Constraint(t *testing.T) {
+	if got := ParseSegment("[id:int]").Constraint; got != "int" {
+		t.Errorf("Constraint = %q, want %q", got, "int")
+	}
+	if got := ParseSegment("[id]").Constraint; got != "" {
+		t.Errorf("Constraint = %q, want empty", got)
+	}
+}
+
+func TestValidateSegments(t *testing.T) {
+	valid := []Segment{ParseSegment("users"), ParseSegment("[id:int]"), ParseSegment("[code:regexp([A-Z]{3})]")}
+	if err := ValidateSegments(valid); err != nil {
+		t.Errorf("ValidateSegments() error = %v", err)
+	}
+	for _, raw := range []string{"[id:number]", "[code:regexp([A-Z)]"} {
+		if err := ValidateSegments([]Segment{ParseSegment(raw)}); err == nil {
+			t.Errorf("ValidateSegments(%q) should fail", raw)
+		}
+	}
+}
+
 func TestBuildScope(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -173,6 +205,7 @@ func TestMakeHandlerName(t *testing.T) {
 		{"/api/users/{id}", "DELETE", "ApiUsersIdDelete"},
 		{"/docs/*", "GET", "DocsWildcardGet"},
 		{"/api/users/{userId}/posts/{postId}", "PUT", "ApiUsersUseridPostsPostidPut"},
+		{"/api/users/{id:[0-9]+}", "GET", "ApiUsersIdGet"},
 	}
 
 	for _, tt := range tests {
@@ -211,6 +244,13 @@ func TestMakePackageName(t *testing.T) {
 			},
 			want: "id",
 		},
+		{
+			name: "dynamic with constraint",
+			segments: []Segment{
+				{Raw: "[id:int]", Name: "id", Constraint: "int", Type: SegmentDynamic},
+			},
+			want: "id",
+		},
 		{
 			name: "catch-all",
 			segments: []Segment{
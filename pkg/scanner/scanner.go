@@ -9,7 +9,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/abdul-hamid-achik/nexo/pkg/nexo"
 )
 
 // Scanner scans the app directory for Next.js-style routes.
@@ -44,6 +47,18 @@ var httpMethods = map[string]string{
 	"Options": http.MethodOptions,
 }
 
+// anyMethods are the methods an Any handler serves when the route has no
+// function of its own for them.
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
 // Scan walks the app directory and discovers all routing files.
 func (s *Scanner) Scan() (*ScanResult, error) {
 	result := &ScanResult{}
@@ -55,6 +70,8 @@ func (s *Scanner) Scan() (*ScanResult, error) {
 
 	// Track discovered patterns for conflict detection
 	routePatterns := make(map[string]string) // pattern+method -> filePath
+	// Track scanned route directories, whose route files are scanned together
+	routeDirs := make(map[string]bool)
 
 	err := filepath.Walk(s.appDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -77,11 +94,23 @@ func (s *Scanner) Scan() (*ScanResult, error) {
 
 		dir := filepath.Dir(relPath)
 		segments := s.parsePathSegments(dir)
+		if err := ValidateSegments(segments); err != nil {
+			result.Warnings = append(result.Warnings, Warning{
+				FilePath: path,
+				Message:  err.Error(),
+			})
+			return nil
+		}
 
-		// Process routing files
-		switch info.Name() {
-		case "route.go":
-			route, err := s.scanRouteFile(path, relPath, segments)
+		// Scan each route once, however many files it's split into
+		if nexo.IsRouteFile(info.Name()) {
+			if routeDirs[dir] {
+				return nil
+			}
+			routeDirs[dir] = true
+
+			route, diags, err := s.scanRouteFiles(path, relPath, segments)
+			result.Diagnostics = append(result.Diagnostics, diags...)
 			if err != nil {
 				result.Warnings = append(result.Warnings, Warning{
 					FilePath: path,
@@ -90,6 +119,13 @@ func (s *Scanner) Scan() (*ScanResult, error) {
 				return nil
 			}
 			if route != nil {
+				for _, name := range route.InvalidHandlers {
+					result.Warnings = append(result.Warnings, Warning{
+						FilePath: path,
+						Message:  fmt.Sprintf("%s has an invalid signature and is not registered; handlers must be func(c *nexo.Context) error", name),
+					})
+				}
+
 				// Check for conflicts
 				for _, h := range route.Handlers {
 					key := route.URLPattern + ":" + h.Method
@@ -97,18 +133,23 @@ func (s *Scanner) Scan() (*ScanResult, error) {
 						result.Conflicts = append(result.Conflicts, Conflict{
 							Pattern: route.URLPattern,
 							File1:   existing,
-							File2:   path,
+							File2:   h.FilePath,
 							Message: fmt.Sprintf("Duplicate %s handler for %s", h.Method, route.URLPattern),
 						})
 					} else {
-						routePatterns[key] = path
+						routePatterns[key] = h.FilePath
 					}
 				}
 				result.Routes = append(result.Routes, *route)
 			}
+			return nil
+		}
 
+		// Process routing files
+		switch info.Name() {
 		case "middleware.go":
-			mw, err := s.scanMiddlewareFile(path, relPath, segments)
+			mw, diags, err := s.scanMiddlewareFile(path, relPath, segments)
+			result.Diagnostics = append(result.Diagnostics, diags...)
 			if err != nil {
 				result.Warnings = append(result.Warnings, Warning{
 					FilePath: path,
@@ -120,6 +161,18 @@ func (s *Scanner) Scan() (*ScanResult, error) {
 				result.Middlewares = append(result.Middlewares, *mw)
 			}
 
+		case "ws.go", "sse.go", "action.go":
+			// Only parsed for diagnostics; the generator registers them
+			file, err := parser.ParseFile(s.fset, path, nil, parser.ParseComments)
+			if err != nil {
+				result.Warnings = append(result.Warnings, Warning{
+					FilePath: path,
+					Message:  err.Error(),
+				})
+				return nil
+			}
+			result.Diagnostics = append(result.Diagnostics, s.diagnose(file, segments)...)
+
 		case "page.templ":
 			page, err := s.scanPageFile(path, relPath, segments)
 			if err != nil {
@@ -201,19 +254,27 @@ func (s *Scanner) parsePathSegments(relDir string) []Segment {
 	return segments
 }
 
-// scanRouteFile scans a route.go file for handlers.
-func (s *Scanner) scanRouteFile(filePath, relPath string, segments []Segment) (*RouteFile, error) {
-	// Read file content for source extraction
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+// diagnose returns the functions nexo.Scanner skips in a parsed routing
+// file, e.g. a misspelled func GET, which would otherwise surface as 404s.
+func (s *Scanner) diagnose(file *ast.File, segments []Segment) []Diagnostic {
+	diags := nexo.Diagnose(s.fset, file)
+	pattern := BuildURLPattern(segments)
+	for i := range diags {
+		diags[i].Pattern = pattern
 	}
+	return diags
+}
 
-	// Parse the Go file
-	file, err := parser.ParseFile(s.fset, filePath, content, parser.ParseComments)
+// scanRouteFiles scans the route files in a directory for handlers:
+// route.go and the route_<method>.go files it may be split into. filePath
+// is the first of them, which the RouteFile is reported as. Diagnostics
+// are returned for the functions that are skipped.
+func (s *Scanner) scanRouteFiles(filePath, relPath string, segments []Segment) (*RouteFile, []Diagnostic, error) {
+	entries, err := os.ReadDir(filepath.Dir(filePath))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse: %w", err)
+		return nil, nil, fmt.Errorf("failed to read directory: %w", err)
 	}
+	var diags []Diagnostic
 
 	route := &RouteFile{
 		FilePath:     filePath,
@@ -225,58 +286,104 @@ func (s *Scanner) scanRouteFile(filePath, relPath string, segments []Segment) (*
 	}
 
 	// Find handler functions
-	for _, decl := range file.Decls {
-		fn, ok := decl.(*ast.FuncDecl)
-		if !ok || !fn.Name.IsExported() {
+	var anyHandler *Handler
+	for _, entry := range entries {
+		if entry.IsDir() || !nexo.IsRouteFile(entry.Name()) {
 			continue
 		}
+		path := filepath.Join(filepath.Dir(filePath), entry.Name())
 
-		method, ok := httpMethods[fn.Name.Name]
-		if !ok {
-			continue
+		// Read file content for source extraction
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, diags, fmt.Errorf("failed to read file: %w", err)
 		}
 
-		if !isValidHandlerSignature(fn) {
+		// Parse the Go file
+		file, err := parser.ParseFile(s.fset, path, content, parser.ParseComments)
+		if err != nil {
+			return nil, diags, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		diags = append(diags, s.diagnose(file, segments)...)
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !fn.Name.IsExported() {
+				continue
+			}
+
+			method, ok := httpMethods[fn.Name.Name]
+			if !ok && fn.Name.Name != "Any" {
+				continue
+			}
+
+			if !isValidHandlerSignature(fn) {
+				if s.verbose {
+					fmt.Printf("  Warning: %s.%s has invalid signature, skipping\n", path, fn.Name.Name)
+				}
+				route.InvalidHandlers = append(route.InvalidHandlers, fn.Name.Name)
+				continue
+			}
+
+			// Extract function body source code
+			var source string
+			if fn.Body != nil {
+				start := s.fset.Position(fn.Body.Pos()).Offset
+				end := s.fset.Position(fn.Body.End()).Offset
+				if start >= 0 && end <= len(content) && start < end {
+					source = string(content[start:end])
+				}
+			}
+
+			// Any handles the methods without a function of their own
+			if !ok {
+				anyHandler = &Handler{Name: fn.Name.Name, Source: source, FilePath: path}
+				continue
+			}
+
+			route.Handlers = append(route.Handlers, Handler{
+				Name:     fn.Name.Name,
+				Method:   method,
+				Source:   source,
+				FilePath: path,
+			})
+
 			if s.verbose {
-				fmt.Printf("  Warning: %s.%s has invalid signature, skipping\n", filePath, fn.Name.Name)
+				fmt.Printf("  Found handler: %s %s in %s\n", method, route.URLPattern, path)
 			}
-			continue
 		}
+	}
 
-		// Extract function body source code
-		var source string
-		if fn.Body != nil {
-			start := s.fset.Position(fn.Body.Pos()).Offset
-			end := s.fset.Position(fn.Body.End()).Offset
-			if start >= 0 && end <= len(content) && start < end {
-				source = string(content[start:end])
+	if anyHandler != nil {
+		for _, method := range anyMethods {
+			if slices.ContainsFunc(route.Handlers, func(h Handler) bool { return h.Method == method }) {
+				continue
 			}
+			h := *anyHandler
+			h.Method = method
+			route.Handlers = append(route.Handlers, h)
 		}
 
-		route.Handlers = append(route.Handlers, Handler{
-			Name:   fn.Name.Name,
-			Method: method,
-			Source: source,
-		})
-
 		if s.verbose {
-			fmt.Printf("  Found handler: %s %s in %s\n", method, route.URLPattern, filePath)
+			fmt.Printf("  Found handler: Any %s in %s\n", route.URLPattern, anyHandler.FilePath)
 		}
 	}
 
-	if len(route.Handlers) == 0 {
-		return nil, nil
+	if len(route.Handlers) == 0 && len(route.InvalidHandlers) == 0 {
+		return nil, diags, nil
 	}
 
-	return route, nil
+	return route, diags, nil
 }
 
-// scanMiddlewareFile scans a middleware.go file.
-func (s *Scanner) scanMiddlewareFile(filePath, relPath string, segments []Segment) (*MiddlewareFile, error) {
+// scanMiddlewareFile scans a middleware.go file, with diagnostics for the
+// functions that are skipped.
+func (s *Scanner) scanMiddlewareFile(filePath, relPath string, segments []Segment) (*MiddlewareFile, []Diagnostic, error) {
 	file, err := parser.ParseFile(s.fset, filePath, nil, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse: %w", err)
 	}
+	diags := s.diagnose(file, segments)
 
 	// Look for Middleware function
 	for _, decl := range file.Decls {
@@ -309,10 +416,10 @@ func (s *Scanner) scanMiddlewareFile(filePath, relPath string, segments []Segmen
 			fmt.Printf("  Found middleware: %s (scope: %s)\n", mw.URLPattern, mw.Scope)
 		}
 
-		return mw, nil
+		return mw, diags, nil
 	}
 
-	return nil, nil
+	return nil, diags, nil
 }
 
 // scanPageFile scans a page.templ file.
@@ -25,13 +25,17 @@ type Segment struct {
 	Raw string
 	// Name is the extracted parameter name (e.g., "id" from "[id]")
 	Name string
+	// Constraint is the dynamic segment's constraint (e.g., "int" from "[id:int]")
+	Constraint string
 	// Type is the segment type
 	Type SegmentType
 }
 
-// RouteFile represents a discovered route.go file.
+// RouteFile represents a discovered route.go file, merged with the
+// route_<method>.go files it may be split into.
 type RouteFile struct {
-	// FilePath is the absolute path to the route.go file
+	// FilePath is the absolute path to the route.go file, or to the first
+	// route_<method>.go file if there is no route.go
 	FilePath string
 	// RelativePath is the path relative to app directory
 	RelativePath string
@@ -43,6 +47,9 @@ type RouteFile struct {
 	Scope string
 	// Handlers are the discovered handler functions
 	Handlers []Handler
+	// InvalidHandlers are exported HTTP method functions (e.g., "Get") whose
+	// signature isn't func(c *nexo.Context) error; they are not registered
+	InvalidHandlers []string
 	// Package is the Go package name for this route
 	Package string
 }
@@ -55,6 +62,8 @@ type Handler struct {
 	Method string
 	// Source is the extracted function body source code
 	Source string
+	// FilePath is the path to the file declaring the function
+	FilePath string
 }
 
 // MiddlewareFile represents a discovered middleware.go file.
@@ -155,6 +164,9 @@ type ScanResult struct {
 	Proxy *ProxyFile
 	// Warnings are non-fatal issues encountered during scanning
 	Warnings []Warning
+	// Diagnostics are the handlers, sockets, streams, actions and
+	// middleware the nexo scanner skips, e.g. a misspelled func GET
+	Diagnostics []Diagnostic
 	// Conflicts are route conflicts detected
 	Conflicts []Conflict
 }
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultMigrationsDir is the conventional location of SQL migrations in a Nexo project
+	DefaultMigrationsDir = "migrations"
+
+	// MigrateInstallHint explains how to install the golang-migrate CLI
+	MigrateInstallHint = "go install -tags 'postgres mysql sqlite3' github.com/golang-migrate/migrate/v4/cmd/migrate@latest"
+)
+
+// MigrateCLI runs migrations from the migrations/ directory using the
+// golang-migrate CLI (https://github.com/golang-migrate/migrate).
+type MigrateCLI struct {
+	binary      string
+	dir         string
+	databaseURL string
+}
+
+// NewMigrateCLI creates a MigrateCLI for the given migrations directory and database URL
+func NewMigrateCLI(dir, databaseURL string) *MigrateCLI {
+	if dir == "" {
+		dir = DefaultMigrationsDir
+	}
+	return &MigrateCLI{
+		binary:      "migrate",
+		dir:         dir,
+		databaseURL: databaseURL,
+	}
+}
+
+// IsInstalled checks if the migrate binary is available on PATH
+func (m *MigrateCLI) IsInstalled() bool {
+	_, err := exec.LookPath(m.binary)
+	return err == nil
+}
+
+// Up applies pending migrations. A steps value of 0 applies all of them.
+func (m *MigrateCLI) Up(steps int) error {
+	return m.run(m.args("up", steps)...)
+}
+
+// Down rolls back applied migrations. A steps value of 0 rolls back all of them.
+func (m *MigrateCLI) Down(steps int) error {
+	args := m.args("down", steps)
+	if steps == 0 {
+		args = append(args, "-all")
+	}
+	return m.run(args...)
+}
+
+// Version returns the current migration version of the database and whether
+// the last migration failed part-way (dirty). A database without applied
+// migrations has version 0.
+func (m *MigrateCLI) Version() (version uint64, dirty bool, err error) {
+	if err := m.check(); err != nil {
+		return 0, false, err
+	}
+
+	// migrate prints the version to stderr
+	var out bytes.Buffer
+	cmd := exec.Command(m.binary, "-path", m.dir, "-database", m.databaseURL, "version")
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	runErr := cmd.Run()
+	return parseMigrateVersion(out.String(), runErr)
+}
+
+// parseMigrateVersion parses the output of "migrate version".
+func parseMigrateVersion(output string, runErr error) (uint64, bool, error) {
+	output = strings.TrimSpace(output)
+	if strings.Contains(output, "no migration") {
+		return 0, false, nil
+	}
+	if runErr != nil {
+		return 0, false, fmt.Errorf("migrate version failed: %w\n%s", runErr, output)
+	}
+
+	lines := strings.Split(output, "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	dirty := strings.HasSuffix(last, "(dirty)")
+	last = strings.TrimSpace(strings.TrimSuffix(last, "(dirty)"))
+
+	version, err := strconv.ParseUint(last, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("unexpected migrate version output: %q", output)
+	}
+	return version, dirty, nil
+}
+
+// Migration is a pair of up/down SQL files in the migrations directory.
+type Migration struct {
+	Version  uint64
+	Name     string
+	UpPath   string
+	DownPath string // Empty if the migration has no down file
+}
+
+// migrationFileRe matches golang-migrate file names: <version>_<name>.(up|down).sql
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ListMigrations returns the migrations in dir, sorted by version.
+func ListMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("migrations directory not found: %s", dir)
+		}
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = mig
+		}
+		path := filepath.Join(dir, entry.Name())
+		if matches[3] == "up" {
+			mig.UpPath = path
+		} else {
+			mig.DownPath = path
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// args builds the argument list for a migrate subcommand
+func (m *MigrateCLI) args(command string, steps int) []string {
+	args := []string{"-path", m.dir, "-database", m.databaseURL, command}
+	if steps > 0 {
+		args = append(args, strconv.Itoa(steps))
+	}
+	return args
+}
+
+// run executes the migrate binary with the given arguments
+func (m *MigrateCLI) run(args ...string) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command(m.binary, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// check verifies the database URL, migrations directory and migrate binary
+func (m *MigrateCLI) check() error {
+	if m.databaseURL == "" {
+		return fmt.Errorf("no database URL configured (set DATABASE_URL or pass --database)")
+	}
+	if _, err := os.Stat(m.dir); os.IsNotExist(err) {
+		return fmt.Errorf("migrations directory not found: %s", m.dir)
+	}
+	if !m.IsInstalled() {
+		return fmt.Errorf("migrate CLI not found in PATH, install it with:\n  %s", MigrateInstallHint)
+	}
+	return nil
+}
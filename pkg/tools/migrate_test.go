@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewMigrateCLI_DefaultDir(t *testing.T) {
+	m := NewMigrateCLI("", "postgres://localhost/app")
+	if m.dir != DefaultMigrationsDir {
+		t.Errorf("dir = %q, want %q", m.dir, DefaultMigrationsDir)
+	}
+}
+
+func TestMigrateCLI_Args(t *testing.T) {
+	m := NewMigrateCLI("db/migrations", "postgres://localhost/app")
+
+	tests := []struct {
+		command string
+		steps   int
+		want    []string
+	}{
+		{"up", 0, []string{"-path", "db/migrations", "-database", "postgres://localhost/app", "up"}},
+		{"down", 2, []string{"-path", "db/migrations", "-database", "postgres://localhost/app", "down", "2"}},
+	}
+
+	for _, tt := range tests {
+		got := m.args(tt.command, tt.steps)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("args(%q, %d) = %v, want %v", tt.command, tt.steps, got, tt.want)
+		}
+	}
+}
+
+func TestMigrateCLI_RequiresDatabaseURL(t *testing.T) {
+	m := NewMigrateCLI(t.TempDir(), "")
+	err := m.Up(0)
+	if err == nil || !strings.Contains(err.Error(), "database URL") {
+		t.Errorf("Up() error = %v, want database URL error", err)
+	}
+}
+
+func TestMigrateCLI_RequiresDir(t *testing.T) {
+	m := NewMigrateCLI("does-not-exist", "postgres://localhost/app")
+	err := m.Up(0)
+	if err == nil || !strings.Contains(err.Error(), "migrations directory not found") {
+		t.Errorf("Up() error = %v, want missing directory error", err)
+	}
+}
+
+func TestParseMigrateVersion(t *testing.T) {
+	tests := []struct {
+		output    string
+		runErr    error
+		version   uint64
+		dirty     bool
+		wantError bool
+	}{
+		{output: "20240115093000\n", version: 20240115093000},
+		{output: "20240115093000 (dirty)\n", version: 20240115093000, dirty: true},
+		{output: "error: no migration\n", runErr: errors.New("exit status 1")},
+		{output: "error: connection refused\n", runErr: errors.New("exit status 1"), wantError: true},
+		{output: "garbage", wantError: true},
+	}
+
+	for _, tt := range tests {
+		version, dirty, err := parseMigrateVersion(tt.output, tt.runErr)
+		if (err != nil) != tt.wantError {
+			t.Errorf("parseMigrateVersion(%q) error = %v, wantError %v", tt.output, err, tt.wantError)
+			continue
+		}
+		if version != tt.version || dirty != tt.dirty {
+			t.Errorf("parseMigrateVersion(%q) = %d, %v, want %d, %v", tt.output, version, dirty, tt.version, tt.dirty)
+		}
+	}
+}
+
+func TestListMigrations(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"20240201000000_add_posts.up.sql",
+		"20240201000000_add_posts.down.sql",
+		"20240101000000_create_users.up.sql",
+		"20240101000000_create_users.down.sql",
+		"20240301000000_no_down.up.sql",
+		"README.md",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrations, err := ListMigrations(dir)
+	if err != nil {
+		t.Fatalf("ListMigrations() error = %v", err)
+	}
+
+	var got []string
+	for _, m := range migrations {
+		got = append(got, fmt.Sprintf("%d_%s", m.Version, m.Name))
+	}
+	want := []string{"20240101000000_create_users", "20240201000000_add_posts", "20240301000000_no_down"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListMigrations() = %v, want %v", got, want)
+	}
+	if migrations[2].DownPath != "" {
+		t.Errorf("DownPath = %q, want empty", migrations[2].DownPath)
+	}
+
+	if _, err := ListMigrations(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
@@ -130,6 +130,21 @@ func (t *TailwindCLI) BuildWithOutput(input, output string) (string, error) {
 // Watch runs Tailwind in watch mode and returns the process.
 // It first runs an initial build to ensure CSS is up-to-date, then starts watching.
 func (t *TailwindCLI) Watch(input, output string) (*exec.Cmd, error) {
+	cmd, err := t.WatchCommand(input, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+// WatchCommand runs an initial build and returns the watch mode command
+// without starting it, so callers can redirect its output first.
+func (t *TailwindCLI) WatchCommand(input, output string) (*exec.Cmd, error) {
 	if err := t.EnsureInstalled(); err != nil {
 		return nil, err
 	}
@@ -156,15 +171,9 @@ func (t *TailwindCLI) Watch(input, output string) (*exec.Cmd, error) {
 		return nil, fmt.Errorf("initial CSS build failed: %w", err)
 	}
 
-	// Now start watch mode
 	cmd := exec.Command(t.BinaryPath(), "-i", input, "-o", output, "--watch", "--cwd", cwd)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-
-	if err := cmd.Start(); err != nil {
-		return nil, err
-	}
-
 	return cmd, nil
 }
 